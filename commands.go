@@ -0,0 +1,563 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"linkedin-automation/internal/automation"
+	"linkedin-automation/internal/browser"
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/report"
+	"linkedin-automation/internal/server"
+	"linkedin-automation/internal/storage"
+)
+
+// handler runs a subcommand with the arguments that follow its name
+type handler func(args []string) error
+
+// commands lists the subcommands in the order the usage prints them
+var commands = []struct {
+	name    string
+	summary string
+}{
+	{"run", "full workflow: log in, search, connect and follow up (default)"},
+	{"search", "log in and run the people search (-connect also sends connection requests)"},
+	{"connect", "log in and send connection requests to collected profiles"},
+	{"message", "log in and run the follow-ups: acceptance checks, replies and messages"},
+	{"reconcile", "log in and mark pending requests found in the 1st-degree network as accepted"},
+	{"export", "write connection requests as CSV"},
+	{"backup", "write an encrypted backup of the database to a file"},
+	{"restore", "restore the database from an encrypted backup"},
+	{"report", "print a report from the database: trend, audit or visibility"},
+}
+
+// commandHandlers maps each subcommand to the function that runs it
+func commandHandlers() map[string]handler {
+	return map[string]handler{
+		"run":       runCommand,
+		"search":    searchCommand,
+		"connect":   phaseCommand("connect", "connect"),
+		"message":   phaseCommand("message", "followups"),
+		"reconcile": reconcileCommand,
+		"export":    exportCommand,
+		"backup":    backupCommand,
+		"restore":   restoreCommand,
+		"report":    reportCommand,
+	}
+}
+
+// parseCommand splits the command line into a subcommand and its arguments.
+// No subcommand, or flags first (the flags of earlier versions), means run.
+func parseCommand(args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "run", nil, nil
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		return "help", nil, nil
+	}
+	if strings.HasPrefix(args[0], "-") {
+		return "run", args, nil
+	}
+
+	for _, command := range commands {
+		if command.name == args[0] {
+			return command.name, args[1:], nil
+		}
+	}
+	return "", nil, fmt.Errorf("unknown command %q (see help for the list)", args[0])
+}
+
+// dispatch runs the handler of the subcommand named in args
+func dispatch(args []string, handlers map[string]handler) error {
+	name, rest, err := parseCommand(args)
+	if err != nil {
+		return err
+	}
+	if name == "help" {
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	run, ok := handlers[name]
+	if !ok {
+		return fmt.Errorf("command %q has no handler", name)
+	}
+	return run(rest)
+}
+
+// printUsage lists the subcommands
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: linkedin-automation [command] [flags]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, command := range commands {
+		fmt.Fprintf(w, "  %-10s  %s\n", command.name, command.summary)
+	}
+	fmt.Fprintln(w, "\nRun a command with -h for its flags.")
+}
+
+// openDatabase opens the SQLite database at DATABASE_PATH
+func openDatabase() (*storage.Database, error) {
+	dbPath := databasePath()
+	logger.Info("Initializing database at: " + dbPath)
+
+	db, err := storage.InitDB(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	logger.Info("Database initialized successfully")
+	return db, nil
+}
+
+// databasePath returns DATABASE_PATH (default: ./data/linkedin_automation.db)
+func databasePath() string {
+	if dbPath := os.Getenv("DATABASE_PATH"); dbPath != "" {
+		return dbPath
+	}
+	return "./data/linkedin_automation.db"
+}
+
+// newRateLimiter creates the rate limiter and prints today's usage
+func newRateLimiter(db *storage.Database) *automation.RateLimiter {
+	config := automation.GetDefaultRateLimitConfig()
+
+	// Suggest a daily connection limit from the account's age and acceptance rate,
+	// and use it instead of MAX_CONNECTIONS_PER_DAY with AUTO_LIMIT=true
+	if ageDays, ok := automation.GetAccountAgeDays(); ok {
+		recommended := automation.RecommendDailyLimit(db, ageDays)
+		fmt.Printf("Recommended connections per day: %d (account age %d days, configured %d)\n",
+			recommended, ageDays, config.MaxConnectionsPerDay)
+		if automation.AutoLimitEnabled() {
+			logger.Info(fmt.Sprintf("AUTO_LIMIT: using %d connections per day", recommended))
+			config.MaxConnectionsPerDay = recommended
+		}
+	} else if automation.AutoLimitEnabled() {
+		logger.Warning("AUTO_LIMIT needs ACCOUNT_CREATED or ACCOUNT_AGE_DAYS - keeping MAX_CONNECTIONS_PER_DAY")
+	}
+
+	rateLimiter := automation.NewRateLimiterWithConfig(db, config)
+
+	// Display current rate limit stats
+	stats, err := rateLimiter.GetDailyStats()
+	if err != nil {
+		logger.Warning("Failed to get rate limit stats: " + err.Error())
+	} else {
+		logger.Info("Rate Limiter initialized")
+		fmt.Println(stats)
+	}
+	return rateLimiter
+}
+
+// runCommand runs the full workflow. Its flags run a one-off task against the
+// database instead and exit.
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	reportName := fs.String("report", "", "print a report and exit (supported: trend, audit, visibility)")
+	reportDays := fs.Int("days", 7, "number of days to include in the report")
+	createCampaign := fs.String("create-campaign", "", "create a messaging campaign with this name from accepted, unmessaged connections and exit")
+	previewNotes := fs.Bool("preview-notes", false, "render connection notes for the next profiles and exit without sending")
+	checkConfig := fs.Bool("check", false, "validate environment and configuration, print a pass/fail report and exit")
+	if err := fs.Parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+
+	// Check if we're in active hours (business hours)
+	// logger.Info("Checking activity schedule...")
+	// if !automation.IsActiveHours() {
+	// 	logger.Warning("Outside active hours - waiting for business hours...")
+	// 	automation.WaitForActiveHours()
+	// }
+	// logger.Info("Within active hours - proceeding with automation")
+
+	// Validate the configuration and exit before touching the database or browser
+	if *checkConfig {
+		results := automation.RunPreflightChecks(databasePath())
+		if !printPreflightResults(results) {
+			return errors.New("configuration check failed")
+		}
+		return nil
+	}
+
+	// Print a report instead of running automation
+	if *reportName != "" {
+		return reportCommand([]string{*reportName, "-days", fmt.Sprint(*reportDays)})
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rateLimiter := newRateLimiter(db)
+
+	// Define a messaging campaign once; runs work through it via MESSAGING_CAMPAIGN_ID
+	if *createCampaign != "" {
+		return createCampaignFromAccepted(db, *createCampaign)
+	}
+
+	// Preview the connection notes the next run would send, without a browser
+	if *previewNotes {
+		limit := 5
+		if os.Getenv("MAX_CONNECTIONS_PER_RUN") != "" {
+			fmt.Sscanf(os.Getenv("MAX_CONNECTIONS_PER_RUN"), "%d", &limit)
+		}
+
+		previews, err := automation.PreviewConnectionNotes(db, connectionTemplateFromEnv(), senderVarsFromEnv(), limit)
+		if err != nil {
+			return fmt.Errorf("failed to preview connection notes: %w", err)
+		}
+		printNotePreviews(previews)
+		return nil
+	}
+
+	// Dry analysis mode - estimate campaign duration and exit without opening a browser
+	if os.Getenv("ESTIMATE_ONLY") == "true" {
+		printCampaignEstimate(db)
+		return nil
+	}
+
+	// Refuse to start right after another run (MIN_RUN_INTERVAL_MINUTES, FORCE_RUN overrides)
+	if err := automation.EnforceMinRunInterval(automation.GetMinRunInterval()); err != nil {
+		return err
+	}
+
+	r, closeBrowser, err := startRunner(db, rateLimiter)
+	if err != nil {
+		return err
+	}
+	defer closeBrowser()
+
+	// Only a run that got past login counts towards the interval
+	if err := storage.RecordRun(); err != nil {
+		logger.Warning("Failed to record the run start: " + err.Error())
+	}
+
+	// Serve a liveness/readiness probe when running as a service
+	if healthAddr := os.Getenv("HEALTH_ADDR"); healthAddr != "" {
+		srv := server.Start(healthAddr, server.HealthChecker{
+			SessionValid: func() bool {
+				state, err := storage.LoadState()
+				return err == nil && storage.IsSessionValid(state)
+			},
+			BrowserAlive:   func() error { return browser.Ping(r.br) },
+			LastActionTime: rateLimiter.LastActionTime,
+			InActiveHours:  automation.IsActiveHours,
+		})
+		defer srv.Close()
+	}
+
+	// On an idle day only the warm-up runs, so the account doesn't act on every scheduled run
+	var only []string
+	if automation.DecideIdleRun(automation.GetIdleDayProbability()) {
+		only = []string{"warmup"}
+	}
+
+	r.runPhases(only)
+	r.saveSnapshot()
+
+	// Email the daily digest (SMTP failures only log a warning)
+	if report.EmailReportsEnabled() {
+		if digest, err := report.CollectDigest(db, r.runErrors); err != nil {
+			logger.Warning("Failed to collect daily digest: " + err.Error())
+		} else {
+			report.SendDailyDigest(digest)
+		}
+	}
+
+	// Display final stats
+	logger.Info("Automation workflow completed successfully!")
+
+	// Show rate limit summary
+	if stats, err := rateLimiter.GetDailyStats(); err == nil {
+		fmt.Println("\n" + stats)
+	}
+
+	// A STOP control file ends the run instead of leaving the browser open
+	if automation.StopRequested() {
+		logger.Info("Stop requested via control file - exiting")
+		return nil
+	}
+
+	logger.Info("Browser will remain open. Press Ctrl+C to exit.")
+
+	// Keep the browser open to see results before closing
+	select {}
+}
+
+// phaseCommand returns a handler that logs in, runs only the named phases of
+// the workflow and saves the daily snapshot, closing the browser when done
+func phaseCommand(name string, phases ...string) handler {
+	return func(args []string) error {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		if err := fs.Parse(args); err != nil {
+			return ignoreHelp(err)
+		}
+		return runPhaseCommand(name, phases, nil)
+	}
+}
+
+// searchCommand runs the people search only. Sending connection requests to
+// the profiles found, as run does, takes -connect.
+func searchCommand(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	connect := fs.Bool("connect", false, "also send connection requests to the profiles found (ENABLE_CONNECTIONS must be true)")
+	if err := fs.Parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+
+	return runPhaseCommand("search", []string{"search"}, func(r *runner) {
+		if !*connect {
+			r.connectionsAllowed = false
+		}
+	})
+}
+
+// runPhaseCommand logs in, runs the named phases and saves the daily snapshot.
+// configure, when set, adjusts the runner before the phases run.
+func runPhaseCommand(name string, phases []string, configure func(r *runner)) error {
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	r, closeBrowser, err := startRunner(db, newRateLimiter(db))
+	if err != nil {
+		return err
+	}
+	defer closeBrowser()
+
+	if configure != nil {
+		configure(r)
+	}
+	r.runPhases(phases)
+	r.saveSnapshot()
+
+	if stats, err := r.rateLimiter.GetDailyStats(); err == nil {
+		fmt.Println("\n" + stats)
+	}
+	if r.runErrors > 0 {
+		return fmt.Errorf("%s finished with %d errors", name, r.runErrors)
+	}
+	return nil
+}
+
+// reconcileCommand logs in and marks pending requests whose profiles are now
+// 1st-degree connections as accepted
+func reconcileCommand(args []string) error {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	r, closeBrowser, err := startRunner(db, automation.NewRateLimiter(db))
+	if err != nil {
+		return err
+	}
+	defer closeBrowser()
+
+	updated, err := automation.BulkReconcileFirstDegree(r.page, db)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile 1st-degree connections: %w", err)
+	}
+	fmt.Printf("Marked %d pending requests as accepted\n", updated)
+	return nil
+}
+
+// exportCommand writes the connection requests and their profiles as CSV
+func exportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("o", "connections.csv", "file to write the CSV to")
+	if err := fs.Parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Not standard output: the logger writes there too
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer file.Close()
+
+	count, err := db.ExportConnectionsCSV(file)
+	if err != nil {
+		return fmt.Errorf("failed to export connection requests: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Exported %d connection requests to %s", count, *output))
+	return nil
+}
+
+// backupCommand writes an encrypted copy of the whole database, keyed by
+// BACKUP_PASSPHRASE: backup <file>
+func backupCommand(args []string) error {
+	backupPath, passphrase, err := backupArgs("backup", args)
+	if err != nil {
+		return err
+	}
+
+	dbPath := databasePath()
+	if err := storage.BackupEncrypted(dbPath, backupPath, passphrase); err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Encrypted backup of %s written to %s", dbPath, backupPath))
+	return nil
+}
+
+// restoreCommand replaces the database with an encrypted backup, keyed by
+// BACKUP_PASSPHRASE: restore <file>
+func restoreCommand(args []string) error {
+	restorePath, passphrase, err := backupArgs("restore", args)
+	if err != nil {
+		return err
+	}
+
+	// A running automation has the database open and would keep writing to the replaced file
+	if pid, ok := browser.ProfileInUse(browser.DefaultUserDataDir); ok {
+		return fmt.Errorf("refusing to restore while a run is in progress (process %d holds the browser profile lock)", pid)
+	}
+
+	dbPath := databasePath()
+	if err := storage.RestoreEncrypted(restorePath, dbPath, passphrase); err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Restored %s from %s (any previous database kept as %s.before-restore)", dbPath, restorePath, dbPath))
+	return nil
+}
+
+// backupArgs reads the backup file named on the command line and the
+// passphrase, which comes from BACKUP_PASSPHRASE rather than a flag so it
+// stays out of shell history
+func backupArgs(name string, args []string) (string, string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return "", "", ignoreHelp(err)
+	}
+	if fs.NArg() != 1 {
+		return "", "", fmt.Errorf("usage: %s <file>", name)
+	}
+
+	passphrase := os.Getenv("BACKUP_PASSPHRASE")
+	if passphrase == "" {
+		return "", "", fmt.Errorf("set BACKUP_PASSPHRASE to encrypt or decrypt the backup")
+	}
+	return fs.Arg(0), passphrase, nil
+}
+
+// reportCommand prints a report from the database: report <trend|audit|visibility> [-days N]
+func reportCommand(args []string) error {
+	name := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	days := fs.Int("days", 7, "number of days to include in the report")
+	if err := fs.Parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	if name == "" {
+		name = fs.Arg(0)
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch name {
+	case "trend":
+		snapshots, err := db.GetSnapshotTrend(*days)
+		if err != nil {
+			return fmt.Errorf("failed to load snapshot trend: %w", err)
+		}
+		printSnapshotTrend(snapshots, *days)
+	case "audit":
+		entries, err := db.GetAuditLog(time.Now().AddDate(0, 0, -*days))
+		if err != nil {
+			return fmt.Errorf("failed to load audit log: %w", err)
+		}
+		printAuditLog(entries, *days)
+	case "visibility":
+		probes, err := db.GetVisibilityProbes(*days)
+		if err != nil {
+			return fmt.Errorf("failed to load visibility probes: %w", err)
+		}
+		printVisibilityProbes(probes, *days)
+	default:
+		return fmt.Errorf("unknown report %q (supported: trend, audit, visibility)", name)
+	}
+	return nil
+}
+
+// ignoreHelp turns the flag.ErrHelp of -h into a clean exit
+func ignoreHelp(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// createCampaignFromAccepted creates a messaging campaign over the accepted,
+// unmessaged connections using MESSAGE_TEMPLATE
+func createCampaignFromAccepted(db *storage.Database, name string) error {
+	templateID := os.Getenv("MESSAGE_TEMPLATE")
+	if templateID == "" {
+		templateID = "msg_introduction"
+	}
+
+	profiles, err := db.GetAcceptedConnectionProfiles(10000, 30, automation.GetMinDaysSinceAccepted())
+	if err != nil {
+		return fmt.Errorf("failed to get campaign targets: %w", err)
+	}
+
+	profileIDs := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		profileIDs = append(profileIDs, p.ID)
+	}
+
+	campaignID, err := db.CreateCampaign(name, templateID, profileIDs)
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Created campaign '%s' (#%d) with %d targets using %s", name, campaignID, len(profileIDs), templateID))
+	fmt.Printf("Set MESSAGING_CAMPAIGN_ID=%d to work through it\n", campaignID)
+	return nil
+}
+
+// printCampaignEstimate prints how long ESTIMATE_TARGETS profiles (default: the
+// uncontacted profiles already collected) would take at the configured limits
+func printCampaignEstimate(db *storage.Database) {
+	targets := 0
+	if envTargets := os.Getenv("ESTIMATE_TARGETS"); envTargets != "" {
+		fmt.Sscanf(envTargets, "%d", &targets)
+	} else if profiles, err := db.GetRecentProfiles(10000, 30); err == nil {
+		targets = len(profiles)
+	} else {
+		logger.Warning("Failed to count uncontacted profiles: " + err.Error())
+	}
+
+	duration, breakdown := automation.EstimateCampaignDuration(targets,
+		automation.GetDefaultRateLimitConfig(), automation.GetDefaultSchedule())
+	logger.Info(fmt.Sprintf("Campaign estimate: %s", breakdown))
+	fmt.Printf("Estimated campaign duration: %s\n", duration.Round(time.Minute))
+}
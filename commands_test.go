@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantName string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{"No arguments runs the workflow", nil, "run", nil, false},
+		{"Legacy flags run the workflow", []string{"--report", "trend"}, "run", []string{"--report", "trend"}, false},
+		{"Run", []string{"run", "-check"}, "run", []string{"-check"}, false},
+		{"Search", []string{"search"}, "search", []string{}, false},
+		{"Connect", []string{"connect"}, "connect", []string{}, false},
+		{"Message", []string{"message"}, "message", []string{}, false},
+		{"Reconcile", []string{"reconcile"}, "reconcile", []string{}, false},
+		{"Export", []string{"export", "-o", "out.csv"}, "export", []string{"-o", "out.csv"}, false},
+		{"Report", []string{"report", "audit", "-days", "3"}, "report", []string{"audit", "-days", "3"}, false},
+		{"Help", []string{"help"}, "help", nil, false},
+		{"Help flag", []string{"-h"}, "help", nil, false},
+		{"Unknown command", []string{"scrape"}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, err := parseCommand(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Expected %s %q, got %s %q", tt.wantName, tt.wantArgs, name, args)
+			}
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	var called string
+	var calledWith []string
+	handlers := make(map[string]handler)
+	for _, command := range commands {
+		name := command.name
+		handlers[name] = func(args []string) error {
+			called, calledWith = name, args
+			return nil
+		}
+	}
+
+	for _, command := range commands {
+		called = ""
+		if err := dispatch([]string{command.name, "-days", "3"}, handlers); err != nil {
+			t.Fatalf("%s: unexpected error: %v", command.name, err)
+		}
+		if called != command.name || !reflect.DeepEqual(calledWith, []string{"-days", "3"}) {
+			t.Errorf("Expected %s to get its arguments, %s got %q", command.name, called, calledWith)
+		}
+	}
+
+	called = ""
+	if err := dispatch(nil, handlers); err != nil || called != "run" {
+		t.Errorf("Expected no arguments to run the workflow, got %q (%v)", called, err)
+	}
+
+	failed := errors.New("login failed")
+	handlers["search"] = func([]string) error { return failed }
+	if err := dispatch([]string{"search"}, handlers); !errors.Is(err, failed) {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+
+	delete(handlers, "export")
+	if err := dispatch([]string{"export"}, handlers); err == nil {
+		t.Error("Expected an error for a command without a handler")
+	}
+}
+
+func TestCommandHandlersCoverCommands(t *testing.T) {
+	handlers := commandHandlers()
+	if len(handlers) != len(commands) {
+		t.Errorf("Expected %d handlers, got %d", len(commands), len(handlers))
+	}
+	for _, command := range commands {
+		if handlers[command.name] == nil {
+			t.Errorf("No handler for %s", command.name)
+		}
+	}
+}
+
+func TestBackupArgs(t *testing.T) {
+	t.Setenv("BACKUP_PASSPHRASE", "")
+	if _, _, err := backupArgs("backup", []string{"linkedin.enc"}); err == nil {
+		t.Error("Expected an error without BACKUP_PASSPHRASE")
+	}
+
+	t.Setenv("BACKUP_PASSPHRASE", "a long passphrase")
+	path, passphrase, err := backupArgs("backup", []string{"linkedin.enc"})
+	if err != nil || path != "linkedin.enc" || passphrase != "a long passphrase" {
+		t.Errorf("Expected the file and passphrase, got %q %q (%v)", path, passphrase, err)
+	}
+
+	for _, args := range [][]string{nil, {"a.enc", "b.enc"}} {
+		if _, _, err := backupArgs("restore", args); err == nil {
+			t.Errorf("Expected a usage error for %q", args)
+		}
+	}
+}
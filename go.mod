@@ -2,7 +2,10 @@ module linkedin-automation
 
 go 1.24.5
 
-require github.com/go-rod/rod v0.116.2
+require (
+	github.com/go-rod/rod v0.116.2
+	golang.org/x/crypto v0.48.0
+)
 
 require (
 	github.com/joho/godotenv v1.5.1 // indirect
@@ -0,0 +1,55 @@
+package automation
+
+import (
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/browser"
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditActionNavigate       = "navigate"
+	AuditActionSearch         = "search"
+	AuditActionSendConnection = "send_connection"
+	AuditActionSendMessage    = "send_message"
+)
+
+// audit writes an audit entry. Best-effort: a failed write is logged and never
+// aborts the action being audited. A nil db disables auditing.
+func audit(db *storage.Database, action, profileID, result, detail string) {
+	if db == nil {
+		return
+	}
+	if err := db.LogAudit(action, profileID, result, detail); err != nil {
+		logger.Warning("Failed to write audit log: " + err.Error())
+	}
+}
+
+// auditResult records the outcome of an action: ok with detail, or failed with the error
+func auditResult(db *storage.Database, action, profileID string, err error, detail string) {
+	if err != nil {
+		audit(db, action, profileID, storage.AuditFailed, err.Error())
+		return
+	}
+	audit(db, action, profileID, storage.AuditOK, detail)
+}
+
+// navigate loads url in page, recording the navigation and its result in the audit log.
+// It waits first if the previous page was opened too recently (see browser.EnforceNavGap)
+// and runs AfterPageLoad once the page has loaded.
+func navigate(page *rod.Page, db *storage.Database, url, profileID string) error {
+	browser.EnforceNavGap()
+	audit(db, AuditActionNavigate, profileID, storage.AuditStarted, url)
+	err := page.Navigate(url)
+	auditResult(db, AuditActionNavigate, profileID, err, url)
+	if err != nil {
+		return err
+	}
+
+	if err := page.WaitLoad(); err == nil {
+		AfterPageLoad(page)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package automation
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+// TestAuditResult tests that results are recorded as ok or failed with the error
+func TestAuditResult(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_audit.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	auditResult(db, AuditActionSendMessage, "alice", nil, "msg_introduction")
+	auditResult(db, AuditActionSendMessage, "bob", errors.New("message button not found"), "msg_introduction")
+
+	entries, err := db.GetAuditLog(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get audit log: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Result != storage.AuditOK || entries[0].Detail != "msg_introduction" {
+		t.Errorf("Unexpected ok entry: %+v", entries[0])
+	}
+	if entries[1].Result != storage.AuditFailed || entries[1].Detail != "message button not found" {
+		t.Errorf("Unexpected failed entry: %+v", entries[1])
+	}
+}
+
+// TestAuditBestEffort tests that audit failures never panic or abort the caller
+func TestAuditBestEffort(t *testing.T) {
+	audit(nil, AuditActionSearch, "", storage.AuditStarted, "")
+
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_audit_closed.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	db.Close()
+
+	// Writing to a closed database fails; the failure is only logged
+	audit(db, AuditActionSearch, "", storage.AuditStarted, "")
+}
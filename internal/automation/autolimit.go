@@ -0,0 +1,118 @@
+package automation
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+)
+
+// accountAgeRamp is the daily connection limit by account age: a new account
+// starts low and earns a higher limit as it ages
+var accountAgeRamp = []struct {
+	MinDays int
+	Limit   int
+}{
+	{0, 5},
+	{14, 8},
+	{30, 10},
+	{90, 14},
+	{180, 18},
+	{365, 20},
+}
+
+// Bounds of the recommended daily connection limit
+const (
+	autoLimitFloor   = 3
+	autoLimitCeiling = 25
+)
+
+// AutoLimitEnabled reports whether AUTO_LIMIT=true, which replaces
+// MAX_CONNECTIONS_PER_DAY with the recommended limit
+func AutoLimitEnabled() bool {
+	return os.Getenv("AUTO_LIMIT") == "true"
+}
+
+// GetAccountAgeDays reads the account age from ACCOUNT_CREATED (YYYY-MM-DD),
+// or from ACCOUNT_AGE_DAYS when that is set instead. Reports false when
+// neither is set or valid.
+func GetAccountAgeDays() (int, bool) {
+	return accountAgeDays(os.Getenv("ACCOUNT_CREATED"), os.Getenv("ACCOUNT_AGE_DAYS"), time.Now())
+}
+
+// accountAgeDays is GetAccountAgeDays at now
+func accountAgeDays(created, ageDays string, now time.Time) (int, bool) {
+	if created = strings.TrimSpace(created); created != "" {
+		day, err := time.ParseInLocation("2006-01-02", created, now.Location())
+		if err != nil || day.After(now) {
+			logger.Warning(fmt.Sprintf("Invalid ACCOUNT_CREATED %q, expected a past date as YYYY-MM-DD", created))
+			return 0, false
+		}
+		return int(now.Sub(day).Hours() / 24), true
+	}
+
+	if ageDays != "" {
+		if val, err := strconv.Atoi(ageDays); err == nil && val >= 0 {
+			return val, true
+		}
+		logger.Warning(fmt.Sprintf("Invalid ACCOUNT_AGE_DAYS %q", ageDays))
+	}
+	return 0, false
+}
+
+// RecommendDailyLimit suggests a MaxConnectionsPerDay for an account
+// accountAgeDays old: the age ramp, adjusted by the acceptance rate over the
+// acceptance guard's window once enough requests were sent to trust it.
+func RecommendDailyLimit(db *storage.Database, accountAgeDays int) int {
+	guard := GetAcceptanceGuardConfig()
+	rate, sample, err := db.GetRecentAcceptanceRate(guard.WindowDays, guard.MinAgeDays)
+	if err != nil {
+		logger.Warning("Failed to get acceptance rate, recommending by account age only: " + err.Error())
+		rate, sample = 0, 0
+	}
+	return recommendDailyLimit(accountAgeDays, rate, sample, guard.MinSample)
+}
+
+// recommendDailyLimit is the recommendation curve. A poor acceptance rate
+// (below 30%, or 20% for the steeper cut) scales the age ramp down; a good one
+// (50% and up) earns a quarter more. Rates from fewer than minSample requests
+// are ignored.
+func recommendDailyLimit(accountAgeDays int, rate float64, sample, minSample int) int {
+	limit := float64(rampLimit(accountAgeDays))
+
+	if sample >= minSample && sample > 0 {
+		switch {
+		case rate < 0.2:
+			limit *= 0.5
+		case rate < 0.3:
+			limit *= 0.75
+		case rate >= 0.5:
+			limit *= 1.25
+		}
+	}
+
+	recommended := int(math.Round(limit))
+	if recommended < autoLimitFloor {
+		recommended = autoLimitFloor
+	}
+	if recommended > autoLimitCeiling {
+		recommended = autoLimitCeiling
+	}
+	return recommended
+}
+
+// rampLimit returns the age ramp's limit for an account accountAgeDays old
+func rampLimit(accountAgeDays int) int {
+	limit := accountAgeRamp[0].Limit
+	for _, step := range accountAgeRamp {
+		if accountAgeDays >= step.MinDays {
+			limit = step.Limit
+		}
+	}
+	return limit
+}
@@ -0,0 +1,114 @@
+package automation
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+func TestRecommendDailyLimitCurve(t *testing.T) {
+	tests := []struct {
+		name   string
+		age    int
+		rate   float64
+		sample int
+		want   int
+	}{
+		{"brand new account, no history", 0, 0, 0, 5},
+		{"two weeks old", 14, 0, 0, 8},
+		{"one month old", 45, 0, 0, 10},
+		{"one year old", 400, 0, 0, 20},
+		{"rate from too few requests ignored", 400, 0.05, 10, 20},
+
+		{"young account, good rate", 20, 0.6, 40, 10},
+		{"young account, poor rate", 20, 0.25, 40, 6},
+		{"young account, very poor rate floored", 3, 0.1, 40, 3},
+		{"old account, good rate capped", 400, 0.7, 40, 25},
+		{"old account, average rate", 400, 0.4, 40, 20},
+		{"old account, poor rate", 400, 0.25, 40, 15},
+		{"old account, very poor rate", 400, 0.1, 40, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recommendDailyLimit(tt.age, tt.rate, tt.sample, 20); got != tt.want {
+				t.Errorf("recommendDailyLimit(%d, %.2f, %d) = %d, want %d", tt.age, tt.rate, tt.sample, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendDailyLimitGrowsWithAge(t *testing.T) {
+	previous := 0
+	for age := 0; age <= 500; age += 5 {
+		limit := recommendDailyLimit(age, 0, 0, 20)
+		if limit < previous {
+			t.Fatalf("Limit dropped from %d to %d at %d days", previous, limit, age)
+		}
+		previous = limit
+	}
+}
+
+func TestRecommendDailyLimitUsesAcceptanceHistory(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_autolimit.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if got := RecommendDailyLimit(db, 400); got != 20 {
+		t.Errorf("Expected the age ramp without history, got %d", got)
+	}
+
+	// 20 recent requests old enough to judge, 2 accepted: a 10% rate halves the limit
+	for i := 0; i < 20; i++ {
+		status := "pending"
+		if i < 2 {
+			status = "accepted"
+		}
+		req := storage.ConnectionRequest{
+			ProfileID: fmt.Sprintf("profile-%d", i),
+			SentAt:    time.Now().AddDate(0, 0, -4).Add(-time.Duration(i) * time.Hour),
+			Status:    status,
+		}
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+
+	if got := RecommendDailyLimit(db, 400); got != 10 {
+		t.Errorf("Expected a poor acceptance rate to halve the limit to 10, got %d", got)
+	}
+}
+
+func TestAccountAgeDays(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		created string
+		ageDays string
+		want    int
+		wantOK  bool
+	}{
+		{"creation date", "2026-01-30", "", 30, true},
+		{"age in days", "", "90", 90, true},
+		{"creation date wins", "2026-02-22", "90", 7, true},
+		{"neither set", "", "", 0, false},
+		{"future date", "2026-04-01", "", 0, false},
+		{"bad date", "03/01/2025", "", 0, false},
+		{"negative age", "", "-3", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := accountAgeDays(tt.created, tt.ageDays, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("accountAgeDays(%q, %q) = (%d, %v), want (%d, %v)", tt.created, tt.ageDays, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package automation
+
+import (
+	"regexp"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/pkg/utils"
+)
+
+// openToWorkPattern matches the #OpenToWork frame in a photo's markup
+var openToWorkPattern = regexp.MustCompile(utils.OpenToWorkPattern)
+
+// hasOpenToWorkBadge reports whether a search card's photo markup carries the
+// #OpenToWork frame. The frame is a ring drawn over the photo, so there is no
+// text to read; the ring element's class or image and the photo's alt text give it away.
+func hasOpenToWorkBadge(photoHTML string) bool {
+	return openToWorkPattern.MatchString(photoHTML)
+}
+
+// openToWorkFromContainer reports whether a search result's photo has the #OpenToWork frame
+func openToWorkFromContainer(container *rod.Element) bool {
+	photos, err := container.Elements(utils.Selectors.SearchResultPhoto)
+	if err != nil {
+		return false
+	}
+
+	for _, photo := range photos {
+		if html, err := photo.HTML(); err == nil && hasOpenToWorkBadge(html) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package automation
+
+import "testing"
+
+func TestHasOpenToWorkBadge(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected bool
+	}{
+		{
+			"frame ring overlay",
+			`<div class="ivm-image-view-model"><div class="ivm-view-attr__img-wrapper"><img class="presence-entity__image" alt="Jane Doe" src="https://media.licdn.com/dms/image/photo.jpg"></div><div class="ivm-view-attr__frame-wrapper"><img class="ivm-view-attr__frame--open-to-work" src="https://static.licdn.com/aero-v1/sc/h/frame.svg"></div></div>`,
+			true,
+		},
+		{
+			"alt text",
+			`<div class="entity-result__universal-image"><img alt="Jane Doe, #OPEN_TO_WORK" src="https://media.licdn.com/dms/image/photo.jpg"></div>`,
+			true,
+		},
+		{
+			"frame image name",
+			`<div class="ivm-image-view-model"><img src="https://media.licdn.com/dms/image/D4E35AQ/profile-framedphoto-shrink_100_100/0/1700000000000?e=1&amp;v=beta&amp;t=OpenToWork"></div>`,
+			true,
+		},
+		{
+			"hiring frame",
+			`<div class="ivm-image-view-model"><img alt="John Roe, #HIRING" src="https://media.licdn.com/dms/image/photo.jpg"><img class="ivm-view-attr__frame--hiring"></div>`,
+			false,
+		},
+		{
+			"plain photo",
+			`<div class="entity-result__universal-image"><img class="presence-entity__image" alt="John Roe" src="https://media.licdn.com/dms/image/photo.jpg"></div>`,
+			false,
+		},
+		{"no photo", "", false},
+	}
+
+	for _, test := range tests {
+		if got := hasOpenToWorkBadge(test.html); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+)
+
+// maxCampaignAttempts is how many failed sends a campaign target gets, over
+// one or more runs, before it is marked failed
+const maxCampaignAttempts = 3
+
+// maxConsecutiveCampaignFailures stops a campaign run after this many sends in
+// a row fail; something is wrong with the page or session, not the targets
+const maxConsecutiveCampaignFailures = 3
+
+// targetHeld is what workCampaignTarget returns for a target MESSAGE_WHITELIST
+// refuses. It is never stored: the target stays pending, without using up an
+// attempt, so it is sent once the whitelist allows it.
+const targetHeld = "held"
+
+// CampaignRunStats tracks what one run of a messaging campaign did
+type CampaignRunStats struct {
+	Sent    int
+	Skipped int
+	Failed  int
+	Held    int  // Left pending because MESSAGE_WHITELIST refused them
+	Done    bool // Every target has been worked
+}
+
+// RunMessagingCampaign works through a campaign's pending targets, up to
+// MAX_MESSAGES_PER_RUN attempts per run and within the daily message limit.
+// Progress is stored per target, so a campaign picks up where it left off
+// after a restart. A target whose send fails stays pending for the next run,
+// up to maxCampaignAttempts failures.
+func RunMessagingCampaign(page *rod.Page, db *storage.Database, rateLimiter *RateLimiter, campaignID int64) (CampaignRunStats, error) {
+	maxMessages := 3
+	if os.Getenv("MAX_MESSAGES_PER_RUN") != "" {
+		fmt.Sscanf(os.Getenv("MAX_MESSAGES_PER_RUN"), "%d", &maxMessages)
+	}
+
+	senderVars := TemplateVariables{
+		YourName:     os.Getenv("YOUR_NAME"),
+		YourTitle:    os.Getenv("YOUR_TITLE"),
+		YourCompany:  os.Getenv("YOUR_COMPANY"),
+		Industry:     os.Getenv("YOUR_INDUSTRY"),
+		CustomReason: os.Getenv("MESSAGE_CUSTOM_REASON"),
+	}
+
+	return runMessagingCampaign(db, campaignID, maxMessages, senderVars,
+		func() error { return rateLimiter.CheckDailyLimit(TaskMessage) },
+		func(req MessageRequest) error { return SendMessage(page, db, req) },
+		func() { rateLimiter.RecordAction(TaskMessage) },
+	)
+}
+
+// runMessagingCampaign is RunMessagingCampaign with injectable rate limiting and sending.
+// checkLimit stops the run when it returns an error; recordAction is called after each sent message.
+// Sent and failed targets both count towards maxMessages.
+func runMessagingCampaign(db *storage.Database, campaignID int64, maxMessages int, senderVars TemplateVariables,
+	checkLimit func() error, send func(MessageRequest) error, recordAction func()) (CampaignRunStats, error) {
+	var stats CampaignRunStats
+
+	campaign, err := db.GetCampaign(campaignID)
+	if err != nil {
+		return stats, fmt.Errorf("failed to load campaign %d: %w", campaignID, err)
+	}
+
+	logger.Info(fmt.Sprintf("Running messaging campaign '%s' (#%d)", campaign.Name, campaign.ID))
+
+	var lastID int64
+	consecutiveFailures := 0
+	for stats.Sent+stats.Failed < maxMessages {
+		// Honor the PAUSE / STOP control files between messages
+		if err := WaitWhilePaused(context.Background()); err != nil {
+			logger.Warning("Pausing campaign: " + err.Error())
+			break
+		}
+
+		if err := checkLimit(); err != nil {
+			logger.Warning("Messaging rate limit reached - pausing campaign: " + err.Error())
+			break
+		}
+
+		target, err := db.NextPendingTarget(campaignID, lastID)
+		if err != nil {
+			return stats, fmt.Errorf("failed to get next campaign target: %w", err)
+		}
+		if target == nil {
+			break
+		}
+		lastID = target.ID
+
+		status := workCampaignTarget(db, campaign, target, senderVars, send)
+		switch status {
+		case storage.TargetSent:
+			stats.Sent++
+			consecutiveFailures = 0
+			recordAction()
+		case storage.TargetSkipped:
+			stats.Skipped++
+		case targetHeld:
+			stats.Held++
+			continue
+		default:
+			stats.Failed++
+			consecutiveFailures++
+		}
+
+		if status == storage.TargetPending {
+			failed, err := db.RecordTargetFailure(target.ID, maxCampaignAttempts)
+			if err != nil {
+				return stats, fmt.Errorf("failed to record campaign progress: %w", err)
+			}
+			if failed {
+				logger.Warning(fmt.Sprintf("Campaign target %s failed %d times, not retrying", target.ProfileID, maxCampaignAttempts))
+			}
+		} else if err := db.MarkTargetDone(target.ID, status); err != nil {
+			return stats, fmt.Errorf("failed to record campaign progress: %w", err)
+		}
+
+		if consecutiveFailures >= maxConsecutiveCampaignFailures {
+			logger.Warning(fmt.Sprintf("%d sends in a row failed - pausing campaign until the next run", consecutiveFailures))
+			break
+		}
+	}
+
+	// Targets left pending this run are retried next run
+	progress, err := db.GetCampaignProgress(campaignID)
+	if err == nil {
+		stats.Done = progress[storage.TargetPending] == 0
+		logger.Info(fmt.Sprintf("Campaign '%s': %d sent, %d skipped, %d failed, %d held by MESSAGE_WHITELIST this run (%d still pending)",
+			campaign.Name, stats.Sent, stats.Skipped, stats.Failed, stats.Held, progress[storage.TargetPending]))
+	}
+	if stats.Done {
+		logger.Info(fmt.Sprintf("Campaign '%s' complete - all targets worked", campaign.Name))
+	}
+
+	return stats, nil
+}
+
+// workCampaignTarget messages one target and returns its resulting status.
+// TargetPending means a send that may work on a later try failed; targetHeld
+// means MESSAGE_WHITELIST refused the target.
+func workCampaignTarget(db *storage.Database, campaign *storage.Campaign, target *storage.CampaignTarget,
+	senderVars TemplateVariables, send func(MessageRequest) error) string {
+	profile, err := db.GetProfile(target.ProfileID)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Campaign target %s not found: %s", target.ProfileID, err.Error()))
+		return storage.TargetFailed
+	}
+
+	// A message recorded for this template means an earlier run sent it but
+	// stopped before saving progress - don't send it twice
+	alreadySent, err := db.HasSentMessage(profile.ID, campaign.TemplateID)
+	if err != nil {
+		logger.Warning("Failed to check message history: " + err.Error())
+		return storage.TargetPending
+	}
+	if alreadySent {
+		logger.Info(fmt.Sprintf("Already messaged %s with %s - skipping", profile.Name, campaign.TemplateID))
+		return storage.TargetSkipped
+	}
+
+	req, err := PrepareMessageFromProfile(*profile, campaign.TemplateID, senderVars)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to prepare message for %s: %s", profile.Name, err.Error()))
+		return storage.TargetFailed
+	}
+
+	if err := send(*req); errors.Is(err, ErrNotWhitelisted) {
+		logger.Info(fmt.Sprintf("Leaving %s pending: %s", profile.Name, err.Error()))
+		return targetHeld
+	} else if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send message to %s: %s", profile.Name, err.Error()))
+		return storage.TargetPending
+	}
+
+	return storage.TargetSent
+}
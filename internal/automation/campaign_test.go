@@ -0,0 +1,245 @@
+package automation
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+// seedCampaign creates profiles and a campaign targeting them, in order
+func seedCampaign(t *testing.T, db *storage.Database, profileIDs []string) int64 {
+	t.Helper()
+	for _, id := range profileIDs {
+		profile := storage.Profile{
+			ID:         id,
+			Name:       id + " Tester",
+			Title:      "Engineer",
+			Company:    "Acme",
+			ProfileURL: "https://www.linkedin.com/in/" + id + "/",
+			VisitedAt:  time.Now(),
+		}
+		if err := db.SaveProfile(profile); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+	}
+
+	campaignID, err := db.CreateCampaign("Intro campaign", "msg_introduction", profileIDs)
+	if err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+	return campaignID
+}
+
+// fakeSender records sends into the messages table like SendMessage does
+func fakeSender(db *storage.Database, sent *[]string, failFor string) func(MessageRequest) error {
+	return func(req MessageRequest) error {
+		if req.ProfileID == failFor {
+			return errors.New("message button not found")
+		}
+		*sent = append(*sent, req.ProfileID)
+		return db.SaveMessage(storage.Message{
+			ConnectionID:   req.ProfileID,
+			TemplateName:   req.TemplateID,
+			MessageContent: req.Body,
+			SentAt:         time.Now(),
+			CreatedAt:      time.Now(),
+		})
+	}
+}
+
+func TestRunMessagingCampaignResumesAfterRestart(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_campaign.db")
+	db, err := storage.InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	campaignID := seedCampaign(t, db, []string{"alice", "bob", "carol", "dave"})
+	noLimit := func() error { return nil }
+	recorded := 0
+	record := func() { recorded++ }
+
+	// First run: two attempts per run, bob fails and counts as one
+	var sent []string
+	stats, err := runMessagingCampaign(db, campaignID, 2, TemplateVariables{YourName: "Sam"}, noLimit, fakeSender(db, &sent, "bob"), record)
+	if err != nil {
+		t.Fatalf("First run failed: %v", err)
+	}
+	if stats.Sent != 1 || stats.Failed != 1 || stats.Done {
+		t.Errorf("Unexpected first run stats: %+v", stats)
+	}
+	if len(sent) != 1 || sent[0] != "alice" {
+		t.Errorf("Expected only alice messaged, got %v", sent)
+	}
+	db.Close()
+
+	// Simulated restart: progress comes from the database, and bob is retried
+	db, err = storage.InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	sent = nil
+	stats, err = runMessagingCampaign(db, campaignID, 5, TemplateVariables{YourName: "Sam"}, noLimit, fakeSender(db, &sent, ""), record)
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+	if len(sent) != 3 || sent[0] != "bob" || sent[1] != "carol" || sent[2] != "dave" {
+		t.Errorf("Expected bob, carol and dave messaged after restart, got %v", sent)
+	}
+	if !stats.Done {
+		t.Errorf("Expected campaign to be complete, got %+v", stats)
+	}
+	if recorded != 4 {
+		t.Errorf("Expected 4 recorded actions, got %d", recorded)
+	}
+}
+
+func TestRunMessagingCampaignStopsAfterConsecutiveFailures(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_campaign.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	campaignID := seedCampaign(t, db, []string{"alice", "bob", "carol", "dave", "erin"})
+	attempts := 0
+	failing := func(MessageRequest) error {
+		attempts++
+		return errors.New("message button not found")
+	}
+
+	stats, err := runMessagingCampaign(db, campaignID, 10, TemplateVariables{}, func() error { return nil }, failing, func() {})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if attempts != maxConsecutiveCampaignFailures || stats.Failed != maxConsecutiveCampaignFailures {
+		t.Errorf("Expected the run to stop after %d failures, got %d attempts (%+v)", maxConsecutiveCampaignFailures, attempts, stats)
+	}
+
+	// Failed sends stay pending for the next run
+	progress, err := db.GetCampaignProgress(campaignID)
+	if err != nil || progress[storage.TargetPending] != 5 {
+		t.Errorf("Expected every target still pending, got %v (err %v)", progress, err)
+	}
+}
+
+func TestRunMessagingCampaignGivesUpAfterMaxAttempts(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_campaign.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	campaignID := seedCampaign(t, db, []string{"alice"})
+	for run := 1; run <= maxCampaignAttempts; run++ {
+		var sent []string
+		if _, err := runMessagingCampaign(db, campaignID, 5, TemplateVariables{}, func() error { return nil }, fakeSender(db, &sent, "alice"), func() {}); err != nil {
+			t.Fatalf("Run %d failed: %v", run, err)
+		}
+	}
+
+	progress, err := db.GetCampaignProgress(campaignID)
+	if err != nil || progress[storage.TargetFailed] != 1 || progress[storage.TargetPending] != 0 {
+		t.Errorf("Expected alice failed after %d attempts, got %v (err %v)", maxCampaignAttempts, progress, err)
+	}
+}
+
+func TestRunMessagingCampaignHoldsTargetsOffWhitelist(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_campaign.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	campaignID := seedCampaign(t, db, []string{"alice", "bob"})
+	whitelisted := func(whitelist string, sent *[]string) func(MessageRequest) error {
+		return func(req MessageRequest) error {
+			return sendIfWhitelisted(req, parseMessageWhitelist(whitelist), fakeSender(db, sent, ""))
+		}
+	}
+
+	// More runs than alice has attempts: a refusal must not use them up
+	for run := 1; run <= maxCampaignAttempts+1; run++ {
+		var sent []string
+		stats, err := runMessagingCampaign(db, campaignID, 5, TemplateVariables{}, func() error { return nil }, whitelisted("bob", &sent), func() {})
+		if err != nil {
+			t.Fatalf("Run %d failed: %v", run, err)
+		}
+		if stats.Held != 1 || stats.Failed != 0 || stats.Skipped != 0 || stats.Done {
+			t.Errorf("Run %d: expected alice held and the campaign unfinished, got %+v", run, stats)
+		}
+	}
+
+	// Once the whitelist allows alice she gets her message
+	var sent []string
+	stats, err := runMessagingCampaign(db, campaignID, 5, TemplateVariables{}, func() error { return nil }, whitelisted("alice,bob", &sent), func() {})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if stats.Sent != 1 || !stats.Done || len(sent) != 1 || sent[0] != "alice" {
+		t.Errorf("Expected alice messaged once whitelisted, got %+v (sent %v)", stats, sent)
+	}
+}
+
+func TestRunMessagingCampaignSkipsAlreadyMessaged(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_campaign.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	campaignID := seedCampaign(t, db, []string{"alice", "bob"})
+
+	// A previous run sent to alice but stopped before saving campaign progress
+	if err := db.SaveMessage(storage.Message{ConnectionID: "alice", TemplateName: "msg_introduction", MessageContent: "Hi", SentAt: time.Now(), CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to save message: %v", err)
+	}
+
+	var sent []string
+	stats, err := runMessagingCampaign(db, campaignID, 5, TemplateVariables{}, func() error { return nil }, fakeSender(db, &sent, ""), func() {})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if stats.Skipped != 1 || stats.Sent != 1 || len(sent) != 1 || sent[0] != "bob" {
+		t.Errorf("Expected alice skipped and bob messaged, got %+v (sent %v)", stats, sent)
+	}
+}
+
+func TestRunMessagingCampaignStopsAtRateLimit(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_campaign.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	campaignID := seedCampaign(t, db, []string{"alice", "bob"})
+
+	calls := 0
+	limit := func() error {
+		calls++
+		if calls > 1 {
+			return &RateLimitError{TaskType: TaskMessage, Current: 1, Limit: 1}
+		}
+		return nil
+	}
+
+	var sent []string
+	stats, err := runMessagingCampaign(db, campaignID, 5, TemplateVariables{}, limit, fakeSender(db, &sent, ""), func() {})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if stats.Sent != 1 || stats.Done {
+		t.Errorf("Expected one message before the limit, got %+v", stats)
+	}
+
+	// bob is left pending for the next run
+	target, err := db.NextPendingTarget(campaignID, 0)
+	if err != nil || target == nil || target.ProfileID != "bob" {
+		t.Errorf("Expected bob still pending, got %+v (err %v)", target, err)
+	}
+}
@@ -0,0 +1,59 @@
+package automation
+
+import (
+	"math/rand"
+	"os"
+	"strconv"
+	"text/template"
+
+	"linkedin-automation/pkg/utils"
+)
+
+// ClauseConfig sets how often the optional profile clauses of a note are
+// included, so notes don't mention the same fields in the same slots every time
+type ClauseConfig struct {
+	CompanyProbability float64 // Chance MentionCompany is set for a render (0-1)
+	TitleProbability   float64 // Chance MentionTitle is set for a render (0-1)
+}
+
+// DefaultClauseConfig mentions the company 70% of the time and the title 50%
+func DefaultClauseConfig() ClauseConfig {
+	return ClauseConfig{CompanyProbability: 0.7, TitleProbability: 0.5}
+}
+
+// GetClauseConfig returns the clause probabilities, overridden by
+// NOTE_MENTION_COMPANY_PERCENT and NOTE_MENTION_TITLE_PERCENT (0-100)
+func GetClauseConfig() ClauseConfig {
+	config := DefaultClauseConfig()
+
+	if v := os.Getenv("NOTE_MENTION_COMPANY_PERCENT"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val >= 0 && val <= 100 {
+			config.CompanyProbability = float64(val) / 100
+		}
+	}
+
+	if v := os.Getenv("NOTE_MENTION_TITLE_PERCENT"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val >= 0 && val <= 100 {
+			config.TitleProbability = float64(val) / 100
+		}
+	}
+
+	return config
+}
+
+// RandomizeClauses draws the per-render Mention* flags for vars. A field that
+// is empty is never mentioned, whatever the probability.
+func RandomizeClauses(vars TemplateVariables, config ClauseConfig, r *rand.Rand) TemplateVariables {
+	vars.MentionCompany = vars.Company != "" && r.Float64() < config.CompanyProbability
+	vars.MentionTitle = vars.Title != "" && r.Float64() < config.TitleProbability
+	return vars
+}
+
+// templateFuncs are the helpers available to message and note templates.
+// chance N is true N% of the time, for one-off optional clauses:
+// {{if chance 30}}...{{end}}
+var templateFuncs = template.FuncMap{
+	"chance": func(percent int) bool {
+		return utils.SessionRand().Intn(100) < percent
+	},
+}
@@ -0,0 +1,130 @@
+package automation
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestRandomizeClausesRates(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	vars := TemplateVariables{FirstName: "Jane", Company: "Acme", Title: "Staff Engineer"}
+
+	const renders = 10000
+	company, title := 0, 0
+	for i := 0; i < renders; i++ {
+		drawn := RandomizeClauses(vars, DefaultClauseConfig(), r)
+		if drawn.MentionCompany {
+			company++
+		}
+		if drawn.MentionTitle {
+			title++
+		}
+	}
+
+	if rate := float64(company) / renders; rate < 0.67 || rate > 0.73 {
+		t.Errorf("Expected the company mentioned ~70%% of the time, got %.1f%%", rate*100)
+	}
+	if rate := float64(title) / renders; rate < 0.47 || rate > 0.53 {
+		t.Errorf("Expected the title mentioned ~50%% of the time, got %.1f%%", rate*100)
+	}
+}
+
+func TestRandomizeClausesBounds(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	vars := TemplateVariables{Company: "Acme", Title: "Staff Engineer"}
+
+	for i := 0; i < 100; i++ {
+		if drawn := RandomizeClauses(vars, ClauseConfig{CompanyProbability: 1, TitleProbability: 0}, r); !drawn.MentionCompany || drawn.MentionTitle {
+			t.Fatalf("Probabilities 1 and 0 should always and never mention, got %+v", drawn)
+		}
+	}
+
+	// Empty fields are never mentioned
+	for i := 0; i < 100; i++ {
+		if drawn := RandomizeClauses(TemplateVariables{}, ClauseConfig{CompanyProbability: 1, TitleProbability: 1}, r); drawn.MentionCompany || drawn.MentionTitle {
+			t.Fatalf("Empty fields should never be mentioned, got %+v", drawn)
+		}
+	}
+}
+
+func TestVariedTemplateRendersEveryCombination(t *testing.T) {
+	tmpl, err := GetTemplateByID("conn_varied")
+	if err != nil {
+		t.Fatalf("GetTemplateByID() error = %v", err)
+	}
+
+	for _, mentionCompany := range []bool{false, true} {
+		for _, mentionTitle := range []bool{false, true} {
+			vars := TemplateVariables{
+				FirstName:      "Jane",
+				Company:        "Acme Corporation International",
+				Title:          "Senior Director of Platform Engineering",
+				MentionCompany: mentionCompany,
+				MentionTitle:   mentionTitle,
+			}
+
+			note, err := RenderTemplate(*tmpl, vars)
+			if err != nil {
+				t.Fatalf("MentionCompany=%v MentionTitle=%v: RenderTemplate() error = %v", mentionCompany, mentionTitle, err)
+			}
+			if err := ValidateMessageLength(note, TemplateConnectionRequest); err != nil {
+				t.Errorf("Rendered note does not validate: %v", err)
+			}
+			if strings.Contains(note, vars.Company) != mentionCompany {
+				t.Errorf("MentionCompany=%v but note is %q", mentionCompany, note)
+			}
+			if strings.Contains(note, vars.Title) != mentionTitle {
+				t.Errorf("MentionTitle=%v but note is %q", mentionTitle, note)
+			}
+		}
+	}
+}
+
+func TestChanceTemplateHelper(t *testing.T) {
+	render := func(body string) string {
+		t.Helper()
+		note, err := RenderTemplate(MessageTemplate{ID: "chance", Type: TemplateFollowUp, Body: body, MaxLength: MessageMaxLength}, TemplateVariables{FirstName: "Jane"})
+		if err != nil {
+			t.Fatalf("RenderTemplate() error = %v", err)
+		}
+		return note
+	}
+
+	if note := render("Hi {{.FirstName}}{{if chance 100}}, hope you're well{{end}}."); note != "Hi Jane, hope you're well." {
+		t.Errorf("chance 100 should always include the clause, got %q", note)
+	}
+	if note := render("Hi {{.FirstName}}{{if chance 0}}, hope you're well{{end}}."); note != "Hi Jane." {
+		t.Errorf("chance 0 should never include the clause, got %q", note)
+	}
+
+	included := 0
+	for i := 0; i < 2000; i++ {
+		if strings.Contains(render("Hi {{.FirstName}}{{if chance 50}}, hope you're well{{end}}."), "hope") {
+			included++
+		}
+	}
+	if included < 850 || included > 1150 {
+		t.Errorf("chance 50 included the clause %d/2000 times", included)
+	}
+}
+
+func TestGetClauseConfig(t *testing.T) {
+	t.Setenv("NOTE_MENTION_COMPANY_PERCENT", "")
+	t.Setenv("NOTE_MENTION_TITLE_PERCENT", "")
+	if config := GetClauseConfig(); config != DefaultClauseConfig() {
+		t.Errorf("Expected defaults, got %+v", config)
+	}
+
+	t.Setenv("NOTE_MENTION_COMPANY_PERCENT", "100")
+	t.Setenv("NOTE_MENTION_TITLE_PERCENT", "0")
+	if config := GetClauseConfig(); config.CompanyProbability != 1 || config.TitleProbability != 0 {
+		t.Errorf("Expected 1 and 0, got %+v", config)
+	}
+
+	t.Setenv("NOTE_MENTION_COMPANY_PERCENT", "150")
+	t.Setenv("NOTE_MENTION_TITLE_PERCENT", "abc")
+	if config := GetClauseConfig(); config != DefaultClauseConfig() {
+		t.Errorf("Invalid values should fall back to defaults, got %+v", config)
+	}
+}
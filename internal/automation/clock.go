@@ -0,0 +1,65 @@
+package automation
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time, and waits, so scheduling and rate limiting
+// can be tested at arbitrary moments (weekends, midnight, DST transitions)
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// realClock reads the system wall clock
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// SystemClock is the Clock used when none is injected
+var SystemClock Clock = realClock{}
+
+// MockClock is a Clock whose time only changes when set or advanced.
+// It is safe for concurrent use.
+type MockClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewMockClock creates a mock clock frozen at t
+func NewMockClock(t time.Time) *MockClock {
+	return &MockClock{now: t}
+}
+
+// Now returns the mock's current time
+func (c *MockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the mock clock to t
+func (c *MockClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Sleep returns at once, advancing the mock clock by d
+func (c *MockClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// Advance moves the mock clock forward by d
+func (c *MockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
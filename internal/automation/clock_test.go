@@ -0,0 +1,47 @@
+package automation
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMockClock(t *testing.T) {
+	start := time.Date(2025, 12, 30, 10, 0, 0, 0, time.UTC)
+	clock := NewMockClock(start)
+
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v, got %v", start, clock.Now())
+	}
+
+	clock.Advance(90 * time.Minute)
+	if expected := start.Add(90 * time.Minute); !clock.Now().Equal(expected) {
+		t.Errorf("Expected %v after Advance, got %v", expected, clock.Now())
+	}
+
+	later := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock.Set(later)
+	if !clock.Now().Equal(later) {
+		t.Errorf("Expected %v after Set, got %v", later, clock.Now())
+	}
+}
+
+func TestMockClockConcurrentUse(t *testing.T) {
+	start := time.Date(2025, 12, 30, 10, 0, 0, 0, time.UTC)
+	clock := NewMockClock(start)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clock.Advance(time.Minute)
+			_ = clock.Now()
+		}()
+	}
+	wg.Wait()
+
+	if expected := start.Add(50 * time.Minute); !clock.Now().Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, clock.Now())
+	}
+}
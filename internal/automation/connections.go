@@ -1,13 +1,19 @@
 package automation
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/proto"
 
+	"linkedin-automation/internal/browser"
 	"linkedin-automation/internal/logger"
 	"linkedin-automation/internal/stealth"
 	"linkedin-automation/internal/storage"
@@ -26,6 +32,9 @@ type ConnectionRequest struct {
 	RequestedAt time.Time
 }
 
+// weeklyLimitPattern matches the weekly invitation limit message
+var weeklyLimitPattern = regexp.MustCompile(utils.WeeklyLimitTextPattern)
+
 // MessageRequest represents a message to be sent
 type MessageRequest struct {
 	ProfileID  string
@@ -44,9 +53,15 @@ type ConnectionStats struct {
 	Failed           int
 	AlreadyConnected int
 	Pending          int // Track pending connections separately
+	CompanyCapped    int // Skipped because the company reached its daily cap
+	Passed           int // My Network suggestions deliberately passed over
+	Dismissed        int // My Network suggestions dismissed
 	Errors           []string
 	StartTime        time.Time
 	EndTime          time.Time
+
+	// TemplateID of each successful request, in send order
+	SentTemplateIDs []string
 }
 
 // MessagingStats tracks statistics for messages sent
@@ -54,6 +69,7 @@ type MessagingStats struct {
 	TotalAttempted int
 	Successful     int
 	Failed         int
+	Skipped        int // Not on MESSAGE_WHITELIST
 	Errors         []string
 	StartTime      time.Time
 	EndTime        time.Time
@@ -62,22 +78,43 @@ type MessagingStats struct {
 // SendConnectionRequest sends a connection request to a LinkedIn profile
 //
 // Edge Cases Handled:
-// 1. Already Connected - Checks for "Connected" status and returns specific error
-// 2. Already Pending - Checks for "Pending" status and returns specific error (not counted as failure)
-// 3. 3rd-Degree Connections - If Connect button not visible, clicks "More..." dropdown to find it
+// 1. Already Connected - Checks the profile API response (or the "Connected" status in the DOM) and returns specific error
+// 2. Already Pending - Same check for "Pending" status, returns specific error (not counted as failure)
+// 3. 3rd-Degree Connections - If Connect button not visible, scrolls to reveal the sticky
+// action bar, then clicks "More..." dropdown to find it
 // 4. Note Addition - Adds personalized note if provided and textarea is available
 //
-// Returns:
+// Returns (check with errors.Is):
 // - nil if connection request sent successfully
-// - error with "already connected" if already connected
-// - error with "connection pending" if request already pending
-// - error if Connect button not found even in More... dropdown
+// - ErrAlreadyConnected if already connected
+// - ErrConnectionPending if request already pending
+// - ErrConnectDisabled / ErrInvitationSent if the page shows an invitation already out
+// - ErrConnectButtonNotFound if Connect button not found even in More... dropdown
+// - ErrWeeklyLimit if LinkedIn's weekly invitation limit was hit
+// - ErrCheckpoint if LinkedIn asks for manual verification
 func SendConnectionRequest(page *rod.Page, db *storage.Database, request ConnectionRequest) error {
+	audit(db, AuditActionSendConnection, request.ProfileID, storage.AuditStarted, request.ProfileURL)
+	err := sendConnectionRequest(page, db, request)
+	auditResult(db, AuditActionSendConnection, request.ProfileID, err, request.ProfileURL)
+	return err
+}
+
+// sendConnectionRequest does the work of SendConnectionRequest
+func sendConnectionRequest(page *rod.Page, db *storage.Database, request ConnectionRequest) error {
 	logger.Info(fmt.Sprintf("Sending connection request to: %s (%s)", request.Name, request.ProfileID))
 
+	// Notes may be built outside RenderTemplate; never type characters the note field rejects
+	request.Note = SanitizeNote(request.Note)
+
+	// Capture the profile API response so the relationship can be read from LinkedIn's own data
+	if err := browser.WatchProfileResponses(page); err != nil {
+		logger.Warning("Relationship API check unavailable: " + err.Error())
+	}
+	browser.ClearProfileResponses(page)
+
 	// Navigate to profile page
 	logger.Info("Navigating to profile: " + request.ProfileURL)
-	err := page.Navigate(request.ProfileURL)
+	err := navigate(page, db, request.ProfileURL, request.ProfileID)
 	if err != nil {
 		return fmt.Errorf("failed to navigate to profile: %w", err)
 	}
@@ -88,29 +125,644 @@ func SendConnectionRequest(page *rod.Page, db *storage.Database, request Connect
 	currentURL := page.MustInfo().URL
 	if utils.IsLinkedInCheckpoint(currentURL) {
 		logger.Error("❌ LinkedIn checkpoint/verification detected at: " + currentURL)
-		return fmt.Errorf("linkedin checkpoint detected, manual verification required")
+		return fmt.Errorf("opening profile %s: %w", request.ProfileID, ErrCheckpoint)
 	}
 	stealth.RandomDelay(2000, 3000)
 
-	// Apply random scroll to simulate reading profile
+	// Apply random scroll to simulate reading profile, dwelling longer on long profiles
 	stealth.RandomScroll(page)
-	stealth.RandomDelay(1000, 2000)
+	stealth.IdleNoise(page)
+	stealth.ReadingDelay(page)
 
-	// Check if already connected
-	// Use Timeout to avoid hanging if element doesn't exist
-	alreadyConnectedMessage, _ := page.Timeout(2 * time.Second).Element(utils.AlreadyConnectedSelector)
-	if alreadyConnectedMessage != nil {
-		logger.Info("Already connected with " + request.Name)
-		return fmt.Errorf("already connected")
+	// Prefer the relationship state from the profile API response; the DOM is the fallback
+	state, err := GetRelationshipState(page)
+	if err != nil {
+		logger.Debug("Relationship state not available from API, checking the page: " + err.Error())
+		state = relationshipStateFromDOM(page)
 	}
 
-	// Check if connection request is pending
-	pendingMessage, _ := page.Timeout(2 * time.Second).Element(utils.PendingConnectionSelector)
-	if pendingMessage != nil {
+	switch state {
+	case RelationshipConnected:
+		logger.Info("Already connected with " + request.Name)
+		return fmt.Errorf("%s: %w", request.Name, ErrAlreadyConnected)
+	case RelationshipPending:
 		logger.Info("Connection request already pending for " + request.Name)
-		return fmt.Errorf("connection pending")
+		recordPendingInvitation(db, request)
+		return fmt.Errorf("%s: %w", request.Name, ErrConnectionPending)
+	}
+
+	// The page is the ground truth: a greyed-out Connect or an "Invitation sent"
+	// pill means an invitation is already out, whatever the database says
+	if err := classifyConnectControl(readConnectControl(page)); err != nil {
+		logger.Info(fmt.Sprintf("Invitation already out for %s (%s)", request.Name, err.Error()))
+		recordPendingInvitation(db, request)
+		return fmt.Errorf("%s: %w", request.Name, err)
+	}
+
+	// Look for the Connect button, optionally reloading once if the page hadn't hydrated
+	connectButton, err := findConnectButtonWithRetry(
+		func() (*rod.Element, error) { return findConnectButton(page) },
+		func() error { return reloadProfile(page) },
+		retryConnectOnMiss(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Scroll button into view
+	err = connectButton.ScrollIntoView()
+	if err != nil {
+		return fmt.Errorf("failed to scroll connect button into view: %w", err)
+	}
+
+	stealth.RandomDelay(500, 1000)
+
+	// Click Connect button
+	logger.Info("Clicking Connect button...")
+	err = stealth.SafeClick(page, connectButton)
+	if err != nil {
+		return fmt.Errorf("failed to click connect button: %w", err)
+	}
+
+	stealth.RandomDelay(1500, 2500)
+
+	// Connect usually opens a modal, but on some profiles it navigates to a
+	// full-page invite form instead
+	flow := detectInviteFlow(page, func() bool {
+		return classifyConnectControl(readConnectControl(page)) != nil
+	})
+	switch flow {
+	case inviteFlowPage:
+		logger.Info("Connect opened the full-page invite form")
+		if err := page.WaitLoad(); err != nil {
+			return fmt.Errorf("failed to load invite page: %w", err)
+		}
+	case inviteFlowNone:
+		logger.Warning("Modal did not appear after clicking Connect. Checking if request was sent automatically...")
+	}
+
+	if err := completeInvite(page, request.Note, flow); err != nil {
+		return err
+	}
+	page.MustWaitLoad()
+
+	// LinkedIn shows an alert instead of sending once the weekly invitation cap is hit
+	if weeklyLimitReached(page) {
+		logger.Error("LinkedIn weekly invitation limit reached")
+		return fmt.Errorf("sending to %s: %w", request.Name, ErrWeeklyLimit)
+	}
+
+	// Capture proof of the sent request when audit screenshots are enabled
+	evidencePath := captureEvidence(auditScreenshotsEnabled(), func() (string, error) {
+		return browser.SaveScreenshot(page, auditScreenshotDir(), "connection_"+request.ProfileID)
+	})
+
+	// Save to database
+	if db != nil {
+		connectionReq := storage.ConnectionRequest{
+			ProfileID:    request.ProfileID,
+			SentAt:       time.Now(),
+			NoteUsed:     request.Note,
+			TemplateID:   request.TemplateID,
+			EvidencePath: evidencePath,
+			Status:       "pending",
+		}
+
+		err = db.SaveConnectionRequest(connectionReq)
+		if err != nil {
+			logger.Warning("Failed to save connection request to database: " + err.Error())
+		}
+	}
+
+	logger.InfoFields("Connection request sent successfully to "+request.Name, map[string]any{
+		"profile_id": request.ProfileID,
+		"action":     AuditActionSendConnection,
+		"note":       request.Note != "",
+	})
+	return nil
+}
+
+// completeInvite finishes the invite UI that Connect opened: answers the
+// relationship question, adds note (if any) and clicks Send. There is nothing
+// to do when Connect sent the invitation straight away.
+func completeInvite(page *rod.Page, note string, flow inviteFlow) error {
+	if flow == inviteFlowSent {
+		logger.Info("Connect sent the invitation straight away")
+		if note != "" {
+			logger.Warning("The invitation went out without the note: LinkedIn showed no invite modal")
+		}
+		return nil
+	}
+
+	// Some modals ask "How do you know this person?" before Send is enabled
+	if err := handleRelationshipStep(page); err != nil {
+		return fmt.Errorf("failed to answer relationship question: %w", err)
+	}
+
+	if note != "" {
+		logger.Info("Adding personalized note...")
+		if err := addConnectionNote(page, note, flow); err != nil {
+			return err
+		}
+	}
+
+	// Find and click the "Send" button
+	logger.Info("Looking for Send button...")
+	sendButton := findSendButton(page)
+	if sendButton == nil {
+		return fmt.Errorf("send button not found")
+	}
+
+	stealth.RandomDelay(500, 1000)
+
+	logger.Info("Clicking Send button...")
+	if err := stealth.SafeClick(page, sendButton); err != nil {
+		return fmt.Errorf("failed to click send button: %w", err)
+	}
+
+	stealth.RandomDelay(2000, 3000)
+	return nil
+}
+
+// findSendButton returns the visible Send button of the invite, or nil if there is none
+func findSendButton(page *rod.Page) *rod.Element {
+	// Selectors for Send button
+	sendSelectors := []string{
+		utils.Selectors.SendConnectionButton,
+		"button[aria-label='Send now']",
+		"button[aria-label='Send invitation']",
+		"button.artdeco-button--primary:has-text('Send')",
+		"button:has-text('Send without a note')", // Fallback if note failed
+	}
+
+	for _, sel := range sendSelectors {
+		btn, err := page.Timeout(2 * time.Second).Element(sel)
+		if err == nil && btn != nil {
+			if visible, _ := btn.Visible(); visible {
+				return btn
+			}
+		}
+	}
+
+	// Try finding by text regex as last resort
+	sendButton, _ := page.Timeout(2*time.Second).ElementR("button", `\bSend\b`)
+	return sendButton
+}
+
+// inviteFlow is the UI LinkedIn shows after Connect is clicked
+type inviteFlow int
+
+const (
+	inviteFlowNone  inviteFlow = iota // Neither appeared (the request may have been sent directly)
+	inviteFlowModal                   // "Add a note" modal over the profile
+	inviteFlowPage                    // Separate full-page invite form
+	inviteFlowSent                    // No invite UI; Connect already turned into Pending
+)
+
+// Polling of the page after Connect is clicked (see waitForInviteUI)
+const inviteUIPollInterval = 250 * time.Millisecond
+
+// GetInviteUITimeout reads INVITE_MODAL_TIMEOUT_SECONDS (default 7): how long to
+// wait after Connect for the invite modal, the invite page or a Pending state
+func GetInviteUITimeout() time.Duration {
+	if v := os.Getenv("INVITE_MODAL_TIMEOUT_SECONDS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			return time.Duration(val) * time.Second
+		}
+	}
+	return 7 * time.Second
+}
+
+// inviteUIState is what the page shows at one poll after Connect was clicked
+type inviteUIState struct {
+	URL           string
+	HasModal      bool
+	HasInviteForm bool
+	Pending       bool // Connect turned into Pending (or a greyed-out Connect)
+}
+
+// waitForInviteUI polls probe until the invite modal or invite page shows, or
+// until Pending has been seen on two polls in a row (a modal that is still
+// animating in wins over a momentary Pending). Gives up once timeout has
+// passed on clock and returns the last state seen.
+func waitForInviteUI(probe func() inviteUIState, clock Clock, timeout, interval time.Duration, sleep func(time.Duration)) inviteUIState {
+	deadline := clock.Now().Add(timeout)
+	pendingPolls := 0
+
+	for {
+		state := probe()
+		if state.HasModal || state.HasInviteForm || isInvitePageURL(state.URL) {
+			return state
+		}
+		if state.Pending {
+			pendingPolls++
+			if pendingPolls >= 2 {
+				return state
+			}
+		} else {
+			pendingPolls = 0
+		}
+
+		if !clock.Now().Before(deadline) {
+			return state
+		}
+		sleep(interval)
+	}
+}
+
+// invitePagePaths are URL paths of the full-page invite form
+var invitePagePaths = []string{"/preload/custom-invite", "/inviteconnect", "/people/invite"}
+
+// isInvitePageURL reports whether rawURL is the full-page invite form
+func isInvitePageURL(rawURL string) bool {
+	lower := strings.ToLower(rawURL)
+	for _, path := range invitePagePaths {
+		if strings.Contains(lower, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// chooseInviteFlow decides which invite UI is showing. The modal wins when
+// present; otherwise the invite page URL or an invite form without a modal
+// around it means the full-page variant.
+func chooseInviteFlow(currentURL string, hasModal, hasInviteForm bool) inviteFlow {
+	if hasModal {
+		return inviteFlowModal
+	}
+	if isInvitePageURL(currentURL) || hasInviteForm {
+		return inviteFlowPage
+	}
+	return inviteFlowNone
+}
+
+// detectInviteFlow waits for the page to react to the Connect click, going on
+// as soon as the modal, the invite page or a Pending state (reported by
+// pending) shows, up to GetInviteUITimeout
+func detectInviteFlow(page *rod.Page, pending func() bool) inviteFlow {
+	probe := func() inviteUIState {
+		var state inviteUIState
+		if info, err := page.Info(); err == nil {
+			state.URL = info.URL
+		}
+		state.HasModal, _, _ = page.Has(".artdeco-modal")
+		state.HasInviteForm, _, _ = page.Has(utils.Selectors.InvitePageForm)
+		state.Pending = pending()
+		return state
+	}
+
+	state := waitForInviteUI(probe, SystemClock, GetInviteUITimeout(), inviteUIPollInterval, time.Sleep)
+	if flow := chooseInviteFlow(state.URL, state.HasModal, state.HasInviteForm); flow != inviteFlowNone || !state.Pending {
+		return flow
+	}
+	return inviteFlowSent
+}
+
+// addConnectionNote types note into the invite. The modal hides the textarea
+// behind "Add a note"; the full-page form usually shows it straight away.
+func addConnectionNote(page *rod.Page, note string, flow inviteFlow) error {
+	if flow == inviteFlowPage {
+		if noteInput, kind := findNoteInput(page); noteInput != nil {
+			return typeConnectionNote(noteInput, kind, note)
+		}
+	}
+
+	// Look for "Add a note" button
+	addNoteButton, _ := page.Timeout(3 * time.Second).Element(utils.Selectors.AddNoteButton)
+	if addNoteButton == nil {
+		// Try finding by text
+		addNoteButton, _ = page.Timeout(3*time.Second).ElementR("button", "Add a note")
+	}
+
+	if addNoteButton == nil {
+		logger.Warning("Add a note button not found, skipping note.")
+		return nil
+	}
+
+	// Click "Add a note" button
+	if err := stealth.SafeClick(page, addNoteButton); err != nil {
+		logger.Warning("Failed to click Add Note button: " + err.Error())
+		return nil
+	}
+	stealth.RandomDelay(1000, 1500)
+
+	noteInput, kind := findNoteInput(page)
+	if noteInput == nil {
+		logger.Warning("Note input not found")
+		return nil
+	}
+	return typeConnectionNote(noteInput, kind, note)
+}
+
+// noteInputKind is how text is typed into the note field
+type noteInputKind int
+
+const (
+	noteInputField    noteInputKind = iota // <textarea> or <input>, typed with input events
+	noteInputEditable                      // contenteditable rich-text box (div[role=textbox])
+)
+
+// noteInputCandidate is the first element matching one of the note field selectors
+type noteInputCandidate struct {
+	Tag             string // Lower-case tag name
+	ContentEditable bool
+	Visible         bool
+}
+
+// noteInputSelectors returns the note field selectors in priority order
+func noteInputSelectors() []string {
+	return append([]string{utils.Selectors.ConnectionNoteTextarea}, utils.Selectors.ConnectionNoteInputs...)
+}
+
+// chooseNoteInput returns the index of the first visible candidate that
+// accepts text and how to type into it, or -1 if none does. Candidates are in
+// selector priority order; a nil entry means the selector matched nothing.
+func chooseNoteInput(candidates []*noteInputCandidate) (int, noteInputKind) {
+	for i, c := range candidates {
+		if c == nil || !c.Visible {
+			continue
+		}
+		switch {
+		case c.Tag == "textarea" || c.Tag == "input":
+			return i, noteInputField
+		case c.ContentEditable:
+			return i, noteInputEditable
+		}
+		// Anything else (e.g. a wrapper div carrying the id) can't take text
+	}
+	return -1, noteInputField
+}
+
+// findNoteInput returns the connection note field and how to type into it, or
+// nil if the page has none. It waits up to 3 seconds for any variant to appear.
+func findNoteInput(page *rod.Page) (*rod.Element, noteInputKind) {
+	selectors := noteInputSelectors()
+	if _, err := page.Timeout(3 * time.Second).Element(strings.Join(selectors, ", ")); err != nil {
+		return nil, noteInputField
+	}
+
+	elements := make([]*rod.Element, len(selectors))
+	candidates := make([]*noteInputCandidate, len(selectors))
+	for i, selector := range selectors {
+		has, el, err := page.Has(selector)
+		if err != nil || !has {
+			continue
+		}
+		elements[i] = el
+		candidates[i] = describeNoteInput(el)
+	}
+
+	i, kind := chooseNoteInput(candidates)
+	if i < 0 {
+		return nil, noteInputField
+	}
+	logger.Debug("Note input found with selector: " + selectors[i])
+	return elements[i], kind
+}
+
+// describeNoteInput reads what chooseNoteInput needs to know about el
+func describeNoteInput(el *rod.Element) *noteInputCandidate {
+	c := &noteInputCandidate{}
+	if tag, err := el.Property("tagName"); err == nil {
+		c.Tag = strings.ToLower(tag.Str())
+	}
+	if editable, err := el.Property("isContentEditable"); err == nil {
+		c.ContentEditable = editable.Bool()
+	}
+	c.Visible, _ = el.Visible()
+	return c
+}
+
+// typeConnectionNote types note into the note field with human-like typing
+func typeConnectionNote(noteInput *rod.Element, kind noteInputKind, note string) error {
+	logger.Info(fmt.Sprintf("Typing note (%d characters)...", utf8.RuneCountInString(note)))
+
+	typeText := stealth.TypeLikeHuman
+	if kind == noteInputEditable {
+		typeText = stealth.TypeIntoEditable
+	}
+	if err := typeText(noteInput, note); err != nil {
+		return fmt.Errorf("failed to type note: %w", err)
+	}
+	stealth.RandomDelay(1000, 2000)
+	return nil
+}
+
+// relationshipStateFromDOM checks the profile page for the connected / pending markers.
+// Uses Timeout to avoid hanging if the elements don't exist.
+func relationshipStateFromDOM(page *rod.Page) string {
+	if el, _ := page.Timeout(2 * time.Second).Element(utils.Selectors.AlreadyConnected); el != nil {
+		return RelationshipConnected
+	}
+	if el, _ := page.Timeout(2 * time.Second).Element(utils.Selectors.PendingConnection); el != nil {
+		return RelationshipPending
+	}
+	return RelationshipNone
+}
+
+// weeklyLimitReached checks the page for LinkedIn's weekly invitation limit alert
+func weeklyLimitReached(page *rod.Page) bool {
+	alerts, err := page.Timeout(2 * time.Second).Elements(utils.Selectors.WeeklyLimitAlert)
+	if err != nil {
+		return false
+	}
+
+	for _, alert := range alerts {
+		text, err := alert.Text()
+		if err == nil && isWeeklyLimitText(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWeeklyLimitText reports whether alert text is the weekly invitation limit message
+func isWeeklyLimitText(text string) bool {
+	return weeklyLimitPattern.MatchString(text)
+}
+
+// auditScreenshotsEnabled reports whether AUDIT_SCREENSHOTS mode is on
+func auditScreenshotsEnabled() bool {
+	return os.Getenv("AUDIT_SCREENSHOTS") == "true"
+}
+
+// auditScreenshotDir returns where audit screenshots are stored (AUDIT_SCREENSHOT_DIR)
+func auditScreenshotDir() string {
+	if dir := os.Getenv("AUDIT_SCREENSHOT_DIR"); dir != "" {
+		return dir
+	}
+	return "./data/screenshots"
+}
+
+// captureEvidence runs capture when audit mode is enabled and returns the saved path.
+// Failures are logged and yield an empty path - evidence never blocks a send.
+func captureEvidence(enabled bool, capture func() (string, error)) string {
+	if !enabled {
+		return ""
+	}
+
+	path, err := capture()
+	if err != nil {
+		logger.Warning("Failed to capture audit screenshot: " + err.Error())
+		return ""
+	}
+
+	logger.Info("Audit screenshot saved: " + path)
+	return path
+}
+
+// dropdownConnectPattern matches the Connect item in the "More" dropdown, which
+// reads "Connect" or "Invite to connect" (sometimes "Invite <name> to connect").
+// Written to be valid both as a Go regexp and a JavaScript RegExp.
+const dropdownConnectPattern = `^\s*(Connect|Invite( .+)? to connect)\s*$`
+
+var dropdownConnectRegex = regexp.MustCompile(dropdownConnectPattern)
+
+// isDropdownConnectLabel reports whether a dropdown item's text is the Connect action
+func isDropdownConnectLabel(text string) bool {
+	return dropdownConnectRegex.MatchString(text)
+}
+
+// connectSearchPlan says where findConnectButton looks for the Connect button
+type connectSearchPlan struct {
+	Direct       bool // Search the profile header for a visible Connect button
+	StickyBar    bool // On a direct miss, scroll to reveal the sticky action bar and search it
+	MoreDropdown bool // Open the "More" dropdown and look for the Connect item
+}
+
+// planConnectSearch chooses the search for a profile. Creator-mode profiles show
+// "Follow" as the primary button and hide Connect under More, so the direct and
+// sticky bar searches are skipped to avoid matching an unrelated button.
+func planConnectSearch(creatorMode bool) connectSearchPlan {
+	if creatorMode {
+		return connectSearchPlan{MoreDropdown: true}
+	}
+	return connectSearchPlan{Direct: true, StickyBar: true, MoreDropdown: true}
+}
+
+// searchWithStickyRetry runs the direct search and, when it misses and sticky
+// is set, the sticky action bar search once. LinkedIn A/B tests where the
+// Connect button renders; in some variants only the sticky header that appears
+// on scroll has it. Returns nil when both miss.
+func searchWithStickyRetry(direct, stickyBar func() *rod.Element, sticky bool) *rod.Element {
+	if btn := direct(); btn != nil {
+		return btn
+	}
+	if !sticky {
+		return nil
+	}
+	return stickyBar()
+}
+
+// RevealStickyActions scrolls past the profile header so LinkedIn renders its
+// sticky header, and returns that header's action bar if it appeared
+func RevealStickyActions(page *rod.Page) (*rod.Element, bool) {
+	r := utils.SessionRand()
+	if err := page.Mouse.Scroll(0, float64(600+r.Intn(300)), 3+r.Intn(3)); err != nil {
+		logger.Warning("Failed to scroll for the sticky action bar: " + err.Error())
+		return nil, false
+	}
+	stealth.RandomDelay(800, 1500)
+
+	bar, err := page.Timeout(3 * time.Second).Element(utils.Selectors.ProfileStickyActions)
+	if err != nil || bar == nil {
+		return nil, false
+	}
+	if visible, _ := bar.Visible(); !visible {
+		return nil, false
+	}
+	return bar, true
+}
+
+// findConnectInActions returns the visible Connect button in a profile action bar, or nil
+func findConnectInActions(actionsEl *rod.Element) *rod.Element {
+	// Try text-based search first
+	btn, err := actionsEl.ElementR("button", `\bConnect\b`)
+	if err == nil && btn != nil {
+		if visible, _ := btn.Visible(); visible {
+			return btn
+		}
+	}
+
+	// Fallback to selector-based search inside actions bar
+	selectors := []string{
+		utils.Selectors.ConnectButton,
+		utils.Selectors.ConnectButtonAlt,
+		"button[aria-label='Connect']",
+		"button[aria-label='Invite to connect']",
+	}
+
+	for _, sel := range selectors {
+		btn, err := actionsEl.Element(sel)
+		if err == nil && btn != nil {
+			if visible, _ := btn.Visible(); visible {
+				logger.Info("Found Connect button by selector in actions bar: " + sel)
+				return btn
+			}
+		}
+	}
+	return nil
+}
+
+// findDirectConnect searches the profile header for a visible Connect button:
+// the actions toolbar first, then anywhere in <main> (never the sidebar)
+func findDirectConnect(mainEl *rod.Element) *rod.Element {
+	if mainEl == nil {
+		return nil
+	}
+
+	// Strategy 1: Look inside the profile actions toolbar
+	logger.Info("Strategy 1: Searching for Connect button in main profile actions bar...")
+	if actionsEl, _ := mainEl.Element(".pvs-profile-actions"); actionsEl != nil {
+		if btn := findConnectInActions(actionsEl); btn != nil {
+			return btn
+		}
+	}
+
+	// Strategy 2: Fallback to searching within <main> only (still avoids sidebar)
+	logger.Info("Strategy 2: Searching for Connect button within <main>...")
+	btn, err := mainEl.ElementR("button", `\bConnect\b`)
+	if err == nil && btn != nil {
+		if visible, _ := btn.Visible(); visible {
+			logger.Info("Found Connect button by text within <main>")
+			return btn
+		}
+	}
+	return nil
+}
+
+// isCreatorMode reports whether a profile is in creator mode: it shows the creator
+// badge or its primary action is Follow
+func isCreatorMode(hasBadge bool, primaryLabel string) bool {
+	return hasBadge || strings.HasPrefix(strings.TrimSpace(primaryLabel), "Follow")
+}
+
+// detectCreatorMode inspects the profile header for creator mode
+func detectCreatorMode(mainEl *rod.Element) bool {
+	if mainEl == nil {
+		return false
+	}
+
+	hasBadge := false
+	if badge, err := mainEl.Element(utils.Selectors.CreatorBadge); err == nil && badge != nil {
+		hasBadge, _ = badge.Visible()
+	}
+
+	var primaryLabel string
+	if actionsEl, _ := mainEl.Element(".pvs-profile-actions"); actionsEl != nil {
+		if primary, err := actionsEl.Element("button.artdeco-button--primary"); err == nil && primary != nil {
+			primaryLabel, _ = primary.Text()
+		}
 	}
 
+	return isCreatorMode(hasBadge, primaryLabel)
+}
+
+// findConnectButton locates the Connect button on a profile page, including
+// inside the "More" dropdown. Returns "already connected" if only a Message
+// button is present, or ErrConnectButtonNotFound.
+func findConnectButton(page *rod.Page) (*rod.Element, error) {
 	// Look for "Connect" button
 	// IMPORTANT: We must avoid sidebar suggestions and only act on
 	// the primary profile header. To do this we scope our searches
@@ -123,59 +775,35 @@ func SendConnectionRequest(page *rod.Page, db *storage.Database, request Connect
 	var mainEl *rod.Element
 	mainEl, _ = page.Timeout(3 * time.Second).Element("main")
 
-	// Strategy 1: Look inside the profile actions toolbar
-	if mainEl != nil {
-		logger.Info("Strategy 1: Searching for Connect button in main profile actions bar...")
-		actionsEl, _ := mainEl.Element(".pvs-profile-actions")
-		if actionsEl != nil {
-			// Try text-based search first
-			btn, err := actionsEl.ElementR("button", `\bConnect\b`)
-			if err == nil && btn != nil {
-				if visible, _ := btn.Visible(); visible {
-					connectButton = btn
-					found = true
-				}
-			}
+	plan := planConnectSearch(detectCreatorMode(mainEl))
+	if !plan.Direct {
+		logger.Info("Creator-mode profile (Follow is the primary button), going straight to the 'More' dropdown")
+	}
 
-			// Fallback to selector-based search inside actions bar
-			if !found {
-				selectors := []string{
-					utils.ConnectButtonSelector,
-					utils.ConnectButtonAltSelector,
-					"button[aria-label='Connect']",
-					"button[aria-label='Invite to connect']",
+	// Strategies 1 and 2: the profile header, then (on a miss) the sticky
+	// action bar revealed by scrolling down
+	if plan.Direct {
+		connectButton = searchWithStickyRetry(
+			func() *rod.Element { return findDirectConnect(mainEl) },
+			func() *rod.Element {
+				logger.Info("Strategy 2b: Scrolling to reveal the sticky action bar...")
+				bar, ok := RevealStickyActions(page)
+				if !ok {
+					return nil
 				}
-
-				for _, sel := range selectors {
-					btn, err := actionsEl.Element(sel)
-					if err == nil && btn != nil {
-						if visible, _ := btn.Visible(); visible {
-							logger.Info("Found Connect button by selector in actions bar: " + sel)
-							connectButton = btn
-							found = true
-							break
-						}
-					}
+				btn := findConnectInActions(bar)
+				if btn != nil {
+					logger.Info("Found Connect button in the sticky action bar")
 				}
-			}
-		}
-	}
-
-	// Strategy 2: Fallback to searching within <main> only (still avoids sidebar)
-	if !found && mainEl != nil {
-		logger.Info("Strategy 2: Searching for Connect button within <main>...")
-		btn, err := mainEl.ElementR("button", `\bConnect\b`)
-		if err == nil && btn != nil {
-			if visible, _ := btn.Visible(); visible {
-				logger.Info("Found Connect button by text within <main>")
-				connectButton = btn
-				found = true
-			}
-		}
+				return btn
+			},
+			plan.StickyBar,
+		)
+		found = connectButton != nil
 	}
 
 	// Strategy 3: Check "More" dropdown (scoped to main/profile header only)
-	if !found {
+	if plan.MoreDropdown && !found {
 		logger.Info("Connect button not found directly. Checking 'More' dropdown in main profile area...")
 
 		var moreButton *rod.Element
@@ -190,8 +818,8 @@ func SendConnectionRequest(page *rod.Page, db *storage.Database, request Connect
 		}
 
 		moreSelectors := []string{
-			utils.MoreActionsButtonSelector,
-			utils.MoreActionsButtonAltSelector,
+			utils.Selectors.MoreActionsButton,
+			utils.Selectors.MoreActionsButtonAlt,
 			"button[aria-label='More actions']",
 			"button:has-text('More')",
 		}
@@ -238,17 +866,20 @@ func SendConnectionRequest(page *rod.Page, db *storage.Database, request Connect
 			logger.Info("Clicking More... button")
 			moreButton.ScrollIntoView()
 			stealth.RandomDelay(500, 1000)
-			moreButton.Click(proto.InputMouseButtonLeft, 1)
+			if err := stealth.SafeClick(page, moreButton); err != nil {
+				logger.Warning("Failed to click More button: " + err.Error())
+			}
 			stealth.RandomDelay(1000, 1500)
 
-			dropdownConnectSelectors := []string{
-				"div[role='button']:has-text('Connect')",
-				"span:has-text('Connect')",
-				".artdeco-dropdown__item:has-text('Connect')",
+			// Items are labelled "Connect" or "Invite to connect"
+			dropdownItemSelectors := []string{
+				"div[role='menu'] div[role='button']",
+				".artdeco-dropdown__item",
+				"div[role='menu'] span",
 			}
 
-			for _, sel := range dropdownConnectSelectors {
-				btn, err := page.Timeout(2 * time.Second).Element(sel)
+			for _, sel := range dropdownItemSelectors {
+				btn, err := page.Timeout(2*time.Second).ElementR(sel, dropdownConnectPattern)
 				if err == nil && btn != nil {
 					if visible, _ := btn.Visible(); visible {
 						logger.Info("Found Connect button in dropdown")
@@ -262,7 +893,7 @@ func SendConnectionRequest(page *rod.Page, db *storage.Database, request Connect
 			// Fallback: JS-based search strictly inside the open dropdown menu
 			if !found {
 				logger.Info("Dropdown selectors failed, running JS-based menu scan for 'Connect' item...")
-				js := `() => {
+				js := `(pattern) => {
 					const menus = Array.from(document.querySelectorAll("div[role='menu']"));
 					if (!menus.length) return null;
 					// Prefer visible menu
@@ -273,13 +904,14 @@ func SendConnectionRequest(page *rod.Page, db *storage.Database, request Connect
 						.map(el => (el.innerText || '').trim())
 						.filter(t => t);
 					console.log('DEBUG_MENU_ITEMS', texts);
-					// Find the first element whose visible text is exactly 'Connect'
+					// Find the first element whose visible text is the Connect action
+					const re = new RegExp(pattern);
 					const candidates = Array.from(root.querySelectorAll("*"));
-					const target = candidates.find(el => (el.innerText || '').trim() === 'Connect');
+					const target = candidates.find(el => re.test((el.innerText || '').trim()));
 					return target || null;
 				}`
 
-				btn, err := page.Timeout(3 * time.Second).ElementByJS(rod.Eval(js))
+				btn, err := page.Timeout(3 * time.Second).ElementByJS(rod.Eval(js, dropdownConnectPattern))
 				if err == nil && btn != nil {
 					if visible, _ := btn.Visible(); visible {
 						logger.Info("Found Connect button in dropdown via JS scan")
@@ -299,161 +931,221 @@ func SendConnectionRequest(page *rod.Page, db *storage.Database, request Connect
 		// already connected: presence of a primary Message button
 		// without any Connect option.
 		logger.Info("Connect button not found, checking if profile is already connected...")
-		msgButton, _ := page.Timeout(2 * time.Second).Element(utils.MessageButtonSelector)
+		msgButton, _ := page.Timeout(2 * time.Second).Element(utils.Selectors.MessageButton)
 		if msgButton == nil {
-			msgButton, _ = page.Timeout(2 * time.Second).Element(utils.MessageButtonAltSelector)
+			msgButton, _ = page.Timeout(2 * time.Second).Element(utils.Selectors.MessageButtonAlt)
 		}
 		if msgButton != nil {
 			if visible, _ := msgButton.Visible(); visible {
 				logger.Info("Message button present but no Connect button - treating as already connected")
-				return fmt.Errorf("already connected")
+				return nil, fmt.Errorf("message button without connect option: %w", ErrAlreadyConnected)
 			}
 		}
 
-		return fmt.Errorf("connect button not found - profile may be out of network")
+		return nil, ErrConnectButtonNotFound
 	}
 
-	// Scroll button into view
-	err = connectButton.ScrollIntoView()
-	if err != nil {
-		return fmt.Errorf("failed to scroll connect button into view: %w", err)
-	}
+	return connectButton, nil
+}
 
-	stealth.RandomDelay(500, 1000)
+// retryConnectOnMiss reports whether a missing Connect button should be retried
+// once after reloading the profile (RETRY_CONNECT_ON_MISS)
+func retryConnectOnMiss() bool {
+	return os.Getenv("RETRY_CONNECT_ON_MISS") == "true"
+}
 
-	// Click Connect button
-	logger.Info("Clicking Connect button...")
-	err = connectButton.Click(proto.InputMouseButtonLeft, 1)
-	if err != nil {
-		return fmt.Errorf("failed to click connect button: %w", err)
+// findConnectButtonWithRetry runs find and, when retry is enabled and the button
+// is missing, reloads the profile once and runs the full search again. A missing
+// button often means the page hadn't hydrated rather than that the profile is out of network.
+func findConnectButtonWithRetry(find func() (*rod.Element, error), reload func() error, retry bool) (*rod.Element, error) {
+	btn, err := find()
+	if err == nil || !retry || !errors.Is(err, ErrConnectButtonNotFound) {
+		return btn, err
 	}
 
-	stealth.RandomDelay(1500, 2500)
-	// Wait for modal to appear (don't use MustWaitLoad as it might not trigger a full page load)
-
-	// Check if "Add a note" modal appeared
-	// We need to wait a bit for the modal animation
-	time.Sleep(2 * time.Second)
-
-	// Check for modal presence
-	_, err = page.Timeout(5 * time.Second).Element(".artdeco-modal")
-	if err != nil {
-		logger.Warning("Modal did not appear after clicking Connect. Checking if request was sent automatically...")
+	logger.Warning("Connect button not found, reloading profile and retrying once...")
+	if err := reload(); err != nil {
+		return nil, fmt.Errorf("failed to reload profile for retry: %w", err)
 	}
 
-	if request.Note != "" {
-		logger.Info("Adding personalized note...")
-
-		// Look for "Add a note" button
-		addNoteButton, _ := page.Timeout(3 * time.Second).Element(utils.AddNoteButtonSelector)
-		if addNoteButton == nil {
-			// Try finding by text
-			addNoteButton, _ = page.Timeout(3*time.Second).ElementR("button", "Add a note")
-		}
+	return find()
+}
 
-		if addNoteButton != nil {
-			// Click "Add a note" button
-			err = addNoteButton.Click(proto.InputMouseButtonLeft, 1)
-			if err != nil {
-				logger.Warning("Failed to click Add Note button: " + err.Error())
-			} else {
-				stealth.RandomDelay(1000, 1500)
+// reloadProfile reloads the current profile page and lets it hydrate
+func reloadProfile(page *rod.Page) error {
+	if err := page.Reload(); err != nil {
+		return err
+	}
+	if err := page.WaitLoad(); err != nil {
+		return err
+	}
+	stealth.RandomDelay(2000, 3000)
+	stealth.RandomScroll(page)
+	return nil
+}
 
-				// Find the note textarea
-				noteTextarea, err := page.Timeout(3 * time.Second).Element(utils.ConnectionNoteTextareaSelector)
-				if err != nil || noteTextarea == nil {
-					noteTextarea, err = page.Timeout(3 * time.Second).Element("textarea[name='message']")
-				}
+// connectionRelationship returns the "How do you know X?" option to select,
+// from CONNECTION_RELATIONSHIP (default "Other")
+func connectionRelationship() string {
+	if relationship := strings.TrimSpace(os.Getenv("CONNECTION_RELATIONSHIP")); relationship != "" {
+		return relationship
+	}
+	return utils.DefaultRelationshipOption
+}
 
-				if err == nil && noteTextarea != nil {
-					// Remove timeout context from the element for long operations like typing
-					noteTextarea = noteTextarea.CancelTimeout()
+// handleRelationshipStep detects the "How do you know X?" variant of the Connect
+// modal, selects the configured relationship and continues to the Send step.
+// Does nothing if the modal has no relationship options.
+func handleRelationshipStep(page *rod.Page) error {
+	radios, err := page.Timeout(2 * time.Second).Elements(utils.Selectors.RelationshipRadio)
+	if err != nil || len(radios) == 0 {
+		return nil
+	}
 
-					// Type the note with human-like typing
-					logger.Info(fmt.Sprintf("Typing note (%d characters)...", len(request.Note)))
-					stealth.TypeLikeHuman(noteTextarea, request.Note)
-					stealth.RandomDelay(1000, 2000)
-				} else {
-					logger.Warning("Note textarea not found")
-				}
+	logger.Info("Connect modal asks how you know this person")
+
+	// Radios are usually hidden behind their labels, so click the label when there is one
+	labels := make([]string, len(radios))
+	targets := make([]*rod.Element, len(radios))
+	for i, radio := range radios {
+		targets[i] = radio
+		if id, err := radio.Attribute("id"); err == nil && id != nil {
+			if label, err := page.Element(fmt.Sprintf("label[for='%s']", *id)); err == nil {
+				labels[i], _ = label.Text()
+				targets[i] = label
+				continue
 			}
-		} else {
-			logger.Warning("Add a note button not found, skipping note.")
+		}
+		if ariaLabel, err := radio.Attribute("aria-label"); err == nil && ariaLabel != nil {
+			labels[i] = *ariaLabel
 		}
 	}
 
-	// Find and click the "Send" button
-	logger.Info("Looking for Send button...")
-	var sendButton *rod.Element
-
-	// Selectors for Send button
-	sendSelectors := []string{
-		utils.SendConnectionButtonSelector,
-		"button[aria-label='Send now']",
-		"button[aria-label='Send invitation']",
-		"button.artdeco-button--primary:has-text('Send')",
-		"button:has-text('Send without a note')", // Fallback if note failed
+	selectOption := func(index int) error {
+		return stealth.SafeClick(page, targets[index])
 	}
 
-	for _, sel := range sendSelectors {
-		btn, err := page.Timeout(2 * time.Second).Element(sel)
-		if err == nil && btn != nil {
-			if visible, _ := btn.Visible(); visible {
-				sendButton = btn
-				break
-			}
+	proceed := func() error {
+		stealth.RandomDelay(500, 1000)
+		continueButton, err := page.Timeout(3 * time.Second).Element(utils.Selectors.RelationshipContinue)
+		if err != nil {
+			return fmt.Errorf("continue button not found: %w", err)
+		}
+		if err := stealth.SafeClick(page, continueButton); err != nil {
+			return err
 		}
+		stealth.RandomDelay(1000, 2000)
+		return nil
 	}
 
-	if sendButton == nil {
-		// Try finding by text regex as last resort
-		sendButton, _ = page.Timeout(2*time.Second).ElementR("button", `\bSend\b`)
+	chosen, err := completeRelationshipStep(labels, connectionRelationship(), selectOption, proceed)
+	if err != nil {
+		return err
 	}
 
-	if sendButton == nil {
-		return fmt.Errorf("send button not found")
+	logger.Info("Selected relationship: " + chosen)
+	return nil
+}
+
+// completeRelationshipStep picks the relationship option, selects it and then
+// continues. Returns the label of the selected option.
+func completeRelationshipStep(labels []string, preferred string, selectOption func(int) error, proceed func() error) (string, error) {
+	index := chooseRelationshipOption(labels, preferred)
+	if index == -1 {
+		return "", fmt.Errorf("no matching relationship option for %q in %v", preferred, labels)
 	}
 
-	stealth.RandomDelay(500, 1000)
+	if err := selectOption(index); err != nil {
+		return "", fmt.Errorf("failed to select relationship option: %w", err)
+	}
 
-	logger.Info("Clicking Send button...")
-	err = sendButton.Click(proto.InputMouseButtonLeft, 1)
-	if err != nil {
-		return fmt.Errorf("failed to click send button: %w", err)
+	if err := proceed(); err != nil {
+		return "", fmt.Errorf("failed to continue after selecting relationship: %w", err)
 	}
 
-	stealth.RandomDelay(2000, 3000)
-	page.MustWaitLoad()
+	return strings.TrimSpace(labels[index]), nil
+}
 
-	// Save to database
-	if db != nil {
-		connectionReq := storage.ConnectionRequest{
-			ProfileID: request.ProfileID,
-			SentAt:    time.Now(),
-			NoteUsed:  request.Note,
-			Status:    "pending",
+// chooseRelationshipOption returns the index of the label matching preferred
+// (case-insensitive, substring), falling back to "Other". Returns -1 if neither exists.
+func chooseRelationshipOption(labels []string, preferred string) int {
+	normalize := func(text string) string {
+		text = strings.ReplaceAll(text, "’", "'") // LinkedIn uses curly apostrophes
+		return strings.ToLower(strings.TrimSpace(text))
+	}
+
+	for _, want := range []string{preferred, utils.DefaultRelationshipOption} {
+		want = normalize(want)
+		if want == "" {
+			continue
 		}
+		for i, label := range labels {
+			if strings.Contains(normalize(label), want) {
+				return i
+			}
+		}
+	}
 
-		err = db.SaveConnectionRequest(connectionReq)
-		if err != nil {
-			logger.Warning("Failed to save connection request to database: " + err.Error())
+	return -1
+}
+
+// GetMaxConnectAttempts reads MAX_CONNECT_ATTEMPTS, the failed connection
+// attempts after which a profile is no longer selected (default 3, 0 = no limit)
+func GetMaxConnectAttempts() int {
+	if v := os.Getenv("MAX_CONNECT_ATTEMPTS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val >= 0 {
+			return val
 		}
 	}
+	return 3
+}
 
-	logger.Info("Connection request sent successfully to " + request.Name)
-	return nil
+// recordConnectAttempt counts the attempt on the profile's retry ledger. Only
+// a sent request or a failure specific to the profile counts; a stop (weekly
+// limit, checkpoint) says nothing about the profile.
+func recordConnectAttempt(db *storage.Database, request ConnectionRequest, outcome connectOutcome, maxAttempts int) {
+	if db == nil || (outcome != outcomeSent && outcome != outcomeFailed) {
+		return
+	}
+
+	permanent, err := db.RecordConnectAttempt(request.ProfileID, outcome == outcomeFailed, maxAttempts)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Failed to record connection attempt for %s: %s", request.ProfileID, err.Error()))
+	} else if permanent && outcome == outcomeFailed {
+		logger.Warning(fmt.Sprintf("%s failed %d connection attempts, not selecting it again", request.Name, maxAttempts))
+	}
 }
 
 // SendConnectionRequests sends multiple connection requests with rate limiting
 func SendConnectionRequests(page *rod.Page, db *storage.Database, rateLimiter *RateLimiter, requests []ConnectionRequest) *ConnectionStats {
+	pacer := NewProfilePacer(page, db, GetProfileBatchSize())
+	reclaimer := browser.NewResourceReclaimer(page, browser.GetReclaimConfig())
+	return sendConnectionRequests(db, rateLimiter, requests, func(request ConnectionRequest) error {
+		reclaimer.BeforeAction()
+		pacer.BeforeProfile()
+		return SendConnectionRequest(page, db, request)
+	})
+}
+
+// sendConnectionRequests runs the shared request loop: pause control, daily
+// and company limits, batching, attempt tracking and cooldowns. send does the
+// invite itself, so callers that connect from somewhere other than the
+// profile page (e.g. My Network cards) get the same limits.
+func sendConnectionRequests(db *storage.Database, rateLimiter *RateLimiter, requests []ConnectionRequest, send func(ConnectionRequest) error) *ConnectionStats {
 	stats := &ConnectionStats{
 		StartTime: time.Now(),
 	}
 
 	logger.Info(fmt.Sprintf("Sending %d connection requests...", len(requests)))
+	batcher := NewRequestBatcher(GetBatchConfig())
+	maxAttempts := GetMaxConnectAttempts()
 
-	for _, request := range requests {
-		stats.TotalAttempted++
+	for i, request := range requests {
+		// Honor the PAUSE / STOP control files between requests
+		if err := WaitWhilePaused(context.Background()); err != nil {
+			stats.Errors = append(stats.Errors, err.Error())
+			break
+		}
 
 		// Check rate limit
 		err := rateLimiter.CheckDailyLimit(TaskConnection)
@@ -463,30 +1155,55 @@ func SendConnectionRequests(page *rod.Page, db *storage.Database, rateLimiter *R
 			break
 		}
 
+		// Skip profiles whose company already got its share of invites today
+		if err := rateLimiter.CheckCompanyLimit(request.Company); err != nil {
+			logger.Info(fmt.Sprintf("Skipping %s: %s", request.Name, err.Error()))
+			stats.CompanyCapped++
+			continue
+		}
+
 		// Send the request
-		err = SendConnectionRequest(page, db, request)
-		if err != nil {
-			if strings.Contains(err.Error(), "already connected") {
-				stats.AlreadyConnected++
-			} else if strings.Contains(err.Error(), "connection pending") {
-				stats.Pending++
-				logger.Info(fmt.Sprintf("Connection request already pending for %s", request.Name))
-			} else {
-				stats.Failed++
-				stats.Errors = append(stats.Errors, fmt.Sprintf("%s: %s", request.Name, err.Error()))
-				logger.Warning(fmt.Sprintf("Failed to send connection to %s: %s", request.Name, err.Error()))
-			}
-		} else {
+		stats.TotalAttempted++
+		batcher.BeforeRequest()
+		err = send(request)
+		outcome := classifyConnectError(err)
+		recordConnectAttempt(db, request, outcome, maxAttempts)
+		switch outcome {
+		case outcomeSent:
 			stats.Successful++
+			stats.SentTemplateIDs = append(stats.SentTemplateIDs, request.TemplateID)
 
 			// Record action for rate limiting
 			if err := rateLimiter.RecordAction(TaskConnection); err != nil {
 				logger.Warning("Failed to record connection action: " + err.Error())
 			}
+
+			// Link the follow-up message to send once they accept
+			if msgTemplate := sequenceMessageTemplate(); msgTemplate != "" && db != nil {
+				if err := EnqueueSequence(db, request.ProfileID, request.TemplateID, msgTemplate); err != nil {
+					logger.Warning(fmt.Sprintf("Failed to start sequence for %s: %s", request.Name, err.Error()))
+				}
+			}
+		case outcomeAlreadyConnected:
+			stats.AlreadyConnected++
+		case outcomePending:
+			stats.Pending++
+			logger.Info(fmt.Sprintf("Connection request already pending for %s", request.Name))
+		case outcomeStop:
+			stats.Failed++
+			stats.Errors = append(stats.Errors, fmt.Sprintf("%s: %s", request.Name, err.Error()))
+			logger.Error("Stopping connection requests: " + err.Error())
+		default:
+			stats.Failed++
+			stats.Errors = append(stats.Errors, fmt.Sprintf("%s: %s", request.Name, err.Error()))
+			logger.Warning(fmt.Sprintf("Failed to send connection to %s: %s", request.Name, err.Error()))
+		}
+		if outcome == outcomeStop {
+			break
 		}
 
 		// Apply cooldown between connections
-		if stats.TotalAttempted < len(requests) {
+		if i < len(requests)-1 {
 			rateLimiter.ApplyCooldown()
 		}
 	}
@@ -511,6 +1228,12 @@ func SendMessages(page *rod.Page, db *storage.Database, rateLimiter *RateLimiter
 	logger.Info(fmt.Sprintf("Sending %d messages...", len(messages)))
 
 	for _, message := range messages {
+		// Honor the PAUSE / STOP control files between messages
+		if err := WaitWhilePaused(context.Background()); err != nil {
+			stats.Errors = append(stats.Errors, err.Error())
+			break
+		}
+
 		stats.TotalAttempted++
 
 		// Check rate limit
@@ -523,6 +1246,11 @@ func SendMessages(page *rod.Page, db *storage.Database, rateLimiter *RateLimiter
 
 		// Send the message
 		err = SendMessage(page, db, message)
+		if errors.Is(err, ErrNotWhitelisted) {
+			stats.Skipped++
+			logger.Info(fmt.Sprintf("Skipping %s: %s", message.Name, err.Error()))
+			continue
+		}
 		if err != nil {
 			stats.Failed++
 			stats.Errors = append(stats.Errors, fmt.Sprintf("%s: %s", message.Name, err.Error()))
@@ -545,25 +1273,21 @@ func SendMessages(page *rod.Page, db *storage.Database, rateLimiter *RateLimiter
 	stats.EndTime = time.Now()
 	duration := stats.EndTime.Sub(stats.StartTime)
 
-	logger.Info(fmt.Sprintf("Messaging completed: %d successful, %d failed in %s",
-		stats.Successful, stats.Failed, duration))
+	logger.Info(fmt.Sprintf("Messaging completed: %d successful, %d failed, %d skipped in %s",
+		stats.Successful, stats.Failed, stats.Skipped, duration))
 
 	return stats
 }
 
-// PrepareConnectionRequestFromProfile creates a ConnectionRequest from a database profile
+// PrepareConnectionRequestFromProfile creates a ConnectionRequest from a database profile.
+// The note comes from the configured NoteGenerator (by default the templateID template).
 func PrepareConnectionRequestFromProfile(profile storage.Profile, templateID string, senderVars TemplateVariables) (*ConnectionRequest, error) {
-	// Get template
-	template, err := GetTemplateByID(templateID)
-	if err != nil {
-		return nil, fmt.Errorf("template not found: %w", err)
-	}
-
-	if template.Type != TemplateConnectionRequest {
-		return nil, fmt.Errorf("template %s is not a connection request template", templateID)
-	}
+	generator := configuredNoteGenerator(templateID, senderVars)
+	return prepareConnectionRequestWithGenerator(profile, generator, noteContext(senderVars), GetPrepareOptions())
+}
 
-	// Prepare template variables
+// buildProfileTemplateVars combines a profile's details with the sender's variables
+func buildProfileTemplateVars(profile storage.Profile, senderVars TemplateVariables) TemplateVariables {
 	vars := TemplateVariables{
 		FullName:     profile.Name,
 		Title:        profile.Title,
@@ -584,27 +1308,7 @@ func PrepareConnectionRequestFromProfile(profile storage.Profile, templateID str
 		}
 	}
 
-	// Render the template
-	note, err := RenderTemplate(*template, vars)
-	if err != nil {
-		return nil, fmt.Errorf("failed to render template: %w", err)
-	}
-
-	// Validate length
-	if err := ValidateMessageLength(note, TemplateConnectionRequest); err != nil {
-		return nil, err
-	}
-
-	return &ConnectionRequest{
-		ProfileID:   profile.ID,
-		ProfileURL:  profile.ProfileURL,
-		Name:        profile.Name,
-		Title:       profile.Title,
-		Company:     profile.Company,
-		Note:        note,
-		TemplateID:  templateID,
-		RequestedAt: time.Now(),
-	}, nil
+	return RandomizeClauses(vars, GetClauseConfig(), utils.SessionRand())
 }
 
 // PrepareMessageFromProfile creates a MessageRequest from a database profile
@@ -620,25 +1324,7 @@ func PrepareMessageFromProfile(profile storage.Profile, templateID string, sende
 	}
 
 	// Prepare template variables
-	vars := TemplateVariables{
-		FullName:     profile.Name,
-		Title:        profile.Title,
-		Company:      profile.Company,
-		YourName:     senderVars.YourName,
-		YourTitle:    senderVars.YourTitle,
-		YourCompany:  senderVars.YourCompany,
-		CustomReason: senderVars.CustomReason,
-		Industry:     senderVars.Industry,
-	}
-
-	// Extract first name
-	parts := strings.Split(profile.Name, " ")
-	if len(parts) > 0 {
-		vars.FirstName = parts[0]
-		if len(parts) > 1 {
-			vars.LastName = strings.Join(parts[1:], " ")
-		}
-	}
+	vars := buildProfileTemplateVars(profile, senderVars)
 
 	// Render the template body
 	body, err := RenderTemplate(*template, vars)
@@ -671,7 +1357,7 @@ func CheckAndUpdateConnectionStatuses(page *rod.Page, db *storage.Database) (int
 	logger.Info("Checking connection request statuses...")
 
 	// Navigate to My Network page
-	err := page.Navigate("https://www.linkedin.com/mynetwork/")
+	err := navigate(page, db, "https://www.linkedin.com/mynetwork/", "")
 	if err != nil {
 		return 0, fmt.Errorf("failed to navigate to My Network: %w", err)
 	}
@@ -682,13 +1368,14 @@ func CheckAndUpdateConnectionStatuses(page *rod.Page, db *storage.Database) (int
 	currentURL := page.MustInfo().URL
 	if utils.IsLinkedInCheckpoint(currentURL) {
 		logger.Error("❌ LinkedIn checkpoint/verification detected at: " + currentURL)
-		return 0, fmt.Errorf("linkedin checkpoint detected, manual verification required")
+		return 0, fmt.Errorf("checking connection statuses: %w", ErrCheckpoint)
 	}
 
 	stealth.RandomDelay(2000, 3000)
 
 	// Scroll to load content
 	stealth.RandomScroll(page)
+	stealth.IdleNoise(page)
 	stealth.RandomDelay(1000, 2000)
 
 	// Get all pending connection requests from database
@@ -705,13 +1392,18 @@ func CheckAndUpdateConnectionStatuses(page *rod.Page, db *storage.Database) (int
 	logger.Info(fmt.Sprintf("Checking status for %d pending connections", len(pendingRequests)))
 
 	acceptedCount := 0
+	pacer := NewProfilePacer(page, db, GetProfileBatchSize())
+	reclaimer := browser.NewResourceReclaimer(page, browser.GetReclaimConfig())
 
 	// For each pending connection, check if they're now in "My Network"
 	for _, request := range pendingRequests {
 		profileID := request.ProfileID
+		reclaimer.BeforeAction()
+		pacer.BeforeProfile()
+
 		// Navigate to their profile
 		profileURL := fmt.Sprintf("https://www.linkedin.com/in/%s/", profileID)
-		err := page.Navigate(profileURL)
+		err := navigate(page, db, profileURL, profileID)
 		if err != nil {
 			logger.Warning(fmt.Sprintf("Failed to navigate to profile %s: %s", profileID, err.Error()))
 			continue
@@ -721,11 +1413,11 @@ func CheckAndUpdateConnectionStatuses(page *rod.Page, db *storage.Database) (int
 		stealth.RandomDelay(1500, 2500)
 
 		// Check for "Connected" indicator
-		connectedElement, _ := page.Element(utils.AlreadyConnectedSelector)
+		connectedElement, _ := page.Element(utils.Selectors.AlreadyConnected)
 		if connectedElement != nil {
 			// Connection was accepted!
 			logger.Info(fmt.Sprintf("Connection accepted: %s", profileID))
-			err = db.UpdateConnectionStatus(profileID, "accepted")
+			err = markConnectionAccepted(db, profileID)
 			if err != nil {
 				logger.Warning(fmt.Sprintf("Failed to update status for %s: %s", profileID, err.Error()))
 			} else {
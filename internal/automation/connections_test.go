@@ -1,8 +1,16 @@
 package automation
 
 import (
+	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
 )
 
 func TestRenderTemplate(t *testing.T) {
@@ -437,3 +445,716 @@ func TestGetMessageTemplates(t *testing.T) {
 		}
 	}
 }
+
+func TestCaptureEvidence(t *testing.T) {
+	calls := 0
+	capture := func() (string, error) {
+		calls++
+		return "data/screenshots/connection_jane-doe.png", nil
+	}
+
+	if path := captureEvidence(false, capture); path != "" {
+		t.Errorf("Expected empty path when audit mode is off, got %q", path)
+	}
+	if calls != 0 {
+		t.Errorf("Capture should not run when audit mode is off, ran %d times", calls)
+	}
+
+	if path := captureEvidence(true, capture); path != "data/screenshots/connection_jane-doe.png" {
+		t.Errorf("Expected screenshot path when audit mode is on, got %q", path)
+	}
+	if calls != 1 {
+		t.Errorf("Expected capture to run once, ran %d times", calls)
+	}
+
+	failing := func() (string, error) { return "", errors.New("page crashed") }
+	if path := captureEvidence(true, failing); path != "" {
+		t.Errorf("Expected empty path when capture fails, got %q", path)
+	}
+}
+
+func TestAuditScreenshotsEnabled(t *testing.T) {
+	t.Setenv("AUDIT_SCREENSHOTS", "true")
+	if !auditScreenshotsEnabled() {
+		t.Error("Expected audit screenshots to be enabled")
+	}
+
+	t.Setenv("AUDIT_SCREENSHOTS", "")
+	if auditScreenshotsEnabled() {
+		t.Error("Expected audit screenshots to be disabled by default")
+	}
+}
+
+func TestGetConnectionNoteMaxLength(t *testing.T) {
+	tests := []struct {
+		env      string
+		expected int
+	}{
+		{"", ConnectionNoteMaxLength},
+		{"200", 200},
+		{"abc", ConnectionNoteMaxLength},
+		{"-5", ConnectionNoteMaxLength},
+	}
+
+	for _, test := range tests {
+		t.Setenv("CONNECTION_NOTE_MAX", test.env)
+		if result := GetConnectionNoteMaxLength(); result != test.expected {
+			t.Errorf("CONNECTION_NOTE_MAX=%q: expected %d, got %d", test.env, test.expected, result)
+		}
+	}
+}
+
+func TestCustomConnectionNoteLimit(t *testing.T) {
+	t.Setenv("CONNECTION_NOTE_MAX", "200")
+
+	note250 := strings.Repeat("a", 250)
+	if err := ValidateMessageLength(note250, TemplateConnectionRequest); err == nil {
+		t.Error("Expected 250 character note to be rejected with a 200 limit")
+	}
+	if err := ValidateMessageLength(strings.Repeat("a", 200), TemplateConnectionRequest); err != nil {
+		t.Errorf("Expected 200 character note to pass with a 200 limit: %v", err)
+	}
+	// Direct messages keep their own limit
+	if err := ValidateMessageLength(note250, TemplateFollowUp); err != nil {
+		t.Errorf("Message limit should not be affected by CONNECTION_NOTE_MAX: %v", err)
+	}
+
+	// Templates are rendered against the effective limit, even if they carry the default
+	tmpl := MessageTemplate{
+		ID:        "test_long",
+		Type:      TemplateConnectionRequest,
+		Name:      "Long",
+		Body:      "Hi {{.FirstName}}, " + note250,
+		MaxLength: ConnectionNoteMaxLength,
+	}
+	if _, err := RenderTemplate(tmpl, TemplateVariables{FirstName: "Jane"}); err == nil {
+		t.Error("Expected rendering to fail when the note exceeds the custom limit")
+	}
+
+	tmpl.Body = "Hi {{.FirstName}}, great to meet you."
+	if _, err := RenderTemplate(tmpl, TemplateVariables{FirstName: "Jane"}); err != nil {
+		t.Errorf("Expected short note to render: %v", err)
+	}
+
+	for _, template := range GetConnectionRequestTemplates() {
+		if template.MaxLength != 200 {
+			t.Errorf("Template %s: expected max length 200, got %d", template.ID, template.MaxLength)
+		}
+	}
+}
+
+func TestConnectionNoteMinLength(t *testing.T) {
+	brief := MessageTemplate{
+		ID:        "test_brief",
+		Type:      TemplateConnectionRequest,
+		Name:      "Brief",
+		Body:      "Hi {{.FirstName}}, let's connect!",
+		MaxLength: ConnectionNoteMaxLength,
+	}
+	vars := TemplateVariables{FirstName: "Jane"}
+
+	t.Setenv("CONNECTION_NOTE_MIN", "60")
+
+	_, err := RenderTemplate(brief, vars)
+	if !errors.Is(err, ErrNoteTooShort) {
+		t.Errorf("Expected ErrNoteTooShort for a short note, got %v", err)
+	}
+	if err := ValidateMessageLength("Hi Jane, let's connect!", TemplateConnectionRequest); !errors.Is(err, ErrNoteTooShort) {
+		t.Errorf("Expected ErrNoteTooShort from ValidateMessageLength, got %v", err)
+	}
+
+	// Notes at or above the minimum pass
+	rich := brief
+	rich.Body = "Hi {{.FirstName}}, I enjoyed your talk on distributed systems and would love to connect."
+	if _, err := RenderTemplate(rich, vars); err != nil {
+		t.Errorf("Expected a rich note to pass the minimum: %v", err)
+	}
+
+	// Direct messages have no minimum
+	if err := ValidateMessageLength("Thanks!", TemplateFollowUp); err != nil {
+		t.Errorf("Messages should not be affected by CONNECTION_NOTE_MIN: %v", err)
+	}
+
+	// A template's own minimum overrides the environment
+	brief.MinLength = 10
+	if _, err := RenderTemplate(brief, vars); err != nil {
+		t.Errorf("Expected template minimum of 10 to accept the note: %v", err)
+	}
+}
+
+func TestConnectionNoteMinLengthDisabled(t *testing.T) {
+	for _, env := range []string{"", "0", "abc", "-5"} {
+		t.Setenv("CONNECTION_NOTE_MIN", env)
+
+		if min := GetConnectionNoteMinLength(); min != 0 {
+			t.Errorf("CONNECTION_NOTE_MIN=%q: expected minimum disabled, got %d", env, min)
+		}
+
+		// Current behavior is preserved: the shortest built-in template still renders
+		tmpl, err := GetTemplateByID("conn_brief")
+		if err != nil {
+			t.Fatalf("Failed to get template: %v", err)
+		}
+		if _, err := RenderTemplate(*tmpl, TemplateVariables{FirstName: "Jo", Company: "X"}); err != nil {
+			t.Errorf("CONNECTION_NOTE_MIN=%q: expected brief note to render, got %v", env, err)
+		}
+		if err := ValidateMessageLength("Hi", TemplateConnectionRequest); err != nil {
+			t.Errorf("CONNECTION_NOTE_MIN=%q: expected short note to validate, got %v", env, err)
+		}
+	}
+}
+
+func TestChooseRelationshipOption(t *testing.T) {
+	labels := []string{
+		"Colleague",
+		"Classmate",
+		"We’ve done business together",
+		"Friend",
+		"Other",
+		"I don’t know Jane",
+	}
+
+	tests := []struct {
+		preferred string
+		labels    []string
+		expected  int
+	}{
+		{"Other", labels, 4},
+		{"We've done business together", labels, 2}, // straight apostrophe matches curly
+		{"colleague", labels, 0},
+		{"", labels, 4},       // default
+		{"Mentor", labels, 4}, // unknown preference falls back to Other
+		{"Mentor", []string{"Colleague", "Friend"}, -1},
+		{"Other", nil, -1},
+	}
+
+	for _, test := range tests {
+		if result := chooseRelationshipOption(test.labels, test.preferred); result != test.expected {
+			t.Errorf("chooseRelationshipOption(%q) = %d, expected %d", test.preferred, result, test.expected)
+		}
+	}
+}
+
+func TestCompleteRelationshipStep(t *testing.T) {
+	labels := []string{"Colleague", "We’ve done business together", "Other"}
+
+	var steps []string
+	selectOption := func(index int) error {
+		steps = append(steps, "select:"+labels[index])
+		return nil
+	}
+	proceed := func() error {
+		steps = append(steps, "continue")
+		return nil
+	}
+
+	chosen, err := completeRelationshipStep(labels, "We've done business together", selectOption, proceed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if chosen != "We’ve done business together" {
+		t.Errorf("Unexpected selection %q", chosen)
+	}
+
+	// The option must be selected before continuing to Send
+	expected := []string{"select:We’ve done business together", "continue"}
+	if strings.Join(steps, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected steps %v, got %v", expected, steps)
+	}
+}
+
+func TestCompleteRelationshipStepFailures(t *testing.T) {
+	proceeded := false
+	proceed := func() error { proceeded = true; return nil }
+
+	// No usable option - nothing is clicked
+	_, err := completeRelationshipStep([]string{"Friend"}, "Mentor", func(int) error {
+		t.Error("selectOption should not be called without a match")
+		return nil
+	}, proceed)
+	if err == nil {
+		t.Error("Expected error when no option matches")
+	}
+	if proceeded {
+		t.Error("Should not continue without a selection")
+	}
+
+	// Selection fails - do not continue
+	_, err = completeRelationshipStep([]string{"Other"}, "Other", func(int) error {
+		return errors.New("element covered")
+	}, proceed)
+	if err == nil || proceeded {
+		t.Error("Expected error and no continue when selection fails")
+	}
+}
+
+func TestConnectionRelationship(t *testing.T) {
+	t.Setenv("CONNECTION_RELATIONSHIP", "")
+	if result := connectionRelationship(); result != "Other" {
+		t.Errorf("Expected default Other, got %q", result)
+	}
+
+	t.Setenv("CONNECTION_RELATIONSHIP", "Colleague")
+	if result := connectionRelationship(); result != "Colleague" {
+		t.Errorf("Expected Colleague, got %q", result)
+	}
+}
+
+func TestFindConnectButtonWithRetry(t *testing.T) {
+	found := &rod.Element{}
+
+	tests := []struct {
+		name            string
+		retry           bool
+		results         []error // Result of each find call; nil = button found
+		expectedFinds   int
+		expectedReloads int
+		expectFound     bool
+		expectedErr     error
+	}{
+		{"found first time", true, []error{nil}, 1, 0, true, nil},
+		{"retry disabled", false, []error{ErrConnectButtonNotFound}, 1, 0, false, ErrConnectButtonNotFound},
+		{"found after reload", true, []error{ErrConnectButtonNotFound, nil}, 2, 1, true, nil},
+		{"second miss is final", true, []error{ErrConnectButtonNotFound, ErrConnectButtonNotFound}, 2, 1, false, ErrConnectButtonNotFound},
+	}
+
+	for _, test := range tests {
+		finds, reloads := 0, 0
+		find := func() (*rod.Element, error) {
+			err := test.results[finds]
+			finds++
+			if err != nil {
+				return nil, err
+			}
+			return found, nil
+		}
+		reload := func() error {
+			reloads++
+			return nil
+		}
+
+		btn, err := findConnectButtonWithRetry(find, reload, test.retry)
+
+		if finds != test.expectedFinds || reloads != test.expectedReloads {
+			t.Errorf("%s: expected %d finds and %d reloads, got %d and %d",
+				test.name, test.expectedFinds, test.expectedReloads, finds, reloads)
+		}
+		if test.expectFound && btn != found {
+			t.Errorf("%s: expected the button to be returned", test.name)
+		}
+		if !errors.Is(err, test.expectedErr) || (test.expectedErr == nil && err != nil) {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectedErr, err)
+		}
+	}
+}
+
+func TestFindConnectButtonWithRetryOnlyRetriesMisses(t *testing.T) {
+	reloads := 0
+	alreadyConnected := errors.New("already connected")
+
+	_, err := findConnectButtonWithRetry(
+		func() (*rod.Element, error) { return nil, alreadyConnected },
+		func() error { reloads++; return nil },
+		true,
+	)
+
+	if err != alreadyConnected || reloads != 0 {
+		t.Errorf("Expected other errors to be returned without a reload, got %v after %d reloads", err, reloads)
+	}
+
+	// A failed reload is reported instead of retrying
+	_, err = findConnectButtonWithRetry(
+		func() (*rod.Element, error) { return nil, ErrConnectButtonNotFound },
+		func() error { return errors.New("navigation failed") },
+		true,
+	)
+	if err == nil || errors.Is(err, ErrConnectButtonNotFound) {
+		t.Errorf("Expected the reload failure to be reported, got %v", err)
+	}
+}
+
+func TestIsDropdownConnectLabel(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Connect", true},
+		{"  Connect\n", true},
+		{"Invite to connect", true},
+		{"Invite Jane Doe to connect", true},
+		{"Disconnect", false},
+		{"Connections", false},
+		{"Follow", false},
+		{"Remove connection", false},
+		{"Connect with Jane on Slack", false},
+	}
+
+	for _, tt := range tests {
+		if got := isDropdownConnectLabel(tt.text); got != tt.want {
+			t.Errorf("isDropdownConnectLabel(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIsCreatorMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		hasBadge     bool
+		primaryLabel string
+		want         bool
+	}{
+		{"creator badge", true, "Message", true},
+		{"follow primary button", false, "  Follow", true},
+		{"follow with name", false, "Follow Jane", true},
+		{"regular profile", false, "Connect", false},
+		{"no primary button", false, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCreatorMode(tt.hasBadge, tt.primaryLabel); got != tt.want {
+				t.Errorf("isCreatorMode(%v, %q) = %v, want %v", tt.hasBadge, tt.primaryLabel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanConnectSearch(t *testing.T) {
+	creator := planConnectSearch(true)
+	if creator.Direct || creator.StickyBar || !creator.MoreDropdown {
+		t.Errorf("Creator-mode profiles should go straight to the More dropdown, got %+v", creator)
+	}
+
+	regular := planConnectSearch(false)
+	if !regular.Direct || !regular.StickyBar || !regular.MoreDropdown {
+		t.Errorf("Regular profiles should search directly, then the sticky bar, then the More dropdown, got %+v", regular)
+	}
+}
+
+func TestSearchWithStickyRetry(t *testing.T) {
+	found := &rod.Element{}
+
+	tests := []struct {
+		name          string
+		direct        *rod.Element
+		sticky        *rod.Element
+		useSticky     bool
+		expectScrolls int
+		expectFound   bool
+	}{
+		{"direct hit skips the scroll", found, nil, true, 0, true},
+		{"direct miss scrolls to the sticky bar", nil, found, true, 1, true},
+		{"miss in both falls through to More", nil, nil, true, 1, false},
+		{"sticky bar not planned", nil, found, false, 0, false},
+	}
+
+	for _, test := range tests {
+		scrolls := 0
+		btn := searchWithStickyRetry(
+			func() *rod.Element { return test.direct },
+			func() *rod.Element { scrolls++; return test.sticky },
+			test.useSticky,
+		)
+
+		if scrolls != test.expectScrolls {
+			t.Errorf("%s: expected %d sticky bar searches, got %d", test.name, test.expectScrolls, scrolls)
+		}
+		if (btn == found) != test.expectFound {
+			t.Errorf("%s: expected found=%v, got %v", test.name, test.expectFound, btn)
+		}
+	}
+}
+
+func TestChooseInviteFlow(t *testing.T) {
+	const profileURL = "https://www.linkedin.com/in/jane-doe/"
+	const invitePageURL = "https://www.linkedin.com/preload/custom-invite/?vanityName=jane-doe"
+
+	tests := []struct {
+		name          string
+		currentURL    string
+		hasModal      bool
+		hasInviteForm bool
+		want          inviteFlow
+	}{
+		{"modal on profile", profileURL, true, false, inviteFlowModal},
+		{"modal with note field", profileURL, true, true, inviteFlowModal},
+		{"navigated to invite page", invitePageURL, false, false, inviteFlowPage},
+		{"invite page with form", invitePageURL, false, true, inviteFlowPage},
+		{"form without modal", profileURL, false, true, inviteFlowPage},
+		{"nothing appeared", profileURL, false, false, inviteFlowNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chooseInviteFlow(tt.currentURL, tt.hasModal, tt.hasInviteForm); got != tt.want {
+				t.Errorf("chooseInviteFlow(%q, %v, %v) = %v, want %v", tt.currentURL, tt.hasModal, tt.hasInviteForm, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChooseNoteInput(t *testing.T) {
+	textarea := &noteInputCandidate{Tag: "textarea", Visible: true}
+	hiddenTextarea := &noteInputCandidate{Tag: "textarea"}
+	editable := &noteInputCandidate{Tag: "div", ContentEditable: true, Visible: true}
+	wrapper := &noteInputCandidate{Tag: "div", Visible: true}
+
+	tests := []struct {
+		name       string
+		candidates []*noteInputCandidate
+		wantIndex  int
+		wantKind   noteInputKind
+	}{
+		{"#custom-message textarea", []*noteInputCandidate{textarea, textarea, nil, nil}, 0, noteInputField},
+		{"id on a wrapper div", []*noteInputCandidate{wrapper, textarea, nil, nil}, 1, noteInputField},
+		{"only textarea[name=message]", []*noteInputCandidate{nil, nil, textarea, nil}, 2, noteInputField},
+		{"rich-text box", []*noteInputCandidate{nil, nil, nil, editable}, 3, noteInputEditable},
+		{"hidden textarea skipped", []*noteInputCandidate{hiddenTextarea, nil, nil, editable}, 3, noteInputEditable},
+		{"input element", []*noteInputCandidate{{Tag: "input", Visible: true}}, 0, noteInputField},
+		{"nothing usable", []*noteInputCandidate{wrapper, nil, hiddenTextarea, nil}, -1, noteInputField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, kind := chooseNoteInput(tt.candidates)
+			if index != tt.wantIndex || kind != tt.wantKind {
+				t.Errorf("chooseNoteInput() = (%d, %v), want (%d, %v)", index, kind, tt.wantIndex, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestNoteInputSelectorsPriority(t *testing.T) {
+	selectors := noteInputSelectors()
+	if len(selectors) < 2 || selectors[0] != utils.Selectors.ConnectionNoteTextarea {
+		t.Fatalf("Expected connection_note_textarea first, got %v", selectors)
+	}
+	if last := selectors[len(selectors)-1]; !strings.Contains(last, "div[role='textbox']") {
+		t.Errorf("Expected the rich-text box variant last, got %q", last)
+	}
+}
+
+// stubInvitePage renders the invite UI once appearAfter has passed on clock
+type stubInvitePage struct {
+	clock       *MockClock
+	start       time.Time
+	appearAfter time.Duration
+	show        inviteUIState // What the page shows once it has rendered
+}
+
+func (p *stubInvitePage) probe() inviteUIState {
+	if p.clock.Now().Sub(p.start) < p.appearAfter {
+		return inviteUIState{URL: "https://www.linkedin.com/in/jane-doe/"}
+	}
+	return p.show
+}
+
+func TestWaitForInviteUI(t *testing.T) {
+	const profileURL = "https://www.linkedin.com/in/jane-doe/"
+	const invitePageURL = "https://www.linkedin.com/preload/custom-invite/?vanityName=jane-doe"
+
+	tests := []struct {
+		name        string
+		appearAfter time.Duration
+		show        inviteUIState
+		wantWaited  time.Duration
+		wantFlow    inviteFlow
+	}{
+		{"modal already open", 0, inviteUIState{URL: profileURL, HasModal: true}, 0, inviteFlowModal},
+		{"modal after a slow animation", 3 * time.Second, inviteUIState{URL: profileURL, HasModal: true}, 3 * time.Second, inviteFlowModal},
+		{"navigated to the invite page", time.Second, inviteUIState{URL: invitePageURL}, time.Second, inviteFlowPage},
+		{"pending confirmed on the next poll", time.Second, inviteUIState{URL: profileURL, Pending: true}, 1250 * time.Millisecond, inviteFlowNone},
+		{"nothing within the timeout", time.Hour, inviteUIState{}, 7 * time.Second, inviteFlowNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+			clock := NewMockClock(start)
+			page := &stubInvitePage{clock: clock, start: start, appearAfter: tt.appearAfter, show: tt.show}
+
+			state := waitForInviteUI(page.probe, clock, 7*time.Second, inviteUIPollInterval, clock.Advance)
+
+			if waited := clock.Now().Sub(start); waited != tt.wantWaited {
+				t.Errorf("Waited %s, want %s", waited, tt.wantWaited)
+			}
+			if flow := chooseInviteFlow(state.URL, state.HasModal, state.HasInviteForm); flow != tt.wantFlow {
+				t.Errorf("Flow %v, want %v", flow, tt.wantFlow)
+			}
+			if state.Pending != tt.show.Pending {
+				t.Errorf("Pending %v, want %v", state.Pending, tt.show.Pending)
+			}
+		})
+	}
+}
+
+func TestWaitForInviteUIModalBeatsMomentaryPending(t *testing.T) {
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	clock := NewMockClock(start)
+
+	// Pending flickers on one poll, then the modal finishes animating in
+	states := []inviteUIState{{}, {Pending: true}, {HasModal: true}}
+	polls := 0
+	probe := func() inviteUIState {
+		state := states[polls]
+		if polls < len(states)-1 {
+			polls++
+		}
+		return state
+	}
+
+	state := waitForInviteUI(probe, clock, 7*time.Second, inviteUIPollInterval, clock.Advance)
+	if !state.HasModal {
+		t.Errorf("Expected to wait for the modal, got %+v", state)
+	}
+}
+
+func TestIsInvitePageURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://www.linkedin.com/preload/custom-invite/?vanityName=jane-doe", true},
+		{"https://www.linkedin.com/people/invite?id=123", true},
+		{"https://www.linkedin.com/in/jane-doe/", false},
+		{"https://www.linkedin.com/mynetwork/invite-connect/connections/", false},
+	}
+
+	for _, tt := range tests {
+		if got := isInvitePageURL(tt.url); got != tt.want {
+			t.Errorf("isInvitePageURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestGreetingFor(t *testing.T) {
+	tests := []struct {
+		hour     int
+		expected string
+	}{
+		{0, "Good evening"},
+		{4, "Good evening"},
+		{5, "Good morning"},
+		{9, "Good morning"},
+		{11, "Good morning"},
+		{12, "Good afternoon"},
+		{16, "Good afternoon"},
+		{17, "Good evening"},
+		{21, "Good evening"},
+	}
+
+	for _, test := range tests {
+		at := time.Date(2025, 3, 10, test.hour, 30, 0, 0, time.UTC)
+		if greeting := GreetingFor(at); greeting != test.expected {
+			t.Errorf("GreetingFor(%02d:30) = %q, expected %q", test.hour, greeting, test.expected)
+		}
+	}
+}
+
+func TestGreetingAtRespectsTimezone(t *testing.T) {
+	t.Setenv("TARGET_TIMEZONE", "")
+
+	// 14:00 UTC is 9:00 in New York (EST) and 23:00 in Tokyo
+	now := time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC)
+
+	if greeting := greetingAt(now, "America/New_York"); greeting != "Good morning" {
+		t.Errorf("New York: expected Good morning, got %q", greeting)
+	}
+	if greeting := greetingAt(now, "Asia/Tokyo"); greeting != "Good evening" {
+		t.Errorf("Tokyo: expected Good evening, got %q", greeting)
+	}
+	if greeting := greetingAt(now, ""); greeting != "Good afternoon" {
+		t.Errorf("No timezone: expected Good afternoon (time as given), got %q", greeting)
+	}
+	if greeting := greetingAt(now, "Not/AZone"); greeting != "Good afternoon" {
+		t.Errorf("Invalid timezone: expected Good afternoon (time as given), got %q", greeting)
+	}
+
+	// TARGET_TIMEZONE is used when the recipient's zone is unknown
+	t.Setenv("TARGET_TIMEZONE", "Asia/Tokyo")
+	if greeting := greetingAt(now, ""); greeting != "Good evening" {
+		t.Errorf("TARGET_TIMEZONE=Asia/Tokyo: expected Good evening, got %q", greeting)
+	}
+}
+
+func TestRenderTemplateGreeting(t *testing.T) {
+	tmpl, err := GetTemplateByID("conn_greeting")
+	if err != nil {
+		t.Fatalf("Failed to get greeting template: %v", err)
+	}
+
+	result, err := RenderTemplate(*tmpl, TemplateVariables{FirstName: "Jane", Company: "Acme", Greeting: "Good afternoon"})
+	if err != nil {
+		t.Fatalf("RenderTemplate failed: %v", err)
+	}
+	if !strings.HasPrefix(result, "Good afternoon Jane,") {
+		t.Errorf("Expected the provided greeting, got %q", result)
+	}
+
+	// Populated automatically when not provided
+	result, err = RenderTemplate(*tmpl, TemplateVariables{FirstName: "Jane", Company: "Acme"})
+	if err != nil {
+		t.Fatalf("RenderTemplate failed: %v", err)
+	}
+	if !strings.HasPrefix(result, "Good ") || strings.Contains(result, "{{") {
+		t.Errorf("Expected an automatic greeting, got %q", result)
+	}
+}
+
+func TestSendConnectionRequestsAppliesLimitsToAnySender(t *testing.T) {
+	t.Setenv("CONTROL_DIR", t.TempDir())
+	t.Setenv("SEQUENCE_MESSAGE_TEMPLATE", "")
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_connect.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	config := GetDefaultRateLimitConfig()
+	config.MaxConnectionsPerCompanyPerDay = 2
+	config.CooldownBetweenActions = 0
+	rl := NewRateLimiterWithConfig(db, config)
+
+	requests := []ConnectionRequest{
+		{ProfileID: "acme-1", Name: "A One", Company: "Acme"},
+		{ProfileID: "acme-2", Name: "A Two", Company: "Acme"},
+		{ProfileID: "acme-3", Name: "A Three", Company: "Acme"},
+		{ProfileID: "globex-1", Name: "G One", Company: "Globex"},
+		{ProfileID: "weekly", Name: "W One", Company: "Initech"},
+		{ProfileID: "never", Name: "N One", Company: "Initech"},
+	}
+
+	var sent []string
+	send := func(request ConnectionRequest) error {
+		if request.ProfileID == "weekly" {
+			return ErrWeeklyLimit
+		}
+		sent = append(sent, request.ProfileID)
+		profile := storage.Profile{ID: request.ProfileID, Name: request.Name, Company: request.Company,
+			ProfileURL: "https://www.linkedin.com/in/" + request.ProfileID + "/", VisitedAt: time.Now()}
+		if err := db.SaveProfile(profile); err != nil {
+			return err
+		}
+		return db.SaveConnectionRequest(storage.ConnectionRequest{ProfileID: request.ProfileID, SentAt: time.Now(), Status: "pending"})
+	}
+
+	stats := sendConnectionRequests(db, rl, requests, send)
+
+	if want := []string{"acme-1", "acme-2", "globex-1"}; strings.Join(sent, ",") != strings.Join(want, ",") {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+	if stats.Successful != 3 || stats.CompanyCapped != 1 || stats.Failed != 1 {
+		t.Errorf("stats = %d sent, %d capped, %d failed; want 3, 1, 1", stats.Successful, stats.CompanyCapped, stats.Failed)
+	}
+	if stats.TotalAttempted != 4 {
+		t.Errorf("TotalAttempted = %d, want 4 (the capped profile is not an attempt)", stats.TotalAttempted)
+	}
+
+	limit, err := db.GetTodayRateLimit()
+	if err != nil {
+		t.Fatalf("GetTodayRateLimit: %v", err)
+	}
+	if limit.ConnectionCount != 3 {
+		t.Errorf("ConnectionCount = %d, want every sent request recorded", limit.ConnectionCount)
+	}
+}
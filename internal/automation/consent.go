@@ -0,0 +1,101 @@
+package automation
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/stealth"
+	"linkedin-automation/pkg/utils"
+)
+
+// CookieConsentChoice is how DismissCookieBanner answers the cookie-consent banner
+type CookieConsentChoice string
+
+const (
+	CookieConsentAccept CookieConsentChoice = "accept" // Accept cookies (default)
+	CookieConsentReject CookieConsentChoice = "reject" // Reject non-essential cookies
+	CookieConsentIgnore CookieConsentChoice = "ignore" // Leave the banner alone
+)
+
+// GetCookieConsentChoice reads COOKIE_CONSENT (accept, reject or ignore; default accept)
+func GetCookieConsentChoice() CookieConsentChoice {
+	switch choice := CookieConsentChoice(strings.ToLower(strings.TrimSpace(os.Getenv("COOKIE_CONSENT")))); choice {
+	case CookieConsentAccept, CookieConsentReject, CookieConsentIgnore:
+		return choice
+	case "":
+		return CookieConsentAccept
+	default:
+		logger.Warning(fmt.Sprintf("Invalid COOKIE_CONSENT %q, accepting cookies", choice))
+		return CookieConsentAccept
+	}
+}
+
+// DismissCookieBanner answers the cookie-consent banner fresh profiles see, which
+// overlays the page and intercepts clicks. Call it once the first page has loaded.
+// Reports whether a banner button was clicked; no banner is not an error.
+func DismissCookieBanner(page *rod.Page) (bool, error) {
+	choice := GetCookieConsentChoice()
+	if choice == CookieConsentIgnore {
+		return false, nil
+	}
+
+	present, banner, err := page.Has(utils.Selectors.CookieBanner)
+	if err != nil {
+		return false, fmt.Errorf("failed to look for the cookie banner: %w", err)
+	}
+
+	var hasAccept, hasReject bool
+	if present {
+		hasAccept, _, _ = banner.Has(utils.Selectors.CookieAcceptButton)
+		hasReject, _, _ = banner.Has(utils.Selectors.CookieRejectButton)
+	}
+
+	selector, err := chooseCookieButton(present, hasAccept, hasReject, choice)
+	if err != nil || selector == "" {
+		return false, err
+	}
+
+	button, err := banner.Element(selector)
+	if err != nil {
+		return false, fmt.Errorf("cookie banner button disappeared: %w", err)
+	}
+
+	stealth.RandomDelay(800, 2000)
+	if err := stealth.SafeClick(page, button); err != nil {
+		return false, fmt.Errorf("failed to click the cookie banner: %w", err)
+	}
+	stealth.RandomDelay(500, 1000)
+
+	logger.Info("Answered the cookie-consent banner")
+	return true, nil
+}
+
+// chooseCookieButton returns the selector of the banner button to click, or ""
+// when there is no banner or it is to be left alone. If the chosen button is
+// missing the other one is used, since a banner left open blocks later clicks.
+func chooseCookieButton(bannerPresent, hasAccept, hasReject bool, choice CookieConsentChoice) (string, error) {
+	if !bannerPresent || choice == CookieConsentIgnore {
+		return "", nil
+	}
+
+	preferred, fallback := utils.Selectors.CookieAcceptButton, utils.Selectors.CookieRejectButton
+	hasPreferred, hasFallback := hasAccept, hasReject
+	if choice == CookieConsentReject {
+		preferred, fallback = fallback, preferred
+		hasPreferred, hasFallback = hasReject, hasAccept
+	}
+
+	switch {
+	case hasPreferred:
+		return preferred, nil
+	case hasFallback:
+		logger.Warning(fmt.Sprintf("Cookie banner has no %s button, using the other one", choice))
+		return fallback, nil
+	default:
+		return "", fmt.Errorf("cookie banner found but neither its accept nor reject button")
+	}
+}
@@ -0,0 +1,56 @@
+package automation
+
+import (
+	"testing"
+
+	"linkedin-automation/pkg/utils"
+)
+
+func TestChooseCookieButton(t *testing.T) {
+	accept, reject := utils.Selectors.CookieAcceptButton, utils.Selectors.CookieRejectButton
+
+	tests := []struct {
+		name                 string
+		present              bool
+		hasAccept, hasReject bool
+		choice               CookieConsentChoice
+		want                 string
+		wantErr              bool
+	}{
+		{"No banner", false, false, false, CookieConsentAccept, "", false},
+		{"Accept", true, true, true, CookieConsentAccept, accept, false},
+		{"Reject", true, true, true, CookieConsentReject, reject, false},
+		{"Ignore leaves banner open", true, true, true, CookieConsentIgnore, "", false},
+		{"Accept falls back to reject", true, false, true, CookieConsentAccept, reject, false},
+		{"Reject falls back to accept", true, true, false, CookieConsentReject, accept, false},
+		{"Banner without buttons", true, false, false, CookieConsentAccept, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chooseCookieButton(tt.present, tt.hasAccept, tt.hasReject, tt.choice)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetCookieConsentChoice(t *testing.T) {
+	tests := map[string]CookieConsentChoice{
+		"":        CookieConsentAccept,
+		"reject":  CookieConsentReject,
+		" Ignore": CookieConsentIgnore,
+		"maybe":   CookieConsentAccept,
+	}
+
+	for value, want := range tests {
+		t.Setenv("COOKIE_CONSENT", value)
+		if got := GetCookieConsentChoice(); got != want {
+			t.Errorf("COOKIE_CONSENT=%q: expected %s, got %s", value, want, got)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"linkedin-automation/internal/logger"
+)
+
+// Control files checked between actions, in CONTROL_DIR (default ./data).
+// PAUSE blocks the send loops until it is removed; STOP ends them.
+const (
+	pauseFileName = "PAUSE"
+	stopFileName  = "STOP"
+)
+
+// pausePollInterval is how often a paused loop re-checks the control files
+const pausePollInterval = 5 * time.Second
+
+// controlDir returns the directory holding the control files (CONTROL_DIR)
+func controlDir() string {
+	if dir := os.Getenv("CONTROL_DIR"); dir != "" {
+		return dir
+	}
+	return "./data"
+}
+
+// StopRequested reports whether the STOP control file exists
+func StopRequested() bool {
+	return fileExists(filepath.Join(controlDir(), stopFileName))
+}
+
+// WaitWhilePaused blocks while the PAUSE control file exists, re-checking
+// every few seconds, and returns once it is removed. Returns ErrStopRequested
+// if the STOP file exists (immediately or while paused), ErrCoolingOff during
+// an unusual activity cool-off, or ctx's error if it ends first.
+func WaitWhilePaused(ctx context.Context) error {
+	return waitWhilePaused(ctx, controlDir(), pausePollInterval)
+}
+
+// waitWhilePaused is WaitWhilePaused with an explicit directory and poll interval
+func waitWhilePaused(ctx context.Context, dir string, interval time.Duration) error {
+	pausePath := filepath.Join(dir, pauseFileName)
+	stopPath := filepath.Join(dir, stopFileName)
+	paused := false
+
+	for {
+		if fileExists(stopPath) {
+			logger.Warning("Stop requested - found " + stopPath)
+			return fmt.Errorf("%w (%s)", ErrStopRequested, stopPath)
+		}
+		if err := checkCoolOff(dir, time.Now()); err != nil {
+			return err
+		}
+
+		if !fileExists(pausePath) {
+			if paused {
+				logger.Info("Resumed - " + pausePath + " removed")
+			}
+			return nil
+		}
+
+		// Log once, not on every re-check
+		if !paused {
+			logger.Info("Paused - remove " + pausePath + " to resume")
+			paused = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
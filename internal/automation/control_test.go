@@ -0,0 +1,108 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWaitWhilePausedNotPaused(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := waitWhilePaused(context.Background(), dir, time.Millisecond); err != nil {
+		t.Errorf("Expected no wait without a PAUSE file, got %v", err)
+	}
+}
+
+func TestWaitWhilePausedBlocksUntilRemoved(t *testing.T) {
+	dir := t.TempDir()
+	pausePath := filepath.Join(dir, pauseFileName)
+	if err := os.WriteFile(pausePath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create pause file: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- waitWhilePaused(context.Background(), dir, 5*time.Millisecond)
+	}()
+
+	// Still blocked while the file exists
+	select {
+	case err := <-done:
+		t.Fatalf("Expected to block while paused, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := os.Remove(pausePath); err != nil {
+		t.Fatalf("Failed to remove pause file: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected nil after resume, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected to unblock after the pause file was removed")
+	}
+}
+
+func TestWaitWhilePausedStopWins(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, pauseFileName), nil, 0644)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- waitWhilePaused(context.Background(), dir, 5*time.Millisecond)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	os.WriteFile(filepath.Join(dir, stopFileName), nil, 0644)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrStopRequested) {
+			t.Errorf("Expected ErrStopRequested, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected STOP to end the pause")
+	}
+
+	// STOP alone returns immediately
+	os.Remove(filepath.Join(dir, pauseFileName))
+	if err := waitWhilePaused(context.Background(), dir, time.Millisecond); !errors.Is(err, ErrStopRequested) {
+		t.Errorf("Expected ErrStopRequested without a pause, got %v", err)
+	}
+}
+
+func TestWaitWhilePausedContextCancel(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, pauseFileName), nil, 0644)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	if err := waitWhilePaused(ctx, dir, 5*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline, got %v", err)
+	}
+}
+
+func TestStopRequested(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONTROL_DIR", dir)
+
+	if StopRequested() {
+		t.Error("Expected no stop without a STOP file")
+	}
+
+	os.WriteFile(filepath.Join(dir, stopFileName), nil, 0644)
+	if !StopRequested() {
+		t.Error("Expected stop with a STOP file")
+	}
+	if err := WaitWhilePaused(context.Background()); !errors.Is(err, ErrStopRequested) {
+		t.Errorf("Expected WaitWhilePaused to honor CONTROL_DIR, got %v", err)
+	}
+}
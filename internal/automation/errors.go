@@ -0,0 +1,96 @@
+package automation
+
+import (
+	"errors"
+
+	"linkedin-automation/internal/browser"
+)
+
+// Sentinel errors for automation failures. They are returned wrapped with
+// context (fmt.Errorf("...: %w", ErrX)), so classify them with errors.Is.
+var (
+	// ErrAlreadyConnected means the profile is already a 1st-degree connection
+	ErrAlreadyConnected = errors.New("already connected")
+
+	// ErrConnectionPending means an invitation to the profile is already pending
+	ErrConnectionPending = errors.New("connection pending")
+
+	// ErrConnectDisabled means the profile shows a greyed-out Connect button, as it does while an invitation is out
+	ErrConnectDisabled = errors.New("connect button disabled")
+
+	// ErrInvitationSent means the profile shows an "Invitation sent" pill instead of Connect
+	ErrInvitationSent = errors.New("invitation already sent")
+
+	// ErrCheckpoint means LinkedIn is asking for manual verification; all automation should stop
+	ErrCheckpoint = errors.New("linkedin checkpoint detected, manual verification required")
+
+	// ErrNotAuthenticated means the session died and LinkedIn redirected to a login wall
+	ErrNotAuthenticated = errors.New("not authenticated")
+
+	// ErrConnectButtonNotFound means no Connect button exists on the profile, even in the More menu
+	ErrConnectButtonNotFound = errors.New("connect button not found - profile may be out of network")
+
+	// ErrWeeklyLimit means LinkedIn's weekly invitation limit was hit; no more invitations can be sent this week
+	ErrWeeklyLimit = errors.New("weekly invitation limit reached")
+
+	// ErrCommercialUseLimit means LinkedIn's monthly profile search cap was hit; searches return nothing until it resets
+	ErrCommercialUseLimit = errors.New("commercial use limit reached")
+
+	// ErrLifetimeLimit means the account reached MAX_LIFETIME_CONNECTIONS; no more invitations are sent
+	ErrLifetimeLimit = errors.New("lifetime connection limit reached")
+
+	// ErrMessageNotConfirmed means Send was clicked but the message never showed up in the thread
+	ErrMessageNotConfirmed = errors.New("sent message did not appear in the thread")
+
+	// ErrStopRequested means the STOP control file exists; the current run should end
+	ErrStopRequested = errors.New("stop requested")
+
+	// ErrWrongAccount means the session belongs to a different account than EXPECTED_ACCOUNT_ID; nothing may be sent
+	ErrWrongAccount = errors.New("logged in to the wrong account")
+
+	// ErrChallengeRequired means sign-in stopped at a verification challenge (a code sent by email or text, or
+	// another checkpoint) that has to be completed by hand
+	ErrChallengeRequired = errors.New("login verification challenge required")
+
+	// ErrPasskeyRequired means sign-in stopped at a "Verify it's you" passkey prompt that could not be skipped
+	ErrPasskeyRequired = errors.New("login passkey verification required")
+
+	// ErrCoolingOff means LinkedIn showed its "unusual activity" banner recently; nothing is sent until the cool-off ends
+	ErrCoolingOff = errors.New("cooling off after an unusual activity warning")
+
+	// ErrRunTooSoon means the previous run started less than MIN_RUN_INTERVAL_MINUTES ago
+	ErrRunTooSoon = errors.New("last run was too recent")
+
+	// ErrNotWhitelisted means MESSAGE_WHITELIST is set and the profile isn't on it; nothing was sent
+	ErrNotWhitelisted = errors.New("not in whitelist")
+)
+
+// connectOutcome classifies the result of sending one connection request
+type connectOutcome int
+
+const (
+	outcomeSent connectOutcome = iota
+	outcomeAlreadyConnected
+	outcomePending
+	outcomeFailed
+	outcomeStop // Stop the whole batch: nothing else can succeed right now
+)
+
+// classifyConnectError maps an error from SendConnectionRequest to an outcome
+func classifyConnectError(err error) connectOutcome {
+	switch {
+	case err == nil:
+		return outcomeSent
+	case errors.Is(err, ErrAlreadyConnected):
+		return outcomeAlreadyConnected
+	case errors.Is(err, ErrConnectionPending), errors.Is(err, ErrConnectDisabled), errors.Is(err, ErrInvitationSent):
+		return outcomePending
+	case errors.Is(err, ErrWeeklyLimit), errors.Is(err, ErrCheckpoint), errors.Is(err, ErrNotAuthenticated):
+		return outcomeStop
+	case browser.IsDisconnectError(err):
+		// Every later request would fail the same way; the run reconnects and resumes
+		return outcomeStop
+	default:
+		return outcomeFailed
+	}
+}
@@ -0,0 +1,104 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/go-rod/rod"
+)
+
+func TestClassifyConnectError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected connectOutcome
+	}{
+		{"sent", nil, outcomeSent},
+		{"already connected", fmt.Errorf("Jane Doe: %w", ErrAlreadyConnected), outcomeAlreadyConnected},
+		{"pending", fmt.Errorf("Jane Doe: %w", ErrConnectionPending), outcomePending},
+		{"disabled connect is pending", fmt.Errorf("Jane Doe: %w", ErrConnectDisabled), outcomePending},
+		{"invitation sent pill is pending", fmt.Errorf("Jane Doe: %w", ErrInvitationSent), outcomePending},
+		{"weekly limit stops the batch", fmt.Errorf("sending to Jane Doe: %w", ErrWeeklyLimit), outcomeStop},
+		{"checkpoint stops the batch", fmt.Errorf("opening profile jane: %w", ErrCheckpoint), outcomeStop},
+		{"login wall stops the batch", fmt.Errorf("redirected: %w", ErrNotAuthenticated), outcomeStop},
+		{"lost browser connection stops the batch", fmt.Errorf("failed to navigate to profile: %w", io.EOF), outcomeStop},
+		{"button missing is a failure", ErrConnectButtonNotFound, outcomeFailed},
+		{"other errors are failures", errors.New("send button not found"), outcomeFailed},
+		// Plain text that merely mentions a sentinel's message is not classified as it
+		{"no string matching", errors.New("already connected"), outcomeFailed},
+	}
+
+	for _, test := range tests {
+		if outcome := classifyConnectError(test.err); outcome != test.expected {
+			t.Errorf("%s: expected outcome %d, got %d", test.name, test.expected, outcome)
+		}
+	}
+}
+
+func TestSentinelErrorsMatchThroughWrapping(t *testing.T) {
+	sentinels := []error{
+		ErrAlreadyConnected,
+		ErrConnectionPending,
+		ErrConnectDisabled,
+		ErrInvitationSent,
+		ErrCheckpoint,
+		ErrNotAuthenticated,
+		ErrConnectButtonNotFound,
+		ErrWeeklyLimit,
+	}
+
+	for _, sentinel := range sentinels {
+		wrapped := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", sentinel))
+		if !errors.Is(wrapped, sentinel) {
+			t.Errorf("Expected errors.Is to match %q through two levels of wrapping", sentinel)
+		}
+
+		for _, other := range sentinels {
+			if other != sentinel && errors.Is(wrapped, other) {
+				t.Errorf("%q unexpectedly matched %q", sentinel, other)
+			}
+		}
+	}
+}
+
+func TestCheckSearchPageAccessSentinels(t *testing.T) {
+	if err := checkSearchPageAccess("https://www.linkedin.com/checkpoint/challenge/AgF"); !errors.Is(err, ErrCheckpoint) {
+		t.Errorf("Expected ErrCheckpoint, got %v", err)
+	}
+	if err := checkSearchPageAccess("https://www.linkedin.com/authwall?trk=bf"); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("Expected ErrNotAuthenticated, got %v", err)
+	}
+}
+
+func TestFindConnectButtonWithRetryReturnsSentinel(t *testing.T) {
+	_, err := findConnectButtonWithRetry(
+		func() (*rod.Element, error) { return nil, fmt.Errorf("profile jane: %w", ErrConnectButtonNotFound) },
+		func() error { return nil },
+		true,
+	)
+	if !errors.Is(err, ErrConnectButtonNotFound) {
+		t.Errorf("Expected ErrConnectButtonNotFound after the retry, got %v", err)
+	}
+}
+
+func TestIsWeeklyLimitText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected bool
+	}{
+		{"You've reached the weekly invitation limit", true},
+		{"You’ve reached the weekly limit for invitations", true},
+		{"WEEKLY INVITATION LIMIT", true},
+		{"Add a note to your invitation?", false},
+		{"How do you know Jane?", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if result := isWeeklyLimitText(test.text); result != test.expected {
+			t.Errorf("isWeeklyLimitText(%q): expected %v, got %v", test.text, test.expected, result)
+		}
+	}
+}
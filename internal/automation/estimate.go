@@ -0,0 +1,84 @@
+package automation
+
+import (
+	"fmt"
+	"time"
+)
+
+// EstimateCampaignDuration estimates how long sending connection requests to
+// numTargets profiles will take, starting now (per schedule.Clock). It accounts for the cooldown
+// between actions, the daily connection limit (spilling into later days),
+// the active-hours window and weekends. Returns the total wall-clock duration
+// and a human-readable breakdown.
+func EstimateCampaignDuration(numTargets int, cfg RateLimitConfig, schedule ScheduleConfig) (time.Duration, string) {
+	return estimateCampaignDurationFrom(schedule.now(), numTargets, cfg, schedule)
+}
+
+// estimateCampaignDurationFrom simulates the campaign day by day from start
+func estimateCampaignDurationFrom(start time.Time, numTargets int, cfg RateLimitConfig, schedule ScheduleConfig) (time.Duration, string) {
+	if numTargets <= 0 {
+		return 0, "no targets - nothing to send"
+	}
+
+	cooldown := cfg.CooldownBetweenActions
+	if cooldown <= 0 {
+		cooldown = time.Second
+	}
+
+	// Actions that fit in one full active-hours window, capped by the daily limit
+	window := time.Duration(schedule.endOfWindow()-schedule.startOfWindow()) * time.Minute
+	perDay := int(window / cooldown)
+	if cfg.MaxConnectionsPerDay > 0 && cfg.MaxConnectionsPerDay < perDay {
+		perDay = cfg.MaxConnectionsPerDay
+	}
+	if perDay <= 0 {
+		return 0, fmt.Sprintf("cannot complete: no actions fit in the %s-%s window with a %s cooldown",
+			formatClock(schedule.StartHour, schedule.StartMinute), formatClock(schedule.EndHour, schedule.EndMinute), cooldown)
+	}
+
+	cursor := schedule.inTargetZone(start)
+	remaining := numTargets
+	activeDays := 0
+
+	for remaining > 0 {
+		if !isActiveHoursAt(cursor, schedule) {
+			cursor = CalculateNextActiveTime(cursor, schedule)
+		}
+
+		// Capacity left today: bounded by the window end and the daily limit
+		windowEnd := time.Date(cursor.Year(), cursor.Month(), cursor.Day(),
+			schedule.EndHour, schedule.EndMinute, 0, 0, cursor.Location())
+		capacity := int(windowEnd.Sub(cursor) / cooldown)
+		if capacity > perDay {
+			capacity = perDay
+		}
+
+		if capacity > 0 {
+			activeDays++
+		}
+
+		if remaining <= capacity {
+			cursor = cursor.Add(time.Duration(remaining) * cooldown)
+			remaining = 0
+			break
+		}
+
+		remaining -= capacity
+		cursor = windowEnd
+	}
+
+	total := cursor.Sub(start)
+	dayWord := "business days"
+	if !schedule.WeekdaysOnly {
+		dayWord = "active days"
+	}
+	if activeDays == 1 {
+		dayWord = dayWord[:len(dayWord)-1]
+	}
+
+	breakdown := fmt.Sprintf("%d targets at %d/day → ~%d %s (finishes in %s, %s cooldown, active %s-%s)",
+		numTargets, perDay, activeDays, dayWord, total.Round(time.Minute), cooldown,
+		formatClock(schedule.StartHour, schedule.StartMinute), formatClock(schedule.EndHour, schedule.EndMinute))
+
+	return total, breakdown
+}
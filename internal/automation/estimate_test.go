@@ -0,0 +1,124 @@
+package automation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func estimateTestConfig() (RateLimitConfig, ScheduleConfig) {
+	cfg := RateLimitConfig{
+		MaxConnectionsPerDay:   14,
+		CooldownBetweenActions: 30 * time.Second,
+	}
+	schedule := ScheduleConfig{StartHour: 9, EndHour: 17, WeekdaysOnly: true, TargetTimezone: "UTC"}
+	return cfg, schedule
+}
+
+func TestEstimateCampaignDurationOneDay(t *testing.T) {
+	cfg, schedule := estimateTestConfig()
+	monday := time.Date(2025, 12, 29, 9, 0, 0, 0, time.UTC)
+
+	duration, breakdown := estimateCampaignDurationFrom(monday, 10, cfg, schedule)
+
+	if duration != 5*time.Minute {
+		t.Errorf("Expected 5m for 10 targets at 30s cooldown, got %s", duration)
+	}
+	if !strings.Contains(breakdown, "14/day") || !strings.Contains(breakdown, "~1 business day ") {
+		t.Errorf("Unexpected breakdown: %s", breakdown)
+	}
+}
+
+func TestEstimateCampaignDurationMultiDaySpill(t *testing.T) {
+	cfg, schedule := estimateTestConfig()
+	monday := time.Date(2025, 12, 29, 9, 0, 0, 0, time.UTC)
+
+	// 50 targets at 14/day: Mon 14, Tue 14, Wed 14, Thu 8
+	duration, breakdown := estimateCampaignDurationFrom(monday, 50, cfg, schedule)
+
+	expected := 3*24*time.Hour + 4*time.Minute
+	if duration != expected {
+		t.Errorf("Expected %s, got %s", expected, duration)
+	}
+	if !strings.Contains(breakdown, "~4 business days") {
+		t.Errorf("Unexpected breakdown: %s", breakdown)
+	}
+}
+
+func TestEstimateCampaignDurationWeekendSkip(t *testing.T) {
+	cfg, schedule := estimateTestConfig()
+	friday := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	// 20 targets: Fri 14, then Monday 6 (weekend skipped)
+	duration, _ := estimateCampaignDurationFrom(friday, 20, cfg, schedule)
+	expected := 3*24*time.Hour + 3*time.Minute
+	if duration != expected {
+		t.Errorf("Expected %s, got %s", expected, duration)
+	}
+
+	// Without the weekday restriction the spill lands on Saturday
+	schedule.WeekdaysOnly = false
+	duration, breakdown := estimateCampaignDurationFrom(friday, 20, cfg, schedule)
+	expected = 24*time.Hour + 3*time.Minute
+	if duration != expected {
+		t.Errorf("Expected %s without weekday restriction, got %s", expected, duration)
+	}
+	if !strings.Contains(breakdown, "active days") {
+		t.Errorf("Unexpected breakdown: %s", breakdown)
+	}
+}
+
+func TestEstimateCampaignDurationStartsOutsideActiveHours(t *testing.T) {
+	cfg, schedule := estimateTestConfig()
+	saturday := time.Date(2026, 1, 3, 12, 0, 0, 0, time.UTC)
+
+	// Nothing happens until Monday 09:00
+	duration, _ := estimateCampaignDurationFrom(saturday, 2, cfg, schedule)
+	expected := 45*time.Hour + time.Minute
+	if duration != expected {
+		t.Errorf("Expected %s, got %s", expected, duration)
+	}
+}
+
+func TestEstimateCampaignDurationCooldownBound(t *testing.T) {
+	// A 1-hour window with a 10 minute cooldown fits only 6 actions per day
+	cfg := RateLimitConfig{MaxConnectionsPerDay: 100, CooldownBetweenActions: 10 * time.Minute}
+	schedule := ScheduleConfig{StartHour: 9, EndHour: 10, TargetTimezone: "UTC"}
+	start := time.Date(2025, 12, 29, 9, 0, 0, 0, time.UTC)
+
+	_, breakdown := estimateCampaignDurationFrom(start, 12, cfg, schedule)
+	if !strings.Contains(breakdown, "6/day") || !strings.Contains(breakdown, "~2 active days") {
+		t.Errorf("Unexpected breakdown: %s", breakdown)
+	}
+}
+
+func TestEstimateCampaignDurationEdgeCases(t *testing.T) {
+	cfg, schedule := estimateTestConfig()
+
+	if duration, _ := EstimateCampaignDuration(0, cfg, schedule); duration != 0 {
+		t.Errorf("Expected 0 for no targets, got %s", duration)
+	}
+
+	// Cooldown longer than the window can never finish
+	cfg.CooldownBetweenActions = 10 * time.Hour
+	duration, breakdown := EstimateCampaignDuration(5, cfg, schedule)
+	if duration != 0 || !strings.Contains(breakdown, "cannot complete") {
+		t.Errorf("Expected cannot-complete result, got %s / %s", duration, breakdown)
+	}
+}
+
+func TestEstimateCampaignDurationMinuteWindow(t *testing.T) {
+	cfg := RateLimitConfig{CooldownBetweenActions: 30 * time.Minute}
+	schedule := ScheduleConfig{StartHour: 9, StartMinute: 30, EndHour: 10, EndMinute: 30, TargetTimezone: "UTC"}
+	start := time.Date(2025, 12, 30, 9, 30, 0, 0, time.UTC)
+
+	// A one-hour window fits two 30-minute actions; the third spills into the next day
+	duration, breakdown := estimateCampaignDurationFrom(start, 3, cfg, schedule)
+
+	if expected := 24*time.Hour + 30*time.Minute; duration != expected {
+		t.Errorf("Expected %s, got %s", expected, duration)
+	}
+	if !strings.Contains(breakdown, "2/day") || !strings.Contains(breakdown, "active 9:30-10:30") {
+		t.Errorf("Unexpected breakdown: %s", breakdown)
+	}
+}
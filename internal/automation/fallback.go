@@ -0,0 +1,107 @@
+package automation
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"linkedin-automation/internal/logger"
+)
+
+// emptyFieldMarker wraps the name of an empty profile field in place of its
+// value while rendering, so the phrase around it can be rewritten afterwards
+const emptyFieldMarker = "\uE000"
+
+// optionalProfileFields are the scraped fields search cards often lack
+var optionalProfileFields = []string{"Company", "Title"}
+
+// emptyFieldPhrases rewrite the text around an empty field, in order.
+// A missing title after an article becomes "a professional"; a preposition
+// leading into a missing field ("your work at ___") is dropped with it.
+var emptyFieldPhrases = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)\b(a)n?\s+` + markerFor("Title")), "$1 professional"},
+	{regexp.MustCompile(`(?i)\s*\b(?:at|with|from|for|in|as|of|about|on|by|the)\s+` + emptyFieldMarker + `\w+` + emptyFieldMarker), ""},
+	{regexp.MustCompile(emptyFieldMarker + `\w+` + emptyFieldMarker), ""},
+	{regexp.MustCompile(`[ \t]+([.,!?;:])`), "$1"},
+}
+
+// markerFor returns the stand-in for an empty field
+func markerFor(field string) string {
+	return emptyFieldMarker + field + emptyFieldMarker
+}
+
+// markEmptyFields replaces the {{.Field}} actions of the empty optional fields
+// in body with their markers. Conditions such as {{if .Company}} are left alone.
+// Returns the rewritten body and whether any action was replaced.
+func markEmptyFields(body string, vars TemplateVariables) (string, bool) {
+	values := map[string]string{"Company": vars.Company, "Title": vars.Title}
+
+	marked := false
+	for _, field := range optionalProfileFields {
+		if strings.TrimSpace(values[field]) != "" {
+			continue
+		}
+
+		action := regexp.MustCompile(`\{\{-?\s*\.` + field + `\s*-?\}\}`)
+		if action.MatchString(body) {
+			body = action.ReplaceAllString(body, markerFor(field))
+			marked = true
+		}
+	}
+	return body, marked
+}
+
+// repairEmptyFields rewrites the phrasing around markers left in rendered text.
+// Reports whether the text contained any.
+func repairEmptyFields(text string) (string, bool) {
+	if !strings.Contains(text, emptyFieldMarker) {
+		return text, false
+	}
+
+	for _, phrase := range emptyFieldPhrases {
+		text = phrase.pattern.ReplaceAllString(text, phrase.replacement)
+	}
+	return text, true
+}
+
+// usesEmptyField reports whether rendering tmplDef with vars would print a
+// Company or Title that is empty
+func usesEmptyField(tmplDef MessageTemplate, vars TemplateVariables) bool {
+	body, marked := markEmptyFields(tmplDef.Body, vars)
+	if !marked {
+		return false
+	}
+
+	t, err := template.New(tmplDef.ID).Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return false
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return false
+	}
+	return strings.Contains(buf.String(), emptyFieldMarker)
+}
+
+// withFallbackTemplate returns the fallback template in place of tmplDef when
+// tmplDef would print an empty Company or Title and a fallback is configured.
+// The bool reports whether the fallback was chosen.
+func withFallbackTemplate(tmplDef *MessageTemplate, vars TemplateVariables, fallbackID string) (*MessageTemplate, bool) {
+	if fallbackID == "" || fallbackID == tmplDef.ID || !usesEmptyField(*tmplDef, vars) {
+		return tmplDef, false
+	}
+
+	fallback, err := GetTemplateByID(fallbackID)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Fallback template unavailable, keeping %s: %s", tmplDef.ID, err.Error()))
+		return tmplDef, false
+	}
+
+	logger.Info(fmt.Sprintf("Profile has no company or title for template '%s', using fallback '%s'", tmplDef.ID, fallbackID))
+	return fallback, true
+}
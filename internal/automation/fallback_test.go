@@ -0,0 +1,167 @@
+package automation
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+
+	"linkedin-automation/internal/storage"
+)
+
+// danglingPhrase matches a preposition or article left without its object
+var danglingPhrase = regexp.MustCompile(`(?i)\b(at|as|a|an|with|in|of|about|the)\s*([.,!?;:]|$)|\s[.,!?;:]|  `)
+
+func TestRenderTemplateEmptyCompanyAndTitle(t *testing.T) {
+	vars := TemplateVariables{
+		FirstName:   "Ann",
+		YourTitle:   "Engineer",
+		YourCompany: "Acme",
+		Industry:    "fintech",
+		// Sender-provided; only the scraped fields are left empty
+		CustomReason: "a new payments API",
+	}
+
+	templates := append(GetConnectionRequestTemplates(), GetMessageTemplates()...)
+	for _, tmpl := range templates {
+		t.Run(tmpl.ID, func(t *testing.T) {
+			rendered, err := RenderTemplate(tmpl, vars)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if strings.Contains(rendered, emptyFieldMarker) {
+				t.Errorf("Marker left in output: %q", rendered)
+			}
+			for _, line := range strings.Split(rendered, "\n") {
+				if match := danglingPhrase.FindString(line); match != "" {
+					t.Errorf("Dangling %q in line %q", match, line)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderTemplateRephrasesEmptyFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		vars TemplateVariables
+		want string
+	}{
+		{
+			"Preposition dropped with company",
+			"Hi {{.FirstName}}, I was impressed by your work at {{.Company}}. Let's connect.",
+			TemplateVariables{FirstName: "Ann"},
+			"Hi Ann, I was impressed by your work. Let's connect.",
+		},
+		{
+			"Missing title becomes professional",
+			"Hi {{.FirstName}}, I noticed you're an {{.Title}} at {{.Company}}.",
+			TemplateVariables{FirstName: "Ann"},
+			"Hi Ann, I noticed you're a professional.",
+		},
+		{
+			"Known company kept when only title is empty",
+			"Hi {{.FirstName}}, your work as {{.Title}} at {{.Company}} stood out!",
+			TemplateVariables{FirstName: "Ann", Company: "Acme"},
+			"Hi Ann, your work at Acme stood out!",
+		},
+		{
+			"Guarded clause untouched",
+			"Hi {{.FirstName}}{{if .Company}} from {{.Company}}{{end}}, let's connect.",
+			TemplateVariables{FirstName: "Ann"},
+			"Hi Ann, let's connect.",
+		},
+		{
+			"Whitespace-trimmed action",
+			"Hi {{.FirstName}}, great work at {{ .Company }}!",
+			TemplateVariables{FirstName: "Ann"},
+			"Hi Ann, great work!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := MessageTemplate{ID: "test", Type: TemplateConnectionRequest, Body: tt.body, MaxLength: ConnectionNoteMaxLength}
+			got, err := RenderTemplate(tmpl, tt.vars)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUsesEmptyField(t *testing.T) {
+	generic, _ := GetTemplateByID("conn_generic")
+	industry, _ := GetTemplateByID("conn_industry")
+	varied, _ := GetTemplateByID("conn_varied")
+
+	noCompany := TemplateVariables{FirstName: "Ann", Title: "Engineer"}
+	if !usesEmptyField(*generic, noCompany) {
+		t.Error("conn_generic prints the company, expected true")
+	}
+	if usesEmptyField(*generic, TemplateVariables{FirstName: "Ann", Company: "Acme", Title: "Engineer"}) {
+		t.Error("Expected false when company and title are set")
+	}
+	if usesEmptyField(*industry, noCompany) {
+		t.Error("conn_industry does not use the company, expected false")
+	}
+
+	// conn_varied only prints the company behind MentionCompany, never set for an empty company
+	if usesEmptyField(*varied, RandomizeClauses(noCompany, ClauseConfig{CompanyProbability: 1}, rand.New(rand.NewSource(1)))) {
+		t.Error("conn_varied guards the company, expected false")
+	}
+}
+
+func TestPrepareFromPoolUsesFallbackTemplate(t *testing.T) {
+	profile := storage.Profile{ID: "ann", Name: "Ann Lee", ProfileURL: "https://www.linkedin.com/in/ann/"}
+	pool := NotePool{{TemplateID: "conn_generic"}}
+	r := rand.New(rand.NewSource(1))
+
+	request, err := prepareConnectionRequestFromPool(profile, pool, TemplateVariables{Industry: "fintech"}, PrepareOptions{FallbackTemplateID: "conn_industry"}, r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.TemplateID != "conn_industry" {
+		t.Errorf("Expected fallback template conn_industry, got %s", request.TemplateID)
+	}
+	if !strings.Contains(request.Note, "fintech") {
+		t.Errorf("Expected the fallback's text, got %q", request.Note)
+	}
+
+	// A profile with a company keeps the chosen template
+	profile.Company = "Acme"
+	request, err = prepareConnectionRequestFromPool(profile, pool, TemplateVariables{}, PrepareOptions{FallbackTemplateID: "conn_industry"}, r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.TemplateID != "conn_generic" {
+		t.Errorf("Expected conn_generic, got %s", request.TemplateID)
+	}
+}
+
+func TestPrepareWithGeneratorUsesFallbackTemplate(t *testing.T) {
+	profile := storage.Profile{ID: "ann", Name: "Ann Lee", ProfileURL: "https://www.linkedin.com/in/ann/"}
+	generator := TemplateNoteGenerator{TemplateID: "conn_brief", SenderVars: TemplateVariables{Industry: "fintech"}}
+
+	request, err := prepareConnectionRequestWithGenerator(profile, generator, "", PrepareOptions{FallbackTemplateID: "conn_industry"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.TemplateID != "conn_industry" {
+		t.Errorf("Expected fallback template conn_industry, got %s", request.TemplateID)
+	}
+
+	// An unknown fallback keeps the original template, rephrased
+	request, err = prepareConnectionRequestWithGenerator(profile, generator, "", PrepareOptions{FallbackTemplateID: "missing"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.TemplateID != "conn_brief" || request.Note != "Hi Ann, impressive work! Would love to connect." {
+		t.Errorf("Expected rephrased conn_brief note, got %s: %q", request.TemplateID, request.Note)
+	}
+}
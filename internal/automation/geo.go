@@ -0,0 +1,141 @@
+package automation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
+)
+
+// geoTypeaheadScript asks LinkedIn's location typeahead (the API behind the
+// search page's Locations filter) for places matching the keywords. It runs
+// in the page so the session cookies and CSRF token go with it.
+const geoTypeaheadScript = `async (keywords) => {
+	const match = document.cookie.match(/JSESSIONID="?([^";]+)"?/);
+	const url = '/voyager/api/typeahead/hitsV2?keywords=' + encodeURIComponent(keywords) +
+		'&origin=OTHER&q=type&type=GEO' +
+		'&queryContext=List(geoVersion-%3E3,bingGeoSubTypeFilters-%3EMARKET_AREA%7CCOUNTRY_REGION%7CADMIN_DIVISION_1%7CCITY)';
+	const res = await fetch(url, {
+		credentials: 'include',
+		headers: {'csrf-token': match ? match[1] : '', 'accept': 'application/json'},
+	});
+	return {status: res.status, body: await res.text()};
+}`
+
+// geoTypeaheadResponse is the part of the typeahead response naming each place and its URN
+type geoTypeaheadResponse struct {
+	Elements []struct {
+		TargetURN string `json:"targetUrn"`
+		Text      struct {
+			Text string `json:"text"`
+		} `json:"text"`
+	} `json:"elements"`
+}
+
+// ResolveLocationURN returns the geoUrn ID for a location missing from
+// utils.LinkedInLocations. The geo_cache table is checked first; otherwise
+// LinkedIn's location typeahead is asked once and the answer is cached for
+// later runs. Reports false when the location can't be resolved.
+func ResolveLocationURN(page *rod.Page, db *storage.Database, location string) (string, bool) {
+	return resolveLocationURN(db, location, func(name string) (string, error) {
+		return lookupGeoURN(page, name)
+	})
+}
+
+// resolveLocationURN is ResolveLocationURN with the typeahead lookup injected
+func resolveLocationURN(db *storage.Database, location string, lookup func(string) (string, error)) (string, bool) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return "", false
+	}
+	if urn, found := utils.LinkedInLocations[location]; found {
+		return urn, true
+	}
+
+	if db != nil {
+		urn, found, err := db.GetGeoURN(location)
+		if err != nil {
+			logger.Warning("Failed to read the geo cache: " + err.Error())
+		} else if found {
+			logger.Debug(fmt.Sprintf("Location '%s' resolved from the geo cache: %s", location, urn))
+			return urn, true
+		}
+	}
+
+	urn, err := lookup(location)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Failed to resolve location '%s': %s", location, err.Error()))
+		return "", false
+	}
+	logger.Info(fmt.Sprintf("Resolved location '%s' to geoUrn %s", location, urn))
+
+	if db != nil {
+		if err := db.SaveGeoURN(location, urn); err != nil {
+			logger.Warning("Failed to cache the resolved location: " + err.Error())
+		}
+	}
+	return urn, true
+}
+
+// lookupGeoURN asks LinkedIn's location typeahead for the location's geoUrn ID
+func lookupGeoURN(page *rod.Page, location string) (string, error) {
+	res, err := page.Timeout(15*time.Second).Eval(geoTypeaheadScript, location)
+	if err != nil {
+		return "", fmt.Errorf("typeahead request failed: %w", err)
+	}
+
+	if status := res.Value.Get("status").Int(); status != 200 {
+		return "", fmt.Errorf("typeahead returned HTTP %d", status)
+	}
+	return parseGeoTypeahead([]byte(res.Value.Get("body").Str()), location)
+}
+
+// parseGeoTypeahead picks the geoUrn ID for location from a typeahead
+// response: the place whose name matches it exactly, else LinkedIn's top hit
+func parseGeoTypeahead(body []byte, location string) (string, error) {
+	var resp geoTypeaheadResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse typeahead response: %w", err)
+	}
+
+	best := ""
+	for _, element := range resp.Elements {
+		id := geoURNID(element.TargetURN)
+		if id == "" {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(element.Text.Text), location) {
+			return id, nil
+		}
+		if best == "" {
+			best = id
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("no place found for '%s'", location)
+	}
+	return best, nil
+}
+
+// geoURNID returns the numeric ID of a geo URN such as urn:li:fs_geo:103644278
+// ("" when it isn't one)
+func geoURNID(urn string) string {
+	i := strings.LastIndex(urn, ":")
+	if i < 0 || !strings.Contains(urn, "geo") {
+		return ""
+	}
+	id := urn[i+1:]
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return id
+}
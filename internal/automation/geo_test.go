@@ -0,0 +1,117 @@
+package automation
+
+import (
+	"errors"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"linkedin-automation/internal/storage"
+)
+
+// geoTestDB opens an empty database in the test's temp directory
+func geoTestDB(t *testing.T) *storage.Database {
+	t.Helper()
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_geo.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestResolveLocationURNPersistsLookup(t *testing.T) {
+	db := geoTestDB(t)
+
+	lookups := 0
+	lookup := func(name string) (string, error) {
+		lookups++
+		return "100733275", nil
+	}
+
+	urn, ok := resolveLocationURN(db, "Lisbon", lookup)
+	if !ok || urn != "100733275" {
+		t.Fatalf("Expected the looked-up URN, got %q (ok %v)", urn, ok)
+	}
+
+	cached, found, err := db.GetGeoURN("lisbon ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !found || cached != "100733275" {
+		t.Errorf("Expected the resolved URN to be cached, got %q (found %v)", cached, found)
+	}
+	if lookups != 1 {
+		t.Errorf("Expected one lookup, got %d", lookups)
+	}
+}
+
+func TestResolveLocationURNCacheHit(t *testing.T) {
+	db := geoTestDB(t)
+	if err := db.SaveGeoURN("Lisbon", "100733275"); err != nil {
+		t.Fatalf("Failed to seed cache: %v", err)
+	}
+
+	lookup := func(name string) (string, error) {
+		t.Errorf("Unexpected lookup for %q", name)
+		return "", nil
+	}
+
+	urn, ok := resolveLocationURN(db, "Lisbon", lookup)
+	if !ok || urn != "100733275" {
+		t.Errorf("Expected the cached URN, got %q (ok %v)", urn, ok)
+	}
+
+	// Locations in the static map never need the cache or a lookup
+	if urn, ok := resolveLocationURN(db, "London", lookup); !ok || urn == "" {
+		t.Errorf("Expected London from the static map, got %q (ok %v)", urn, ok)
+	}
+}
+
+func TestResolveLocationURNLookupFails(t *testing.T) {
+	db := geoTestDB(t)
+	lookup := func(name string) (string, error) {
+		return "", errors.New("typeahead returned HTTP 403")
+	}
+
+	if urn, ok := resolveLocationURN(db, "Lisbon", lookup); ok || urn != "" {
+		t.Errorf("Expected no URN, got %q (ok %v)", urn, ok)
+	}
+	if _, found, _ := db.GetGeoURN("Lisbon"); found {
+		t.Error("Expected nothing cached after a failed lookup")
+	}
+}
+
+func TestParseGeoTypeahead(t *testing.T) {
+	body := []byte(`{"elements":[
+		{"targetUrn":"urn:li:fs_geo:105773754","text":{"text":"Lisbon, Lisbon, Portugal"}},
+		{"targetUrn":"urn:li:fs_geo:100733275","text":{"text":"Lisbon"}},
+		{"targetUrn":"urn:li:fs_geo:90009616","text":{"text":"Lisbon Metropolitan Area"}}
+	]}`)
+
+	if got, err := parseGeoTypeahead(body, "lisbon"); err != nil || got != "100733275" {
+		t.Errorf("Expected the exact match, got %q (%v)", got, err)
+	}
+	if got, err := parseGeoTypeahead(body, "Lisboa"); err != nil || got != "105773754" {
+		t.Errorf("Expected the top hit, got %q (%v)", got, err)
+	}
+
+	if _, err := parseGeoTypeahead([]byte(`{"elements":[]}`), "Nowhere"); err == nil {
+		t.Error("Expected an error for no places")
+	}
+	if _, err := parseGeoTypeahead([]byte(`<html>`), "Lisbon"); err == nil {
+		t.Error("Expected an error for a non-JSON body")
+	}
+}
+
+func TestBuildSearchURLResolvedLocation(t *testing.T) {
+	searchURL, err := buildSearchURL(SearchConfig{Keywords: "engineer", Location: "Lisbon", LocationURN: "100733275"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parsed, _ := url.Parse(searchURL)
+	if got := parsed.Query().Get("geoUrn"); got != `["urn:li:fs_geo:100733275"]` {
+		t.Errorf("Expected the resolved geoUrn, got %q", got)
+	}
+}
@@ -0,0 +1,297 @@
+package automation
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/browser"
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/stealth"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
+)
+
+// Context sources recorded on saved profiles (storage.Profile.ContextSource)
+const (
+	ContextSourceSearch = "search"
+	ContextSourceGroup  = "group" // Recorded as "group:<members URL>"
+	ContextSourceEvent  = "event" // Recorded as "event:<event URL>"
+)
+
+// maxListLoads caps the "Show more results" clicks and scrolls on a member list
+const maxListLoads = 20
+
+// maxAttendeePages caps the attendee search pages read for one event
+const maxAttendeePages = 10
+
+var (
+	groupIDPattern = regexp.MustCompile(`/groups/(\d+)`)
+	eventIDPattern = regexp.MustCompile(`/events/(?:[^/?#]*-)?(\d{10,})`)
+)
+
+// ParseListURLs splits a comma-separated URL list (as in GROUP_URLS), dropping blank entries
+func ParseListURLs(spec string) []string {
+	var urls []string
+	for _, u := range strings.Split(spec, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// groupMembersURL returns the member list URL of the group groupURL points to
+func groupMembersURL(groupURL string) (string, error) {
+	match := groupIDPattern.FindStringSubmatch(groupURL)
+	if match == nil {
+		return "", fmt.Errorf("%q is not a LinkedIn group URL", groupURL)
+	}
+	return fmt.Sprintf("%s/groups/%s/members/", utils.LinkedInBaseURL, match[1]), nil
+}
+
+// eventAttendeesURL returns the people search listing the attendees of the
+// event eventURL points to; the event's "Attendees" link opens the same search
+func eventAttendeesURL(eventURL string, pageNum int) (string, error) {
+	match := eventIDPattern.FindStringSubmatch(eventURL)
+	if match == nil {
+		return "", fmt.Errorf("%q is not a LinkedIn event URL", eventURL)
+	}
+
+	params := url.Values{}
+	params.Set("eventAttending", fmt.Sprintf(`["%s"]`, match[1]))
+	params.Set("origin", "EVENT_PAGE_CANONICAL")
+	if pageNum > 1 {
+		params.Set("page", fmt.Sprintf("%d", pageNum))
+	}
+	return utils.LinkedInSearchURL + "?" + params.Encode(), nil
+}
+
+// ScrapeGroupMembers reads up to max members (0 = all it can load) from the
+// member list of the group at groupURL, clicking "Show more results" and
+// scrolling to load more. Members of a shared group accept more often; save
+// them with SaveScrapedProfiles to feed the connect phase.
+func ScrapeGroupMembers(page *rod.Page, groupURL string, max int) ([]SearchResult, error) {
+	membersURL, err := groupMembersURL(groupURL)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Opening group members: " + membersURL)
+	if err := openListPage(page, membersURL); err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	seen := make(map[string]bool)
+	for loads := 0; ; loads++ {
+		members, err := ParseGroupMembers(page)
+		if err != nil {
+			return results, err
+		}
+
+		added := 0
+		for _, member := range members {
+			if seen[member.ProfileID] {
+				continue
+			}
+			seen[member.ProfileID] = true
+			results = append(results, member)
+			added++
+		}
+
+		if max > 0 && len(results) >= max {
+			return results[:max], nil
+		}
+		if (added == 0 && loads > 0) || loads >= maxListLoads {
+			return results, nil
+		}
+
+		loadMoreListItems(page)
+	}
+}
+
+// ScrapeEventAttendees reads up to max attendees (0 = all, within
+// maxAttendeePages pages) of the event at eventURL from its attendee search
+func ScrapeEventAttendees(page *rod.Page, eventURL string, max int) ([]SearchResult, error) {
+	var results []SearchResult
+	seen := make(map[string]bool)
+
+	for pageNum := 1; pageNum <= maxAttendeePages; pageNum++ {
+		attendeesURL, err := eventAttendeesURL(eventURL, pageNum)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info(fmt.Sprintf("Opening event attendees page %d: %s", pageNum, attendeesURL))
+		if err := openListPage(page, attendeesURL); err != nil {
+			return results, err
+		}
+
+		attendees, err := ParseSearchResults(page)
+		if err != nil {
+			return results, fmt.Errorf("failed to parse attendees page %d: %w", pageNum, err)
+		}
+
+		added := 0
+		for _, attendee := range attendees {
+			if seen[attendee.ProfileID] {
+				continue
+			}
+			seen[attendee.ProfileID] = true
+			results = append(results, attendee)
+			added++
+		}
+
+		if max > 0 && len(results) >= max {
+			return results[:max], nil
+		}
+		if added == 0 {
+			break
+		}
+		stealth.RandomDelay(2000, 4000)
+	}
+
+	return results, nil
+}
+
+// openListPage navigates to a member or attendee list and checks it wasn't
+// replaced by a checkpoint or login wall
+func openListPage(page *rod.Page, listURL string) error {
+	if err := navigate(page, nil, listURL, ""); err != nil {
+		return fmt.Errorf("failed to open %s: %w", listURL, err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		logger.Warning("List page did not finish loading: " + err.Error())
+	}
+	stealth.RandomDelay(2000, 3000)
+
+	info, err := page.Info()
+	if err != nil {
+		return fmt.Errorf("failed to read page URL: %w", err)
+	}
+	return checkSearchPageAccess(info.URL)
+}
+
+// loadMoreListItems clicks "Show more results" if the list has it, otherwise
+// scrolls to the bottom to trigger lazy loading
+func loadMoreListItems(page *rod.Page) {
+	if has, button, err := page.Has(utils.Selectors.ShowMoreResults); err == nil && has {
+		stealth.RandomDelay(800, 1800)
+		if err := stealth.SafeClick(page, button); err != nil {
+			logger.Warning("Failed to click Show more results: " + err.Error())
+		}
+	} else if err := stealth.ScrollThroughContent(page, 2); err != nil {
+		logger.Warning("Failed to scroll the list: " + err.Error())
+	}
+	stealth.RandomDelay(1500, 3000)
+}
+
+// ParseGroupMembersFromHTML runs ParseGroupMembers over a saved member list
+// page in a headless replay browser (see ParseSearchResultsFromHTML)
+func ParseGroupMembersFromHTML(html string) ([]SearchResult, error) {
+	page, closeBrowser, err := browser.ReplayPage(html)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBrowser()
+
+	return ParseGroupMembers(page)
+}
+
+// ParseGroupMembers extracts the members shown on a group's member list.
+// Rows without a visible name or profile link are skipped.
+func ParseGroupMembers(page *rod.Page) ([]SearchResult, error) {
+	items, err := page.Elements(utils.Selectors.GroupMemberItem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find group members: %w", err)
+	}
+	return parseResultContainers(items, parseGroupMember), nil
+}
+
+// parseGroupMember extracts one member row; rows share the new search layout's
+// shape (name, "Role at Company" headline, profile link)
+func parseGroupMember(item *rod.Element) (*SearchResult, error) {
+	var href string
+	if link, err := item.Element("a[href*='/in/']"); err == nil {
+		if attr, err := link.Attribute("href"); err == nil && attr != nil {
+			href = *attr
+		}
+	}
+
+	text := func(selector string) string {
+		if el, err := item.Element(selector); err == nil {
+			value, _ := el.Text()
+			return value
+		}
+		return ""
+	}
+
+	return parseSearchResultV2("", absoluteProfileURL(href),
+		firstLine(text(utils.Selectors.GroupMemberName)),
+		firstLine(text(utils.Selectors.GroupMemberHeadline)),
+		"", "")
+}
+
+// absoluteProfileURL prefixes a relative profile link ("/in/jane-doe/") with LinkedIn's origin
+func absoluteProfileURL(href string) string {
+	if strings.HasPrefix(href, "/") {
+		return utils.LinkedInBaseURL + href
+	}
+	return href
+}
+
+// firstLine returns the first non-blank line of text; list rows repeat the
+// name for screen readers on a second line
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// SaveScrapedProfiles saves group members or event attendees as profiles for
+// the connect phase, recording source ("group:<url>" or "event:<url>") as
+// their origin. Profiles seen within duplicateDays are skipped. Returns how
+// many were saved.
+func SaveScrapedProfiles(db *storage.Database, results []SearchResult, source string, duplicateDays int) (int, error) {
+	saved := 0
+	for _, result := range results {
+		if isDupe, err := db.IsDuplicateProfile(result.ProfileID, duplicateDays); err != nil {
+			logger.Warning(fmt.Sprintf("Failed to check duplicate for %s: %s", result.ProfileID, err.Error()))
+		} else if isDupe {
+			continue
+		}
+
+		scrapedAt := result.ScrapedAt
+		if scrapedAt.IsZero() {
+			scrapedAt = time.Now()
+		}
+		profile := storage.Profile{
+			ID:         result.ProfileID,
+			Name:       result.Name,
+			Title:      result.Title,
+			Company:    result.Company,
+			Location:   result.Location,
+			ProfileURL: result.ProfileURL,
+			VisitedAt:  scrapedAt,
+			CreatedAt:  scrapedAt,
+
+			MutualCount: result.MutualCount,
+			OpenToWork:  result.OpenToWork,
+
+			ContextSource: source,
+		}
+		if err := db.SaveProfile(profile); err != nil {
+			return saved, fmt.Errorf("failed to save profile %s: %w", result.ProfileID, err)
+		}
+		saved++
+	}
+	return saved, nil
+}
@@ -0,0 +1,149 @@
+package automation
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+func TestParseGroupMembersFixture(t *testing.T) {
+	members, err := ParseGroupMembers(openFixture(t, "group_members.html"))
+	if err != nil {
+		t.Fatalf("ParseGroupMembers() error = %v", err)
+	}
+
+	// The hidden "LinkedIn Member" row has no name or link and is skipped
+	if len(members) != 2 {
+		t.Fatalf("Expected 2 members, got %d: %+v", len(members), members)
+	}
+
+	profiles := fixtureProfiles(members)
+	jane := profiles["jane-doe"]
+	if jane.Name != "Jane Doe" || jane.Title != "Staff Engineer" || jane.Company != "Acme Corp" ||
+		jane.ProfileURL != "https://www.linkedin.com/in/jane-doe/" {
+		t.Errorf("Unexpected jane-doe member: %+v", jane)
+	}
+
+	john := profiles["john-roe"]
+	if john.Name != "John Roe" || john.Title != "Head of Platform Engineering" || john.Company != "" ||
+		john.ProfileURL != "https://www.linkedin.com/in/john-roe/" {
+		t.Errorf("Unexpected john-roe member: %+v", john)
+	}
+}
+
+func TestGroupMembersURL(t *testing.T) {
+	tests := []struct {
+		groupURL string
+		expected string
+	}{
+		{"https://www.linkedin.com/groups/1234567/", "https://www.linkedin.com/groups/1234567/members/"},
+		{"https://www.linkedin.com/groups/1234567/members/?q=go", "https://www.linkedin.com/groups/1234567/members/"},
+		{"linkedin.com/groups/42", "https://www.linkedin.com/groups/42/members/"},
+		{"https://www.linkedin.com/company/acme/", ""},
+	}
+
+	for _, test := range tests {
+		got, err := groupMembersURL(test.groupURL)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("groupMembersURL(%q): expected an error, got %s", test.groupURL, got)
+			}
+			continue
+		}
+		if err != nil || got != test.expected {
+			t.Errorf("groupMembersURL(%q) = %q, %v; expected %s", test.groupURL, got, err, test.expected)
+		}
+	}
+}
+
+func TestEventAttendeesURL(t *testing.T) {
+	base := "https://www.linkedin.com/search/results/people/?eventAttending=%5B%227123456789012345678%22%5D&origin=EVENT_PAGE_CANONICAL"
+
+	tests := []struct {
+		eventURL string
+		pageNum  int
+		expected string
+	}{
+		{"https://www.linkedin.com/events/7123456789012345678/", 1, base},
+		{"https://www.linkedin.com/events/gomeetupberlin7123456789012345678/", 1, ""},
+		{"https://www.linkedin.com/events/go-meetup-berlin-7123456789012345678/about/", 1, base},
+		{"https://www.linkedin.com/events/7123456789012345678/comments/", 3, base + "&page=3"},
+		{"https://www.linkedin.com/events/", 1, ""},
+	}
+
+	for _, test := range tests {
+		got, err := eventAttendeesURL(test.eventURL, test.pageNum)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("eventAttendeesURL(%q): expected an error, got %s", test.eventURL, got)
+			}
+			continue
+		}
+		if err != nil || got != test.expected {
+			t.Errorf("eventAttendeesURL(%q, %d) = %q, %v; expected %s", test.eventURL, test.pageNum, got, err, test.expected)
+		}
+	}
+}
+
+func TestParseListURLs(t *testing.T) {
+	got := ParseListURLs(" https://www.linkedin.com/groups/1/ ,, https://www.linkedin.com/groups/2/")
+	want := []string{"https://www.linkedin.com/groups/1/", "https://www.linkedin.com/groups/2/"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := ParseListURLs(""); got != nil {
+		t.Errorf("Expected no URLs, got %v", got)
+	}
+}
+
+func TestSaveScrapedProfiles(t *testing.T) {
+	testDBPath := "./test_scraped.db"
+	defer os.Remove(testDBPath)
+
+	db, err := storage.InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	// Found by a search first; the group keeps that origin
+	if err := db.SaveProfile(storage.Profile{
+		ID: "searched", Name: "Sam Search", ProfileURL: "https://www.linkedin.com/in/searched/",
+		VisitedAt: time.Now().AddDate(0, 0, -60), CreatedAt: time.Now().AddDate(0, 0, -60),
+		ContextSource: ContextSourceSearch,
+	}); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+
+	members := []SearchResult{
+		{ProfileID: "jane-doe", Name: "Jane Doe", Title: "Staff Engineer", Company: "Acme Corp", ProfileURL: "https://www.linkedin.com/in/jane-doe/"},
+		{ProfileID: "searched", Name: "Sam Search", ProfileURL: "https://www.linkedin.com/in/searched/"},
+	}
+	source := ContextSourceGroup + ":https://www.linkedin.com/groups/1234567/members/"
+
+	saved, err := SaveScrapedProfiles(db, members, source, 30)
+	if err != nil {
+		t.Fatalf("SaveScrapedProfiles() error = %v", err)
+	}
+	if saved != 2 {
+		t.Errorf("Expected 2 profiles saved, got %d", saved)
+	}
+
+	for id, expected := range map[string]string{"jane-doe": source, "searched": ContextSourceSearch} {
+		profile, err := db.GetProfile(id)
+		if err != nil {
+			t.Fatalf("Failed to get %s: %v", id, err)
+		}
+		if profile.ContextSource != expected {
+			t.Errorf("%s: expected context source %q, got %q", id, expected, profile.ContextSource)
+		}
+	}
+
+	// Saved again within the duplicate window: skipped
+	if saved, err := SaveScrapedProfiles(db, members, source, 30); err != nil || saved != 0 {
+		t.Errorf("Expected recently saved profiles to be skipped, saved %d (%v)", saved, err)
+	}
+}
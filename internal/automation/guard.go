@@ -0,0 +1,86 @@
+package automation
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"linkedin-automation/internal/storage"
+)
+
+// AcceptanceGuardConfig stops connection requests when the recent acceptance
+// rate falls below a floor - a sign the targeting or notes are off, or that
+// the account is being limited
+type AcceptanceGuardConfig struct {
+	MinRate    float64 // Floor as a fraction, e.g. 0.2 for 20% (0 = guard disabled)
+	MinSample  int     // Requests needed before the rate is trusted
+	WindowDays int     // Look-back window for the rate
+	MinAgeDays int     // Requests younger than this are not judged yet
+}
+
+// GetAcceptanceGuardConfig reads the guard from MIN_ACCEPTANCE_RATE (percent),
+// ACCEPTANCE_RATE_MIN_SAMPLE, ACCEPTANCE_RATE_DAYS and ACCEPTANCE_RATE_MIN_AGE_DAYS
+func GetAcceptanceGuardConfig() AcceptanceGuardConfig {
+	config := AcceptanceGuardConfig{
+		MinRate:    0,  // Disabled by default
+		MinSample:  20, // Fewer requests than this are too noisy to judge
+		WindowDays: 14,
+		MinAgeDays: 3, // Most invitations that will be accepted are within a few days
+	}
+
+	if envRate := os.Getenv("MIN_ACCEPTANCE_RATE"); envRate != "" {
+		if val, err := strconv.ParseFloat(envRate, 64); err == nil && val > 0 && val <= 100 {
+			config.MinRate = val / 100
+		}
+	}
+
+	if envSample := os.Getenv("ACCEPTANCE_RATE_MIN_SAMPLE"); envSample != "" {
+		if val, err := strconv.Atoi(envSample); err == nil && val > 0 {
+			config.MinSample = val
+		}
+	}
+
+	if envDays := os.Getenv("ACCEPTANCE_RATE_DAYS"); envDays != "" {
+		if val, err := strconv.Atoi(envDays); err == nil && val > 0 {
+			config.WindowDays = val
+		}
+	}
+
+	if envAge := os.Getenv("ACCEPTANCE_RATE_MIN_AGE_DAYS"); envAge != "" {
+		if val, err := strconv.Atoi(envAge); err == nil && val >= 0 {
+			config.MinAgeDays = val
+		}
+	}
+
+	return config
+}
+
+// CheckAcceptanceRate returns an error with a recommendation when connection
+// requests should stop because the recent acceptance rate is below the floor
+func CheckAcceptanceRate(db *storage.Database, config AcceptanceGuardConfig) error {
+	if config.MinRate <= 0 {
+		return nil
+	}
+
+	rate, sample, err := db.GetRecentAcceptanceRate(config.WindowDays, config.MinAgeDays)
+	if err != nil {
+		return fmt.Errorf("failed to get acceptance rate: %w", err)
+	}
+
+	return acceptanceRateDecision(rate, sample, config)
+}
+
+// acceptanceRateDecision applies the guard to a measured rate and sample size
+func acceptanceRateDecision(rate float64, sample int, config AcceptanceGuardConfig) error {
+	if config.MinRate <= 0 || sample < config.MinSample {
+		return nil
+	}
+
+	if rate < config.MinRate {
+		return fmt.Errorf("acceptance rate %.1f%% over the last %d days (%d requests) is below the %.1f%% floor - "+
+			"pause outreach, review your search targeting and connection notes, and check the account for restrictions",
+			rate*100, config.WindowDays, sample, config.MinRate*100)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package automation
+
+import (
+	"testing"
+)
+
+func TestAcceptanceRateDecision(t *testing.T) {
+	config := AcceptanceGuardConfig{MinRate: 0.2, MinSample: 20, WindowDays: 14}
+
+	tests := []struct {
+		name   string
+		rate   float64
+		sample int
+		config AcceptanceGuardConfig
+		stop   bool
+	}{
+		{"low rate, adequate sample", 0.1, 40, config, true},
+		{"low rate, small sample", 0.0, 5, config, false},
+		{"sample exactly at minimum", 0.1, 20, config, true},
+		{"rate at floor", 0.2, 40, config, false},
+		{"healthy rate", 0.45, 40, config, false},
+		{"guard disabled", 0.0, 100, AcceptanceGuardConfig{MinSample: 20, WindowDays: 14}, false},
+	}
+
+	for _, test := range tests {
+		err := acceptanceRateDecision(test.rate, test.sample, test.config)
+		if (err != nil) != test.stop {
+			t.Errorf("%s: expected stop=%v, got %v", test.name, test.stop, err)
+		}
+	}
+}
+
+func TestGetAcceptanceGuardConfig(t *testing.T) {
+	t.Setenv("MIN_ACCEPTANCE_RATE", "")
+	t.Setenv("ACCEPTANCE_RATE_MIN_SAMPLE", "")
+	t.Setenv("ACCEPTANCE_RATE_DAYS", "")
+	t.Setenv("ACCEPTANCE_RATE_MIN_AGE_DAYS", "")
+
+	config := GetAcceptanceGuardConfig()
+	if config.MinRate != 0 || config.MinSample != 20 || config.WindowDays != 14 || config.MinAgeDays != 3 {
+		t.Errorf("Unexpected defaults: %+v", config)
+	}
+
+	t.Setenv("MIN_ACCEPTANCE_RATE", "25")
+	t.Setenv("ACCEPTANCE_RATE_MIN_SAMPLE", "10")
+	t.Setenv("ACCEPTANCE_RATE_DAYS", "7")
+	t.Setenv("ACCEPTANCE_RATE_MIN_AGE_DAYS", "0")
+
+	config = GetAcceptanceGuardConfig()
+	if config.MinRate != 0.25 || config.MinSample != 10 || config.WindowDays != 7 || config.MinAgeDays != 0 {
+		t.Errorf("Unexpected config from env: %+v", config)
+	}
+
+	t.Setenv("MIN_ACCEPTANCE_RATE", "150")
+	if config := GetAcceptanceGuardConfig(); config.MinRate != 0 {
+		t.Errorf("Expected an out-of-range rate to leave the guard disabled, got %v", config.MinRate)
+	}
+}
@@ -0,0 +1,112 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/stealth"
+	"linkedin-automation/pkg/utils"
+)
+
+// identitySignals is what a logged-in page shows about its own account
+type identitySignals struct {
+	ProfileHrefs []string // hrefs of the own-profile links (Me menu, feed identity card)
+	Names        []string // Texts (or avatar alt texts) that may carry the account's name
+}
+
+// GetLoggedInIdentity returns the name and profile ID of the account the
+// session belongs to, read from the feed's identity card or, failing that,
+// the "Me" menu, which is opened and closed again.
+func GetLoggedInIdentity(page *rod.Page) (name, profileID string, err error) {
+	name, profileID, err = identityFromSignals(readIdentitySignals(page))
+	if err == nil {
+		return name, profileID, nil
+	}
+
+	// The Me menu only renders its "View Profile" link once opened
+	present, button, hasErr := page.Has(utils.Selectors.MeMenuButton)
+	if hasErr != nil || !present {
+		return "", "", err
+	}
+	if clickErr := stealth.SafeClick(page, button); clickErr != nil {
+		return "", "", fmt.Errorf("failed to open the Me menu: %w", clickErr)
+	}
+	stealth.RandomDelay(800, 1500)
+
+	name, profileID, err = identityFromSignals(readIdentitySignals(page))
+	if closeErr := page.Keyboard.Press(input.Escape); closeErr != nil {
+		logger.Debug("Failed to close the Me menu: " + closeErr.Error())
+	}
+	return name, profileID, err
+}
+
+// readIdentitySignals reads the identity signals from the current page without waiting
+func readIdentitySignals(page *rod.Page) identitySignals {
+	var signals identitySignals
+
+	if links, err := page.Elements(utils.Selectors.IdentityProfileLink); err == nil {
+		for _, link := range links {
+			if href, err := link.Attribute("href"); err == nil && href != nil {
+				signals.ProfileHrefs = append(signals.ProfileHrefs, *href)
+			}
+		}
+	}
+
+	if elements, err := page.Elements(utils.Selectors.IdentityName); err == nil {
+		for _, el := range elements {
+			text, _ := el.Text()
+			if strings.TrimSpace(text) == "" {
+				if alt, err := el.Attribute("alt"); err == nil && alt != nil {
+					text = *alt
+				}
+			}
+			signals.Names = append(signals.Names, text)
+		}
+	}
+
+	return signals
+}
+
+// identityFromSignals picks the profile ID from the first own-profile link and
+// the first non-empty name. The name is optional; the profile ID is not.
+func identityFromSignals(signals identitySignals) (name, profileID string, err error) {
+	for _, href := range signals.ProfileHrefs {
+		if profileID = utils.ExtractProfileID(href); profileID != "" {
+			break
+		}
+	}
+	if profileID == "" {
+		return "", "", errors.New("no link to the logged-in profile found")
+	}
+
+	for _, candidate := range signals.Names {
+		if candidate = strings.Join(strings.Fields(candidate), " "); candidate != "" {
+			name = candidate
+			break
+		}
+	}
+	return name, profileID, nil
+}
+
+// VerifyAccount returns an error wrapping ErrWrongAccount unless profileID is
+// the expected account. expected may be a profile ID or a profile URL and is
+// compared case-insensitively; an empty expected accepts any account.
+func VerifyAccount(expected, profileID string) error {
+	expected = strings.TrimSpace(expected)
+	if expected == "" {
+		return nil
+	}
+	if id := utils.ExtractProfileID(expected); id != "" {
+		expected = id
+	}
+
+	if !strings.EqualFold(expected, profileID) {
+		return fmt.Errorf("%w: the session belongs to %q, EXPECTED_ACCOUNT_ID is %q", ErrWrongAccount, profileID, expected)
+	}
+	return nil
+}
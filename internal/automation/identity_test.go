@@ -0,0 +1,80 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIdentityFromSignals(t *testing.T) {
+	tests := []struct {
+		name        string
+		signals     identitySignals
+		wantName    string
+		wantProfile string
+		wantErr     bool
+	}{
+		{
+			name: "feed identity card",
+			signals: identitySignals{
+				ProfileHrefs: []string{"/in/jane-doe-42/?miniProfileUrn=urn%3Ali%3Afs_miniProfile%3AACoAAA1"},
+				Names:        []string{"  Jane Doe\n"},
+			},
+			wantName: "Jane Doe", wantProfile: "jane-doe-42",
+		},
+		{
+			name: "name from the avatar after an empty element",
+			signals: identitySignals{
+				ProfileHrefs: []string{"https://www.linkedin.com/in/jane-doe-42/"},
+				Names:        []string{"", "Jane Doe"},
+			},
+			wantName: "Jane Doe", wantProfile: "jane-doe-42",
+		},
+		{
+			name: "first link without a profile ID is skipped",
+			signals: identitySignals{
+				ProfileHrefs: []string{"/in/", "/in/jane-doe-42/recent-activity/"},
+			},
+			wantName: "", wantProfile: "jane-doe-42",
+		},
+		{
+			name:    "no profile link",
+			signals: identitySignals{Names: []string{"Jane Doe"}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, profileID, err := identityFromSignals(test.signals)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Expected error=%v, got %v", test.wantErr, err)
+			}
+			if name != test.wantName || profileID != test.wantProfile {
+				t.Errorf("Expected %q (%s), got %q (%s)", test.wantName, test.wantProfile, name, profileID)
+			}
+		})
+	}
+}
+
+func TestVerifyAccount(t *testing.T) {
+	tests := []struct {
+		expected  string
+		profileID string
+		abort     bool
+	}{
+		{"", "anyone", false},
+		{"jane-doe-42", "jane-doe-42", false},
+		{"Jane-Doe-42", "jane-doe-42", false},
+		{"https://www.linkedin.com/in/jane-doe-42/", "jane-doe-42", false},
+		{" jane-doe-42 ", "jane-doe-42", false},
+		{"jane-doe-42", "john-roe", true},
+		{"https://www.linkedin.com/in/jane-doe-42/", "john-roe", true},
+	}
+
+	for _, test := range tests {
+		err := VerifyAccount(test.expected, test.profileID)
+		if errors.Is(err, ErrWrongAccount) != test.abort {
+			t.Errorf("VerifyAccount(%q, %q): expected abort=%v, got %v", test.expected, test.profileID, test.abort, err)
+		}
+	}
+}
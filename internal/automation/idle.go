@@ -0,0 +1,45 @@
+package automation
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/pkg/utils"
+)
+
+// GetIdleDayProbability reads IDLE_DAY_PROBABILITY, the fraction of runs (0-1)
+// that only browse the feed and send nothing (default 0 = never)
+func GetIdleDayProbability() float64 {
+	if v := os.Getenv("IDLE_DAY_PROBABILITY"); v != "" {
+		if val, err := strconv.ParseFloat(v, 64); err == nil && val >= 0 && val <= 1 {
+			return val
+		}
+		logger.Warning(fmt.Sprintf("Invalid IDLE_DAY_PROBABILITY %q (expected 0-1), ignoring", v))
+	}
+	return 0
+}
+
+// DecideIdleRun decides whether this run is an idle day - a light browse of
+// the feed with no searches, invitations or messages, like a day a person just
+// scrolls. Call it once per run. Uses the session random generator, so
+// STEALTH_SEED replays the decision.
+func DecideIdleRun(probability float64) bool {
+	idle := decideIdleRun(utils.SessionRand(), probability)
+	if idle {
+		logger.Info(fmt.Sprintf("Idle day (IDLE_DAY_PROBABILITY=%.2f) - browsing the feed only, sending nothing", probability))
+	} else if probability > 0 {
+		logger.Info(fmt.Sprintf("Active day (IDLE_DAY_PROBABILITY=%.2f)", probability))
+	}
+	return idle
+}
+
+// decideIdleRun returns true with the given probability
+func decideIdleRun(r *rand.Rand, probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+	return r.Float64() < probability
+}
@@ -0,0 +1,54 @@
+package automation
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDecideIdleRunProbability(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const runs = 10000
+
+	tests := []struct {
+		probability float64
+		min, max    int // Accepted number of idle runs
+	}{
+		{0, 0, 0},
+		{1, runs, runs},
+		{0.2, 1800, 2200},
+		{0.5, 4800, 5200},
+	}
+
+	for _, test := range tests {
+		idle := 0
+		for i := 0; i < runs; i++ {
+			if decideIdleRun(r, test.probability) {
+				idle++
+			}
+		}
+		if idle < test.min || idle > test.max {
+			t.Errorf("Probability %.1f: %d idle runs out of %d, expected %d-%d", test.probability, idle, runs, test.min, test.max)
+		}
+	}
+}
+
+func TestGetIdleDayProbability(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected float64
+	}{
+		{"", 0},
+		{"0.15", 0.15},
+		{"1", 1},
+		{"15", 0}, // A percentage is out of range
+		{"-0.1", 0},
+		{"often", 0},
+	}
+
+	for _, test := range tests {
+		t.Setenv("IDLE_DAY_PROBABILITY", test.value)
+		if got := GetIdleDayProbability(); got != test.expected {
+			t.Errorf("IDLE_DAY_PROBABILITY=%q: expected %v, got %v", test.value, test.expected, got)
+		}
+	}
+}
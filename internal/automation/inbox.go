@@ -2,6 +2,8 @@ package automation
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,12 +16,53 @@ import (
 	"linkedin-automation/pkg/utils"
 )
 
-// CheckInboxForReplies checks the inbox for new replies and updates the database
+// Default number of conversations scanned per inbox check
+const DefaultMaxInboxScan = 50
+
+// markUnreadPattern matches the "Mark as unread" item of a conversation's
+// options menu (a JavaScript RegExp for ElementR)
+const markUnreadPattern = `/mark as unread/i`
+
+// previewKind is what a conversation list preview tells about the last message
+type previewKind int
+
+const (
+	previewUnknown previewKind = iota // Empty or an attachment: the thread must be opened
+	previewOurs                       // "You: ..." - our message is the last one
+	previewTheirs                     // The other person wrote last
+)
+
+// readOnlyInboxScan reports whether READ_ONLY_INBOX_SCAN is enabled. In this mode
+// replies are read from the list previews and threads are only opened when the
+// preview can't settle it, so a human looking at the account doesn't find
+// messages mysteriously marked read.
+func readOnlyInboxScan() bool {
+	return os.Getenv("READ_ONLY_INBOX_SCAN") == "true"
+}
+
+// restoreUnreadState reports whether threads that had to be opened should be marked unread again
+func restoreUnreadState() bool {
+	return os.Getenv("RESTORE_UNREAD_STATE") == "true"
+}
+
+// GetMaxInboxScan returns how many conversations to scan, from MAX_INBOX_SCAN (default 50)
+func GetMaxInboxScan() int {
+	if envMax := os.Getenv("MAX_INBOX_SCAN"); envMax != "" {
+		if val, err := strconv.Atoi(envMax); err == nil && val > 0 {
+			return val
+		}
+	}
+	return DefaultMaxInboxScan
+}
+
+// CheckInboxForReplies checks the inbox for new replies and updates the database.
+// The conversation list is scrolled to load older threads until MAX_INBOX_SCAN
+// conversations have been checked or no more load.
 func CheckInboxForReplies(page *rod.Page, db *storage.Database) error {
 	logger.Info("Checking inbox for replies...")
 
 	// Navigate to messaging
-	err := page.Navigate("https://www.linkedin.com/messaging/")
+	err := navigate(page, db, "https://www.linkedin.com/messaging/", "")
 	if err != nil {
 		return fmt.Errorf("failed to navigate to messaging: %w", err)
 	}
@@ -27,77 +70,275 @@ func CheckInboxForReplies(page *rod.Page, db *storage.Database) error {
 	page.MustWaitLoad()
 	stealth.RandomDelay(2000, 3000)
 
-	// Get list of conversations
-	conversationSelector := ".msg-conversation-listitem"
-	conversations, err := page.Timeout(5 * time.Second).Elements(conversationSelector)
-	if err != nil {
+	// Make sure the conversation list rendered
+	if _, err := page.Timeout(5 * time.Second).Element(utils.Selectors.InboxConversation); err != nil {
 		logger.Warning("Failed to get conversations or inbox empty: " + err.Error())
 		return nil
 	}
 
-	logger.Info(fmt.Sprintf("Found %d conversations", len(conversations)))
+	maxScan := GetMaxInboxScan()
 
-	// Limit to top 10 to avoid excessive processing
-	limit := 10
-	if len(conversations) < limit {
-		limit = len(conversations)
+	listIDs := func() []string {
+		conversations, err := page.Elements(utils.Selectors.InboxConversation)
+		if err != nil {
+			return nil
+		}
+		ids := make([]string, len(conversations))
+		for i, conv := range conversations {
+			ids[i] = conversationKey(conv, i)
+		}
+		return ids
 	}
 
-	for i := 0; i < limit; i++ {
-		// Re-fetch conversations to avoid stale elements
-		conversations, _ = page.Elements(conversationSelector)
-		if i >= len(conversations) {
-			break
+	loadMore := func() {
+		// Scrolling the last item into view makes LinkedIn load older conversations
+		conversations, err := page.Elements(utils.Selectors.InboxConversation)
+		if err != nil || len(conversations) == 0 {
+			return
 		}
-		conv := conversations[i]
+		conversations[len(conversations)-1].ScrollIntoView()
+		stealth.RandomDelay(1500, 2500)
+	}
 
-		// Click to open conversation
-		conv.Click(proto.InputMouseButtonLeft, 1)
-		stealth.RandomDelay(1000, 1500)
+	readOnly := readOnlyInboxScan()
+	restoreUnread := restoreUnreadState()
+	opened, fromPreviews := 0, 0
 
-		// Identify the other person
-		headerLink, err := page.Timeout(3 * time.Second).Element(".msg-entity-lockup__link")
-		if err != nil {
-			continue
+	process := func(index int, _ string) {
+		// Re-fetch conversations to avoid stale elements
+		conversations, _ := page.Elements(utils.Selectors.InboxConversation)
+		if index >= len(conversations) {
+			return
 		}
+		conv := conversations[index]
 
-		href, err := headerLink.Attribute("href")
-		if err != nil || href == nil {
-			continue
+		unread := false
+		if readOnly {
+			var preview, name string
+			preview, name, unread = readConversationPreview(conv)
+			switch classifyPreview(preview) {
+			case previewOurs:
+				// Last message is ours - no reply to record, leave the thread untouched
+				return
+			case previewTheirs:
+				if recordReplyFromPreview(db, name) {
+					fromPreviews++
+					return
+				}
+			}
 		}
 
-		profileID := utils.ExtractProfileID(*href)
-		if profileID == "" {
-			continue
-		}
+		checkConversationForReply(page, db, conv)
+		opened++
 
-		// Check last message
-		bubbles, err := page.Elements(".msg-s-message-list__event")
-		if err != nil || len(bubbles) == 0 {
-			continue
+		if readOnly && restoreUnread && unread {
+			if err := markConversationUnread(page, conv); err != nil {
+				logger.Warning("Failed to restore unread state: " + err.Error())
+			}
 		}
+	}
+
+	scanned := scanConversations(maxScan, listIDs, loadMore, process)
+	logger.Info(fmt.Sprintf("Scanned %d conversations (max %d), opened %d, %d replies read from previews", scanned, maxScan, opened, fromPreviews))
+
+	return nil
+}
 
-		lastBubble := bubbles[len(bubbles)-1]
+// scanConversations walks the conversation list, calling process once per unseen
+// conversation, and calls loadMore when the visible list is exhausted.
+// It stops after maxScan conversations or when loading more yields nothing new.
+// Returns the number of conversations processed.
+func scanConversations(maxScan int, listIDs func() []string, loadMore func(), process func(index int, conversationID string)) int {
+	seen := make(map[string]bool)
+	processed := 0
 
-		// Check if it is from me
-		// LinkedIn uses classes like 'msg-s-message-list__event--s-me' for sent messages
-		// and 'msg-s-message-list__event--other' for received messages.
-		class, err := lastBubble.Attribute("class")
-		if err != nil || class == nil {
-			continue
+	for processed < maxScan {
+		progressed := false
+		for i, id := range listIDs() {
+			if processed >= maxScan {
+				break
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			process(i, id)
+			processed++
+			progressed = true
 		}
 
-		isFromMe := strings.Contains(*class, "--s-me")
+		if !progressed || processed >= maxScan {
+			break
+		}
+		loadMore()
+	}
 
-		if !isFromMe {
-			// It's a reply!
-			logger.Info(fmt.Sprintf("Detected reply from %s", profileID))
-			err = db.UpdateConnectionReplyStatus(profileID, true)
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to update reply status for %s: %s", profileID, err.Error()))
+	return processed
+}
+
+// conversationKey identifies a conversation list item by its thread ID,
+// falling back to its position when no thread link is present
+func conversationKey(conv *rod.Element, index int) string {
+	if link, err := conv.Element(utils.Selectors.InboxConversationLink); err == nil {
+		if href, err := link.Attribute("href"); err == nil && href != nil {
+			if id := parseConversationID(*href); id != "" {
+				return id
 			}
 		}
 	}
+	return fmt.Sprintf("position_%d", index)
+}
+
+// parseConversationID extracts the thread ID from a messaging link
+// e.g. /messaging/thread/2-abc123==/ -> 2-abc123==
+func parseConversationID(href string) string {
+	const marker = "/messaging/thread/"
+	idx := strings.Index(href, marker)
+	if idx == -1 {
+		return ""
+	}
+	id := href[idx+len(marker):]
+	if end := strings.IndexAny(id, "/?#"); end != -1 {
+		id = id[:end]
+	}
+	return id
+}
+
+// readConversationPreview reads the snippet text, the other participant's name
+// and the unread badge from a conversation list item without opening the thread
+func readConversationPreview(conv *rod.Element) (string, string, bool) {
+	preview := ""
+	if snippet, err := conv.Element(utils.Selectors.InboxConversationSnippet); err == nil {
+		preview, _ = snippet.Text()
+	}
+
+	name := ""
+	if names, err := conv.Element(utils.Selectors.InboxConversationName); err == nil {
+		name, _ = names.Text()
+	}
+
+	unread := false
+	if badges, err := conv.Elements(utils.Selectors.InboxConversationUnread); err == nil && len(badges) > 0 {
+		unread = true
+	}
+
+	return preview, strings.TrimSpace(name), unread
+}
+
+// classifyPreview reads who wrote last from a list preview. LinkedIn prefixes
+// the snippet with "You:" when the last message is ours; any other text is the
+// other person's. Empty previews (attachments) can't be classified.
+func classifyPreview(preview string) previewKind {
+	preview = strings.TrimSpace(preview)
+	if preview == "" {
+		return previewUnknown
+	}
+	if strings.HasPrefix(strings.ToLower(preview), "you:") {
+		return previewOurs
+	}
+	return previewTheirs
+}
+
+// recordReplyFromPreview records a reply from the contact named name, whose
+// message is the thread's preview. It only acts when the name matches exactly
+// one profile that was sent a request; otherwise it returns false and the
+// thread is opened to find out who wrote.
+func recordReplyFromPreview(db *storage.Database, name string) bool {
+	if name == "" {
+		return false
+	}
+
+	ids, err := db.GetRequestedProfileIDsByName(name)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Failed to look up %s: %s", name, err.Error()))
+		return false
+	}
+	if len(ids) != 1 {
+		return false
+	}
+
+	logger.Info(fmt.Sprintf("Detected reply from %s (inbox preview)", ids[0]))
+	if err := db.UpdateConnectionReplyStatus(ids[0], true); err != nil {
+		logger.Error(fmt.Sprintf("Failed to update reply status for %s: %s", ids[0], err.Error()))
+	}
+	return true
+}
+
+// markConversationUnread uses the conversation's options menu to mark it unread again
+func markConversationUnread(page *rod.Page, conv *rod.Element) error {
+	if err := conv.Hover(); err != nil {
+		return fmt.Errorf("failed to hover conversation: %w", err)
+	}
+	stealth.RandomDelay(300, 700)
+
+	optionsButton, err := conv.Element(utils.Selectors.InboxConversationOptions)
+	if err != nil {
+		return fmt.Errorf("conversation options button not found: %w", err)
+	}
+	if err := stealth.SafeClick(page, optionsButton); err != nil {
+		return fmt.Errorf("failed to open conversation options: %w", err)
+	}
+	stealth.RandomDelay(500, 1000)
+
+	markUnread, err := page.Timeout(3*time.Second).ElementR(utils.Selectors.InboxConversationMenuItem, markUnreadPattern)
+	if err != nil {
+		return fmt.Errorf("mark as unread option not found: %w", err)
+	}
+	if err := stealth.SafeClick(page, markUnread); err != nil {
+		return fmt.Errorf("failed to click mark as unread: %w", err)
+	}
+	stealth.RandomDelay(500, 1000)
 
 	return nil
 }
+
+// checkConversationForReply opens a conversation and records a reply if the
+// last message came from the other person
+func checkConversationForReply(page *rod.Page, db *storage.Database, conv *rod.Element) {
+	// Click to open conversation
+	conv.Click(proto.InputMouseButtonLeft, 1)
+	stealth.RandomDelay(1000, 1500)
+
+	// Identify the other person
+	headerLink, err := page.Timeout(3 * time.Second).Element(".msg-entity-lockup__link")
+	if err != nil {
+		return
+	}
+
+	href, err := headerLink.Attribute("href")
+	if err != nil || href == nil {
+		return
+	}
+
+	profileID := utils.ExtractProfileID(*href)
+	if profileID == "" {
+		return
+	}
+
+	// Check last message
+	bubbles, err := page.Elements(".msg-s-message-list__event")
+	if err != nil || len(bubbles) == 0 {
+		return
+	}
+
+	lastBubble := bubbles[len(bubbles)-1]
+
+	// Check if it is from me
+	// LinkedIn uses classes like 'msg-s-message-list__event--s-me' for sent messages
+	// and 'msg-s-message-list__event--other' for received messages.
+	class, err := lastBubble.Attribute("class")
+	if err != nil || class == nil {
+		return
+	}
+
+	isFromMe := strings.Contains(*class, "--s-me")
+
+	if !isFromMe {
+		// It's a reply!
+		logger.Info(fmt.Sprintf("Detected reply from %s", profileID))
+		err = db.UpdateConnectionReplyStatus(profileID, true)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to update reply status for %s: %s", profileID, err.Error()))
+		}
+	}
+}
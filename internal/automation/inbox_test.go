@@ -0,0 +1,235 @@
+package automation
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+// fakeInbox simulates a conversation list that grows by one batch per loadMore call
+type fakeInbox struct {
+	batches   [][]string
+	loaded    int
+	loadCalls int
+}
+
+func (f *fakeInbox) listIDs() []string {
+	var ids []string
+	for i := 0; i <= f.loaded && i < len(f.batches); i++ {
+		ids = append(ids, f.batches[i]...)
+	}
+	return ids
+}
+
+func (f *fakeInbox) loadMore() {
+	f.loadCalls++
+	if f.loaded < len(f.batches)-1 {
+		f.loaded++
+	}
+}
+
+func makeIDs(prefix string, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s-%d", prefix, i)
+	}
+	return ids
+}
+
+func TestScanConversations(t *testing.T) {
+	tests := []struct {
+		name          string
+		batches       [][]string
+		maxScan       int
+		wantProcessed int
+		wantLoadCalls int
+	}{
+		{"stops at max scan", [][]string{makeIDs("a", 10), makeIDs("b", 10), makeIDs("c", 10)}, 15, 15, 1},
+		{"stops when no more load", [][]string{makeIDs("a", 10), makeIDs("b", 5)}, 50, 15, 2},
+		{"single page smaller than max", [][]string{makeIDs("a", 3)}, 50, 3, 1},
+		{"empty inbox", [][]string{}, 50, 0, 0},
+		{"exact max on first page", [][]string{makeIDs("a", 10), makeIDs("b", 10)}, 10, 10, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inbox := &fakeInbox{batches: test.batches}
+			calls := 0
+			processed := scanConversations(test.maxScan, inbox.listIDs, inbox.loadMore, func(int, string) { calls++ })
+
+			if processed != test.wantProcessed || calls != test.wantProcessed {
+				t.Errorf("Expected %d processed, got %d (process called %d times)", test.wantProcessed, processed, calls)
+			}
+			if inbox.loadCalls != test.wantLoadCalls {
+				t.Errorf("Expected %d loadMore calls, got %d", test.wantLoadCalls, inbox.loadCalls)
+			}
+		})
+	}
+}
+
+func TestScanConversationsSkipsSeen(t *testing.T) {
+	// The second batch repeats conversations already at the top of the list
+	inbox := &fakeInbox{batches: [][]string{
+		{"t1", "t2", "t3"},
+		{"t2", "t4", "t1", "t5"},
+	}}
+
+	var processed []string
+	var indexes []int
+	scanConversations(50, inbox.listIDs, inbox.loadMore, func(index int, id string) {
+		processed = append(processed, id)
+		indexes = append(indexes, index)
+	})
+
+	expected := []string{"t1", "t2", "t3", "t4", "t5"}
+	if fmt.Sprint(processed) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, processed)
+	}
+	// Indexes refer to the position in the full list at processing time
+	if fmt.Sprint(indexes) != fmt.Sprint([]int{0, 1, 2, 4, 6}) {
+		t.Errorf("Unexpected indexes %v", indexes)
+	}
+}
+
+func TestParseConversationID(t *testing.T) {
+	tests := []struct {
+		href     string
+		expected string
+	}{
+		{"/messaging/thread/2-abc123==/", "2-abc123=="},
+		{"https://www.linkedin.com/messaging/thread/2-xyz/?trk=inbox", "2-xyz"},
+		{"/messaging/thread/2-noslash", "2-noslash"},
+		{"/in/john-doe/", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if result := parseConversationID(test.href); result != test.expected {
+			t.Errorf("parseConversationID(%q) = %q, expected %q", test.href, result, test.expected)
+		}
+	}
+}
+
+func TestGetMaxInboxScan(t *testing.T) {
+	t.Setenv("MAX_INBOX_SCAN", "")
+	if result := GetMaxInboxScan(); result != DefaultMaxInboxScan {
+		t.Errorf("Expected default %d, got %d", DefaultMaxInboxScan, result)
+	}
+
+	t.Setenv("MAX_INBOX_SCAN", "120")
+	if result := GetMaxInboxScan(); result != 120 {
+		t.Errorf("Expected 120, got %d", result)
+	}
+
+	t.Setenv("MAX_INBOX_SCAN", "0")
+	if result := GetMaxInboxScan(); result != DefaultMaxInboxScan {
+		t.Errorf("Expected default for invalid value, got %d", result)
+	}
+}
+
+func TestClassifyPreview(t *testing.T) {
+	tests := []struct {
+		name     string
+		preview  string
+		expected previewKind
+	}{
+		{"our message last", "You: Thanks for connecting, Jane!", previewOurs},
+		{"our message last lowercase", "you: following up", previewOurs},
+		{"our message with padding", "  You: hello", previewOurs},
+		{"their reply", "Jane: Happy to chat next week", previewTheirs},
+		{"their reply without name", "Sounds great, let's talk", previewTheirs},
+		{"empty preview", "", previewUnknown},
+		{"attachment only", "   ", previewUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := classifyPreview(test.preview); result != test.expected {
+				t.Errorf("classifyPreview(%q) = %v, expected %v", test.preview, result, test.expected)
+			}
+		})
+	}
+}
+
+func TestReadOnlyInboxScanSkipsOwnMessages(t *testing.T) {
+	// Scan a list where only the preview decides whether the thread is opened
+	previews := map[string]string{
+		"t1": "You: Thanks for connecting!",
+		"t2": "Jane: Happy to chat",
+		"t3": "You: Just following up",
+		"t4": "",
+	}
+	inbox := &fakeInbox{batches: [][]string{{"t1", "t2", "t3", "t4"}}}
+
+	var checked []string
+	scanConversations(50, inbox.listIDs, inbox.loadMore, func(_ int, id string) {
+		if classifyPreview(previews[id]) != previewOurs {
+			checked = append(checked, id)
+		}
+	})
+
+	if fmt.Sprint(checked) != fmt.Sprint([]string{"t2", "t4"}) {
+		t.Errorf("Expected only t2 and t4 to be checked, got %v", checked)
+	}
+}
+
+func TestRecordReplyFromPreview(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_inbox.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	for _, p := range []storage.Profile{
+		{ID: "jane-doe", Name: "Jane Doe"},
+		{ID: "john-roe", Name: "John Roe"},
+		{ID: "john-roe-2", Name: "John Roe"},
+	} {
+		p.ProfileURL = "https://www.linkedin.com/in/" + p.ID
+		p.VisitedAt, p.CreatedAt = time.Now(), time.Now()
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+		if err := db.SaveConnectionRequest(storage.ConnectionRequest{ProfileID: p.ID, SentAt: time.Now(), Status: "accepted", CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+
+	if !recordReplyFromPreview(db, "Jane Doe") {
+		t.Error("Expected a reply recorded for a unique name")
+	}
+	// Ambiguous, unknown and group names need the thread opened
+	for _, name := range []string{"John Roe", "Sam Poe", "Jane Doe, Sam Poe", ""} {
+		if recordReplyFromPreview(db, name) {
+			t.Errorf("Expected no reply recorded for %q", name)
+		}
+	}
+
+	// Replied contacts drop out of the follow-up list
+	awaiting, err := db.GetAcceptedConnectionProfiles(10, 30, 0)
+	if err != nil {
+		t.Fatalf("Failed to read accepted connections: %v", err)
+	}
+	for _, p := range awaiting {
+		if p.ID == "jane-doe" {
+			t.Error("Expected jane-doe marked replied")
+		}
+	}
+	if len(awaiting) != 2 {
+		t.Errorf("Expected the two John Roes still awaiting a reply, got %d", len(awaiting))
+	}
+}
+
+func TestReadOnlyInboxScanEnv(t *testing.T) {
+	t.Setenv("READ_ONLY_INBOX_SCAN", "true")
+	t.Setenv("RESTORE_UNREAD_STATE", "")
+	if !readOnlyInboxScan() {
+		t.Error("Expected read-only scan to be enabled")
+	}
+	if restoreUnreadState() {
+		t.Error("Expected unread restore to be disabled by default")
+	}
+}
@@ -0,0 +1,75 @@
+package automation
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
+)
+
+// invitationSentPattern matches the "Invitation sent" pill
+var invitationSentPattern = regexp.MustCompile(utils.InvitationSentTextPattern)
+
+// connectControlSignals is what a profile shows in place of an active Connect button
+type connectControlSignals struct {
+	ConnectDisabled bool     // A Connect button is present but disabled
+	IndicatorTexts  []string // Texts of the elements that may carry the "Invitation sent" pill
+}
+
+// readConnectControl reads the connect control signals from a loaded profile page.
+// Neither check waits: the profile has loaded by the time this runs.
+func readConnectControl(page *rod.Page) connectControlSignals {
+	var signals connectControlSignals
+
+	if disabled, _, err := page.Has(utils.Selectors.ConnectButtonDisabled); err == nil {
+		signals.ConnectDisabled = disabled
+	}
+
+	if indicators, err := page.Elements(utils.Selectors.InvitationSentIndicator); err == nil {
+		for _, indicator := range indicators {
+			if text, err := indicator.Text(); err == nil {
+				signals.IndicatorTexts = append(signals.IndicatorTexts, text)
+			}
+		}
+	}
+
+	return signals
+}
+
+// classifyConnectControl returns ErrInvitationSent or ErrConnectDisabled when the
+// signals show an invitation already out, or nil when Connect should work. The
+// pill is checked first as the more specific of the two.
+func classifyConnectControl(signals connectControlSignals) error {
+	for _, text := range signals.IndicatorTexts {
+		if invitationSentPattern.MatchString(text) {
+			return ErrInvitationSent
+		}
+	}
+	if signals.ConnectDisabled {
+		return ErrConnectDisabled
+	}
+	return nil
+}
+
+// recordPendingInvitation makes the database agree with a profile showing a
+// pending invitation, adding a pending request if none is recorded (e.g. the
+// invitation was sent by hand or the request was marked withdrawn)
+func recordPendingInvitation(db *storage.Database, request ConnectionRequest) {
+	if db == nil {
+		return
+	}
+
+	added, err := db.RecordPendingInvitation(request.ProfileID, time.Now())
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Failed to record pending invitation for %s: %s", request.Name, err.Error()))
+		return
+	}
+	if added {
+		logger.Info(fmt.Sprintf("Recorded the pending invitation to %s found on the profile", request.Name))
+	}
+}
@@ -0,0 +1,34 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyConnectControl(t *testing.T) {
+	tests := []struct {
+		name    string
+		signals connectControlSignals
+		want    error
+	}{
+		{"Active connect", connectControlSignals{IndicatorTexts: []string{"Message", "More"}}, nil},
+		{"Nothing on the page", connectControlSignals{}, nil},
+		{"Disabled connect", connectControlSignals{ConnectDisabled: true}, ErrConnectDisabled},
+		{"Invitation sent pill", connectControlSignals{IndicatorTexts: []string{"Follow", "Invitation sent"}}, ErrInvitationSent},
+		{"Pill text in other case", connectControlSignals{IndicatorTexts: []string{"INVITATION  SENT ✓"}}, ErrInvitationSent},
+		{"Pill wins over disabled connect", connectControlSignals{ConnectDisabled: true, IndicatorTexts: []string{"Invitation sent"}}, ErrInvitationSent},
+		{"Other invitation text", connectControlSignals{IndicatorTexts: []string{"Send invitation"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyConnectControl(tt.signals)
+			if !errors.Is(got, tt.want) || (tt.want == nil && got != nil) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			if tt.want != nil && classifyConnectError(got) != outcomePending {
+				t.Errorf("Expected %v to be classified as pending", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package automation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/stealth"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
+)
+
+// PendingInvitation is a pending connection request and when it was sent
+type PendingInvitation struct {
+	ProfileID    string
+	SentAt       time.Time
+	FromLinkedIn bool // SentAt comes from the Sent invitations page rather than connection_requests.sent_at
+}
+
+// Age returns how long the invitation has been pending at now
+func (p PendingInvitation) Age(now time.Time) time.Duration {
+	return now.Sub(p.SentAt)
+}
+
+// PendingInvitationAges returns the pending requests in the database, each
+// dated by LinkedIn's own "Sent X ago" label when the Sent invitations page
+// shows it. sent_at can drift from LinkedIn's record (a request retried, or
+// one recorded after the fact), so withdrawing stale invites goes by this.
+// If the page can't be read, the database dates are used.
+func PendingInvitationAges(page *rod.Page, db *storage.Database) ([]PendingInvitation, error) {
+	pending, err := db.GetPendingConnections()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending connections: %w", err)
+	}
+
+	sentTimes, err := ScrapeSentInvitationTimes(page, db)
+	if err != nil {
+		logger.Warning("Failed to read sent invitation dates, using the database's: " + err.Error())
+	}
+	return mergePendingSince(pending, sentTimes), nil
+}
+
+// maxSentInvitationPages caps the Sent invitations pages read for their dates
+const maxSentInvitationPages = 20
+
+// sentInvitationCard is one card of the Sent invitations list (SentAt is zero
+// when the card has no readable "Sent X ago" label)
+type sentInvitationCard struct {
+	ProfileID string
+	SentAt    time.Time
+}
+
+// ScrapeSentInvitationTimes reads when each invitation on the Sent
+// invitations list was sent, from its relative time label, by profile ID.
+// The list is newest first, so it pages through (?page=N) until a page shows
+// no new invitations - the stale ones are at the end. Cards whose label
+// can't be parsed are left out.
+func ScrapeSentInvitationTimes(page *rod.Page, db *storage.Database) (map[string]time.Time, error) {
+	now := time.Now()
+	return pageSentInvitationTimes(maxSentInvitationPages, func(pageNum int) ([]sentInvitationCard, error) {
+		return readSentInvitationPage(page, db, pageNum, now)
+	})
+}
+
+// pageSentInvitationTimes reads pages 1..maxPages with readPage, collecting
+// the sent time of each profile, and stops at the first page with no profile
+// it hasn't seen. A failure on the first page is an error; on a later page
+// the dates read so far are kept.
+func pageSentInvitationTimes(maxPages int, readPage func(pageNum int) ([]sentInvitationCard, error)) (map[string]time.Time, error) {
+	sentTimes := make(map[string]time.Time)
+	seen := make(map[string]bool)
+
+	for pageNum := 1; pageNum <= maxPages; pageNum++ {
+		cards, err := readPage(pageNum)
+		if err != nil {
+			if pageNum == 1 {
+				return nil, err
+			}
+			logger.Warning(fmt.Sprintf("Stopped reading sent invitations at page %d: %s", pageNum, err.Error()))
+			break
+		}
+
+		added := 0
+		for _, card := range cards {
+			if seen[card.ProfileID] {
+				continue
+			}
+			seen[card.ProfileID] = true
+			added++
+
+			if !card.SentAt.IsZero() {
+				sentTimes[card.ProfileID] = card.SentAt
+			} else {
+				logger.Debug("No sent time label on the invitation card for " + card.ProfileID)
+			}
+		}
+		if added == 0 {
+			break
+		}
+	}
+
+	logger.Info(fmt.Sprintf("Read sent dates of %d of %d invitations", len(sentTimes), len(seen)))
+	return sentTimes, nil
+}
+
+// readSentInvitationPage opens page pageNum of the Sent invitations list and
+// reads its cards. Only an empty first page is an error (likely a stale
+// selector); an empty later page is the end of the list.
+func readSentInvitationPage(page *rod.Page, db *storage.Database, pageNum int, now time.Time) ([]sentInvitationCard, error) {
+	if err := openSentInvitationsPage(page, db, pageNum); err != nil {
+		return nil, err
+	}
+	// Cards below the first screenful load as they scroll into view
+	if err := stealth.ScrollThroughContent(page, 5); err != nil {
+		logger.Warning("Failed to scroll the sent invitations: " + err.Error())
+	}
+
+	elements, err := page.Elements(utils.Selectors.SentInvitationCard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sent invitations: %w", err)
+	}
+	if len(elements) == 0 && pageNum == 1 {
+		return nil, fmt.Errorf("no sent invitations found (check sent_invitation_card selector)")
+	}
+
+	var cards []sentInvitationCard
+	for _, element := range elements {
+		profileID := sentInvitationProfileID(element)
+		if profileID == "" {
+			continue
+		}
+
+		card := sentInvitationCard{ProfileID: profileID}
+		if text, err := element.Text(); err == nil {
+			card.SentAt = sentLabelTime(text, now)
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// sentInvitationsPageURL returns the URL of page pageNum of the Sent invitations list
+func sentInvitationsPageURL(pageNum int) string {
+	if pageNum <= 1 {
+		return sentInvitationURL
+	}
+	return fmt.Sprintf("%s?page=%d", sentInvitationURL, pageNum)
+}
+
+// sentInvitationProfileID returns the invitee's profile ID from a Sent
+// invitation card ("" if the card has no profile link)
+func sentInvitationProfileID(card *rod.Element) string {
+	link, err := card.Element("a[href*='/in/']")
+	if err != nil {
+		return ""
+	}
+	href, err := link.Attribute("href")
+	if err != nil || href == nil {
+		return ""
+	}
+	return utils.ExtractProfileID(*href)
+}
+
+// sentLabelTime finds the relative time label ("Sent 2 weeks ago") among the
+// lines of a sent invitation card and returns when it was sent (zero if none)
+func sentLabelTime(cardText string, now time.Time) time.Time {
+	for _, line := range strings.Split(cardText, "\n") {
+		if sentAt := utils.ParseRelativeTime(line, now); !sentAt.IsZero() {
+			return sentAt
+		}
+	}
+	return time.Time{}
+}
+
+// mergePendingSince dates each pending request by LinkedIn's sent time when
+// there is one, falling back to the database's sent_at
+func mergePendingSince(pending []storage.ConnectionRequest, linkedInSent map[string]time.Time) []PendingInvitation {
+	invitations := make([]PendingInvitation, 0, len(pending))
+	for _, req := range pending {
+		invitation := PendingInvitation{ProfileID: req.ProfileID, SentAt: req.SentAt}
+		if sentAt, ok := linkedInSent[req.ProfileID]; ok {
+			invitation.SentAt = sentAt
+			invitation.FromLinkedIn = true
+		}
+		invitations = append(invitations, invitation)
+	}
+	return invitations
+}
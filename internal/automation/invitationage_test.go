@@ -0,0 +1,118 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+func TestSentLabelTime(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	card := "Jane Doe\nSenior Engineer at Acme\n2 mutual connections\nSent 3 weeks ago\nWithdraw"
+	if got := sentLabelTime(card, now); !got.Equal(now.AddDate(0, 0, -21)) {
+		t.Errorf("Expected three weeks before now, got %s", got)
+	}
+
+	if got := sentLabelTime("Jane Doe\nSenior Engineer at Acme\nWithdraw", now); !got.IsZero() {
+		t.Errorf("Expected no time for a card without a label, got %s", got)
+	}
+}
+
+func TestMergePendingSincePrefersLinkedIn(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	pending := []storage.ConnectionRequest{
+		{ProfileID: "jane-doe", SentAt: now.AddDate(0, 0, -2)},
+		{ProfileID: "john-roe", SentAt: now.AddDate(0, 0, -10)},
+	}
+	linkedIn := map[string]time.Time{"jane-doe": now.AddDate(0, 0, -28)}
+
+	invitations := mergePendingSince(pending, linkedIn)
+	if len(invitations) != 2 {
+		t.Fatalf("Expected 2 invitations, got %d", len(invitations))
+	}
+
+	jane, john := invitations[0], invitations[1]
+	if !jane.FromLinkedIn || jane.Age(now) != 28*24*time.Hour {
+		t.Errorf("Expected LinkedIn's date for jane-doe, got %+v", jane)
+	}
+	if john.FromLinkedIn || john.Age(now) != 10*24*time.Hour {
+		t.Errorf("Expected the database date for john-roe, got %+v", john)
+	}
+
+	// Without the page every date comes from the database
+	for _, invitation := range mergePendingSince(pending, nil) {
+		if invitation.FromLinkedIn {
+			t.Errorf("Expected database dates without the page, got %+v", invitation)
+		}
+	}
+}
+
+func TestPageSentInvitationTimesReadsToTheEnd(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	pages := map[int][]sentInvitationCard{
+		1: {{ProfileID: "newest", SentAt: now.AddDate(0, 0, -1)}, {ProfileID: "unlabelled"}},
+		2: {{ProfileID: "month", SentAt: now.AddDate(0, 0, -30)}},
+		3: {{ProfileID: "oldest", SentAt: now.AddDate(0, 0, -90)}},
+		4: {{ProfileID: "oldest", SentAt: now.AddDate(0, 0, -90)}}, // Past the end the last page repeats
+	}
+
+	var read []int
+	sentTimes, err := pageSentInvitationTimes(10, func(pageNum int) ([]sentInvitationCard, error) {
+		read = append(read, pageNum)
+		return pages[pageNum], nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the pages to be read, got %v", err)
+	}
+
+	if len(read) != 4 {
+		t.Errorf("Expected to stop at the first page without new invitations (4), read %v", read)
+	}
+	if len(sentTimes) != 3 || !sentTimes["oldest"].Equal(now.AddDate(0, 0, -90)) {
+		t.Errorf("Expected the oldest invitation past the first page to be dated, got %v", sentTimes)
+	}
+	if _, ok := sentTimes["unlabelled"]; ok {
+		t.Error("Expected a card without a label to be left out")
+	}
+}
+
+func TestPageSentInvitationTimesErrors(t *testing.T) {
+	if _, err := pageSentInvitationTimes(10, func(int) ([]sentInvitationCard, error) {
+		return nil, errors.New("no sent invitations found")
+	}); err == nil {
+		t.Error("Expected a failure on the first page to be an error")
+	}
+
+	sentTimes, err := pageSentInvitationTimes(10, func(pageNum int) ([]sentInvitationCard, error) {
+		if pageNum == 2 {
+			return nil, errors.New("navigation timed out")
+		}
+		return []sentInvitationCard{{ProfileID: "jane-doe", SentAt: time.Now()}}, nil
+	})
+	if err != nil || len(sentTimes) != 1 {
+		t.Errorf("Expected the first page's dates to be kept, got %v (%v)", sentTimes, err)
+	}
+
+	// The page cap holds even if every page has new invitations
+	pages := 0
+	pageSentInvitationTimes(3, func(pageNum int) ([]sentInvitationCard, error) {
+		pages++
+		return []sentInvitationCard{{ProfileID: fmt.Sprintf("p%d", pageNum)}}, nil
+	})
+	if pages != 3 {
+		t.Errorf("Expected 3 pages read under the cap, read %d", pages)
+	}
+}
+
+func TestSentInvitationsPageURL(t *testing.T) {
+	if got := sentInvitationsPageURL(1); got != sentInvitationURL {
+		t.Errorf("Expected the plain list for page 1, got %s", got)
+	}
+	if got := sentInvitationsPageURL(3); got != sentInvitationURL+"?page=3" {
+		t.Errorf("Unexpected page 3 URL: %s", got)
+	}
+}
@@ -9,145 +9,253 @@ package automation
 */
 import (
 	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 
+	"linkedin-automation/internal/browser"
 	"linkedin-automation/internal/logger"
 	"linkedin-automation/internal/stealth"
+	"linkedin-automation/pkg/utils"
 )
 
 /*
 LoginLinkedln - logs into linkedin 	with given credentials
 page - rod page to perform actions on (currently opened linkedin login page)
-returns errors if any issue occurs during linkedin login
+returns errors if any issue occurs during linkedin login, ErrChallengeRequired
+if linkedin asks for a verification code (see GetChallengeWait) and
+ErrPasskeyRequired if it asks for a passkey (see SKIP_PASSKEY)
 */
 func LoginLinkedln(page *rod.Page, email string, password string) error {
+	return loginWithForm(rodLoginPage{page: page}, email, password, stealth.RandomDelay, loginOptionsFromEnv())
+}
+
+// loginOptions decides what the login flow does at the prompts it can't fill in
+type loginOptions struct {
+	Challenge   ChallengeWait // Verification code challenge
+	SkipPasskey bool          // Skip a passkey prompt for the password (SKIP_PASSKEY=true)
+	Delay       LoginDelay    // Pauses between filling in the form fields
+}
+
+// loginOptionsFromEnv reads the login options from WAIT_FOR_MANUAL,
+// MANUAL_CHALLENGE_TIMEOUT_MINUTES, SKIP_PASSKEY and LOGIN_DELAY_MIN_MS/MAX_MS
+func loginOptionsFromEnv() loginOptions {
+	return loginOptions{
+		Challenge:   GetChallengeWait(),
+		SkipPasskey: os.Getenv("SKIP_PASSKEY") == "true",
+		Delay:       GetLoginDelay(),
+	}
+}
+
+// LoginDelay bounds the random pause before each login form field, in milliseconds
+type LoginDelay struct {
+	MinMs int
+	MaxMs int
+}
+
+// GetLoginDelay reads LOGIN_DELAY_MIN_MS and LOGIN_DELAY_MAX_MS (default
+// utils.MinLoginDelay to utils.MaxLoginDelay). A maximum below the minimum is
+// raised to it.
+func GetLoginDelay() LoginDelay {
+	delay := LoginDelay{MinMs: utils.MinLoginDelay, MaxMs: utils.MaxLoginDelay}
+
+	if v := os.Getenv("LOGIN_DELAY_MIN_MS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			delay.MinMs = val
+		}
+	}
+	if v := os.Getenv("LOGIN_DELAY_MAX_MS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			delay.MaxMs = val
+		}
+	}
+	if delay.MaxMs < delay.MinMs {
+		delay.MaxMs = delay.MinMs
+	}
+
+	return delay
+}
+
+// signIn returns the bounds of the pause before clicking Sign in: longer and
+// more variable than the field pauses, like someone double-checking what they typed
+func (d LoginDelay) signIn() LoginDelay {
+	return LoginDelay{MinMs: d.MinMs * 3 / 2, MaxMs: d.MaxMs * 3}
+}
+
+// loginPage is the part of a browser page the login flow interacts with.
+// Every step returns an error instead of panicking so a transient failure
+// ends the login cleanly rather than crashing the process.
+type loginPage interface {
+	Navigate(url string) error
+	WaitLoad() error
+	DismissCookieBanner()
+	Field(selector string) (loginField, error)
+	Has(selector string) bool
+	URL() (string, error)
+}
+
+// loginField is a form element on the login page
+type loginField interface {
+	Input(text string) error
+	TypeLikeHuman(text string) error
+	Click() error
+}
+
+// rodLoginPage adapts a rod page to loginPage
+type rodLoginPage struct {
+	page *rod.Page
+}
+
+func (p rodLoginPage) Navigate(url string) error {
+	browser.EnforceNavGap()
+	return p.page.Navigate(url)
+}
+
+func (p rodLoginPage) WaitLoad() error { return p.page.WaitLoad() }
+
+func (p rodLoginPage) DismissCookieBanner() {
+	if _, err := DismissCookieBanner(p.page); err != nil {
+		logger.Warning("Cookie banner not dismissed: " + err.Error())
+	}
+}
+
+func (p rodLoginPage) Field(selector string) (loginField, error) {
+	el, err := p.page.Timeout(10 * time.Second).Element(selector)
+	if err != nil {
+		return nil, err
+	}
+	return rodLoginField{el: el}, nil
+}
+
+func (p rodLoginPage) Has(selector string) bool {
+	has, _, err := p.page.Has(selector)
+	return err == nil && has
+}
+
+func (p rodLoginPage) URL() (string, error) {
+	info, err := p.page.Info()
+	if err != nil {
+		return "", err
+	}
+	return info.URL, nil
+}
+
+// rodLoginField adapts a rod element to loginField
+type rodLoginField struct {
+	el *rod.Element
+}
+
+func (f rodLoginField) Input(text string) error { return f.el.Input(text) }
+
+func (f rodLoginField) TypeLikeHuman(text string) error { return stealth.TypeLikeHuman(f.el, text) }
+
+func (f rodLoginField) Click() error { return f.el.Click(proto.InputMouseButtonLeft, 1) }
+
+// loginWithForm runs the login flow against page. pause is called between
+// steps to mimic human timing (stealth.RandomDelay in production); opts
+// decides what happens at a passkey prompt or verification challenge.
+func loginWithForm(page loginPage, email string, password string, pause func(minMs, maxMs int), opts loginOptions) error {
 
 	//navigate to linkedin login page and wait until the page is fully loaded
 	logger.Info("Opening Linkedin Login page")
-	page.MustNavigate("https://www.linkedin.com/login")
-	page.MustWaitLoad()
+	if err := page.Navigate("https://www.linkedin.com/login"); err != nil {
+		return fmt.Errorf("failed to open login page: %w", err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("login page did not load: %w", err)
+	}
+
+	// Fresh profiles get a cookie-consent banner that would intercept the form clicks
+	page.DismissCookieBanner()
 
 	//Human like delay between actions
-	stealth.RandomDelay(1500, 3000)
+	pause(1500, 3000)
 
 	//Locate email iput field  and tell user if the email id input field is empty
 	logger.Info("Locating email input field")
-	emailInput, err := page.Timeout(10 * time.Second).Element(`input#username`)
+	emailInput, err := page.Field(`input#username`)
 	if err != nil {
 		return errors.New("email input not found")
 	}
 
 	//pause for random time to mimic human behaviour and type email id like a human
-	stealth.RandomDelay(800, 1500)
-	emailInput.MustInput(email)
+	pause(opts.Delay.MinMs, opts.Delay.MaxMs)
+	if err := emailInput.Input(email); err != nil {
+		return fmt.Errorf("failed to enter email: %w", err)
+	}
 
 	//Locate password input field  and tell user if the password input field is empty
 	logger.Info("Locating password input field")
-	passwordInput, err := page.Timeout(10 * time.Second).Element(`input#password`)
+	passwordInput, err := page.Field(`input#password`)
 	if err != nil {
 		return errors.New("password input not found")
 	}
 
 	//pause for random time to mimic human behaviour and type password like a human
-	stealth.RandomDelay(800, 1500)
-	stealth.TypeLikeHuman(passwordInput, password)
+	pause(opts.Delay.MinMs, opts.Delay.MaxMs)
+	if err := passwordInput.TypeLikeHuman(password); err != nil {
+		return fmt.Errorf("failed to enter password: %w", err)
+	}
 
 	//Locate and click on the Sign in button to submit the credentials
 	logger.Info("Locating and clicking on sign in button")
-	loginBtn, err := page.Timeout(10 * time.Second).Element(`button[type="submit"]`)
+	loginBtn, err := page.Field(`button[type="submit"]`)
 	if err != nil {
 		return errors.New("Login Button not found")
 	}
 
-	//puase for random time to show human behaviour and  click on the login button
-	stealth.RandomDelay(1000, 2000)
-	loginBtn.MustClick()
+	//pause a little longer, as if double-checking the credentials, then click on the login button
+	signIn := opts.Delay.signIn()
+	pause(signIn.MinMs, signIn.MaxMs)
+	if err := loginBtn.Click(); err != nil {
+		return fmt.Errorf("failed to click sign in: %w", err)
+	}
 
 	//Wait for linkedln page to load after the login btn is clicked
-	stealth.RandomDelay(3000, 5000)
-	page.MustWaitLoad()
+	pause(3000, 5000)
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("page did not load after sign in: %w", err)
+	}
+
+	// "Verify it's you" asks for a passkey we can't provide
+	if page.Has(utils.Selectors.PasskeyPrompt) {
+		if err := skipPasskeyPrompt(page, opts.SkipPasskey, pause); err != nil {
+			return err
+		}
+	}
 
 	// Check current URL first to see if login succeeded immediately
 	logger.Info("Checking login status...")
-	stealth.RandomDelay(2000, 3000)
-	currentURL := page.MustInfo().URL
+	pause(2000, 3000)
+	currentURL, err := page.URL()
+	if err != nil {
+		return fmt.Errorf("failed to read page URL after sign in: %w", err)
+	}
 	logger.Info("Current page URL: " + currentURL)
 
+	// A verification code page sits between the form and the feed
+	if isLoginChallenge(currentURL, page.Has(utils.Selectors.LoginChallengePinInput)) {
+		return waitForChallenge(page, opts.Challenge)
+	}
+
 	// If already on feed/home page, login succeeded without 2FA
 	if currentURL != "https://www.linkedin.com/login" &&
-		(len(currentURL) >= 28 && currentURL[:28] == "https://www.linkedin.com/feed" ||
-			len(currentURL) >= 29 && currentURL[:29] == "https://www.linkedin.com/check") {
+		(strings.HasPrefix(currentURL, "https://www.linkedin.com/feed") ||
+			strings.HasPrefix(currentURL, "https://www.linkedin.com/check")) {
 		logger.Info("✓ Login successful!")
 		return nil
 	}
 
-	// Still on login page - check for challenges with timeout
-	logger.Info("Checking for 2FA or CAPTCHA challenges...")
-
-	// Check for 2FA challenge (with timeout)
-	// twoFAChallenge, _ := page.Timeout(2 * time.Second).Element("#challenge")
-	// if twoFAChallenge != nil {
-	// 	logger.Warning("⚠️  2FA challenge detected! Manual intervention required.")
-	// 	logger.Info("Please complete 2FA verification manually in the browser.")
-	// 	logger.Info("Waiting up to 5 minutes for completion...")
-
-	// 	// Wait up to 5 minutes, checking every 10 seconds
-	// 	for i := 0; i < 30; i++ {
-	// 		stealth.RandomDelay(10000, 10500)
-	// 		currentURL = page.MustInfo().URL
-	// 		if currentURL != "https://www.linkedin.com/login" {
-	// 			logger.Info("✓ 2FA completed successfully!")
-	// 			return nil
-	// 		}
-	// 		logger.Info(fmt.Sprintf("Still waiting... (%d/30 checks)", i+1))
-	// 	}
-	// 	return errors.New("2FA timeout - please try again")
-	// }
-
-	// Check for CAPTCHA (with timeout)
-	// captchaChallenge, _ := page.Timeout(2 * time.Second).Element(".g-recaptcha")
-	// if captchaChallenge != nil {
-	// 	logger.Warning("⚠️  CAPTCHA challenge detected! Manual intervention required.")
-	// 	logger.Info("Please complete CAPTCHA verification manually in the browser.")
-	// 	logger.Info("Waiting up to 5 minutes for completion...")
-
-	// 	// Wait up to 5 minutes, checking every 10 seconds
-	// 	for i := 0; i < 30; i++ {
-	// 		stealth.RandomDelay(10000, 10500)
-	// 		currentURL = page.MustInfo().URL
-	// 		if currentURL != "https://www.linkedin.com/login" {
-	// 			logger.Info("✓ CAPTCHA completed successfully!")
-	// 			return nil
-	// 		}
-	// 		logger.Info(fmt.Sprintf("Still waiting... (%d/30 checks)", i+1))
-	// 	}
-	// 	return errors.New("CAPTCHA timeout - please try again")
-	// }
-
-	// Check for security verification (with timeout)
-	// securityChallenge, _ := page.Timeout(2 * time.Second).Element("form[action*='checkpoint']")
-	// if securityChallenge != nil {
-	// 	logger.Warning("⚠️  Security verification detected! Manual intervention required.")
-	// 	logger.Info("Please complete security verification manually in the browser.")
-	// 	logger.Info("Waiting up to 5 minutes for completion...")
-
-	// 	// Wait up to 5 minutes, checking every 10 seconds
-	// 	for i := 0; i < 30; i++ {
-	// 		stealth.RandomDelay(10000, 10500)
-	// 		currentURL = page.MustInfo().URL
-	// 		if currentURL != "https://www.linkedin.com/login" {
-	// 			logger.Info("✓ Security verification completed successfully!")
-	// 			return nil
-	// 		}
-	// 		logger.Info(fmt.Sprintf("Still waiting... (%d/30 checks)", i+1))
-	// 	}
-	// 	return errors.New("Security verification timeout - please try again")
-	// }
-
 	// Final check - are we logged in now?
-	currentURL = page.MustInfo().URL
+	currentURL, err = page.URL()
+	if err != nil {
+		return fmt.Errorf("failed to read page URL: %w", err)
+	}
 	logger.Info("Final URL check: " + currentURL)
 
 	// LinkedIn home page URL should contain "/feed" or similar indicators
@@ -158,3 +266,131 @@ func LoginLinkedln(page *rod.Page, email string, password string) error {
 
 	return errors.New("login failed - still on login page")
 }
+
+// decidePasskey returns whether to click the passkey prompt's skip option, or
+// ErrPasskeyRequired when skipping is off or the prompt offers no way around it
+func decidePasskey(skipEnabled, skipOffered bool) (bool, error) {
+	switch {
+	case !skipEnabled:
+		return false, fmt.Errorf("%w (set SKIP_PASSKEY=true to sign in with the password instead)", ErrPasskeyRequired)
+	case !skipOffered:
+		return false, fmt.Errorf("%w: the prompt has no skip or password option", ErrPasskeyRequired)
+	default:
+		return true, nil
+	}
+}
+
+// skipPasskeyPrompt gets past the passkey prompt with its "Skip" or "Use
+// password instead" option when skipEnabled, and fails with
+// ErrPasskeyRequired otherwise or if the prompt is still there afterwards
+func skipPasskeyPrompt(page loginPage, skipEnabled bool, pause func(minMs, maxMs int)) error {
+	logger.Warning("⚠️  LinkedIn is asking to verify with a passkey")
+
+	skip, err := decidePasskey(skipEnabled, page.Has(utils.Selectors.PasskeySkipButton))
+	if !skip {
+		return err
+	}
+
+	button, err := page.Field(utils.Selectors.PasskeySkipButton)
+	if err != nil {
+		return fmt.Errorf("%w: skip option disappeared: %v", ErrPasskeyRequired, err)
+	}
+	pause(800, 1500)
+	if err := button.Click(); err != nil {
+		return fmt.Errorf("failed to skip the passkey prompt: %w", err)
+	}
+
+	pause(2000, 3000)
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("page did not load after skipping the passkey prompt: %w", err)
+	}
+	if page.Has(utils.Selectors.PasskeyPrompt) {
+		return fmt.Errorf("%w: the prompt came back after skipping", ErrPasskeyRequired)
+	}
+
+	logger.Info("Skipped the passkey prompt")
+	return nil
+}
+
+// challengeURLs are the sign-in challenge pages: a code sent by email or text,
+// CAPTCHA and the other checkpoints that need a person
+var challengeURLs = []string{
+	"/checkpoint/challenge",
+	"/checkpoint/pin",
+	"/uas/login-verification",
+	"/uas/challenge",
+	"/cap/",
+}
+
+// isLoginChallenge reports whether the page after sign in is a verification
+// challenge, from its URL or a verification code input on the page
+func isLoginChallenge(url string, hasPinInput bool) bool {
+	if hasPinInput {
+		return true
+	}
+	for _, pattern := range challengeURLs {
+		if strings.Contains(url, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChallengeWait controls waiting for a sign-in challenge to be completed by
+// hand in the browser window instead of failing with ErrChallengeRequired
+type ChallengeWait struct {
+	Enabled  bool          // Wait for the challenge (WAIT_FOR_MANUAL=true)
+	Timeout  time.Duration // Give up after this long
+	Interval time.Duration // Time between checks
+
+	clock Clock
+	sleep func(time.Duration)
+}
+
+// GetChallengeWait reads WAIT_FOR_MANUAL and MANUAL_CHALLENGE_TIMEOUT_MINUTES (default 5)
+func GetChallengeWait() ChallengeWait {
+	wait := ChallengeWait{
+		Enabled:  os.Getenv("WAIT_FOR_MANUAL") == "true",
+		Timeout:  5 * time.Minute,
+		Interval: 10 * time.Second,
+		clock:    SystemClock,
+		sleep:    time.Sleep,
+	}
+
+	if v := os.Getenv("MANUAL_CHALLENGE_TIMEOUT_MINUTES"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			wait.Timeout = time.Duration(val) * time.Minute
+		}
+	}
+
+	return wait
+}
+
+// waitForChallenge returns ErrChallengeRequired, or with wait enabled polls
+// page until the challenge is completed (it leaves both the challenge and the
+// login page) or wait.Timeout passes
+func waitForChallenge(page loginPage, wait ChallengeWait) error {
+	logger.Warning("⚠️  LinkedIn is asking for a verification code to sign in")
+	if !wait.Enabled {
+		logger.Info("Complete the verification in the browser and run again, or set WAIT_FOR_MANUAL=true to wait for it")
+		return ErrChallengeRequired
+	}
+
+	logger.Info(fmt.Sprintf("Please complete the verification in the browser. Waiting up to %s...", wait.Timeout))
+	deadline := wait.clock.Now().Add(wait.Timeout)
+	for wait.clock.Now().Before(deadline) {
+		wait.sleep(wait.Interval)
+
+		url, err := page.URL()
+		if err != nil {
+			logger.Warning("Failed to read page URL while waiting for verification: " + err.Error())
+			continue
+		}
+		if !isLoginChallenge(url, page.Has(utils.Selectors.LoginChallengePinInput)) && !utils.IsLinkedInLoginWall(url) {
+			logger.Info("✓ Verification completed")
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: not completed within %s", ErrChallengeRequired, wait.Timeout)
+}
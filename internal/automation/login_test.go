@@ -1,7 +1,12 @@
 package automation
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
+
+	"linkedin-automation/pkg/utils"
 )
 
 // TestCredentialValidation verifies credentials format
@@ -146,3 +151,385 @@ func isValidPassword(password string) bool {
 	// Minimum length of 6 characters
 	return len(password) >= 6
 }
+
+// fakeLoginPage scripts the login page; each err field fails that step
+type fakeLoginPage struct {
+	navigateErr error
+	waitErr     error
+	missing     map[string]bool
+	inputErr    error
+	typeErr     error
+	clickErr    error
+	urlErr      error
+	afterURL    string
+	present     map[string]bool
+
+	// urls, when set, are returned one per URL call after sign in (the last one repeats)
+	urls []string
+
+	typed          map[string]string
+	clicked        bool
+	bannerAnswered bool
+
+	// passkeyStays keeps the passkey prompt up after its skip option is clicked
+	passkeyStays   bool
+	passkeySkipped bool
+}
+
+func (p *fakeLoginPage) Navigate(url string) error { return p.navigateErr }
+
+func (p *fakeLoginPage) WaitLoad() error { return p.waitErr }
+
+func (p *fakeLoginPage) DismissCookieBanner() { p.bannerAnswered = true }
+
+func (p *fakeLoginPage) Field(selector string) (loginField, error) {
+	if p.missing[selector] {
+		return nil, errors.New("element not found")
+	}
+	return &fakeLoginField{page: p, selector: selector}, nil
+}
+
+func (p *fakeLoginPage) Has(selector string) bool { return p.present[selector] }
+
+func (p *fakeLoginPage) URL() (string, error) {
+	if p.urlErr != nil {
+		return "", p.urlErr
+	}
+	if p.clicked && len(p.urls) > 0 {
+		url := p.urls[0]
+		if len(p.urls) > 1 {
+			p.urls = p.urls[1:]
+		}
+		return url, nil
+	}
+	if p.clicked {
+		return p.afterURL, nil
+	}
+	return "https://www.linkedin.com/login", nil
+}
+
+type fakeLoginField struct {
+	page     *fakeLoginPage
+	selector string
+}
+
+func (f *fakeLoginField) Input(text string) error {
+	if f.page.inputErr != nil {
+		return f.page.inputErr
+	}
+	f.page.typed[f.selector] = text
+	return nil
+}
+
+func (f *fakeLoginField) TypeLikeHuman(text string) error {
+	if f.page.typeErr != nil {
+		return f.page.typeErr
+	}
+	f.page.typed[f.selector] = text
+	return nil
+}
+
+func (f *fakeLoginField) Click() error {
+	if f.page.clickErr != nil {
+		return f.page.clickErr
+	}
+	if f.selector == utils.Selectors.PasskeySkipButton {
+		f.page.passkeySkipped = true
+		if !f.page.passkeyStays {
+			delete(f.page.present, utils.Selectors.PasskeyPrompt)
+		}
+		return nil
+	}
+	f.page.clicked = true
+	return nil
+}
+
+func noPause(minMs, maxMs int) {}
+
+func TestLoginWithFormSuccess(t *testing.T) {
+	page := &fakeLoginPage{afterURL: "https://www.linkedin.com/feed/", typed: map[string]string{}}
+
+	if err := loginWithForm(page, "user@example.com", "secret123", noPause, loginOptions{}); err != nil {
+		t.Fatalf("Expected login to succeed, got %v", err)
+	}
+	if page.typed["input#username"] != "user@example.com" || page.typed["input#password"] != "secret123" {
+		t.Errorf("Expected credentials to be entered, got %v", page.typed)
+	}
+	if !page.clicked {
+		t.Error("Expected sign in to be clicked")
+	}
+	if !page.bannerAnswered {
+		t.Error("Expected the cookie banner to be answered before the form")
+	}
+}
+
+func TestLoginWithFormReturnsErrors(t *testing.T) {
+	transient := errors.New("context deadline exceeded")
+
+	tests := []struct {
+		name       string
+		page       *fakeLoginPage
+		errContain string
+	}{
+		{"Navigation fails", &fakeLoginPage{navigateErr: transient}, "failed to open login page"},
+		{"Page never loads", &fakeLoginPage{waitErr: transient}, "login page did not load"},
+		{"Email field missing", &fakeLoginPage{missing: map[string]bool{"input#username": true}}, "email input not found"},
+		{"Email input fails", &fakeLoginPage{inputErr: transient}, "failed to enter email"},
+		{"Password field missing", &fakeLoginPage{missing: map[string]bool{"input#password": true}}, "password input not found"},
+		{"Password typing fails", &fakeLoginPage{typeErr: transient}, "failed to enter password"},
+		{"Sign in button missing", &fakeLoginPage{missing: map[string]bool{`button[type="submit"]`: true}}, "Login Button not found"},
+		{"Click fails", &fakeLoginPage{clickErr: transient}, "failed to click sign in"},
+		{"URL unreadable", &fakeLoginPage{urlErr: transient}, "failed to read page URL"},
+		{"Still on login page", &fakeLoginPage{afterURL: "https://www.linkedin.com/login"}, "still on login page"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.page.typed = map[string]string{}
+
+			err := loginWithForm(test.page, "user@example.com", "secret123", noPause, loginOptions{})
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), test.errContain) {
+				t.Errorf("Expected error containing %q, got %q", test.errContain, err.Error())
+			}
+			if errors.Is(err, transient) != (test.page.navigateErr != nil || test.page.waitErr != nil || test.page.inputErr != nil ||
+				test.page.typeErr != nil || test.page.clickErr != nil || test.page.urlErr != nil) {
+				t.Errorf("Expected the underlying error to be wrapped, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoginWithFormChallenge(t *testing.T) {
+	tests := []struct {
+		name string
+		page *fakeLoginPage
+	}{
+		{"Challenge URL", &fakeLoginPage{afterURL: "https://www.linkedin.com/checkpoint/challenge/AgF"}},
+		{"Code input", &fakeLoginPage{
+			afterURL: "https://www.linkedin.com/checkpoint/lg/login-submit",
+			present:  map[string]bool{utils.Selectors.LoginChallengePinInput: true},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.page.typed = map[string]string{}
+
+			err := loginWithForm(test.page, "user@example.com", "secret123", noPause, loginOptions{})
+			if !errors.Is(err, ErrChallengeRequired) {
+				t.Errorf("Expected ErrChallengeRequired, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecidePasskey(t *testing.T) {
+	tests := []struct {
+		name        string
+		skipEnabled bool
+		skipOffered bool
+		skip        bool
+	}{
+		{"Skip enabled and offered", true, true, true},
+		{"Skip enabled, no option", true, false, false},
+		{"Skip disabled", false, true, false},
+		{"Skip disabled, no option", false, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			skip, err := decidePasskey(test.skipEnabled, test.skipOffered)
+			if skip != test.skip {
+				t.Errorf("Expected skip=%v, got %v", test.skip, skip)
+			}
+			if skip != (err == nil) || (err != nil && !errors.Is(err, ErrPasskeyRequired)) {
+				t.Errorf("Expected ErrPasskeyRequired exactly when not skipping, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoginWithFormPasskeyPrompt(t *testing.T) {
+	passkeyPage := func(skipOffered, stays bool) *fakeLoginPage {
+		present := map[string]bool{utils.Selectors.PasskeyPrompt: true}
+		if skipOffered {
+			present[utils.Selectors.PasskeySkipButton] = true
+		}
+		return &fakeLoginPage{
+			afterURL:     "https://www.linkedin.com/feed/",
+			present:      present,
+			passkeyStays: stays,
+			typed:        map[string]string{},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		page        *fakeLoginPage
+		skipPasskey bool
+		wantErr     bool
+		wantSkipped bool
+	}{
+		{"Skipped", passkeyPage(true, false), true, false, true},
+		{"Skip disabled", passkeyPage(true, false), false, true, false},
+		{"No skip option", passkeyPage(false, false), true, true, false},
+		{"Prompt comes back", passkeyPage(true, true), true, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := loginWithForm(test.page, "user@example.com", "secret123", noPause, loginOptions{SkipPasskey: test.skipPasskey})
+			if test.wantErr != errors.Is(err, ErrPasskeyRequired) || (!test.wantErr && err != nil) {
+				t.Errorf("Expected ErrPasskeyRequired=%v, got %v", test.wantErr, err)
+			}
+			if test.page.passkeySkipped != test.wantSkipped {
+				t.Errorf("Expected skip clicked=%v, got %v", test.wantSkipped, test.page.passkeySkipped)
+			}
+		})
+	}
+}
+
+func TestIsLoginChallenge(t *testing.T) {
+	tests := []struct {
+		url         string
+		hasPinInput bool
+		expected    bool
+	}{
+		{"https://www.linkedin.com/checkpoint/challenge/AgFz9?ut=1", false, true},
+		{"https://www.linkedin.com/checkpoint/pin/verify", false, true},
+		{"https://www.linkedin.com/uas/login-verification", false, true},
+		{"https://www.linkedin.com/cap/captcha", false, true},
+		{"https://www.linkedin.com/checkpoint/lg/login-submit", true, true},
+		{"https://www.linkedin.com/checkpoint/lg/login-submit", false, false},
+		{"https://www.linkedin.com/feed/", false, false},
+		{"https://www.linkedin.com/login", false, false},
+	}
+
+	for _, test := range tests {
+		if got := isLoginChallenge(test.url, test.hasPinInput); got != test.expected {
+			t.Errorf("isLoginChallenge(%q, %v) = %v, expected %v", test.url, test.hasPinInput, got, test.expected)
+		}
+	}
+}
+
+// manualWait returns a ChallengeWait whose sleeps advance clock instead of blocking
+func manualWait(clock *MockClock) ChallengeWait {
+	return ChallengeWait{
+		Enabled:  true,
+		Timeout:  5 * time.Minute,
+		Interval: 10 * time.Second,
+		clock:    clock,
+		sleep:    clock.Advance,
+	}
+}
+
+func TestWaitForChallengeCompleted(t *testing.T) {
+	clock := NewMockClock(time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC))
+	page := &fakeLoginPage{clicked: true, urls: []string{
+		"https://www.linkedin.com/checkpoint/challenge/AgF",
+		"https://www.linkedin.com/checkpoint/challenge/AgF",
+		"https://www.linkedin.com/feed/",
+	}}
+
+	if err := waitForChallenge(page, manualWait(clock)); err != nil {
+		t.Fatalf("Expected the completed challenge to succeed, got %v", err)
+	}
+	if waited := clock.Now().Sub(time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)); waited != 30*time.Second {
+		t.Errorf("Expected three checks (30s), waited %s", waited)
+	}
+}
+
+func TestWaitForChallengeTimeout(t *testing.T) {
+	start := time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)
+	clock := NewMockClock(start)
+	page := &fakeLoginPage{clicked: true, afterURL: "https://www.linkedin.com/checkpoint/challenge/AgF"}
+
+	err := waitForChallenge(page, manualWait(clock))
+	if !errors.Is(err, ErrChallengeRequired) || !strings.Contains(err.Error(), "not completed within 5m0s") {
+		t.Errorf("Expected a timeout wrapping ErrChallengeRequired, got %v", err)
+	}
+	if waited := clock.Now().Sub(start); waited != 5*time.Minute {
+		t.Errorf("Expected to give up after 5m, waited %s", waited)
+	}
+
+	// Bounced back to the login page is not a completed challenge either
+	clock.Set(start)
+	page = &fakeLoginPage{clicked: true, afterURL: "https://www.linkedin.com/login"}
+	if err := waitForChallenge(page, manualWait(clock)); !errors.Is(err, ErrChallengeRequired) {
+		t.Errorf("Expected a timeout on the login page, got %v", err)
+	}
+}
+
+func TestGetChallengeWait(t *testing.T) {
+	t.Setenv("WAIT_FOR_MANUAL", "")
+	t.Setenv("MANUAL_CHALLENGE_TIMEOUT_MINUTES", "")
+	if wait := GetChallengeWait(); wait.Enabled || wait.Timeout != 5*time.Minute {
+		t.Errorf("Unexpected defaults: enabled %v, timeout %s", wait.Enabled, wait.Timeout)
+	}
+
+	t.Setenv("WAIT_FOR_MANUAL", "true")
+	t.Setenv("MANUAL_CHALLENGE_TIMEOUT_MINUTES", "15")
+	if wait := GetChallengeWait(); !wait.Enabled || wait.Timeout != 15*time.Minute {
+		t.Errorf("Expected a 15m wait, got enabled %v, timeout %s", wait.Enabled, wait.Timeout)
+	}
+}
+
+func TestGetLoginDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max string
+		want     LoginDelay
+	}{
+		{"defaults", "", "", LoginDelay{utils.MinLoginDelay, utils.MaxLoginDelay}},
+		{"overridden", "1200", "2600", LoginDelay{1200, 2600}},
+		{"invalid ignored", "soon", "-5", LoginDelay{utils.MinLoginDelay, utils.MaxLoginDelay}},
+		{"max raised to min", "2000", "900", LoginDelay{2000, 2000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LOGIN_DELAY_MIN_MS", tt.min)
+			t.Setenv("LOGIN_DELAY_MAX_MS", tt.max)
+			if got := GetLoginDelay(); got != tt.want {
+				t.Errorf("GetLoginDelay() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginWithFormDelaysWithinBounds(t *testing.T) {
+	delay := LoginDelay{MinMs: 1000, MaxMs: 1800}
+	if signIn := delay.signIn(); signIn != (LoginDelay{MinMs: 1500, MaxMs: 5400}) {
+		t.Fatalf("Expected a longer pause before Sign in, got %+v", signIn)
+	}
+
+	// Record the bounds loginWithForm asks for; the draw within them is stealth.RandomDelay's job
+	var requested []LoginDelay
+	pause := func(minMs, maxMs int) {
+		requested = append(requested, LoginDelay{MinMs: minMs, MaxMs: maxMs})
+	}
+
+	page := &fakeLoginPage{afterURL: "https://www.linkedin.com/feed/", typed: map[string]string{}}
+	if err := loginWithForm(page, "user@example.com", "secret123", pause, loginOptions{Delay: delay}); err != nil {
+		t.Fatalf("Expected login to succeed, got %v", err)
+	}
+
+	// Page load, email, password, sign in, ...
+	if len(requested) < 4 {
+		t.Fatalf("Expected at least 4 pauses, got %d", len(requested))
+	}
+	want := []LoginDelay{delay, delay, {MinMs: 1500, MaxMs: 5400}}
+	for i, bounds := range want {
+		if got := requested[i+1]; got != bounds {
+			t.Errorf("Pause %d requested %+v, want %+v", i+1, got, bounds)
+		}
+	}
+	for i, bounds := range requested {
+		if bounds.MinMs > bounds.MaxMs {
+			t.Errorf("Pause %d requested an empty range %+v", i, bounds)
+		}
+	}
+}
@@ -2,23 +2,73 @@ package automation
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/proto"
 
 	"linkedin-automation/internal/logger"
 	"linkedin-automation/internal/stealth"
 	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
 )
 
-// SendMessage sends a direct message to a connection
+// How long to wait, and how often to look, for a sent message to appear in the thread
+const (
+	messageConfirmTimeout = 10 * time.Second
+	messageConfirmPoll    = 500 * time.Millisecond
+)
+
+// SendMessage sends a direct message to a connection. With MESSAGE_WHITELIST
+// set, profiles not on it are refused with ErrNotWhitelisted.
 func SendMessage(page *rod.Page, db *storage.Database, request MessageRequest) error {
+	return sendIfWhitelisted(request, GetMessageWhitelist(), func(request MessageRequest) error {
+		audit(db, AuditActionSendMessage, request.ProfileID, storage.AuditStarted, request.TemplateID)
+		err := sendMessage(page, db, request)
+		auditResult(db, AuditActionSendMessage, request.ProfileID, err, request.TemplateID)
+		return err
+	})
+}
+
+// GetMessageWhitelist reads MESSAGE_WHITELIST: comma-separated profile IDs (or
+// profile URLs) that are the only ones messages may go to. Empty when unset,
+// which allows every profile.
+func GetMessageWhitelist() map[string]bool {
+	return parseMessageWhitelist(os.Getenv("MESSAGE_WHITELIST"))
+}
+
+// parseMessageWhitelist splits a MESSAGE_WHITELIST value into a set of profile IDs
+func parseMessageWhitelist(spec string) map[string]bool {
+	whitelist := make(map[string]bool)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if id := utils.ExtractProfileID(item); id != "" {
+			item = id
+		}
+		if item != "" {
+			whitelist[strings.ToLower(item)] = true
+		}
+	}
+	return whitelist
+}
+
+// sendIfWhitelisted calls send unless a non-empty whitelist leaves the
+// request's profile out, in which case nothing is sent
+func sendIfWhitelisted(request MessageRequest, whitelist map[string]bool, send func(MessageRequest) error) error {
+	if len(whitelist) > 0 && !whitelist[strings.ToLower(request.ProfileID)] {
+		return fmt.Errorf("%s: %w", request.ProfileID, ErrNotWhitelisted)
+	}
+	return send(request)
+}
+
+// sendMessage does the work of SendMessage
+func sendMessage(page *rod.Page, db *storage.Database, request MessageRequest) error {
 	logger.Info(fmt.Sprintf("Sending message to: %s (%s)", request.Name, request.ProfileID))
 
 	// Navigate to profile page
 	logger.Info("Navigating to profile: " + request.ProfileURL)
-	err := page.Navigate(request.ProfileURL)
+	err := navigate(page, db, request.ProfileURL, request.ProfileID)
 	if err != nil {
 		return fmt.Errorf("failed to navigate to profile: %w", err)
 	}
@@ -51,7 +101,9 @@ func SendMessage(page *rod.Page, db *storage.Database, request MessageRequest) e
 		return fmt.Errorf("message button not found")
 	}
 
-	messageButton.Click(proto.InputMouseButtonLeft, 1)
+	if err := stealth.SafeClick(page, messageButton); err != nil {
+		return fmt.Errorf("failed to click message button: %w", err)
+	}
 	stealth.RandomDelay(1500, 2500)
 
 	// Wait for message box to open
@@ -88,8 +140,22 @@ func SendMessage(page *rod.Page, db *storage.Database, request MessageRequest) e
 		return fmt.Errorf("send button not visible")
 	}
 
-	sendButton.Click(proto.InputMouseButtonLeft, 1)
-	logger.Info("Message sent successfully")
+	if err := stealth.SafeClick(page, sendButton); err != nil {
+		return fmt.Errorf("failed to click send button: %w", err)
+	}
+
+	// Only record the message once it shows up in the thread; a disabled
+	// button or network error can swallow the click silently
+	err = confirmMessageSent(func() (string, error) { return newestSentBubble(page) },
+		request.Body, SystemClock, messageConfirmTimeout, messageConfirmPoll, time.Sleep)
+	if err != nil {
+		return err
+	}
+	logger.InfoFields("Message sent successfully", map[string]any{
+		"profile_id": request.ProfileID,
+		"action":     AuditActionSendMessage,
+		"template":   request.TemplateID,
+	})
 
 	// Record in DB
 	msg := storage.Message{
@@ -106,3 +172,42 @@ func SendMessage(page *rod.Page, db *storage.Database, request MessageRequest) e
 
 	return nil
 }
+
+// newestSentBubble returns the text of the last message we sent in the open thread ("" if none)
+func newestSentBubble(page *rod.Page) (string, error) {
+	bubbles, err := page.Elements(utils.Selectors.SentMessageBubble)
+	if err != nil {
+		return "", err
+	}
+	if len(bubbles) == 0 {
+		return "", nil
+	}
+	return bubbles[len(bubbles)-1].Text()
+}
+
+// confirmMessageSent polls newestSent until it returns body, or fails with
+// ErrMessageNotConfirmed once timeout has passed on clock. Whitespace is
+// compared loosely because the thread re-wraps the text.
+func confirmMessageSent(newestSent func() (string, error), body string, clock Clock, timeout, poll time.Duration, sleep func(time.Duration)) error {
+	want := strings.Join(strings.Fields(body), " ")
+	deadline := clock.Now().Add(timeout)
+
+	var lastErr error
+	for {
+		text, err := newestSent()
+		if err == nil && strings.Join(strings.Fields(text), " ") == want {
+			return nil
+		}
+		lastErr = err
+
+		if !clock.Now().Before(deadline) {
+			break
+		}
+		sleep(poll)
+	}
+
+	if lastErr != nil {
+		return fmt.Errorf("%w after %s: %v", ErrMessageNotConfirmed, timeout, lastErr)
+	}
+	return fmt.Errorf("%w after %s", ErrMessageNotConfirmed, timeout)
+}
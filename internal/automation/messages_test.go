@@ -0,0 +1,133 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubThread returns the newest sent bubble from a scripted sequence of reads,
+// repeating the last one once the script runs out
+type stubThread struct {
+	reads []string
+	errs  []error
+	calls int
+}
+
+func (s *stubThread) newestSent() (string, error) {
+	i := s.calls
+	if i >= len(s.reads) {
+		i = len(s.reads) - 1
+	}
+	s.calls++
+
+	var err error
+	if i < len(s.errs) {
+		err = s.errs[i]
+	}
+	return s.reads[i], err
+}
+
+// TestConfirmMessageSent tests the confirm-vs-timeout decision against a stubbed thread
+func TestConfirmMessageSent(t *testing.T) {
+	body := "Hi Jane,\nthanks for connecting!"
+
+	tests := []struct {
+		name      string
+		thread    *stubThread
+		confirmed bool
+	}{
+		{"appears immediately", &stubThread{reads: []string{"Hi Jane,\nthanks for connecting!"}}, true},
+		{"appears after a few polls", &stubThread{reads: []string{"", "Earlier message", "Hi Jane, thanks for connecting!"}}, true},
+		{"re-wrapped whitespace", &stubThread{reads: []string{"  Hi Jane,  thanks for\nconnecting! "}}, true},
+		{"never appears", &stubThread{reads: []string{"Earlier message"}}, false},
+		{"empty thread", &stubThread{reads: []string{""}}, false},
+		{"different newest bubble", &stubThread{reads: []string{"Hi Jane, thanks for connecting"}}, false},
+		{"read errors then appears", &stubThread{reads: []string{"", body}, errs: []error{errors.New("detached")}}, true},
+		{"read keeps failing", &stubThread{reads: []string{""}, errs: []error{errors.New("detached")}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewMockClock(time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC))
+			sleep := func(d time.Duration) { clock.Advance(d) }
+
+			err := confirmMessageSent(tt.thread.newestSent, body, clock, 5*time.Second, time.Second, sleep)
+
+			if tt.confirmed && err != nil {
+				t.Errorf("confirmMessageSent() error = %v, want confirmed", err)
+			}
+			if !tt.confirmed && !errors.Is(err, ErrMessageNotConfirmed) {
+				t.Errorf("confirmMessageSent() error = %v, want ErrMessageNotConfirmed", err)
+			}
+		})
+	}
+}
+
+// TestConfirmMessageSentStopsAtTimeout tests that polling stops once the timeout passes
+func TestConfirmMessageSentStopsAtTimeout(t *testing.T) {
+	clock := NewMockClock(time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC))
+	thread := &stubThread{reads: []string{"Earlier message"}}
+
+	err := confirmMessageSent(thread.newestSent, "Hello", clock, 5*time.Second, time.Second,
+		func(d time.Duration) { clock.Advance(d) })
+	if !errors.Is(err, ErrMessageNotConfirmed) {
+		t.Fatalf("confirmMessageSent() error = %v, want ErrMessageNotConfirmed", err)
+	}
+
+	// One read at t=0 and one after each 1s poll up to the 5s deadline
+	if thread.calls != 6 {
+		t.Errorf("thread read %d times, want 6", thread.calls)
+	}
+}
+
+func TestSendIfWhitelisted(t *testing.T) {
+	whitelist := parseMessageWhitelist("alice-smith, https://www.linkedin.com/in/Bob-Jones/")
+
+	tests := []struct {
+		profileID string
+		wantSent  bool
+	}{
+		{"alice-smith", true},
+		{"bob-jones", true},
+		{"carol-white", false},
+	}
+
+	for _, tt := range tests {
+		sent := false
+		err := sendIfWhitelisted(MessageRequest{ProfileID: tt.profileID}, whitelist, func(MessageRequest) error {
+			sent = true
+			return nil
+		})
+
+		if sent != tt.wantSent {
+			t.Errorf("%s: expected sent=%v, got %v", tt.profileID, tt.wantSent, sent)
+		}
+		if tt.wantSent && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.profileID, err)
+		}
+		if !tt.wantSent && !errors.Is(err, ErrNotWhitelisted) {
+			t.Errorf("%s: expected ErrNotWhitelisted, got %v", tt.profileID, err)
+		}
+	}
+
+	// Without a whitelist every profile proceeds
+	sent := false
+	sendIfWhitelisted(MessageRequest{ProfileID: "carol-white"}, parseMessageWhitelist(""), func(MessageRequest) error {
+		sent = true
+		return nil
+	})
+	if !sent {
+		t.Error("Expected messages to proceed without a whitelist")
+	}
+}
+
+func TestSendMessageRefusesProfilesOffWhitelist(t *testing.T) {
+	t.Setenv("MESSAGE_WHITELIST", "alice-smith")
+
+	// Refused before the page is touched, so no browser is needed
+	err := SendMessage(nil, nil, MessageRequest{ProfileID: "carol-white", Name: "Carol White"})
+	if !errors.Is(err, ErrNotWhitelisted) {
+		t.Errorf("Expected ErrNotWhitelisted, got %v", err)
+	}
+}
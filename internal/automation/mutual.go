@@ -0,0 +1,83 @@
+package automation
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/pkg/utils"
+)
+
+var (
+	mutualCountPattern  = regexp.MustCompile(utils.MutualCountTextPattern)
+	mutualOthersPattern = regexp.MustCompile(utils.MutualOthersTextPattern)
+	mutualNamesPattern  = regexp.MustCompile(utils.MutualNamesTextPattern)
+
+	// Separates the names listed in a mutual connection insight
+	mutualNameSeparator = regexp.MustCompile(`\s*,\s*(?:and\s+)?|\s+and\s+`)
+)
+
+// ParseMutualConnections returns the number of mutual connections in an
+// insight line such as "Jane Doe and 12 other mutual connections" (13) or
+// "Jane Doe is a mutual connection" (1). Text that isn't about mutual
+// connections returns 0.
+func ParseMutualConnections(text string) int {
+	text = strings.Join(strings.Fields(text), " ")
+
+	if match := mutualCountPattern.FindStringSubmatch(text); match != nil {
+		return parseCount(match[1])
+	}
+
+	// The named people come on top of the "N others"
+	if match := mutualOthersPattern.FindStringSubmatch(text); match != nil {
+		return countNames(match[1]) + parseCount(match[2])
+	}
+
+	if match := mutualNamesPattern.FindStringSubmatch(text); match != nil {
+		return countNames(match[1])
+	}
+
+	return 0
+}
+
+// parseCount parses a number that may have thousands separators ("1,204")
+func parseCount(digits string) int {
+	count, err := strconv.Atoi(strings.ReplaceAll(digits, ",", ""))
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// countNames counts the names in a list like "Jane Doe, John Smith and Ann Lee"
+func countNames(list string) int {
+	count := 0
+	for _, name := range mutualNameSeparator.Split(strings.TrimSpace(list), -1) {
+		if name != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// mutualCountFromContainer reads the mutual connection count off a search
+// result's insight lines, or 0 if none mentions mutual connections
+func mutualCountFromContainer(container *rod.Element) int {
+	insights, err := container.Elements(utils.Selectors.SearchResultInsight)
+	if err != nil {
+		return 0
+	}
+
+	for _, insight := range insights {
+		text, err := insight.Text()
+		if err != nil {
+			continue
+		}
+		if count := ParseMutualConnections(text); count > 0 {
+			return count
+		}
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package automation
+
+import "testing"
+
+func TestParseMutualConnections(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"12 mutual connections", 12},
+		{"1 mutual connection", 1},
+		{"1,204 mutual connections", 1204},
+		{"John Doe and 12 other mutual connections", 13},
+		{"John Doe and 1 other mutual connection", 2},
+		{"Jane Roe, John Doe and 10 other mutual connections", 12},
+		{"John Doe is a mutual connection", 1},
+		{"John Doe and Jane Roe are mutual connections", 2},
+		{"Jane Roe, John Doe, and Ann Lee are mutual connections", 3},
+		{"  John Doe and 3 other\n mutual connections  ", 4},
+		{"JOHN DOE AND 5 OTHER MUTUAL CONNECTIONS", 6},
+		{"Followed by 3 of your connections", 0},
+		{"Current: Engineer at Acme", 0},
+		{"", 0},
+	}
+
+	for _, test := range tests {
+		if got := ParseMutualConnections(test.text); got != test.expected {
+			t.Errorf("ParseMutualConnections(%q) = %d, expected %d", test.text, got, test.expected)
+		}
+	}
+}
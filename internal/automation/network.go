@@ -1,7 +1,13 @@
 package automation
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-rod/rod"
 
@@ -16,7 +22,7 @@ func CheckRecentConnections(page *rod.Page, db *storage.Database) error {
 	logger.Info("Checking recent connections...")
 
 	// Navigate to connections page
-	err := page.Navigate("https://www.linkedin.com/mynetwork/invite-connect/connections/")
+	err := navigate(page, db, "https://www.linkedin.com/mynetwork/invite-connect/connections/", "")
 	if err != nil {
 		return fmt.Errorf("failed to navigate to connections: %w", err)
 	}
@@ -53,7 +59,7 @@ func CheckRecentConnections(page *rod.Page, db *storage.Database) error {
 		}
 
 		// Update status to accepted
-		err = db.UpdateConnectionStatus(profileID, "accepted")
+		err = markConnectionAccepted(db, profileID)
 		if err != nil {
 			logger.Error(fmt.Sprintf("Failed to update status for %s: %s", profileID, err.Error()))
 		} else {
@@ -64,3 +70,471 @@ func CheckRecentConnections(page *rod.Page, db *storage.Database) error {
 	logger.Info(fmt.Sprintf("Processed %d connections", count))
 	return nil
 }
+
+// NetworkSuggestion represents a "People you may know" card on the My Network page
+type NetworkSuggestion struct {
+	ProfileID  string
+	ProfileURL string
+	Name       string
+	Title      string
+
+	card *rod.Element // Card element the suggestion was scraped from
+}
+
+// SuggestionTriageConfig controls how selective ConnectFromMyNetwork looks.
+// Connecting with every suggestion is bot-like; people pass over some and
+// dismiss the irrelevant ones.
+type SuggestionTriageConfig struct {
+	SkipRate    float64 // Fraction of suggestions passed over without action
+	DismissRate float64 // Fraction of suggestions dismissed with the card's X button
+}
+
+// GetSuggestionTriageConfig reads the rates from MYNETWORK_SKIP_PERCENT and
+// MYNETWORK_DISMISS_PERCENT (0-100; 0 disables)
+func GetSuggestionTriageConfig() SuggestionTriageConfig {
+	config := SuggestionTriageConfig{
+		SkipRate:    0.2,
+		DismissRate: 0.05,
+	}
+
+	if envSkip := os.Getenv("MYNETWORK_SKIP_PERCENT"); envSkip != "" {
+		if val, err := strconv.ParseFloat(envSkip, 64); err == nil && val >= 0 && val <= 100 {
+			config.SkipRate = val / 100
+		}
+	}
+
+	if envDismiss := os.Getenv("MYNETWORK_DISMISS_PERCENT"); envDismiss != "" {
+		if val, err := strconv.ParseFloat(envDismiss, 64); err == nil && val >= 0 && val <= 100 {
+			config.DismissRate = val / 100
+		}
+	}
+
+	return config
+}
+
+// suggestionAction is what ConnectFromMyNetwork does with a suggestion
+type suggestionAction int
+
+const (
+	suggestionConnect suggestionAction = iota
+	suggestionSkip
+	suggestionDismiss
+)
+
+// triagedSuggestion pairs a suggestion with the action chosen for it
+type triagedSuggestion struct {
+	Suggestion NetworkSuggestion
+	Action     suggestionAction
+}
+
+// ConnectFromMyNetwork sends connection requests directly from the "People you may know"
+// cards on the My Network page. These are pre-vetted 2nd-degree suggestions with high
+// acceptance rates. Profiles already contacted are skipped and rate limits are respected.
+func ConnectFromMyNetwork(page *rod.Page, db *storage.Database, rateLimiter *RateLimiter, max int) *ConnectionStats {
+	stats := &ConnectionStats{
+		StartTime: time.Now(),
+	}
+
+	logger.Info("Connecting from 'People you may know' suggestions...")
+
+	err := navigate(page, db, utils.LinkedInMyNetworkURL, "")
+	if err != nil {
+		stats.Errors = append(stats.Errors, "failed to navigate to My Network: "+err.Error())
+		stats.EndTime = time.Now()
+		return stats
+	}
+
+	page.MustWaitLoad()
+
+	// Check for LinkedIn checkpoint/verification page
+	currentURL := page.MustInfo().URL
+	if utils.IsLinkedInCheckpoint(currentURL) {
+		logger.Error("❌ LinkedIn checkpoint/verification detected at: " + currentURL)
+		stats.Errors = append(stats.Errors, ErrCheckpoint.Error())
+		stats.EndTime = time.Now()
+		return stats
+	}
+
+	stealth.RandomDelay(2000, 3000)
+
+	// Scroll to load the suggestion cards
+	stealth.RandomScroll(page)
+	stealth.RandomDelay(1000, 2000)
+
+	cards, err := page.Timeout(5 * time.Second).Elements(utils.Selectors.PYMKCard)
+	if err != nil || len(cards) == 0 {
+		logger.Warning("No 'People you may know' cards found")
+		stats.EndTime = time.Now()
+		return stats
+	}
+
+	logger.Info(fmt.Sprintf("Found %d suggestion cards", len(cards)))
+
+	var suggestions []NetworkSuggestion
+	for _, card := range cards {
+		suggestion, err := scrapeSuggestionCard(card)
+		if err != nil {
+			logger.Debug("Skipping suggestion card: " + err.Error())
+			continue
+		}
+		suggestions = append(suggestions, *suggestion)
+	}
+
+	// Dedup against the database, then decide which suggestions to pass over or dismiss.
+	// max caps the connection requests, not the cards looked at.
+	candidates := selectSuggestions(suggestions, 0, suggestionHandled(db))
+	plan := triageSuggestions(candidates, GetSuggestionTriageConfig(), utils.SessionRand())
+
+	logger.Info(fmt.Sprintf("Considering %d of %d suggestions for connection requests", len(plan), len(suggestions)))
+
+	// Pass over and dismiss suggestions up to the last one that gets a request;
+	// the requests themselves go through the shared connection loop
+	var requests []ConnectionRequest
+	suggestionsByID := make(map[string]NetworkSuggestion)
+	for _, step := range plan {
+		if max > 0 && len(requests) >= max {
+			break
+		}
+
+		// Honor the PAUSE / STOP control files between suggestions
+		if err := WaitWhilePaused(context.Background()); err != nil {
+			stats.Errors = append(stats.Errors, err.Error())
+			stats.EndTime = time.Now()
+			return stats
+		}
+
+		suggestion := step.Suggestion
+		switch step.Action {
+		case suggestionSkip:
+			stats.Passed++
+			logger.Debug("Passing over suggestion " + suggestion.Name)
+		case suggestionDismiss:
+			if err := dismissSuggestion(page, db, suggestion); err != nil {
+				logger.Warning(fmt.Sprintf("Failed to dismiss suggestion %s: %s", suggestion.Name, err.Error()))
+			} else {
+				stats.Dismissed++
+			}
+		default:
+			suggestionsByID[suggestion.ProfileID] = suggestion
+			requests = append(requests, ConnectionRequest{
+				ProfileID:   suggestion.ProfileID,
+				ProfileURL:  suggestion.ProfileURL,
+				Name:        suggestion.Name,
+				Title:       suggestion.Title,
+				RequestedAt: time.Now(),
+			})
+		}
+	}
+
+	connStats := sendConnectionRequests(db, rateLimiter, requests, func(request ConnectionRequest) error {
+		return sendSuggestionConnection(page, db, suggestionsByID[request.ProfileID])
+	})
+	connStats.StartTime = stats.StartTime
+	connStats.Passed = stats.Passed
+	connStats.Dismissed = stats.Dismissed
+	stats = connStats
+
+	stats.EndTime = time.Now()
+	logger.Info(fmt.Sprintf("My Network connections completed: %d successful, %d failed in %s",
+		stats.Successful, stats.Failed, stats.EndTime.Sub(stats.StartTime)))
+
+	return stats
+}
+
+// sendSuggestionConnection clicks Connect on a suggestion card and saves the
+// profile and pending request once it goes through
+func sendSuggestionConnection(page *rod.Page, db *storage.Database, suggestion NetworkSuggestion) error {
+	audit(db, AuditActionSendConnection, suggestion.ProfileID, storage.AuditStarted, suggestion.ProfileURL)
+	err := connectFromSuggestionCard(page, suggestion)
+	auditResult(db, AuditActionSendConnection, suggestion.ProfileID, err, "from My Network suggestions")
+	if err != nil {
+		return err
+	}
+	logger.Info("Connection request sent to " + suggestion.Name)
+
+	if db != nil {
+		now := time.Now()
+		profile := storage.Profile{
+			ID:         suggestion.ProfileID,
+			Name:       suggestion.Name,
+			Title:      suggestion.Title,
+			ProfileURL: suggestion.ProfileURL,
+			VisitedAt:  now,
+			CreatedAt:  now,
+		}
+		if err := db.SaveProfile(profile); err != nil {
+			logger.Warning(fmt.Sprintf("Failed to save profile %s: %s", suggestion.ProfileID, err.Error()))
+		}
+
+		connectionReq := storage.ConnectionRequest{
+			ProfileID: suggestion.ProfileID,
+			SentAt:    now,
+			Status:    "pending",
+		}
+		if err := db.SaveConnectionRequest(connectionReq); err != nil {
+			logger.Warning("Failed to save connection request to database: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// scrapeSuggestionCard reads the profile link, name and headline from a suggestion card
+func scrapeSuggestionCard(card *rod.Element) (*NetworkSuggestion, error) {
+	link, err := card.Element(utils.Selectors.PYMKCardLink)
+	if err != nil {
+		return nil, fmt.Errorf("no profile link found")
+	}
+
+	href, err := link.Attribute("href")
+	if err != nil || href == nil {
+		return nil, fmt.Errorf("profile link has no href")
+	}
+
+	var name, title string
+	if nameEl, err := card.Element(utils.Selectors.PYMKCardName); err == nil {
+		name, _ = nameEl.Text()
+	}
+	if titleEl, err := card.Element(utils.Selectors.PYMKCardOccupation); err == nil {
+		title, _ = titleEl.Text()
+	}
+
+	suggestion, err := parseSuggestionCard(*href, name, title)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion.card = card
+	return suggestion, nil
+}
+
+// parseSuggestionCard builds a NetworkSuggestion from the raw text scraped off a card
+func parseSuggestionCard(href, name, title string) (*NetworkSuggestion, error) {
+	profileID := utils.ExtractProfileID(href)
+	if profileID == "" {
+		return nil, fmt.Errorf("could not extract profile ID from URL: %s", href)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("no name found for %s", profileID)
+	}
+
+	return &NetworkSuggestion{
+		ProfileID:  profileID,
+		ProfileURL: utils.LinkedInProfileBase + profileID + "/",
+		Name:       name,
+		Title:      strings.TrimSpace(title),
+	}, nil
+}
+
+// selectSuggestions drops suggestions already handled (or repeated on the page)
+// and returns at most max entries. Lookup errors skip the suggestion to stay safe.
+func selectSuggestions(suggestions []NetworkSuggestion, max int, alreadyHandled func(profileID string) (bool, error)) []NetworkSuggestion {
+	var selected []NetworkSuggestion
+	seen := make(map[string]bool)
+
+	for _, suggestion := range suggestions {
+		if max > 0 && len(selected) >= max {
+			break
+		}
+
+		if seen[suggestion.ProfileID] {
+			continue
+		}
+		seen[suggestion.ProfileID] = true
+
+		handled, err := alreadyHandled(suggestion.ProfileID)
+		if err != nil {
+			logger.Warning(fmt.Sprintf("Failed to check connection history for %s: %s", suggestion.ProfileID, err.Error()))
+			continue
+		}
+		if handled {
+			logger.Info(fmt.Sprintf("Skipping %s - already contacted or dismissed", suggestion.Name))
+			continue
+		}
+
+		selected = append(selected, suggestion)
+	}
+
+	return selected
+}
+
+// suggestionHandled returns the lookup selectSuggestions uses to drop profiles
+// already contacted or whose suggestion was dismissed before
+func suggestionHandled(db *storage.Database) func(profileID string) (bool, error) {
+	return func(profileID string) (bool, error) {
+		if db == nil {
+			return false, nil
+		}
+		sent, err := db.HasSentConnectionRequest(profileID)
+		if err != nil || sent {
+			return sent, err
+		}
+		return db.IsSuggestionDismissed(profileID)
+	}
+}
+
+// triageSuggestions decides per suggestion whether to connect, pass over or dismiss
+// it, keeping page order. Each suggestion is dismissed with probability DismissRate
+// and passed over with probability SkipRate.
+func triageSuggestions(suggestions []NetworkSuggestion, config SuggestionTriageConfig, r *rand.Rand) []triagedSuggestion {
+	plan := make([]triagedSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		action := suggestionConnect
+		roll := r.Float64()
+		switch {
+		case roll < config.DismissRate:
+			action = suggestionDismiss
+		case roll < config.DismissRate+config.SkipRate:
+			action = suggestionSkip
+		}
+		plan = append(plan, triagedSuggestion{Suggestion: suggestion, Action: action})
+	}
+	return plan
+}
+
+// dismissSuggestion clicks the X on a suggestion card and remembers the dismissal
+func dismissSuggestion(page *rod.Page, db *storage.Database, suggestion NetworkSuggestion) error {
+	if suggestion.card == nil {
+		return fmt.Errorf("suggestion card not available")
+	}
+
+	button, err := suggestion.card.Element(utils.Selectors.PYMKDismissButton)
+	if err != nil || button == nil {
+		return fmt.Errorf("dismiss button not found on suggestion card")
+	}
+
+	stealth.RandomDelay(600, 1200)
+
+	if err := stealth.SafeClick(page, button); err != nil {
+		return fmt.Errorf("failed to click dismiss button: %w", err)
+	}
+	logger.Info("Dismissed suggestion " + suggestion.Name)
+
+	if db != nil {
+		if err := db.RecordDismissedSuggestion(suggestion.ProfileID); err != nil {
+			logger.Warning(err.Error())
+		}
+	}
+
+	stealth.RandomDelay(1000, 2000)
+	return nil
+}
+
+// connectFromSuggestionCard clicks the Connect button on a suggestion card
+func connectFromSuggestionCard(page *rod.Page, suggestion NetworkSuggestion) error {
+	if suggestion.card == nil {
+		return fmt.Errorf("suggestion card not available")
+	}
+
+	button, err := suggestion.card.Element(utils.Selectors.PYMKConnectButton)
+	if err != nil || button == nil {
+		return fmt.Errorf("connect button not found on suggestion card")
+	}
+
+	if err := button.ScrollIntoView(); err != nil {
+		return fmt.Errorf("failed to scroll connect button into view: %w", err)
+	}
+
+	stealth.RandomDelay(800, 1500)
+
+	if err := stealth.SafeClick(page, button); err != nil {
+		return fmt.Errorf("failed to click connect button: %w", err)
+	}
+
+	stealth.RandomDelay(1500, 2500)
+	return nil
+}
+
+// maxReconcilePages caps how many 1st-degree search pages BulkReconcileFirstDegree scans
+const maxReconcilePages = 10
+
+// BulkReconcileFirstDegree catches connections accepted while the bot was offline.
+// It searches the account's own 1st-degree network and marks any pending connection
+// request whose profile appears in the results as accepted.
+// Returns the number of connection requests updated.
+func BulkReconcileFirstDegree(page *rod.Page, db *storage.Database) (int, error) {
+	logger.Info("Reconciling pending connections against 1st-degree network...")
+
+	pendingRequests, err := db.GetPendingConnections()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending connections: %w", err)
+	}
+
+	if len(pendingRequests) == 0 {
+		logger.Info("No pending connections to reconcile")
+		return 0, nil
+	}
+
+	firstDegree := make(map[string]bool)
+	for pageNum := 1; pageNum <= maxReconcilePages; pageNum++ {
+		searchURL, err := buildSearchURL(SearchConfig{
+			Network:   []string{NetworkFirstDegree},
+			StartPage: pageNum,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to build search URL: %w", err)
+		}
+
+		logger.Info(fmt.Sprintf("Scanning 1st-degree connections page %d", pageNum))
+		if err := navigate(page, db, searchURL, ""); err != nil {
+			return 0, fmt.Errorf("failed to navigate to search page: %w", err)
+		}
+
+		page.MustWaitLoad()
+		stealth.RandomDelay(2000, 3000)
+
+		if err := checkSearchPageAccess(page.MustInfo().URL); err != nil {
+			return 0, err
+		}
+
+		results, err := ParseSearchResults(page)
+		if err != nil {
+			logger.Warning(fmt.Sprintf("Failed to parse page %d: %s", pageNum, err.Error()))
+			break
+		}
+
+		if len(results) == 0 {
+			break
+		}
+
+		for _, result := range results {
+			firstDegree[result.ProfileID] = true
+		}
+
+		stealth.RandomDelay(1500, 3000)
+	}
+
+	logger.Info(fmt.Sprintf("Found %d 1st-degree connections", len(firstDegree)))
+
+	updated := reconcileAccepted(pendingRequests, firstDegree, func(profileID string) error {
+		return markConnectionAccepted(db, profileID)
+	})
+
+	logger.Info(fmt.Sprintf("Marked %d pending connections as accepted", updated))
+	return updated, nil
+}
+
+// reconcileAccepted marks every pending request whose profile is in firstDegree as accepted.
+// Returns the number of profiles successfully updated.
+func reconcileAccepted(pending []storage.ConnectionRequest, firstDegree map[string]bool, markAccepted func(profileID string) error) int {
+	updated := 0
+	seen := make(map[string]bool)
+
+	for _, request := range pending {
+		if !firstDegree[request.ProfileID] || seen[request.ProfileID] {
+			continue
+		}
+		seen[request.ProfileID] = true
+
+		if err := markAccepted(request.ProfileID); err != nil {
+			logger.Warning(fmt.Sprintf("Failed to update status for %s: %s", request.ProfileID, err.Error()))
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Connection accepted: %s", request.ProfileID))
+		updated++
+	}
+
+	return updated
+}
@@ -0,0 +1,258 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+func TestParseSuggestionCard(t *testing.T) {
+	tests := []struct {
+		name      string
+		href      string
+		cardName  string
+		cardTitle string
+		wantError bool
+		wantID    string
+		wantTitle string
+	}{
+		{
+			name:      "Relative profile link",
+			href:      "/in/jane-doe/",
+			cardName:  "  Jane Doe ",
+			cardTitle: " Engineering Manager at Acme ",
+			wantID:    "jane-doe",
+			wantTitle: "Engineering Manager at Acme",
+		},
+		{
+			name:     "Absolute link with tracking params",
+			href:     "https://www.linkedin.com/in/john-smith-123?miniProfileUrn=abc",
+			cardName: "John Smith",
+			wantID:   "john-smith-123",
+		},
+		{
+			name:      "Not a profile link",
+			href:      "/company/acme/",
+			cardName:  "Acme",
+			wantError: true,
+		},
+		{
+			name:      "Missing name",
+			href:      "/in/no-name/",
+			cardName:  "   ",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion, err := parseSuggestionCard(tt.href, tt.cardName, tt.cardTitle)
+
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Expected error but got %+v", suggestion)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if suggestion.ProfileID != tt.wantID {
+				t.Errorf("Expected profile ID %s, got %s", tt.wantID, suggestion.ProfileID)
+			}
+			if suggestion.ProfileURL != "https://www.linkedin.com/in/"+tt.wantID+"/" {
+				t.Errorf("Unexpected profile URL: %s", suggestion.ProfileURL)
+			}
+			if suggestion.Title != tt.wantTitle {
+				t.Errorf("Expected title %q, got %q", tt.wantTitle, suggestion.Title)
+			}
+		})
+	}
+}
+
+func TestSelectSuggestions(t *testing.T) {
+	suggestions := []NetworkSuggestion{
+		{ProfileID: "alice", Name: "Alice"},
+		{ProfileID: "bob", Name: "Bob"},
+		{ProfileID: "alice", Name: "Alice"}, // Repeated card
+		{ProfileID: "carol", Name: "Carol"},
+		{ProfileID: "dave", Name: "Dave"},
+		{ProfileID: "erin", Name: "Erin"},
+	}
+
+	contacted := map[string]bool{"bob": true}
+	alreadySent := func(profileID string) (bool, error) {
+		if profileID == "dave" {
+			return false, errors.New("database locked")
+		}
+		return contacted[profileID], nil
+	}
+
+	tests := []struct {
+		name    string
+		max     int
+		wantIDs []string
+	}{
+		{"Dedup without limit", 0, []string{"alice", "carol", "erin"}},
+		{"Limit enforced", 2, []string{"alice", "carol"}},
+		{"Limit of one", 1, []string{"alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := selectSuggestions(suggestions, tt.max, alreadySent)
+
+			if len(selected) != len(tt.wantIDs) {
+				t.Fatalf("Expected %d suggestions, got %d: %+v", len(tt.wantIDs), len(selected), selected)
+			}
+
+			for i, id := range tt.wantIDs {
+				if selected[i].ProfileID != id {
+					t.Errorf("Position %d: expected %s, got %s", i, id, selected[i].ProfileID)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileAccepted(t *testing.T) {
+	pending := []storage.ConnectionRequest{
+		{ProfileID: "alice", Status: "pending"},
+		{ProfileID: "bob", Status: "pending"},
+		{ProfileID: "carol", Status: "pending"},
+		{ProfileID: "alice", Status: "pending"}, // Duplicate request row
+		{ProfileID: "dave", Status: "pending"},
+	}
+
+	// Stubbed 1st-degree search results
+	var results []SearchResult
+	for _, id := range []string{"alice", "carol", "dave", "zed"} {
+		results = append(results, SearchResult{ProfileID: id})
+	}
+	firstDegree := make(map[string]bool)
+	for _, result := range results {
+		firstDegree[result.ProfileID] = true
+	}
+
+	var marked []string
+	updated := reconcileAccepted(pending, firstDegree, func(profileID string) error {
+		if profileID == "dave" {
+			return errors.New("database locked")
+		}
+		marked = append(marked, profileID)
+		return nil
+	})
+
+	if updated != 2 {
+		t.Errorf("Expected 2 updates, got %d", updated)
+	}
+
+	want := []string{"alice", "carol"}
+	if len(marked) != len(want) {
+		t.Fatalf("Expected %v to be marked accepted, got %v", want, marked)
+	}
+	for i := range want {
+		if marked[i] != want[i] {
+			t.Errorf("Expected %s at position %d, got %s", want[i], i, marked[i])
+		}
+	}
+}
+
+func TestReconcileAcceptedNoOverlap(t *testing.T) {
+	pending := []storage.ConnectionRequest{{ProfileID: "alice"}}
+	updated := reconcileAccepted(pending, map[string]bool{"bob": true}, func(string) error {
+		t.Fatal("No profile should be updated")
+		return nil
+	})
+
+	if updated != 0 {
+		t.Errorf("Expected 0 updates, got %d", updated)
+	}
+}
+
+func TestTriageSuggestionsRates(t *testing.T) {
+	suggestions := make([]NetworkSuggestion, 10000)
+	for i := range suggestions {
+		suggestions[i] = NetworkSuggestion{ProfileID: fmt.Sprintf("p%d", i)}
+	}
+
+	config := SuggestionTriageConfig{SkipRate: 0.2, DismissRate: 0.05}
+	plan := triageSuggestions(suggestions, config, rand.New(rand.NewSource(1)))
+
+	if len(plan) != len(suggestions) {
+		t.Fatalf("Expected %d planned suggestions, got %d", len(suggestions), len(plan))
+	}
+
+	counts := make(map[suggestionAction]int)
+	for i, step := range plan {
+		if step.Suggestion.ProfileID != suggestions[i].ProfileID {
+			t.Fatalf("Plan out of page order at %d", i)
+		}
+		counts[step.Action]++
+	}
+
+	skipRate := float64(counts[suggestionSkip]) / float64(len(plan))
+	dismissRate := float64(counts[suggestionDismiss]) / float64(len(plan))
+	if math.Abs(skipRate-0.2) > 0.02 {
+		t.Errorf("Expected skip rate near 0.20, got %.3f", skipRate)
+	}
+	if math.Abs(dismissRate-0.05) > 0.01 {
+		t.Errorf("Expected dismiss rate near 0.05, got %.3f", dismissRate)
+	}
+}
+
+func TestTriageSuggestionsDisabled(t *testing.T) {
+	suggestions := []NetworkSuggestion{{ProfileID: "alice"}, {ProfileID: "bob"}, {ProfileID: "carol"}}
+
+	plan := triageSuggestions(suggestions, SuggestionTriageConfig{}, rand.New(rand.NewSource(1)))
+	for _, step := range plan {
+		if step.Action != suggestionConnect {
+			t.Errorf("Expected every suggestion connected with zero rates, got %v for %s", step.Action, step.Suggestion.ProfileID)
+		}
+	}
+}
+
+func TestSuggestionHandledRemembersDismissals(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_network.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.RecordDismissedSuggestion("alice"); err != nil {
+		t.Fatalf("Failed to record dismissal: %v", err)
+	}
+	if err := db.SaveConnectionRequest(storage.ConnectionRequest{ProfileID: "bob", SentAt: time.Now(), Status: "pending"}); err != nil {
+		t.Fatalf("Failed to save connection request: %v", err)
+	}
+
+	suggestions := []NetworkSuggestion{{ProfileID: "alice"}, {ProfileID: "bob"}, {ProfileID: "carol"}}
+	selected := selectSuggestions(suggestions, 0, suggestionHandled(db))
+
+	if len(selected) != 1 || selected[0].ProfileID != "carol" {
+		t.Errorf("Expected only carol to be reconsidered, got %+v", selected)
+	}
+}
+
+func TestGetSuggestionTriageConfig(t *testing.T) {
+	t.Setenv("MYNETWORK_SKIP_PERCENT", "10")
+	t.Setenv("MYNETWORK_DISMISS_PERCENT", "0")
+
+	config := GetSuggestionTriageConfig()
+	if config.SkipRate != 0.1 || config.DismissRate != 0 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	t.Setenv("MYNETWORK_SKIP_PERCENT", "150")
+	if config := GetSuggestionTriageConfig(); config.SkipRate != 0.2 {
+		t.Errorf("Expected out-of-range percent to keep the default, got %+v", config)
+	}
+}
@@ -0,0 +1,81 @@
+package automation
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"strings"
+
+	"linkedin-automation/internal/logger"
+)
+
+// defaultNoisePages are the pages browsed for warm-up and between profile
+// batches when NOISE_PAGES is not set
+var defaultNoisePages = []string{
+	"https://www.linkedin.com/feed/",
+	"https://www.linkedin.com/notifications/",
+	"https://www.linkedin.com/jobs/",
+}
+
+// GetNoisePages returns the allowlist of pages browsed as noise (warm-up and
+// context switches) from NOISE_PAGES, a comma-separated list of LinkedIn URLs.
+// Entries that aren't LinkedIn URLs are dropped with a warning, so a typo can't
+// send the browser to an external site; with none left the defaults are used.
+func GetNoisePages() []string {
+	spec := os.Getenv("NOISE_PAGES")
+	if strings.TrimSpace(spec) == "" {
+		return defaultNoisePages
+	}
+
+	var pages []string
+	for _, page := range strings.Split(spec, ",") {
+		page = strings.TrimSpace(page)
+		if page == "" {
+			continue
+		}
+		if err := ValidateNoisePage(page); err != nil {
+			logger.Warning("Ignoring NOISE_PAGES entry: " + err.Error())
+			continue
+		}
+		pages = append(pages, page)
+	}
+
+	if len(pages) == 0 {
+		logger.Warning("NOISE_PAGES has no valid LinkedIn URLs, using the defaults")
+		return defaultNoisePages
+	}
+	return pages
+}
+
+// ValidateNoisePage returns an error unless page is an https URL on linkedin.com
+func ValidateNoisePage(page string) error {
+	u, err := url.Parse(page)
+	if err != nil {
+		return fmt.Errorf("%q is not a URL: %w", page, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%q is not an https URL", page)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host != "linkedin.com" && !strings.HasSuffix(host, ".linkedin.com") {
+		return fmt.Errorf("%q is not a LinkedIn page", page)
+	}
+	return nil
+}
+
+// PickNoisePage returns a random page from pages
+func PickNoisePage(r *rand.Rand, pages []string) string {
+	return pages[r.Intn(len(pages))]
+}
+
+// OnNoisePage reports whether current is one of pages (or a page under one of them)
+func OnNoisePage(current string, pages []string) bool {
+	for _, page := range pages {
+		if strings.HasPrefix(current, strings.TrimSuffix(page, "/")) {
+			return true
+		}
+	}
+	return false
+}
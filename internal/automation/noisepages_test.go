@@ -0,0 +1,99 @@
+package automation
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"linkedin-automation/pkg/utils"
+)
+
+func TestValidateNoisePage(t *testing.T) {
+	tests := []struct {
+		page  string
+		valid bool
+	}{
+		{"https://www.linkedin.com/feed/", true},
+		{"https://linkedin.com/jobs/", true},
+		{"https://WWW.LINKEDIN.COM/notifications/", true},
+		{"http://www.linkedin.com/feed/", false},
+		{"https://www.linkedin.com.evil.example/feed/", false},
+		{"https://notlinkedin.com/feed/", false},
+		{"https://example.com/?u=https://www.linkedin.com/feed/", false},
+		{"www.linkedin.com/feed/", false},
+		{"javascript:alert(1)", false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateNoisePage(test.page); (err == nil) != test.valid {
+			t.Errorf("ValidateNoisePage(%q): expected valid=%v, got %v", test.page, test.valid, err)
+		}
+	}
+}
+
+func TestGetNoisePages(t *testing.T) {
+	t.Setenv("NOISE_PAGES", "")
+	if pages := GetNoisePages(); !reflect.DeepEqual(pages, defaultNoisePages) {
+		t.Errorf("Expected the defaults, got %v", pages)
+	}
+
+	t.Setenv("NOISE_PAGES", " https://www.linkedin.com/jobs/ ,https://example.com/,, https://www.linkedin.com/mynetwork/")
+	want := []string{"https://www.linkedin.com/jobs/", "https://www.linkedin.com/mynetwork/"}
+	if pages := GetNoisePages(); !reflect.DeepEqual(pages, want) {
+		t.Errorf("Expected %v, got %v", want, pages)
+	}
+
+	t.Setenv("NOISE_PAGES", "https://example.com/")
+	if pages := GetNoisePages(); !reflect.DeepEqual(pages, defaultNoisePages) {
+		t.Errorf("Expected the defaults when no entry is valid, got %v", pages)
+	}
+}
+
+func TestDefaultNoisePagesAreNotProfiles(t *testing.T) {
+	for _, page := range defaultNoisePages {
+		if err := ValidateNoisePage(page); err != nil {
+			t.Errorf("Default noise page is invalid: %v", err)
+		}
+		if utils.ExtractProfileID(page) != "" {
+			t.Errorf("Default noise page %s is a profile page", page)
+		}
+	}
+}
+
+func TestPickNoisePageStaysInList(t *testing.T) {
+	pages := []string{"https://www.linkedin.com/feed/", "https://www.linkedin.com/jobs/", "https://www.linkedin.com/notifications/"}
+	r := rand.New(rand.NewSource(1))
+
+	picked := make(map[string]int)
+	for i := 0; i < 300; i++ {
+		page := PickNoisePage(r, pages)
+		if !OnNoisePage(page, pages) {
+			t.Fatalf("Picked %s, which is not in the list", page)
+		}
+		picked[page]++
+	}
+	if len(picked) != len(pages) {
+		t.Errorf("Expected every page to be picked, got %v", picked)
+	}
+}
+
+func TestOnNoisePage(t *testing.T) {
+	pages := []string{"https://www.linkedin.com/feed/", "https://www.linkedin.com/jobs/"}
+
+	tests := []struct {
+		current  string
+		expected bool
+	}{
+		{"https://www.linkedin.com/feed/", true},
+		{"https://www.linkedin.com/feed", true},
+		{"https://www.linkedin.com/jobs/collections/recommended/", true},
+		{"https://www.linkedin.com/in/jane-doe/", false},
+		{"https://www.linkedin.com/notifications/", false},
+	}
+
+	for _, test := range tests {
+		if got := OnNoisePage(test.current, pages); got != test.expected {
+			t.Errorf("OnNoisePage(%q) = %v, expected %v", test.current, got, test.expected)
+		}
+	}
+}
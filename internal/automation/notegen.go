@@ -0,0 +1,245 @@
+package automation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+)
+
+// GeneratedNoteTemplateID is recorded as the TemplateID of requests whose note
+// came from a generator other than a template (e.g. an LLM endpoint)
+const GeneratedNoteTemplateID = "generated"
+
+// noteGeneratorTimeout bounds a single call to an HTTP note generator
+const noteGeneratorTimeout = 30 * time.Second
+
+// NoteGenerator produces the connection note for a profile.
+// context is free-form guidance about the sender and why they want to connect.
+type NoteGenerator interface {
+	Generate(profile storage.Profile, context string) (string, error)
+}
+
+// TemplateNoteGenerator renders a connection request template (the default generator)
+type TemplateNoteGenerator struct {
+	TemplateID string
+	SenderVars TemplateVariables
+}
+
+// Generate renders the template for the profile. The template carries its own
+// wording, so context is ignored.
+func (g TemplateNoteGenerator) Generate(profile storage.Profile, context string) (string, error) {
+	template, err := NoteEntry{TemplateID: g.TemplateID}.template(0)
+	if err != nil {
+		return "", err
+	}
+
+	return RenderTemplate(*template, buildProfileTemplateVars(profile, g.SenderVars))
+}
+
+// HTTPNoteGenerator asks an external service (e.g. an LLM behind a small API) for a note.
+// It POSTs a noteGeneratorRequest as JSON and expects a noteGeneratorResponse back.
+type HTTPNoteGenerator struct {
+	Endpoint string
+	APIKey   string // Sent as "Authorization: Bearer <key>" when set
+	MaxChars int    // Length limit passed to the service
+	Client   *http.Client
+}
+
+// noteGeneratorRequest is the body sent to an HTTP note generator
+type noteGeneratorRequest struct {
+	Profile  noteGeneratorProfile `json:"profile"`
+	Context  string               `json:"context"`
+	MaxChars int                  `json:"max_chars"`
+}
+
+// noteGeneratorProfile is the profile data shared with an HTTP note generator
+type noteGeneratorProfile struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	FirstName string `json:"first_name"`
+	Title     string `json:"title"`
+	Company   string `json:"company"`
+	Location  string `json:"location"`
+}
+
+// noteGeneratorResponse is the body expected from an HTTP note generator
+type noteGeneratorResponse struct {
+	Note string `json:"note"`
+}
+
+// Generate sends the profile fields to the endpoint and returns the note it answers with
+func (g HTTPNoteGenerator) Generate(profile storage.Profile, context string) (string, error) {
+	vars := buildProfileTemplateVars(profile, TemplateVariables{})
+	payload, err := json.Marshal(noteGeneratorRequest{
+		Profile: noteGeneratorProfile{
+			ID:        profile.ID,
+			Name:      profile.Name,
+			FirstName: vars.FirstName,
+			Title:     profile.Title,
+			Company:   profile.Company,
+			Location:  profile.Location,
+		},
+		Context:  context,
+		MaxChars: g.MaxChars,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode note request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, g.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to build note request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.APIKey)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = &http.Client{Timeout: noteGeneratorTimeout}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("note generator request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", fmt.Errorf("failed to read note generator response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("note generator returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var result noteGeneratorResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("invalid note generator response: %w", err)
+	}
+	if strings.TrimSpace(result.Note) == "" {
+		return "", fmt.Errorf("note generator returned an empty note")
+	}
+
+	return result.Note, nil
+}
+
+// noteGenerator replaces the generator chosen from the environment when set
+var noteGenerator NoteGenerator
+
+// SetNoteGenerator makes every connection request use g for its note (nil restores the default)
+func SetNoteGenerator(g NoteGenerator) {
+	noteGenerator = g
+}
+
+// ExternalNoteGeneratorEnabled reports whether notes come from an HTTP generator (NOTE_GENERATOR=http)
+func ExternalNoteGeneratorEnabled() bool {
+	return strings.EqualFold(os.Getenv("NOTE_GENERATOR"), "http")
+}
+
+// NoteGeneratorFromEnv returns the HTTP generator when NOTE_GENERATOR=http
+// (NOTE_GENERATOR_URL, NOTE_GENERATOR_API_KEY), otherwise the template generator
+func NoteGeneratorFromEnv(templateID string, senderVars TemplateVariables) NoteGenerator {
+	if ExternalNoteGeneratorEnabled() {
+		if endpoint := os.Getenv("NOTE_GENERATOR_URL"); endpoint != "" {
+			return HTTPNoteGenerator{
+				Endpoint: endpoint,
+				APIKey:   os.Getenv("NOTE_GENERATOR_API_KEY"),
+				MaxChars: GetConnectionNoteMaxLength(),
+			}
+		}
+		logger.Warning("NOTE_GENERATOR=http but NOTE_GENERATOR_URL is empty, using template " + templateID)
+	}
+	return TemplateNoteGenerator{TemplateID: templateID, SenderVars: senderVars}
+}
+
+// configuredNoteGenerator returns the generator set by SetNoteGenerator, or the one from the environment
+func configuredNoteGenerator(templateID string, senderVars TemplateVariables) NoteGenerator {
+	if noteGenerator != nil {
+		return noteGenerator
+	}
+	return NoteGeneratorFromEnv(templateID, senderVars)
+}
+
+// noteContext describes the sender for a generator
+func noteContext(senderVars TemplateVariables) string {
+	var parts []string
+	if senderVars.YourName != "" {
+		parts = append(parts, "Sender: "+senderVars.YourName)
+	}
+	if senderVars.YourTitle != "" {
+		parts = append(parts, "Sender title: "+senderVars.YourTitle)
+	}
+	if senderVars.YourCompany != "" {
+		parts = append(parts, "Sender company: "+senderVars.YourCompany)
+	}
+	if senderVars.Industry != "" {
+		parts = append(parts, "Industry: "+senderVars.Industry)
+	}
+	if senderVars.CustomReason != "" {
+		parts = append(parts, "Reason to connect: "+senderVars.CustomReason)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// prepareConnectionRequestWithGenerator builds a ConnectionRequest whose note comes from generator.
+// Generated notes are sanitized and length-checked like template notes. A note over
+// the limit is truncated when opts.TruncateGeneratedNotes is set, sent without a note
+// when opts.NotelessOnOverlength is set, and rejected with ErrMessageTooLong otherwise.
+func prepareConnectionRequestWithGenerator(profile storage.Profile, generator NoteGenerator, context string, opts PrepareOptions) (*ConnectionRequest, error) {
+	templateID := GeneratedNoteTemplateID
+	if tg, ok := generator.(TemplateNoteGenerator); ok {
+		if template, err := GetTemplateByID(tg.TemplateID); err == nil {
+			vars := buildProfileTemplateVars(profile, tg.SenderVars)
+			if fallback, ok := withFallbackTemplate(template, vars, opts.FallbackTemplateID); ok {
+				tg.TemplateID = fallback.ID
+				generator = tg
+			}
+		}
+		templateID = tg.TemplateID
+	}
+
+	note, err := generator.Generate(profile, context)
+	if err == nil {
+		note = SanitizeNote(cleanupWhitespace(note))
+		err = ValidateMessageLength(note, TemplateConnectionRequest)
+
+		if errors.Is(err, ErrMessageTooLong) && opts.TruncateGeneratedNotes {
+			logger.Warning(fmt.Sprintf("Generated note for %s is too long, truncating", profile.Name))
+			note = TruncateNote(note, GetConnectionNoteMaxLength())
+			err = ValidateMessageLength(note, TemplateConnectionRequest)
+		}
+	}
+
+	if err != nil {
+		if !opts.NotelessOnOverlength || !errors.Is(err, ErrMessageTooLong) {
+			return nil, fmt.Errorf("failed to generate note: %w", err)
+		}
+
+		// Better to connect without a note than not at all
+		logger.Warning(fmt.Sprintf("Note for %s is too long (%s), sending without a note", profile.Name, err.Error()))
+		note = ""
+		templateID = ""
+	}
+
+	return &ConnectionRequest{
+		ProfileID:   profile.ID,
+		ProfileURL:  profile.ProfileURL,
+		Name:        profile.Name,
+		Title:       profile.Title,
+		Company:     profile.Company,
+		Note:        note,
+		TemplateID:  templateID,
+		RequestedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,191 @@
+package automation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"linkedin-automation/internal/storage"
+)
+
+// mockNoteGenerator returns a fixed note and records what it was asked for
+type mockNoteGenerator struct {
+	note    string
+	err     error
+	profile storage.Profile
+	context string
+}
+
+func (m *mockNoteGenerator) Generate(profile storage.Profile, context string) (string, error) {
+	m.profile = profile
+	m.context = context
+	return m.note, m.err
+}
+
+var noteGenProfile = storage.Profile{
+	ID:         "jane-doe",
+	Name:       "Jane Doe",
+	Title:      "Engineering Manager",
+	Company:    "Acme",
+	ProfileURL: "https://www.linkedin.com/in/jane-doe/",
+}
+
+func TestPrepareConnectionRequestWithGenerator(t *testing.T) {
+	mock := &mockNoteGenerator{note: "  Hi Jane,   loved your talk on “platform teams” — would be great to connect!  "}
+
+	request, err := prepareConnectionRequestWithGenerator(noteGenProfile, mock, "Reason to connect: platform work", PrepareOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if mock.profile.ID != "jane-doe" || mock.context != "Reason to connect: platform work" {
+		t.Errorf("Generator got profile %q and context %q", mock.profile.ID, mock.context)
+	}
+	if request.TemplateID != GeneratedNoteTemplateID {
+		t.Errorf("Expected template ID %q, got %q", GeneratedNoteTemplateID, request.TemplateID)
+	}
+	// Whitespace is collapsed and the note sanitized like template notes
+	want := `Hi Jane, loved your talk on "platform teams" - would be great to connect!`
+	if request.Note != want {
+		t.Errorf("Expected note %q, got %q", want, request.Note)
+	}
+}
+
+func TestPrepareConnectionRequestWithGeneratorOverLength(t *testing.T) {
+	long := strings.Repeat("Great to meet you at the conference. ", 20)
+
+	t.Run("rejected by default", func(t *testing.T) {
+		_, err := prepareConnectionRequestWithGenerator(noteGenProfile, &mockNoteGenerator{note: long}, "", PrepareOptions{})
+		if !errors.Is(err, ErrMessageTooLong) {
+			t.Fatalf("Expected ErrMessageTooLong, got %v", err)
+		}
+	})
+
+	t.Run("truncated when enabled", func(t *testing.T) {
+		request, err := prepareConnectionRequestWithGenerator(noteGenProfile, &mockNoteGenerator{note: long}, "",
+			PrepareOptions{TruncateGeneratedNotes: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if n := utf8.RuneCountInString(request.Note); n > GetConnectionNoteMaxLength() {
+			t.Errorf("Truncated note is %d characters, limit %d", n, GetConnectionNoteMaxLength())
+		}
+		if !strings.HasSuffix(request.Note, "...") {
+			t.Errorf("Expected truncated note to end with an ellipsis, got %q", request.Note)
+		}
+	})
+
+	t.Run("noteless when enabled", func(t *testing.T) {
+		request, err := prepareConnectionRequestWithGenerator(noteGenProfile, &mockNoteGenerator{note: long}, "",
+			PrepareOptions{NotelessOnOverlength: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if request.Note != "" || request.TemplateID != "" {
+			t.Errorf("Expected a request without a note, got %+v", request)
+		}
+	})
+}
+
+func TestPrepareConnectionRequestWithGeneratorError(t *testing.T) {
+	mock := &mockNoteGenerator{err: errors.New("service unavailable")}
+
+	_, err := prepareConnectionRequestWithGenerator(noteGenProfile, mock, "", PrepareOptions{NotelessOnOverlength: true})
+	if err == nil || !strings.Contains(err.Error(), "service unavailable") {
+		t.Errorf("Expected the generator error, got %v", err)
+	}
+}
+
+func TestPrepareConnectionRequestFromProfileUsesConfiguredGenerator(t *testing.T) {
+	mock := &mockNoteGenerator{note: "Hi Jane, would love to connect and swap notes on platform teams."}
+	SetNoteGenerator(mock)
+	defer SetNoteGenerator(nil)
+
+	request, err := PrepareConnectionRequestFromProfile(noteGenProfile, "conn_generic", TemplateVariables{YourName: "Sam"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.Note != mock.note {
+		t.Errorf("Expected the mock note, got %q", request.Note)
+	}
+	if !strings.Contains(mock.context, "Sender: Sam") {
+		t.Errorf("Expected sender details in the context, got %q", mock.context)
+	}
+}
+
+func TestTemplateNoteGeneratorIsDefault(t *testing.T) {
+	t.Setenv("NOTE_GENERATOR", "")
+
+	request, err := PrepareConnectionRequestFromProfile(noteGenProfile, "conn_generic", TemplateVariables{YourName: "Sam"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.TemplateID != "conn_generic" {
+		t.Errorf("Expected template ID conn_generic, got %q", request.TemplateID)
+	}
+	if !strings.Contains(request.Note, "Jane") {
+		t.Errorf("Expected the rendered template to mention Jane, got %q", request.Note)
+	}
+}
+
+func TestHTTPNoteGenerator(t *testing.T) {
+	var got noteGeneratorRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(noteGeneratorResponse{Note: "Hi " + got.Profile.FirstName + ", let's connect!"})
+	}))
+	defer server.Close()
+
+	generator := HTTPNoteGenerator{Endpoint: server.URL, APIKey: "secret", MaxChars: 300}
+	note, err := generator.Generate(noteGenProfile, "Reason to connect: hiring")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if note != "Hi Jane, let's connect!" {
+		t.Errorf("Unexpected note: %q", note)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Expected bearer auth header, got %q", auth)
+	}
+	if got.Profile.Company != "Acme" || got.Context != "Reason to connect: hiring" || got.MaxChars != 300 {
+		t.Errorf("Unexpected request body: %+v", got)
+	}
+}
+
+func TestHTTPNoteGeneratorErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+		}},
+		{"malformed body", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("not json"))
+		}},
+		{"empty note", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"note": "  "}`))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			if _, err := (HTTPNoteGenerator{Endpoint: server.URL}).Generate(noteGenProfile, ""); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
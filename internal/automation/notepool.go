@@ -0,0 +1,211 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
+)
+
+// DefaultConnectionTemplateID is the connection note used when none is configured
+const DefaultConnectionTemplateID = "conn_generic"
+
+// NoteEntry is a single pre-approved connection note in a NotePool
+type NoteEntry struct {
+	TemplateID string // Connection request template to render (takes precedence over Text)
+	Text       string // Raw note text, may use template variables like {{.FirstName}}
+	Weight     int    // Relative selection weight (0 or less counts as 1)
+}
+
+// NotePool is a library of connection notes; one entry is chosen at random per profile
+// so that consecutive requests don't all carry the same text
+type NotePool []NoteEntry
+
+// Pick selects an entry at random, honoring entry weights.
+// Returns the chosen entry and its index in the pool. An empty pool
+// yields the default connection template.
+func (p NotePool) Pick(r *rand.Rand) (NoteEntry, int) {
+	if len(p) == 0 {
+		return NoteEntry{TemplateID: DefaultConnectionTemplateID}, 0
+	}
+
+	total := 0
+	for _, entry := range p {
+		total += entry.weight()
+	}
+
+	target := r.Intn(total)
+	for i, entry := range p {
+		target -= entry.weight()
+		if target < 0 {
+			return entry, i
+		}
+	}
+
+	// Unreachable with a non-empty pool
+	return p[len(p)-1], len(p) - 1
+}
+
+// weight returns the effective selection weight of an entry
+func (e NoteEntry) weight() int {
+	if e.Weight <= 0 {
+		return 1
+	}
+	return e.Weight
+}
+
+// key identifies which note was used, for tracking per profile
+func (e NoteEntry) key(index int) string {
+	if e.TemplateID != "" {
+		return e.TemplateID
+	}
+	return fmt.Sprintf("pool_note_%d", index+1)
+}
+
+// template resolves the entry into a connection request template
+func (e NoteEntry) template(index int) (*MessageTemplate, error) {
+	if e.TemplateID == "" {
+		if strings.TrimSpace(e.Text) == "" {
+			return nil, fmt.Errorf("note pool entry %d has neither a template ID nor text", index+1)
+		}
+
+		return &MessageTemplate{
+			ID:        e.key(index),
+			Type:      TemplateConnectionRequest,
+			Name:      fmt.Sprintf("Note pool entry %d", index+1),
+			Body:      e.Text,
+			MaxLength: GetConnectionNoteMaxLength(),
+		}, nil
+	}
+
+	template, err := GetTemplateByID(e.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("template not found: %w", err)
+	}
+
+	if template.Type != TemplateConnectionRequest {
+		return nil, fmt.Errorf("template %s is not a connection request template", e.TemplateID)
+	}
+
+	return template, nil
+}
+
+// ParseNotePool parses a comma-separated list of template IDs with optional weights,
+// e.g. "conn_generic:3,conn_brief,conn_industry:2"
+func ParseNotePool(spec string) (NotePool, error) {
+	var pool NotePool
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		entry := NoteEntry{TemplateID: item}
+		if idx := strings.LastIndex(item, ":"); idx != -1 {
+			weight, err := strconv.Atoi(strings.TrimSpace(item[idx+1:]))
+			if err != nil || weight <= 0 {
+				return nil, fmt.Errorf("invalid weight in note pool entry %q", item)
+			}
+			entry.TemplateID = strings.TrimSpace(item[:idx])
+			entry.Weight = weight
+		}
+
+		if _, err := GetTemplateByID(entry.TemplateID); err != nil {
+			return nil, err
+		}
+
+		pool = append(pool, entry)
+	}
+
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("note pool is empty")
+	}
+
+	return pool, nil
+}
+
+// PrepareOptions controls how connection requests are prepared
+type PrepareOptions struct {
+	// NotelessOnOverlength sends the request without a note (with a warning)
+	// instead of dropping it when the rendered note exceeds the limit
+	NotelessOnOverlength bool
+
+	// TruncateGeneratedNotes shortens an over-length note from a NoteGenerator
+	// to the limit instead of rejecting it
+	TruncateGeneratedNotes bool
+
+	// FallbackTemplateID is rendered instead of the chosen template when that
+	// template would print the profile's empty company or title
+	FallbackTemplateID string
+}
+
+// GetPrepareOptions reads preparation options from the environment
+// (NOTELESS_ON_OVERLENGTH, NOTE_GENERATOR_TRUNCATE, NOTE_FALLBACK_TEMPLATE)
+func GetPrepareOptions() PrepareOptions {
+	return PrepareOptions{
+		NotelessOnOverlength:   os.Getenv("NOTELESS_ON_OVERLENGTH") == "true",
+		TruncateGeneratedNotes: os.Getenv("NOTE_GENERATOR_TRUNCATE") == "true",
+		FallbackTemplateID:     os.Getenv("NOTE_FALLBACK_TEMPLATE"),
+	}
+}
+
+// PrepareConnectionRequestFromPool creates a ConnectionRequest using a note picked at random from the pool.
+// The chosen entry is recorded in the request's TemplateID so it can be tracked per profile.
+func PrepareConnectionRequestFromPool(profile storage.Profile, pool NotePool, senderVars TemplateVariables) (*ConnectionRequest, error) {
+	r := utils.SessionRand()
+	return prepareConnectionRequestFromPool(profile, pool, senderVars, GetPrepareOptions(), r)
+}
+
+// prepareConnectionRequestFromPool is PrepareConnectionRequestFromPool with injectable options and random source
+func prepareConnectionRequestFromPool(profile storage.Profile, pool NotePool, senderVars TemplateVariables, opts PrepareOptions, r *rand.Rand) (*ConnectionRequest, error) {
+	entry, index := pool.Pick(r)
+
+	template, err := entry.template(index)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := buildProfileTemplateVars(profile, senderVars)
+
+	templateID := entry.key(index)
+	if fallback, ok := withFallbackTemplate(template, vars, opts.FallbackTemplateID); ok {
+		template, templateID = fallback, fallback.ID
+	}
+
+	// Render the template
+	note, err := RenderTemplate(*template, vars)
+	if err == nil {
+		// Validate length
+		err = ValidateMessageLength(note, TemplateConnectionRequest)
+	}
+
+	if err != nil {
+		if !opts.NotelessOnOverlength || !errors.Is(err, ErrMessageTooLong) {
+			return nil, fmt.Errorf("failed to render template: %w", err)
+		}
+
+		// Better to connect without a note than not at all
+		logger.Warning(fmt.Sprintf("Note for %s is too long (%s), sending without a note", profile.Name, err.Error()))
+		note = ""
+		templateID = ""
+	}
+
+	return &ConnectionRequest{
+		ProfileID:   profile.ID,
+		ProfileURL:  profile.ProfileURL,
+		Name:        profile.Name,
+		Title:       profile.Title,
+		Company:     profile.Company,
+		Note:        note,
+		TemplateID:  templateID,
+		RequestedAt: time.Now(),
+	}, nil
+}
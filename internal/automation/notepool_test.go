@@ -0,0 +1,200 @@
+package automation
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"linkedin-automation/internal/storage"
+)
+
+func TestNotePoolWeightedPick(t *testing.T) {
+	pool := NotePool{
+		{TemplateID: "conn_generic", Weight: 6},
+		{TemplateID: "conn_brief", Weight: 3},
+		{TemplateID: "conn_industry"}, // Zero weight counts as 1
+	}
+
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, len(pool))
+	draws := 10000
+	for i := 0; i < draws; i++ {
+		entry, index := pool.Pick(r)
+		if pool[index].TemplateID != entry.TemplateID {
+			t.Fatalf("Index %d does not match entry %s", index, entry.TemplateID)
+		}
+		counts[index]++
+	}
+
+	expected := []float64{0.6, 0.3, 0.1}
+	for i, want := range expected {
+		got := float64(counts[i]) / float64(draws)
+		if got < want-0.03 || got > want+0.03 {
+			t.Errorf("Entry %d picked %.3f of the time, expected ~%.2f", i, got, want)
+		}
+	}
+}
+
+func TestEmptyNotePoolFallsBackToDefaultTemplate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	entry, _ := NotePool{}.Pick(r)
+	if entry.TemplateID != DefaultConnectionTemplateID {
+		t.Errorf("Expected the default template from an empty pool, got %+v", entry)
+	}
+
+	profile := storage.Profile{ID: "jane-doe", Name: "Jane Doe", Company: "Acme"}
+	request, err := prepareConnectionRequestFromPool(profile, nil, TemplateVariables{}, PrepareOptions{}, r)
+	if err != nil {
+		t.Fatalf("Expected an empty pool to use the default template, got %v", err)
+	}
+	if request.TemplateID != DefaultConnectionTemplateID || request.Note == "" {
+		t.Errorf("Expected a %s note, got %q (%s)", DefaultConnectionTemplateID, request.Note, request.TemplateID)
+	}
+}
+
+func TestParseNotePool(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		wantError   bool
+		wantIDs     []string
+		wantWeights []int
+	}{
+		{"Single template", "conn_generic", false, []string{"conn_generic"}, []int{0}},
+		{"Weights and spaces", " conn_generic:3 , conn_brief ", false, []string{"conn_generic", "conn_brief"}, []int{3, 0}},
+		{"Unknown template", "conn_generic,nope", true, nil, nil},
+		{"Bad weight", "conn_generic:x", true, nil, nil},
+		{"Empty", " , ", true, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := ParseNotePool(tt.spec)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Expected error but got %+v", pool)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(pool) != len(tt.wantIDs) {
+				t.Fatalf("Expected %d entries, got %d", len(tt.wantIDs), len(pool))
+			}
+			for i := range pool {
+				if pool[i].TemplateID != tt.wantIDs[i] || pool[i].Weight != tt.wantWeights[i] {
+					t.Errorf("Entry %d: got %+v", i, pool[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPrepareConnectionRequestFromPoolRespectsLength(t *testing.T) {
+	pool := NotePool{
+		{Text: "Hi {{.FirstName}}, always great to meet people working on {{.Title}} problems. Let's connect!"},
+	}
+	for _, template := range GetConnectionRequestTemplates() {
+		pool = append(pool, NoteEntry{TemplateID: template.ID})
+	}
+
+	profiles := []storage.Profile{
+		{ID: "jane-doe", Name: "Jane Doe", Title: "Engineer", Company: "Acme", ProfileURL: "https://www.linkedin.com/in/jane-doe/"},
+		{ID: "long-name", Name: "Maximiliana " + strings.Repeat("Verylongsurname", 12), Title: strings.Repeat("Principal ", 10), Company: strings.Repeat("Conglomerate ", 10)},
+	}
+
+	senderVars := TemplateVariables{
+		YourName:     "Sam Sender",
+		YourTitle:    "Founder",
+		YourCompany:  "Startup",
+		Industry:     "Software",
+		CustomReason: "I enjoyed your talk.",
+	}
+
+	r := rand.New(rand.NewSource(3))
+	used := make(map[string]bool)
+	for i := 0; i < 200; i++ {
+		for _, profile := range profiles {
+			request, err := prepareConnectionRequestFromPool(profile, pool, senderVars, PrepareOptions{}, r)
+			if err != nil {
+				// Over-length renders must be rejected rather than returned
+				continue
+			}
+
+			if len(request.Note) > ConnectionNoteMaxLength {
+				t.Fatalf("Note for %s exceeds limit: %d characters (template %s)", profile.ID, len(request.Note), request.TemplateID)
+			}
+			if request.TemplateID == "" {
+				t.Fatal("Expected the used note to be tracked in TemplateID")
+			}
+			used[request.TemplateID] = true
+		}
+	}
+
+	if !used["pool_note_1"] {
+		t.Error("Expected the raw text entry to be tracked as pool_note_1")
+	}
+	if len(used) < 3 {
+		t.Errorf("Expected a variety of notes to be used, got %v", used)
+	}
+}
+
+func TestPrepareConnectionRequestFromPoolErrors(t *testing.T) {
+	profile := storage.Profile{ID: "jane-doe", Name: "Jane Doe", Company: "Acme"}
+	r := rand.New(rand.NewSource(1))
+
+	if _, err := prepareConnectionRequestFromPool(profile, NotePool{{TemplateID: "msg_introduction"}}, TemplateVariables{}, PrepareOptions{}, r); err == nil {
+		t.Error("Expected error for non-connection template")
+	}
+
+	if _, err := prepareConnectionRequestFromPool(profile, NotePool{{Weight: 2}}, TemplateVariables{}, PrepareOptions{}, r); err == nil {
+		t.Error("Expected error for entry without template or text")
+	}
+}
+
+func TestPrepareConnectionRequestNotelessOnOverlength(t *testing.T) {
+	profile := storage.Profile{ID: "long-name", Name: "Maximiliana " + strings.Repeat("Verylongsurname", 30), Company: "Acme"}
+	pool := NotePool{{Text: "Hi {{.FirstName}} {{.LastName}}, I'd love to connect!"}}
+	r := rand.New(rand.NewSource(1))
+
+	// Default: an over-length note is a hard error
+	_, err := prepareConnectionRequestFromPool(profile, pool, TemplateVariables{}, PrepareOptions{}, r)
+	if !errors.Is(err, ErrMessageTooLong) {
+		t.Fatalf("Expected ErrMessageTooLong by default, got %v", err)
+	}
+
+	// Fallback: proceed without a note
+	request, err := prepareConnectionRequestFromPool(profile, pool, TemplateVariables{}, PrepareOptions{NotelessOnOverlength: true}, r)
+	if err != nil {
+		t.Fatalf("Expected noteless fallback, got error: %v", err)
+	}
+	if request.Note != "" {
+		t.Errorf("Expected empty note, got %d characters", len(request.Note))
+	}
+	if request.TemplateID != "" {
+		t.Errorf("Expected no template to be tracked for a noteless request, got %q", request.TemplateID)
+	}
+	if request.ProfileID != profile.ID {
+		t.Errorf("Expected profile %q, got %q", profile.ID, request.ProfileID)
+	}
+
+	// Other failures are not masked by the fallback
+	if _, err := prepareConnectionRequestFromPool(profile, NotePool{{TemplateID: "msg_introduction"}}, TemplateVariables{}, PrepareOptions{NotelessOnOverlength: true}, r); err == nil {
+		t.Error("Expected non-length errors to still fail")
+	}
+}
+
+func TestGetPrepareOptions(t *testing.T) {
+	t.Setenv("NOTELESS_ON_OVERLENGTH", "")
+	if GetPrepareOptions().NotelessOnOverlength {
+		t.Error("Expected noteless fallback to be off by default")
+	}
+	t.Setenv("NOTELESS_ON_OVERLENGTH", "true")
+	if !GetPrepareOptions().NotelessOnOverlength {
+		t.Error("Expected NOTELESS_ON_OVERLENGTH=true to enable the fallback")
+	}
+}
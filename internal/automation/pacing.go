@@ -0,0 +1,170 @@
+package automation
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/stealth"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
+)
+
+// Dwell time on a context switch page, in milliseconds
+const (
+	contextSwitchMinDwell = 8000
+	contextSwitchMaxDwell = 20000
+)
+
+// GetProfileBatchSize returns how many profiles are visited between context
+// switches, from PROFILE_BATCH_SIZE (default 0 = never switch)
+func GetProfileBatchSize() int {
+	if v := os.Getenv("PROFILE_BATCH_SIZE"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			return val
+		}
+	}
+	return 0
+}
+
+// ProfilePacer breaks up runs of profile navigations: after every BatchSize
+// profiles it switches context (visits a noise page, see GetNoisePages, and
+// dwells) before the next profile is opened, so the navigation history is not
+// an unbroken run of /in/ pages
+type ProfilePacer struct {
+	BatchSize int // Profiles between context switches (0 disables pacing)
+
+	visited       int
+	switchContext func() error
+}
+
+// NewProfilePacer returns a pacer whose context switches navigate page
+func NewProfilePacer(page *rod.Page, db *storage.Database, batchSize int) *ProfilePacer {
+	return &ProfilePacer{
+		BatchSize: batchSize,
+		switchContext: func() error {
+			return switchContext(page, db, GetNoisePages(), utils.SessionRand())
+		},
+	}
+}
+
+// BeforeProfile is called before each profile navigation. Once a full batch
+// has been visited it runs the context switch and starts a new batch. A failed
+// switch is only logged; the caller carries on with the profile.
+func (p *ProfilePacer) BeforeProfile() {
+	if p == nil || p.BatchSize <= 0 {
+		return
+	}
+
+	if p.visited >= p.BatchSize {
+		logger.Info(fmt.Sprintf("Visited %d profiles, switching context before continuing", p.visited))
+		if err := p.switchContext(); err != nil {
+			logger.Warning("Context switch failed: " + err.Error())
+		}
+		p.visited = 0
+	}
+	p.visited++
+}
+
+// switchContext visits a random noise page and dwells there like a user
+// glancing at their feed
+func switchContext(page *rod.Page, db *storage.Database, pages []string, r *rand.Rand) error {
+	url := PickNoisePage(r, pages)
+	if err := navigate(page, db, url, ""); err != nil {
+		return fmt.Errorf("failed to navigate to %s: %w", url, err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to load %s: %w", url, err)
+	}
+
+	stealth.RandomScroll(page)
+	stealth.IdleNoise(page)
+	stealth.RandomDelay(contextSwitchMinDwell, contextSwitchMaxDwell)
+	return nil
+}
+
+// BatchConfig splits a run's connection requests into batches with a longer
+// rest between them, like a person who sends a few invites, leaves and comes
+// back. The rest comes on top of the per-action cooldown.
+type BatchConfig struct {
+	BatchSize        int // Requests per batch (0 = no batching)
+	BatchRestMinutes int // Average rest between batches; each rest is randomized between half and 1.5x
+}
+
+// GetBatchConfig reads CONNECTION_BATCH_SIZE (default 0 = off) and
+// CONNECTION_BATCH_REST_MINUTES (default 15)
+func GetBatchConfig() BatchConfig {
+	config := BatchConfig{
+		BatchSize:        0,
+		BatchRestMinutes: 15,
+	}
+
+	if v := os.Getenv("CONNECTION_BATCH_SIZE"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			config.BatchSize = val
+		}
+	}
+
+	if v := os.Getenv("CONNECTION_BATCH_REST_MINUTES"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			config.BatchRestMinutes = val
+		}
+	}
+
+	return config
+}
+
+// RequestBatcher rests between batches of connection requests
+type RequestBatcher struct {
+	Config BatchConfig
+
+	processed int
+	r         *rand.Rand
+	rest      func(time.Duration)
+}
+
+// NewRequestBatcher returns a batcher that rests with restUntilStopped
+func NewRequestBatcher(config BatchConfig) *RequestBatcher {
+	return &RequestBatcher{Config: config, r: utils.SessionRand(), rest: restUntilStopped}
+}
+
+// BeforeRequest is called before each connection request is sent. Once a full
+// batch has been processed it rests before starting the next batch, so there
+// is no rest after the last one.
+func (b *RequestBatcher) BeforeRequest() {
+	if b == nil || b.Config.BatchSize <= 0 {
+		return
+	}
+
+	if b.processed >= b.Config.BatchSize {
+		rest := b.restDuration()
+		logger.Info(fmt.Sprintf("Finished a batch of %d connection requests, resting %s before the next one",
+			b.processed, rest.Round(time.Second)))
+		b.rest(rest)
+		b.processed = 0
+	}
+	b.processed++
+}
+
+// restDuration returns a random rest between half and 1.5x BatchRestMinutes
+func (b *RequestBatcher) restDuration() time.Duration {
+	average := time.Duration(b.Config.BatchRestMinutes) * time.Minute
+	return average/2 + time.Duration(b.r.Int63n(int64(average)+1))
+}
+
+// restUntilStopped sleeps for d, returning early if the STOP control file appears
+func restUntilStopped(d time.Duration) {
+	deadline := time.Now().Add(d)
+	for remaining := d; remaining > 0; remaining = time.Until(deadline) {
+		if StopRequested() {
+			logger.Info("Stop requested - ending the rest between batches")
+			return
+		}
+		time.Sleep(min(remaining, pausePollInterval))
+	}
+}
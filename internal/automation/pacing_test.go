@@ -0,0 +1,173 @@
+package automation
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// countingPacer returns a pacer that records the profile index at which each context switch ran
+func countingPacer(batchSize int, visited *int, switches *[]int) *ProfilePacer {
+	return &ProfilePacer{
+		BatchSize: batchSize,
+		switchContext: func() error {
+			*switches = append(*switches, *visited)
+			return nil
+		},
+	}
+}
+
+func TestProfilePacerSwitchesEveryBatch(t *testing.T) {
+	var visited int
+	var switches []int
+	pacer := countingPacer(3, &visited, &switches)
+
+	for visited = 0; visited < 10; visited++ {
+		pacer.BeforeProfile()
+	}
+
+	// Profiles 0-2 form the first batch; the switch runs before profiles 3, 6 and 9
+	expected := []int{3, 6, 9}
+	if len(switches) != len(expected) {
+		t.Fatalf("Expected switches before profiles %v, got %v", expected, switches)
+	}
+	for i := range expected {
+		if switches[i] != expected[i] {
+			t.Errorf("Expected switches before profiles %v, got %v", expected, switches)
+			break
+		}
+	}
+}
+
+func TestProfilePacerDisabled(t *testing.T) {
+	var visited int
+	var switches []int
+	pacer := countingPacer(0, &visited, &switches)
+
+	for visited = 0; visited < 10; visited++ {
+		pacer.BeforeProfile()
+	}
+	if len(switches) != 0 {
+		t.Errorf("Expected no context switches with batch size 0, got %v", switches)
+	}
+
+	// A nil pacer is a no-op
+	var nilPacer *ProfilePacer
+	nilPacer.BeforeProfile()
+}
+
+func TestProfilePacerContinuesAfterFailedSwitch(t *testing.T) {
+	calls := 0
+	pacer := &ProfilePacer{
+		BatchSize: 2,
+		switchContext: func() error {
+			calls++
+			return errors.New("navigation failed")
+		},
+	}
+
+	for i := 0; i < 5; i++ {
+		pacer.BeforeProfile()
+	}
+	if calls != 2 {
+		t.Errorf("Expected a new batch to start after a failed switch (2 switches), got %d", calls)
+	}
+}
+
+func TestGetProfileBatchSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+	}{
+		{"", 0},
+		{"5", 5},
+		{"0", 0},
+		{"-2", 0},
+		{"abc", 0},
+	}
+	for _, test := range tests {
+		t.Setenv("PROFILE_BATCH_SIZE", test.value)
+		if got := GetProfileBatchSize(); got != test.expected {
+			t.Errorf("PROFILE_BATCH_SIZE=%q: got %d, expected %d", test.value, got, test.expected)
+		}
+	}
+}
+
+// recordingBatcher returns a batcher that records the request index at which each rest ran and its length
+func recordingBatcher(config BatchConfig, processed *int, restsAt *[]int, rests *[]time.Duration) *RequestBatcher {
+	return &RequestBatcher{
+		Config: config,
+		r:      rand.New(rand.NewSource(3)),
+		rest: func(d time.Duration) {
+			*restsAt = append(*restsAt, *processed)
+			*rests = append(*rests, d)
+		},
+	}
+}
+
+func TestRequestBatcherRestsBetweenBatches(t *testing.T) {
+	config := BatchConfig{BatchSize: 4, BatchRestMinutes: 20}
+	var processed int
+	var restsAt []int
+	var rests []time.Duration
+	batcher := recordingBatcher(config, &processed, &restsAt, &rests)
+
+	requests := 10
+	for processed = 0; processed < requests; processed++ {
+		batcher.BeforeRequest()
+	}
+
+	// Requests 0-3 form the first batch; rests run before requests 4 and 8, none after the last batch
+	expected := []int{4, 8}
+	if len(restsAt) != len(expected) || restsAt[0] != expected[0] || restsAt[1] != expected[1] {
+		t.Fatalf("Expected rests before requests %v, got %v", expected, restsAt)
+	}
+	if processed != requests {
+		t.Errorf("Expected all %d requests to be processed, got %d", requests, processed)
+	}
+
+	for _, rest := range rests {
+		if rest < 10*time.Minute || rest > 30*time.Minute {
+			t.Errorf("Rest %s outside the 10m-30m range for a 20 minute average", rest)
+		}
+	}
+}
+
+func TestRequestBatcherDisabled(t *testing.T) {
+	var processed int
+	var restsAt []int
+	var rests []time.Duration
+	batcher := recordingBatcher(BatchConfig{BatchRestMinutes: 20}, &processed, &restsAt, &rests)
+
+	for processed = 0; processed < 10; processed++ {
+		batcher.BeforeRequest()
+	}
+	if len(restsAt) != 0 {
+		t.Errorf("Expected no rests with batch size 0, got %v", restsAt)
+	}
+
+	// A nil batcher is a no-op
+	var nilBatcher *RequestBatcher
+	nilBatcher.BeforeRequest()
+}
+
+func TestGetBatchConfig(t *testing.T) {
+	t.Setenv("CONNECTION_BATCH_SIZE", "")
+	t.Setenv("CONNECTION_BATCH_REST_MINUTES", "")
+	if config := GetBatchConfig(); config.BatchSize != 0 || config.BatchRestMinutes != 15 {
+		t.Errorf("Unexpected defaults: %+v", config)
+	}
+
+	t.Setenv("CONNECTION_BATCH_SIZE", "5")
+	t.Setenv("CONNECTION_BATCH_REST_MINUTES", "40")
+	if config := GetBatchConfig(); config.BatchSize != 5 || config.BatchRestMinutes != 40 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	t.Setenv("CONNECTION_BATCH_SIZE", "-1")
+	t.Setenv("CONNECTION_BATCH_REST_MINUTES", "none")
+	if config := GetBatchConfig(); config.BatchSize != 0 || config.BatchRestMinutes != 15 {
+		t.Errorf("Expected invalid values to be ignored, got %+v", config)
+	}
+}
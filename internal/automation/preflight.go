@@ -0,0 +1,290 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"linkedin-automation/internal/browser"
+	"linkedin-automation/pkg/utils"
+)
+
+// proxyDialTimeout bounds the proxy reachability check
+const proxyDialTimeout = 5 * time.Second
+
+// PreflightResult is the outcome of one configuration check
+type PreflightResult struct {
+	Name   string
+	Detail string // Shown when the check passes (e.g. what was verified)
+	Err    error  // nil when the check passed
+}
+
+// Passed reports whether the check passed
+func (r PreflightResult) Passed() bool {
+	return r.Err == nil
+}
+
+// requiredEnvVars must be set for a run to log in
+var requiredEnvVars = []string{"LINKEDIN_EMAIL", "LINKEDIN_PASSWORD"}
+
+// rateLimitEnvVars are the numeric limits that must be positive when set.
+// The Get*Config functions silently fall back to defaults on bad values,
+// so a typo would otherwise go unnoticed.
+var rateLimitEnvVars = []string{
+	"MAX_CONNECTIONS_PER_DAY",
+	"MAX_MESSAGES_PER_DAY",
+	"MAX_SEARCHES_PER_DAY",
+	"COOLDOWN_SECONDS",
+	"MAX_CONNECTIONS_PER_RUN",
+	"MAX_MYNETWORK_CONNECTIONS_PER_RUN",
+}
+
+// RunPreflightChecks validates the environment and configuration without
+// launching the browser, so misconfiguration surfaces before a run starts
+func RunPreflightChecks(dbPath string) []PreflightResult {
+	var results []PreflightResult
+	add := func(name, detail string, err error) {
+		results = append(results, PreflightResult{Name: name, Detail: detail, Err: err})
+	}
+
+	add("Required environment variables", strings.Join(requiredEnvVars, ", "), checkRequiredEnv(os.Getenv, requiredEnvVars))
+	add("Template IDs", "", checkTemplateIDs(os.Getenv))
+	add("Active hours", "", checkSchedule(os.Getenv))
+	add("Rate limits", "", checkRateLimits(os.Getenv))
+	add("Database writable", dbPath, checkDatabaseWritable(dbPath))
+
+	if selectorsFile := os.Getenv("SELECTORS_FILE"); selectorsFile != "" {
+		add("Selector overrides", selectorsFile, utils.LoadSelectorOverrides(selectorsFile))
+	}
+
+	detail, err := checkProxyReachable(os.Getenv("CHROME_FLAGS"), net.DialTimeout)
+	add("Proxy reachable", detail, err)
+
+	return results
+}
+
+// checkRequiredEnv returns an error listing the names that are unset or blank
+func checkRequiredEnv(getenv func(string) string, names []string) error {
+	var missing []string
+	for _, name := range names {
+		if strings.TrimSpace(getenv(name)) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkTemplateIDs verifies every template referenced by the environment exists
+// and is of the right type for where it is used
+func checkTemplateIDs(getenv func(string) string) error {
+	var problems []error
+
+	connectionTemplate := getenv("CONNECTION_TEMPLATE")
+	if connectionTemplate == "" {
+		connectionTemplate = DefaultConnectionTemplateID
+	}
+	if err := checkTemplateType("CONNECTION_TEMPLATE", connectionTemplate, true); err != nil {
+		problems = append(problems, err)
+	}
+
+	if fallback := getenv("NOTE_FALLBACK_TEMPLATE"); fallback != "" {
+		if err := checkTemplateType("NOTE_FALLBACK_TEMPLATE", fallback, true); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	if pool := getenv("CONNECTION_TEMPLATE_POOL"); pool != "" {
+		if _, err := ParseNotePool(pool); err != nil {
+			problems = append(problems, fmt.Errorf("CONNECTION_TEMPLATE_POOL: %w", err))
+		}
+	}
+
+	for _, rotation := range []struct {
+		envVar     string
+		connection bool
+	}{{"CONNECTION_TEMPLATE_ROTATION", true}, {"MESSAGE_TEMPLATE_ROTATION", false}} {
+		if spec := getenv(rotation.envVar); spec != "" {
+			if _, err := parseRotationTemplates(rotation.envVar, spec, rotation.connection); err != nil {
+				problems = append(problems, err)
+			}
+		}
+	}
+	if _, err := ParseRotationStrategy(getenv("TEMPLATE_ROTATION_STRATEGY")); err != nil {
+		problems = append(problems, err)
+	}
+
+	for _, name := range []string{"MESSAGE_TEMPLATE", "SEQUENCE_MESSAGE_TEMPLATE"} {
+		if templateID := getenv(name); templateID != "" {
+			if err := checkTemplateType(name, templateID, false); err != nil {
+				problems = append(problems, err)
+			}
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// checkTemplateType checks that templateID exists and is (or is not) a connection request template
+func checkTemplateType(envVar, templateID string, connection bool) error {
+	template, err := GetTemplateByID(templateID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", envVar, err)
+	}
+	if connection && template.Type != TemplateConnectionRequest {
+		return fmt.Errorf("%s: %s is not a connection request template", envVar, templateID)
+	}
+	if !connection && template.Type == TemplateConnectionRequest {
+		return fmt.Errorf("%s: %s is a connection request template, not a message template", envVar, templateID)
+	}
+	return nil
+}
+
+// checkSchedule verifies ACTIVE_HOURS_START/END parse, leave a non-empty window,
+// and that TARGET_TIMEZONE names a known zone
+func checkSchedule(getenv func(string) string) error {
+	startHour, startMinute := 9, 0
+	endHour, endMinute := 17, 0
+
+	if v := getenv("ACTIVE_HOURS_START"); v != "" {
+		h, m, ok := parseClockTime(v)
+		if !ok {
+			return fmt.Errorf("ACTIVE_HOURS_START %q is not an hour (9) or time (09:30)", v)
+		}
+		startHour, startMinute = h, m
+	}
+
+	if v := getenv("ACTIVE_HOURS_END"); v != "" {
+		h, m, ok := parseClockTime(v)
+		if !ok {
+			return fmt.Errorf("ACTIVE_HOURS_END %q is not an hour (17) or time (17:30)", v)
+		}
+		endHour, endMinute = h, m
+	}
+
+	config := ScheduleConfig{StartHour: startHour, StartMinute: startMinute, EndHour: endHour, EndMinute: endMinute}
+	if config.startOfWindow() >= config.endOfWindow() {
+		return fmt.Errorf("active hours start %s is not before end %s",
+			formatClock(startHour, startMinute), formatClock(endHour, endMinute))
+	}
+
+	if tz := getenv("TARGET_TIMEZONE"); tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("TARGET_TIMEZONE: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkRateLimits verifies every configured rate limit is a positive integer
+func checkRateLimits(getenv func(string) string) error {
+	var problems []error
+	for _, name := range rateLimitEnvVars {
+		v := getenv(name)
+		if v == "" {
+			continue
+		}
+		if val, err := strconv.Atoi(v); err != nil || val <= 0 {
+			problems = append(problems, fmt.Errorf("%s %q must be a positive integer", name, v))
+		}
+	}
+
+	// 0 means "no cap" for these limits
+	for _, name := range []string{"MAX_CONNECTIONS_PER_COMPANY_PER_DAY", "MAX_LIFETIME_CONNECTIONS"} {
+		if v := getenv(name); v != "" {
+			if val, err := strconv.Atoi(v); err != nil || val < 0 {
+				problems = append(problems, fmt.Errorf("%s %q must be 0 or a positive integer", name, v))
+			}
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// checkDatabaseWritable verifies the database file (or, if it does not exist
+// yet, its directory) can be written without touching existing data
+func checkDatabaseWritable(dbPath string) error {
+	if _, err := os.Stat(dbPath); err == nil {
+		f, err := os.OpenFile(dbPath, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("database is not writable: %w", err)
+		}
+		return f.Close()
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("cannot access database: %w", err)
+	}
+
+	dir := filepath.Dir(dbPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create database directory: %w", err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("database directory is not writable: %w", err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// checkProxyReachable dials the proxy set with --proxy-server in CHROME_FLAGS.
+// Passes with a note when no proxy is configured.
+func checkProxyReachable(chromeFlags string, dial func(network, address string, timeout time.Duration) (net.Conn, error)) (string, error) {
+	var proxy string
+	for _, flag := range browser.ParseLaunchFlags(chromeFlags) {
+		if value, ok := strings.CutPrefix(flag, "--proxy-server="); ok {
+			proxy = value
+		}
+	}
+	if proxy == "" {
+		return "no proxy configured", nil
+	}
+
+	address, err := proxyAddress(proxy)
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := dial("tcp", address, proxyDialTimeout)
+	if err != nil {
+		return "", fmt.Errorf("proxy %s unreachable: %w", address, err)
+	}
+	conn.Close()
+	return address, nil
+}
+
+// proxyAddress extracts host:port from a --proxy-server value such as
+// "host:8080", "http://host:8080" or "socks5://host:1080"
+func proxyAddress(proxy string) (string, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid proxy %q", proxy)
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "socks4", "socks5":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
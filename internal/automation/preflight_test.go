@@ -0,0 +1,178 @@
+package automation
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envMap is a getenv backed by a map
+func envMap(values map[string]string) func(string) string {
+	return func(name string) string { return values[name] }
+}
+
+func TestCheckRequiredEnv(t *testing.T) {
+	names := []string{"LINKEDIN_EMAIL", "LINKEDIN_PASSWORD"}
+
+	if err := checkRequiredEnv(envMap(map[string]string{"LINKEDIN_EMAIL": "me@example.com", "LINKEDIN_PASSWORD": "secret"}), names); err != nil {
+		t.Errorf("Expected pass with both set, got %v", err)
+	}
+
+	err := checkRequiredEnv(envMap(map[string]string{"LINKEDIN_EMAIL": "me@example.com", "LINKEDIN_PASSWORD": "  "}), names)
+	if err == nil || !strings.Contains(err.Error(), "LINKEDIN_PASSWORD") || strings.Contains(err.Error(), "LINKEDIN_EMAIL") {
+		t.Errorf("Expected only LINKEDIN_PASSWORD reported missing, got %v", err)
+	}
+}
+
+func TestCheckTemplateIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"defaults", map[string]string{}, ""},
+		{"valid references", map[string]string{
+			"CONNECTION_TEMPLATE":       "conn_brief",
+			"CONNECTION_TEMPLATE_POOL":  "conn_generic:2,conn_brief",
+			"MESSAGE_TEMPLATE":          "msg_introduction",
+			"SEQUENCE_MESSAGE_TEMPLATE": "msg_introduction",
+		}, ""},
+		{"unknown connection template", map[string]string{"CONNECTION_TEMPLATE": "conn_missing"}, "CONNECTION_TEMPLATE"},
+		{"message template used for notes", map[string]string{"CONNECTION_TEMPLATE": "msg_introduction"}, "not a connection request template"},
+		{"connection template used for messages", map[string]string{"MESSAGE_TEMPLATE": "conn_generic"}, "MESSAGE_TEMPLATE"},
+		{"bad pool", map[string]string{"CONNECTION_TEMPLATE_POOL": "conn_generic:x"}, "CONNECTION_TEMPLATE_POOL"},
+		{"unknown sequence template", map[string]string{"SEQUENCE_MESSAGE_TEMPLATE": "msg_missing"}, "SEQUENCE_MESSAGE_TEMPLATE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTemplateIDs(envMap(tt.env))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected pass, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{"defaults", map[string]string{}, false},
+		{"minute precision", map[string]string{"ACTIVE_HOURS_START": "09:30", "ACTIVE_HOURS_END": "17:30"}, false},
+		{"valid timezone", map[string]string{"TARGET_TIMEZONE": "America/New_York"}, false},
+		{"start after end", map[string]string{"ACTIVE_HOURS_START": "18", "ACTIVE_HOURS_END": "9"}, true},
+		{"empty window", map[string]string{"ACTIVE_HOURS_START": "9", "ACTIVE_HOURS_END": "09:00"}, true},
+		{"unparseable start", map[string]string{"ACTIVE_HOURS_START": "9am"}, true},
+		{"out of range end", map[string]string{"ACTIVE_HOURS_END": "25"}, true},
+		{"unknown timezone", map[string]string{"TARGET_TIMEZONE": "Mars/Olympus"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkSchedule(envMap(tt.env)); (err != nil) != tt.wantErr {
+				t.Errorf("checkSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckRateLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{"unset", map[string]string{}, false},
+		{"positive values", map[string]string{"MAX_CONNECTIONS_PER_DAY": "14", "COOLDOWN_SECONDS": "30"}, false},
+		{"company cap disabled", map[string]string{"MAX_CONNECTIONS_PER_COMPANY_PER_DAY": "0"}, false},
+		{"zero daily limit", map[string]string{"MAX_CONNECTIONS_PER_DAY": "0"}, true},
+		{"negative cooldown", map[string]string{"COOLDOWN_SECONDS": "-5"}, true},
+		{"not a number", map[string]string{"MAX_MESSAGES_PER_DAY": "fifty"}, true},
+		{"negative company cap", map[string]string{"MAX_CONNECTIONS_PER_COMPANY_PER_DAY": "-1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkRateLimits(envMap(tt.env)); (err != nil) != tt.wantErr {
+				t.Errorf("checkRateLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckDatabaseWritable(t *testing.T) {
+	dir := t.TempDir()
+
+	// New database in a directory that does not exist yet
+	if err := checkDatabaseWritable(filepath.Join(dir, "data", "new.db")); err != nil {
+		t.Errorf("Expected a new database path to pass, got %v", err)
+	}
+
+	// Existing writable database is left untouched
+	existing := filepath.Join(dir, "existing.db")
+	if err := os.WriteFile(existing, []byte("data"), 0644); err != nil {
+		t.Fatalf("Failed to write database file: %v", err)
+	}
+	if err := checkDatabaseWritable(existing); err != nil {
+		t.Errorf("Expected existing database to pass, got %v", err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "data" {
+		t.Errorf("Database contents changed to %q", data)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("Permission checks do not apply to root")
+	}
+	readOnly := filepath.Join(dir, "readonly")
+	if err := os.Mkdir(readOnly, 0555); err != nil {
+		t.Fatalf("Failed to create read-only directory: %v", err)
+	}
+	if err := checkDatabaseWritable(filepath.Join(readOnly, "app.db")); err == nil {
+		t.Error("Expected a read-only directory to fail")
+	}
+}
+
+func TestCheckProxyReachable(t *testing.T) {
+	var dialed string
+	okDial := func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dialed = address
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}
+	failDial := func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	if detail, err := checkProxyReachable("--disable-gpu", failDial); err != nil || detail != "no proxy configured" {
+		t.Errorf("Expected pass without a proxy, got %q, %v", detail, err)
+	}
+
+	if _, err := checkProxyReachable("--proxy-server=socks5://127.0.0.1", okDial); err != nil {
+		t.Errorf("Expected reachable proxy to pass, got %v", err)
+	}
+	if dialed != "127.0.0.1:1080" {
+		t.Errorf("Expected default socks port to be dialed, got %s", dialed)
+	}
+
+	if _, err := checkProxyReachable("--proxy-server=proxy.local:3128", failDial); err == nil || !strings.Contains(err.Error(), "proxy.local:3128") {
+		t.Errorf("Expected unreachable proxy to fail, got %v", err)
+	}
+
+	if _, err := checkProxyReachable("--proxy-server=http://:8080", okDial); err == nil {
+		t.Error("Expected a proxy without a host to fail")
+	}
+}
@@ -0,0 +1,44 @@
+package automation
+
+import (
+	"fmt"
+
+	"linkedin-automation/internal/storage"
+)
+
+// previewDaysBack matches the profile window used when sending connection requests
+const previewDaysBack = 30
+
+// NotePreview is a connection note rendered for a profile without sending it
+type NotePreview struct {
+	ProfileID string
+	Name      string
+	Note      string
+	Err       error // Set when the note could not be rendered for this profile
+}
+
+// PreviewConnectionNotes renders connection notes for up to limit uncontacted
+// profiles from the database, without a browser and without sending anything.
+// Render errors are captured per profile so one bad profile doesn't hide the rest.
+func PreviewConnectionNotes(db *storage.Database, templateID string, senderVars TemplateVariables, limit int) ([]NotePreview, error) {
+	profiles, err := db.GetRecentProfiles(limit, previewDaysBack)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profiles: %w", err)
+	}
+
+	previews := make([]NotePreview, 0, len(profiles))
+	for _, profile := range profiles {
+		preview := NotePreview{ProfileID: profile.ID, Name: profile.Name}
+
+		request, err := PrepareConnectionRequestFromProfile(profile, templateID, senderVars)
+		if err != nil {
+			preview.Err = err
+		} else {
+			preview.Note = request.Note
+		}
+
+		previews = append(previews, preview)
+	}
+
+	return previews, nil
+}
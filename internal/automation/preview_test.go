@@ -0,0 +1,89 @@
+package automation
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+func TestPreviewConnectionNotes(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_preview.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	seed := []storage.Profile{
+		{ID: "jane-doe", Name: "Jane Doe", Title: "Engineer", Company: "Acme", VisitedAt: now},
+		// A company name long enough to push the note over the limit
+		{ID: "long-co", Name: "Long Company", Title: "CTO", Company: strings.Repeat("Globex ", 60), VisitedAt: now.Add(-time.Minute)},
+	}
+	for _, p := range seed {
+		p.ProfileURL = "https://www.linkedin.com/in/" + p.ID + "/"
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+	}
+
+	senderVars := TemplateVariables{YourName: "Sam", YourTitle: "Recruiter", YourCompany: "Initech"}
+	previews, err := PreviewConnectionNotes(db, "conn_generic", senderVars, 10)
+	if err != nil {
+		t.Fatalf("PreviewConnectionNotes failed: %v", err)
+	}
+
+	if len(previews) != 2 {
+		t.Fatalf("Expected 2 previews, got %d", len(previews))
+	}
+
+	byID := make(map[string]NotePreview)
+	for _, p := range previews {
+		byID[p.ProfileID] = p
+	}
+
+	jane := byID["jane-doe"]
+	if jane.Err != nil {
+		t.Fatalf("Expected Jane's note to render, got error: %v", jane.Err)
+	}
+	if jane.Name != "Jane Doe" || !strings.Contains(jane.Note, "Jane") || !strings.Contains(jane.Note, "Acme") {
+		t.Errorf("Expected a personalized note for Jane, got %q", jane.Note)
+	}
+
+	long := byID["long-co"]
+	if long.Err == nil {
+		t.Errorf("Expected a render error for the over-long note, got %q", long.Note)
+	}
+	if long.Note != "" {
+		t.Errorf("Expected no note when rendering failed, got %q", long.Note)
+	}
+
+	// Nothing is sent, so the same profiles remain uncontacted
+	again, err := PreviewConnectionNotes(db, "conn_generic", senderVars, 10)
+	if err != nil || len(again) != 2 {
+		t.Errorf("Expected previewing to leave profiles uncontacted, got %d previews (err %v)", len(again), err)
+	}
+}
+
+func TestPreviewConnectionNotesUnknownTemplate(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_preview.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	profile := storage.Profile{ID: "jane-doe", Name: "Jane Doe", ProfileURL: "https://www.linkedin.com/in/jane-doe/", VisitedAt: time.Now()}
+	if err := db.SaveProfile(profile); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+
+	previews, err := PreviewConnectionNotes(db, "no_such_template", TemplateVariables{}, 10)
+	if err != nil {
+		t.Fatalf("Expected per-profile errors, not a failure: %v", err)
+	}
+	if len(previews) != 1 || previews[0].Err == nil {
+		t.Errorf("Expected the unknown template error captured on the profile, got %+v", previews)
+	}
+}
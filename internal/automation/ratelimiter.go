@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"linkedin-automation/internal/logger"
@@ -25,8 +27,20 @@ type RateLimitConfig struct {
 	MaxMessagesPerDay      int
 	MaxSearchesPerDay      int
 	CooldownBetweenActions time.Duration // Cooldown between individual actions
+
+	// Cap on invites to one company per day so outreach doesn't look like a targeted attack (0 = no cap)
+	MaxConnectionsPerCompanyPerDay int
+
+	// Hard ceiling on connection requests ever sent, to ease a new account in (0 = unlimited)
+	MaxLifetimeConnections int
+
+	// Let today's limits grow by yesterday's unused quota, up to carryOverCap × the base limit
+	CarryOverUnused bool
 }
 
+// carryOverCap bounds the effective daily limit when CarryOverUnused is set
+const carryOverCap = 1.5
+
 // RateLimitError represents a rate limit exceeded error
 type RateLimitError struct {
 	TaskType  TaskType
@@ -68,6 +82,22 @@ func GetDefaultRateLimitConfig() RateLimitConfig {
 		}
 	}
 
+	if envCompany := os.Getenv("MAX_CONNECTIONS_PER_COMPANY_PER_DAY"); envCompany != "" {
+		if val, err := strconv.Atoi(envCompany); err == nil && val > 0 {
+			config.MaxConnectionsPerCompanyPerDay = val
+		}
+	}
+
+	if envLifetime := os.Getenv("MAX_LIFETIME_CONNECTIONS"); envLifetime != "" {
+		if val, err := strconv.Atoi(envLifetime); err == nil && val > 0 {
+			config.MaxLifetimeConnections = val
+		}
+	}
+
+	if os.Getenv("RATE_LIMIT_CARRY_OVER") == "true" {
+		config.CarryOverUnused = true
+	}
+
 	if envCooldown := os.Getenv("COOLDOWN_SECONDS"); envCooldown != "" {
 		if val, err := strconv.Atoi(envCooldown); err == nil && val > 0 {
 			config.CooldownBetweenActions = time.Duration(val) * time.Second
@@ -81,24 +111,32 @@ func GetDefaultRateLimitConfig() RateLimitConfig {
 type RateLimiter struct {
 	db             *storage.Database
 	config         RateLimitConfig
+	clock          Clock
 	lastActionTime time.Time
+
+	// lastRecorded is when an action was last recorded (zero if none yet).
+	// Guarded by mu because health checks read it from another goroutine.
+	mu           sync.Mutex
+	lastRecorded time.Time
 }
 
 // NewRateLimiter creates a new rate limiter instance
 func NewRateLimiter(db *storage.Database) *RateLimiter {
-	return &RateLimiter{
-		db:             db,
-		config:         GetDefaultRateLimitConfig(),
-		lastActionTime: time.Now().Add(-1 * time.Hour), // Allow immediate first action
-	}
+	return NewRateLimiterWithClock(db, GetDefaultRateLimitConfig(), SystemClock)
 }
 
 // NewRateLimiterWithConfig creates a rate limiter with custom config
 func NewRateLimiterWithConfig(db *storage.Database, config RateLimitConfig) *RateLimiter {
+	return NewRateLimiterWithClock(db, config, SystemClock)
+}
+
+// NewRateLimiterWithClock creates a rate limiter that reads time from clock
+func NewRateLimiterWithClock(db *storage.Database, config RateLimitConfig, clock Clock) *RateLimiter {
 	return &RateLimiter{
 		db:             db,
 		config:         config,
-		lastActionTime: time.Now().Add(-1 * time.Hour),
+		clock:          clock,
+		lastActionTime: clock.Now().Add(-1 * time.Hour), // Allow immediate first action
 	}
 }
 
@@ -106,42 +144,151 @@ func NewRateLimiterWithConfig(db *storage.Database, config RateLimitConfig) *Rat
 // Returns error if limit exceeded, nil otherwise
 func (rl *RateLimiter) CheckDailyLimit(taskType TaskType) error {
 	// Get today's rate limit from database
-	limit, err := rl.db.GetTodayRateLimit()
+	limit, err := rl.todayUsage()
 	if err != nil {
 		return fmt.Errorf("failed to get rate limit: %w", err)
 	}
 
-	// Check limit based on task type
+	if taskType == TaskConnection {
+		if err := rl.checkLifetimeLimit(); err != nil {
+			return err
+		}
+	}
+
+	current, err := taskCount(limit, taskType)
+	if err != nil {
+		return err
+	}
+
+	max, err := rl.effectiveLimit(taskType)
+	if err != nil {
+		return err
+	}
+
+	if current >= max {
+		return &RateLimitError{
+			TaskType:  taskType,
+			Current:   current,
+			Limit:     max,
+			ResetTime: rl.getNextMidnight(),
+		}
+	}
+
+	return nil
+}
+
+// todayUsage returns the counts recorded on the limiter clock's current day
+func (rl *RateLimiter) todayUsage() (*storage.RateLimit, error) {
+	return rl.db.GetDailyStats(rl.clock.Now().Format("2006-01-02"))
+}
+
+// taskCount returns the number of taskType actions recorded in limit
+func taskCount(limit *storage.RateLimit, taskType TaskType) (int, error) {
 	switch taskType {
 	case TaskConnection:
-		if limit.ConnectionCount >= rl.config.MaxConnectionsPerDay {
-			return &RateLimitError{
-				TaskType:  TaskConnection,
-				Current:   limit.ConnectionCount,
-				Limit:     rl.config.MaxConnectionsPerDay,
-				ResetTime: rl.getNextMidnight(),
-			}
-		}
+		return limit.ConnectionCount, nil
 	case TaskMessage:
-		if limit.MessageCount >= rl.config.MaxMessagesPerDay {
-			return &RateLimitError{
-				TaskType:  TaskMessage,
-				Current:   limit.MessageCount,
-				Limit:     rl.config.MaxMessagesPerDay,
-				ResetTime: rl.getNextMidnight(),
-			}
-		}
+		return limit.MessageCount, nil
 	case TaskSearch:
-		if limit.SearchCount >= rl.config.MaxSearchesPerDay {
-			return &RateLimitError{
-				TaskType:  TaskSearch,
-				Current:   limit.SearchCount,
-				Limit:     rl.config.MaxSearchesPerDay,
-				ResetTime: rl.getNextMidnight(),
-			}
-		}
+		return limit.SearchCount, nil
 	default:
-		return fmt.Errorf("unknown task type: %s", taskType)
+		return 0, fmt.Errorf("unknown task type: %s", taskType)
+	}
+}
+
+// baseLimit returns the configured daily limit for taskType
+func (rl *RateLimiter) baseLimit(taskType TaskType) (int, error) {
+	switch taskType {
+	case TaskConnection:
+		return rl.config.MaxConnectionsPerDay, nil
+	case TaskMessage:
+		return rl.config.MaxMessagesPerDay, nil
+	case TaskSearch:
+		return rl.config.MaxSearchesPerDay, nil
+	default:
+		return 0, fmt.Errorf("unknown task type: %s", taskType)
+	}
+}
+
+// effectiveLimit returns today's limit for taskType: the base limit, plus
+// yesterday's unused quota when CarryOverUnused is set. Only a day the bot
+// actually worked carries anything: a day without a recorded action (a
+// weekend under WEEKDAYS_ONLY, or before the first run) leaves nothing unused.
+func (rl *RateLimiter) effectiveLimit(taskType TaskType) (int, error) {
+	base, err := rl.baseLimit(taskType)
+	if err != nil || !rl.config.CarryOverUnused {
+		return base, err
+	}
+
+	yesterday := rl.clock.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	stats, err := rl.db.GetDailyStats(yesterday)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get yesterday's usage: %w", err)
+	}
+
+	if stats.ConnectionCount+stats.MessageCount+stats.SearchCount == 0 {
+		return base, nil
+	}
+
+	used, err := taskCount(stats, taskType)
+	if err != nil {
+		return 0, err
+	}
+
+	return carryOverLimit(base, used), nil
+}
+
+// carryOverLimit returns base plus the part of yesterday's base quota that went
+// unused, capped at carryOverCap × base. Yesterday is measured against the base
+// limit, not its own carried-over limit, so unused quota never compounds and a
+// day that borrowed beyond its base leaves nothing to carry.
+func carryOverLimit(base, usedYesterday int) int {
+	unused := base - usedYesterday
+	if unused <= 0 {
+		return base
+	}
+
+	limit := base + unused
+	if ceiling := int(float64(base) * carryOverCap); limit > ceiling {
+		limit = ceiling
+	}
+	return limit
+}
+
+// checkLifetimeLimit blocks connection requests once MaxLifetimeConnections have been sent
+func (rl *RateLimiter) checkLifetimeLimit() error {
+	if rl.config.MaxLifetimeConnections <= 0 {
+		return nil
+	}
+
+	total, err := rl.db.CountTotalConnectionsSent()
+	if err != nil {
+		return fmt.Errorf("failed to count lifetime connections: %w", err)
+	}
+
+	if total >= rl.config.MaxLifetimeConnections {
+		logger.Warning(fmt.Sprintf("Lifetime connection limit reached: %d/%d requests sent - no more connection requests will be sent (raise MAX_LIFETIME_CONNECTIONS to continue)",
+			total, rl.config.MaxLifetimeConnections))
+		return fmt.Errorf("%w: %d/%d", ErrLifetimeLimit, total, rl.config.MaxLifetimeConnections)
+	}
+
+	return nil
+}
+
+// CheckCompanyLimit checks if today's connection requests to a company have reached
+// MaxConnectionsPerCompanyPerDay. Profiles without a company are never capped.
+func (rl *RateLimiter) CheckCompanyLimit(company string) error {
+	if rl.config.MaxConnectionsPerCompanyPerDay <= 0 || strings.TrimSpace(company) == "" {
+		return nil
+	}
+
+	count, err := rl.db.CountConnectionsToCompanyToday(company)
+	if err != nil {
+		return fmt.Errorf("failed to count connections to company: %w", err)
+	}
+
+	if count >= rl.config.MaxConnectionsPerCompanyPerDay {
+		return fmt.Errorf("company cap reached for %s: %d/%d today", company, count, rl.config.MaxConnectionsPerCompanyPerDay)
 	}
 
 	return nil
@@ -149,15 +296,15 @@ func (rl *RateLimiter) CheckDailyLimit(taskType TaskType) error {
 
 // ApplyCooldown waits for the cooldown period since last action
 func (rl *RateLimiter) ApplyCooldown() {
-	timeSinceLastAction := time.Since(rl.lastActionTime)
+	timeSinceLastAction := rl.clock.Now().Sub(rl.lastActionTime)
 
 	if timeSinceLastAction < rl.config.CooldownBetweenActions {
 		waitTime := rl.config.CooldownBetweenActions - timeSinceLastAction
 		logger.Info(fmt.Sprintf("Applying cooldown: waiting %.1f seconds", waitTime.Seconds()))
-		time.Sleep(waitTime)
+		rl.clock.Sleep(waitTime)
 	}
 
-	rl.lastActionTime = time.Now()
+	rl.lastActionTime = rl.clock.Now()
 }
 
 // RecordAction records that an action was performed and increments the counter
@@ -165,65 +312,72 @@ func (rl *RateLimiter) RecordAction(taskType TaskType) error {
 	// Apply cooldown before action
 	rl.ApplyCooldown()
 
-	// Increment the counter in database
-	var err error
+	// Increment the counter in database, on the limiter clock's day
+	var counter string
 	switch taskType {
 	case TaskConnection:
-		err = rl.db.IncrementConnectionCount()
+		counter = storage.CounterConnections
 	case TaskMessage:
-		err = rl.db.IncrementMessageCount()
+		counter = storage.CounterMessages
 	case TaskSearch:
-		err = rl.db.IncrementSearchCount()
+		counter = storage.CounterSearches
 	default:
 		return fmt.Errorf("unknown task type: %s", taskType)
 	}
 
-	if err != nil {
+	if err := rl.db.IncrementDailyCount(rl.clock.Now().Format("2006-01-02"), counter); err != nil {
 		return fmt.Errorf("failed to record action: %w", err)
 	}
 
+	rl.mu.Lock()
+	rl.lastRecorded = rl.clock.Now()
+	rl.mu.Unlock()
+
 	return nil
 }
 
+// LastActionTime returns when an action was last recorded, or the zero time if none has been
+func (rl *RateLimiter) LastActionTime() time.Time {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.lastRecorded
+}
+
 // GetRemainingQuota returns how many actions are remaining for a task type
 func (rl *RateLimiter) GetRemainingQuota(taskType TaskType) (int, error) {
-	limit, err := rl.db.GetTodayRateLimit()
+	limit, err := rl.todayUsage()
 	if err != nil {
 		return 0, err
 	}
 
-	switch taskType {
-	case TaskConnection:
-		return rl.config.MaxConnectionsPerDay - limit.ConnectionCount, nil
-	case TaskMessage:
-		return rl.config.MaxMessagesPerDay - limit.MessageCount, nil
-	case TaskSearch:
-		return rl.config.MaxSearchesPerDay - limit.SearchCount, nil
-	default:
-		return 0, fmt.Errorf("unknown task type: %s", taskType)
+	current, err := taskCount(limit, taskType)
+	if err != nil {
+		return 0, err
+	}
+
+	max, err := rl.effectiveLimit(taskType)
+	if err != nil {
+		return 0, err
 	}
+
+	return max - current, nil
 }
 
 // GetUsagePercentage returns the percentage of daily quota used
 func (rl *RateLimiter) GetUsagePercentage(taskType TaskType) (float64, error) {
-	limit, err := rl.db.GetTodayRateLimit()
+	limit, err := rl.todayUsage()
 	if err != nil {
 		return 0, err
 	}
 
-	var current, max int
-	switch taskType {
-	case TaskConnection:
-		current = limit.ConnectionCount
-		max = rl.config.MaxConnectionsPerDay
-	case TaskMessage:
-		current = limit.MessageCount
-		max = rl.config.MaxMessagesPerDay
-	case TaskSearch:
-		current = limit.SearchCount
-		max = rl.config.MaxSearchesPerDay
-	default:
-		return 0, fmt.Errorf("unknown task type: %s", taskType)
+	current, err := taskCount(limit, taskType)
+	if err != nil {
+		return 0, err
+	}
+
+	max, err := rl.effectiveLimit(taskType)
+	if err != nil {
+		return 0, err
 	}
 
 	if max == 0 {
@@ -245,13 +399,15 @@ func (rl *RateLimiter) ShouldWarnAboutLimit(taskType TaskType) (bool, error) {
 
 // getNextMidnight returns the time of the next midnight (when limits reset)
 func (rl *RateLimiter) getNextMidnight() time.Time {
-	now := time.Now()
-	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	now := rl.clock.Now()
+	// Step the calendar date, then rebuild midnight so DST days (23h/25h) are handled
+	tomorrow := now.AddDate(0, 0, 1)
+	return time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, now.Location())
 }
 
 // GetDailyStats returns a summary of today's rate limit usage
 func (rl *RateLimiter) GetDailyStats() (string, error) {
-	limit, err := rl.db.GetTodayRateLimit()
+	limit, err := rl.todayUsage()
 	if err != nil {
 		return "", err
 	}
@@ -260,14 +416,19 @@ func (rl *RateLimiter) GetDailyStats() (string, error) {
 	msgPercent, _ := rl.GetUsagePercentage(TaskMessage)
 	searchPercent, _ := rl.GetUsagePercentage(TaskSearch)
 
+	// Show the limits in force today, including any carried-over quota
+	maxConnections, _ := rl.effectiveLimit(TaskConnection)
+	maxMessages, _ := rl.effectiveLimit(TaskMessage)
+	maxSearches, _ := rl.effectiveLimit(TaskSearch)
+
 	stats := fmt.Sprintf(`Daily Rate Limit Usage:
   Connections: %d/%d (%.1f%%)
   Messages:    %d/%d (%.1f%%)
   Searches:    %d/%d (%.1f%%)
   Resets at:   %s`,
-		limit.ConnectionCount, rl.config.MaxConnectionsPerDay, connPercent,
-		limit.MessageCount, rl.config.MaxMessagesPerDay, msgPercent,
-		limit.SearchCount, rl.config.MaxSearchesPerDay, searchPercent,
+		limit.ConnectionCount, maxConnections, connPercent,
+		limit.MessageCount, maxMessages, msgPercent,
+		limit.SearchCount, maxSearches, searchPercent,
 		rl.getNextMidnight().Format("15:04:05"))
 
 	return stats, nil
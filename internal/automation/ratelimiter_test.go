@@ -0,0 +1,394 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+func TestCheckCompanyLimit(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_ratelimiter.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	// Two invites already sent to Acme today, one to Globex
+	seed := []storage.Profile{
+		{ID: "acme-1", Name: "A One", Company: "Acme", ProfileURL: "https://www.linkedin.com/in/acme-1/"},
+		{ID: "acme-2", Name: "A Two", Company: "Acme", ProfileURL: "https://www.linkedin.com/in/acme-2/"},
+		{ID: "globex-1", Name: "G One", Company: "Globex", ProfileURL: "https://www.linkedin.com/in/globex-1/"},
+	}
+	for _, p := range seed {
+		p.VisitedAt = time.Now()
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+		req := storage.ConnectionRequest{ProfileID: p.ID, SentAt: time.Now(), Status: "pending", CreatedAt: time.Now()}
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+
+	config := GetDefaultRateLimitConfig()
+	config.MaxConnectionsPerCompanyPerDay = 2
+	rl := NewRateLimiterWithConfig(db, config)
+
+	if err := rl.CheckCompanyLimit("Acme"); err == nil {
+		t.Error("Expected Acme to be at its daily cap")
+	}
+	if err := rl.CheckCompanyLimit("Globex"); err != nil {
+		t.Errorf("Expected Globex to be allowed: %v", err)
+	}
+	if err := rl.CheckCompanyLimit("Initech"); err != nil {
+		t.Errorf("Expected a new company to be allowed: %v", err)
+	}
+	if err := rl.CheckCompanyLimit(""); err != nil {
+		t.Errorf("Profiles without a company should never be capped: %v", err)
+	}
+
+	// No cap configured
+	config.MaxConnectionsPerCompanyPerDay = 0
+	rl = NewRateLimiterWithConfig(db, config)
+	if err := rl.CheckCompanyLimit("Acme"); err != nil {
+		t.Errorf("Expected no cap when MaxConnectionsPerCompanyPerDay is 0: %v", err)
+	}
+}
+
+func TestGetDefaultRateLimitConfigCompanyCap(t *testing.T) {
+	t.Setenv("MAX_CONNECTIONS_PER_COMPANY_PER_DAY", "")
+	if limit := GetDefaultRateLimitConfig().MaxConnectionsPerCompanyPerDay; limit != 0 {
+		t.Errorf("Expected no company cap by default, got %d", limit)
+	}
+
+	t.Setenv("MAX_CONNECTIONS_PER_COMPANY_PER_DAY", "3")
+	if limit := GetDefaultRateLimitConfig().MaxConnectionsPerCompanyPerDay; limit != 3 {
+		t.Errorf("Expected company cap 3, got %d", limit)
+	}
+}
+
+func TestGetNextMidnightUsesClock(t *testing.T) {
+	clock := NewMockClock(time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC))
+	rl := NewRateLimiterWithClock(nil, GetDefaultRateLimitConfig(), clock)
+
+	expected := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := rl.getNextMidnight(); !got.Equal(expected) {
+		t.Errorf("Expected next midnight %v, got %v", expected, got)
+	}
+
+	// Exactly at midnight the next reset is a full day away
+	clock.Advance(time.Second)
+	expected = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	if got := rl.getNextMidnight(); !got.Equal(expected) {
+		t.Errorf("Expected next midnight %v, got %v", expected, got)
+	}
+}
+
+func TestGetNextMidnightAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	// 01:00 on the spring-forward day: the clock skips 02:00-03:00, so the
+	// next midnight is 23h away by the wall clock but only 22h in real time
+	clock := NewMockClock(time.Date(2026, 3, 8, 1, 0, 0, 0, ny))
+	rl := NewRateLimiterWithClock(nil, GetDefaultRateLimitConfig(), clock)
+
+	got := rl.getNextMidnight()
+	expected := time.Date(2026, 3, 9, 0, 0, 0, 0, ny)
+	if !got.Equal(expected) {
+		t.Errorf("Expected next midnight %v, got %v", expected, got)
+	}
+	if elapsed := got.Sub(clock.Now()); elapsed != 22*time.Hour {
+		t.Errorf("Expected 22h until reset on the 23h day, got %v", elapsed)
+	}
+}
+
+func TestApplyCooldownUsesClock(t *testing.T) {
+	clock := NewMockClock(time.Date(2025, 12, 30, 10, 0, 0, 0, time.UTC))
+	config := GetDefaultRateLimitConfig()
+	config.CooldownBetweenActions = time.Hour
+	rl := NewRateLimiterWithClock(nil, config, clock)
+
+	// The first action is allowed immediately; had the real clock been used
+	// with a one-hour cooldown, this test would block
+	start := time.Now()
+	rl.ApplyCooldown()
+	clock.Advance(2 * time.Hour)
+	rl.ApplyCooldown()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected no real waiting once the mock clock passes the cooldown, waited %v", elapsed)
+	}
+	if !rl.lastActionTime.Equal(clock.Now()) {
+		t.Errorf("Expected last action time %v, got %v", clock.Now(), rl.lastActionTime)
+	}
+}
+
+func TestApplyCooldownWaitsOnClock(t *testing.T) {
+	clock := NewMockClock(time.Date(2025, 12, 30, 10, 0, 0, 0, time.UTC))
+	config := GetDefaultRateLimitConfig()
+	config.CooldownBetweenActions = time.Hour
+	rl := NewRateLimiterWithClock(nil, config, clock)
+
+	rl.ApplyCooldown()
+	clock.Advance(20 * time.Minute)
+
+	// The remaining 40 minutes pass on the mock clock, not in real time
+	start := time.Now()
+	rl.ApplyCooldown()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the cooldown to wait on the clock, waited %v", elapsed)
+	}
+	want := time.Date(2025, 12, 30, 11, 0, 0, 0, time.UTC)
+	if !clock.Now().Equal(want) {
+		t.Errorf("Expected the clock at %v after the cooldown, got %v", want, clock.Now())
+	}
+}
+
+func TestRateLimiterCountsOnClockDay(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_ratelimiter.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	clock := NewMockClock(time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC))
+	config := GetDefaultRateLimitConfig()
+	config.MaxConnectionsPerDay = 2
+	config.CooldownBetweenActions = 0
+	rl := NewRateLimiterWithClock(db, config, clock)
+
+	// Counts of the wall-clock day are not the clock's day
+	for i := 0; i < 5; i++ {
+		if err := db.IncrementConnectionCount(); err != nil {
+			t.Fatalf("Failed to record connection: %v", err)
+		}
+	}
+	if err := rl.CheckDailyLimit(TaskConnection); err != nil {
+		t.Fatalf("Expected the clock's day to start empty: %v", err)
+	}
+
+	// Actions are recorded on the clock's day
+	for i := 0; i < 2; i++ {
+		if err := rl.RecordAction(TaskConnection); err != nil {
+			t.Fatalf("Failed to record action: %v", err)
+		}
+	}
+	stats, err := db.GetDailyStats("2025-03-10")
+	if err != nil {
+		t.Fatalf("Failed to get daily stats: %v", err)
+	}
+	if stats.ConnectionCount != 2 {
+		t.Errorf("Expected 2 connections on 2025-03-10, got %d", stats.ConnectionCount)
+	}
+	if remaining, _ := rl.GetRemainingQuota(TaskConnection); remaining != 0 {
+		t.Errorf("Expected the clock's day to be used up, %d remaining", remaining)
+	}
+
+	// The next day on the clock starts over
+	clock.Advance(24 * time.Hour)
+	if err := rl.CheckDailyLimit(TaskConnection); err != nil {
+		t.Errorf("Expected a fresh quota on the clock's next day: %v", err)
+	}
+}
+
+func TestGetNextMidnightFallBack(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	// Midnight starting the fall-back day: the day has 25 real hours
+	clock := NewMockClock(time.Date(2026, 11, 1, 0, 0, 0, 0, ny))
+	rl := NewRateLimiterWithClock(nil, GetDefaultRateLimitConfig(), clock)
+
+	got := rl.getNextMidnight()
+	expected := time.Date(2026, 11, 2, 0, 0, 0, 0, ny)
+	if !got.Equal(expected) || got.Hour() != 0 {
+		t.Errorf("Expected next midnight %v, got %v", expected, got)
+	}
+	if elapsed := got.Sub(clock.Now()); elapsed != 25*time.Hour {
+		t.Errorf("Expected 25h until reset on the fall-back day, got %v", elapsed)
+	}
+}
+
+func TestCheckDailyLimitLifetimeCap(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_lifetime.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	// Three invites sent over the account's life, two of them on earlier days
+	sentAt := []time.Time{time.Now().AddDate(0, 0, -30), time.Now().AddDate(0, 0, -2), time.Now()}
+	sendInvite := func(id string, at time.Time) {
+		t.Helper()
+		p := storage.Profile{ID: id, Name: id, ProfileURL: "https://www.linkedin.com/in/" + id + "/", VisitedAt: at}
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+		req := storage.ConnectionRequest{ProfileID: id, SentAt: at, Status: "pending", CreatedAt: at}
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+	for i, at := range sentAt {
+		sendInvite(fmt.Sprintf("lifetime-%d", i), at)
+	}
+
+	config := GetDefaultRateLimitConfig()
+	config.MaxConnectionsPerDay = 100
+	config.MaxLifetimeConnections = 4
+
+	rl := NewRateLimiterWithConfig(db, config)
+	if err := rl.CheckDailyLimit(TaskConnection); err != nil {
+		t.Fatalf("Expected connections allowed below the lifetime cap: %v", err)
+	}
+
+	// An invitation only seen on a profile was not sent by the bot and uses none of the cap
+	if _, err := db.RecordPendingInvitation("sent-by-hand", time.Now()); err != nil {
+		t.Fatalf("Failed to record pending invitation: %v", err)
+	}
+	if err := rl.CheckDailyLimit(TaskConnection); err != nil {
+		t.Fatalf("Expected an observed invitation not to count toward the lifetime cap: %v", err)
+	}
+
+	// The fourth invite reaches the cap; everything after is blocked
+	sendInvite("lifetime-3", time.Now())
+	err = rl.CanPerformTask(TaskConnection)
+	if !errors.Is(err, ErrLifetimeLimit) {
+		t.Fatalf("CanPerformTask() error = %v, want ErrLifetimeLimit", err)
+	}
+
+	// Other task types are unaffected
+	if err := rl.CheckDailyLimit(TaskMessage); err != nil {
+		t.Errorf("Expected messages to be unaffected by the lifetime cap: %v", err)
+	}
+
+	// 0 means unlimited
+	config.MaxLifetimeConnections = 0
+	rl = NewRateLimiterWithConfig(db, config)
+	if err := rl.CheckDailyLimit(TaskConnection); err != nil {
+		t.Errorf("Expected no lifetime cap when MaxLifetimeConnections is 0: %v", err)
+	}
+}
+
+func TestGetDefaultRateLimitConfigLifetimeCap(t *testing.T) {
+	t.Setenv("MAX_LIFETIME_CONNECTIONS", "")
+	if limit := GetDefaultRateLimitConfig().MaxLifetimeConnections; limit != 0 {
+		t.Errorf("Expected unlimited lifetime connections by default, got %d", limit)
+	}
+
+	t.Setenv("MAX_LIFETIME_CONNECTIONS", "200")
+	if limit := GetDefaultRateLimitConfig().MaxLifetimeConnections; limit != 200 {
+		t.Errorf("Expected lifetime cap 200, got %d", limit)
+	}
+}
+
+func TestCarryOverLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		base, used    int
+		expectedLimit int
+	}{
+		{"partly unused", 14, 10, 18},
+		{"hard cap", 14, 0, 21},
+		{"cap just reached", 14, 7, 21},
+		{"fully used", 14, 14, 14},
+		{"borrowed beyond base", 14, 20, 14},
+		{"no limit", 0, 0, 0},
+	}
+	for _, test := range tests {
+		if got := carryOverLimit(test.base, test.used); got != test.expectedLimit {
+			t.Errorf("%s: carryOverLimit(%d, %d) = %d, expected %d", test.name, test.base, test.used, got, test.expectedLimit)
+		}
+	}
+}
+
+func TestCheckDailyLimitCarryOver(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_ratelimiter.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	config := GetDefaultRateLimitConfig()
+	config.MaxConnectionsPerDay = 4
+	config.CarryOverUnused = true
+	rl := NewRateLimiterWithConfig(db, config)
+
+	// Yesterday the bot ran (one search) but sent nothing, so today's limit rises to the 1.5× cap (6)
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	if err := db.IncrementDailyCount(yesterday, storage.CounterSearches); err != nil {
+		t.Fatalf("Failed to record yesterday's search: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		if err := rl.CheckDailyLimit(TaskConnection); err != nil {
+			t.Fatalf("Connection %d should be allowed with carry-over: %v", i+1, err)
+		}
+		if err := db.IncrementConnectionCount(); err != nil {
+			t.Fatalf("Failed to record connection: %v", err)
+		}
+	}
+
+	var rateErr *RateLimitError
+	if err := rl.CheckDailyLimit(TaskConnection); !errors.As(err, &rateErr) || rateErr.Limit != 6 {
+		t.Errorf("Expected a rate limit error at the carried-over limit 6, got %v", err)
+	}
+	if remaining, _ := rl.GetRemainingQuota(TaskConnection); remaining != 0 {
+		t.Errorf("Expected no remaining quota, got %d", remaining)
+	}
+
+	// Without carry-over the base limit applies
+	config.CarryOverUnused = false
+	rl = NewRateLimiterWithConfig(db, config)
+	if err := rl.CheckDailyLimit(TaskConnection); !errors.As(err, &rateErr) || rateErr.Limit != 4 {
+		t.Errorf("Expected a rate limit error at the base limit 4, got %v", err)
+	}
+}
+
+func TestCheckDailyLimitNoCarryOverWithoutActivity(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_ratelimiter.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	// A Monday after a weekend off: Sunday has no rate_limits row
+	clock := NewMockClock(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	config := GetDefaultRateLimitConfig()
+	config.MaxConnectionsPerDay = 4
+	config.CarryOverUnused = true
+	rl := NewRateLimiterWithClock(db, config, clock)
+
+	if remaining, err := rl.GetRemainingQuota(TaskConnection); err != nil || remaining != 4 {
+		t.Errorf("Expected the base limit 4 without a row for yesterday, got %d (%v)", remaining, err)
+	}
+
+	// A row the bot created without recording anything is no activity either
+	if _, err := db.GetTodayRateLimit(); err != nil {
+		t.Fatalf("Failed to create today's row: %v", err)
+	}
+	clock.Set(time.Now().AddDate(0, 0, 1))
+	if remaining, err := rl.GetRemainingQuota(TaskConnection); err != nil || remaining != 4 {
+		t.Errorf("Expected the base limit 4 after an idle day, got %d (%v)", remaining, err)
+	}
+}
+
+func TestGetDefaultRateLimitConfigCarryOver(t *testing.T) {
+	t.Setenv("RATE_LIMIT_CARRY_OVER", "")
+	if GetDefaultRateLimitConfig().CarryOverUnused {
+		t.Error("Expected carry-over to be off by default")
+	}
+
+	t.Setenv("RATE_LIMIT_CARRY_OVER", "true")
+	if !GetDefaultRateLimitConfig().CarryOverUnused {
+		t.Error("Expected RATE_LIMIT_CARRY_OVER=true to enable carry-over")
+	}
+}
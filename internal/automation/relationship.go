@@ -0,0 +1,144 @@
+package automation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/browser"
+	"linkedin-automation/pkg/utils"
+)
+
+// Relationship states reported by GetRelationshipState
+const (
+	RelationshipConnected = "CONNECTED" // 1st-degree connection
+	RelationshipPending   = "PENDING"   // Invitation already sent and awaiting a response
+	RelationshipNone      = "NONE"      // Not connected, no pending invitation
+)
+
+// GetRelationshipState reads the relationship with the profile open in page from
+// the Voyager profile API response captured by browser.WatchProfileResponses.
+// Returns an error when no response for the profile was captured or none could be
+// parsed; callers should then fall back to checking the DOM.
+func GetRelationshipState(page *rod.Page) (string, error) {
+	info, err := page.Info()
+	if err != nil {
+		return "", fmt.Errorf("failed to read page URL: %w", err)
+	}
+
+	profileID := utils.ExtractProfileID(info.URL)
+	if profileID == "" {
+		return "", fmt.Errorf("page is not a profile: %s", info.URL)
+	}
+
+	return relationshipStateFromResponses(profileID, browser.ProfileResponses(page))
+}
+
+// relationshipStateFromResponses returns the state from the newest parseable
+// response that belongs to profileID
+func relationshipStateFromResponses(profileID string, responses []browser.ProfileResponse) (string, error) {
+	for i := len(responses) - 1; i >= 0; i-- {
+		if !responseMatchesProfile(responses[i].URL, profileID) {
+			continue
+		}
+		if state, err := parseRelationshipState(responses[i].Body); err == nil {
+			return state, nil
+		}
+	}
+
+	return "", fmt.Errorf("no relationship state captured for %s (%d responses)", profileID, len(responses))
+}
+
+// responseMatchesProfile reports whether a Voyager URL was requested for profileID.
+// The profile page also loads other members (e.g. "People also viewed"), so
+// responses are matched on the ID rather than trusted by order.
+func responseMatchesProfile(rawURL, profileID string) bool {
+	if profileID == "" {
+		return false
+	}
+
+	decoded, err := url.QueryUnescape(rawURL)
+	if err != nil {
+		decoded = rawURL
+	}
+	return strings.Contains(decoded, profileID)
+}
+
+// parseRelationshipState extracts the relationship state from a Voyager profile
+// response. The state lives in a memberRelationship entity, either nested in the
+// profile or in the normalized "included" list:
+//
+//	"memberRelationshipUnion": {"connection": {...}}                                    → CONNECTED
+//	"memberRelationshipUnion": {"noConnection": {"invitationUnion": {"invitation": {}}}} → PENDING
+//	"memberRelationshipUnion": {"noConnection": {"invitationUnion": {"noInvitation": {}}}} → NONE
+func parseRelationshipState(body []byte) (string, error) {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return "", fmt.Errorf("failed to parse profile response: %w", err)
+	}
+
+	if state := findRelationshipState(doc); state != "" {
+		return state, nil
+	}
+	return "", fmt.Errorf("profile response has no relationship state")
+}
+
+// findRelationshipState walks the JSON depth-first and returns the first state found
+func findRelationshipState(node interface{}) string {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if union, ok := value["memberRelationshipUnion"].(map[string]interface{}); ok {
+			if state := relationshipFromUnion(union); state != "" {
+				return state
+			}
+		}
+
+		// Map order is random; walk keys sorted so the result is stable
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if state := findRelationshipState(value[key]); state != "" {
+				return state
+			}
+		}
+
+	case []interface{}:
+		for _, item := range value {
+			if state := findRelationshipState(item); state != "" {
+				return state
+			}
+		}
+	}
+
+	return ""
+}
+
+// relationshipFromUnion maps a memberRelationshipUnion object to a state
+func relationshipFromUnion(union map[string]interface{}) string {
+	if _, ok := union["connection"]; ok {
+		return RelationshipConnected
+	}
+
+	noConnection, ok := union["noConnection"].(map[string]interface{})
+	if !ok {
+		// e.g. "self" when viewing our own profile
+		return ""
+	}
+
+	invitationUnion, _ := noConnection["invitationUnion"].(map[string]interface{})
+	if invitation, ok := invitationUnion["invitation"].(map[string]interface{}); ok {
+		// Only a pending invitation blocks a new request; withdrawn or ignored ones don't
+		if state, _ := invitation["invitationState"].(string); state == "" || strings.EqualFold(state, "PENDING") {
+			return RelationshipPending
+		}
+	}
+
+	return RelationshipNone
+}
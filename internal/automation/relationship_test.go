@@ -0,0 +1,115 @@
+package automation
+
+import (
+	"testing"
+
+	"linkedin-automation/internal/browser"
+)
+
+// Trimmed Voyager dash/profiles responses for each relationship state
+const (
+	voyagerConnectedJSON = `{
+		"data": {"*elements": ["urn:li:fsd_profile:ACoAAB1"]},
+		"included": [
+			{"$type": "com.linkedin.voyager.dash.identity.profile.Profile", "entityUrn": "urn:li:fsd_profile:ACoAAB1", "publicIdentifier": "jane-doe"},
+			{"$type": "com.linkedin.voyager.dash.relationships.MemberRelationship", "entityUrn": "urn:li:fsd_memberRelationship:ACoAAB1",
+			 "memberRelationshipUnion": {"connection": {"*connection": "urn:li:fsd_connection:ACoAAB1", "createdAt": 1733000000000}}}
+		]
+	}`
+
+	voyagerPendingJSON = `{
+		"included": [
+			{"$type": "com.linkedin.voyager.dash.relationships.MemberRelationship",
+			 "memberRelationshipUnion": {"noConnection": {"memberDistance": "DISTANCE_2",
+			   "invitationUnion": {"invitation": {"invitationState": "PENDING", "sharedSecret": "abc"}}}}}
+		]
+	}`
+
+	voyagerNoneJSON = `{
+		"included": [
+			{"$type": "com.linkedin.voyager.dash.relationships.MemberRelationship",
+			 "memberRelationshipUnion": {"noConnection": {"memberDistance": "DISTANCE_3",
+			   "invitationUnion": {"noInvitation": {"$type": "com.linkedin.voyager.dash.relationships.NoInvitation"}}}}}
+		]
+	}`
+
+	// Decorated (non-normalized) responses nest the relationship in the profile
+	voyagerNestedConnectedJSON = `{
+		"elements": [{"publicIdentifier": "jane-doe",
+			"memberRelationship": {"memberRelationshipUnion": {"connection": {"createdAt": 1733000000000}}}}]
+	}`
+)
+
+func TestParseRelationshipState(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+		wantErr  bool
+	}{
+		{"Connected", voyagerConnectedJSON, RelationshipConnected, false},
+		{"Pending", voyagerPendingJSON, RelationshipPending, false},
+		{"None", voyagerNoneJSON, RelationshipNone, false},
+		{"Nested connected", voyagerNestedConnectedJSON, RelationshipConnected, false},
+		{"Withdrawn invitation", `{"memberRelationshipUnion": {"noConnection": {"invitationUnion": {"invitation": {"invitationState": "WITHDRAWN"}}}}}`, RelationshipNone, false},
+		{"Own profile", `{"memberRelationshipUnion": {"self": {}}}`, "", true},
+		{"No relationship", `{"included": [{"publicIdentifier": "jane-doe"}]}`, "", true},
+		{"Not JSON", `<html></html>`, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state, err := parseRelationshipState([]byte(test.body))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseRelationshipState() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if state != test.expected {
+				t.Errorf("parseRelationshipState() = %q, expected %q", state, test.expected)
+			}
+		})
+	}
+}
+
+func TestRelationshipStateFromResponses(t *testing.T) {
+	base := "https://www.linkedin.com/voyager/api/identity/dash/profiles?q=memberIdentity&memberIdentity="
+	responses := []browser.ProfileResponse{
+		{URL: base + "jane-doe&decorationId=Full", Body: []byte(voyagerNoneJSON)},
+		{URL: base + "jane-doe&decorationId=TopCard", Body: []byte(`{"included": []}`)},
+		{URL: base + "someone-else", Body: []byte(voyagerConnectedJSON)},
+	}
+
+	// Newest response for another member and unparseable ones are skipped
+	state, err := relationshipStateFromResponses("jane-doe", responses)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state != RelationshipNone {
+		t.Errorf("Expected %q, got %q", RelationshipNone, state)
+	}
+
+	if _, err := relationshipStateFromResponses("john-smith", responses); err == nil {
+		t.Error("Expected error when no response matches the profile")
+	}
+	if _, err := relationshipStateFromResponses("jane-doe", nil); err == nil {
+		t.Error("Expected error when nothing was captured")
+	}
+}
+
+func TestResponseMatchesProfile(t *testing.T) {
+	tests := []struct {
+		url       string
+		profileID string
+		expected  bool
+	}{
+		{"https://www.linkedin.com/voyager/api/identity/dash/profiles?memberIdentity=jane-doe", "jane-doe", true},
+		{"https://www.linkedin.com/voyager/api/identity/dash/profiles?memberIdentity=j%C3%BCrgen-m", "jürgen-m", true},
+		{"https://www.linkedin.com/voyager/api/identity/dash/profiles?memberIdentity=john-smith", "jane-doe", false},
+		{"https://www.linkedin.com/voyager/api/identity/dash/profiles?memberIdentity=jane-doe", "", false},
+	}
+
+	for _, test := range tests {
+		if result := responseMatchesProfile(test.url, test.profileID); result != test.expected {
+			t.Errorf("responseMatchesProfile(%q, %q) = %v, expected %v", test.url, test.profileID, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package automation
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+)
+
+// RotationStrategy decides which template a TemplateRotator hands out next
+type RotationStrategy string
+
+const (
+	// RotationRoundRobin moves to the next template after every successful send
+	RotationRoundRobin RotationStrategy = "round_robin"
+	// RotationWeekday uses one template per day: the first on Monday, the second on Tuesday, ...
+	RotationWeekday RotationStrategy = "weekday"
+)
+
+// Names the rotation positions are saved under
+const (
+	ConnectionRotationName = "connection"
+	MessageRotationName    = "message"
+)
+
+// TemplateRotator cycles through templates deterministically so outreach stays
+// varied and each template's results can be compared. The round-robin position
+// is saved in the database so it survives restarts.
+type TemplateRotator struct {
+	Name      string
+	Templates []MessageTemplate
+	Strategy  RotationStrategy
+
+	db    *storage.Database
+	clock Clock
+}
+
+// NewTemplateRotator creates a rotator whose position is saved in db under
+// name. It needs at least one template.
+func NewTemplateRotator(db *storage.Database, name string, templates []MessageTemplate, strategy RotationStrategy) (*TemplateRotator, error) {
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("template rotation %q has no templates", name)
+	}
+	return &TemplateRotator{Name: name, Templates: templates, Strategy: strategy, db: db, clock: SystemClock}, nil
+}
+
+// Next returns the template to use for the next send. It does not move the
+// rotation; call Advance once a send with it succeeded.
+func (r *TemplateRotator) Next() (MessageTemplate, error) {
+	return r.Peek(0)
+}
+
+// Peek returns the template offset sends after the next one, for preparing
+// a batch of sends up front
+func (r *TemplateRotator) Peek(offset int) (MessageTemplate, error) {
+	if len(r.Templates) == 0 {
+		return MessageTemplate{}, fmt.Errorf("template rotation %q has no templates", r.Name)
+	}
+
+	if r.Strategy == RotationWeekday {
+		return r.Templates[weekdayIndex(r.clock.Now())%len(r.Templates)], nil
+	}
+	return r.Templates[(r.savedIndex()+offset)%len(r.Templates)], nil
+}
+
+// Advance moves a round-robin rotation past templateID after a successful
+// send with it, so a failed send doesn't use up its template's turn.
+// Weekday rotations don't move.
+func (r *TemplateRotator) Advance(templateID string) {
+	if r.Strategy == RotationWeekday || r.db == nil {
+		return
+	}
+
+	for i, template := range r.Templates {
+		if template.ID == templateID {
+			if err := r.db.SetRotationIndex(r.Name, (i+1)%len(r.Templates)); err != nil {
+				logger.Warning("Failed to save template rotation: " + err.Error())
+			}
+			return
+		}
+	}
+}
+
+// savedIndex returns the saved round-robin position, 0 without a database
+func (r *TemplateRotator) savedIndex() int {
+	if r.db == nil {
+		return 0
+	}
+	index, err := r.db.GetRotationIndex(r.Name)
+	if err != nil {
+		logger.Warning("Failed to load template rotation, starting over: " + err.Error())
+		return 0
+	}
+	return index
+}
+
+// weekdayIndex numbers the days of the week from Monday (0) to Sunday (6)
+func weekdayIndex(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+// ParseRotationStrategy converts a TEMPLATE_ROTATION_STRATEGY value.
+// Empty means round-robin.
+func ParseRotationStrategy(value string) (RotationStrategy, error) {
+	switch RotationStrategy(strings.ToLower(strings.TrimSpace(value))) {
+	case "", RotationRoundRobin:
+		return RotationRoundRobin, nil
+	case RotationWeekday:
+		return RotationWeekday, nil
+	default:
+		return "", fmt.Errorf("unknown template rotation strategy %q (use round_robin or weekday)", value)
+	}
+}
+
+// parseRotationTemplates resolves a comma-separated list of template IDs,
+// checking each is (or is not) a connection request template
+func parseRotationTemplates(envVar, spec string, connection bool) ([]MessageTemplate, error) {
+	var templates []MessageTemplate
+	for _, id := range strings.Split(spec, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if err := checkTemplateType(envVar, id, connection); err != nil {
+			return nil, err
+		}
+		template, _ := GetTemplateByID(id)
+		templates = append(templates, *template)
+	}
+
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("%s lists no templates", envVar)
+	}
+	return templates, nil
+}
+
+// TemplateRotatorFromEnv builds the rotator for connection notes (CONNECTION_TEMPLATE_ROTATION)
+// or follow-up messages (MESSAGE_TEMPLATE_ROTATION) using TEMPLATE_ROTATION_STRATEGY.
+// Returns nil without error when the rotation is not configured.
+func TemplateRotatorFromEnv(db *storage.Database, connection bool) (*TemplateRotator, error) {
+	envVar, name := "MESSAGE_TEMPLATE_ROTATION", MessageRotationName
+	if connection {
+		envVar, name = "CONNECTION_TEMPLATE_ROTATION", ConnectionRotationName
+	}
+
+	spec := os.Getenv(envVar)
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	templates, err := parseRotationTemplates(envVar, spec, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy, err := ParseRotationStrategy(os.Getenv("TEMPLATE_ROTATION_STRATEGY"))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTemplateRotator(db, name, templates, strategy)
+}
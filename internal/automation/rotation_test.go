@@ -0,0 +1,205 @@
+package automation
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+// newRotator creates a rotator over templates, failing the test on error
+func newRotator(t *testing.T, db *storage.Database, name string, templates []MessageTemplate, strategy RotationStrategy) *TemplateRotator {
+	t.Helper()
+	rotator, err := NewTemplateRotator(db, name, templates, strategy)
+	if err != nil {
+		t.Fatalf("NewTemplateRotator failed: %v", err)
+	}
+	return rotator
+}
+
+// nextID returns the ID of the rotator's next template, failing the test on error
+func nextID(t *testing.T, rotator *TemplateRotator) string {
+	t.Helper()
+	template, err := rotator.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	return template.ID
+}
+
+// rotationTemplates returns three connection templates in a fixed order
+func rotationTemplates(t *testing.T) []MessageTemplate {
+	t.Helper()
+	templates, err := parseRotationTemplates("CONNECTION_TEMPLATE_ROTATION", "conn_generic, conn_brief,conn_industry", true)
+	if err != nil {
+		t.Fatalf("Failed to parse rotation templates: %v", err)
+	}
+	return templates
+}
+
+func TestTemplateRotatorRoundRobin(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_rotation.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	rotator := newRotator(t, db, ConnectionRotationName, rotationTemplates(t), RotationRoundRobin)
+
+	want := []string{"conn_generic", "conn_brief", "conn_industry", "conn_generic", "conn_brief"}
+	for i, id := range want {
+		got := nextID(t, rotator)
+		if got != id {
+			t.Errorf("send %d: expected %s, got %s", i+1, id, got)
+		}
+		rotator.Advance(got)
+	}
+}
+
+func TestTemplateRotatorAdvancesOnlyOnSuccess(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_rotation.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	rotator := newRotator(t, db, ConnectionRotationName, rotationTemplates(t), RotationRoundRobin)
+
+	// A failed send keeps its template's turn
+	if got := nextID(t, rotator); got != "conn_generic" {
+		t.Fatalf("Expected conn_generic first, got %s", got)
+	}
+	if got := nextID(t, rotator); got != "conn_generic" {
+		t.Errorf("Expected conn_generic again while nothing was sent, got %s", got)
+	}
+
+	// A batch prepared up front takes consecutive turns; only sent ones move the rotation
+	batch := make([]string, 3)
+	for i := range batch {
+		template, err := rotator.Peek(i)
+		if err != nil {
+			t.Fatalf("Peek(%d) failed: %v", i, err)
+		}
+		batch[i] = template.ID
+	}
+	if want := []string{"conn_generic", "conn_brief", "conn_industry"}; !reflect.DeepEqual(batch, want) {
+		t.Errorf("Expected batch %v, got %v", want, batch)
+	}
+	rotator.Advance("conn_brief") // conn_generic failed, conn_brief was sent, conn_industry failed
+	if got := nextID(t, rotator); got != "conn_industry" {
+		t.Errorf("Expected the rotation to continue after the last sent template, got %s", got)
+	}
+}
+
+func TestNewTemplateRotatorRejectsEmpty(t *testing.T) {
+	if _, err := NewTemplateRotator(nil, ConnectionRotationName, nil, RotationRoundRobin); err == nil {
+		t.Error("Expected an error for a rotation without templates")
+	}
+	for _, strategy := range []RotationStrategy{RotationRoundRobin, RotationWeekday} {
+		rotator := &TemplateRotator{Name: ConnectionRotationName, Strategy: strategy, clock: SystemClock}
+		if _, err := rotator.Next(); err == nil {
+			t.Errorf("%s: expected an error instead of a division by zero", strategy)
+		}
+	}
+}
+
+func TestTemplateRotatorPersistsIndex(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_rotation.db")
+	db, err := storage.InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	first := newRotator(t, db, ConnectionRotationName, rotationTemplates(t), RotationRoundRobin)
+	first.Advance(nextID(t, first))
+	first.Advance(nextID(t, first))
+	db.Close()
+
+	// A new run picks up where the last one stopped
+	db, err = storage.InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	restarted := newRotator(t, db, ConnectionRotationName, rotationTemplates(t), RotationRoundRobin)
+	if got := nextID(t, restarted); got != "conn_industry" {
+		t.Errorf("Expected the rotation to continue with conn_industry after a restart, got %s", got)
+	}
+
+	// Rotations are saved independently
+	messages, err := parseRotationTemplates("MESSAGE_TEMPLATE_ROTATION", "msg_introduction,msg_value_add", false)
+	if err != nil {
+		t.Fatalf("Failed to parse message rotation: %v", err)
+	}
+	messageRotator := newRotator(t, db, MessageRotationName, messages, RotationRoundRobin)
+	if got := nextID(t, messageRotator); got != "msg_introduction" {
+		t.Errorf("Expected the message rotation to start at msg_introduction, got %s", got)
+	}
+}
+
+func TestTemplateRotatorWeekday(t *testing.T) {
+	rotator := newRotator(t, nil, ConnectionRotationName, rotationTemplates(t), RotationWeekday)
+	clock := NewMockClock(time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)) // a Monday
+	rotator.clock = clock
+
+	// Mon..Sun with three templates wraps around on Thursday and Sunday
+	want := []string{"conn_generic", "conn_brief", "conn_industry", "conn_generic", "conn_brief", "conn_industry", "conn_generic"}
+	for day, id := range want {
+		// Same template all day long
+		for send := 0; send < 2; send++ {
+			got := nextID(t, rotator)
+			if got != id {
+				t.Errorf("%s send %d: expected %s, got %s", clock.Now().Weekday(), send+1, id, got)
+			}
+		}
+		if day < len(want)-1 {
+			clock.Advance(24 * time.Hour)
+		}
+	}
+}
+
+func TestParseRotationStrategy(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected RotationStrategy
+		wantErr  bool
+	}{
+		{"", RotationRoundRobin, false},
+		{"round_robin", RotationRoundRobin, false},
+		{" Weekday ", RotationWeekday, false},
+		{"random", "", true},
+	}
+
+	for _, test := range tests {
+		strategy, err := ParseRotationStrategy(test.value)
+		if (err != nil) != test.wantErr || strategy != test.expected {
+			t.Errorf("ParseRotationStrategy(%q) = %q, %v; expected %q (error %v)", test.value, strategy, err, test.expected, test.wantErr)
+		}
+	}
+}
+
+func TestTemplateRotatorFromEnv(t *testing.T) {
+	t.Setenv("CONNECTION_TEMPLATE_ROTATION", "")
+	t.Setenv("TEMPLATE_ROTATION_STRATEGY", "")
+	if rotator, err := TemplateRotatorFromEnv(nil, true); rotator != nil || err != nil {
+		t.Errorf("Expected no rotator when unset, got %v (err %v)", rotator, err)
+	}
+
+	t.Setenv("CONNECTION_TEMPLATE_ROTATION", "conn_generic,msg_introduction")
+	if _, err := TemplateRotatorFromEnv(nil, true); err == nil {
+		t.Error("Expected an error for a message template in the connection rotation")
+	}
+
+	t.Setenv("CONNECTION_TEMPLATE_ROTATION", "conn_generic,conn_brief")
+	t.Setenv("TEMPLATE_ROTATION_STRATEGY", "weekday")
+	rotator, err := TemplateRotatorFromEnv(nil, true)
+	if err != nil {
+		t.Fatalf("TemplateRotatorFromEnv failed: %v", err)
+	}
+	if rotator.Name != ConnectionRotationName || rotator.Strategy != RotationWeekday || len(rotator.Templates) != 2 {
+		t.Errorf("Unexpected rotator: %+v", rotator)
+	}
+}
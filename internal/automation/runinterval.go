@@ -0,0 +1,65 @@
+package automation
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+)
+
+// GetMinRunInterval reads MIN_RUN_INTERVAL_MINUTES, the least time between the
+// starts of two runs (default 0 = no minimum)
+func GetMinRunInterval() time.Duration {
+	if v := os.Getenv("MIN_RUN_INTERVAL_MINUTES"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			return time.Duration(val) * time.Minute
+		}
+	}
+	return 0
+}
+
+// EnforceMinRunInterval refuses to start a run when the last one, as saved in
+// the state file, started less than minInterval ago - back-to-back runs (a
+// cron overlap, a restart loop) look nothing like a person. FORCE_RUN=true
+// skips the check. Returns an error wrapping ErrRunTooSoon with the time left.
+func EnforceMinRunInterval(minInterval time.Duration) error {
+	if minInterval <= 0 {
+		return nil
+	}
+
+	state, err := storage.LoadState()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
+	var lastRun time.Time
+	if state != nil {
+		lastRun = state.LastRun
+	}
+
+	err = checkRunInterval(lastRun, SystemClock.Now(), minInterval)
+	if err != nil && os.Getenv("FORCE_RUN") == "true" {
+		logger.Warning("FORCE_RUN set - ignoring: " + err.Error())
+		return nil
+	}
+	return err
+}
+
+// checkRunInterval returns an error if lastRun is less than minInterval before
+// now. A zero lastRun (no run recorded) always passes.
+func checkRunInterval(lastRun, now time.Time, minInterval time.Duration) error {
+	if minInterval <= 0 || lastRun.IsZero() {
+		return nil
+	}
+
+	elapsed := now.Sub(lastRun)
+	if elapsed >= minInterval {
+		return nil
+	}
+
+	return fmt.Errorf("%w: started %s ago, the minimum interval is %s - try again in %s or set FORCE_RUN=true",
+		ErrRunTooSoon, elapsed.Round(time.Minute), minInterval, (minInterval - elapsed).Round(time.Minute))
+}
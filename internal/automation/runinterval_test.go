@@ -0,0 +1,75 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+func TestCheckRunInterval(t *testing.T) {
+	now := time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		lastRun     time.Time
+		minInterval time.Duration
+		tooSoon     bool
+	}{
+		{"recent run", now.Add(-20 * time.Minute), time.Hour, true},
+		{"old run", now.Add(-3 * time.Hour), time.Hour, false},
+		{"exactly the interval", now.Add(-time.Hour), time.Hour, false},
+		{"no run recorded", time.Time{}, time.Hour, false},
+		{"no minimum", now.Add(-time.Minute), 0, false},
+	}
+
+	for _, test := range tests {
+		err := checkRunInterval(test.lastRun, now, test.minInterval)
+		if errors.Is(err, ErrRunTooSoon) != test.tooSoon {
+			t.Errorf("%s: expected too soon=%v, got %v", test.name, test.tooSoon, err)
+		}
+	}
+}
+
+func TestEnforceMinRunInterval(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("FORCE_RUN", "")
+
+	// No state file yet: the first run may start
+	if err := EnforceMinRunInterval(time.Hour); err != nil {
+		t.Fatalf("Expected the first run to start, got %v", err)
+	}
+
+	if err := storage.RecordRun(); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+	if err := EnforceMinRunInterval(time.Hour); !errors.Is(err, ErrRunTooSoon) {
+		t.Errorf("Expected ErrRunTooSoon right after a run, got %v", err)
+	}
+	if err := EnforceMinRunInterval(0); err != nil {
+		t.Errorf("Expected no check without a minimum, got %v", err)
+	}
+
+	t.Setenv("FORCE_RUN", "true")
+	if err := EnforceMinRunInterval(time.Hour); err != nil {
+		t.Errorf("Expected FORCE_RUN to skip the check, got %v", err)
+	}
+}
+
+func TestGetMinRunInterval(t *testing.T) {
+	t.Setenv("MIN_RUN_INTERVAL_MINUTES", "")
+	if got := GetMinRunInterval(); got != 0 {
+		t.Errorf("Expected no minimum by default, got %s", got)
+	}
+
+	t.Setenv("MIN_RUN_INTERVAL_MINUTES", "90")
+	if got := GetMinRunInterval(); got != 90*time.Minute {
+		t.Errorf("Expected 90m, got %s", got)
+	}
+
+	t.Setenv("MIN_RUN_INTERVAL_MINUTES", "-5")
+	if got := GetMinRunInterval(); got != 0 {
+		t.Errorf("Expected an invalid value to be ignored, got %s", got)
+	}
+}
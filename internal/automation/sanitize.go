@@ -0,0 +1,96 @@
+package automation
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+// NoteSanitizeConfig controls what SanitizeNote does besides dropping
+// control and zero-width characters, which are always removed
+type NoteSanitizeConfig struct {
+	StripEmoji  bool // Remove emoji and pictographs
+	ASCIIQuotes bool // Replace smart quotes, dashes, ellipses and non-breaking spaces with ASCII
+}
+
+// GetNoteSanitizeConfig reads NOTE_STRIP_EMOJI and NOTE_ASCII_QUOTES (both default true)
+func GetNoteSanitizeConfig() NoteSanitizeConfig {
+	return NoteSanitizeConfig{
+		StripEmoji:  os.Getenv("NOTE_STRIP_EMOJI") != "false",
+		ASCIIQuotes: os.Getenv("NOTE_ASCII_QUOTES") != "false",
+	}
+}
+
+// asciiReplacements maps typographic characters to what LinkedIn's note field accepts
+var asciiReplacements = map[rune]string{
+	'\u2018': "'", '\u2019': "'", '\u201A': "'", '\u201B': "'", // Single quotes
+	'\u201C': `"`, '\u201D': `"`, '\u201E': `"`, '\u201F': `"`, // Double quotes
+	'\u2013': "-", '\u2014': "-", '\u2212': "-", // En/em dash, minus
+	'\u2026': "...",              // Ellipsis
+	'\u00A0': " ", '\u202F': " ", // Non-breaking spaces
+}
+
+// SanitizeNote strips characters from a rendered connection note that
+// LinkedIn's note field rejects or that come from scraped profile data:
+// control and zero-width characters always, emoji and typographic
+// punctuation as configured by GetNoteSanitizeConfig
+func SanitizeNote(s string) string {
+	return sanitizeNote(s, GetNoteSanitizeConfig())
+}
+
+// sanitizeNote is SanitizeNote with an explicit configuration
+func sanitizeNote(s string, cfg NoteSanitizeConfig) string {
+	s = strings.ToValidUTF8(s, "")
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteByte(' ')
+		case unicode.IsControl(r), isZeroWidth(r):
+			// Dropped
+		case cfg.StripEmoji && isEmoji(r):
+			// Dropped
+		default:
+			if replacement, ok := asciiReplacements[r]; ok && cfg.ASCIIQuotes {
+				b.WriteString(replacement)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+
+	return cleanupWhitespace(b.String())
+}
+
+// isZeroWidth reports invisible formatting characters (zero-width spaces/joiners, BOM, tags)
+func isZeroWidth(r rune) bool {
+	switch {
+	case r >= 0x200B && r <= 0x200F, r >= 0x2060 && r <= 0x2064, r == 0xFEFF:
+		return true
+	case r >= 0xE0000 && r <= 0xE007F: // Tag characters used in flag sequences
+		return true
+	}
+	return false
+}
+
+// isEmoji reports emoji, pictographs and the modifiers that combine with them
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Emoticons, pictographs, transport, flags, skin tones
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Misc symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // Stars, arrows and other emoji-presentation symbols
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F: // Variation selectors
+		return true
+	case r == 0x20E3: // Combining keycap
+		return true
+	}
+	return false
+}
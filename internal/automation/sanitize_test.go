@@ -0,0 +1,182 @@
+package automation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeNote(t *testing.T) {
+	defaults := NoteSanitizeConfig{StripEmoji: true, ASCIIQuotes: true}
+
+	tests := []struct {
+		name     string
+		input    string
+		cfg      NoteSanitizeConfig
+		expected string
+	}{
+		{"Plain text unchanged", "Hi Jane, let's connect!", defaults, "Hi Jane, let's connect!"},
+		{"Smart quotes", "Hi Jane, I’d love to hear about “Go at scale”", defaults, `Hi Jane, I'd love to hear about "Go at scale"`},
+		{"Dashes and ellipsis", "Engineer — Platform…", defaults, "Engineer - Platform..."},
+		{"Emoji removed", "Hi Jane \U0001F44B great work \U0001F680✨", defaults, "Hi Jane great work"},
+		{"Emoji sequences removed", "Team \U0001F468‍\U0001F4BB\U0001F3FD and \U0001F1FA\U0001F1F8", defaults, "Team and"},
+		{"Control characters removed", "Hi\x00 Jane\x07,\r\nwelcome\tback", defaults, "Hi Jane,\nwelcome back"},
+		{"Zero-width removed", "Ja\u200bne\ufeff Doe", defaults, "Jane Doe"},
+		{"Accents kept", "Hi José, grüße aus München", defaults, "Hi José, grüße aus München"},
+		{"Invalid UTF-8 removed", "Hi \xff\xfeJane", defaults, "Hi Jane"},
+		{"Emoji kept when disabled", "Hi \U0001F44B", NoteSanitizeConfig{ASCIIQuotes: true}, "Hi \U0001F44B"},
+		{"Quotes kept when disabled", "I’m here", NoteSanitizeConfig{StripEmoji: true}, "I’m here"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := sanitizeNote(test.input, test.cfg); result != test.expected {
+				t.Errorf("sanitizeNote(%q) = %q, expected %q", test.input, result, test.expected)
+			}
+		})
+	}
+}
+
+func TestSanitizeNoteFromEnv(t *testing.T) {
+	t.Setenv("NOTE_STRIP_EMOJI", "false")
+	t.Setenv("NOTE_ASCII_QUOTES", "")
+
+	if result := SanitizeNote("I’m here \U0001F44B"); result != "I'm here \U0001F44B" {
+		t.Errorf("Expected emoji kept and quotes replaced, got %q", result)
+	}
+}
+
+func TestValidateMessageLengthCountsRunes(t *testing.T) {
+	t.Setenv("CONNECTION_NOTE_MAX", "")
+	t.Setenv("CONNECTION_NOTE_MIN", "")
+
+	// 300 characters but 600 bytes
+	note := strings.Repeat("é", ConnectionNoteMaxLength)
+	if len(note) <= ConnectionNoteMaxLength {
+		t.Fatalf("Test note should be longer in bytes than the limit, got %d bytes", len(note))
+	}
+	if err := ValidateMessageLength(note, TemplateConnectionRequest); err != nil {
+		t.Errorf("Expected a %d-character multibyte note to fit, got %v", ConnectionNoteMaxLength, err)
+	}
+
+	if err := ValidateMessageLength(note+"é", TemplateConnectionRequest); err == nil {
+		t.Error("Expected a note one character over the limit to fail")
+	}
+}
+
+func TestRenderTemplateSanitizesAndCountsRunes(t *testing.T) {
+	t.Setenv("CONNECTION_NOTE_MAX", "")
+	t.Setenv("CONNECTION_NOTE_MIN", "")
+	t.Setenv("NOTE_STRIP_EMOJI", "")
+	t.Setenv("NOTE_ASCII_QUOTES", "")
+
+	tmpl := MessageTemplate{
+		ID:   "test_note",
+		Name: "Test note",
+		Type: TemplateConnectionRequest,
+		Body: "Hi {{.FirstName}}, I saw you work on {{.Title}}.",
+	}
+
+	// Title scraped with emoji and multibyte characters
+	title := "Zürich \U0001F680 " + strings.Repeat("é", 240)
+	result, err := RenderTemplate(tmpl, TemplateVariables{FullName: "José Pérez", Title: title})
+	if err != nil {
+		t.Fatalf("Expected note under the character limit to render, got %v", err)
+	}
+	if strings.Contains(result, "\U0001F680") {
+		t.Errorf("Expected emoji to be removed, got %q", result)
+	}
+	if n := utf8.RuneCountInString(result); n > ConnectionNoteMaxLength {
+		t.Errorf("Rendered note has %d characters, over the limit", n)
+	}
+}
+
+func TestTruncateMessageMultibyte(t *testing.T) {
+	message := strings.Repeat("ü", 50)
+
+	result := TruncateMessage(message, 20)
+	if !utf8.ValidString(result) {
+		t.Fatalf("Truncation split a multibyte character: %q", result)
+	}
+	if n := utf8.RuneCountInString(result); n != 20 {
+		t.Errorf("Expected 20 characters, got %d", n)
+	}
+	if !strings.HasSuffix(result, "...") {
+		t.Errorf("Expected ellipsis, got %q", result)
+	}
+}
+
+func TestEstimateLinkedInNoteLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		note     string
+		naive    int // utf8.RuneCountInString
+		expected int
+	}{
+		{"plain ASCII", "Hi Jane, let's connect!", 23, 23},
+		{"accented name", "Hi José", 7, 7},
+		{"BMP symbol", "Great talk ✓", 12, 12},
+		{"emoji counts as 2", "Hi 👋", 4, 5},
+		{"several emoji", "🚀🚀🚀", 3, 6},
+		{"newline counts as 2", "Hi Jane,\nlet's connect", 22, 23},
+		{"CRLF counts as 2", "Hi Jane,\r\nlet's connect", 23, 23},
+		{"emoji and newlines", "Hi 👋\n\nThanks!", 13, 16},
+		{"empty", "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if naive := utf8.RuneCountInString(tt.note); naive != tt.naive {
+				t.Errorf("RuneCountInString(%q) = %d, expected %d", tt.note, naive, tt.naive)
+			}
+			if got := EstimateLinkedInNoteLength(tt.note); got != tt.expected {
+				t.Errorf("EstimateLinkedInNoteLength(%q) = %d, expected %d", tt.note, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateMessageLengthUsesLinkedInCount(t *testing.T) {
+	t.Setenv("CONNECTION_NOTE_MAX", "")
+	t.Setenv("CONNECTION_NOTE_MIN", "")
+
+	// 296 characters plus two emoji: 298 by rune count, 300 by LinkedIn's count
+	atLimit := strings.Repeat("a", 296) + "🎉🎉"
+	if err := ValidateMessageLength(atLimit, TemplateConnectionRequest); err != nil {
+		t.Errorf("Expected a note at exactly 300 to pass: %v", err)
+	}
+
+	// One more emoji passes a naive rune count (299) but LinkedIn would reject it (302)
+	overLimit := atLimit + "🎉"
+	if n := utf8.RuneCountInString(overLimit); n > ConnectionNoteMaxLength {
+		t.Fatalf("Test note should pass a naive count, has %d runes", n)
+	}
+	if err := ValidateMessageLength(overLimit, TemplateConnectionRequest); !errors.Is(err, ErrMessageTooLong) {
+		t.Errorf("Expected ErrMessageTooLong for %d LinkedIn characters, got %v", EstimateLinkedInNoteLength(overLimit), err)
+	}
+
+	// Messages keep counting characters
+	if err := ValidateMessageLength(strings.Repeat("🎉", 5000), TemplateFollowUp); err != nil {
+		t.Errorf("Expected a 5000-character message to pass: %v", err)
+	}
+}
+
+func TestTruncateNoteFitsLinkedInCount(t *testing.T) {
+	note := strings.Repeat("Hi 👋\n", 30)
+
+	result := TruncateNote(note, 50)
+	if !utf8.ValidString(result) {
+		t.Fatalf("Truncation split a character: %q", result)
+	}
+	if n := EstimateLinkedInNoteLength(result); n > 50 {
+		t.Errorf("Truncated note counts %d for LinkedIn, over 50", n)
+	}
+	if !strings.HasSuffix(result, "...") {
+		t.Errorf("Expected ellipsis, got %q", result)
+	}
+
+	if short := TruncateNote("Hi 👋", 50); short != "Hi 👋" {
+		t.Errorf("Expected a short note unchanged, got %q", short)
+	}
+}
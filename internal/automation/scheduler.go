@@ -1,8 +1,10 @@
 package automation
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"linkedin-automation/internal/logger"
@@ -10,27 +12,109 @@ import (
 
 // ScheduleConfig holds configuration for activity scheduling
 type ScheduleConfig struct {
-	StartHour    int  // Business hours start (default: 9 AM)
-	EndHour      int  // Business hours end (default: 5 PM)
-	WeekdaysOnly bool // Only operate on weekdays (Monday-Friday)
+	StartHour      int    // Business hours start (default: 9 AM)
+	StartMinute    int    // Minutes past StartHour, e.g. 30 for 9:30 (default: 0)
+	EndHour        int    // Business hours end (default: 5 PM)
+	EndMinute      int    // Minutes past EndHour, e.g. 30 for 17:30 (default: 0)
+	WeekdaysOnly   bool   // Only operate on weekdays (Monday-Friday)
+	TargetTimezone string // IANA zone of the target audience, e.g. "America/New_York" (default: server local time)
+	Clock          Clock  // Time source (default: SystemClock)
+}
+
+// now returns the current time from the configured clock
+func (c ScheduleConfig) now() time.Time {
+	if c.Clock == nil {
+		return SystemClock.Now()
+	}
+	return c.Clock.Now()
+}
+
+// sleep waits on the configured clock
+func (c ScheduleConfig) sleep(d time.Duration) {
+	if c.Clock == nil {
+		SystemClock.Sleep(d)
+		return
+	}
+	c.Clock.Sleep(d)
+}
+
+// startOfWindow returns the start of active hours in minutes since midnight
+func (c ScheduleConfig) startOfWindow() int {
+	return c.StartHour*60 + c.StartMinute
+}
+
+// endOfWindow returns the end of active hours in minutes since midnight
+func (c ScheduleConfig) endOfWindow() int {
+	return c.EndHour*60 + c.EndMinute
+}
+
+// minuteOfDay returns the minutes since midnight of t's wall-clock time
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// formatClock formats an hour and minute as H:MM (e.g. 9:30)
+func formatClock(hour, minute int) string {
+	return fmt.Sprintf("%d:%02d", hour, minute)
+}
+
+// parseClockTime parses an active-hours value: a whole hour ("9") or
+// hour and minutes ("09:30"). ok is false for malformed or out-of-range values.
+func parseClockTime(value string) (hour, minute int, ok bool) {
+	hourPart, minutePart, hasMinutes := strings.Cut(strings.TrimSpace(value), ":")
+
+	hour, err := strconv.Atoi(hourPart)
+	if err != nil || hour < 0 || hour >= 24 {
+		return 0, 0, false
+	}
+
+	if hasMinutes {
+		if len(minutePart) != 2 {
+			return 0, 0, false
+		}
+		minute, err = strconv.Atoi(minutePart)
+		if err != nil || minute < 0 || minute >= 60 {
+			return 0, 0, false
+		}
+	}
+
+	return hour, minute, true
+}
+
+// inTargetZone converts t into the audience's timezone so active hours
+// match their business hours. Falls back to t unchanged if no zone is set
+// or the zone cannot be loaded.
+func (c ScheduleConfig) inTargetZone(t time.Time) time.Time {
+	if c.TargetTimezone == "" {
+		return t
+	}
+
+	loc, err := time.LoadLocation(c.TargetTimezone)
+	if err != nil {
+		logger.Warning("Invalid target timezone " + c.TargetTimezone + ", using local time: " + err.Error())
+		return t
+	}
+
+	return t.In(loc)
 }
 
 // GetDefaultSchedule returns the default scheduling configuration
 func GetDefaultSchedule() ScheduleConfig {
 	// Try to get from environment variables
-	startHour := 9
-	endHour := 17
+	startHour, startMinute := 9, 0
+	endHour, endMinute := 17, 0
 	weekdaysOnly := true
 
+	// Accepts a whole hour ("9") or hour and minutes ("09:30")
 	if envStart := os.Getenv("ACTIVE_HOURS_START"); envStart != "" {
-		if h, err := strconv.Atoi(envStart); err == nil && h >= 0 && h < 24 {
-			startHour = h
+		if h, m, ok := parseClockTime(envStart); ok {
+			startHour, startMinute = h, m
 		}
 	}
 
 	if envEnd := os.Getenv("ACTIVE_HOURS_END"); envEnd != "" {
-		if h, err := strconv.Atoi(envEnd); err == nil && h >= 0 && h < 24 {
-			endHour = h
+		if h, m, ok := parseClockTime(envEnd); ok {
+			endHour, endMinute = h, m
 		}
 	}
 
@@ -39,9 +123,12 @@ func GetDefaultSchedule() ScheduleConfig {
 	}
 
 	return ScheduleConfig{
-		StartHour:    startHour,
-		EndHour:      endHour,
-		WeekdaysOnly: weekdaysOnly,
+		StartHour:      startHour,
+		StartMinute:    startMinute,
+		EndHour:        endHour,
+		EndMinute:      endMinute,
+		WeekdaysOnly:   weekdaysOnly,
+		TargetTimezone: os.Getenv("TARGET_TIMEZONE"),
 	}
 }
 
@@ -51,9 +138,14 @@ func IsActiveHours() bool {
 	return IsActiveHoursWithConfig(GetDefaultSchedule())
 }
 
-// IsActiveHoursWithConfig checks if the current time is within configured hours
+// IsActiveHoursWithConfig checks if the current time (from config.Clock) is within configured hours
 func IsActiveHoursWithConfig(config ScheduleConfig) bool {
-	now := time.Now()
+	return isActiveHoursAt(config.now(), config)
+}
+
+// isActiveHoursAt checks if now is within configured hours, evaluated in the target timezone
+func isActiveHoursAt(now time.Time, config ScheduleConfig) bool {
+	now = config.inTargetZone(now)
 
 	// Check if it's a weekday (Monday = 1, Sunday = 0)
 	if config.WeekdaysOnly {
@@ -64,11 +156,11 @@ func IsActiveHoursWithConfig(config ScheduleConfig) bool {
 		}
 	}
 
-	// Check if it's within business hours
-	currentHour := now.Hour()
-	if currentHour < config.StartHour || currentHour >= config.EndHour {
-		logger.Debug("Outside active hours: Current hour " + strconv.Itoa(currentHour) +
-			" not in range " + strconv.Itoa(config.StartHour) + "-" + strconv.Itoa(config.EndHour))
+	// Check if it's within business hours (to the minute)
+	current := minuteOfDay(now)
+	if current < config.startOfWindow() || current >= config.endOfWindow() {
+		logger.Debug("Outside active hours: Current time " + formatClock(now.Hour(), now.Minute()) +
+			" not in range " + formatClock(config.StartHour, config.StartMinute) + "-" + formatClock(config.EndHour, config.EndMinute))
 		return false
 	}
 
@@ -87,7 +179,7 @@ func WaitForActiveHoursWithConfig(config ScheduleConfig) {
 		return
 	}
 
-	now := time.Now()
+	now := config.now()
 
 	// Calculate next active time
 	nextActive := CalculateNextActiveTime(now, config)
@@ -96,40 +188,37 @@ func WaitForActiveHoursWithConfig(config ScheduleConfig) {
 	logger.Info("Outside active hours. Waiting until " + nextActive.Format("2006-01-02 15:04:05") +
 		" (" + waitDuration.String() + ")")
 
-	time.Sleep(waitDuration)
+	config.sleep(waitDuration)
 
 	logger.Info("Active hours resumed")
 }
 
 // CalculateNextActiveTime calculates the next time when automation should run
+// The result is expressed in the target timezone when one is configured
 func CalculateNextActiveTime(current time.Time, config ScheduleConfig) time.Time {
-	// Start with today at the start hour
-	nextActive := time.Date(
-		current.Year(), current.Month(), current.Day(),
-		config.StartHour, 0, 0, 0, current.Location(),
-	)
+	current = config.inTargetZone(current)
 
-	// If we're already past start hour today, move to tomorrow
-	if current.Hour() >= config.EndHour {
-		nextActive = nextActive.Add(24 * time.Hour)
+	// Work in calendar days: a day is not always 24h across DST transitions,
+	// so step the date with AddDate and rebuild the wall-clock time with time.Date
+	day := current
+
+	// If we're already past the end of active hours today, move to tomorrow
+	if minuteOfDay(current) >= config.endOfWindow() {
+		day = day.AddDate(0, 0, 1)
 	}
 
 	// Skip weekends if configured
 	if config.WeekdaysOnly {
-		for {
-			weekday := nextActive.Weekday()
-			if weekday == time.Saturday {
-				// Skip to Monday
-				nextActive = nextActive.Add(48 * time.Hour)
-			} else if weekday == time.Sunday {
-				// Skip to Monday
-				nextActive = nextActive.Add(24 * time.Hour)
-			} else {
-				break
-			}
+		for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			day = day.AddDate(0, 0, 1)
 		}
 	}
 
+	nextActive := time.Date(
+		day.Year(), day.Month(), day.Day(),
+		config.StartHour, config.StartMinute, 0, 0, current.Location(),
+	)
+
 	return nextActive
 }
 
@@ -144,7 +233,7 @@ func GetTimeUntilNextActiveWithConfig(config ScheduleConfig) time.Duration {
 		return 0
 	}
 
-	now := time.Now()
+	now := config.now()
 	nextActive := CalculateNextActiveTime(now, config)
 	return nextActive.Sub(now)
 }
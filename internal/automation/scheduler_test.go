@@ -46,20 +46,52 @@ func TestIsActiveHoursWithConfig(t *testing.T) {
 }
 
 func TestIsActiveHoursWeekendDetection(t *testing.T) {
-	// Create a config that excludes weekends
+	clock := NewMockClock(time.Date(2025, 12, 27, 10, 0, 0, 0, time.UTC)) // Saturday
 	weekdayOnly := ScheduleConfig{
 		StartHour:    0,
 		EndHour:      23,
 		WeekdaysOnly: true,
+		Clock:        clock,
 	}
 
-	// We can't easily test this without mocking time, but we can verify
-	// it doesn't crash and returns a boolean
-	result := IsActiveHoursWithConfig(weekdayOnly)
+	if IsActiveHoursWithConfig(weekdayOnly) {
+		t.Error("Expected inactive on Saturday with WeekdaysOnly")
+	}
+
+	clock.Advance(24 * time.Hour) // Sunday
+	if IsActiveHoursWithConfig(weekdayOnly) {
+		t.Error("Expected inactive on Sunday with WeekdaysOnly")
+	}
+
+	clock.Advance(24 * time.Hour) // Monday
+	if !IsActiveHoursWithConfig(weekdayOnly) {
+		t.Error("Expected active on Monday with WeekdaysOnly")
+	}
 
-	// Check that it returns a boolean
-	if result != true && result != false {
-		t.Error("IsActiveHoursWithConfig should return a boolean")
+	weekdayOnly.WeekdaysOnly = false
+	clock.Set(time.Date(2025, 12, 27, 10, 0, 0, 0, time.UTC))
+	if !IsActiveHoursWithConfig(weekdayOnly) {
+		t.Error("Expected active on Saturday without WeekdaysOnly")
+	}
+}
+
+func TestIsActiveHoursMidnightBoundary(t *testing.T) {
+	// Friday 23:59:59 -> Saturday 00:00:00
+	clock := NewMockClock(time.Date(2025, 12, 26, 23, 59, 59, 0, time.UTC))
+	config := ScheduleConfig{StartHour: 0, EndHour: 24, WeekdaysOnly: true, Clock: clock}
+
+	if !IsActiveHoursWithConfig(config) {
+		t.Error("Expected active at Friday 23:59:59")
+	}
+
+	clock.Advance(time.Second)
+	if IsActiveHoursWithConfig(config) {
+		t.Error("Expected inactive at Saturday 00:00:00")
+	}
+
+	// Outside hours, the wait ends at Monday's start hour
+	if wait := GetTimeUntilNextActiveWithConfig(config); wait != 48*time.Hour {
+		t.Errorf("Expected 48h until Monday 00:00, got %v", wait)
 	}
 }
 
@@ -170,3 +202,224 @@ func BenchmarkCalculateNextActiveTime(b *testing.B) {
 		CalculateNextActiveTime(now, config)
 	}
 }
+
+func TestIsActiveHoursInTargetTimezone(t *testing.T) {
+	// Tuesday 2025-12-30 15:00 UTC
+	now := time.Date(2025, 12, 30, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone string
+		expected bool
+	}{
+		{"UTC", true},                  // 15:00 - business hours
+		{"America/New_York", true},     // 10:00 - business hours
+		{"America/Los_Angeles", false}, // 07:00 - too early
+		{"Asia/Tokyo", false},          // 00:00 Wednesday - night
+		{"Europe/Berlin", true},        // 16:00 - business hours
+	}
+
+	for _, test := range tests {
+		config := ScheduleConfig{StartHour: 9, EndHour: 17, WeekdaysOnly: true, TargetTimezone: test.timezone}
+		if result := isActiveHoursAt(now, config); result != test.expected {
+			t.Errorf("%s: expected active=%v, got %v", test.timezone, test.expected, result)
+		}
+	}
+}
+
+func TestIsActiveHoursTargetTimezoneWeekend(t *testing.T) {
+	// Monday 2025-12-29 02:00 UTC is still Sunday evening in Los Angeles
+	now := time.Date(2025, 12, 29, 2, 0, 0, 0, time.UTC)
+	config := ScheduleConfig{StartHour: 0, EndHour: 23, WeekdaysOnly: true, TargetTimezone: "America/Los_Angeles"}
+
+	if isActiveHoursAt(now, config) {
+		t.Error("Expected inactive: it is Sunday in the target timezone")
+	}
+}
+
+func TestIsActiveHoursInvalidTimezoneFallsBack(t *testing.T) {
+	now := time.Date(2025, 12, 30, 10, 0, 0, 0, time.UTC)
+	config := ScheduleConfig{StartHour: 9, EndHour: 17, TargetTimezone: "Not/AZone"}
+
+	if !isActiveHoursAt(now, config) {
+		t.Error("Expected invalid timezone to fall back to the time's own zone")
+	}
+}
+
+func TestCalculateNextActiveTimeTargetTimezone(t *testing.T) {
+	// Tuesday 2025-12-30 23:00 UTC is 18:00 in New York, after business hours
+	current := time.Date(2025, 12, 30, 23, 0, 0, 0, time.UTC)
+	config := ScheduleConfig{StartHour: 9, EndHour: 17, TargetTimezone: "America/New_York"}
+
+	nextActive := CalculateNextActiveTime(current, config)
+
+	// Next active is Wednesday 09:00 New York time = 14:00 UTC
+	expected := time.Date(2025, 12, 31, 14, 0, 0, 0, time.UTC)
+	if !nextActive.Equal(expected) {
+		t.Errorf("Expected next active %v, got %v", expected, nextActive.UTC())
+	}
+	if nextActive.Location().String() != "America/New_York" {
+		t.Errorf("Expected result in target timezone, got %s", nextActive.Location())
+	}
+}
+
+func TestIsActiveHoursDuringDSTTransition(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	// Monday 2026-03-09 13:30 UTC is 09:30 EDT (it would be 08:30 under EST)
+	clock := NewMockClock(time.Date(2026, 3, 9, 13, 30, 0, 0, time.UTC))
+	config := ScheduleConfig{StartHour: 9, EndHour: 17, WeekdaysOnly: true, TargetTimezone: ny.String(), Clock: clock}
+
+	if !IsActiveHoursWithConfig(config) {
+		t.Error("Expected active at 09:30 EDT after the spring-forward transition")
+	}
+}
+
+func TestCalculateNextActiveTimeOverDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		weekdaysOnly bool
+		current      time.Time
+		expected     time.Time
+		realHours    time.Duration
+	}{
+		{
+			// Adding a literal 24h would land at 10:00 EDT
+			name:      "spring forward (23h day)",
+			current:   time.Date(2026, 3, 7, 18, 0, 0, 0, ny),
+			expected:  time.Date(2026, 3, 8, 9, 0, 0, 0, ny),
+			realHours: 14 * time.Hour,
+		},
+		{
+			// Adding a literal 24h would land at 08:00 EST
+			name:      "fall back (25h day)",
+			current:   time.Date(2026, 10, 31, 18, 0, 0, 0, ny),
+			expected:  time.Date(2026, 11, 1, 9, 0, 0, 0, ny),
+			realHours: 16 * time.Hour,
+		},
+		{
+			// Clocks spring forward on Sunday 2026-03-08
+			name:         "spring forward over weekend",
+			weekdaysOnly: true,
+			current:      time.Date(2026, 3, 6, 18, 0, 0, 0, ny), // Friday evening
+			expected:     time.Date(2026, 3, 9, 9, 0, 0, 0, ny),  // Monday 09:00
+			realHours:    62 * time.Hour,
+		},
+		{
+			// Clocks fall back on Sunday 2026-11-01
+			name:         "fall back over weekend",
+			weekdaysOnly: true,
+			current:      time.Date(2026, 10, 30, 18, 0, 0, 0, ny), // Friday evening
+			expected:     time.Date(2026, 11, 2, 9, 0, 0, 0, ny),   // Monday 09:00
+			realHours:    64 * time.Hour,
+		},
+		{
+			name:         "saturday evening before fall back",
+			weekdaysOnly: true,
+			current:      time.Date(2026, 10, 31, 20, 0, 0, 0, ny),
+			expected:     time.Date(2026, 11, 2, 9, 0, 0, 0, ny),
+			realHours:    38 * time.Hour,
+		},
+	}
+
+	for _, test := range tests {
+		config := ScheduleConfig{StartHour: 9, EndHour: 17, WeekdaysOnly: test.weekdaysOnly}
+		nextActive := CalculateNextActiveTime(test.current, config)
+		if !nextActive.Equal(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, nextActive)
+		}
+		if nextActive.Hour() != config.StartHour {
+			t.Errorf("%s: expected wall-clock hour %d, got %d", test.name, config.StartHour, nextActive.Hour())
+		}
+		if wait := nextActive.Sub(test.current); wait != test.realHours {
+			t.Errorf("%s: expected %v of real time, got %v", test.name, test.realHours, wait)
+		}
+	}
+}
+
+func TestIsActiveHoursMinutePrecision(t *testing.T) {
+	config := ScheduleConfig{StartHour: 9, StartMinute: 30, EndHour: 17, EndMinute: 30}
+
+	tests := []struct {
+		hour, minute int
+		expected     bool
+	}{
+		{9, 29, false},
+		{9, 30, true},
+		{9, 31, true},
+		{17, 29, true},
+		{17, 30, false},
+		{17, 31, false},
+	}
+
+	for _, test := range tests {
+		now := time.Date(2025, 12, 30, test.hour, test.minute, 0, 0, time.UTC)
+		if result := isActiveHoursAt(now, config); result != test.expected {
+			t.Errorf("%02d:%02d: expected active=%v, got %v", test.hour, test.minute, test.expected, result)
+		}
+	}
+}
+
+func TestCalculateNextActiveTimeMinutePrecision(t *testing.T) {
+	config := ScheduleConfig{StartHour: 9, StartMinute: 30, EndHour: 17, EndMinute: 30}
+
+	// 17:15 is still inside the window, so the next start is today
+	current := time.Date(2025, 12, 30, 17, 15, 0, 0, time.UTC)
+	expected := time.Date(2025, 12, 30, 9, 30, 0, 0, time.UTC)
+	if next := CalculateNextActiveTime(current, config); !next.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, next)
+	}
+
+	// 17:30 is past the end, so the next start is tomorrow at 9:30
+	current = time.Date(2025, 12, 30, 17, 30, 0, 0, time.UTC)
+	expected = time.Date(2025, 12, 31, 9, 30, 0, 0, time.UTC)
+	if next := CalculateNextActiveTime(current, config); !next.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, next)
+	}
+}
+
+func TestParseClockTime(t *testing.T) {
+	tests := []struct {
+		value        string
+		hour, minute int
+		ok           bool
+	}{
+		{"9", 9, 0, true},
+		{"17", 17, 0, true},
+		{"09:30", 9, 30, true},
+		{" 17:45 ", 17, 45, true},
+		{"9:5", 0, 0, false},
+		{"24:00", 0, 0, false},
+		{"09:60", 0, 0, false},
+		{"nine", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		hour, minute, ok := parseClockTime(test.value)
+		if ok != test.ok || hour != test.hour || minute != test.minute {
+			t.Errorf("parseClockTime(%q) = %d, %d, %v; want %d, %d, %v",
+				test.value, hour, minute, ok, test.hour, test.minute, test.ok)
+		}
+	}
+}
+
+func TestGetDefaultScheduleMinutesFromEnv(t *testing.T) {
+	t.Setenv("ACTIVE_HOURS_START", "09:30")
+	t.Setenv("ACTIVE_HOURS_END", "17")
+
+	config := GetDefaultSchedule()
+	if config.StartHour != 9 || config.StartMinute != 30 {
+		t.Errorf("Expected start 9:30, got %d:%02d", config.StartHour, config.StartMinute)
+	}
+	if config.EndHour != 17 || config.EndMinute != 0 {
+		t.Errorf("Expected end 17:00, got %d:%02d", config.EndHour, config.EndMinute)
+	}
+}
@@ -1,13 +1,20 @@
 package automation
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"math/rand"
 	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
 
+	"linkedin-automation/internal/browser"
 	"linkedin-automation/internal/logger"
 	"linkedin-automation/internal/stealth"
 	"linkedin-automation/internal/storage"
@@ -21,14 +28,52 @@ type SearchConfig struct {
 	Company  string // Filter by company name
 	Location string // Location name (e.g., "San Francisco Bay Area")
 
+	// geoUrn ID for a Location missing from utils.LinkedInLocations, resolved
+	// through LinkedIn's location typeahead (see ResolveLocationURN)
+	LocationURN string
+
+	// Only people currently at Company (currentCompany facet) rather than any
+	// profile mentioning it. Needs Company in utils.LinkedInCompanies.
+	CurrentCompanyOnly bool
+
+	// Post-filters on the scraped title (case-insensitive): a result is kept
+	// only if its title has every include keyword and no exclude keyword
+	IncludeTitleKeywords []string
+	ExcludeTitleKeywords []string
+
+	// Drop results showing fewer mutual connections than this (0 = no minimum).
+	// People with more mutual connections accept more often.
+	MinMutualConnections int
+
+	// Keep only results whose photo has the #OpenToWork frame (for recruiting)
+	OpenToWorkOnly bool
+
+	// Connection degree filter (NetworkFirstDegree, NetworkSecondDegree, NetworkThirdDegree)
+	Network []string
+
 	// Pagination settings
 	MaxPages int // Maximum number of pages to scrape (0 = all available)
 
+	// Start page settings - starting deeper in the results reaches different cohorts
+	StartPage       int  // Results page to start from (0 or 1 = first page)
+	RandomStartPage bool // Pick a random start page between 1 and StartPageMax
+	StartPageMax    int  // Upper bound for the random start page (default: 10)
+
 	// Duplicate handling
 	SkipDuplicates bool // Skip profiles visited in last 30 days
 	DuplicateDays  int  // Days to consider as duplicate (default: 30)
+
+	// Skip the search entirely if the same filters already ran today
+	SkipIfRunToday bool
 }
 
+// Connection degree values for the SearchConfig.Network filter
+const (
+	NetworkFirstDegree  = "F"
+	NetworkSecondDegree = "S"
+	NetworkThirdDegree  = "O"
+)
+
 // SearchResult represents a parsed profile from search results
 type SearchResult struct {
 	ProfileID  string    // Extracted from URL
@@ -39,6 +84,9 @@ type SearchResult struct {
 	ProfileURL string    // Full LinkedIn profile URL
 	Degree     string    // Connection degree (1st, 2nd, 3rd)
 	ScrapedAt  time.Time // When this result was found
+
+	MutualCount int  // Mutual connections shown on the card (0 = none shown)
+	OpenToWork  bool // The photo has the #OpenToWork frame
 }
 
 // SearchStats tracks statistics for a search session
@@ -46,14 +94,24 @@ type SearchStats struct {
 	TotalFound   int
 	NewProfiles  int
 	Duplicates   int
+	Filtered     int // Results dropped by the title keyword, mutual connection and #OpenToWork filters
 	PagesScraped int
 	ErrorCount   int
+	Skipped      bool // Search did not run because it already ran today
 	StartTime    time.Time
 	EndTime      time.Time
 }
 
 // SearchPeople performs a LinkedIn people search with the given configuration
 func SearchPeople(page *rod.Page, db *storage.Database, config SearchConfig) ([]SearchResult, *SearchStats, error) {
+	audit(db, AuditActionSearch, "", storage.AuditStarted, fmt.Sprintf("keywords='%s'", config.Keywords))
+	results, stats, err := searchPeople(page, db, config)
+	auditResult(db, AuditActionSearch, "", err, fmt.Sprintf("%d profiles", len(results)))
+	return results, stats, err
+}
+
+// searchPeople does the work of SearchPeople
+func searchPeople(page *rod.Page, db *storage.Database, config SearchConfig) ([]SearchResult, *SearchStats, error) {
 	logger.Info("Starting LinkedIn people search")
 	logger.Info(fmt.Sprintf("Search parameters: keywords='%s', title='%s', company='%s', location='%s'",
 		config.Keywords, config.JobTitle, config.Company, config.Location))
@@ -63,6 +121,20 @@ func SearchPeople(page *rod.Page, db *storage.Database, config SearchConfig) ([]
 	}
 	var allResults []SearchResult
 
+	// Avoid doubling the scraping load when the same search is triggered twice a day
+	hash := searchHash(config)
+	if config.SkipIfRunToday && db != nil {
+		ranToday, err := db.HasRunSearchToday(hash)
+		if err != nil {
+			logger.Warning("Failed to check previous search runs: " + err.Error())
+		} else if ranToday {
+			logger.Info("Identical search already ran today, skipping (hash " + hash[:12] + ")")
+			stats.Skipped = true
+			stats.EndTime = time.Now()
+			return nil, stats, nil
+		}
+	}
+
 	// Set default values
 	if config.MaxPages == 0 {
 		config.MaxPages = utils.MaxPaginationPages
@@ -74,6 +146,15 @@ func SearchPeople(page *rod.Page, db *storage.Database, config SearchConfig) ([]
 		config.SkipDuplicates = true // Default to skip duplicates
 	}
 
+	// Pick a random start page so different runs reach different cohorts
+	if config.RandomStartPage {
+		r := utils.SessionRand()
+		config.StartPage = chooseStartPage(config, r)
+		logger.Info(fmt.Sprintf("Randomized start page: %d", config.StartPage))
+	}
+
+	config.LocationURN = locationURNFor(page, db, config)
+
 	// Build search URL
 	searchURL, err := buildSearchURL(config)
 	if err != nil {
@@ -83,7 +164,7 @@ func SearchPeople(page *rod.Page, db *storage.Database, config SearchConfig) ([]
 	logger.Info("Navigating to search URL: " + searchURL)
 
 	// Navigate to search page
-	err = page.Navigate(searchURL)
+	err = navigate(page, db, searchURL, "")
 	if err != nil {
 		return nil, stats, fmt.Errorf("failed to navigate to search page: %w", err)
 	}
@@ -92,11 +173,17 @@ func SearchPeople(page *rod.Page, db *storage.Database, config SearchConfig) ([]
 	page.MustWaitLoad()
 	time.Sleep(2 * time.Second) // Additional wait for dynamic content
 
-	// Check for LinkedIn checkpoint/verification page
+	// Check for checkpoint or login wall redirects before blaming selectors
 	currentURL := page.MustInfo().URL
-	if utils.IsLinkedInCheckpoint(currentURL) {
-		logger.Error("❌ LinkedIn checkpoint/verification detected at: " + currentURL)
-		return nil, stats, fmt.Errorf("linkedin checkpoint detected, manual verification required")
+	if err := checkSearchPageAccess(currentURL); err != nil {
+		logger.Error("❌ Search page not accessible at " + currentURL + ": " + err.Error())
+		return nil, stats, err
+	}
+
+	// The commercial use limit still renders the search page, just without results
+	if commercialUseLimitReached(page) {
+		logger.Error("❌ LinkedIn commercial use limit reached - profile searches are blocked until the monthly reset")
+		return nil, stats, fmt.Errorf("opening search: %w", ErrCommercialUseLimit)
 	}
 
 	// Apply stealth actions
@@ -125,6 +212,11 @@ func SearchPeople(page *rod.Page, db *storage.Database, config SearchConfig) ([]
 
 		// Process each result
 		for _, result := range results {
+			if !cardWanted(result, config) {
+				stats.Filtered++
+				continue
+			}
+
 			// Check for duplicates if enabled
 			if config.SkipDuplicates && db != nil {
 				isDupe, err := db.IsDuplicateProfile(result.ProfileID, config.DuplicateDays)
@@ -139,18 +231,7 @@ func SearchPeople(page *rod.Page, db *storage.Database, config SearchConfig) ([]
 
 			// Save new profile to database
 			if db != nil {
-				profile := storage.Profile{
-					ID:         result.ProfileID,
-					Name:       result.Name,
-					Title:      result.Title,
-					Company:    result.Company,
-					Location:   result.Location,
-					ProfileURL: result.ProfileURL,
-					VisitedAt:  result.ScrapedAt,
-					CreatedAt:  result.ScrapedAt,
-				}
-
-				err := db.SaveProfile(profile)
+				err := db.SaveProfile(searchResultProfile(result))
 				if err != nil {
 					logger.Warning(fmt.Sprintf("Failed to save profile %s: %s", result.ProfileID, err.Error()))
 					stats.ErrorCount++
@@ -168,11 +249,19 @@ func SearchPeople(page *rod.Page, db *storage.Database, config SearchConfig) ([]
 		break
 	}
 
+	// Record the run so a repeat today can be skipped. A search that found
+	// nothing (parse failure, selector change) stays eligible for a retry.
+	if db != nil && searchReturnedResults(stats) {
+		if err := db.RecordSearchRun(hash); err != nil {
+			logger.Warning("Failed to record search run: " + err.Error())
+		}
+	}
+
 	stats.EndTime = time.Now()
 	duration := stats.EndTime.Sub(stats.StartTime)
 
-	logger.Info(fmt.Sprintf("Search completed: %d total found, %d new profiles, %d duplicates, %d pages scraped in %s",
-		stats.TotalFound, stats.NewProfiles, stats.Duplicates, stats.PagesScraped, duration))
+	logger.Info(fmt.Sprintf("Search completed: %d total found, %d new profiles, %d duplicates, %d filtered, %d pages scraped in %s",
+		stats.TotalFound, stats.NewProfiles, stats.Duplicates, stats.Filtered, stats.PagesScraped, duration))
 
 	return allResults, stats, nil
 }
@@ -192,14 +281,27 @@ func buildSearchURL(config SearchConfig) (string, error) {
 		params.Add("title", config.JobTitle)
 	}
 
-	// Add company filter
+	// Add company filter: the currentCompany facet when asked for and the
+	// company's ID is known, otherwise the free-text company keyword
 	if config.Company != "" {
-		params.Add("company", config.Company)
+		companyID, found := companyFacetID(config.Company)
+		switch {
+		case config.CurrentCompanyOnly && found:
+			params.Add("currentCompany", fmt.Sprintf("[%q]", companyID))
+		case config.CurrentCompanyOnly:
+			logger.Warning(fmt.Sprintf("Company '%s' not found in company map, searching by keyword (includes past employees)", config.Company))
+			params.Add("company", config.Company)
+		default:
+			params.Add("company", config.Company)
+		}
 	}
 
 	// Add location filter (convert name to URN)
 	if config.Location != "" {
 		locationURN, found := utils.LinkedInLocations[config.Location]
+		if !found && config.LocationURN != "" {
+			locationURN, found = config.LocationURN, true
+		}
 		if found {
 			params.Add("geoUrn", fmt.Sprintf("[\"urn:li:fs_geo:%s\"]", locationURN))
 		} else {
@@ -207,15 +309,154 @@ func buildSearchURL(config SearchConfig) (string, error) {
 		}
 	}
 
+	// Add connection degree filter (e.g. network=["F"] for 1st-degree connections)
+	if len(config.Network) > 0 {
+		quoted := make([]string, len(config.Network))
+		for i, degree := range config.Network {
+			quoted[i] = fmt.Sprintf("%q", degree)
+		}
+		params.Add("network", "["+strings.Join(quoted, ",")+"]")
+	}
+
 	// Build final URL
 	if len(params) == 0 {
 		return "", fmt.Errorf("at least one search parameter is required")
 	}
 
+	// Add start page (LinkedIn's first page has no page param)
+	if config.StartPage > 1 {
+		params.Add("page", strconv.Itoa(config.StartPage))
+	}
+
 	fullURL := baseURL + "?" + params.Encode()
 	return fullURL, nil
 }
 
+// locationURNFor resolves the search's Location when the static location map
+// lacks it ("" when it is in the map, unset or unresolvable)
+func locationURNFor(page *rod.Page, db *storage.Database, config SearchConfig) string {
+	if config.Location == "" || config.LocationURN != "" {
+		return config.LocationURN
+	}
+	if _, found := utils.LinkedInLocations[config.Location]; found {
+		return ""
+	}
+	urn, _ := ResolveLocationURN(page, db, config.Location)
+	return urn
+}
+
+// companyFacetID looks up a company's currentCompany facet ID, ignoring case
+func companyFacetID(company string) (string, bool) {
+	company = strings.TrimSpace(company)
+	for name, id := range utils.LinkedInCompanies {
+		if strings.EqualFold(name, company) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// searchReturnedResults reports whether a finished search scraped any result
+// cards, i.e. whether it counts as a run for SkipIfRunToday
+func searchReturnedResults(stats *SearchStats) bool {
+	return stats.PagesScraped > 0 && stats.TotalFound > 0
+}
+
+// searchHash identifies a search by its filters, so the same search can be
+// recognized across runs. Pagination settings are excluded because a random
+// start page still targets the same audience.
+func searchHash(config SearchConfig) string {
+	network := append([]string(nil), config.Network...)
+	sort.Strings(network)
+
+	normalize := func(value string) string {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+
+	key := strings.Join([]string{
+		normalize(config.Keywords),
+		normalize(config.JobTitle),
+		normalize(config.Company),
+		normalize(config.Location),
+		strings.Join(network, ","),
+	}, "|")
+
+	// Appended only when set so hashes of existing searches stay the same
+	if config.CurrentCompanyOnly {
+		key += "|current-company"
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkSearchPageAccess classifies the URL reached after navigating to a search.
+// A dead session redirects to /login or /authwall, which would otherwise parse as
+// zero results and be misreported as a selector change.
+func checkSearchPageAccess(currentURL string) error {
+	if utils.IsLinkedInCheckpoint(currentURL) {
+		return fmt.Errorf("opening search: %w", ErrCheckpoint)
+	}
+
+	if utils.IsLinkedInLoginWall(currentURL) {
+		return fmt.Errorf("search redirected to login wall: %w", ErrNotAuthenticated)
+	}
+
+	return nil
+}
+
+// commercialUseLimitPattern matches the commercial use limit message
+var commercialUseLimitPattern = regexp.MustCompile(utils.CommercialUseLimitTextPattern)
+
+// commercialUseLimitReached reports whether the search page shows the commercial use limit warning
+func commercialUseLimitReached(page *rod.Page) bool {
+	notices, err := page.Timeout(2 * time.Second).Elements(utils.Selectors.CommercialUseLimit)
+	if err != nil {
+		return false
+	}
+
+	for _, notice := range notices {
+		text, err := notice.Text()
+		if err == nil && isCommercialUseLimitText(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCommercialUseLimitText reports whether notice text is the commercial use limit message
+func isCommercialUseLimitText(text string) bool {
+	return commercialUseLimitPattern.MatchString(text)
+}
+
+// chooseStartPage picks a random results page in [1, StartPageMax]
+// StartPageMax defaults to 10 and is capped at MaxPaginationPages
+func chooseStartPage(config SearchConfig, r *rand.Rand) int {
+	maxPage := config.StartPageMax
+	if maxPage <= 0 {
+		maxPage = 10
+	}
+	if maxPage > utils.MaxPaginationPages {
+		maxPage = utils.MaxPaginationPages
+	}
+
+	return r.Intn(maxPage) + 1
+}
+
+// ParseSearchResultsFromHTML runs ParseSearchResults over a saved search page
+// (see browser.RecordPages) in a headless replay browser, so the parser can be
+// tested offline. Returns an error wrapping browser.ErrNoBrowser if no
+// Chrome/Chromium is installed.
+func ParseSearchResultsFromHTML(html string) ([]SearchResult, error) {
+	page, closeBrowser, err := browser.ReplayPage(html)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBrowser()
+
+	return ParseSearchResults(page)
+}
+
 // ParseSearchResults extracts profile information from the current search results page
 func ParseSearchResults(page *rod.Page) ([]SearchResult, error) {
 	var results []SearchResult
@@ -237,7 +478,8 @@ func ParseSearchResults(page *rod.Page) ([]SearchResult, error) {
 			'.reusable-search-simple-insight__container',
 			'[data-view-name="search-entity-result"]',
 			'.scaffold-layout__list-container li',
-			'.search-results-container li'
+			'.search-results-container li',
+			'[data-chameleon-result-urn]'
 		];
 		const found = [];
 		containers.forEach(sel => {
@@ -251,6 +493,16 @@ func ParseSearchResults(page *rod.Page) ([]SearchResult, error) {
 		logger.Info(fmt.Sprintf("Found selectors on page: %v", foundSelectors.Value))
 	}
 
+	// Newer layout first: its results also sit in li.reusable-search__result-container,
+	// where the legacy parser would only recover the name from the link text
+	if v2Containers, err := page.Elements(utils.Selectors.SearchResultContainerV2); err == nil && len(v2Containers) > 0 {
+		logger.Info(fmt.Sprintf("✓ Found %d results with selector: %s", len(v2Containers), utils.Selectors.SearchResultContainerV2))
+		if results = parseResultContainers(v2Containers, parseProfileFromContainerV2); len(results) > 0 {
+			return results, nil
+		}
+		logger.Warning("No results parsed from the new layout, trying legacy selectors...")
+	}
+
 	// Try multiple selectors since LinkedIn frequently changes their HTML structure
 	var resultContainers rod.Elements
 	var err error
@@ -372,10 +624,16 @@ parseResults:
 		return results, nil // Empty results, not an error
 	}
 
-	logger.Info(fmt.Sprintf("Parsing %d result containers", len(resultContainers)))
+	return parseResultContainers(resultContainers, parseProfileFromContainer), nil
+}
 
-	for i, container := range resultContainers {
-		result, err := parseProfileFromContainer(container)
+// parseResultContainers runs parse over each container, skipping those that fail
+func parseResultContainers(containers rod.Elements, parse func(*rod.Element) (*SearchResult, error)) []SearchResult {
+	logger.Info(fmt.Sprintf("Parsing %d result containers", len(containers)))
+
+	var results []SearchResult
+	for i, container := range containers {
+		result, err := parse(container)
 		if err != nil {
 			logger.Warning(fmt.Sprintf("Failed to parse result %d: %s", i+1, err.Error()))
 			continue
@@ -386,7 +644,7 @@ parseResults:
 		}
 	}
 
-	return results, nil
+	return results
 }
 
 // parseProfileFromContainer extracts profile data from a single result container
@@ -415,8 +673,9 @@ func parseProfileFromContainer(container *rod.Element) (*SearchResult, error) {
 
 		// Check if this is a profile link (and not a shared post or other noise)
 		// Profile links usually look like /in/username
-		if strings.Contains(*href, "/in/") {
+		if profileID := utils.ExtractProfileID(*href); profileID != "" {
 			profileURL = *href
+			result.ProfileID = profileID
 			break
 		}
 	}
@@ -425,26 +684,13 @@ func parseProfileFromContainer(container *rod.Element) (*SearchResult, error) {
 		return nil, fmt.Errorf("no valid profile URL found")
 	}
 
-	// Clean URL (remove query params)
-	if idx := strings.Index(profileURL, "?"); idx != -1 {
+	// Clean URL (remove query params and fragment)
+	if idx := strings.IndexAny(profileURL, "?#"); idx != -1 {
 		profileURL = profileURL[:idx]
 	}
 
 	result.ProfileURL = profileURL
 
-	// Extract profile ID from URL (e.g., /in/john-doe/ -> john-doe)
-	if strings.Contains(profileURL, "/in/") {
-		parts := strings.Split(profileURL, "/in/")
-		if len(parts) >= 2 {
-			profileID := strings.TrimSuffix(parts[1], "/")
-			result.ProfileID = profileID
-		}
-	}
-
-	if result.ProfileID == "" {
-		return nil, fmt.Errorf("could not extract profile ID from URL: %s", profileURL)
-	}
-
 	// Extract name (from title link)
 	titleElement, err := container.Element(".entity-result__title-text a span[aria-hidden='true']")
 	if err == nil {
@@ -484,26 +730,23 @@ func parseProfileFromContainer(container *rod.Element) (*SearchResult, error) {
 	}
 
 	// Extract company/location (secondary subtitle)
-	secondaryElement, err := container.Element(".entity-result__secondary-subtitle")
+	// Often format is "Company | Location" or just "Location"
+	secondaryElement, err := container.Element(utils.Selectors.SearchResultSecondary)
 	if err == nil {
 		secondary, _ := secondaryElement.Text()
-		secondary = strings.TrimSpace(secondary)
+		result.Company, result.Location = utils.SplitCompanyLocation(secondary)
+	}
 
-		// Often format is "Company | Location" or just "Location"
-		if strings.Contains(secondary, " | ") {
-			parts := strings.Split(secondary, " | ")
-			if len(parts) >= 1 {
-				result.Company = strings.TrimSpace(parts[0])
-			}
-			if len(parts) >= 2 {
-				result.Location = strings.TrimSpace(parts[1])
+	// The dedicated location element, when the card has one, beats the subtitle heuristics
+	if locationElement, err := container.Element(utils.Selectors.SearchResultLocation); err == nil {
+		location, _ := locationElement.Text()
+		if location = strings.TrimSpace(location); location != "" {
+			// With the location on its own line, a lone subtitle part is the company
+			if result.Company == "" && !strings.EqualFold(result.Location, location) {
+				result.Company = result.Location
 			}
-		} else {
-			result.Location = secondary
+			result.Location = location
 		}
-	} else {
-		// Fallback: Try to find location in any secondary text
-		// Often location is in a span with class containing 'location' or 'secondary'
 	}
 
 	// Extract connection degree (e.g., "1st", "2nd", "3rd")
@@ -513,14 +756,167 @@ func parseProfileFromContainer(container *rod.Element) (*SearchResult, error) {
 		result.Degree = strings.TrimSpace(degree)
 	}
 
+	// Extract mutual connections (e.g., "Jane Doe and 12 other mutual connections")
+	result.MutualCount = mutualCountFromContainer(container)
+	result.OpenToWork = openToWorkFromContainer(container)
+
+	return result, nil
+}
+
+// parseProfileFromContainerV2 extracts profile data from a result in the newer
+// layout, where the container carries a data-chameleon-result-urn attribute
+func parseProfileFromContainerV2(container *rod.Element) (*SearchResult, error) {
+	var urn string
+	if attr, err := container.Attribute("data-chameleon-result-urn"); err == nil && attr != nil {
+		urn = *attr
+	}
+
+	var href string
+	if links, err := container.Elements("a[href*='/in/']"); err == nil {
+		for _, link := range links {
+			if attr, err := link.Attribute("href"); err == nil && attr != nil && utils.ExtractProfileID(*attr) != "" {
+				href = *attr
+				break
+			}
+		}
+	}
+
+	text := func(selector string) string {
+		if el, err := container.Element(selector); err == nil {
+			value, _ := el.Text()
+			return value
+		}
+		return ""
+	}
+
+	result, err := parseSearchResultV2(urn, href,
+		text(utils.Selectors.SearchResultNameV2),
+		text(utils.Selectors.SearchResultHeadlineV2),
+		text(utils.Selectors.SearchResultLocationV2),
+		text(utils.Selectors.SearchResultDegreeV2))
+	if err != nil {
+		return nil, err
+	}
+
+	result.MutualCount = mutualCountFromContainer(container)
+	result.OpenToWork = openToWorkFromContainer(container)
 	return result, nil
 }
 
+// parseSearchResultV2 builds a SearchResult from the raw text scraped off a
+// new-layout result. The profile link gives the ID; the URN is the fallback.
+func parseSearchResultV2(urn, href, name, headline, location, degree string) (*SearchResult, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.Contains(name, "LinkedIn Member") {
+		// Out-of-network results hide the name and have no usable profile link
+		return nil, fmt.Errorf("no visible name for %s", urn)
+	}
+
+	result := &SearchResult{
+		Name:      name,
+		Location:  strings.TrimSpace(location),
+		Degree:    strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(degree), "•")),
+		ScrapedAt: time.Now(),
+	}
+
+	if profileID := utils.ExtractProfileID(href); profileID != "" {
+		result.ProfileID = profileID
+		result.ProfileURL = href
+		if idx := strings.IndexAny(href, "?#"); idx != -1 {
+			result.ProfileURL = href[:idx]
+		}
+	} else if profileID := utils.ExtractProfileID(urn); profileID != "" {
+		result.ProfileID = profileID
+		result.ProfileURL = utils.LinkedInProfileBase + profileID + "/"
+	} else {
+		return nil, fmt.Errorf("no profile link or URN found for %s", name)
+	}
+
+	result.Title, result.Company = splitHeadline(headline)
+	return result, nil
+}
+
+// splitHeadline splits a "Role at Company" headline. Headlines without " at "
+// are returned whole as the title.
+func splitHeadline(headline string) (title, company string) {
+	headline = strings.TrimSpace(headline)
+	if idx := strings.LastIndex(headline, " at "); idx != -1 {
+		return strings.TrimSpace(headline[:idx]), strings.TrimSpace(headline[idx+len(" at "):])
+	}
+	return headline, ""
+}
+
+// cardWanted applies the search's title keyword, mutual connection and
+// #OpenToWork filters to a result card
+func cardWanted(result SearchResult, config SearchConfig) bool {
+	if !matchesTitleKeywords(result.Title, config.IncludeTitleKeywords, config.ExcludeTitleKeywords) {
+		logger.Info(fmt.Sprintf("Skipping %s, title filtered out: %s", result.Name, result.Title))
+		return false
+	}
+	if result.MutualCount < config.MinMutualConnections {
+		logger.Info(fmt.Sprintf("Skipping %s, %d mutual connections (minimum %d)", result.Name, result.MutualCount, config.MinMutualConnections))
+		return false
+	}
+	if config.OpenToWorkOnly && !result.OpenToWork {
+		logger.Info(fmt.Sprintf("Skipping %s, not #OpenToWork", result.Name))
+		return false
+	}
+	return true
+}
+
+// searchResultProfile is the profile record saved for a search result
+func searchResultProfile(result SearchResult) storage.Profile {
+	return storage.Profile{
+		ID:         result.ProfileID,
+		Name:       result.Name,
+		Title:      result.Title,
+		Company:    result.Company,
+		Location:   result.Location,
+		ProfileURL: result.ProfileURL,
+		VisitedAt:  result.ScrapedAt,
+		CreatedAt:  result.ScrapedAt,
+
+		MutualCount: result.MutualCount,
+		OpenToWork:  result.OpenToWork,
+
+		ContextSource: ContextSourceSearch,
+	}
+}
+
+// matchesTitleKeywords reports whether title contains every include keyword
+// and none of the exclude keywords, ignoring case. Empty lists match anything.
+func matchesTitleKeywords(title string, include, exclude []string) bool {
+	title = strings.ToLower(title)
+	for _, keyword := range include {
+		if !strings.Contains(title, strings.ToLower(keyword)) {
+			return false
+		}
+	}
+	for _, keyword := range exclude {
+		if strings.Contains(title, strings.ToLower(keyword)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTitleKeywords splits a comma-separated keyword list (as in
+// SEARCH_INCLUDE_TITLE_KEYWORDS), dropping blank entries
+func ParseTitleKeywords(spec string) []string {
+	var keywords []string
+	for _, keyword := range strings.Split(spec, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
 // HasNextPage checks if there's a next page button available
 /*
 func HasNextPage(page *rod.Page) (bool, error) {
 	logger.Info("Checking for next page button...")
-	nextButton, err := page.Timeout(5 * time.Second).Element(utils.PaginationNextButtonSelector)
+	nextButton, err := page.Timeout(5 * time.Second).Element(utils.Selectors.PaginationNextButton)
 	if err != nil {
 		// Button not found means no next page
 		logger.Info("Next page button not found - no more pages")
@@ -534,7 +930,7 @@ func HasNextPage(page *rod.Page) (bool, error) {
 		return false, err
 	}
 
-	if classes != nil && strings.Contains(*classes, utils.PaginationDisabledClass) {
+	if classes != nil && strings.Contains(*classes, utils.Selectors.PaginationDisabledClass) {
 		logger.Info("Next page button is disabled - no more pages")
 		return false, nil
 	}
@@ -548,7 +944,7 @@ func HasNextPage(page *rod.Page) (bool, error) {
 /*
 func ClickNextPage(page *rod.Page) error {
 	logger.Info("Clicking next page button...")
-	nextButton, err := page.Timeout(5 * time.Second).Element(utils.PaginationNextButtonSelector)
+	nextButton, err := page.Timeout(5 * time.Second).Element(utils.Selectors.PaginationNextButton)
 	if err != nil {
 		return fmt.Errorf("next page button not found: %w", err)
 	}
@@ -559,7 +955,7 @@ func ClickNextPage(page *rod.Page) error {
 		return err
 	}
 
-	if classes != nil && strings.Contains(*classes, utils.PaginationDisabledClass) {
+	if classes != nil && strings.Contains(*classes, utils.Selectors.PaginationDisabledClass) {
 		return fmt.Errorf("next page button is disabled")
 	}
 
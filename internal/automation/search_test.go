@@ -1,8 +1,21 @@
 package automation
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/browser"
 	"linkedin-automation/pkg/utils"
 )
 
@@ -66,6 +79,14 @@ func TestBuildSearchURL(t *testing.T) {
 			wantError: false,
 			contains:  []string{"keywords=engineer"},
 		},
+		{
+			name: "1st-degree network filter",
+			config: SearchConfig{
+				Network: []string{NetworkFirstDegree},
+			},
+			wantError: false,
+			contains:  []string{"network=%5B%22F%22%5D"},
+		},
 		{
 			name:      "No filters - should error",
 			config:    SearchConfig{},
@@ -105,6 +126,125 @@ func TestBuildSearchURL(t *testing.T) {
 	}
 }
 
+func TestBuildSearchURLStartPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		startPage int
+		wantParam string
+	}{
+		{"Zero start page omits param", 0, ""},
+		{"First page omits param", 1, ""},
+		{"Deeper page adds param", 7, "7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawURL, err := buildSearchURL(SearchConfig{Keywords: "engineer", StartPage: tt.startPage})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			parsed, err := url.Parse(rawURL)
+			if err != nil {
+				t.Fatalf("Invalid URL %s: %v", rawURL, err)
+			}
+
+			if got := parsed.Query().Get("page"); got != tt.wantParam {
+				t.Errorf("Expected page=%q, got %q (URL: %s)", tt.wantParam, got, rawURL)
+			}
+		})
+	}
+}
+
+func TestChooseStartPageBounds(t *testing.T) {
+	tests := []struct {
+		name         string
+		startPageMax int
+		wantMax      int
+	}{
+		{"Default bound", 0, 10},
+		{"Custom bound", 4, 4},
+		{"Single page", 1, 1},
+		{"Capped at pagination limit", 500, utils.MaxPaginationPages},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(42))
+			config := SearchConfig{Keywords: "engineer", RandomStartPage: true, StartPageMax: tt.startPageMax}
+
+			for i := 0; i < 200; i++ {
+				config.StartPage = chooseStartPage(config, r)
+				if config.StartPage < 1 || config.StartPage > tt.wantMax {
+					t.Fatalf("Start page %d outside [1, %d]", config.StartPage, tt.wantMax)
+				}
+
+				rawURL, err := buildSearchURL(config)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				parsed, _ := url.Parse(rawURL)
+				page := parsed.Query().Get("page")
+				if config.StartPage == 1 {
+					if page != "" {
+						t.Errorf("Expected no page param for first page, got %s", page)
+					}
+					continue
+				}
+				if page != strconv.Itoa(config.StartPage) {
+					t.Errorf("Expected page=%d in URL, got %s", config.StartPage, rawURL)
+				}
+			}
+		})
+	}
+}
+
+func TestChooseStartPageVaries(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	seen := make(map[int]bool)
+	for i := 0; i < 100; i++ {
+		seen[chooseStartPage(SearchConfig{StartPageMax: 10}, r)] = true
+	}
+
+	if len(seen) < 2 {
+		t.Errorf("Expected varied start pages, got %v", seen)
+	}
+}
+
+func TestCheckSearchPageAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		wantErr    bool
+		errContain string
+	}{
+		{"Search results page", "https://www.linkedin.com/search/results/people/?keywords=engineer", false, ""},
+		{"Login redirect", "https://www.linkedin.com/login?session_redirect=%2Fsearch%2Fresults", true, "not authenticated"},
+		{"Auth wall", "https://www.linkedin.com/authwall?trk=bf&originalReferer=", true, "not authenticated"},
+		{"Checkpoint", "https://www.linkedin.com/checkpoint/challenge/AgF", true, "checkpoint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSearchPageAccess(tt.url)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected error for %s", tt.url)
+			}
+			if !strings.Contains(err.Error(), tt.errContain) {
+				t.Errorf("Expected error containing %q, got %q", tt.errContain, err.Error())
+			}
+		})
+	}
+}
+
 func TestSearchConfigDefaults(t *testing.T) {
 	config := SearchConfig{
 		Keywords: "test",
@@ -120,6 +260,51 @@ func TestSearchConfigDefaults(t *testing.T) {
 	}
 }
 
+func TestBuildSearchURLCompany(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         SearchConfig
+		wantFacet      string
+		wantCompanyKey string
+	}{
+		{"Known company uses currentCompany facet", SearchConfig{Company: "Google", CurrentCompanyOnly: true}, `["1441"]`, ""},
+		{"Facet lookup ignores case", SearchConfig{Company: " microsoft ", CurrentCompanyOnly: true}, `["1035"]`, ""},
+		{"Unknown company falls back to keyword", SearchConfig{Company: "Acme Widgets", CurrentCompanyOnly: true}, "", "Acme Widgets"},
+		{"Known company without option uses keyword", SearchConfig{Company: "Google"}, "", "Google"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Keywords = "engineer"
+			rawURL, err := buildSearchURL(tt.config)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			parsed, err := url.Parse(rawURL)
+			if err != nil {
+				t.Fatalf("Invalid URL %s: %v", rawURL, err)
+			}
+
+			query := parsed.Query()
+			if got := query.Get("currentCompany"); got != tt.wantFacet {
+				t.Errorf("Expected currentCompany=%q, got %q (URL: %s)", tt.wantFacet, got, rawURL)
+			}
+			if got := query.Get("company"); got != tt.wantCompanyKey {
+				t.Errorf("Expected company=%q, got %q (URL: %s)", tt.wantCompanyKey, got, rawURL)
+			}
+		})
+	}
+}
+
+func TestCompanyMapping(t *testing.T) {
+	for name, id := range utils.LinkedInCompanies {
+		if _, err := strconv.Atoi(id); err != nil {
+			t.Errorf("Company '%s' has non-numeric ID %q", name, id)
+		}
+	}
+}
+
 func TestLocationMapping(t *testing.T) {
 	// Test that key locations are present
 	keyLocations := []string{
@@ -176,3 +361,311 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestSearchHash(t *testing.T) {
+	base := SearchConfig{
+		Keywords: "software engineer",
+		JobTitle: "Recruiter",
+		Location: "San Francisco Bay Area",
+		Network:  []string{NetworkSecondDegree, NetworkThirdDegree},
+	}
+
+	same := []SearchConfig{
+		base,
+		// Case and whitespace differences are the same search
+		{Keywords: "  Software Engineer ", JobTitle: "recruiter", Location: "San Francisco Bay Area", Network: []string{NetworkSecondDegree, NetworkThirdDegree}},
+		// Network order does not matter
+		{Keywords: "software engineer", JobTitle: "Recruiter", Location: "San Francisco Bay Area", Network: []string{NetworkThirdDegree, NetworkSecondDegree}},
+		// Pagination settings do not change the audience
+		{Keywords: "software engineer", JobTitle: "Recruiter", Location: "San Francisco Bay Area", Network: []string{NetworkSecondDegree, NetworkThirdDegree}, RandomStartPage: true, MaxPages: 5},
+	}
+	for i, config := range same {
+		if searchHash(config) != searchHash(base) {
+			t.Errorf("Config %d: expected same hash as base", i)
+		}
+	}
+
+	changed := []SearchConfig{
+		{Keywords: "product manager", JobTitle: "Recruiter", Location: "San Francisco Bay Area", Network: base.Network},
+		{Keywords: "software engineer", JobTitle: "Recruiter", Location: "London", Network: base.Network},
+		{Keywords: "software engineer", JobTitle: "Recruiter", Company: "Acme", Location: "San Francisco Bay Area", Network: base.Network},
+		{Keywords: "software engineer", JobTitle: "Recruiter", Location: "San Francisco Bay Area"},
+		{Keywords: "software engineer", JobTitle: "Recruiter", Location: "San Francisco Bay Area", Network: base.Network, CurrentCompanyOnly: true},
+	}
+	for i, config := range changed {
+		if searchHash(config) == searchHash(base) {
+			t.Errorf("Changed config %d: expected a different hash", i)
+		}
+	}
+
+	// The caller's slice must not be reordered
+	network := []string{NetworkThirdDegree, NetworkSecondDegree}
+	searchHash(SearchConfig{Keywords: "x", Network: network})
+	if network[0] != NetworkThirdDegree {
+		t.Error("searchHash should not modify config.Network")
+	}
+}
+
+func TestIsCommercialUseLimitText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected bool
+	}{
+		{"You've reached the monthly limit for profile searches", true},
+		{"You’ve reached the commercial use limit on search", true},
+		{"COMMERCIAL USE LIMIT", true},
+		{"Showing 1,000+ results", false},
+		{"No results found", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if result := isCommercialUseLimitText(test.text); result != test.expected {
+			t.Errorf("isCommercialUseLimitText(%q): expected %v, got %v", test.text, test.expected, result)
+		}
+	}
+
+	err := fmt.Errorf("opening search: %w", ErrCommercialUseLimit)
+	if !errors.Is(err, ErrCommercialUseLimit) {
+		t.Error("Expected wrapped error to match ErrCommercialUseLimit")
+	}
+	if !strings.Contains(err.Error(), "commercial use limit reached") {
+		t.Errorf("Expected error message to mention the limit, got %q", err.Error())
+	}
+}
+
+func TestParseSearchResultV2(t *testing.T) {
+	result, err := parseSearchResultV2("urn:li:member:1111",
+		"https://www.linkedin.com/in/jane-doe?miniProfileUrn=x", " Jane Doe ", "Staff Engineer at Acme Corp", "San Francisco Bay Area", "• 2nd")
+	if err != nil {
+		t.Fatalf("parseSearchResultV2() error = %v", err)
+	}
+
+	expected := SearchResult{
+		ProfileID:  "jane-doe",
+		Name:       "Jane Doe",
+		Title:      "Staff Engineer",
+		Company:    "Acme Corp",
+		Location:   "San Francisco Bay Area",
+		ProfileURL: "https://www.linkedin.com/in/jane-doe",
+		Degree:     "2nd",
+	}
+	result.ScrapedAt = expected.ScrapedAt
+	if *result != expected {
+		t.Errorf("parseSearchResultV2() = %+v, expected %+v", *result, expected)
+	}
+}
+
+func TestParseSearchResultV2FallsBackToURN(t *testing.T) {
+	result, err := parseSearchResultV2("urn:li:fsd_profile:ACoAAB123", "", "Jane Doe", "", "", "")
+	if err != nil {
+		t.Fatalf("parseSearchResultV2() error = %v", err)
+	}
+	if result.ProfileID != "ACoAAB123" || result.ProfileURL != utils.LinkedInProfileBase+"ACoAAB123/" {
+		t.Errorf("Expected the ID from the URN, got %q (%s)", result.ProfileID, result.ProfileURL)
+	}
+}
+
+func TestParseSearchResultV2Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		urn, href string
+		fullName  string
+	}{
+		{"out of network", "urn:li:member:3333", "https://www.linkedin.com/search/results/people/headless", "LinkedIn Member"},
+		{"no name", "urn:li:member:1111", "https://www.linkedin.com/in/jane-doe", ""},
+		{"no link or URN", "", "", "Jane Doe"},
+	}
+	for _, test := range tests {
+		if _, err := parseSearchResultV2(test.urn, test.href, test.fullName, "", "", ""); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestSplitHeadline(t *testing.T) {
+	tests := []struct {
+		headline, title, company string
+	}{
+		{"Staff Engineer at Acme Corp", "Staff Engineer", "Acme Corp"},
+		{"Head of Growth at Scale at Beta Labs", "Head of Growth at Scale", "Beta Labs"},
+		{"Product Manager", "Product Manager", ""},
+		{"  ", "", ""},
+	}
+	for _, test := range tests {
+		title, company := splitHeadline(test.headline)
+		if title != test.title || company != test.company {
+			t.Errorf("splitHeadline(%q) = %q, %q; expected %q, %q", test.headline, title, company, test.title, test.company)
+		}
+	}
+}
+
+func TestMatchesTitleKeywords(t *testing.T) {
+	tests := []struct {
+		name             string
+		title            string
+		include, exclude []string
+		want             bool
+	}{
+		{"No filters", "Staff Engineer", nil, nil, true},
+		{"All include keywords", "Senior Backend Engineer", []string{"senior", "ENGINEER"}, nil, true},
+		{"One include keyword missing", "Senior Product Manager", []string{"senior", "engineer"}, nil, false},
+		{"No exclude keyword", "Software Engineer", nil, []string{"intern", "recruiter"}, true},
+		{"Any exclude keyword", "Technical Recruiter", nil, []string{"intern", "recruiter"}, false},
+		{"Exclude wins over include", "Software Engineer Intern", []string{"engineer"}, []string{"intern"}, false},
+		{"Empty title fails include", "", []string{"engineer"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesTitleKeywords(tt.title, tt.include, tt.exclude); got != tt.want {
+				t.Errorf("Expected %v for %q, got %v", tt.want, tt.title, got)
+			}
+		})
+	}
+}
+
+func TestParseTitleKeywords(t *testing.T) {
+	got := ParseTitleKeywords(" senior, engineer ,,")
+	if !reflect.DeepEqual(got, []string{"senior", "engineer"}) {
+		t.Errorf("Unexpected keywords %q", got)
+	}
+	if got := ParseTitleKeywords(""); got != nil {
+		t.Errorf("Expected no keywords, got %q", got)
+	}
+}
+
+// openFixture loads an HTML fixture from testdata into a headless browser.
+// The test is skipped when no Chrome/Chromium is installed.
+func openFixture(t *testing.T, name string) *rod.Page {
+	t.Helper()
+
+	html, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	page, closeBrowser, err := browser.ReplayPage(string(html))
+	if errors.Is(err, browser.ErrNoBrowser) {
+		t.Skip("Chrome/Chromium not found, skipping HTML fixture test")
+	}
+	if err != nil {
+		t.Skipf("Failed to load fixture in a browser: %v", err)
+	}
+	t.Cleanup(closeBrowser)
+	return page
+}
+
+// fixtureProfiles maps profile ID to the parsed result
+func fixtureProfiles(results []SearchResult) map[string]SearchResult {
+	byID := make(map[string]SearchResult)
+	for _, result := range results {
+		byID[result.ProfileID] = result
+	}
+	return byID
+}
+
+func TestParseSearchResultsLayouts(t *testing.T) {
+	for _, fixture := range []string{"search_results_legacy.html", "search_results_v2.html"} {
+		t.Run(fixture, func(t *testing.T) {
+			results, err := ParseSearchResults(openFixture(t, fixture))
+			if err != nil {
+				t.Fatalf("ParseSearchResults() error = %v", err)
+			}
+			if len(results) != 2 {
+				t.Fatalf("Expected 2 results, got %d: %+v", len(results), results)
+			}
+
+			profiles := fixtureProfiles(results)
+			jane, ok := profiles["jane-doe"]
+			if !ok {
+				t.Fatalf("jane-doe missing from %+v", results)
+			}
+			if jane.Name != "Jane Doe" || jane.Title != "Staff Engineer" || jane.Company != "Acme Corp" ||
+				jane.Location != "San Francisco Bay Area" || jane.Degree != "2nd" ||
+				jane.ProfileURL != "https://www.linkedin.com/in/jane-doe" {
+				t.Errorf("Unexpected jane-doe result: %+v", jane)
+			}
+
+			john, ok := profiles["john-roe"]
+			if !ok {
+				t.Fatalf("john-roe missing from %+v", results)
+			}
+			if john.Name != "John Roe" || john.Title != "Product Manager" || john.Location != "London, England, United Kingdom" {
+				t.Errorf("Unexpected john-roe result: %+v", john)
+			}
+		})
+	}
+}
+
+// recordedResult is the expected parse of a result in a recorded page, kept
+// next to the page as <name>.json
+type recordedResult struct {
+	ProfileID   string `json:"profile_id"`
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Company     string `json:"company"`
+	Location    string `json:"location"`
+	Degree      string `json:"degree"`
+	MutualCount int    `json:"mutual_count"`
+}
+
+func TestParseSearchResultsRecordedPages(t *testing.T) {
+	pages, err := filepath.Glob(filepath.Join("testdata", "pages", "search_results_*.html"))
+	if err != nil || len(pages) == 0 {
+		t.Fatalf("No recorded search pages found: %v", err)
+	}
+
+	for _, path := range pages {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			html, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read recorded page: %v", err)
+			}
+			expectedJSON, err := os.ReadFile(strings.TrimSuffix(path, ".html") + ".json")
+			if err != nil {
+				t.Fatalf("Recorded page has no expected results: %v", err)
+			}
+			var expected []recordedResult
+			if err := json.Unmarshal(expectedJSON, &expected); err != nil {
+				t.Fatalf("Invalid expected results: %v", err)
+			}
+
+			results, err := ParseSearchResultsFromHTML(string(html))
+			if errors.Is(err, browser.ErrNoBrowser) {
+				t.Skip("Chrome/Chromium not found, skipping recorded page test")
+			}
+			if err != nil {
+				t.Fatalf("ParseSearchResultsFromHTML() error = %v", err)
+			}
+
+			got := make([]recordedResult, len(results))
+			for i, result := range results {
+				got[i] = recordedResult{result.ProfileID, result.Name, result.Title, result.Company,
+					result.Location, result.Degree, result.MutualCount}
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("Parsed %+v\nexpected %+v", got, expected)
+			}
+		})
+	}
+}
+
+func TestSearchReturnedResults(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats SearchStats
+		want  bool
+	}{
+		{"no results", SearchStats{}, false},
+		{"parse failure", SearchStats{ErrorCount: 1}, false},
+		{"results found", SearchStats{TotalFound: 10, PagesScraped: 1}, true},
+		{"only duplicates", SearchStats{TotalFound: 10, Duplicates: 10, PagesScraped: 1}, true},
+	}
+
+	for _, test := range tests {
+		if got := searchReturnedResults(&test.stats); got != test.want {
+			t.Errorf("%s: searchReturnedResults = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
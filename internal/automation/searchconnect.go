@@ -0,0 +1,327 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/browser"
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/stealth"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
+)
+
+// searchConnectSteps are the page and bookkeeping operations of searchAndConnect
+type searchConnectSteps struct {
+	openPage      func(pageNum int) ([]SearchResult, error)   // Load a results page and parse its cards
+	isDuplicate   func(profileID string) (bool, error)        // Profile seen recently or already invited
+	checkLimit    func() error                                // Daily connection limit
+	checkCompany  func(company string) error                  // Per-company daily cap
+	saveProfile   func(result SearchResult)                   // Save the profile before connecting
+	connect       func(result SearchResult) error             // Send the invitation from the card
+	recordOutcome func(result SearchResult, o connectOutcome) // Save the request and count the action
+	cooldown      func()                                      // Pause between invitations
+}
+
+// SearchAndConnect runs the people search in config and sends invitations
+// straight from the result cards, never opening a profile. Each results page
+// is worked through card by card before the next page is loaded. Profiles and
+// sent requests are saved as it goes, and it stops at the daily connection
+// limit, the weekly invitation limit or a checkpoint. note is sent with every
+// invitation ("" sends none).
+func SearchAndConnect(page *rod.Page, db *storage.Database, rateLimiter *RateLimiter, config SearchConfig, note string) *ConnectionStats {
+	if config.MaxPages == 0 {
+		config.MaxPages = utils.MaxPaginationPages
+	}
+	if config.DuplicateDays == 0 {
+		config.DuplicateDays = 30
+	}
+	if config.RandomStartPage {
+		config.StartPage = chooseStartPage(config, utils.SessionRand())
+		logger.Info(fmt.Sprintf("Randomized start page: %d", config.StartPage))
+	}
+	config.LocationURN = locationURNFor(page, db, config)
+	note = SanitizeNote(note)
+	maxAttempts := GetMaxConnectAttempts()
+	reclaimer := browser.NewResourceReclaimer(page, browser.GetReclaimConfig())
+
+	steps := searchConnectSteps{
+		openPage: func(pageNum int) ([]SearchResult, error) {
+			return openSearchResultsPage(page, db, config, pageNum)
+		},
+		isDuplicate: func(profileID string) (bool, error) {
+			if db == nil {
+				return false, nil
+			}
+			if dupe, err := db.IsDuplicateProfile(profileID, config.DuplicateDays); err != nil || dupe {
+				return dupe, err
+			}
+			return db.HasSentConnectionRequest(profileID)
+		},
+		checkLimit: func() error {
+			return rateLimiter.CheckDailyLimit(TaskConnection)
+		},
+		checkCompany: rateLimiter.CheckCompanyLimit,
+		saveProfile: func(result SearchResult) {
+			if db == nil {
+				return
+			}
+			if err := db.SaveProfile(searchResultProfile(result)); err != nil {
+				logger.Warning(fmt.Sprintf("Failed to save profile %s: %s", result.ProfileID, err.Error()))
+			}
+		},
+		connect: func(result SearchResult) error {
+			reclaimer.BeforeAction()
+			audit(db, AuditActionSendConnection, result.ProfileID, storage.AuditStarted, result.ProfileURL)
+			err := connectFromSearchCard(page, result, note)
+			auditResult(db, AuditActionSendConnection, result.ProfileID, err, "from the search results card")
+			return err
+		},
+		recordOutcome: func(result SearchResult, outcome connectOutcome) {
+			request := ConnectionRequest{ProfileID: result.ProfileID, Name: result.Name}
+			recordConnectAttempt(db, request, outcome, maxAttempts)
+
+			switch outcome {
+			case outcomeSent:
+				if db != nil {
+					connectionReq := storage.ConnectionRequest{
+						ProfileID: result.ProfileID,
+						SentAt:    time.Now(),
+						NoteUsed:  note,
+						Status:    "pending",
+					}
+					if err := db.SaveConnectionRequest(connectionReq); err != nil {
+						logger.Warning("Failed to save connection request to database: " + err.Error())
+					}
+				}
+				if err := rateLimiter.RecordAction(TaskConnection); err != nil {
+					logger.Warning("Failed to record connection action: " + err.Error())
+				}
+			case outcomePending:
+				recordPendingInvitation(db, request)
+			}
+		},
+		cooldown: rateLimiter.ApplyCooldown,
+	}
+
+	logger.Info(fmt.Sprintf("Connecting from search results: keywords='%s', up to %d pages", config.Keywords, config.MaxPages))
+	stats := searchAndConnect(config, steps)
+
+	logger.Info(fmt.Sprintf("Search-and-connect completed: %d successful, %d failed, %d already connected, %d pending in %s",
+		stats.Successful, stats.Failed, stats.AlreadyConnected, stats.Pending, stats.EndTime.Sub(stats.StartTime)))
+	return stats
+}
+
+// searchAndConnect runs the search-and-connect loop: every card on a page is
+// filtered, deduplicated and connected before the next page is opened. It
+// stops at an empty or unreadable page, the daily limit, a stop outcome or a
+// stop request.
+func searchAndConnect(config SearchConfig, steps searchConnectSteps) *ConnectionStats {
+	stats := &ConnectionStats{
+		StartTime: time.Now(),
+	}
+	defer func() { stats.EndTime = time.Now() }()
+
+	firstPage := config.StartPage
+	if firstPage < 1 {
+		firstPage = 1
+	}
+
+	for pageNum := firstPage; pageNum < firstPage+config.MaxPages; pageNum++ {
+		results, err := steps.openPage(pageNum)
+		if err != nil {
+			logger.Warning(fmt.Sprintf("Failed to open search page %d: %s", pageNum, err.Error()))
+			stats.Errors = append(stats.Errors, fmt.Sprintf("page %d: %s", pageNum, err.Error()))
+			return stats
+		}
+		if len(results) == 0 {
+			logger.Info("No results found on this page, stopping pagination")
+			return stats
+		}
+		logger.Info(fmt.Sprintf("Found %d profiles on page %d", len(results), pageNum))
+
+		for _, result := range results {
+			// Honor the PAUSE / STOP control files between invitations
+			if err := WaitWhilePaused(context.Background()); err != nil {
+				stats.Errors = append(stats.Errors, err.Error())
+				return stats
+			}
+
+			if !cardWanted(result, config) {
+				continue
+			}
+			if strings.Contains(result.Degree, "1st") {
+				stats.AlreadyConnected++
+				continue
+			}
+
+			if dupe, err := steps.isDuplicate(result.ProfileID); err != nil {
+				// Skip rather than risk a second invitation
+				logger.Warning(fmt.Sprintf("Failed to check duplicate for %s: %s", result.ProfileID, err.Error()))
+				continue
+			} else if dupe {
+				logger.Debug("Skipping duplicate profile: " + result.Name)
+				continue
+			}
+
+			if err := steps.checkLimit(); err != nil {
+				logger.Warning("Connection rate limit reached: " + err.Error())
+				stats.Errors = append(stats.Errors, "Rate limit reached")
+				return stats
+			}
+
+			// Skip profiles whose company already got its share of invites today
+			if err := steps.checkCompany(result.Company); err != nil {
+				logger.Info(fmt.Sprintf("Skipping %s: %s", result.Name, err.Error()))
+				stats.CompanyCapped++
+				continue
+			}
+
+			stats.TotalAttempted++
+			steps.saveProfile(result)
+			err := steps.connect(result)
+			outcome := classifyConnectError(err)
+			steps.recordOutcome(result, outcome)
+
+			switch outcome {
+			case outcomeSent:
+				stats.Successful++
+				logger.Info("Connection request sent to " + result.Name)
+			case outcomeAlreadyConnected:
+				stats.AlreadyConnected++
+			case outcomePending:
+				stats.Pending++
+				logger.Info(fmt.Sprintf("Connection request already pending for %s", result.Name))
+			case outcomeStop:
+				stats.Failed++
+				stats.Errors = append(stats.Errors, fmt.Sprintf("%s: %s", result.Name, err.Error()))
+				logger.Error("Stopping connection requests: " + err.Error())
+				return stats
+			default:
+				stats.Failed++
+				stats.Errors = append(stats.Errors, fmt.Sprintf("%s: %s", result.Name, err.Error()))
+				logger.Warning(fmt.Sprintf("Failed to connect with %s from the search card: %s", result.Name, err.Error()))
+			}
+
+			steps.cooldown()
+		}
+	}
+
+	return stats
+}
+
+// openSearchResultsPage navigates to results page pageNum of the search and parses its cards
+func openSearchResultsPage(page *rod.Page, db *storage.Database, config SearchConfig, pageNum int) ([]SearchResult, error) {
+	config.StartPage = pageNum
+	searchURL, err := buildSearchURL(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search URL: %w", err)
+	}
+
+	logger.Info("Navigating to search URL: " + searchURL)
+	if err := navigate(page, db, searchURL, ""); err != nil {
+		return nil, fmt.Errorf("failed to navigate to search page: %w", err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to load search page: %w", err)
+	}
+	time.Sleep(2 * time.Second) // Additional wait for dynamic content
+
+	if err := checkSearchPageAccess(page.MustInfo().URL); err != nil {
+		return nil, err
+	}
+	if commercialUseLimitReached(page) {
+		return nil, fmt.Errorf("opening search: %w", ErrCommercialUseLimit)
+	}
+
+	// Bring every card onto the page so its Connect button can be found
+	if err := stealth.ScrollThroughContent(page, 3); err != nil {
+		logger.Debug("Failed to scroll search results: " + err.Error())
+	}
+
+	return ParseSearchResults(page)
+}
+
+// findSearchCard returns the result card on the page that links to profileID
+func findSearchCard(page *rod.Page, profileID string) (*rod.Element, error) {
+	link := fmt.Sprintf("a[href*='/in/%s']", profileID)
+	for _, selector := range []string{utils.Selectors.SearchResultContainerV2, utils.Selectors.SearchResultItem} {
+		cards, err := page.Elements(selector)
+		if err != nil {
+			continue
+		}
+		for _, card := range cards {
+			if has, _, _ := card.Has(link); has {
+				return card, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("search card for %s not found", profileID)
+}
+
+// connectFromSearchCard clicks Connect on the result's search card and sends
+// the invitation, with note when one is given
+func connectFromSearchCard(page *rod.Page, result SearchResult, note string) error {
+	card, err := findSearchCard(page, result.ProfileID)
+	if err != nil {
+		return err
+	}
+
+	// The card shows Pending instead of Connect once an invitation is out
+	if pending, _, _ := card.Has(utils.Selectors.SearchResultPendingButton); pending {
+		return fmt.Errorf("%s: %w", result.Name, ErrConnectionPending)
+	}
+
+	button, err := card.Element(utils.Selectors.SearchResultConnectButton)
+	if err != nil || button == nil {
+		// Cards of creators and out-of-network profiles offer Follow or Message instead
+		return fmt.Errorf("connect button not found on the search card of %s", result.Name)
+	}
+
+	if err := button.ScrollIntoView(); err != nil {
+		return fmt.Errorf("failed to scroll connect button into view: %w", err)
+	}
+	stealth.RandomDelay(800, 1500)
+
+	if err := stealth.SafeClick(page, button); err != nil {
+		return fmt.Errorf("failed to click connect button: %w", err)
+	}
+	stealth.RandomDelay(1500, 2500)
+
+	flow := detectInviteFlow(page, func() bool {
+		pending, _, _ := card.Has(utils.Selectors.SearchResultPendingButton)
+		return pending
+	})
+	if flow == inviteFlowPage {
+		logger.Info("Connect opened the full-page invite form")
+		if err := page.WaitLoad(); err != nil {
+			return fmt.Errorf("failed to load invite page: %w", err)
+		}
+	}
+
+	if err := completeInvite(page, note, flow); err != nil {
+		return err
+	}
+
+	// LinkedIn shows an alert instead of sending once the weekly invitation cap is hit
+	if weeklyLimitReached(page) {
+		logger.Error("LinkedIn weekly invitation limit reached")
+		return fmt.Errorf("sending to %s: %w", result.Name, ErrWeeklyLimit)
+	}
+
+	// The full-page form leaves the results; go back for the remaining cards
+	if flow == inviteFlowPage {
+		if err := page.NavigateBack(); err != nil {
+			return fmt.Errorf("failed to return to the search results: %w", err)
+		}
+		if err := page.WaitLoad(); err != nil {
+			return fmt.Errorf("failed to reload the search results: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fakeSearchConnect records the order searchAndConnect calls its steps in
+type fakeSearchConnect struct {
+	pages      map[int][]SearchResult
+	dupes      map[string]bool
+	limit      int              // Invitations allowed before checkLimit fails (0 = unlimited)
+	connectErr map[string]error // Error returned when connecting to a profile
+	events     []string
+	sent       int
+}
+
+func (f *fakeSearchConnect) steps() searchConnectSteps {
+	return searchConnectSteps{
+		openPage: func(pageNum int) ([]SearchResult, error) {
+			f.events = append(f.events, fmt.Sprintf("open %d", pageNum))
+			return f.pages[pageNum], nil
+		},
+		isDuplicate: func(profileID string) (bool, error) {
+			return f.dupes[profileID], nil
+		},
+		checkLimit: func() error {
+			if f.limit > 0 && f.sent >= f.limit {
+				return errors.New("daily limit reached")
+			}
+			return nil
+		},
+		checkCompany: func(company string) error { return nil },
+		saveProfile: func(result SearchResult) {
+			f.events = append(f.events, "save "+result.ProfileID)
+		},
+		connect: func(result SearchResult) error {
+			f.events = append(f.events, "connect "+result.ProfileID)
+			return f.connectErr[result.ProfileID]
+		},
+		recordOutcome: func(result SearchResult, outcome connectOutcome) {
+			if outcome == outcomeSent {
+				f.sent++
+			}
+		},
+		cooldown: func() {},
+	}
+}
+
+func searchCards(ids ...string) []SearchResult {
+	results := make([]SearchResult, len(ids))
+	for i, id := range ids {
+		results[i] = SearchResult{ProfileID: id, Name: id, Degree: "2nd"}
+	}
+	return results
+}
+
+func TestSearchAndConnectWorksThroughEachPageBeforeTheNext(t *testing.T) {
+	fake := &fakeSearchConnect{
+		pages: map[int][]SearchResult{
+			1: searchCards("ann", "bob"),
+			2: append(searchCards("cat"), SearchResult{ProfileID: "dan", Name: "dan", Degree: "1st"}),
+			3: searchCards("eve"),
+		},
+		dupes: map[string]bool{"bob": true},
+	}
+
+	stats := searchAndConnect(SearchConfig{MaxPages: 5}, fake.steps())
+
+	want := []string{
+		"open 1", "save ann", "connect ann",
+		"open 2", "save cat", "connect cat",
+		"open 3", "save eve", "connect eve",
+		"open 4",
+	}
+	if !reflect.DeepEqual(fake.events, want) {
+		t.Errorf("Expected events\n%v\ngot\n%v", want, fake.events)
+	}
+	if stats.Successful != 3 || stats.TotalAttempted != 3 || stats.AlreadyConnected != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestSearchAndConnectStartsAtStartPageAndStopsAtMaxPages(t *testing.T) {
+	fake := &fakeSearchConnect{pages: map[int][]SearchResult{
+		3: searchCards("ann"),
+		4: searchCards("bob"),
+		5: searchCards("cat"),
+	}}
+
+	searchAndConnect(SearchConfig{StartPage: 3, MaxPages: 2}, fake.steps())
+
+	want := []string{"open 3", "save ann", "connect ann", "open 4", "save bob", "connect bob"}
+	if !reflect.DeepEqual(fake.events, want) {
+		t.Errorf("Expected events %v, got %v", want, fake.events)
+	}
+}
+
+func TestSearchAndConnectHaltsAtRateLimit(t *testing.T) {
+	fake := &fakeSearchConnect{
+		pages: map[int][]SearchResult{
+			1: searchCards("ann", "bob"),
+			2: searchCards("cat", "dan"),
+		},
+		limit: 3,
+	}
+
+	stats := searchAndConnect(SearchConfig{MaxPages: 5}, fake.steps())
+
+	want := []string{
+		"open 1", "save ann", "connect ann", "save bob", "connect bob",
+		"open 2", "save cat", "connect cat",
+	}
+	if !reflect.DeepEqual(fake.events, want) {
+		t.Errorf("Expected events\n%v\ngot\n%v", want, fake.events)
+	}
+	if stats.Successful != 3 || len(stats.Errors) != 1 || stats.Errors[0] != "Rate limit reached" {
+		t.Errorf("Expected 3 sent then a rate limit error, got %+v", stats)
+	}
+}
+
+func TestSearchAndConnectStopsOnWeeklyLimit(t *testing.T) {
+	fake := &fakeSearchConnect{
+		pages: map[int][]SearchResult{1: searchCards("ann", "bob", "cat")},
+		connectErr: map[string]error{
+			"ann": fmt.Errorf("ann: %w", ErrConnectionPending),
+			"bob": fmt.Errorf("sending to bob: %w", ErrWeeklyLimit),
+		},
+	}
+
+	stats := searchAndConnect(SearchConfig{MaxPages: 5}, fake.steps())
+
+	want := []string{"open 1", "save ann", "connect ann", "save bob", "connect bob"}
+	if !reflect.DeepEqual(fake.events, want) {
+		t.Errorf("Expected events %v, got %v", want, fake.events)
+	}
+	if stats.Pending != 1 || stats.Failed != 1 || stats.Successful != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,54 @@
+package automation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/notify"
+	"linkedin-automation/pkg/utils"
+)
+
+// SelectorsMayHaveChanged reports whether a search loaded result pages but
+// found nobody on them, which usually means LinkedIn changed its markup
+func SelectorsMayHaveChanged(stats *SearchStats) bool {
+	return stats != nil && stats.TotalFound == 0 && stats.PagesScraped > 0
+}
+
+// AlertSelectorsMayHaveChanged sends a selectors_may_have_changed event carrying
+// the search and the selector values in use when SelectorsMayHaveChanged holds.
+// Returns whether an event was sent; delivery failures are only logged.
+func AlertSelectorsMayHaveChanged(n notify.Notifier, config SearchConfig, stats *SearchStats) bool {
+	if n == nil || !SelectorsMayHaveChanged(stats) {
+		return false
+	}
+
+	event := notify.Event{
+		Type:    notify.EventSelectorsMayHaveChanged,
+		Message: fmt.Sprintf("Search scraped %d page(s) but found 0 profiles - LinkedIn may have changed its HTML selectors", stats.PagesScraped),
+		Data: map[string]interface{}{
+			"keywords":      config.Keywords,
+			"pages_scraped": stats.PagesScraped,
+			"selectors":     selectorValues(utils.Selectors),
+		},
+	}
+
+	if err := n.Notify(event); err != nil {
+		logger.Warning("Failed to send selector change alert: " + err.Error())
+		return false
+	}
+	return true
+}
+
+// selectorValues returns the selectors keyed by their SELECTORS_FILE names
+func selectorValues(set utils.SelectorSet) map[string]interface{} {
+	values := map[string]interface{}{}
+	data, err := json.Marshal(set)
+	if err == nil {
+		err = json.Unmarshal(data, &values)
+	}
+	if err != nil {
+		logger.Warning("Failed to encode selectors: " + err.Error())
+	}
+	return values
+}
@@ -0,0 +1,78 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+
+	"linkedin-automation/internal/notify"
+	"linkedin-automation/pkg/utils"
+)
+
+// recordingNotifier keeps the events it is asked to send
+type recordingNotifier struct {
+	events []notify.Event
+	err    error
+}
+
+func (n *recordingNotifier) Notify(event notify.Event) error {
+	n.events = append(n.events, event)
+	return n.err
+}
+
+// TestAlertSelectorsMayHaveChanged tests that the alert fires only for zero results across loaded pages
+func TestAlertSelectorsMayHaveChanged(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats *SearchStats
+		want  bool
+	}{
+		{"zero results with pages", &SearchStats{TotalFound: 0, PagesScraped: 2}, true},
+		{"results found", &SearchStats{TotalFound: 5, PagesScraped: 2}, false},
+		{"no pages loaded", &SearchStats{TotalFound: 0, PagesScraped: 0}, false},
+		{"no stats", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &recordingNotifier{}
+			sent := AlertSelectorsMayHaveChanged(n, SearchConfig{Keywords: "golang"}, tt.stats)
+
+			if sent != tt.want {
+				t.Errorf("AlertSelectorsMayHaveChanged() = %v, want %v", sent, tt.want)
+			}
+			if got := len(n.events) == 1; got != tt.want {
+				t.Fatalf("sent %d events, want event = %v", len(n.events), tt.want)
+			}
+			if !tt.want {
+				return
+			}
+
+			event := n.events[0]
+			if event.Type != notify.EventSelectorsMayHaveChanged {
+				t.Errorf("event type = %q, want %q", event.Type, notify.EventSelectorsMayHaveChanged)
+			}
+			selectors, ok := event.Data["selectors"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("event data has no selectors: %v", event.Data)
+			}
+			if selectors["search_result_item"] != utils.Selectors.SearchResultItem {
+				t.Errorf("selectors.search_result_item = %v, want %q", selectors["search_result_item"], utils.Selectors.SearchResultItem)
+			}
+		})
+	}
+}
+
+// TestAlertSelectorsMayHaveChangedWithoutNotifier tests that nothing is sent when no webhook is configured
+func TestAlertSelectorsMayHaveChangedWithoutNotifier(t *testing.T) {
+	if AlertSelectorsMayHaveChanged(nil, SearchConfig{}, &SearchStats{PagesScraped: 1}) {
+		t.Error("Expected no alert without a notifier")
+	}
+}
+
+// TestAlertSelectorsMayHaveChangedDeliveryFailure tests that a failed delivery is reported as not sent
+func TestAlertSelectorsMayHaveChangedDeliveryFailure(t *testing.T) {
+	n := &recordingNotifier{err: errors.New("connection refused")}
+	if AlertSelectorsMayHaveChanged(n, SearchConfig{}, &SearchStats{PagesScraped: 1}) {
+		t.Error("Expected a failed delivery to report false")
+	}
+}
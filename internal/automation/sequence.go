@@ -0,0 +1,239 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
+)
+
+// SequenceDelayConfig bounds the randomized wait between an acceptance and the linked follow-up
+type SequenceDelayConfig struct {
+	MinDelay time.Duration
+	MaxDelay time.Duration
+}
+
+// GetSequenceDelayConfig reads SEQUENCE_DELAY_MIN_HOURS / SEQUENCE_DELAY_MAX_HOURS (default 4-24h)
+func GetSequenceDelayConfig() SequenceDelayConfig {
+	cfg := SequenceDelayConfig{
+		MinDelay: 4 * time.Hour,
+		MaxDelay: 24 * time.Hour,
+	}
+
+	if v := os.Getenv("SEQUENCE_DELAY_MIN_HOURS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			cfg.MinDelay = time.Duration(val) * time.Hour
+		}
+	}
+
+	if v := os.Getenv("SEQUENCE_DELAY_MAX_HOURS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			cfg.MaxDelay = time.Duration(val) * time.Hour
+		}
+	}
+
+	if cfg.MaxDelay < cfg.MinDelay {
+		cfg.MaxDelay = cfg.MinDelay
+	}
+
+	return cfg
+}
+
+// randomDelay picks a delay in [MinDelay, MaxDelay] with minute granularity
+func (c SequenceDelayConfig) randomDelay(r *rand.Rand) time.Duration {
+	spread := int((c.MaxDelay - c.MinDelay) / time.Minute)
+	if spread <= 0 {
+		return c.MinDelay
+	}
+	return c.MinDelay + time.Duration(r.Intn(spread+1))*time.Minute
+}
+
+// EnqueueSequence links a profile's connection request to a follow-up message:
+// once the connection is accepted, msgTemplate is queued to be sent after a
+// randomized delay. connTemplate records which note started the sequence.
+func EnqueueSequence(db *storage.Database, profileID, connTemplate, msgTemplate string) error {
+	template, err := GetTemplateByID(msgTemplate)
+	if err != nil {
+		return fmt.Errorf("sequence message template not found: %w", err)
+	}
+	if template.Type == TemplateConnectionRequest {
+		return fmt.Errorf("template %s is a connection request template, not a message template", msgTemplate)
+	}
+
+	return db.CreateSequence(profileID, connTemplate, msgTemplate)
+}
+
+// sequenceMessageTemplate returns the follow-up template linked to new connection requests
+// (SEQUENCE_MESSAGE_TEMPLATE), or "" when sequences are disabled
+func sequenceMessageTemplate() string {
+	return os.Getenv("SEQUENCE_MESSAGE_TEMPLATE")
+}
+
+// markConnectionAccepted records an acceptance and enqueues the profile's linked
+// follow-up message, if it has one. Enqueue failures are logged, not returned -
+// the acceptance itself has been recorded.
+func markConnectionAccepted(db *storage.Database, profileID string) error {
+	if err := db.UpdateConnectionStatus(profileID, "accepted"); err != nil {
+		return err
+	}
+
+	enqueueSequenceFollowUp(db, profileID, time.Now(), GetSequenceDelayConfig(), utils.SessionRand())
+	return nil
+}
+
+// enqueueSequenceFollowUp queues the profile's linked follow-up at now plus a random delay.
+// Returns whether a message was queued; a profile without a waiting sequence queues nothing.
+func enqueueSequenceFollowUp(db *storage.Database, profileID string, now time.Time, cfg SequenceDelayConfig, r *rand.Rand) bool {
+	sendAfter := now.Add(cfg.randomDelay(r))
+
+	enqueued, err := db.EnqueueSequenceMessage(profileID, sendAfter)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Failed to enqueue follow-up for %s: %s", profileID, err.Error()))
+		return false
+	}
+	if enqueued {
+		logger.Info(fmt.Sprintf("Queued sequence follow-up for %s at %s", profileID, sendAfter.Format("2006-01-02 15:04")))
+	}
+
+	return enqueued
+}
+
+// maxScheduledAttempts is how many failed sends a scheduled message gets, over
+// one or more runs, before it is marked failed
+const maxScheduledAttempts = 3
+
+// ProcessScheduledMessages sends queued messages that are due, up to
+// MAX_MESSAGES_PER_RUN and within the daily message limit. Like the daily
+// follow-ups, a message waits until its connection has been accepted for
+// FOLLOWUP_MIN_DAYS_SINCE_ACCEPTED days. Returns how many were sent.
+func ProcessScheduledMessages(page *rod.Page, db *storage.Database, rateLimiter *RateLimiter) (int, error) {
+	maxMessages := 3
+	if os.Getenv("MAX_MESSAGES_PER_RUN") != "" {
+		fmt.Sscanf(os.Getenv("MAX_MESSAGES_PER_RUN"), "%d", &maxMessages)
+	}
+
+	senderVars := TemplateVariables{
+		YourName:     os.Getenv("YOUR_NAME"),
+		YourTitle:    os.Getenv("YOUR_TITLE"),
+		YourCompany:  os.Getenv("YOUR_COMPANY"),
+		Industry:     os.Getenv("YOUR_INDUSTRY"),
+		CustomReason: os.Getenv("MESSAGE_CUSTOM_REASON"),
+	}
+
+	return runScheduledMessages(db, time.Now(), maxMessages, GetMinDaysSinceAccepted(), senderVars,
+		func() error { return rateLimiter.CheckDailyLimit(TaskMessage) },
+		func(req MessageRequest) error { return SendMessage(page, db, req) },
+		func() { rateLimiter.RecordAction(TaskMessage) },
+	)
+}
+
+// scheduledHeld is what sendScheduledMessage returns for a profile
+// MESSAGE_WHITELIST refuses. It is never stored: the message stays queued and
+// goes out once the whitelist allows it.
+const scheduledHeld = "held"
+
+// runScheduledMessages is ProcessScheduledMessages with injectable time, rate limiting and sending.
+// Messages held by MESSAGE_WHITELIST don't count towards maxMessages; a failed
+// send stays queued for the next run, up to maxScheduledAttempts.
+func runScheduledMessages(db *storage.Database, now time.Time, maxMessages, minDaysSinceAccepted int, senderVars TemplateVariables,
+	checkLimit func() error, send func(MessageRequest) error, recordAction func()) (int, error) {
+	due, err := db.GetDueScheduledMessages(now, 0, minDaysSinceAccepted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get scheduled messages: %w", err)
+	}
+
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	logger.Info(fmt.Sprintf("Sending %d scheduled messages", len(due)))
+
+	sent, attempted := 0, 0
+	for _, msg := range due {
+		if attempted >= maxMessages {
+			break
+		}
+
+		// Honor the PAUSE / STOP control files between messages
+		if err := WaitWhilePaused(context.Background()); err != nil {
+			logger.Warning("Leaving remaining scheduled messages queued: " + err.Error())
+			break
+		}
+
+		if err := checkLimit(); err != nil {
+			logger.Warning("Messaging rate limit reached - leaving remaining scheduled messages queued: " + err.Error())
+			break
+		}
+
+		status := sendScheduledMessage(db, msg, senderVars, send)
+		if status == scheduledHeld {
+			continue
+		}
+		attempted++
+		if status == storage.ScheduledSent {
+			sent++
+			recordAction()
+		}
+
+		if status == storage.ScheduledPending {
+			failed, err := db.RecordScheduledFailure(msg.ID, maxScheduledAttempts)
+			if err != nil {
+				return sent, fmt.Errorf("failed to record scheduled message: %w", err)
+			}
+			if failed {
+				logger.Warning(fmt.Sprintf("Scheduled message to %s failed %d times, not retrying", msg.ProfileID, maxScheduledAttempts))
+			}
+		} else if err := db.MarkScheduledMessage(msg.ID, status); err != nil {
+			return sent, fmt.Errorf("failed to record scheduled message: %w", err)
+		}
+	}
+
+	return sent, nil
+}
+
+// sendScheduledMessage sends one queued message and returns its resulting
+// status. ScheduledPending means a send that may work on a later try failed;
+// scheduledHeld means MESSAGE_WHITELIST refused the profile.
+func sendScheduledMessage(db *storage.Database, msg storage.ScheduledMessage, senderVars TemplateVariables,
+	send func(MessageRequest) error) string {
+	profile, err := db.GetProfile(msg.ProfileID)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Scheduled message target %s not found: %s", msg.ProfileID, err.Error()))
+		return storage.ScheduledFailed
+	}
+
+	alreadySent, err := db.HasSentMessage(profile.ID, msg.TemplateID)
+	if err != nil {
+		logger.Warning("Failed to check message history: " + err.Error())
+		return storage.ScheduledPending
+	}
+	if alreadySent {
+		logger.Info(fmt.Sprintf("Already messaged %s with %s - skipping", profile.Name, msg.TemplateID))
+		return storage.ScheduledSkipped
+	}
+
+	req, err := PrepareMessageFromProfile(*profile, msg.TemplateID, senderVars)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to prepare message for %s: %s", profile.Name, err.Error()))
+		return storage.ScheduledFailed
+	}
+
+	if err := send(*req); errors.Is(err, ErrNotWhitelisted) {
+		logger.Info(fmt.Sprintf("Leaving %s queued: %s", profile.Name, err.Error()))
+		return scheduledHeld
+	} else if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send message to %s: %s", profile.Name, err.Error()))
+		return storage.ScheduledPending
+	}
+
+	return storage.ScheduledSent
+}
@@ -0,0 +1,225 @@
+package automation
+
+import (
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+// seedPendingConnection saves a profile with a pending connection request
+func seedPendingConnection(t *testing.T, db *storage.Database, profileID string) {
+	t.Helper()
+	profile := storage.Profile{
+		ID:         profileID,
+		Name:       profileID + " Tester",
+		Title:      "Engineer",
+		Company:    "Acme",
+		ProfileURL: "https://www.linkedin.com/in/" + profileID + "/",
+		VisitedAt:  time.Now(),
+	}
+	if err := db.SaveProfile(profile); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+	if err := db.SaveConnectionRequest(storage.ConnectionRequest{
+		ProfileID:  profileID,
+		SentAt:     time.Now(),
+		TemplateID: "conn_generic",
+		Status:     "pending",
+	}); err != nil {
+		t.Fatalf("Failed to save connection request: %v", err)
+	}
+}
+
+func TestAcceptanceEnqueuesSequenceMessageOnce(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_sequence.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	seedPendingConnection(t, db, "alice")
+	seedPendingConnection(t, db, "bob")
+
+	if err := EnqueueSequence(db, "alice", "conn_generic", "msg_introduction"); err != nil {
+		t.Fatalf("Failed to enqueue sequence: %v", err)
+	}
+
+	// Acceptance is seen by several checks (recent connections, status check, reconcile)
+	for i := 0; i < 3; i++ {
+		if err := markConnectionAccepted(db, "alice"); err != nil {
+			t.Fatalf("Failed to mark accepted: %v", err)
+		}
+	}
+	if err := markConnectionAccepted(db, "bob"); err != nil {
+		t.Fatalf("Failed to mark accepted: %v", err)
+	}
+
+	due, err := db.GetDueScheduledMessages(time.Now().Add(48*time.Hour), 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to get scheduled messages: %v", err)
+	}
+	if len(due) != 1 {
+		t.Fatalf("Expected exactly one scheduled message, got %d: %+v", len(due), due)
+	}
+	if due[0].ProfileID != "alice" || due[0].TemplateID != "msg_introduction" {
+		t.Errorf("Unexpected scheduled message: %+v", due[0])
+	}
+
+	// Not due before the minimum delay
+	cfg := GetSequenceDelayConfig()
+	if early, _ := db.GetDueScheduledMessages(time.Now().Add(cfg.MinDelay-time.Minute), 10, 0); len(early) != 0 {
+		t.Errorf("Expected follow-up to wait at least %s, got %+v", cfg.MinDelay, early)
+	}
+
+	// Sending it marks it done so later runs don't resend
+	var sent []string
+	count, err := runScheduledMessages(db, time.Now().Add(48*time.Hour), 10, 0, TemplateVariables{YourName: "Sam"},
+		func() error { return nil }, fakeSender(db, &sent, ""), func() {})
+	if err != nil || count != 1 || len(sent) != 1 || sent[0] != "alice" {
+		t.Fatalf("Expected alice's follow-up to be sent once, got count=%d sent=%v err=%v", count, sent, err)
+	}
+
+	count, err = runScheduledMessages(db, time.Now().Add(48*time.Hour), 10, 0, TemplateVariables{},
+		func() error { return nil }, fakeSender(db, &sent, ""), func() {})
+	if err != nil || count != 0 {
+		t.Errorf("Expected nothing left to send, got count=%d err=%v", count, err)
+	}
+}
+
+func TestScheduledMessagesOffWhitelistStayQueued(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_sequence.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	seedAcceptedSequence(t, db, "alice")
+	seedAcceptedSequence(t, db, "bob")
+
+	later := time.Now().Add(48 * time.Hour)
+	whitelisted := func(whitelist string, sent *[]string) func(MessageRequest) error {
+		return func(req MessageRequest) error {
+			return sendIfWhitelisted(req, parseMessageWhitelist(whitelist), fakeSender(db, sent, ""))
+		}
+	}
+
+	// alice is refused; with room for one message, bob still goes out
+	var sent []string
+	count, err := runScheduledMessages(db, later, 1, 0, TemplateVariables{}, func() error { return nil }, whitelisted("bob", &sent), func() {})
+	if err != nil || count != 1 || len(sent) != 1 || sent[0] != "bob" {
+		t.Fatalf("Expected bob's follow-up sent past the held one, got count=%d sent=%v err=%v", count, sent, err)
+	}
+
+	due, err := db.GetDueScheduledMessages(later, 10, 0)
+	if err != nil || len(due) != 1 || due[0].ProfileID != "alice" {
+		t.Fatalf("Expected alice's follow-up still queued, got %+v (err %v)", due, err)
+	}
+
+	sent = nil
+	count, err = runScheduledMessages(db, later, 1, 0, TemplateVariables{}, func() error { return nil }, whitelisted("alice,bob", &sent), func() {})
+	if err != nil || count != 1 || len(sent) != 1 || sent[0] != "alice" {
+		t.Errorf("Expected alice's follow-up sent once whitelisted, got count=%d sent=%v err=%v", count, sent, err)
+	}
+}
+
+// seedAcceptedSequence queues a follow-up for an accepted connection to profileID
+func seedAcceptedSequence(t *testing.T, db *storage.Database, profileID string) {
+	t.Helper()
+	seedPendingConnection(t, db, profileID)
+	if err := EnqueueSequence(db, profileID, "conn_generic", "msg_introduction"); err != nil {
+		t.Fatalf("Failed to enqueue sequence: %v", err)
+	}
+	if err := markConnectionAccepted(db, profileID); err != nil {
+		t.Fatalf("Failed to mark accepted: %v", err)
+	}
+}
+
+func TestScheduledMessagesRetryFailedSends(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_sequence.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	seedAcceptedSequence(t, db, "alice")
+	later := time.Now().Add(48 * time.Hour)
+
+	for run := 1; run <= maxScheduledAttempts; run++ {
+		due, err := db.GetDueScheduledMessages(later, 10, 0)
+		if err != nil || len(due) != 1 || due[0].Attempts != run-1 {
+			t.Fatalf("Run %d: expected alice's follow-up queued after %d failures, got %+v (err %v)", run, run-1, due, err)
+		}
+
+		var sent []string
+		if _, err := runScheduledMessages(db, later, 10, 0, TemplateVariables{}, func() error { return nil }, fakeSender(db, &sent, "alice"), func() {}); err != nil {
+			t.Fatalf("Run %d failed: %v", run, err)
+		}
+	}
+
+	if due, _ := db.GetDueScheduledMessages(later, 10, 0); len(due) != 0 {
+		t.Errorf("Expected the follow-up marked failed after %d attempts, got %+v", maxScheduledAttempts, due)
+	}
+}
+
+func TestScheduledMessagesWaitForMinDaysSinceAccepted(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_sequence.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	seedAcceptedSequence(t, db, "alice")
+	later := time.Now().Add(48 * time.Hour)
+
+	// Due by its delay, but the acceptance is only two days old by then
+	var sent []string
+	count, err := runScheduledMessages(db, later, 10, 3, TemplateVariables{}, func() error { return nil }, fakeSender(db, &sent, ""), func() {})
+	if err != nil || count != 0 || len(sent) != 0 {
+		t.Fatalf("Expected the follow-up held for FOLLOWUP_MIN_DAYS_SINCE_ACCEPTED, got count=%d sent=%v err=%v", count, sent, err)
+	}
+
+	count, err = runScheduledMessages(db, later, 10, 1, TemplateVariables{}, func() error { return nil }, fakeSender(db, &sent, ""), func() {})
+	if err != nil || count != 1 || len(sent) != 1 || sent[0] != "alice" {
+		t.Errorf("Expected the follow-up sent once the acceptance is old enough, got count=%d sent=%v err=%v", count, sent, err)
+	}
+}
+
+func TestEnqueueSequenceRejectsConnectionTemplate(t *testing.T) {
+	db, err := storage.InitDB(filepath.Join(t.TempDir(), "test_sequence.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	if err := EnqueueSequence(db, "alice", "conn_generic", "conn_brief"); err == nil {
+		t.Error("Expected error for a connection request template as follow-up")
+	}
+	if err := EnqueueSequence(db, "alice", "conn_generic", "does_not_exist"); err == nil {
+		t.Error("Expected error for an unknown template")
+	}
+}
+
+func TestSequenceDelayConfig(t *testing.T) {
+	t.Setenv("SEQUENCE_DELAY_MIN_HOURS", "2")
+	t.Setenv("SEQUENCE_DELAY_MAX_HOURS", "3")
+	cfg := GetSequenceDelayConfig()
+	if cfg.MinDelay != 2*time.Hour || cfg.MaxDelay != 3*time.Hour {
+		t.Fatalf("Unexpected config: %+v", cfg)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if d := cfg.randomDelay(r); d < cfg.MinDelay || d > cfg.MaxDelay {
+			t.Fatalf("Delay %s outside [%s, %s]", d, cfg.MinDelay, cfg.MaxDelay)
+		}
+	}
+
+	// Max below min collapses to min
+	t.Setenv("SEQUENCE_DELAY_MAX_HOURS", "1")
+	if cfg := GetSequenceDelayConfig(); cfg.MaxDelay != cfg.MinDelay {
+		t.Errorf("Expected max to be raised to min, got %+v", cfg)
+	}
+}
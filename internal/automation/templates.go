@@ -2,10 +2,15 @@ package automation
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"linkedin-automation/internal/logger"
 )
@@ -33,6 +38,12 @@ type TemplateVariables struct {
 	YourCompany  string // Sender's company
 	CustomReason string // Custom reason for connection
 	Date         string // Current date
+	Greeting     string // "Good morning/afternoon/evening" for the recipient's local time
+	Timezone     string // Recipient's IANA timezone for Greeting (default: TARGET_TIMEZONE, else local time)
+
+	// Optional clauses, drawn per render by RandomizeClauses ({{if .MentionCompany}}...{{end}})
+	MentionCompany bool
+	MentionTitle   bool
 }
 
 // MessageTemplate represents a message template with metadata
@@ -44,17 +55,71 @@ type MessageTemplate struct {
 	Body        string
 	Description string
 	MaxLength   int // Character limit (300 for connection notes, 8000 for messages)
+	MinLength   int // Minimum characters (0 = use CONNECTION_NOTE_MIN for connection notes, otherwise no minimum)
 }
 
 // Character limits per LinkedIn's specifications
 const (
-	ConnectionNoteMaxLength = 300  // LinkedIn's limit for connection request notes
+	ConnectionNoteMaxLength = 300  // LinkedIn's default limit for connection request notes (US)
 	MessageMaxLength        = 8000 // LinkedIn's limit for direct messages
 	SubjectMaxLength        = 200  // LinkedIn's limit for message subjects
 )
 
+// GetConnectionNoteMaxLength returns the effective connection note limit.
+// Some locales report a different limit, so CONNECTION_NOTE_MAX overrides the default of 300.
+func GetConnectionNoteMaxLength() int {
+	if envMax := os.Getenv("CONNECTION_NOTE_MAX"); envMax != "" {
+		if val, err := strconv.Atoi(envMax); err == nil && val > 0 {
+			return val
+		}
+		logger.Warning(fmt.Sprintf("Invalid CONNECTION_NOTE_MAX %q, using %d", envMax, ConnectionNoteMaxLength))
+	}
+	return ConnectionNoteMaxLength
+}
+
+// ErrMessageTooLong is returned when a rendered note or message exceeds its character limit
+var ErrMessageTooLong = errors.New("message exceeds maximum length")
+
+// ErrNoteTooShort is returned when a rendered connection note is below the minimum length,
+// so the caller can fall back to a richer template
+var ErrNoteTooShort = errors.New("connection note below minimum length")
+
+// GetConnectionNoteMinLength returns the minimum connection note length from
+// CONNECTION_NOTE_MIN. Very short notes look templated; 0 (the default) disables the check.
+func GetConnectionNoteMinLength() int {
+	if envMin := os.Getenv("CONNECTION_NOTE_MIN"); envMin != "" {
+		if val, err := strconv.Atoi(envMin); err == nil && val >= 0 {
+			return val
+		}
+		logger.Warning(fmt.Sprintf("Invalid CONNECTION_NOTE_MIN %q, minimum disabled", envMin))
+	}
+	return 0
+}
+
+// minLengthFor returns the minimum length to enforce for a template.
+// A template's own MinLength wins; connection notes otherwise use CONNECTION_NOTE_MIN.
+func minLengthFor(tmplDef MessageTemplate) int {
+	if tmplDef.MinLength > 0 {
+		return tmplDef.MinLength
+	}
+	if tmplDef.Type == TemplateConnectionRequest {
+		return GetConnectionNoteMinLength()
+	}
+	return 0
+}
+
+// maxLengthFor returns the character limit to enforce for a template.
+// Connection notes always use the effective limit so a locale override applies to every template.
+func maxLengthFor(tmplDef MessageTemplate) int {
+	if tmplDef.Type == TemplateConnectionRequest {
+		return GetConnectionNoteMaxLength()
+	}
+	return tmplDef.MaxLength
+}
+
 // GetConnectionRequestTemplates returns predefined connection request templates
 func GetConnectionRequestTemplates() []MessageTemplate {
+	noteMax := GetConnectionNoteMaxLength()
 	return []MessageTemplate{
 		{
 			ID:          "conn_generic",
@@ -62,7 +127,7 @@ func GetConnectionRequestTemplates() []MessageTemplate {
 			Name:        "Generic Professional",
 			Body:        "Hi {{.FirstName}}, I came across your profile and was impressed by your work at {{.Company}}. I'd love to connect{{if .Industry}} and learn more about your experience in {{.Industry}}{{end}}.",
 			Description: "Generic professional connection request",
-			MaxLength:   ConnectionNoteMaxLength,
+			MaxLength:   noteMax,
 		},
 		{
 			ID:          "conn_role_specific",
@@ -70,7 +135,7 @@ func GetConnectionRequestTemplates() []MessageTemplate {
 			Name:        "Role-Specific",
 			Body:        "Hi {{.FirstName}}, I noticed you're a {{.Title}} at {{.Company}}. I'm {{.YourTitle}} at {{.YourCompany}} and would love to connect to exchange insights about our field.",
 			Description: "Connection based on similar roles",
-			MaxLength:   ConnectionNoteMaxLength,
+			MaxLength:   noteMax,
 		},
 		{
 			ID:          "conn_industry",
@@ -78,7 +143,7 @@ func GetConnectionRequestTemplates() []MessageTemplate {
 			Name:        "Industry Connection",
 			Body:        "Hi {{.FirstName}}, I saw your profile and noticed we both work in {{.Industry}}. I'd appreciate the opportunity to connect and potentially collaborate in the future.",
 			Description: "Connection based on shared industry",
-			MaxLength:   ConnectionNoteMaxLength,
+			MaxLength:   noteMax,
 		},
 		{
 			ID:          "conn_mutual_interest",
@@ -86,7 +151,7 @@ func GetConnectionRequestTemplates() []MessageTemplate {
 			Name:        "Mutual Interest",
 			Body:        "Hi {{.FirstName}}, your experience at {{.Company}} caught my attention. {{.CustomReason}} I'd love to connect and learn from your expertise.",
 			Description: "Connection with custom reason",
-			MaxLength:   ConnectionNoteMaxLength,
+			MaxLength:   noteMax,
 		},
 		{
 			ID:          "conn_networking",
@@ -94,7 +159,23 @@ func GetConnectionRequestTemplates() []MessageTemplate {
 			Name:        "Networking",
 			Body:        "Hi {{.FirstName}}, I'm expanding my professional network with {{.Industry}} professionals. Your background at {{.Company}} is impressive. Let's connect!",
 			Description: "General networking connection",
-			MaxLength:   ConnectionNoteMaxLength,
+			MaxLength:   noteMax,
+		},
+		{
+			ID:          "conn_greeting",
+			Type:        TemplateConnectionRequest,
+			Name:        "Time-of-Day Greeting",
+			Body:        "{{.Greeting}} {{.FirstName}}, I came across your work at {{.Company}} and would love to connect and follow what you're building.",
+			Description: "Opens with a greeting that matches the recipient's time of day",
+			MaxLength:   noteMax,
+		},
+		{
+			ID:          "conn_varied",
+			Type:        TemplateConnectionRequest,
+			Name:        "Varied Mentions",
+			Body:        "Hi {{.FirstName}}, I came across your profile{{if .MentionCompany}} while reading about {{.Company}}{{end}} and would love to connect.{{if .MentionTitle}} I'd enjoy hearing how you approach your role as {{.Title}}.{{end}}",
+			Description: "Mentions the company and title only some of the time (NOTE_MENTION_*_PERCENT)",
+			MaxLength:   noteMax,
 		},
 		{
 			ID:          "conn_brief",
@@ -102,7 +183,7 @@ func GetConnectionRequestTemplates() []MessageTemplate {
 			Name:        "Brief & Direct",
 			Body:        "Hi {{.FirstName}}, impressive work at {{.Company}}! Would love to connect.",
 			Description: "Short and direct connection request",
-			MaxLength:   ConnectionNoteMaxLength,
+			MaxLength:   noteMax,
 		},
 	}
 }
@@ -158,6 +239,35 @@ func GetMessageTemplates() []MessageTemplate {
 	}
 }
 
+// GreetingFor returns the greeting that fits the time of day at t:
+// morning from 5:00, afternoon from 12:00, evening from 17:00 through the night
+func GreetingFor(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour >= 5 && hour < 12:
+		return "Good morning"
+	case hour >= 12 && hour < 17:
+		return "Good afternoon"
+	default:
+		return "Good evening"
+	}
+}
+
+// greetingAt returns the greeting for now in timezone. An empty timezone
+// falls back to TARGET_TIMEZONE, then to now's own location.
+func greetingAt(now time.Time, timezone string) string {
+	if timezone == "" {
+		timezone = os.Getenv("TARGET_TIMEZONE")
+	}
+	if timezone != "" {
+		if loc, err := time.LoadLocation(timezone); err == nil {
+			now = now.In(loc)
+		} else {
+			logger.Warning("Invalid timezone " + timezone + " for greeting, using local time: " + err.Error())
+		}
+	}
+	return GreetingFor(now)
+}
+
 // RenderTemplate renders a template with the given variables
 func RenderTemplate(tmplDef MessageTemplate, vars TemplateVariables) (string, error) {
 	// Set default values if not provided
@@ -174,6 +284,11 @@ func RenderTemplate(tmplDef MessageTemplate, vars TemplateVariables) (string, er
 		vars.Date = time.Now().Format("January 2, 2006")
 	}
 
+	// Greet for the time of day where the recipient is
+	if vars.Greeting == "" {
+		vars.Greeting = greetingAt(time.Now(), vars.Timezone)
+	}
+
 	// Extract first name if not provided
 	if vars.FirstName == "" && vars.FullName != "" {
 		parts := strings.Split(vars.FullName, " ")
@@ -183,8 +298,12 @@ func RenderTemplate(tmplDef MessageTemplate, vars TemplateVariables) (string, er
 		}
 	}
 
+	// Search cards often lack a company or title; mark where an empty one
+	// would be printed so the sentence can be rephrased around it
+	body, marked := markEmptyFields(tmplDef.Body, vars)
+
 	// Parse the template
-	t, err := template.New(tmplDef.ID).Parse(tmplDef.Body)
+	t, err := template.New(tmplDef.ID).Funcs(templateFuncs).Parse(body)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -196,13 +315,22 @@ func RenderTemplate(tmplDef MessageTemplate, vars TemplateVariables) (string, er
 	}
 
 	result := buf.String()
+	if marked {
+		result, _ = repairEmptyFields(result)
+	}
 
 	// Clean up extra whitespace
 	result = cleanupWhitespace(result)
 
-	// Validate length
-	if len(result) > tmplDef.MaxLength {
-		return "", fmt.Errorf("rendered message exceeds maximum length (%d > %d)", len(result), tmplDef.MaxLength)
+	// Scraped names and titles can carry characters the note field rejects
+	if tmplDef.Type == TemplateConnectionRequest {
+		result = SanitizeNote(result)
+	}
+
+	// Validate length the way LinkedIn counts it
+	length := messageLength(result, tmplDef.Type)
+	if maxLength := maxLengthFor(tmplDef); length > maxLength {
+		return "", fmt.Errorf("rendered %w (%d > %d)", ErrMessageTooLong, length, maxLength)
 	}
 
 	// Validate that we didn't end up with an empty message
@@ -210,7 +338,11 @@ func RenderTemplate(tmplDef MessageTemplate, vars TemplateVariables) (string, er
 		return "", fmt.Errorf("rendered message is empty - check that template variables are provided")
 	}
 
-	logger.Info(fmt.Sprintf("Rendered template '%s' (%d characters)", tmplDef.Name, len(result)))
+	if minLength := minLengthFor(tmplDef); length < minLength {
+		return "", fmt.Errorf("%w: %d characters (min %d) from template '%s'", ErrNoteTooShort, length, minLength, tmplDef.ID)
+	}
+
+	logger.Info(fmt.Sprintf("Rendered template '%s' (%d characters)", tmplDef.Name, length))
 	return result, nil
 }
 
@@ -226,7 +358,7 @@ func RenderSubject(subjectTemplate string, vars TemplateVariables) string {
 	}
 
 	// Parse the template
-	t, err := template.New("subject").Parse(subjectTemplate)
+	t, err := template.New("subject").Funcs(templateFuncs).Parse(subjectTemplate)
 	if err != nil {
 		// Fallback to simple replacement if parsing fails
 		logger.Warning("Failed to parse subject template, falling back to simple replacement: " + err.Error())
@@ -243,9 +375,7 @@ func RenderSubject(subjectTemplate string, vars TemplateVariables) string {
 	result := buf.String()
 
 	// Trim to max length if needed
-	if len(result) > SubjectMaxLength {
-		result = result[:SubjectMaxLength-3] + "..."
-	}
+	result = TruncateMessage(result, SubjectMaxLength)
 
 	return strings.TrimSpace(result)
 }
@@ -288,15 +418,16 @@ func GetTemplatesByType(templateType TemplateType) []MessageTemplate {
 
 // ValidateMessageLength checks if a message is within LinkedIn's limits
 func ValidateMessageLength(message string, messageType TemplateType) error {
-	length := len(message)
+	// Count characters as LinkedIn does, not bytes
+	length := messageLength(message, messageType)
 
 	if messageType == TemplateConnectionRequest {
-		if length > ConnectionNoteMaxLength {
-			return fmt.Errorf("connection note too long: %d characters (max %d)", length, ConnectionNoteMaxLength)
+		if noteMax := GetConnectionNoteMaxLength(); length > noteMax {
+			return fmt.Errorf("connection note too long: %d characters (max %d): %w", length, noteMax, ErrMessageTooLong)
 		}
 	} else {
 		if length > MessageMaxLength {
-			return fmt.Errorf("message too long: %d characters (max %d)", length, MessageMaxLength)
+			return fmt.Errorf("message too long: %d characters (max %d): %w", length, MessageMaxLength, ErrMessageTooLong)
 		}
 	}
 
@@ -304,9 +435,57 @@ func ValidateMessageLength(message string, messageType TemplateType) error {
 		return fmt.Errorf("message cannot be empty")
 	}
 
+	if messageType == TemplateConnectionRequest {
+		if noteMin := GetConnectionNoteMinLength(); length < noteMin {
+			return fmt.Errorf("%w: %d characters (min %d)", ErrNoteTooShort, length, noteMin)
+		}
+	}
+
 	return nil
 }
 
+// EstimateLinkedInNoteLength estimates how long LinkedIn considers a connection note.
+// The note field counts like a browser text field: in UTF-16 code units, so emoji
+// and other characters outside the Basic Multilingual Plane count as 2, and
+// newlines are submitted as CRLF, so each counts as 2.
+func EstimateLinkedInNoteLength(s string) int {
+	length := 0
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			length += 2
+		case r == '\r':
+			// Part of a CRLF already counted at its \n
+		default:
+			length += utf16.RuneLen(r)
+		}
+	}
+	return length
+}
+
+// messageLength returns the length LinkedIn enforces its limit on: the note
+// estimate for connection notes, otherwise characters (not bytes)
+func messageLength(message string, messageType TemplateType) int {
+	if messageType == TemplateConnectionRequest {
+		return EstimateLinkedInNoteLength(message)
+	}
+	return utf8.RuneCountInString(message)
+}
+
+// TruncateNote shortens a connection note with an ellipsis so that
+// EstimateLinkedInNoteLength fits maxLength, never splitting a character
+func TruncateNote(note string, maxLength int) string {
+	if EstimateLinkedInNoteLength(note) <= maxLength {
+		return note
+	}
+
+	runes := []rune(note)
+	for len(runes) > 0 && EstimateLinkedInNoteLength(string(runes))+3 > maxLength {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
 // cleanupWhitespace removes excessive whitespace from text
 func cleanupWhitespace(text string) string {
 	// Replace multiple spaces with single space
@@ -331,10 +510,11 @@ func cleanupWhitespace(text string) string {
 
 // TruncateMessage truncates a message to fit within the specified length
 func TruncateMessage(message string, maxLength int) string {
-	if len(message) <= maxLength {
+	runes := []rune(message)
+	if len(runes) <= maxLength {
 		return message
 	}
 
-	// Truncate with ellipsis
-	return message[:maxLength-3] + "..."
+	// Truncate with ellipsis, never splitting a multibyte character
+	return string(runes[:maxLength-3]) + "..."
 }
@@ -0,0 +1,171 @@
+package automation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/notify"
+	"linkedin-automation/pkg/utils"
+)
+
+// coolOffFileName is the control file holding the end of the cool-off (RFC 3339)
+const coolOffFileName = "COOLOFF"
+
+var unusualActivityPattern = regexp.MustCompile(utils.UnusualActivityTextPattern)
+
+// coolOffTripped remembers a cool-off started this run, in case the control file couldn't be written
+var (
+	coolOffMu      sync.Mutex
+	coolOffTripped time.Time
+)
+
+// GetCoolOffDuration reads UNUSUAL_ACTIVITY_COOLOFF_HOURS (default 48): how long
+// nothing is sent after LinkedIn shows its "unusual activity" banner
+func GetCoolOffDuration() time.Duration {
+	if v := os.Getenv("UNUSUAL_ACTIVITY_COOLOFF_HOURS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			return time.Duration(val) * time.Hour
+		}
+	}
+	return 48 * time.Hour
+}
+
+// DetectUnusualActivityBanner reports whether the page shows LinkedIn's soft
+// "we noticed unusual activity" warning. Unlike a checkpoint the page still
+// works, but carrying on invites a restriction.
+func DetectUnusualActivityBanner(page *rod.Page) bool {
+	banners, err := page.Timeout(2 * time.Second).Elements(utils.Selectors.UnusualActivityBanner)
+	if err != nil {
+		return false
+	}
+
+	for _, banner := range banners {
+		text, err := banner.Text()
+		if err == nil && isUnusualActivityText(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnusualActivityText reports whether banner text is the unusual activity warning
+func isUnusualActivityText(text string) bool {
+	return unusualActivityPattern.MatchString(strings.Join(strings.Fields(text), " "))
+}
+
+// AfterPageLoad runs the checks every loaded page gets. On the unusual
+// activity banner it starts the cool-off, which stops sending for the rest of
+// the run and for UNUSUAL_ACTIVITY_COOLOFF_HOURS, and notifies NOTIFY_WEBHOOK_URL.
+func AfterPageLoad(page *rod.Page) {
+	if CoolingOff() || !DetectUnusualActivityBanner(page) {
+		return
+	}
+
+	url := ""
+	if info, err := page.Info(); err == nil {
+		url = info.URL
+	}
+	logger.Error("⚠️  LinkedIn shows an unusual activity warning at " + url + " - stopping all actions")
+
+	until, err := startCoolOff(controlDir(), time.Now(), GetCoolOffDuration())
+	if err != nil {
+		logger.Warning("Failed to persist the cool-off, it only lasts this run: " + err.Error())
+	}
+	notifyUnusualActivity(notify.FromEnv(), url, until)
+}
+
+// CoolingOff reports whether a cool-off from an unusual activity warning is in force
+func CoolingOff() bool {
+	_, active := coolOffUntil(controlDir(), time.Now())
+	return active
+}
+
+// CheckCoolOff returns an error wrapping ErrCoolingOff while a cool-off is in
+// force, so a run can exit before it opens LinkedIn at all
+func CheckCoolOff() error {
+	return checkCoolOff(controlDir(), time.Now())
+}
+
+// checkCoolOff is CheckCoolOff with an explicit control directory and time
+func checkCoolOff(dir string, now time.Time) error {
+	if until, active := coolOffUntil(dir, now); active {
+		return fmt.Errorf("%w (until %s)", ErrCoolingOff, until.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// startCoolOff records a cool-off lasting d from now in dir's COOLOFF file and
+// in memory, and returns when it ends. A later end already on file is kept.
+func startCoolOff(dir string, now time.Time, d time.Duration) (time.Time, error) {
+	until := now.Add(d)
+	if current, active := coolOffUntil(dir, now); active && current.After(until) {
+		until = current
+	}
+
+	coolOffMu.Lock()
+	coolOffTripped = until
+	coolOffMu.Unlock()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return until, fmt.Errorf("failed to create control directory: %w", err)
+	}
+	path := filepath.Join(dir, coolOffFileName)
+	if err := os.WriteFile(path, []byte(until.Format(time.RFC3339)+"\n"), 0644); err != nil {
+		return until, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	logger.Warning(fmt.Sprintf("Cooling off until %s (delete %s to resume sooner)", until.Format("2006-01-02 15:04"), path))
+	return until, nil
+}
+
+// coolOffUntil returns when the cool-off ends and whether it is still in force
+// at now. An unreadable COOLOFF file counts as in force for safety; it ends
+// one default cool-off after it was written.
+func coolOffUntil(dir string, now time.Time) (time.Time, bool) {
+	coolOffMu.Lock()
+	until := coolOffTripped
+	coolOffMu.Unlock()
+
+	path := filepath.Join(dir, coolOffFileName)
+	if info, err := os.Stat(path); err == nil {
+		fileUntil := info.ModTime().Add(GetCoolOffDuration())
+		if data, err := os.ReadFile(path); err == nil {
+			if parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data))); err == nil {
+				fileUntil = parsed
+			}
+		}
+		if fileUntil.After(until) {
+			until = fileUntil
+		}
+	}
+
+	return until, now.Before(until)
+}
+
+// notifyUnusualActivity sends an unusual_activity event; delivery failures are only logged
+func notifyUnusualActivity(n notify.Notifier, url string, until time.Time) {
+	if n == nil {
+		return
+	}
+
+	event := notify.Event{
+		Type:    notify.EventUnusualActivity,
+		Message: "LinkedIn showed an unusual activity warning - automation stopped until " + until.Format(time.RFC3339),
+		Data: map[string]interface{}{
+			"url":            url,
+			"cool_off_until": until.Format(time.RFC3339),
+		},
+	}
+	if err := n.Notify(event); err != nil {
+		logger.Warning("Failed to send unusual activity alert: " + err.Error())
+	}
+}
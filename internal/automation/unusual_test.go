@@ -0,0 +1,143 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/notify"
+)
+
+// resetCoolOff clears the in-memory cool-off when the test ends
+func resetCoolOff(t *testing.T) {
+	t.Cleanup(func() {
+		coolOffMu.Lock()
+		coolOffTripped = time.Time{}
+		coolOffMu.Unlock()
+	})
+}
+
+func TestIsUnusualActivityText(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"We noticed unusual activity on your account. To keep it safe, review your recent activity.", true},
+		{"We've noticed some unusual activity from your account", true},
+		{"We’ve noticed some\n  unusual activity", true},
+		{"There's been unusual activity from your account", true},
+		{"We’ve restricted some activity on your account", true},
+		{"WE NOTICED UNUSUAL ACTIVITY", true},
+
+		{"You've reached the weekly invitation limit", false},
+		{"Your invitation to Jane Doe was sent.", false},
+		{"Unusual times call for unusual measures - a post about activity trackers", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isUnusualActivityText(tt.text); got != tt.want {
+			t.Errorf("isUnusualActivityText(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCoolOffPersistsAndExpires(t *testing.T) {
+	resetCoolOff(t)
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	if _, active := coolOffUntil(dir, now); active {
+		t.Fatal("Expected no cool-off before one is started")
+	}
+
+	until, err := startCoolOff(dir, now, 48*time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !until.Equal(now.Add(48 * time.Hour)) {
+		t.Errorf("Expected the cool-off to end at %s, got %s", now.Add(48*time.Hour), until)
+	}
+
+	// A fresh process reads the end from the COOLOFF file
+	coolOffMu.Lock()
+	coolOffTripped = time.Time{}
+	coolOffMu.Unlock()
+
+	if got, active := coolOffUntil(dir, now.Add(47*time.Hour)); !active || !got.Equal(until) {
+		t.Errorf("Expected the cool-off in force until %s, got %s (active %v)", until, got, active)
+	}
+	if _, active := coolOffUntil(dir, now.Add(49*time.Hour)); active {
+		t.Error("Expected the cool-off to have ended")
+	}
+
+	// A shorter cool-off never cuts a longer one short
+	if later, _ := startCoolOff(dir, now.Add(time.Hour), time.Hour); !later.Equal(until) {
+		t.Errorf("Expected the longer cool-off to be kept, got %s", later)
+	}
+}
+
+func TestCoolOffUnreadableFileStillCounts(t *testing.T) {
+	resetCoolOff(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, coolOffFileName), []byte("soon\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, active := coolOffUntil(dir, time.Now()); !active {
+		t.Error("Expected an unreadable COOLOFF file to count as a cool-off")
+	}
+}
+
+func TestWaitWhilePausedStopsDuringCoolOff(t *testing.T) {
+	resetCoolOff(t)
+	dir := t.TempDir()
+	if _, err := startCoolOff(dir, time.Now(), time.Hour); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err := waitWhilePaused(context.Background(), dir, time.Millisecond)
+	if !errors.Is(err, ErrCoolingOff) {
+		t.Errorf("Expected ErrCoolingOff, got %v", err)
+	}
+}
+
+func TestCheckCoolOff(t *testing.T) {
+	resetCoolOff(t)
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	if err := checkCoolOff(dir, now); err != nil {
+		t.Fatalf("Expected no cool-off yet, got %v", err)
+	}
+	if _, err := startCoolOff(dir, now, time.Hour); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := checkCoolOff(dir, now.Add(30*time.Minute)); !errors.Is(err, ErrCoolingOff) {
+		t.Errorf("Expected ErrCoolingOff during the cool-off, got %v", err)
+	}
+	if err := checkCoolOff(dir, now.Add(2*time.Hour)); err != nil {
+		t.Errorf("Expected the cool-off to end, got %v", err)
+	}
+}
+
+func TestNotifyUnusualActivity(t *testing.T) {
+	n := &recordingNotifier{}
+	until := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	notifyUnusualActivity(n, "https://www.linkedin.com/feed/", until)
+
+	if len(n.events) != 1 {
+		t.Fatalf("Expected one event, got %d", len(n.events))
+	}
+	event := n.events[0]
+	if event.Type != notify.EventUnusualActivity || event.Data["cool_off_until"] != "2026-03-04T10:00:00Z" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	// Without a webhook nothing is sent
+	notifyUnusualActivity(nil, "https://www.linkedin.com/feed/", until)
+}
@@ -0,0 +1,328 @@
+package automation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/stealth"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
+)
+
+// A shadow-limited account can still send invitations, but LinkedIn quietly
+// stops showing it to others. Nothing reports this directly, so ProbeVisibility
+// reads signals that tend to move when it happens:
+//
+//   - Search appearances: the dashboard's weekly count. A sharp drop against the
+//     account's own recent average suggests it is being left out of search.
+//   - Profile views: the dashboard's 90-day count. Falls more slowly, so it
+//     weighs less than search appearances.
+//   - Sent invitations: recent requests still pending in the database should be
+//     listed under My Network → Sent. Invitations that vanish from that list
+//     without being accepted or withdrawn were likely discarded.
+//
+// These are heuristics. Views and appearances also drop when activity slows
+// down, and a single probe means little; the score compares each probe with the
+// probes stored over the previous visibilityHistoryDays days.
+
+// Risk levels of a VisibilityAssessment
+const (
+	VisibilityRiskLow    = "low"
+	VisibilityRiskMedium = "medium"
+	VisibilityRiskHigh   = "high"
+)
+
+const (
+	dashboardURL      = "https://www.linkedin.com/dashboard/"
+	sentInvitationURL = "https://www.linkedin.com/mynetwork/invitation-manager/sent/"
+
+	// visibilityHistoryDays is how far back earlier probes form the baseline
+	visibilityHistoryDays = 28
+
+	// visibilityMinBaseline is how many earlier dashboard reads a trend needs
+	visibilityMinBaseline = 2
+
+	// visibilityMinCount keeps small counts, where a drop is noise, out of the score
+	visibilityMinCount = 5
+
+	// visibilityInvitesToCheck is how many of the newest pending requests are
+	// looked for on the Sent page (only the first screenful loads reliably)
+	visibilityInvitesToCheck = 10
+
+	// visibilityInviteMaxAge excludes requests old enough to have expired
+	visibilityInviteMaxAge = 14 * 24 * time.Hour
+
+	// visibilityMinInvites is how many invitations must be checked for the ratio to count
+	visibilityMinInvites = 3
+)
+
+// Dashboard counts, e.g. "1,204 profile views" and "87 search appearances"
+var (
+	profileViewsPattern      = regexp.MustCompile(`(?i)([\d,]+)\s+profile views?`)
+	searchAppearancesPattern = regexp.MustCompile(`(?i)([\d,]+)\s+search appearances?`)
+)
+
+// VisibilitySignals are the observations of one probe
+type VisibilitySignals struct {
+	DashboardRead     bool // Whether both dashboard counts were found
+	ProfileViews      int
+	SearchAppearances int
+	InvitesChecked    int // Recent pending requests looked for under Sent
+	InvitesMissing    int // Of those, how many were not listed
+}
+
+// VisibilityAssessment is the risk that the account is shadow-limited
+type VisibilityAssessment struct {
+	Signals VisibilitySignals
+	Score   int      // 0-100
+	Level   string   // VisibilityRiskLow, VisibilityRiskMedium or VisibilityRiskHigh
+	Reasons []string // Signals that added to the score
+}
+
+// ProbeVisibility reads the visibility signals, scores them against earlier
+// probes and stores the result. A signal that cannot be read is left out of
+// the score rather than failing the probe.
+func ProbeVisibility(page *rod.Page, db *storage.Database) (*VisibilityAssessment, error) {
+	logger.Info("Probing account visibility...")
+
+	history, err := db.GetVisibilityProbes(visibilityHistoryDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load earlier probes: %w", err)
+	}
+
+	var signals VisibilitySignals
+	if text, err := readPageText(page, db, dashboardURL); err != nil {
+		logger.Warning("Could not read the dashboard: " + err.Error())
+	} else {
+		signals.ProfileViews, signals.SearchAppearances, signals.DashboardRead = parseDashboardCounts(text)
+		if !signals.DashboardRead {
+			logger.Warning("Dashboard counts not found, skipping views and search appearances")
+		}
+	}
+
+	if shown, err := scrapeSentInvitations(page, db); err != nil {
+		logger.Warning("Could not read sent invitations: " + err.Error())
+	} else if pending, err := db.GetPendingConnections(); err != nil {
+		logger.Warning("Could not load pending requests: " + err.Error())
+	} else {
+		signals.InvitesChecked, signals.InvitesMissing = missingInvitations(pending, shown, time.Now())
+	}
+
+	assessment := ScoreVisibility(signals, history)
+
+	err = db.SaveVisibilityProbe(storage.VisibilityProbe{
+		ProbedAt:          time.Now(),
+		DashboardRead:     signals.DashboardRead,
+		ProfileViews:      signals.ProfileViews,
+		SearchAppearances: signals.SearchAppearances,
+		InvitesChecked:    signals.InvitesChecked,
+		InvitesMissing:    signals.InvitesMissing,
+		RiskScore:         assessment.Score,
+		RiskLevel:         assessment.Level,
+	})
+	if err != nil {
+		logger.Warning("Failed to save visibility probe: " + err.Error())
+	}
+
+	logger.Info(fmt.Sprintf("Visibility risk: %s (%d/100)", assessment.Level, assessment.Score))
+	return &assessment, nil
+}
+
+// ScoreVisibility scores the signals of a probe against earlier probes (oldest
+// first). Each signal adds to the score on its own:
+//   - half or more of the checked invitations missing: +40 (a fifth or more: +20)
+//   - search appearances under half the baseline: +35 (under three quarters: +15)
+//   - profile views under half the baseline: +25 (under three quarters: +10)
+//
+// 60 or more is high risk, 30 or more medium.
+func ScoreVisibility(signals VisibilitySignals, history []storage.VisibilityProbe) VisibilityAssessment {
+	assessment := VisibilityAssessment{Signals: signals}
+	add := func(points int, reason string) {
+		assessment.Score += points
+		assessment.Reasons = append(assessment.Reasons, reason)
+	}
+
+	if signals.InvitesChecked >= visibilityMinInvites {
+		ratio := float64(signals.InvitesMissing) / float64(signals.InvitesChecked)
+		reason := fmt.Sprintf("%d of %d recent invitations missing from Sent", signals.InvitesMissing, signals.InvitesChecked)
+		switch {
+		case ratio >= 0.5:
+			add(40, reason)
+		case ratio >= 0.2:
+			add(20, reason)
+		}
+	}
+
+	if signals.DashboardRead {
+		views, appearances, samples := dashboardBaseline(history)
+		if samples >= visibilityMinBaseline {
+			if points := dropPoints(signals.SearchAppearances, appearances, 35, 15); points > 0 {
+				add(points, fmt.Sprintf("search appearances down to %d from an average of %.0f", signals.SearchAppearances, appearances))
+			}
+			if points := dropPoints(signals.ProfileViews, views, 25, 10); points > 0 {
+				add(points, fmt.Sprintf("profile views down to %d from an average of %.0f", signals.ProfileViews, views))
+			}
+		}
+	}
+
+	if assessment.Score > 100 {
+		assessment.Score = 100
+	}
+
+	switch {
+	case assessment.Score >= 60:
+		assessment.Level = VisibilityRiskHigh
+	case assessment.Score >= 30:
+		assessment.Level = VisibilityRiskMedium
+	default:
+		assessment.Level = VisibilityRiskLow
+	}
+
+	return assessment
+}
+
+// dashboardBaseline averages the dashboard counts of earlier probes that read them
+func dashboardBaseline(history []storage.VisibilityProbe) (views, appearances float64, samples int) {
+	for _, probe := range history {
+		if !probe.DashboardRead {
+			continue
+		}
+		views += float64(probe.ProfileViews)
+		appearances += float64(probe.SearchAppearances)
+		samples++
+	}
+	if samples == 0 {
+		return 0, 0, 0
+	}
+	return views / float64(samples), appearances / float64(samples), samples
+}
+
+// dropPoints returns major when current is under half of baseline, minor when
+// under three quarters, and 0 otherwise or when baseline is too small to judge
+func dropPoints(current int, baseline float64, major, minor int) int {
+	if baseline < visibilityMinCount {
+		return 0
+	}
+	switch ratio := float64(current) / baseline; {
+	case ratio < 0.5:
+		return major
+	case ratio < 0.75:
+		return minor
+	}
+	return 0
+}
+
+// parseDashboardCounts extracts the profile view and search appearance counts
+// from the dashboard's text. ok is false unless both are found.
+func parseDashboardCounts(text string) (views, appearances int, ok bool) {
+	views, viewsOK := firstCount(profileViewsPattern, text)
+	appearances, appearancesOK := firstCount(searchAppearancesPattern, text)
+	if !viewsOK || !appearancesOK {
+		return 0, 0, false
+	}
+	return views, appearances, true
+}
+
+// firstCount parses the number captured by pattern's first match in text
+func firstCount(pattern *regexp.Regexp, text string) (int, bool) {
+	match := pattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0, false
+	}
+	count, err := strconv.Atoi(strings.ReplaceAll(match[1], ",", ""))
+	if err != nil {
+		return 0, false
+	}
+	return count, true
+}
+
+// missingInvitations looks for the newest pending requests, sent within
+// visibilityInviteMaxAge of now, among the profile IDs shown under Sent
+func missingInvitations(pending []storage.ConnectionRequest, shown map[string]bool, now time.Time) (checked, missing int) {
+	for _, req := range pending {
+		if checked == visibilityInvitesToCheck {
+			break
+		}
+		if now.Sub(req.SentAt) > visibilityInviteMaxAge {
+			continue
+		}
+
+		checked++
+		if !shown[req.ProfileID] {
+			missing++
+		}
+	}
+	return checked, missing
+}
+
+// readPageText navigates to url and returns the text of its main content
+func readPageText(page *rod.Page, db *storage.Database, url string) (string, error) {
+	if err := navigate(page, db, url, ""); err != nil {
+		return "", fmt.Errorf("failed to navigate to %s: %w", url, err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return "", fmt.Errorf("failed to load %s: %w", url, err)
+	}
+	stealth.RandomDelay(2000, 4000)
+
+	main, err := page.Timeout(10 * time.Second).Element("main")
+	if err != nil {
+		return "", fmt.Errorf("main content not found: %w", err)
+	}
+	return main.Text()
+}
+
+// openSentInvitations navigates to the Sent invitations page and lets it load
+func openSentInvitations(page *rod.Page, db *storage.Database) error {
+	return openSentInvitationsPage(page, db, 1)
+}
+
+// openSentInvitationsPage navigates to page pageNum of the Sent invitations list and lets it load
+func openSentInvitationsPage(page *rod.Page, db *storage.Database, pageNum int) error {
+	if err := navigate(page, db, sentInvitationsPageURL(pageNum), ""); err != nil {
+		return fmt.Errorf("failed to navigate to sent invitations: %w", err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to load sent invitations: %w", err)
+	}
+	stealth.RandomDelay(2000, 4000)
+	stealth.RandomScroll(page)
+	return nil
+}
+
+// scrapeSentInvitations returns the profile IDs listed under Sent invitations
+func scrapeSentInvitations(page *rod.Page, db *storage.Database) (map[string]bool, error) {
+	if err := openSentInvitations(page, db); err != nil {
+		return nil, err
+	}
+
+	links, err := page.Elements(utils.Selectors.SentInvitationLink)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sent invitations: %w", err)
+	}
+
+	// An empty list is far more likely a stale selector than every invitation gone
+	if len(links) == 0 {
+		return nil, fmt.Errorf("no sent invitations found (check sent_invitation_link selector)")
+	}
+
+	shown := make(map[string]bool)
+	for _, link := range links {
+		href, err := link.Attribute("href")
+		if err != nil || href == nil {
+			continue
+		}
+		if profileID := utils.ExtractProfileID(*href); profileID != "" {
+			shown[profileID] = true
+		}
+	}
+
+	logger.Info(fmt.Sprintf("Found %d sent invitations", len(shown)))
+	return shown, nil
+}
@@ -0,0 +1,139 @@
+package automation
+
+import (
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+// steadyHistory is three earlier probes averaging 100 views and 40 appearances
+var steadyHistory = []storage.VisibilityProbe{
+	{DashboardRead: true, ProfileViews: 90, SearchAppearances: 35},
+	{DashboardRead: true, ProfileViews: 100, SearchAppearances: 40},
+	{DashboardRead: true, ProfileViews: 110, SearchAppearances: 45},
+	{DashboardRead: false}, // Failed read, not part of the baseline
+}
+
+func TestScoreVisibility(t *testing.T) {
+	tests := []struct {
+		name      string
+		signals   VisibilitySignals
+		history   []storage.VisibilityProbe
+		wantScore int
+		wantLevel string
+	}{
+		{
+			"Steady signals",
+			VisibilitySignals{DashboardRead: true, ProfileViews: 105, SearchAppearances: 42, InvitesChecked: 10},
+			steadyHistory, 0, VisibilityRiskLow,
+		},
+		{
+			"Most invitations missing",
+			VisibilitySignals{InvitesChecked: 10, InvitesMissing: 6},
+			nil, 40, VisibilityRiskMedium,
+		},
+		{
+			"Some invitations missing",
+			VisibilitySignals{InvitesChecked: 5, InvitesMissing: 1},
+			nil, 20, VisibilityRiskLow,
+		},
+		{
+			"Too few invitations to judge",
+			VisibilitySignals{InvitesChecked: 2, InvitesMissing: 2},
+			nil, 0, VisibilityRiskLow,
+		},
+		{
+			"Search appearances collapsed",
+			VisibilitySignals{DashboardRead: true, ProfileViews: 95, SearchAppearances: 10},
+			steadyHistory, 35, VisibilityRiskMedium,
+		},
+		{
+			"Moderate drop in both counts",
+			VisibilitySignals{DashboardRead: true, ProfileViews: 70, SearchAppearances: 28},
+			steadyHistory, 25, VisibilityRiskLow,
+		},
+		{
+			"All signals bad",
+			VisibilitySignals{DashboardRead: true, ProfileViews: 20, SearchAppearances: 5, InvitesChecked: 8, InvitesMissing: 8},
+			steadyHistory, 100, VisibilityRiskHigh,
+		},
+		{
+			"Drop ignored without enough history",
+			VisibilitySignals{DashboardRead: true, ProfileViews: 5, SearchAppearances: 0},
+			steadyHistory[:1], 0, VisibilityRiskLow,
+		},
+		{
+			"Drop ignored when baseline is tiny",
+			VisibilitySignals{DashboardRead: true, ProfileViews: 0, SearchAppearances: 0},
+			[]storage.VisibilityProbe{{DashboardRead: true, ProfileViews: 3, SearchAppearances: 2}, {DashboardRead: true, ProfileViews: 4, SearchAppearances: 1}},
+			0, VisibilityRiskLow,
+		},
+		{
+			"Unread dashboard ignores history",
+			VisibilitySignals{InvitesChecked: 4, InvitesMissing: 2},
+			steadyHistory, 40, VisibilityRiskMedium,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreVisibility(tt.signals, tt.history)
+			if got.Score != tt.wantScore || got.Level != tt.wantLevel {
+				t.Errorf("Expected %s (%d), got %s (%d), reasons: %v", tt.wantLevel, tt.wantScore, got.Level, got.Score, got.Reasons)
+			}
+			if (got.Score > 0) != (len(got.Reasons) > 0) {
+				t.Errorf("Expected a reason for each scored signal, got %v for score %d", got.Reasons, got.Score)
+			}
+		})
+	}
+}
+
+func TestParseDashboardCounts(t *testing.T) {
+	tests := []struct {
+		name            string
+		text            string
+		wantViews       int
+		wantAppearances int
+		wantOK          bool
+	}{
+		{"Both counts", "1,204\nprofile views\nDiscover who's viewed your profile.\n87\nsearch appearances", 1204, 87, true},
+		{"Singular", "1 Profile view\n0 search appearances", 1, 0, true},
+		{"Missing appearances", "1,204 profile views", 0, 0, false},
+		{"No counts", "Something went wrong", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			views, appearances, ok := parseDashboardCounts(tt.text)
+			if views != tt.wantViews || appearances != tt.wantAppearances || ok != tt.wantOK {
+				t.Errorf("Expected (%d, %d, %v), got (%d, %d, %v)", tt.wantViews, tt.wantAppearances, tt.wantOK, views, appearances, ok)
+			}
+		})
+	}
+}
+
+func TestMissingInvitations(t *testing.T) {
+	now := time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC)
+	pending := []storage.ConnectionRequest{
+		{ProfileID: "shown-1", SentAt: now.Add(-time.Hour)},
+		{ProfileID: "gone-1", SentAt: now.Add(-24 * time.Hour)},
+		{ProfileID: "shown-2", SentAt: now.Add(-48 * time.Hour)},
+		{ProfileID: "expired", SentAt: now.AddDate(0, 0, -30)},
+	}
+	shown := map[string]bool{"shown-1": true, "shown-2": true}
+
+	checked, missing := missingInvitations(pending, shown, now)
+	if checked != 3 || missing != 1 {
+		t.Errorf("Expected 1 of 3 missing, got %d of %d", missing, checked)
+	}
+
+	// Only the newest visibilityInvitesToCheck requests are looked for
+	var many []storage.ConnectionRequest
+	for i := 0; i < visibilityInvitesToCheck+5; i++ {
+		many = append(many, storage.ConnectionRequest{ProfileID: "p", SentAt: now})
+	}
+	if checked, _ := missingInvitations(many, nil, now); checked != visibilityInvitesToCheck {
+		t.Errorf("Expected %d checked, got %d", visibilityInvitesToCheck, checked)
+	}
+}
@@ -1,16 +1,32 @@
 package automation
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/go-rod/rod"
 
 	"linkedin-automation/internal/logger"
 	"linkedin-automation/internal/storage"
+	"linkedin-automation/pkg/utils"
 )
 
+// GetMinDaysSinceAccepted reads FOLLOWUP_MIN_DAYS_SINCE_ACCEPTED (default 0):
+// days a connection must have been accepted before its first follow-up, so
+// messages don't land seconds after the acceptance
+func GetMinDaysSinceAccepted() int {
+	if v := os.Getenv("FOLLOWUP_MIN_DAYS_SINCE_ACCEPTED"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			return val
+		}
+	}
+	return 0
+}
+
 // ProcessDailyFollowUps handles the daily follow-up messaging workflow
 func ProcessDailyFollowUps(page *rod.Page, db *storage.Database, rateLimiter *RateLimiter) error {
 	logger.Info("Starting daily follow-up workflow...")
@@ -20,6 +36,13 @@ func ProcessDailyFollowUps(page *rod.Page, db *storage.Database, rateLimiter *Ra
 		if err := CheckRecentConnections(page, db); err != nil {
 			logger.Error("Failed to check recent connections: " + err.Error())
 		}
+
+		// Catch older acceptances that fell off the "Recently Added" list
+		if os.Getenv("RECONCILE_FIRST_DEGREE") == "true" {
+			if _, err := BulkReconcileFirstDegree(page, db); err != nil {
+				logger.Error("Failed to reconcile 1st-degree connections: " + err.Error())
+			}
+		}
 	}
 
 	// 2. Check for replies (stop automation for them)
@@ -35,12 +58,27 @@ func ProcessDailyFollowUps(page *rod.Page, db *storage.Database, rateLimiter *Ra
 			return nil
 		}
 
+		// Follow-ups queued by accepted sequences go first - they are already due
+		if _, err := ProcessScheduledMessages(page, db, rateLimiter); err != nil {
+			logger.Error("Failed to send scheduled messages: " + err.Error())
+		}
+
+		// Work through a defined campaign instead of ad-hoc follow-ups when one is configured
+		if envCampaign := os.Getenv("MESSAGING_CAMPAIGN_ID"); envCampaign != "" {
+			campaignID, err := strconv.ParseInt(envCampaign, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid MESSAGING_CAMPAIGN_ID %q: %w", envCampaign, err)
+			}
+			_, err = RunMessagingCampaign(page, db, rateLimiter, campaignID)
+			return err
+		}
+
 		maxMessages := 3
 		if os.Getenv("MAX_MESSAGES_PER_RUN") != "" {
 			fmt.Sscanf(os.Getenv("MAX_MESSAGES_PER_RUN"), "%d", &maxMessages)
 		}
 
-		profiles, err := db.GetAcceptedConnectionProfiles(maxMessages, 30)
+		profiles, err := db.GetAcceptedConnectionProfiles(maxMessages, 30, GetMinDaysSinceAccepted())
 		if err != nil {
 			return fmt.Errorf("failed to get profiles for messaging: %w", err)
 		}
@@ -52,14 +90,33 @@ func ProcessDailyFollowUps(page *rod.Page, db *storage.Database, rateLimiter *Ra
 			templateID = "msg_introduction"
 		}
 
+		// MESSAGE_TEMPLATE_ROTATION cycles through several templates instead
+		rotator, err := TemplateRotatorFromEnv(db, false)
+		if err != nil {
+			logger.Warning("Invalid message template rotation, using " + templateID + ": " + err.Error())
+		}
+
 		for _, profile := range profiles {
+			// Honor the PAUSE / STOP control files between messages
+			if err := WaitWhilePaused(context.Background()); err != nil {
+				logger.Warning("Stopping follow-ups: " + err.Error())
+				break
+			}
+
 			// Check rate limit again
 			if err := rateLimiter.CheckDailyLimit(TaskMessage); err != nil {
 				break
 			}
 
-			tmpl, err := GetTemplateByID(templateID)
-			if err != nil {
+			var tmpl *MessageTemplate
+			if rotator != nil {
+				next, err := rotator.Next()
+				if err != nil {
+					logger.Error("Template rotation unusable: " + err.Error())
+					break
+				}
+				tmpl = &next
+			} else if tmpl, err = GetTemplateByID(templateID); err != nil {
 				logger.Error("Template not found: " + err.Error())
 				continue
 			}
@@ -80,6 +137,7 @@ func ProcessDailyFollowUps(page *rod.Page, db *storage.Database, rateLimiter *Ra
 				Industry:     os.Getenv("YOUR_INDUSTRY"),
 				CustomReason: os.Getenv("MESSAGE_CUSTOM_REASON"),
 			}
+			vars = RandomizeClauses(vars, GetClauseConfig(), utils.SessionRand())
 
 			body, err := RenderTemplate(*tmpl, vars)
 			if err != nil {
@@ -95,10 +153,15 @@ func ProcessDailyFollowUps(page *rod.Page, db *storage.Database, rateLimiter *Ra
 				TemplateID: tmpl.ID,
 			}
 
-			if err := SendMessage(page, db, req); err != nil {
+			if err := SendMessage(page, db, req); errors.Is(err, ErrNotWhitelisted) {
+				logger.Info(fmt.Sprintf("Skipping %s: %s", profile.Name, err.Error()))
+			} else if err != nil {
 				logger.Error(fmt.Sprintf("Failed to send message to %s: %s", profile.Name, err.Error()))
 			} else {
 				rateLimiter.RecordAction(TaskMessage)
+				if rotator != nil {
+					rotator.Advance(tmpl.ID)
+				}
 			}
 		}
 	}
@@ -3,9 +3,14 @@ package browser
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+	"github.com/go-rod/rod/lib/proto"
 
 	"linkedin-automation/internal/logger"
 	"linkedin-automation/internal/stealth"
@@ -13,29 +18,108 @@ import (
 
 // BrowserConfig holds configuration for browser initialization
 type BrowserConfig struct {
-	UserDataDir string
-	Headless    bool
+	UserDataDir       string
+	Headless          bool
+	BrowserBinaryPath string   // Chrome executable to launch (default: found or downloaded by Rod)
+	ExtraLaunchFlags  []string // Additional Chrome flags, e.g. "--disable-gpu" or "--lang=en-US"
 }
 
+// DefaultUserDataDir is the persistent Chrome profile directory
+const DefaultUserDataDir = "./browser_data"
+
+// headlessWindowSize is the window size used in headless mode unless CHROME_FLAGS
+// sets one; headless Chrome otherwise defaults to a telltale 800x600 window
+const headlessWindowSize = "1920,1080"
+
+// headlessActive records whether the running browser is headless, so page
+// fingerprinting can hide the tells headless Chrome leaves behind
+var headlessActive bool
+
 // StartBrowser launches and returns a Rod Browser instance with persistent session support
 // Reads HEADLESS configuration from environment variable
 func StartBrowser() (*rod.Browser, error) {
+	return StartBrowserWithConfig(DefaultBrowserConfig())
+}
+
+// DefaultBrowserConfig returns the persistent-profile configuration,
+// reading headless mode from the HEADLESS environment variable
+func DefaultBrowserConfig() BrowserConfig {
 	// Read headless mode from environment (default: false for visibility)
-	headless := false
-	if os.Getenv("HEADLESS") == "true" {
-		headless = true
+	headless, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("HEADLESS")))
+	if headless {
 		logger.Info("Browser starting in headless mode (no visible window)")
 	} else {
 		logger.Info("Browser starting in visible mode")
 	}
 
-	return StartBrowserWithConfig(BrowserConfig{
-		UserDataDir: "./browser_data",
+	return BrowserConfig{
+		UserDataDir: DefaultUserDataDir,
 		Headless:    headless,
-	})
+	}
+}
+
+// ParseLaunchFlags splits a comma-separated CHROME_FLAGS value into flags.
+// A comma inside a flag's value is kept when the next part doesn't start
+// with "-", so "--window-size=1280,800,--disable-gpu" yields two flags.
+func ParseLaunchFlags(spec string) []string {
+	var launchFlags []string
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if n := len(launchFlags); n > 0 && !strings.HasPrefix(part, "-") && strings.Contains(launchFlags[n-1], "=") {
+			launchFlags[n-1] += "," + part
+			continue
+		}
+		launchFlags = append(launchFlags, part)
+	}
+	return launchFlags
+}
+
+// newLauncher builds the Chrome launcher for a configuration.
+// Returns an error if a custom binary is configured but doesn't exist.
+func newLauncher(config BrowserConfig) (*launcher.Launcher, error) {
+	l := launcher.New().
+		Delete("leakless").
+		Headless(config.Headless).
+		UserDataDir(config.UserDataDir)
+
+	// Give headless Chrome a desktop-sized window; CHROME_FLAGS can still override it
+	if config.Headless {
+		l = l.Set("window-size", headlessWindowSize)
+	}
+
+	if config.BrowserBinaryPath != "" {
+		info, err := os.Stat(config.BrowserBinaryPath)
+		if err != nil {
+			return nil, fmt.Errorf("chrome binary not found at %s: %w", config.BrowserBinaryPath, err)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("chrome binary path %s is a directory", config.BrowserBinaryPath)
+		}
+		l = l.Bin(config.BrowserBinaryPath)
+		logger.Info("Using custom Chrome binary: " + config.BrowserBinaryPath)
+	}
+
+	for _, launchFlag := range config.ExtraLaunchFlags {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(launchFlag, "-"), "=")
+		if name == "" {
+			continue
+		}
+		if hasValue {
+			l = l.Set(flags.Flag(name), value)
+		} else {
+			l = l.Set(flags.Flag(name))
+		}
+	}
+
+	return l, nil
 }
 
 // StartBrowserWithConfig launches a browser with custom configuration
+// The user data directory is locked until CloseBrowser is called
 func StartBrowserWithConfig(config BrowserConfig) (*rod.Browser, error) {
 	logger.Info("Launching browser with persistent session...")
 
@@ -45,25 +129,48 @@ func StartBrowserWithConfig(config BrowserConfig) (*rod.Browser, error) {
 	}
 
 	// Configure launcher with user data directory for session persistence
-	l := launcher.New().
-		Delete("leakless").
-		Headless(config.Headless).
-		UserDataDir(config.UserDataDir)
+	l, err := newLauncher(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kill Chrome left running against this profile by a crashed run
+	if os.Getenv("CLEANUP_ORPHANS") == "true" {
+		if err := CleanupOrphans(config.UserDataDir); err != nil {
+			logger.Warning("Failed to clean up orphaned Chrome processes: " + err.Error())
+		}
+	}
+
+	// Refuse to launch if another run is using the same profile
+	lockPath, err := acquireProfileLock(config.UserDataDir)
+	if err != nil {
+		return nil, err
+	}
 
 	u, err := l.Launch()
 	if err != nil {
+		releaseProfileLock(lockPath)
 		return nil, fmt.Errorf("failed to launch browser: %w", err)
 	}
 
 	logger.Info("Browser launched, connecting...")
 
-	browser := rod.New().ControlURL(u)
-
-	err = browser.Connect()
+	browser, ws, err := connectBrowser(u)
 	if err != nil {
+		releaseProfileLock(lockPath)
 		return nil, fmt.Errorf("failed to connect to browser: %w", err)
 	}
 
+	profileLocksMu.Lock()
+	profileLocks[browser] = lockPath
+	profileLocksMu.Unlock()
+
+	connectionsMu.Lock()
+	connections[browser] = &browserConn{url: u, ws: ws}
+	connectionsMu.Unlock()
+
+	headlessActive = config.Headless
+
 	logger.Info("Browser connected successfully with persistent session!")
 
 	return browser, nil
@@ -92,7 +199,9 @@ func OpenPage(browser *rod.Browser, url string) (*rod.Page, error) {
 	page := browser.MustPage("about:blank")
 
 	// CRITICAL: Apply fingerprint masking BEFORE navigation
-	// This prevents LinkedIn's detection scripts from running before our masks are in place
+	// This prevents LinkedIn's detection scripts from running before our masks are in place.
+	// The masks are registered for every new document, so they also cover the
+	// headless tells (see planFingerprint) on the page we navigate to.
 	logger.Info("Applying fingerprint masking to page before navigation...")
 	err := ApplyPageFingerprint(page)
 	if err != nil {
@@ -100,7 +209,15 @@ func OpenPage(browser *rod.Browser, url string) (*rod.Page, error) {
 		// Continue anyway - better to try with partial masking than fail completely
 	}
 
+	// RECORD_PAGES=true saves every page this tab loads, for offline parser tests
+	if dir := PageRecordDir(); dir != "" {
+		if err := RecordPages(page, dir); err != nil {
+			logger.Warning("Page recording disabled: " + err.Error())
+		}
+	}
+
 	// NOW navigate to the target URL with masking already applied
+	EnforceNavGap()
 	err = page.Navigate(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to navigate to %s: %w", url, err)
@@ -108,3 +225,14 @@ func OpenPage(browser *rod.Browser, url string) (*rod.Page, error) {
 
 	return page, nil
 }
+
+// pingTimeout bounds the liveness check so a hung browser reports as disconnected
+const pingTimeout = 2 * time.Second
+
+// Ping checks the browser is still reachable with a lightweight CDP call
+func Ping(br *rod.Browser) error {
+	if _, err := (proto.BrowserGetVersion{}).Call(br.Timeout(pingTimeout)); err != nil {
+		return fmt.Errorf("browser not responding: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/launcher/flags"
+)
+
+func TestParseLaunchFlags(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected []string
+	}{
+		{"", nil},
+		{"--disable-gpu", []string{"--disable-gpu"}},
+		{" --disable-gpu , --lang=en-US ", []string{"--disable-gpu", "--lang=en-US"}},
+		{"--window-size=1280,800,--disable-gpu", []string{"--window-size=1280,800", "--disable-gpu"}},
+		{"--disable-gpu,,", []string{"--disable-gpu"}},
+	}
+
+	for _, test := range tests {
+		if result := ParseLaunchFlags(test.spec); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("ParseLaunchFlags(%q): expected %v, got %v", test.spec, test.expected, result)
+		}
+	}
+}
+
+func TestNewLauncherCustomBinaryAndFlags(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "chrome")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to create fake binary: %v", err)
+	}
+
+	l, err := newLauncher(BrowserConfig{
+		UserDataDir:       t.TempDir(),
+		BrowserBinaryPath: bin,
+		ExtraLaunchFlags:  []string{"--disable-gpu", "--window-size=1280,800", "lang=en-US"},
+	})
+	if err != nil {
+		t.Fatalf("newLauncher failed: %v", err)
+	}
+
+	if got := l.Get(flags.Bin); got != bin {
+		t.Errorf("Expected binary %s, got %q", bin, got)
+	}
+	if !l.Has("disable-gpu") {
+		t.Error("Expected --disable-gpu to be set")
+	}
+	if got := l.Get("window-size"); got != "1280,800" {
+		t.Errorf("Expected window-size 1280,800, got %q", got)
+	}
+	if got := l.Get("lang"); got != "en-US" {
+		t.Errorf("Expected lang en-US, got %q", got)
+	}
+
+	args := strings.Join(l.FormatArgs(), " ")
+	for _, want := range []string{"--disable-gpu", "--window-size=1280,800", "--lang=en-US"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Expected %s in launch args, got %s", want, args)
+		}
+	}
+}
+
+func TestNewLauncherMissingBinary(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "no-such-chrome")
+
+	_, err := newLauncher(BrowserConfig{UserDataDir: t.TempDir(), BrowserBinaryPath: missing})
+	if err == nil {
+		t.Fatal("Expected an error for a missing Chrome binary")
+	}
+	if !strings.Contains(err.Error(), missing) {
+		t.Errorf("Expected the error to name the missing path, got %v", err)
+	}
+
+	_, err = newLauncher(BrowserConfig{UserDataDir: t.TempDir(), BrowserBinaryPath: t.TempDir()})
+	if err == nil {
+		t.Error("Expected an error when the binary path is a directory")
+	}
+}
+
+func TestNewLauncherDefaultsToRodBinary(t *testing.T) {
+	l, err := newLauncher(BrowserConfig{UserDataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("newLauncher failed: %v", err)
+	}
+	if got := l.Get(flags.Bin); got != "" {
+		t.Errorf("Expected no custom binary, got %q", got)
+	}
+}
+
+func TestNewLauncherHeadlessWindowSize(t *testing.T) {
+	l, err := newLauncher(BrowserConfig{UserDataDir: t.TempDir(), Headless: true})
+	if err != nil {
+		t.Fatalf("newLauncher failed: %v", err)
+	}
+	if got := l.Get("window-size"); got != headlessWindowSize {
+		t.Errorf("Expected headless window-size %s, got %q", headlessWindowSize, got)
+	}
+
+	// CHROME_FLAGS wins over the headless default
+	l, err = newLauncher(BrowserConfig{UserDataDir: t.TempDir(), Headless: true, ExtraLaunchFlags: []string{"--window-size=1280,800"}})
+	if err != nil {
+		t.Fatalf("newLauncher failed: %v", err)
+	}
+	if got := l.Get("window-size"); got != "1280,800" {
+		t.Errorf("Expected window-size 1280,800 from the extra flags, got %q", got)
+	}
+
+	// Headed browsers keep their own window
+	l, err = newLauncher(BrowserConfig{UserDataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("newLauncher failed: %v", err)
+	}
+	if l.Has("window-size") {
+		t.Errorf("Expected no window-size for a headed browser, got %q", l.Get("window-size"))
+	}
+}
+
+func TestDefaultBrowserConfigHeadless(t *testing.T) {
+	tests := []struct {
+		value    string
+		headless bool
+	}{
+		{"", false},
+		{"false", false},
+		{"true", true},
+		{"TRUE", true},
+		{"1", true},
+		{"yes", false},
+	}
+
+	for _, test := range tests {
+		t.Setenv("HEADLESS", test.value)
+		if got := DefaultBrowserConfig().Headless; got != test.headless {
+			t.Errorf("HEADLESS=%q: expected headless %v, got %v", test.value, test.headless, got)
+		}
+	}
+}
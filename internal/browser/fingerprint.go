@@ -2,25 +2,31 @@ package browser
 
 import (
 	"fmt"
-	"math/rand"
-	"time"
+	"strings"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 
 	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/stealth"
 	"linkedin-automation/pkg/utils"
 )
 
 // ApplyFingerprintMasking applies comprehensive anti-detection measures to the browser.
 func ApplyFingerprintMasking(br *rod.Browser) {
 	// Ignore certificate errors
-	br.MustIgnoreCertErrors(true)
+	if err := br.IgnoreCertErrors(true); err != nil {
+		logger.Warning("Failed to ignore certificate errors: " + err.Error())
+	}
 
 	logger.Info("Applying advanced fingerprint masking...")
 
 	// Get all pages and apply masking to each
-	pages := br.MustPages()
+	pages, err := br.Pages()
+	if err != nil {
+		logger.Warning("Failed to list pages for fingerprint masking: " + err.Error())
+		return
+	}
 	for _, page := range pages {
 		if err := ApplyPageFingerprint(page); err != nil {
 			logger.Warning("Failed to apply fingerprint to page: " + err.Error())
@@ -28,9 +34,77 @@ func ApplyFingerprintMasking(br *rod.Browser) {
 	}
 }
 
-// ApplyPageFingerprint applies fingerprint masking to a specific page
+// Names of the masking blocks injected by ApplyPageFingerprint
+const (
+	maskBlockWebDriver   = "webdriver"
+	maskBlockAutomation  = "automation"
+	maskBlockPlugins     = "plugins"
+	maskBlockLanguages   = "languages"
+	maskBlockPermissions = "permissions"
+	maskBlockCanvas      = "canvas"
+	maskBlockWebGL       = "webgl"
+	maskBlockScreen      = "screen"
+	maskBlockBattery     = "battery"
+	maskBlockConnection  = "connection"
+	maskBlockHeadless    = "headless"
+)
+
+// fingerprintPlan lists which parts of the fingerprint masking run for a stealth mode
+type fingerprintPlan struct {
+	Blocks    []string // masking script blocks, in injection order
+	UserAgent bool     // override the user agent
+	Viewport  bool     // randomize the viewport size
+}
+
+// planFingerprint returns the masking applied for a stealth mode.
+// Off applies nothing, basic hides the obvious automation flags,
+// advanced and maximum apply every patch. In headless mode every
+// masking mode also hides the headless tells and overrides the user agent,
+// which would otherwise contain "HeadlessChrome".
+func planFingerprint(mode stealth.Mode, headless bool) fingerprintPlan {
+	basic := []string{
+		maskBlockWebDriver,
+		maskBlockAutomation,
+		maskBlockPlugins,
+		maskBlockLanguages,
+		maskBlockPermissions,
+	}
+
+	var plan fingerprintPlan
+	switch mode {
+	case stealth.ModeOff:
+		return fingerprintPlan{}
+	case stealth.ModeBasic:
+		plan = fingerprintPlan{Blocks: basic, UserAgent: true}
+	default:
+		all := append(basic,
+			maskBlockCanvas,
+			maskBlockWebGL,
+			maskBlockScreen,
+			maskBlockBattery,
+			maskBlockConnection,
+		)
+		plan = fingerprintPlan{Blocks: all, UserAgent: true, Viewport: true}
+	}
+
+	if headless {
+		plan.Blocks = append(plan.Blocks, maskBlockHeadless)
+		plan.UserAgent = true
+	}
+	return plan
+}
+
+// ApplyPageFingerprint applies fingerprint masking to a specific page.
+// The amount of masking depends on the active stealth mode.
 func ApplyPageFingerprint(page *rod.Page) error {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	mode := stealth.ActiveMode()
+	plan := planFingerprint(mode, headlessActive)
+	if len(plan.Blocks) == 0 && !plan.UserAgent && !plan.Viewport {
+		logger.Info("Stealth mode is off, skipping fingerprint masking")
+		return nil
+	}
+
+	r := utils.SessionRand()
 
 	// We construct a single large IIFE (Immediately Invoked Function Expression)
 	// to ensure variables like 'const' don't leak or conflict, and comments don't break structure.
@@ -197,42 +271,107 @@ func ApplyPageFingerprint(page *rod.Page) error {
 		} catch (e) {}
 	`
 
-	// Combine all masking scripts inside an IIFE to isolate scope
-	fullScript := fmt.Sprintf(`
+	// 11. Hide headless Chrome tells
+	maskHeadless := `
+		try {
+			// Headless Chrome has no window.chrome at all
+			if (!window.chrome) {
+				window.chrome = {
+					runtime: {},
+					loadTimes: function() {},
+					csi: function() {},
+					app: {}
+				};
+			}
+
+			// Drop the HeadlessChrome token wherever the user agent is exposed
+			const ua = navigator.userAgent.replace('HeadlessChrome', 'Chrome');
+			Object.defineProperty(navigator, 'userAgent', { get: () => ua });
+			Object.defineProperty(navigator, 'appVersion', { get: () => ua.replace(/^Mozilla\//, '') });
+			if (navigator.userAgentData && navigator.userAgentData.brands) {
+				const brands = navigator.userAgentData.brands.map(b => ({
+					brand: b.brand.replace('HeadlessChrome', 'Google Chrome'),
+					version: b.version
+				}));
+				Object.defineProperty(navigator.userAgentData, 'brands', { get: () => brands });
+			}
+
+			// Headless reports no MIME types and a zero-size outer window
+			if (navigator.mimeTypes.length === 0) {
+				Object.defineProperty(navigator, 'mimeTypes', {
+					get: () => [{ type: 'application/pdf', suffixes: 'pdf', description: 'Portable Document Format' }]
+				});
+			}
+			if (window.outerWidth === 0 || window.outerHeight === 0) {
+				Object.defineProperty(window, 'outerWidth', { get: () => window.innerWidth });
+				Object.defineProperty(window, 'outerHeight', { get: () => window.innerHeight + 85 });
+			}
+
+			// Headless denies notifications outright; a real profile starts at 'default'
+			if (window.Notification && Notification.permission === 'denied') {
+				Object.defineProperty(Notification, 'permission', { get: () => 'default' });
+			}
+		} catch (e) {}
+	`
+
+	scripts := map[string]string{
+		maskBlockWebDriver:   maskWebDriver,
+		maskBlockAutomation:  maskAutomation,
+		maskBlockPlugins:     maskPlugins,
+		maskBlockLanguages:   maskLanguages,
+		maskBlockPermissions: maskPermissions,
+		maskBlockCanvas:      maskCanvas,
+		maskBlockWebGL:       maskWebGL,
+		maskBlockScreen:      maskScreen,
+		maskBlockBattery:     maskBattery,
+		maskBlockConnection:  maskConnection,
+		maskBlockHeadless:    maskHeadless,
+	}
+
+	if len(plan.Blocks) > 0 {
+		// Combine the enabled masking scripts inside an IIFE to isolate scope
+		var body strings.Builder
+		for _, name := range plan.Blocks {
+			body.WriteString(scripts[name])
+		}
+		fullScript := fmt.Sprintf(`
 		(function() {
 			%s
-			%s
-			%s
-			%s
-			%s
-			%s
-			%s
-			%s
-			%s
-			%s
 		})();
-	`, maskWebDriver, maskAutomation, maskPlugins, maskLanguages,
-		maskPermissions, maskCanvas, maskWebGL, maskScreen, maskBattery, maskConnection)
+	`, body.String())
 
-	// Execute the masking script
-	_, err := page.Eval(fullScript)
-	if err != nil {
-		return fmt.Errorf("failed to apply fingerprint masking: %w", err)
+		// Register the script for every document the page loads, so the masks
+		// are in place before the site's own scripts run after navigation
+		if _, err := page.EvalOnNewDocument(fullScript); err != nil {
+			return fmt.Errorf("failed to register fingerprint masking: %w", err)
+		}
+
+		// Also mask the document that is already loaded
+		if _, err := page.Eval(fullScript); err != nil {
+			return fmt.Errorf("failed to apply fingerprint masking: %w", err)
+		}
 	}
 
 	// Set custom user agent
-	err = page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
-		UserAgent: utils.ChromeUserAgent,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set user agent: %w", err)
+	if plan.UserAgent {
+		err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
+			UserAgent: utils.ChromeUserAgent,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set user agent: %w", err)
+		}
+	}
+
+	if !plan.Viewport {
+		logger.Info(fmt.Sprintf("Fingerprint applied (%s mode): %d masking blocks", mode, len(plan.Blocks)))
+		return nil
 	}
 
 	// Randomize viewport size
 	viewportWidth := 1366 + r.Intn(500) // 1366-1866
 	viewportHeight := 768 + r.Intn(300) // 768-1068
 
-	err = page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+	err := page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
 		Width:             viewportWidth,
 		Height:            viewportHeight,
 		DeviceScaleFactor: 1,
@@ -242,8 +381,8 @@ func ApplyPageFingerprint(page *rod.Page) error {
 		return fmt.Errorf("failed to set viewport: %w", err)
 	}
 
-	logger.Info(fmt.Sprintf("Fingerprint applied: viewport %dx%d, screen %dx%d",
-		viewportWidth, viewportHeight, screenWidth, screenHeight))
+	logger.Info(fmt.Sprintf("Fingerprint applied (%s mode): viewport %dx%d, screen %dx%d",
+		mode, viewportWidth, viewportHeight, screenWidth, screenHeight))
 
 	return nil
 }
@@ -0,0 +1,113 @@
+package browser
+
+import (
+	"testing"
+
+	"linkedin-automation/internal/stealth"
+)
+
+func TestPlanFingerprint(t *testing.T) {
+	tests := []struct {
+		mode      stealth.Mode
+		blocks    int
+		userAgent bool
+		viewport  bool
+	}{
+		{stealth.ModeOff, 0, false, false},
+		{stealth.ModeBasic, 5, true, false},
+		{stealth.ModeAdvanced, 10, true, true},
+		{stealth.ModeMaximum, 10, true, true},
+	}
+
+	for _, test := range tests {
+		plan := planFingerprint(test.mode, false)
+		if len(plan.Blocks) != test.blocks {
+			t.Errorf("%s: expected %d masking blocks, got %d (%v)", test.mode, test.blocks, len(plan.Blocks), plan.Blocks)
+		}
+		if plan.UserAgent != test.userAgent {
+			t.Errorf("%s: user agent override = %v, expected %v", test.mode, plan.UserAgent, test.userAgent)
+		}
+		if plan.Viewport != test.viewport {
+			t.Errorf("%s: viewport randomization = %v, expected %v", test.mode, plan.Viewport, test.viewport)
+		}
+	}
+}
+
+func TestPlanFingerprintBasicBlocks(t *testing.T) {
+	plan := planFingerprint(stealth.ModeBasic, false)
+
+	active := make(map[string]bool)
+	for _, name := range plan.Blocks {
+		active[name] = true
+	}
+
+	for _, name := range []string{maskBlockWebDriver, maskBlockAutomation} {
+		if !active[name] {
+			t.Errorf("Basic mode should mask %s", name)
+		}
+	}
+	for _, name := range []string{maskBlockCanvas, maskBlockWebGL, maskBlockBattery} {
+		if active[name] {
+			t.Errorf("Basic mode should not mask %s", name)
+		}
+	}
+}
+
+func TestPlanFingerprintMaximumIncludesEveryBlock(t *testing.T) {
+	plan := planFingerprint(stealth.ModeMaximum, false)
+
+	all := []string{
+		maskBlockWebDriver, maskBlockAutomation, maskBlockPlugins, maskBlockLanguages,
+		maskBlockPermissions, maskBlockCanvas, maskBlockWebGL, maskBlockScreen,
+		maskBlockBattery, maskBlockConnection,
+	}
+	active := make(map[string]bool)
+	for _, name := range plan.Blocks {
+		active[name] = true
+	}
+	for _, name := range all {
+		if !active[name] {
+			t.Errorf("Maximum mode is missing masking block %s", name)
+		}
+	}
+}
+
+func TestPlanFingerprintHeadless(t *testing.T) {
+	for _, mode := range []stealth.Mode{stealth.ModeBasic, stealth.ModeAdvanced, stealth.ModeMaximum} {
+		headed := planFingerprint(mode, false)
+		headless := planFingerprint(mode, true)
+
+		if !containsBlock(headless.Blocks, maskBlockHeadless) {
+			t.Errorf("%s: headless plan is missing the %s block: %v", mode, maskBlockHeadless, headless.Blocks)
+		}
+		if containsBlock(headed.Blocks, maskBlockHeadless) {
+			t.Errorf("%s: headed plan should not include the %s block", mode, maskBlockHeadless)
+		}
+		if !headless.UserAgent {
+			t.Errorf("%s: headless plan must override the HeadlessChrome user agent", mode)
+		}
+
+		// Headless keeps the plugins and window.chrome patches of the mode
+		for _, name := range []string{maskBlockPlugins, maskBlockAutomation} {
+			if !containsBlock(headless.Blocks, name) {
+				t.Errorf("%s: headless plan is missing the %s block", mode, name)
+			}
+		}
+		if len(headless.Blocks) != len(headed.Blocks)+1 {
+			t.Errorf("%s: headless plan should only add the headless block, got %v", mode, headless.Blocks)
+		}
+	}
+
+	if plan := planFingerprint(stealth.ModeOff, true); len(plan.Blocks) != 0 || plan.UserAgent || plan.Viewport {
+		t.Errorf("Stealth mode off should apply nothing even when headless, got %+v", plan)
+	}
+}
+
+func containsBlock(blocks []string, name string) bool {
+	for _, block := range blocks {
+		if block == name {
+			return true
+		}
+	}
+	return false
+}
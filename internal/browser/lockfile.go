@@ -0,0 +1,121 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+)
+
+// profileLockName is the lockfile created inside the user data directory
+const profileLockName = ".lock"
+
+// profileLocks maps each launched browser to the lockfile it holds
+var (
+	profileLocksMu sync.Mutex
+	profileLocks   = make(map[*rod.Browser]string)
+)
+
+// acquireProfileLock creates <userDataDir>/.lock containing the current PID.
+// A lock left behind by a dead process is reclaimed; a lock held by a live
+// process is refused so two runs never share the same Chrome profile.
+func acquireProfileLock(userDataDir string) (string, error) {
+	lockPath := filepath.Join(userDataDir, profileLockName)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, writeErr := f.WriteString(strconv.Itoa(os.Getpid()))
+			closeErr := f.Close()
+			if writeErr != nil || closeErr != nil {
+				os.Remove(lockPath)
+				return "", fmt.Errorf("failed to write profile lock: %w", errors.Join(writeErr, closeErr))
+			}
+			return lockPath, nil
+		}
+		if !os.IsExist(err) {
+			return "", fmt.Errorf("failed to create profile lock: %w", err)
+		}
+
+		pid, readErr := readLockPID(lockPath)
+		if readErr == nil && processAlive(pid) {
+			return "", fmt.Errorf("browser profile already in use: %s is locked by running process %d", userDataDir, pid)
+		}
+
+		// Stale or unreadable lock - remove it and try again
+		logger.Warning(fmt.Sprintf("Removing stale browser profile lock %s", lockPath))
+		if err := os.Remove(lockPath); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to remove stale profile lock: %w", err)
+		}
+	}
+
+	return "", fmt.Errorf("browser profile already in use: could not acquire %s", lockPath)
+}
+
+// releaseProfileLock removes the lockfile if it still belongs to this process
+func releaseProfileLock(lockPath string) error {
+	pid, err := readLockPID(lockPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if pid != os.Getpid() {
+		return nil
+	}
+	if err := os.Remove(lockPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove profile lock: %w", err)
+	}
+	return nil
+}
+
+// ProfileInUse reports whether a live process other than this one holds the
+// profile lock of userDataDir, and that process's PID
+func ProfileInUse(userDataDir string) (int, bool) {
+	pid, err := readLockPID(filepath.Join(userDataDir, profileLockName))
+	if err != nil || pid == os.Getpid() || !processAlive(pid) {
+		return 0, false
+	}
+	return pid, true
+}
+
+// readLockPID reads the PID stored in a lockfile
+func readLockPID(lockPath string) (int, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid profile lock contents: %w", err)
+	}
+	return pid, nil
+}
+
+// CloseBrowser closes the browser and releases its profile lock
+func CloseBrowser(br *rod.Browser) error {
+	closeErr := br.Close()
+
+	profileLocksMu.Lock()
+	lockPath, ok := profileLocks[br]
+	delete(profileLocks, br)
+	profileLocksMu.Unlock()
+
+	forgetConnection(br)
+
+	if ok {
+		if err := releaseProfileLock(lockPath); err != nil {
+			logger.Warning("Failed to release browser profile lock: " + err.Error())
+		}
+	}
+
+	return closeErr
+}
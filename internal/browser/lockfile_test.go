@@ -0,0 +1,112 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// deadPID is above the Linux pid_max ceiling, so no process can have it
+const deadPID = 99999999
+
+func TestAcquireProfileLock(t *testing.T) {
+	dir := t.TempDir()
+
+	lockPath, err := acquireProfileLock(dir)
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+	if lockPath != filepath.Join(dir, profileLockName) {
+		t.Errorf("Unexpected lock path %s", lockPath)
+	}
+
+	pid, err := readLockPID(lockPath)
+	if err != nil {
+		t.Fatalf("Failed to read lock: %v", err)
+	}
+	if pid != os.Getpid() {
+		t.Errorf("Expected lock to contain pid %d, got %d", os.Getpid(), pid)
+	}
+
+	if err := releaseProfileLock(lockPath); err != nil {
+		t.Fatalf("Failed to release lock: %v", err)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Error("Expected lock file to be removed on release")
+	}
+}
+
+func TestAcquireProfileLockReclaimsStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, profileLockName)
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(deadPID)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := acquireProfileLock(dir); err != nil {
+		t.Fatalf("Expected stale lock to be reclaimed, got: %v", err)
+	}
+
+	pid, _ := readLockPID(lockPath)
+	if pid != os.Getpid() {
+		t.Errorf("Expected reclaimed lock to contain pid %d, got %d", os.Getpid(), pid)
+	}
+}
+
+func TestAcquireProfileLockReclaimsCorruptLock(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, profileLockName)
+	if err := os.WriteFile(lockPath, []byte("not-a-pid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := acquireProfileLock(dir); err != nil {
+		t.Fatalf("Expected corrupt lock to be reclaimed, got: %v", err)
+	}
+}
+
+func TestAcquireProfileLockRefusesLiveLock(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := acquireProfileLock(dir); err != nil {
+		t.Fatalf("Failed to acquire first lock: %v", err)
+	}
+
+	// The current process is alive, so a second acquire must fail
+	_, err := acquireProfileLock(dir)
+	if err == nil {
+		t.Fatal("Expected second acquire to fail while the lock is held")
+	}
+	if !strings.Contains(err.Error(), "browser profile already in use") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestReleaseProfileLockKeepsForeignLock(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, profileLockName)
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(deadPID)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := releaseProfileLock(lockPath); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Error("Release should not remove a lock owned by another process")
+	}
+}
+
+func TestProcessAlive(t *testing.T) {
+	if !processAlive(os.Getpid()) {
+		t.Error("Expected current process to be alive")
+	}
+	if processAlive(deadPID) {
+		t.Error("Expected unused pid to be reported dead")
+	}
+	if processAlive(0) {
+		t.Error("Expected pid 0 to be reported dead")
+	}
+}
@@ -0,0 +1,81 @@
+package browser
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/pkg/utils"
+)
+
+// navGapJitter is the most a navigation gap is stretched beyond the minimum,
+// as a fraction of it, so gaps don't cluster at one value
+const navGapJitter = 0.5
+
+// GetMinInterPageSeconds reads MIN_INTER_PAGE_SECONDS, the least time between
+// two top-level navigations (default 3, 0 = no minimum)
+func GetMinInterPageSeconds() int {
+	if v := os.Getenv("MIN_INTER_PAGE_SECONDS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val >= 0 {
+			return val
+		}
+	}
+	return 3
+}
+
+// NavGap keeps consecutive top-level navigations at least MinGap (plus
+// jitter) apart. Opening profile after profile in under a second is a bot
+// signature; a person reads the page first.
+type NavGap struct {
+	MinGap time.Duration
+
+	mu    sync.Mutex
+	last  time.Time // Start of the previous navigation (zero before the first)
+	now   func() time.Time
+	sleep func(time.Duration)
+	r     *rand.Rand
+}
+
+// NewNavGap returns a NavGap on the wall clock
+func NewNavGap(minGap time.Duration) *NavGap {
+	return &NavGap{MinGap: minGap, now: time.Now, sleep: time.Sleep, r: utils.SessionRand()}
+}
+
+// Wait sleeps until the gap since the previous navigation is long enough,
+// then records the navigation about to start. Returns how long it slept.
+func (g *NavGap) Wait() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var wait time.Duration
+	if g.MinGap > 0 && !g.last.IsZero() {
+		gap := g.MinGap + time.Duration(g.r.Float64()*navGapJitter*float64(g.MinGap))
+		if elapsed := g.now().Sub(g.last); elapsed < gap {
+			wait = gap - elapsed
+			logger.Info(fmt.Sprintf("Last page opened %s ago, waiting %s before the next one",
+				elapsed.Round(100*time.Millisecond), wait.Round(100*time.Millisecond)))
+			g.sleep(wait)
+		}
+	}
+
+	g.last = g.now()
+	return wait
+}
+
+var (
+	navGapOnce sync.Once
+	navGap     *NavGap
+)
+
+// EnforceNavGap is called before every top-level navigation. It waits until
+// at least MIN_INTER_PAGE_SECONDS (with jitter) have passed since the last one.
+func EnforceNavGap() {
+	navGapOnce.Do(func() {
+		navGap = NewNavGap(time.Duration(GetMinInterPageSeconds()) * time.Second)
+	})
+	navGap.Wait()
+}
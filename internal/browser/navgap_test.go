@@ -0,0 +1,90 @@
+package browser
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// testNavGap returns a NavGap on a fake clock that only moves when the gap sleeps or the test advances it
+func testNavGap(minGap time.Duration, now *time.Time, slept *[]time.Duration) *NavGap {
+	return &NavGap{
+		MinGap: minGap,
+		now:    func() time.Time { return *now },
+		sleep: func(d time.Duration) {
+			*slept = append(*slept, d)
+			*now = now.Add(d)
+		},
+		r: rand.New(rand.NewSource(1)),
+	}
+}
+
+func TestNavGapWaitsAfterFastNavigation(t *testing.T) {
+	now := time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	gap := testNavGap(3*time.Second, &now, &slept)
+
+	if wait := gap.Wait(); wait != 0 {
+		t.Errorf("Expected no wait before the first navigation, got %s", wait)
+	}
+
+	// Profile to profile in 400ms
+	for i := 0; i < 5; i++ {
+		start := now
+		now = now.Add(400 * time.Millisecond)
+		wait := gap.Wait()
+
+		elapsed := now.Sub(start)
+		if wait <= 0 {
+			t.Fatalf("Navigation %d: expected a wait after 400ms", i+1)
+		}
+		if elapsed < 3*time.Second || elapsed > 4500*time.Millisecond {
+			t.Errorf("Navigation %d: expected a gap between 3s and 4.5s, got %s", i+1, elapsed)
+		}
+	}
+	if len(slept) != 5 {
+		t.Errorf("Expected 5 sleeps, got %d", len(slept))
+	}
+}
+
+func TestNavGapNoWaitWhenSlow(t *testing.T) {
+	now := time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	gap := testNavGap(3*time.Second, &now, &slept)
+
+	for i := 0; i < 5; i++ {
+		gap.Wait()
+		now = now.Add(8 * time.Second)
+	}
+	if len(slept) != 0 {
+		t.Errorf("Expected no waits between pages 8s apart, got %v", slept)
+	}
+
+	// No minimum configured
+	off := testNavGap(0, &now, &slept)
+	off.Wait()
+	off.Wait()
+	if len(slept) != 0 {
+		t.Errorf("Expected no waits with the minimum off, got %v", slept)
+	}
+}
+
+func TestGetMinInterPageSeconds(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+	}{
+		{"", 3},
+		{"0", 0},
+		{"7", 7},
+		{"-2", 3},
+		{"abc", 3},
+	}
+
+	for _, test := range tests {
+		t.Setenv("MIN_INTER_PAGE_SECONDS", test.value)
+		if got := GetMinInterPageSeconds(); got != test.expected {
+			t.Errorf("MIN_INTER_PAGE_SECONDS=%q: expected %d, got %d", test.value, test.expected, got)
+		}
+	}
+}
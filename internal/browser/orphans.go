@@ -0,0 +1,204 @@
+package browser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"linkedin-automation/internal/logger"
+)
+
+// userDataDirFlag is the Chrome flag naming the profile directory
+const userDataDirFlag = "--user-data-dir="
+
+// processInfo is one running process as reported by the OS
+type processInfo struct {
+	PID         int
+	CommandLine string // Executable followed by its arguments, space separated
+}
+
+// CleanupOrphans kills Chrome processes left running against userDataDir by a
+// crashed run. Leakless is disabled, so Chrome outlives a run that dies before
+// CloseBrowser. Nothing is killed while another live run holds the profile
+// lock; processAlive checks the lock holder per platform.
+func CleanupOrphans(userDataDir string) error {
+	killed, err := cleanupOrphans(userDataDir, listProcesses, killProcess)
+	if err != nil {
+		return err
+	}
+	if killed == 0 {
+		logger.Info("No orphaned Chrome processes found for " + userDataDir)
+	}
+	return nil
+}
+
+// cleanupOrphans is CleanupOrphans with an injectable process lister and killer.
+// Returns how many processes were killed.
+func cleanupOrphans(userDataDir string, list func() ([]processInfo, error), kill func(pid int) error) (int, error) {
+	if pid, ok := ProfileInUse(userDataDir); ok {
+		logger.Info(fmt.Sprintf("Browser profile %s is in use by running process %d, not cleaning up", userDataDir, pid))
+		return 0, nil
+	}
+
+	processes, err := list()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	killed := 0
+	for _, proc := range findOrphans(processes, userDataDir, os.Getpid()) {
+		if err := kill(proc.PID); err != nil {
+			logger.Warning(fmt.Sprintf("Failed to kill orphaned Chrome process %d: %s", proc.PID, err.Error()))
+			continue
+		}
+		logger.Info(fmt.Sprintf("Killed orphaned Chrome process %d: %s", proc.PID, truncateCommand(proc.CommandLine)))
+		killed++
+	}
+	return killed, nil
+}
+
+// findOrphans returns the Chrome processes whose --user-data-dir is userDataDir,
+// excluding self
+func findOrphans(processes []processInfo, userDataDir string, self int) []processInfo {
+	foldCase := runtime.GOOS == "windows"
+
+	var orphans []processInfo
+	for _, proc := range processes {
+		if proc.PID == self || proc.PID <= 0 || !isChromeCommand(proc.CommandLine) {
+			continue
+		}
+		dir, ok := userDataDirArg(proc.CommandLine)
+		if ok && samePath(dir, userDataDir, foldCase) {
+			orphans = append(orphans, proc)
+		}
+	}
+	return orphans
+}
+
+// isChromeCommand reports whether the executable of a command line is Chrome or Chromium
+func isChromeCommand(commandLine string) bool {
+	exe := commandLine
+	if i := strings.Index(exe, " --"); i != -1 {
+		exe = exe[:i]
+	}
+	name := strings.ToLower(filepath.Base(strings.Trim(exe, `"`)))
+	return strings.Contains(name, "chrome") || strings.Contains(name, "chromium")
+}
+
+// userDataDirArg extracts the --user-data-dir value from a command line.
+// The value runs to the next " --" so paths containing spaces are kept whole.
+func userDataDirArg(commandLine string) (string, bool) {
+	i := strings.Index(commandLine, userDataDirFlag)
+	if i == -1 {
+		return "", false
+	}
+	value := commandLine[i+len(userDataDirFlag):]
+	if end := strings.Index(value, " --"); end != -1 {
+		value = value[:end]
+	}
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	return value, value != ""
+}
+
+// cleanPath makes a path comparable: absolute and cleaned
+func cleanPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.Clean(path)
+}
+
+// samePath reports whether two paths name the same directory. Windows paths
+// are case-insensitive, so foldCase compares them that way.
+func samePath(a, b string, foldCase bool) bool {
+	a, b = cleanPath(a), cleanPath(b)
+	if foldCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// truncateCommand shortens a command line for logging
+func truncateCommand(commandLine string) string {
+	const maxLen = 120
+	if len(commandLine) <= maxLen {
+		return commandLine
+	}
+	return commandLine[:maxLen] + "..."
+}
+
+// killProcess terminates a process by PID
+func killProcess(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Kill()
+}
+
+// listProcesses returns the running processes with their command lines:
+// from /proc on Linux, ps on other Unix systems and CIM on Windows
+func listProcesses() ([]processInfo, error) {
+	if runtime.GOOS == "linux" {
+		return listProcFS("/proc")
+	}
+	if runtime.GOOS == "windows" {
+		out, err := exec.Command("powershell", "-NoProfile", "-Command",
+			`Get-CimInstance Win32_Process | ForEach-Object { "$($_.ProcessId) $($_.CommandLine)" }`).Output()
+		if err != nil {
+			return nil, err
+		}
+		return parsePSOutput(out), nil
+	}
+
+	out, err := exec.Command("ps", "-axww", "-o", "pid=", "-o", "command=").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parsePSOutput(out), nil
+}
+
+// listProcFS reads every /proc/<pid>/cmdline below root
+func listProcFS(root string) ([]processInfo, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []processInfo
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		cmdline, err := os.ReadFile(filepath.Join(root, entry.Name(), "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			continue // Exited, or a kernel thread
+		}
+		args := strings.TrimRight(string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '})), " ")
+		processes = append(processes, processInfo{PID: pid, CommandLine: args})
+	}
+	return processes, nil
+}
+
+// parsePSOutput parses "<pid> <command line>" lines
+func parsePSOutput(out []byte) []processInfo {
+	var processes []processInfo
+	for _, line := range strings.Split(string(out), "\n") {
+		pidField, commandLine, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(pidField)
+		if err != nil {
+			continue
+		}
+		processes = append(processes, processInfo{PID: pid, CommandLine: strings.TrimSpace(commandLine)})
+	}
+	return processes
+}
@@ -0,0 +1,177 @@
+package browser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestFindOrphans(t *testing.T) {
+	dataDir := t.TempDir()
+	otherDir := t.TempDir()
+	self := os.Getpid()
+
+	processes := []processInfo{
+		{PID: 101, CommandLine: "/usr/bin/google-chrome --user-data-dir=" + dataDir + " --headless"},
+		{PID: 102, CommandLine: "/root/.cache/rod/browser/chromium-1234/chrome --type=renderer --user-data-dir=" + dataDir},
+		{PID: 103, CommandLine: "/usr/bin/chromium --user-data-dir=" + otherDir},
+		{PID: 104, CommandLine: "/usr/bin/vim " + dataDir + "/notes --user-data-dir=" + dataDir},
+		{PID: 105, CommandLine: "/usr/bin/google-chrome --no-first-run"},
+		{PID: self, CommandLine: "/usr/bin/chrome --user-data-dir=" + dataDir},
+		{PID: 106, CommandLine: `"C:\Program Files\Google\Chrome\Application\chrome.exe" --user-data-dir="` + dataDir + `" --no-sandbox`},
+	}
+
+	var pids []int
+	for _, proc := range findOrphans(processes, dataDir, self) {
+		pids = append(pids, proc.PID)
+	}
+
+	if want := []int{101, 102, 106}; !reflect.DeepEqual(pids, want) {
+		t.Errorf("findOrphans() = %v, expected %v", pids, want)
+	}
+}
+
+func TestFindOrphansRelativeDataDir(t *testing.T) {
+	abs, err := filepath.Abs("./browser_data")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processes := []processInfo{{PID: 200, CommandLine: "/usr/bin/chrome --user-data-dir=" + abs + " --mute-audio"}}
+	if orphans := findOrphans(processes, "./browser_data", os.Getpid()); len(orphans) != 1 {
+		t.Errorf("Expected a relative data dir to match its absolute form, got %v", orphans)
+	}
+}
+
+func TestSamePath(t *testing.T) {
+	if !samePath("./browser_data", "browser_data/", false) {
+		t.Error("Expected relative and trailing-slash forms of a path to match")
+	}
+	if samePath("/tmp/Browser_Data", "/tmp/browser_data", false) {
+		t.Error("Expected case to matter when not folding")
+	}
+	if !samePath("/tmp/Browser_Data", "/tmp/browser_data", true) {
+		t.Error("Expected case-insensitive match when folding (Windows)")
+	}
+}
+
+func TestUserDataDirArg(t *testing.T) {
+	tests := []struct {
+		commandLine string
+		expected    string
+		ok          bool
+	}{
+		{"chrome --user-data-dir=/data/profile --headless", "/data/profile", true},
+		{"chrome --user-data-dir=/home/me/My Profile --headless", "/home/me/My Profile", true},
+		{`chrome.exe --user-data-dir="C:\Users\me\data"`, `C:\Users\me\data`, true},
+		{"chrome --headless", "", false},
+		{"chrome --user-data-dir=", "", false},
+	}
+
+	for _, test := range tests {
+		dir, ok := userDataDirArg(test.commandLine)
+		if dir != test.expected || ok != test.ok {
+			t.Errorf("userDataDirArg(%q) = %q, %v; expected %q, %v", test.commandLine, dir, ok, test.expected, test.ok)
+		}
+	}
+}
+
+func TestCleanupOrphans(t *testing.T) {
+	dataDir := t.TempDir()
+	list := func() ([]processInfo, error) {
+		return []processInfo{
+			{PID: 301, CommandLine: "/usr/bin/chrome --user-data-dir=" + dataDir},
+			{PID: 302, CommandLine: "/usr/bin/chrome --type=gpu-process --user-data-dir=" + dataDir},
+			{PID: 303, CommandLine: "/usr/bin/chrome --user-data-dir=/somewhere/else"},
+		}, nil
+	}
+
+	var killed []int
+	kill := func(pid int) error {
+		if pid == 302 {
+			return errors.New("operation not permitted")
+		}
+		killed = append(killed, pid)
+		return nil
+	}
+
+	count, err := cleanupOrphans(dataDir, list, kill)
+	if err != nil {
+		t.Fatalf("cleanupOrphans() error = %v", err)
+	}
+	if count != 1 || !reflect.DeepEqual(killed, []int{301}) {
+		t.Errorf("Expected only 301 killed (302 failed), got %d %v", count, killed)
+	}
+
+	// Listing failures are reported
+	failing := func() ([]processInfo, error) { return nil, errors.New("ps not found") }
+	if _, err := cleanupOrphans(dataDir, failing, kill); err == nil {
+		t.Error("Expected an error when processes cannot be listed")
+	}
+}
+
+func TestCleanupOrphansSkipsLiveLock(t *testing.T) {
+	dataDir := t.TempDir()
+
+	// The parent (go test) is alive and is not this process
+	lock := filepath.Join(dataDir, profileLockName)
+	if err := os.WriteFile(lock, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+		t.Fatalf("Failed to write lock: %v", err)
+	}
+
+	listed := false
+	list := func() ([]processInfo, error) {
+		listed = true
+		return []processInfo{{PID: 401, CommandLine: "/usr/bin/chrome --user-data-dir=" + dataDir}}, nil
+	}
+	kill := func(pid int) error {
+		t.Errorf("Killed %d while another run holds the profile", pid)
+		return nil
+	}
+
+	if count, err := cleanupOrphans(dataDir, list, kill); err != nil || count != 0 {
+		t.Errorf("Expected nothing cleaned, got %d (err %v)", count, err)
+	}
+	if listed {
+		t.Error("Processes should not be listed while the profile is locked by a live run")
+	}
+}
+
+func TestParsePSOutput(t *testing.T) {
+	out := []byte("  1 /sbin/init\n 4242 /usr/bin/chrome --user-data-dir=/data x\n\nbogus line\n")
+	expected := []processInfo{
+		{PID: 1, CommandLine: "/sbin/init"},
+		{PID: 4242, CommandLine: "/usr/bin/chrome --user-data-dir=/data x"},
+	}
+	if got := parsePSOutput(out); !reflect.DeepEqual(got, expected) {
+		t.Errorf("parsePSOutput() = %v, expected %v", got, expected)
+	}
+}
+
+func TestListProcFS(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, cmdline string) {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("12", "/usr/bin/chrome\x00--user-data-dir=/data\x00")
+	write("13", "") // kernel thread
+	write("self", "ignored")
+
+	processes, err := listProcFS(root)
+	if err != nil {
+		t.Fatalf("listProcFS() error = %v", err)
+	}
+	expected := []processInfo{{PID: 12, CommandLine: "/usr/bin/chrome --user-data-dir=/data"}}
+	if !reflect.DeepEqual(processes, expected) {
+		t.Errorf("listProcFS() = %v, expected %v", processes, expected)
+	}
+}
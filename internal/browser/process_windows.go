@@ -0,0 +1,36 @@
+//go:build windows
+
+package browser
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	// processQueryLimitedInformation is the least access that allows GetExitCodeProcess
+	processQueryLimitedInformation = 0x1000
+	// stillActive is the exit code GetExitCodeProcess reports for a running process
+	stillActive = 259
+)
+
+// processAlive reports whether a process with the given PID is running.
+// os.FindProcess and Signal can't tell on Windows, so this opens the process
+// and asks for its exit code.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		// Access denied means the process exists but belongs to another user
+		return errors.Is(err, syscall.ERROR_ACCESS_DENIED)
+	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
@@ -0,0 +1,208 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+
+	"linkedin-automation/internal/logger"
+)
+
+// maxReconnectAttempts is how many times EnsureConnected tries to reconnect before giving up
+const maxReconnectAttempts = 3
+
+// reconnectBackoff is the wait after the first failed attempt; it doubles after each one
+const reconnectBackoff = 2 * time.Second
+
+// reopenURL is loaded when the page's tab did not survive the disconnect
+const reopenURL = "https://www.linkedin.com/feed/"
+
+// browserConn is how a launched browser is reached: the DevTools websocket URL,
+// so a dropped connection can be re-established to the same Chrome, and the
+// websocket in use, so a replaced one can be closed
+type browserConn struct {
+	url string
+	ws  *cdp.WebSocket
+}
+
+// connections maps each launched browser to its connection
+var (
+	connectionsMu sync.Mutex
+	connections   = make(map[*rod.Browser]*browserConn)
+)
+
+// disconnectMessages are error texts of a closed or reset websocket that do not
+// always wrap a sentinel error (notably on Windows)
+var disconnectMessages = []string{
+	"use of closed network connection",
+	"connection reset",
+	"broken pipe",
+	"websocket: close",
+}
+
+// IsDisconnectError reports whether err means the CDP connection is gone: the
+// websocket was closed or reset, or the page's session no longer exists.
+// Timeouts and ordinary protocol errors are not disconnects.
+func IsDisconnectError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, target := range []error{io.EOF, io.ErrUnexpectedEOF, net.ErrClosed, syscall.ECONNRESET, syscall.ECONNREFUSED, syscall.EPIPE, cdp.ErrSessionNotFound} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, text := range disconnectMessages {
+		if strings.Contains(message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureConnected checks that the browser and page still respond. If the CDP
+// connection has dropped it reconnects to the same Chrome (up to
+// maxReconnectAttempts times), reattaches to the page's tab or opens a new one,
+// and re-applies fingerprint masking. Use the returned page in place of page.
+func EnsureConnected(br *rod.Browser, page *rod.Page) (*rod.Page, error) {
+	err := checkConnection(br, page)
+	if err == nil {
+		return page, nil
+	}
+	if !IsDisconnectError(err) {
+		return nil, fmt.Errorf("browser check failed: %w", err)
+	}
+
+	logger.Warning("Browser connection lost, reconnecting: " + err.Error())
+	attempts, err := retryReconnect(func() error { return reconnect(br) }, maxReconnectAttempts, reconnectBackoff, time.Sleep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconnect to the browser after %d attempts: %w", attempts, err)
+	}
+	logger.Info(fmt.Sprintf("Reconnected to the browser (attempt %d)", attempts))
+
+	return reopenPage(br, page)
+}
+
+// Disconnected reports whether the CDP connection to br or page has dropped,
+// e.g. partway through a phase
+func Disconnected(br *rod.Browser, page *rod.Page) bool {
+	return IsDisconnectError(checkConnection(br, page))
+}
+
+// checkConnection pings the browser and then the page's own session
+func checkConnection(br *rod.Browser, page *rod.Page) error {
+	if err := Ping(br); err != nil {
+		return err
+	}
+	if _, err := (proto.PageGetFrameTree{}).Call(page.Timeout(pingTimeout)); err != nil {
+		return fmt.Errorf("page not responding: %w", err)
+	}
+	return nil
+}
+
+// retryReconnect calls connect until it succeeds or attempts run out, sleeping
+// between attempts with a doubling backoff. It returns the attempts used.
+func retryReconnect(connect func() error, attempts int, backoff time.Duration, sleep func(time.Duration)) (int, error) {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = connect(); err == nil {
+			return attempt, nil
+		}
+		logger.Warning(fmt.Sprintf("Reconnect attempt %d/%d failed: %s", attempt, attempts, err.Error()))
+
+		if attempt < attempts {
+			sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return attempts, err
+}
+
+// reconnect opens a new CDP connection to br's Chrome and swaps it into br, so
+// existing references (profile lock, health checks, CloseBrowser) keep working.
+// The old websocket is closed so its reader doesn't outlive the swap.
+func reconnect(br *rod.Browser) error {
+	connectionsMu.Lock()
+	conn, ok := connections[br]
+	connectionsMu.Unlock()
+	if !ok {
+		return errors.New("browser control URL unknown (not started by StartBrowserWithConfig)")
+	}
+
+	fresh, ws, err := connectBrowser(conn.url)
+	if err != nil {
+		return err
+	}
+
+	connectionsMu.Lock()
+	old := conn.ws
+	conn.ws = ws
+	connectionsMu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+
+	*br = *fresh
+	return nil
+}
+
+// connectBrowser opens a CDP connection to the Chrome whose DevTools websocket
+// is at url, keeping the websocket so it can be closed later
+func connectBrowser(url string) (*rod.Browser, *cdp.WebSocket, error) {
+	ws := &cdp.WebSocket{}
+	if err := ws.Connect(context.Background(), url, nil); err != nil {
+		return nil, nil, err
+	}
+
+	br := rod.New().Client(cdp.New().Start(ws))
+	if err := br.Connect(); err != nil {
+		ws.Close()
+		return nil, nil, err
+	}
+	return br, ws, nil
+}
+
+// forgetConnection drops br's connection and closes its websocket
+func forgetConnection(br *rod.Browser) {
+	connectionsMu.Lock()
+	conn, ok := connections[br]
+	delete(connections, br)
+	connectionsMu.Unlock()
+
+	if ok && conn.ws != nil {
+		conn.ws.Close()
+	}
+}
+
+// reopenPage reattaches to old's tab if it is still open, otherwise opens a new
+// page, and re-applies fingerprint masking either way
+func reopenPage(br *rod.Browser, old *rod.Page) (*rod.Page, error) {
+	if old != nil {
+		if pages, err := br.Pages(); err == nil {
+			for _, page := range pages {
+				if page.TargetID == old.TargetID {
+					if err := ApplyPageFingerprint(page); err != nil {
+						logger.Warning("Failed to re-apply fingerprint after reconnect: " + err.Error())
+					}
+					return page, nil
+				}
+			}
+		}
+	}
+
+	logger.Warning("Previous tab did not survive the disconnect, opening a new one")
+	return OpenPage(br, reopenURL)
+}
@@ -0,0 +1,108 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+)
+
+func TestIsDisconnectError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"websocket EOF", fmt.Errorf("read: %w", io.EOF), true},
+		{"closed connection", &net.OpError{Op: "write", Net: "tcp", Err: net.ErrClosed}, true},
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+		{"browser gone", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"broken pipe text", errors.New("write tcp 127.0.0.1:5000: broken pipe"), true},
+		{"session gone", fmt.Errorf("call failed: %w", cdp.ErrSessionNotFound), true},
+		{"timeout", fmt.Errorf("browser not responding: %w", context.DeadlineExceeded), false},
+		{"missing element", errors.New("cannot find element"), false},
+		{"navigation context", cdp.ErrCtxNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDisconnectError(tt.err); got != tt.want {
+				t.Errorf("IsDisconnectError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryReconnectSucceedsAfterFailures(t *testing.T) {
+	calls := 0
+	var sleeps []time.Duration
+	attempts, err := retryReconnect(func() error {
+		calls++
+		if calls < 3 {
+			return io.EOF
+		}
+		return nil
+	}, 3, time.Second, func(d time.Duration) { sleeps = append(sleeps, d) })
+
+	if err != nil {
+		t.Fatalf("retryReconnect() error = %v", err)
+	}
+	if attempts != 3 || calls != 3 {
+		t.Errorf("Expected success on attempt 3, got attempts=%d calls=%d", attempts, calls)
+	}
+	if len(sleeps) != 2 || sleeps[0] != time.Second || sleeps[1] != 2*time.Second {
+		t.Errorf("Expected doubling backoff [1s 2s], got %v", sleeps)
+	}
+}
+
+func TestRetryReconnectGivesUp(t *testing.T) {
+	calls := 0
+	sleeps := 0
+	attempts, err := retryReconnect(func() error {
+		calls++
+		return syscall.ECONNREFUSED
+	}, maxReconnectAttempts, time.Second, func(time.Duration) { sleeps++ })
+
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Errorf("Expected the last connect error, got %v", err)
+	}
+	if attempts != maxReconnectAttempts || calls != maxReconnectAttempts {
+		t.Errorf("Expected %d attempts, got attempts=%d calls=%d", maxReconnectAttempts, attempts, calls)
+	}
+	if sleeps != maxReconnectAttempts-1 {
+		t.Errorf("Expected no sleep after the last attempt, got %d sleeps", sleeps)
+	}
+}
+
+func TestReconnectUnknownBrowser(t *testing.T) {
+	if err := reconnect(rod.New()); err == nil {
+		t.Error("Expected an error for a browser without a recorded control URL")
+	}
+}
+
+func TestReconnectFailureKeepsConnection(t *testing.T) {
+	br := rod.New()
+	connectionsMu.Lock()
+	connections[br] = &browserConn{url: "ws://127.0.0.1:1/devtools/browser/gone"}
+	connectionsMu.Unlock()
+	t.Cleanup(func() { forgetConnection(br) })
+
+	if err := reconnect(br); err == nil {
+		t.Fatal("Expected an error reconnecting to a browser that is gone")
+	}
+
+	connectionsMu.Lock()
+	_, ok := connections[br]
+	connectionsMu.Unlock()
+	if !ok {
+		t.Error("Expected the control URL to be kept for the next attempt")
+	}
+}
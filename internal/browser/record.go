@@ -0,0 +1,137 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+
+	"linkedin-automation/internal/logger"
+)
+
+// recordSettleDelay lets LinkedIn's scripts render a page before it is saved
+const recordSettleDelay = 3 * time.Second
+
+// ErrNoBrowser means no Chrome/Chromium binary was found to replay pages in
+var ErrNoBrowser = errors.New("chrome/chromium not found")
+
+// PageRecordDir returns the directory RECORD_PAGES=true saves navigated pages
+// into (RECORD_PAGES_DIR, default data/recorded_pages), or "" when recording is off.
+// The default stays out of testdata so raw pages, which hold real names, are
+// never picked up as fixtures by accident.
+func PageRecordDir() string {
+	if os.Getenv("RECORD_PAGES") != "true" {
+		return ""
+	}
+	if dir := os.Getenv("RECORD_PAGES_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join("data", "recorded_pages")
+}
+
+// RecordPages saves the HTML of every page the tab loads into dir, including
+// LinkedIn's in-app navigations, until the tab closes. The files can be loaded
+// back with ReplayPage to test parsers offline. They hold real profile data:
+// scrub names and URLs before committing one as a fixture.
+func RecordPages(page *rod.Page, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create page record directory: %w", err)
+	}
+
+	save := func() {
+		time.Sleep(recordSettleDelay)
+		if path, err := savePageHTML(page, dir); err != nil {
+			logger.Debug("Failed to record page: " + err.Error())
+		} else {
+			logger.Debug("Recorded page to " + path)
+		}
+	}
+
+	// Save outside the event loop, which must keep running for the page calls to return
+	go page.EachEvent(func(e *proto.PageLoadEventFired) {
+		go save()
+	}, func(e *proto.PageNavigatedWithinDocument) {
+		go save()
+	})()
+
+	logger.Info("Recording visited pages to " + dir)
+	return nil
+}
+
+// savePageHTML writes the page's current HTML into dir and returns the file path
+func savePageHTML(page *rod.Page, dir string) (string, error) {
+	info, err := page.Info()
+	if err != nil {
+		return "", fmt.Errorf("failed to read page URL: %w", err)
+	}
+
+	html, err := page.HTML()
+	if err != nil {
+		return "", fmt.Errorf("failed to read page HTML: %w", err)
+	}
+
+	path := filepath.Join(dir, recordFileName(info.URL, time.Now()))
+	if err := os.WriteFile(path, []byte(html), 0644); err != nil {
+		return "", fmt.Errorf("failed to write recorded page: %w", err)
+	}
+	return path, nil
+}
+
+// recordFileName names a recorded page after its URL path and the time, e.g.
+// search_results_people_20251210_090000.000.html
+func recordFileName(rawURL string, at time.Time) string {
+	name := "page"
+	if u, err := url.Parse(rawURL); err == nil {
+		if path := strings.Trim(u.Path, "/"); path != "" {
+			name = sanitizeFileName(path)
+		}
+	}
+	return fmt.Sprintf("%s_%s.html", name, at.Format("20060102_150405.000"))
+}
+
+// ReplayPage loads html into a blank page of a fresh headless browser, so
+// parsing and selector logic can run against recorded pages without LinkedIn.
+// Uses CHROME_BIN or a Chrome/Chromium found on the PATH and returns an error
+// wrapping ErrNoBrowser if there is none. The returned function closes the browser.
+func ReplayPage(html string) (*rod.Page, func(), error) {
+	bin := os.Getenv("CHROME_BIN")
+	if bin == "" {
+		found, ok := launcher.LookPath()
+		if !ok {
+			return nil, nil, ErrNoBrowser
+		}
+		bin = found
+	} else if _, err := os.Stat(bin); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNoBrowser, bin)
+	}
+
+	controlURL, err := launcher.New().Bin(bin).Headless(true).Launch()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to launch replay browser: %w", err)
+	}
+
+	br := rod.New().ControlURL(controlURL)
+	if err := br.Connect(); err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to replay browser: %w", err)
+	}
+	closeBrowser := func() { br.Close() }
+
+	page, err := br.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		closeBrowser()
+		return nil, nil, fmt.Errorf("failed to open replay page: %w", err)
+	}
+	if err := page.SetDocumentContent(html); err != nil {
+		closeBrowser()
+		return nil, nil, fmt.Errorf("failed to load replay HTML: %w", err)
+	}
+
+	return page, closeBrowser, nil
+}
@@ -0,0 +1,45 @@
+package browser
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordFileName(t *testing.T) {
+	at := time.Date(2025, 12, 10, 9, 4, 12, 317000000, time.UTC)
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://www.linkedin.com/search/results/people/?keywords=engineer", "search_results_people_20251210_090412.317.html"},
+		{"https://www.linkedin.com/in/jane-doe/", "in_jane-doe_20251210_090412.317.html"},
+		{"https://www.linkedin.com/", "page_20251210_090412.317.html"},
+		{"about:blank", "page_20251210_090412.317.html"},
+	}
+
+	for _, test := range tests {
+		if got := recordFileName(test.url, at); got != test.expected {
+			t.Errorf("recordFileName(%q): expected %s, got %s", test.url, test.expected, got)
+		}
+	}
+}
+
+func TestPageRecordDir(t *testing.T) {
+	t.Setenv("RECORD_PAGES", "")
+	t.Setenv("RECORD_PAGES_DIR", "/tmp/pages")
+	if dir := PageRecordDir(); dir != "" {
+		t.Errorf("Expected recording off by default, got %q", dir)
+	}
+
+	t.Setenv("RECORD_PAGES", "true")
+	if dir := PageRecordDir(); dir != "/tmp/pages" {
+		t.Errorf("Expected RECORD_PAGES_DIR, got %q", dir)
+	}
+
+	t.Setenv("RECORD_PAGES_DIR", "")
+	if dir := PageRecordDir(); dir != filepath.Join("data", "recorded_pages") {
+		t.Errorf("Expected the default directory, got %q", dir)
+	}
+}
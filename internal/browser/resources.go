@@ -0,0 +1,118 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+)
+
+// ReclaimConfig controls periodic cleanup of browser resources. Over a long run
+// tabs left open by conversations, profiles and the login flow keep Chrome's
+// memory growing.
+type ReclaimConfig struct {
+	EveryActions int  // Actions between cleanups (0 disables them)
+	Reload       bool // Also reload the active page to free its renderer memory
+}
+
+// GetReclaimConfig reads RECLAIM_EVERY_ACTIONS (default 25, 0 = off) and
+// RECLAIM_RELOAD (default false)
+func GetReclaimConfig() ReclaimConfig {
+	config := ReclaimConfig{
+		EveryActions: 25,
+		Reload:       os.Getenv("RECLAIM_RELOAD") == "true",
+	}
+
+	if v := os.Getenv("RECLAIM_EVERY_ACTIONS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val >= 0 {
+			config.EveryActions = val
+		}
+	}
+
+	return config
+}
+
+// ReclaimResources closes every page of br except active, the page the run is
+// driving. Returns how many pages were closed.
+func ReclaimResources(br *rod.Browser, active *rod.Page) (int, error) {
+	pages, err := br.Pages()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pages: %w", err)
+	}
+	return closeExtraPages(pages, active, func(page *rod.Page) error { return page.Close() })
+}
+
+// closeExtraPages closes the pages other than active with closePage. A page
+// that fails to close doesn't stop the others from being closed.
+func closeExtraPages(pages rod.Pages, active *rod.Page, closePage func(*rod.Page) error) (int, error) {
+	if active == nil {
+		return 0, errors.New("no active page to keep")
+	}
+
+	closed := 0
+	var errs []error
+	for _, page := range pages {
+		if page.TargetID == active.TargetID {
+			continue
+		}
+		if err := closePage(page); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close page %s: %w", page.TargetID, err))
+			continue
+		}
+		closed++
+	}
+	return closed, errors.Join(errs...)
+}
+
+// ResourceReclaimer runs ReclaimResources after every EveryActions actions
+type ResourceReclaimer struct {
+	Config ReclaimConfig
+
+	actions int
+	reclaim func() error
+}
+
+// NewResourceReclaimer returns a reclaimer that keeps page and closes the
+// browser's other pages, reloading page too when the config asks for it
+func NewResourceReclaimer(page *rod.Page, config ReclaimConfig) *ResourceReclaimer {
+	return &ResourceReclaimer{
+		Config: config,
+		reclaim: func() error {
+			closed, err := ReclaimResources(page.Browser(), page)
+			if closed > 0 {
+				logger.Info(fmt.Sprintf("Closed %d extra browser pages", closed))
+			}
+			if err != nil {
+				return err
+			}
+			if config.Reload {
+				if err := page.Reload(); err != nil {
+					return fmt.Errorf("failed to reload page: %w", err)
+				}
+				return page.WaitLoad()
+			}
+			return nil
+		},
+	}
+}
+
+// BeforeAction is called before each action (e.g. a profile visit). Once
+// EveryActions actions have run it reclaims resources before the next one; a
+// failed cleanup is only logged.
+func (r *ResourceReclaimer) BeforeAction() {
+	if r == nil || r.Config.EveryActions <= 0 {
+		return
+	}
+
+	if r.actions >= r.Config.EveryActions {
+		if err := r.reclaim(); err != nil {
+			logger.Warning("Failed to reclaim browser resources: " + err.Error())
+		}
+		r.actions = 0
+	}
+	r.actions++
+}
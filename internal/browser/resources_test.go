@@ -0,0 +1,92 @@
+package browser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestCloseExtraPagesKeepsActivePage(t *testing.T) {
+	active := &rod.Page{TargetID: "main"}
+	pages := rod.Pages{
+		{TargetID: "login"},
+		{TargetID: "main"},
+		{TargetID: "conversation"},
+		{TargetID: "stuck"},
+	}
+
+	var closedIDs []proto.TargetTargetID
+	closed, err := closeExtraPages(pages, active, func(page *rod.Page) error {
+		if page.TargetID == "stuck" {
+			return errors.New("target crashed")
+		}
+		closedIDs = append(closedIDs, page.TargetID)
+		return nil
+	})
+
+	if err == nil {
+		t.Error("Expected the page that failed to close to be reported")
+	}
+	if closed != 2 {
+		t.Errorf("Expected 2 pages closed, got %d", closed)
+	}
+	if want := []proto.TargetTargetID{"login", "conversation"}; !reflect.DeepEqual(closedIDs, want) {
+		t.Errorf("Expected %v closed, got %v", want, closedIDs)
+	}
+}
+
+func TestCloseExtraPagesWithoutActivePage(t *testing.T) {
+	closed, err := closeExtraPages(rod.Pages{{TargetID: "main"}}, nil, func(*rod.Page) error {
+		t.Error("No page should be closed without an active page")
+		return nil
+	})
+	if err == nil || closed != 0 {
+		t.Errorf("Expected an error and nothing closed, got %d closed, err %v", closed, err)
+	}
+}
+
+func TestResourceReclaimerBeforeAction(t *testing.T) {
+	reclaims := 0
+	reclaimer := &ResourceReclaimer{
+		Config:  ReclaimConfig{EveryActions: 3},
+		reclaim: func() error { reclaims++; return nil },
+	}
+
+	// Reclaims before the 4th and 7th actions, never before the first
+	for i := 0; i < 7; i++ {
+		reclaimer.BeforeAction()
+	}
+	if reclaims != 2 {
+		t.Errorf("Expected 2 reclaims in 7 actions, got %d", reclaims)
+	}
+
+	disabled := &ResourceReclaimer{reclaim: func() error { t.Error("Disabled reclaimer reclaimed"); return nil }}
+	for i := 0; i < 5; i++ {
+		disabled.BeforeAction()
+	}
+
+	var none *ResourceReclaimer
+	none.BeforeAction()
+}
+
+func TestGetReclaimConfig(t *testing.T) {
+	t.Setenv("RECLAIM_EVERY_ACTIONS", "")
+	t.Setenv("RECLAIM_RELOAD", "")
+	if config := GetReclaimConfig(); config != (ReclaimConfig{EveryActions: 25}) {
+		t.Errorf("Unexpected defaults %+v", config)
+	}
+
+	t.Setenv("RECLAIM_EVERY_ACTIONS", "0")
+	t.Setenv("RECLAIM_RELOAD", "true")
+	if config := GetReclaimConfig(); config != (ReclaimConfig{EveryActions: 0, Reload: true}) {
+		t.Errorf("Unexpected config %+v", config)
+	}
+
+	t.Setenv("RECLAIM_EVERY_ACTIONS", "-4")
+	if config := GetReclaimConfig(); config.EveryActions != 25 {
+		t.Errorf("Expected invalid values to keep the default, got %d", config.EveryActions)
+	}
+}
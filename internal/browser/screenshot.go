@@ -0,0 +1,46 @@
+package browser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// SaveScreenshot captures the visible viewport and writes it as a PNG into dir.
+// The file name is built from name plus a timestamp so repeated captures don't collide.
+// Returns the path of the written file.
+func SaveScreenshot(page *rod.Page, dir, name string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create screenshot directory: %w", err)
+	}
+
+	data, err := page.Screenshot(false, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture screenshot: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%s_%s.png", sanitizeFileName(name), time.Now().Format("20060102_150405"))
+	path := filepath.Join(dir, fileName)
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write screenshot: %w", err)
+	}
+
+	return path, nil
+}
+
+// sanitizeFileName replaces characters that are unsafe in file names
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
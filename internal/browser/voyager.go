@@ -0,0 +1,185 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/pkg/utils"
+)
+
+// maxProfileResponses bounds how many captured responses are kept per page
+const maxProfileResponses = 20
+
+// ProfileResponse is a captured Voyager profile API response
+type ProfileResponse struct {
+	URL  string
+	Body []byte
+}
+
+// profileWatch holds the event subscription and captured responses for one page
+type profileWatch struct {
+	stop context.CancelFunc
+
+	mu        sync.Mutex
+	pending   map[proto.NetworkRequestID]string // Matching requests whose body hasn't finished loading
+	responses []ProfileResponse
+}
+
+// profileWatches maps each watched page to its capture
+var (
+	profileWatchesMu sync.Mutex
+	profileWatches   = make(map[*rod.Page]*profileWatch)
+)
+
+// WatchProfileResponses records the page's Voyager profile API responses as
+// they arrive, so the relationship state can be read from LinkedIn's own data
+// instead of the DOM. Capture is passive: requests go out untouched and bodies
+// are read back from Chrome once loaded. Calling it again for the same page is a no-op.
+func WatchProfileResponses(page *rod.Page) error {
+	profileWatchesMu.Lock()
+	defer profileWatchesMu.Unlock()
+
+	if _, ok := profileWatches[page]; ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(page.GetContext())
+	watch := &profileWatch{stop: cancel, pending: make(map[proto.NetworkRequestID]string)}
+	watched := page.Context(ctx)
+
+	wait := watched.EachEvent(
+		func(e *proto.NetworkResponseReceived) {
+			if isProfileAPIURL(e.Response.URL) {
+				watch.track(e.RequestID, e.Response.URL)
+			}
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			if url, ok := watch.take(e.RequestID); ok {
+				watch.capture(watched, e.RequestID, url)
+			}
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			watch.take(e.RequestID)
+		},
+	)
+	go wait()
+
+	profileWatches[page] = watch
+	return nil
+}
+
+// StopWatchingProfileResponses stops recording the page's Voyager responses
+func StopWatchingProfileResponses(page *rod.Page) error {
+	profileWatchesMu.Lock()
+	watch, ok := profileWatches[page]
+	delete(profileWatches, page)
+	profileWatchesMu.Unlock()
+
+	if ok {
+		watch.stop()
+	}
+	return nil
+}
+
+// ProfileResponses returns the responses captured for the page, oldest first
+func ProfileResponses(page *rod.Page) []ProfileResponse {
+	watch := lookupProfileWatch(page)
+	if watch == nil {
+		return nil
+	}
+	return watch.snapshot()
+}
+
+// ClearProfileResponses drops the responses captured for the page,
+// e.g. before navigating to the next profile
+func ClearProfileResponses(page *rod.Page) {
+	if watch := lookupProfileWatch(page); watch != nil {
+		watch.clear()
+	}
+}
+
+// lookupProfileWatch returns the capture for the page, or nil if it isn't watched
+func lookupProfileWatch(page *rod.Page) *profileWatch {
+	profileWatchesMu.Lock()
+	defer profileWatchesMu.Unlock()
+	return profileWatches[page]
+}
+
+// isProfileAPIURL reports whether url is a Voyager profile API call
+func isProfileAPIURL(url string) bool {
+	return strings.Contains(url, utils.VoyagerProfileAPIPath)
+}
+
+// track remembers a matching request until its body has loaded
+func (w *profileWatch) track(id proto.NetworkRequestID, url string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending[id] = url
+}
+
+// take removes a tracked request, returning its URL if it was tracked
+func (w *profileWatch) take(id proto.NetworkRequestID) (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	url, ok := w.pending[id]
+	delete(w.pending, id)
+	return url, ok
+}
+
+// capture reads a loaded response body back from Chrome and records it.
+// A body Chrome has already evicted is skipped; the DOM check still runs.
+func (w *profileWatch) capture(page *rod.Page, id proto.NetworkRequestID, url string) {
+	result, err := proto.NetworkGetResponseBody{RequestID: id}.Call(page)
+	if err != nil {
+		logger.Debug("Failed to capture profile API response: " + err.Error())
+		return
+	}
+
+	body, err := responseBody(result)
+	if err != nil {
+		logger.Debug("Failed to decode profile API response: " + err.Error())
+		return
+	}
+	w.record(ProfileResponse{URL: url, Body: body})
+}
+
+// record appends a response, keeping only the most recent maxProfileResponses
+func (w *profileWatch) record(response ProfileResponse) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.responses = append(w.responses, response)
+	if len(w.responses) > maxProfileResponses {
+		w.responses = w.responses[len(w.responses)-maxProfileResponses:]
+	}
+}
+
+// snapshot returns a copy of the captured responses
+func (w *profileWatch) snapshot() []ProfileResponse {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]ProfileResponse(nil), w.responses...)
+}
+
+// clear drops all captured responses
+func (w *profileWatch) clear() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.responses = nil
+	w.pending = make(map[proto.NetworkRequestID]string)
+}
+
+// responseBody returns the bytes of a body Chrome handed back, which it
+// base64-encodes when the content isn't text
+func responseBody(result *proto.NetworkGetResponseBodyResult) ([]byte, error) {
+	if !result.Base64Encoded {
+		return []byte(result.Body), nil
+	}
+	return base64.StdEncoding.DecodeString(result.Body)
+}
@@ -0,0 +1,72 @@
+package browser
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestProfileWatchRecordKeepsMostRecent(t *testing.T) {
+	watch := &profileWatch{}
+	for i := 0; i < maxProfileResponses+5; i++ {
+		watch.record(ProfileResponse{URL: fmt.Sprintf("https://www.linkedin.com/voyager/api/identity/dash/profiles?n=%d", i)})
+	}
+
+	responses := watch.snapshot()
+	if len(responses) != maxProfileResponses {
+		t.Fatalf("Expected %d responses, got %d", maxProfileResponses, len(responses))
+	}
+	if responses[0].URL != "https://www.linkedin.com/voyager/api/identity/dash/profiles?n=5" {
+		t.Errorf("Expected the oldest responses to be dropped, first is %s", responses[0].URL)
+	}
+
+	watch.clear()
+	if len(watch.snapshot()) != 0 {
+		t.Error("Expected clear to drop all responses")
+	}
+}
+
+func TestIsProfileAPIURL(t *testing.T) {
+	if !isProfileAPIURL("https://www.linkedin.com/voyager/api/identity/dash/profiles?q=memberIdentity&memberIdentity=jane-doe") {
+		t.Error("Expected the profile API call to match")
+	}
+	if isProfileAPIURL("https://www.linkedin.com/voyager/api/feed/updates") {
+		t.Error("Expected other Voyager calls not to match")
+	}
+}
+
+func TestProfileWatchTracksUntilLoaded(t *testing.T) {
+	watch := &profileWatch{pending: make(map[proto.NetworkRequestID]string)}
+	watch.track("1", "https://www.linkedin.com/voyager/api/identity/dash/profiles")
+
+	if url, ok := watch.take("1"); !ok || url != "https://www.linkedin.com/voyager/api/identity/dash/profiles" {
+		t.Errorf("Expected the tracked request, got %q %v", url, ok)
+	}
+	if _, ok := watch.take("1"); ok {
+		t.Error("Expected a request to be taken only once")
+	}
+	if _, ok := watch.take("2"); ok {
+		t.Error("Expected an untracked request to be ignored")
+	}
+}
+
+func TestResponseBody(t *testing.T) {
+	plain := `{"included": []}`
+
+	body, err := responseBody(&proto.NetworkGetResponseBodyResult{Body: plain})
+	if err != nil || string(body) != plain {
+		t.Errorf("Expected a text body unchanged, got %q (%v)", body, err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(plain))
+	body, err = responseBody(&proto.NetworkGetResponseBodyResult{Body: encoded, Base64Encoded: true})
+	if err != nil || string(body) != plain {
+		t.Errorf("Expected a base64 body decoded, got %q (%v)", body, err)
+	}
+
+	if _, err := responseBody(&proto.NetworkGetResponseBodyResult{Body: "not base64!", Base64Encoded: true}); err == nil {
+		t.Error("Expected an invalid base64 body to fail")
+	}
+}
@@ -1,9 +1,14 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,29 +20,107 @@ const (
 	colorBlue   = "\033[34m"
 )
 
+// output receives every log line; mu keeps concurrent lines whole
+var (
+	mu     sync.Mutex
+	output io.Writer = os.Stdout
+)
+
+// jsonEntry is one line of LOG_FORMAT=json output
+type jsonEntry struct {
+	TS     string         `json:"ts"`
+	Level  string         `json:"level"`
+	Msg    string         `json:"msg"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
 // Info logs an informational message
 func Info(message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Printf("%s[%s] %sINFO%s: %s\n", colorBlue, timestamp, colorGreen, colorReset, message)
+	write("INFO", colorBlue, colorGreen, message, nil)
+}
+
+// InfoFields logs an informational message with structured context such as
+// profile_id or action. JSON output carries the fields as an object; text
+// output appends them as key=value pairs.
+func InfoFields(message string, fields map[string]any) {
+	write("INFO", colorBlue, colorGreen, message, fields)
 }
 
 // Error logs an error message
 func Error(message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Printf("%s[%s] %sERROR%s: %s\n", colorRed, timestamp, colorRed, colorReset, message)
-	log.Printf("[%s] ERROR: %s", timestamp, message)
+	write("ERROR", colorRed, colorRed, message, nil)
+	if !jsonFormat() {
+		log.Printf("[%s] ERROR: %s", time.Now().Format("2006-01-02 15:04:05"), message)
+	}
 }
 
 // Warning logs a warning message
 func Warning(message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Printf("%s[%s] %sWARNING%s: %s\n", colorYellow, timestamp, colorYellow, colorReset, message)
+	write("WARNING", colorYellow, colorYellow, message, nil)
 }
 
 // Debug logs a debug message
 func Debug(message string) {
 	if os.Getenv("DEBUG") == "true" {
-		timestamp := time.Now().Format("2006-01-02 15:04:05")
-		fmt.Printf("%s[%s] %sDEBUG%s: %s\n", colorBlue, timestamp, colorBlue, colorReset, message)
+		write("DEBUG", colorBlue, colorBlue, message, nil)
+	}
+}
+
+// jsonFormat reports whether LOG_FORMAT=json asks for JSON lines instead of the colored text format
+func jsonFormat() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("LOG_FORMAT")), "json")
+}
+
+// write emits one log line in the configured format
+func write(level, timeColor, levelColor, message string, fields map[string]any) {
+	now := time.Now()
+
+	var line string
+	if jsonFormat() {
+		line = formatJSON(now, level, message, fields)
+	} else {
+		line = fmt.Sprintf("%s[%s] %s%s%s: %s%s\n", timeColor, now.Format("2006-01-02 15:04:05"),
+			levelColor, level, colorReset, message, formatFields(fields))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	fmt.Fprint(output, line)
+}
+
+// formatJSON renders a log line as a JSON object followed by a newline
+func formatJSON(ts time.Time, level, message string, fields map[string]any) string {
+	entry := jsonEntry{
+		TS:     ts.Format(time.RFC3339Nano),
+		Level:  strings.ToLower(level),
+		Msg:    message,
+		Fields: fields,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		// A field that can't be encoded shouldn't lose the message
+		entry.Fields = map[string]any{"fields_error": err.Error()}
+		data, _ = json.Marshal(entry)
+	}
+	return string(data) + "\n"
+}
+
+// formatFields renders fields as " key=value" pairs sorted by key ("" for none)
+func formatFields(fields map[string]any) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, fields[key])
 	}
+	return b.String()
 }
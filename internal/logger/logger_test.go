@@ -1,7 +1,11 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestLoggerInfo verifies info logs are created
@@ -54,3 +58,92 @@ func TestLoggerConcurrency(t *testing.T) {
 		<-done
 	}
 }
+
+// captureOutput redirects log lines to a buffer for the rest of the test
+func captureOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	mu.Lock()
+	previous := output
+	output = &buf
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		output = previous
+		mu.Unlock()
+	})
+	return &buf
+}
+
+// TestJSONFormat parses LOG_FORMAT=json lines and checks their fields
+func TestJSONFormat(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "json")
+	buf := captureOutput(t)
+
+	InfoFields("Connection request sent", map[string]any{"profile_id": "jane-doe", "action": "send_connection"})
+	Warning("Approaching rate limit")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 JSON lines, got %d: %q", len(lines), buf.String())
+	}
+
+	var entry struct {
+		TS     string         `json:"ts"`
+		Level  string         `json:"level"`
+		Msg    string         `json:"msg"`
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Line is not JSON: %v (%s)", err, lines[0])
+	}
+	if entry.Level != "info" || entry.Msg != "Connection request sent" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if entry.Fields["profile_id"] != "jane-doe" || entry.Fields["action"] != "send_connection" {
+		t.Errorf("Expected profile_id and action fields, got %v", entry.Fields)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, entry.TS); err != nil {
+		t.Errorf("Expected an RFC 3339 timestamp, got %q", entry.TS)
+	}
+
+	var warning map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &warning); err != nil {
+		t.Fatalf("Line is not JSON: %v (%s)", err, lines[1])
+	}
+	if warning["level"] != "warning" {
+		t.Errorf("Expected level warning, got %v", warning["level"])
+	}
+	if _, ok := warning["fields"]; ok {
+		t.Errorf("Expected no fields key without fields, got %v", warning["fields"])
+	}
+}
+
+// TestJSONFormatUnencodableField keeps the message when a field can't be encoded
+func TestJSONFormatUnencodableField(t *testing.T) {
+	line := formatJSON(time.Now(), "INFO", "Sent", map[string]any{"bad": func() {}})
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("Line is not JSON: %v (%s)", err, line)
+	}
+	if entry["msg"] != "Sent" {
+		t.Errorf("Expected the message to survive, got %v", entry)
+	}
+}
+
+// TestTextFormatAppendsFields keeps the text format the default, with fields as key=value
+func TestTextFormatAppendsFields(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "")
+	buf := captureOutput(t)
+
+	InfoFields("Connection request sent", map[string]any{"profile_id": "jane-doe", "action": "send_connection"})
+
+	got := buf.String()
+	if !strings.Contains(got, "INFO") || !strings.HasSuffix(got, "Connection request sent action=send_connection profile_id=jane-doe\n") {
+		t.Errorf("Unexpected text line %q", got)
+	}
+	if strings.HasPrefix(strings.TrimSpace(got), "{") {
+		t.Errorf("Expected text output by default, got %q", got)
+	}
+}
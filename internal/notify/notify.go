@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// webhookTimeout bounds a single webhook delivery
+const webhookTimeout = 10 * time.Second
+
+// Event types sent to the webhook
+const (
+	EventSelectorsMayHaveChanged = "selectors_may_have_changed"
+	EventUnusualActivity         = "unusual_activity"
+)
+
+// Event is the JSON body POSTed to the webhook
+type Event struct {
+	Type      string                 `json:"event"`
+	Timestamp time.Time              `json:"timestamp"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Notifier delivers events to an external system
+type Notifier interface {
+	Notify(event Event) error
+}
+
+// WebhookNotifier POSTs each event as JSON to URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify sends the event to the webhook and fails on a non-2xx response
+func (w WebhookNotifier) Notify(event Event) error {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+
+	resp, err := client.Post(w.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// FromEnv returns a WebhookNotifier for NOTIFY_WEBHOOK_URL, or nil when it is unset
+func FromEnv() Notifier {
+	url := strings.TrimSpace(os.Getenv("NOTIFY_WEBHOOK_URL"))
+	if url == "" {
+		return nil
+	}
+	return WebhookNotifier{URL: url}
+}
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWebhookNotifierPostsEvent tests that the event arrives as JSON
+func TestWebhookNotifierPostsEvent(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	err := WebhookNotifier{URL: server.URL}.Notify(Event{
+		Type:    EventSelectorsMayHaveChanged,
+		Message: "zero results",
+		Data:    map[string]interface{}{"pages_scraped": 2},
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if received.Type != EventSelectorsMayHaveChanged {
+		t.Errorf("event = %q, want %q", received.Type, EventSelectorsMayHaveChanged)
+	}
+	if received.Timestamp.IsZero() {
+		t.Error("Expected the timestamp to be filled in")
+	}
+	if received.Data["pages_scraped"] != float64(2) {
+		t.Errorf("data.pages_scraped = %v, want 2", received.Data["pages_scraped"])
+	}
+}
+
+// TestWebhookNotifierErrorStatus tests that a non-2xx response is an error
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := (WebhookNotifier{URL: server.URL}).Notify(Event{Type: "test"}); err == nil {
+		t.Error("Notify() error = nil, want error for a 500 response")
+	}
+}
+
+// TestFromEnv tests that no notifier is configured without NOTIFY_WEBHOOK_URL
+func TestFromEnv(t *testing.T) {
+	t.Setenv("NOTIFY_WEBHOOK_URL", "")
+	if n := FromEnv(); n != nil {
+		t.Errorf("FromEnv() = %v, want nil", n)
+	}
+
+	t.Setenv("NOTIFY_WEBHOOK_URL", "https://hooks.example.com/x")
+	n, ok := FromEnv().(WebhookNotifier)
+	if !ok || n.URL != "https://hooks.example.com/x" {
+		t.Errorf("FromEnv() = %v, want WebhookNotifier for the URL", n)
+	}
+}
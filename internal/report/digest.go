@@ -0,0 +1,129 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+// maxDigestErrors caps how many error lines the digest lists
+const maxDigestErrors = 20
+
+// DigestStats is what the daily digest reports on
+type DigestStats struct {
+	Date            string   // YYYY-MM-DD
+	Searches        int      // Searches run today
+	ConnectionsSent int      // Connection requests sent today
+	Accepted        int      // Accepted connections in total
+	MessagesSent    int      // Messages sent today
+	Replies         int      // Connections that replied in total
+	Errors          []string // Failed actions today, oldest first
+	ErrorsOmitted   int      // Failures beyond the listed Errors
+	Checkpoint      bool     // LinkedIn asked for manual verification today
+}
+
+// CollectDigest gathers today's digest from the database: the daily snapshot
+// counts, today's searches and the failures recorded in the audit log
+func CollectDigest(db *storage.Database, runErrors int) (DigestStats, error) {
+	snapshot, err := db.CollectDailySnapshot(runErrors)
+	if err != nil {
+		return DigestStats{}, fmt.Errorf("failed to collect daily snapshot: %w", err)
+	}
+
+	stats := DigestStats{
+		Date:            snapshot.Date,
+		ConnectionsSent: snapshot.ConnectionsSent,
+		Accepted:        snapshot.Accepted,
+		MessagesSent:    snapshot.MessagesSent,
+		Replies:         snapshot.Replies,
+	}
+
+	limit, err := db.GetTodayRateLimit()
+	if err != nil {
+		return stats, fmt.Errorf("failed to get today's searches: %w", err)
+	}
+	stats.Searches = limit.SearchCount
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	entries, err := db.GetAuditLog(startOfDay)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	addFailures(&stats, entries)
+
+	return stats, nil
+}
+
+// addFailures lists the failed audit entries in stats and flags checkpoints
+func addFailures(stats *DigestStats, entries []storage.AuditEntry) {
+	for _, entry := range entries {
+		if entry.Result != storage.AuditFailed {
+			continue
+		}
+		if strings.Contains(strings.ToLower(entry.Detail), "checkpoint") {
+			stats.Checkpoint = true
+		}
+		if len(stats.Errors) >= maxDigestErrors {
+			stats.ErrorsOmitted++
+			continue
+		}
+
+		line := entry.Action
+		if entry.ProfileID != "" {
+			line += " " + entry.ProfileID
+		}
+		stats.Errors = append(stats.Errors, fmt.Sprintf("%s %s: %s", entry.Timestamp.Local().Format("15:04"), line, entry.Detail))
+	}
+}
+
+// digestTemplate is the HTML body of the daily digest email
+var digestTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #222;">
+<h2>LinkedIn automation digest for {{.Date}}</h2>
+{{if .Checkpoint}}<p style="color: #b00020;"><strong>LinkedIn asked for manual verification (checkpoint). Log in and resolve it before the next run.</strong></p>
+{{end}}<table cellpadding="6" style="border-collapse: collapse;">
+<tr><td>Searches</td><td><strong>{{.Searches}}</strong></td></tr>
+<tr><td>Connection requests sent</td><td><strong>{{.ConnectionsSent}}</strong></td></tr>
+<tr><td>Connections accepted (total)</td><td><strong>{{.Accepted}}</strong></td></tr>
+<tr><td>Messages sent</td><td><strong>{{.MessagesSent}}</strong></td></tr>
+<tr><td>Replies (total)</td><td><strong>{{.Replies}}</strong></td></tr>
+</table>
+{{if .Errors}}<h3>Errors ({{len .Errors}}{{if .ErrorsOmitted}} of {{.TotalErrors}}{{end}})</h3>
+<ul>
+{{range .Errors}}<li>{{.}}</li>
+{{end}}</ul>
+{{else}}<p>No errors today.</p>
+{{end}}</body>
+</html>
+`))
+
+// TotalErrors is the number of failures, listed or not
+func (s DigestStats) TotalErrors() int {
+	return len(s.Errors) + s.ErrorsOmitted
+}
+
+// RenderDigestHTML renders the digest email body
+func RenderDigestHTML(stats DigestStats) (string, error) {
+	var buf bytes.Buffer
+	if err := digestTemplate.Execute(&buf, stats); err != nil {
+		return "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// digestSubject is the email subject for a digest
+func digestSubject(stats DigestStats) string {
+	subject := fmt.Sprintf("LinkedIn digest %s: %d sent, %d messages", stats.Date, stats.ConnectionsSent, stats.MessagesSent)
+	if stats.Checkpoint {
+		subject = "[CHECKPOINT] " + subject
+	} else if stats.TotalErrors() > 0 {
+		subject += fmt.Sprintf(", %d errors", stats.TotalErrors())
+	}
+	return subject
+}
@@ -0,0 +1,141 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"linkedin-automation/internal/storage"
+)
+
+func TestRenderDigestHTML(t *testing.T) {
+	stats := DigestStats{
+		Date:            "2025-01-06",
+		Searches:        2,
+		ConnectionsSent: 12,
+		Accepted:        40,
+		MessagesSent:    3,
+		Replies:         7,
+		Errors:          []string{"10:15 send_connection jane-doe: connect button not found"},
+	}
+
+	body, err := RenderDigestHTML(stats)
+	if err != nil {
+		t.Fatalf("RenderDigestHTML() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"digest for 2025-01-06",
+		"Searches</td><td><strong>2<",
+		"Connection requests sent</td><td><strong>12<",
+		"Connections accepted (total)</td><td><strong>40<",
+		"Messages sent</td><td><strong>3<",
+		"Replies (total)</td><td><strong>7<",
+		"Errors (1)",
+		"<li>10:15 send_connection jane-doe: connect button not found</li>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Digest body is missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "checkpoint") {
+		t.Error("Digest should not mention a checkpoint when there was none")
+	}
+}
+
+func TestRenderDigestHTMLCheckpointAndNoErrors(t *testing.T) {
+	body, err := RenderDigestHTML(DigestStats{Date: "2025-01-06", Checkpoint: true})
+	if err != nil {
+		t.Fatalf("RenderDigestHTML() error = %v", err)
+	}
+	if !strings.Contains(body, "manual verification (checkpoint)") {
+		t.Errorf("Expected the checkpoint warning in the digest:\n%s", body)
+	}
+	if !strings.Contains(body, "No errors today.") {
+		t.Errorf("Expected the no-errors line in the digest:\n%s", body)
+	}
+}
+
+func TestRenderDigestHTMLEscapesErrors(t *testing.T) {
+	stats := DigestStats{Errors: []string{`<script>alert("x")</script>`}, ErrorsOmitted: 4}
+	body, err := RenderDigestHTML(stats)
+	if err != nil {
+		t.Fatalf("RenderDigestHTML() error = %v", err)
+	}
+	if strings.Contains(body, "<script>") {
+		t.Error("Error text must be HTML-escaped")
+	}
+	if !strings.Contains(body, "Errors (1 of 5)") {
+		t.Errorf("Expected the omitted errors to be counted:\n%s", body)
+	}
+}
+
+func TestDigestSubject(t *testing.T) {
+	tests := []struct {
+		stats    DigestStats
+		expected string
+	}{
+		{DigestStats{Date: "2025-01-06", ConnectionsSent: 5, MessagesSent: 2}, "LinkedIn digest 2025-01-06: 5 sent, 2 messages"},
+		{DigestStats{Date: "2025-01-06", Errors: []string{"x"}, ErrorsOmitted: 1}, "LinkedIn digest 2025-01-06: 0 sent, 0 messages, 2 errors"},
+		{DigestStats{Date: "2025-01-06", Checkpoint: true}, "[CHECKPOINT] LinkedIn digest 2025-01-06: 0 sent, 0 messages"},
+	}
+	for _, test := range tests {
+		if subject := digestSubject(test.stats); subject != test.expected {
+			t.Errorf("digestSubject() = %q, expected %q", subject, test.expected)
+		}
+	}
+}
+
+func TestCollectDigest(t *testing.T) {
+	testDBPath := "./test_digest.db"
+	defer os.Remove(testDBPath)
+
+	db, err := storage.InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	profile := storage.Profile{ID: "jane-doe", Name: "Jane Doe", ProfileURL: "https://www.linkedin.com/in/jane-doe/", VisitedAt: time.Now()}
+	if err := db.SaveProfile(profile); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+	if err := db.SaveConnectionRequest(storage.ConnectionRequest{ProfileID: "jane-doe", SentAt: time.Now(), Status: "pending", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to save connection request: %v", err)
+	}
+	if err := db.IncrementSearchCount(); err != nil {
+		t.Fatalf("Failed to record search: %v", err)
+	}
+	db.LogAudit("send_connection", "jane-doe", storage.AuditOK, "")
+	db.LogAudit("send_connection", "john-roe", storage.AuditFailed, "linkedin checkpoint detected, manual verification required")
+
+	stats, err := CollectDigest(db, 1)
+	if err != nil {
+		t.Fatalf("CollectDigest() error = %v", err)
+	}
+
+	if stats.Date != time.Now().Format("2006-01-02") || stats.Searches != 1 || stats.ConnectionsSent != 1 {
+		t.Errorf("Unexpected counts: %+v", stats)
+	}
+	if !stats.Checkpoint {
+		t.Error("Expected the checkpoint failure to be flagged")
+	}
+	if len(stats.Errors) != 1 || !strings.Contains(stats.Errors[0], "send_connection john-roe: linkedin checkpoint") {
+		t.Errorf("Expected the failed action listed, got %v", stats.Errors)
+	}
+}
+
+func TestAddFailuresCapsList(t *testing.T) {
+	var entries []storage.AuditEntry
+	for i := 0; i < maxDigestErrors+3; i++ {
+		entries = append(entries, storage.AuditEntry{Action: "navigate", Result: storage.AuditFailed, Detail: fmt.Sprintf("timeout %d", i)})
+	}
+
+	var stats DigestStats
+	addFailures(&stats, entries)
+	if len(stats.Errors) != maxDigestErrors || stats.ErrorsOmitted != 3 {
+		t.Errorf("Expected %d listed and 3 omitted, got %d and %d", maxDigestErrors, len(stats.Errors), stats.ErrorsOmitted)
+	}
+}
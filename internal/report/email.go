@@ -0,0 +1,121 @@
+package report
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"linkedin-automation/internal/logger"
+)
+
+// SMTPConfig is where and how digest emails are sent
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	User     string // Empty sends without authentication
+	Password string
+	From     string
+	To       []string
+}
+
+// EmailReportsEnabled reports whether a digest should be emailed after each run (EMAIL_REPORTS=true)
+func EmailReportsEnabled() bool {
+	return os.Getenv("EMAIL_REPORTS") == "true"
+}
+
+// SMTPConfigFromEnv reads SMTP_HOST, SMTP_PORT (default 587), SMTP_USER,
+// SMTP_PASSWORD, SMTP_FROM (default SMTP_USER) and SMTP_TO (comma-separated)
+func SMTPConfigFromEnv() SMTPConfig {
+	config := SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		User:     os.Getenv("SMTP_USER"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if config.Port == "" {
+		config.Port = "587"
+	}
+	if config.From == "" {
+		config.From = config.User
+	}
+	for _, to := range strings.Split(os.Getenv("SMTP_TO"), ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			config.To = append(config.To, to)
+		}
+	}
+	return config
+}
+
+// validate checks the settings needed to send
+func (c SMTPConfig) validate() error {
+	var missing []string
+	if c.Host == "" {
+		missing = append(missing, "SMTP_HOST")
+	}
+	if c.From == "" {
+		missing = append(missing, "SMTP_FROM")
+	}
+	if len(c.To) == 0 {
+		missing = append(missing, "SMTP_TO")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("email reports need %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// sendMail delivers a message; replaced in tests
+var sendMail = smtp.SendMail
+
+// SendDailyDigest emails the digest using the SMTP settings from the environment.
+// Failures are logged and returned but should not stop the run.
+func SendDailyDigest(stats DigestStats) error {
+	err := sendDigest(SMTPConfigFromEnv(), stats)
+	if err != nil {
+		logger.Warning("Failed to email daily digest: " + err.Error())
+		return err
+	}
+	logger.Info("Daily digest emailed")
+	return nil
+}
+
+// sendDigest renders the digest and sends it with config
+func sendDigest(config SMTPConfig, stats DigestStats) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	body, err := RenderDigestHTML(stats)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if config.User != "" {
+		auth = smtp.PlainAuth("", config.User, config.Password, config.Host)
+	}
+
+	msg := buildMessage(config.From, config.To, digestSubject(stats), body, time.Now())
+	if err := sendMail(net.JoinHostPort(config.Host, config.Port), auth, config.From, config.To, msg); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	return nil
+}
+
+// buildMessage formats an HTML email with its headers
+func buildMessage(from string, to []string, subject, htmlBody string, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	b.WriteString("Subject: " + subject + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(htmlBody, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"errors"
+	"net/smtp"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSMTPConfigFromEnv(t *testing.T) {
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_PORT", "")
+	t.Setenv("SMTP_USER", "me@example.com")
+	t.Setenv("SMTP_PASSWORD", "secret")
+	t.Setenv("SMTP_FROM", "")
+	t.Setenv("SMTP_TO", "a@example.com, b@example.com,")
+
+	config := SMTPConfigFromEnv()
+	if config.Port != "587" {
+		t.Errorf("Expected default port 587, got %q", config.Port)
+	}
+	if config.From != "me@example.com" {
+		t.Errorf("Expected From to default to SMTP_USER, got %q", config.From)
+	}
+	if want := []string{"a@example.com", "b@example.com"}; !reflect.DeepEqual(config.To, want) {
+		t.Errorf("To = %v, expected %v", config.To, want)
+	}
+}
+
+func TestSendDigest(t *testing.T) {
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		return nil
+	}
+	t.Cleanup(func() { sendMail = smtp.SendMail })
+
+	config := SMTPConfig{Host: "smtp.example.com", Port: "2525", From: "bot@example.com", To: []string{"me@example.com"}}
+	if err := sendDigest(config, DigestStats{Date: "2025-01-06", ConnectionsSent: 4}); err != nil {
+		t.Fatalf("sendDigest() error = %v", err)
+	}
+
+	if gotAddr != "smtp.example.com:2525" || gotFrom != "bot@example.com" || !reflect.DeepEqual(gotTo, []string{"me@example.com"}) {
+		t.Errorf("Unexpected envelope: %s %s %v", gotAddr, gotFrom, gotTo)
+	}
+	msg := string(gotMsg)
+	for _, want := range []string{"Subject: LinkedIn digest 2025-01-06: 4 sent", "Content-Type: text/html; charset=UTF-8", "\r\n\r\n<!DOCTYPE html>"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Message is missing %q", want)
+		}
+	}
+}
+
+func TestSendDigestErrors(t *testing.T) {
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		return errors.New("connection refused")
+	}
+	t.Cleanup(func() { sendMail = smtp.SendMail })
+
+	if err := sendDigest(SMTPConfig{Host: "smtp.example.com"}, DigestStats{}); err == nil || !strings.Contains(err.Error(), "SMTP_FROM, SMTP_TO") {
+		t.Errorf("Expected missing settings to be reported, got %v", err)
+	}
+
+	config := SMTPConfig{Host: "smtp.example.com", Port: "587", From: "bot@example.com", To: []string{"me@example.com"}}
+	if err := sendDigest(config, DigestStats{}); err == nil {
+		t.Error("Expected the SMTP failure to be returned")
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	date := time.Date(2025, 1, 6, 18, 0, 0, 0, time.UTC)
+	msg := string(buildMessage("bot@example.com", []string{"a@example.com", "b@example.com"}, "Digest", "<p>hi</p>\n", date))
+
+	expected := "From: bot@example.com\r\nTo: a@example.com, b@example.com\r\nSubject: Digest\r\n" +
+		"Date: Mon, 06 Jan 2025 18:00:00 +0000\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n<p>hi</p>\r\n"
+	if msg != expected {
+		t.Errorf("buildMessage() =\n%q\nexpected\n%q", msg, expected)
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"linkedin-automation/internal/logger"
+)
+
+// HealthChecker gathers the inputs of the health status. Each check is a
+// function so the status can be computed without a real browser or session.
+type HealthChecker struct {
+	SessionValid   func() bool      // Saved LinkedIn session is still valid
+	BrowserAlive   func() error     // Lightweight CDP ping (nil = connected)
+	LastActionTime func() time.Time // Zero if no action has been performed yet
+	InActiveHours  func() bool
+	Now            func() time.Time // Defaults to time.Now
+}
+
+// HealthStatus is the JSON body returned by /healthz
+type HealthStatus struct {
+	Healthy          bool   `json:"healthy"`
+	SessionValid     bool   `json:"session_valid"`
+	BrowserConnected bool   `json:"browser_connected"`
+	BrowserError     string `json:"browser_error,omitempty"`
+	LastActionAgo    string `json:"last_action_ago"` // e.g. "2m30s", or "never"
+	InActiveHours    bool   `json:"in_active_hours"`
+}
+
+// Status computes the current health and the HTTP status code to return.
+// The probe fails (503) when the browser is disconnected or the session is invalid;
+// being outside active hours is reported but is not a failure.
+func (c HealthChecker) Status() (HealthStatus, int) {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+
+	status := HealthStatus{LastActionAgo: "never"}
+
+	if c.SessionValid != nil {
+		status.SessionValid = c.SessionValid()
+	}
+
+	if c.BrowserAlive != nil {
+		if err := c.BrowserAlive(); err != nil {
+			status.BrowserError = err.Error()
+		} else {
+			status.BrowserConnected = true
+		}
+	}
+
+	if c.LastActionTime != nil {
+		if last := c.LastActionTime(); !last.IsZero() {
+			status.LastActionAgo = now().Sub(last).Round(time.Second).String()
+		}
+	}
+
+	if c.InActiveHours != nil {
+		status.InActiveHours = c.InActiveHours()
+	}
+
+	status.Healthy = status.SessionValid && status.BrowserConnected
+	if !status.Healthy {
+		return status, http.StatusServiceUnavailable
+	}
+	return status, http.StatusOK
+}
+
+// ServeHTTP handles GET /healthz
+func (c HealthChecker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, code := c.Status()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		logger.Warning("Failed to write health status: " + err.Error())
+	}
+}
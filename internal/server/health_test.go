@@ -0,0 +1,110 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthStatus(t *testing.T) {
+	now := time.Date(2025, 12, 30, 10, 0, 0, 0, time.UTC)
+	alive := func() error { return nil }
+	dead := func() error { return errors.New("browser not responding") }
+
+	tests := []struct {
+		name          string
+		sessionValid  bool
+		browser       func() error
+		lastAction    time.Time
+		expectedCode  int
+		expectedAgo   string
+		expectBrowser bool
+	}{
+		{"healthy", true, alive, now.Add(-150 * time.Second), http.StatusOK, "2m30s", true},
+		{"no actions yet", true, alive, time.Time{}, http.StatusOK, "never", true},
+		{"browser disconnected", true, dead, now.Add(-time.Minute), http.StatusServiceUnavailable, "1m0s", false},
+		{"session invalid", false, alive, now.Add(-time.Minute), http.StatusServiceUnavailable, "1m0s", true},
+	}
+
+	for _, test := range tests {
+		checker := HealthChecker{
+			SessionValid:   func() bool { return test.sessionValid },
+			BrowserAlive:   test.browser,
+			LastActionTime: func() time.Time { return test.lastAction },
+			InActiveHours:  func() bool { return false },
+			Now:            func() time.Time { return now },
+		}
+
+		status, code := checker.Status()
+		if code != test.expectedCode {
+			t.Errorf("%s: expected code %d, got %d", test.name, test.expectedCode, code)
+		}
+		if status.Healthy != (test.expectedCode == http.StatusOK) {
+			t.Errorf("%s: healthy=%v does not match code %d", test.name, status.Healthy, code)
+		}
+		if status.BrowserConnected != test.expectBrowser {
+			t.Errorf("%s: expected browser_connected=%v", test.name, test.expectBrowser)
+		}
+		if !test.expectBrowser && status.BrowserError == "" {
+			t.Errorf("%s: expected the browser error to be reported", test.name)
+		}
+		if status.LastActionAgo != test.expectedAgo {
+			t.Errorf("%s: expected last_action_ago %q, got %q", test.name, test.expectedAgo, status.LastActionAgo)
+		}
+		if status.InActiveHours {
+			t.Errorf("%s: expected in_active_hours=false", test.name)
+		}
+	}
+}
+
+func TestHealthStatusOutsideActiveHoursIsHealthy(t *testing.T) {
+	checker := HealthChecker{
+		SessionValid:  func() bool { return true },
+		BrowserAlive:  func() error { return nil },
+		InActiveHours: func() bool { return false },
+	}
+
+	if _, code := checker.Status(); code != http.StatusOK {
+		t.Errorf("Expected being outside active hours not to fail the probe, got %d", code)
+	}
+}
+
+func TestHealthzHandler(t *testing.T) {
+	checker := HealthChecker{
+		SessionValid:  func() bool { return true },
+		BrowserAlive:  func() error { return errors.New("connection refused") },
+		InActiveHours: func() bool { return true },
+	}
+
+	rec := httptest.NewRecorder()
+	NewMux(checker).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON body: %v", err)
+	}
+	for _, key := range []string{"session_valid", "browser_connected", "last_action_ago", "in_active_hours"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("Expected %q in response, got %v", key, body)
+		}
+	}
+	if body["browser_connected"] != false || body["session_valid"] != true {
+		t.Errorf("Unexpected response body: %v", body)
+	}
+
+	rec = httptest.NewRecorder()
+	NewMux(checker).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST, got %d", rec.Code)
+	}
+}
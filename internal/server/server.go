@@ -0,0 +1,35 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"linkedin-automation/internal/logger"
+)
+
+// NewMux returns the HTTP routes served while the automation runs
+func NewMux(health HealthChecker) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", health)
+	return mux
+}
+
+// Start serves the routes on addr (e.g. ":8080") in the background.
+// The returned server can be shut down with Close.
+func Start(addr string, health HealthChecker) *http.Server {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           NewMux(health),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		logger.Info("Health endpoint listening on " + addr + "/healthz")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Health server stopped: " + err.Error())
+		}
+	}()
+
+	return srv
+}
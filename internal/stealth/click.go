@@ -0,0 +1,107 @@
+package stealth
+
+import (
+	"fmt"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/pkg/utils"
+)
+
+// maxSafeClickAttempts is how many times SafeClick dismisses overlays before giving up
+const maxSafeClickAttempts = 3
+
+// coveringElementJS returns a description of the element on top of the
+// target's center point, or "" if the target (or its child) is on top
+const coveringElementJS = `(target) => {
+	const r = target.getBoundingClientRect();
+	const top = document.elementFromPoint(r.left + r.width / 2, r.top + r.height / 2);
+	if (!top || top === target || target.contains(top) || top.contains(target)) {
+		return "";
+	}
+	const cls = typeof top.className === "string" && top.className.trim() !== ""
+		? "." + top.className.trim().split(/\s+/).join(".")
+		: "";
+	return top.tagName.toLowerCase() + cls;
+}`
+
+// dismissOverlaysJS clicks visible dismiss buttons of known overlays,
+// skipping any overlay that contains the click target itself
+const dismissOverlaysJS = `(target, selectors, containerSelector) => {
+	let dismissed = 0;
+	for (const sel of selectors) {
+		for (const btn of document.querySelectorAll(sel)) {
+			if (btn.offsetParent === null) continue;
+			const overlay = btn.closest(containerSelector) || btn.parentElement;
+			if (overlay && overlay.contains(target)) continue;
+			btn.click();
+			dismissed++;
+		}
+	}
+	return dismissed;
+}`
+
+// SafeClick clicks an element only after verifying it is the top-most element
+// at its center point. If a cookie banner, nag modal or messaging overlay covers
+// it, known overlays are dismissed and the check is retried.
+func SafeClick(page *rod.Page, el *rod.Element) error {
+	if err := el.ScrollIntoView(); err != nil {
+		return fmt.Errorf("failed to scroll element into view: %w", err)
+	}
+
+	covering := func() (string, error) {
+		res, err := page.Eval(coveringElementJS, el.Object)
+		if err != nil {
+			return "", err
+		}
+		return res.Value.Str(), nil
+	}
+
+	dismiss := func() int {
+		res, err := page.Eval(dismissOverlaysJS, el.Object, utils.Selectors.BlockingOverlayDismiss, utils.Selectors.BlockingOverlayContainer)
+		if err != nil {
+			logger.Warning("Failed to dismiss overlays: " + err.Error())
+			return 0
+		}
+		dismissed := res.Value.Int()
+		if dismissed > 0 {
+			// Give the overlay time to animate away
+			RandomDelay(500, 1000)
+		}
+		return dismissed
+	}
+
+	click := func() error {
+		return el.Click(proto.InputMouseButtonLeft, 1)
+	}
+
+	return safeClick(covering, dismiss, click, maxSafeClickAttempts)
+}
+
+// safeClick runs the covered check / dismiss / retry loop.
+// covering returns a description of the blocking element ("" if not covered),
+// dismiss returns how many overlays it closed.
+func safeClick(covering func() (string, error), dismiss func() int, click func() error, attempts int) error {
+	blocker := ""
+	for attempt := 0; attempt < attempts; attempt++ {
+		var err error
+		blocker, err = covering()
+		if err != nil {
+			// Can't verify - fall back to a plain click rather than failing the action
+			logger.Warning("Could not verify click target is visible: " + err.Error())
+			return click()
+		}
+		if blocker == "" {
+			return click()
+		}
+
+		logger.Warning(fmt.Sprintf("Click target is covered by %s, dismissing overlays", blocker))
+		if dismiss() == 0 {
+			return fmt.Errorf("element is covered by %s and no known overlay could be dismissed", blocker)
+		}
+	}
+
+	return fmt.Errorf("element is still covered by %s after %d attempts", blocker, attempts)
+}
@@ -0,0 +1,109 @@
+package stealth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeClickTarget scripts the covered check results for safeClick
+type fakeClickTarget struct {
+	blockers  []string // blocker returned per covered check ("" = not covered)
+	checks    int
+	dismissed int
+	clicks    int
+	dismissOK bool
+}
+
+func (f *fakeClickTarget) covering() (string, error) {
+	blocker := ""
+	if f.checks < len(f.blockers) {
+		blocker = f.blockers[f.checks]
+	}
+	f.checks++
+	return blocker, nil
+}
+
+func (f *fakeClickTarget) dismiss() int {
+	if !f.dismissOK {
+		return 0
+	}
+	f.dismissed++
+	return 1
+}
+
+func (f *fakeClickTarget) click() error {
+	f.clicks++
+	return nil
+}
+
+func TestSafeClickNotCovered(t *testing.T) {
+	target := &fakeClickTarget{blockers: []string{""}, dismissOK: true}
+
+	if err := safeClick(target.covering, target.dismiss, target.click, 3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if target.clicks != 1 || target.dismissed != 0 {
+		t.Errorf("Expected 1 click and no dismissals, got %d clicks, %d dismissals", target.clicks, target.dismissed)
+	}
+}
+
+func TestSafeClickCoveredThenDismissed(t *testing.T) {
+	target := &fakeClickTarget{blockers: []string{"div.artdeco-global-alert", ""}, dismissOK: true}
+
+	if err := safeClick(target.covering, target.dismiss, target.click, 3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if target.dismissed != 1 {
+		t.Errorf("Expected overlay to be dismissed once, got %d", target.dismissed)
+	}
+	if target.clicks != 1 {
+		t.Errorf("Expected click after dismissal, got %d clicks", target.clicks)
+	}
+}
+
+func TestSafeClickCoveredNothingToDismiss(t *testing.T) {
+	target := &fakeClickTarget{blockers: []string{"div.unknown-overlay"}, dismissOK: false}
+
+	err := safeClick(target.covering, target.dismiss, target.click, 3)
+	if err == nil {
+		t.Fatal("Expected error when covered by an unknown overlay")
+	}
+	if !strings.Contains(err.Error(), "div.unknown-overlay") {
+		t.Errorf("Expected error to name the blocker, got: %v", err)
+	}
+	if target.clicks != 0 {
+		t.Errorf("Covered element must not be clicked, got %d clicks", target.clicks)
+	}
+}
+
+func TestSafeClickStillCoveredAfterRetries(t *testing.T) {
+	target := &fakeClickTarget{
+		blockers:  []string{"div.modal", "div.modal", "div.modal"},
+		dismissOK: true,
+	}
+
+	err := safeClick(target.covering, target.dismiss, target.click, 3)
+	if err == nil {
+		t.Fatal("Expected error when overlay keeps reappearing")
+	}
+	if target.checks != 3 || target.dismissed != 3 {
+		t.Errorf("Expected 3 checks and 3 dismissals, got %d and %d", target.checks, target.dismissed)
+	}
+	if target.clicks != 0 {
+		t.Errorf("Covered element must not be clicked, got %d clicks", target.clicks)
+	}
+}
+
+func TestSafeClickFallsBackWhenCheckFails(t *testing.T) {
+	clicks := 0
+	covering := func() (string, error) { return "", errors.New("context destroyed") }
+	click := func() error { clicks++; return nil }
+
+	if err := safeClick(covering, func() int { return 0 }, click, 3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if clicks != 1 {
+		t.Errorf("Expected plain click fallback, got %d clicks", clicks)
+	}
+}
@@ -3,20 +3,64 @@ package stealth
 // math/rand used to generate random delays / nummbers
 //time used to pause executions  for a certain duration
 import (
-	"math/rand"
 	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/pkg/utils"
 ) // both these are required to behave like human
 
 // minMs and maxMs : delay in Milliseconds
 // eg: RandomDelay(1000,500)  will generate a random delay between 500ms to 1000ms
 func RandomDelay(minMs int, maxMs int) {
 
-	//seed initializes the random generator
-	//time.Now().UnixNano() provides a different seed value each time the program runs
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	// The session generator is seeded once per run (see SeedFromEnv)
+	r := utils.SessionRand()
 
 	delay := r.Intn(maxMs-minMs+1) + minMs
 
 	time.Sleep(time.Duration(delay) * time.Millisecond)
 
 }
+
+// Bounds for ReadingDelay: short pages keep the old 1-2s dwell, long pages are capped
+const (
+	readingDelayMinMs     = 1000  // Dwell floor for an empty or unmeasurable page
+	readingDelayCeilingMs = 6000  // Highest lower bound, reached around 8000px of content
+	readingDelayMaxMs     = 10000 // Longest dwell ever applied
+	readingMsPer1000Px    = 625   // Extra dwell per 1000px of page height
+)
+
+// pageHeightJS returns the full scrollable height of the page
+const pageHeightJS = `() => Math.max(document.body ? document.body.scrollHeight : 0, document.documentElement.scrollHeight)`
+
+// ReadingDelay pauses as if reading the page, scaled to how much content it has:
+// long profiles get a longer dwell than short ones
+func ReadingDelay(page *rod.Page) {
+	height := 0
+	if res, err := page.Eval(pageHeightJS); err == nil {
+		height = res.Value.Int()
+	}
+
+	minMs, maxMs := readingDelayRange(height)
+	RandomDelay(minMs, maxMs)
+}
+
+// readingDelayRange derives the dwell range in milliseconds from the page height in pixels
+func readingDelayRange(heightPx int) (int, int) {
+	if heightPx < 0 {
+		heightPx = 0
+	}
+
+	minMs := readingDelayMinMs + heightPx*readingMsPer1000Px/1000
+	if minMs > readingDelayCeilingMs {
+		minMs = readingDelayCeilingMs
+	}
+
+	maxMs := minMs * 2
+	if maxMs > readingDelayMaxMs {
+		maxMs = readingDelayMaxMs
+	}
+
+	return minMs, maxMs
+}
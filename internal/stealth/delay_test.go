@@ -61,3 +61,39 @@ func TestRandomDelayWithSmallRange(t *testing.T) {
 		t.Errorf("Delay too long: %v", elapsed)
 	}
 }
+
+func TestReadingDelayRange(t *testing.T) {
+	tests := []struct {
+		height int
+		minMs  int
+		maxMs  int
+	}{
+		{-10, 1000, 2000},  // Unmeasurable page falls back to the floor
+		{0, 1000, 2000},    // Same dwell as the old fixed delay
+		{1600, 2000, 4000}, // Short profile
+		{4800, 4000, 8000}, // Long profile
+		{8000, 6000, 10000},
+		{50000, 6000, 10000}, // Capped
+	}
+
+	for _, test := range tests {
+		minMs, maxMs := readingDelayRange(test.height)
+		if minMs != test.minMs || maxMs != test.maxMs {
+			t.Errorf("height %d: expected %d-%dms, got %d-%dms", test.height, test.minMs, test.maxMs, minMs, maxMs)
+		}
+	}
+}
+
+func TestReadingDelayRangeGrowsWithHeight(t *testing.T) {
+	prevMin, prevMax := readingDelayRange(0)
+	for height := 500; height <= 20000; height += 500 {
+		minMs, maxMs := readingDelayRange(height)
+		if minMs < prevMin || maxMs < prevMax {
+			t.Errorf("height %d: dwell shrank from %d-%d to %d-%d", height, prevMin, prevMax, minMs, maxMs)
+		}
+		if minMs > maxMs || maxMs > readingDelayMaxMs {
+			t.Errorf("height %d: invalid range %d-%d", height, minMs, maxMs)
+		}
+		prevMin, prevMax = minMs, maxMs
+	}
+}
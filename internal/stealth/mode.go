@@ -0,0 +1,114 @@
+package stealth
+
+import (
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/pkg/utils"
+)
+
+// Mode controls how aggressively fingerprint masking and human-like behavior are applied.
+type Mode string
+
+const (
+	ModeOff      Mode = utils.StealthModeOff
+	ModeBasic    Mode = utils.StealthModeBasic
+	ModeAdvanced Mode = utils.StealthModeAdvanced
+	ModeMaximum  Mode = utils.StealthModeMaximum
+)
+
+// DefaultMode matches the behavior the tool had before modes existed:
+// every fingerprint patch applied, no idle noise.
+const DefaultMode = ModeAdvanced
+
+// activeMode is the mode used by the behavior functions (mouse, scroll, idle)
+var activeMode = DefaultMode
+
+// Behavior describes how much human-like activity a mode performs.
+// Min/max pairs are inclusive counts per call.
+type Behavior struct {
+	MinMouseMoves int
+	MaxMouseMoves int
+	MinScrolls    int
+	MaxScrolls    int
+	HoverElements bool
+	IdleNoise     bool
+}
+
+// ParseMode converts a STEALTH_MODE value to a Mode.
+// Unknown or empty values fall back to DefaultMode.
+func ParseMode(value string) Mode {
+	switch Mode(strings.ToLower(strings.TrimSpace(value))) {
+	case ModeOff:
+		return ModeOff
+	case ModeBasic:
+		return ModeBasic
+	case ModeAdvanced:
+		return ModeAdvanced
+	case ModeMaximum:
+		return ModeMaximum
+	default:
+		return DefaultMode
+	}
+}
+
+// ModeFromEnv reads the stealth mode from the STEALTH_MODE environment variable
+func ModeFromEnv() Mode {
+	return ParseMode(os.Getenv("STEALTH_MODE"))
+}
+
+// SetMode sets the mode used by the behavior functions
+func SetMode(mode Mode) {
+	activeMode = mode
+}
+
+// ActiveMode returns the mode currently used by the behavior functions
+func ActiveMode() Mode {
+	return activeMode
+}
+
+// Behavior returns the human-like activity settings for the mode
+func (m Mode) Behavior() Behavior {
+	switch m {
+	case ModeOff:
+		// Still scroll once so lazy-loaded content appears
+		return Behavior{MinScrolls: 1, MaxScrolls: 1}
+	case ModeBasic:
+		return Behavior{MinMouseMoves: 1, MaxMouseMoves: 2, MinScrolls: 2, MaxScrolls: 3}
+	case ModeMaximum:
+		return Behavior{MinMouseMoves: 5, MaxMouseMoves: 7, MinScrolls: 4, MaxScrolls: 7, HoverElements: true, IdleNoise: true}
+	default:
+		return Behavior{MinMouseMoves: 3, MaxMouseMoves: 5, MinScrolls: 3, MaxScrolls: 5, HoverElements: true}
+	}
+}
+
+// randomCount returns a value in [min, max] (min when the range is empty)
+func randomCount(r *rand.Rand, min, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + r.Intn(max-min+1)
+}
+
+// IdleNoise simulates a user pausing on the page: a short mouse drift
+// followed by a reading pause. Only active in maximum mode.
+func IdleNoise(page *rod.Page) {
+	if !activeMode.Behavior().IdleNoise {
+		return
+	}
+
+	r := utils.SessionRand()
+
+	fromX := float64(200 + r.Intn(400))
+	fromY := float64(150 + r.Intn(300))
+	toX := fromX + float64(r.Intn(80)-40)
+	toY := fromY + float64(r.Intn(60)-30)
+	MoveBezier(page, fromX, fromY, toX, toY)
+
+	// Pause for 1.5-4s as if reading
+	time.Sleep(time.Duration(1500+r.Intn(2500)) * time.Millisecond)
+}
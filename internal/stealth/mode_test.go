@@ -0,0 +1,104 @@
+package stealth
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Mode
+	}{
+		{"off", ModeOff},
+		{"basic", ModeBasic},
+		{"advanced", ModeAdvanced},
+		{"maximum", ModeMaximum},
+		{" MAXIMUM ", ModeMaximum},
+		{"", DefaultMode},
+		{"paranoid", DefaultMode},
+	}
+
+	for _, test := range tests {
+		if result := ParseMode(test.input); result != test.expected {
+			t.Errorf("ParseMode(%q) = %q, expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv("STEALTH_MODE", "basic")
+	if mode := ModeFromEnv(); mode != ModeBasic {
+		t.Errorf("Expected basic mode from env, got %q", mode)
+	}
+}
+
+func TestModeBehavior(t *testing.T) {
+	tests := []struct {
+		mode          Mode
+		mouseMoves    bool
+		hoverElements bool
+		idleNoise     bool
+	}{
+		{ModeOff, false, false, false},
+		{ModeBasic, true, false, false},
+		{ModeAdvanced, true, true, false},
+		{ModeMaximum, true, true, true},
+	}
+
+	for _, test := range tests {
+		b := test.mode.Behavior()
+		if (b.MaxMouseMoves > 0) != test.mouseMoves {
+			t.Errorf("%s: mouse movements active = %v, expected %v", test.mode, b.MaxMouseMoves > 0, test.mouseMoves)
+		}
+		if b.HoverElements != test.hoverElements {
+			t.Errorf("%s: hover = %v, expected %v", test.mode, b.HoverElements, test.hoverElements)
+		}
+		if b.IdleNoise != test.idleNoise {
+			t.Errorf("%s: idle noise = %v, expected %v", test.mode, b.IdleNoise, test.idleNoise)
+		}
+		if b.MinScrolls < 1 {
+			t.Errorf("%s: expected at least one scroll so lazy content loads, got %d", test.mode, b.MinScrolls)
+		}
+	}
+}
+
+func TestModeBehaviorScalesWithMode(t *testing.T) {
+	modes := []Mode{ModeOff, ModeBasic, ModeAdvanced, ModeMaximum}
+	for i := 1; i < len(modes); i++ {
+		prev, cur := modes[i-1].Behavior(), modes[i].Behavior()
+		if cur.MaxMouseMoves < prev.MaxMouseMoves || cur.MaxScrolls < prev.MaxScrolls {
+			t.Errorf("%s should be at least as active as %s", modes[i], modes[i-1])
+		}
+	}
+}
+
+func TestAdvancedBehaviorMatchesLegacyRanges(t *testing.T) {
+	// Advanced is the default and must keep the original 3-5 movements/scrolls
+	b := ModeAdvanced.Behavior()
+	if b.MinMouseMoves != 3 || b.MaxMouseMoves != 5 || b.MinScrolls != 3 || b.MaxScrolls != 5 {
+		t.Errorf("Unexpected advanced behavior: %+v", b)
+	}
+}
+
+func TestRandomCount(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		n := randomCount(r, 2, 4)
+		if n < 2 || n > 4 {
+			t.Fatalf("randomCount(2, 4) = %d, out of range", n)
+		}
+	}
+	if n := randomCount(r, 0, 0); n != 0 {
+		t.Errorf("randomCount(0, 0) = %d, expected 0", n)
+	}
+}
+
+func TestSetMode(t *testing.T) {
+	defer SetMode(ActiveMode())
+
+	SetMode(ModeMaximum)
+	if ActiveMode() != ModeMaximum {
+		t.Errorf("Expected active mode maximum, got %q", ActiveMode())
+	}
+}
@@ -3,9 +3,13 @@ package stealth
 import (
 	"math"
 	"math/rand"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/go-rod/rod"
+
+	"linkedin-automation/pkg/utils"
 )
 
 // Point represents a 2D coordinate
@@ -14,11 +18,98 @@ type Point struct {
 	Y float64
 }
 
+// MouseConfig tunes Bézier mouse movements. Fewer steps and no overshoot
+// make movements faster, at the cost of looking less human.
+type MouseConfig struct {
+	MinSteps             int     // Fewest points on a movement path
+	MaxSteps             int     // Most points on a movement path
+	OvershootProbability float64 // Chance (0-1) of overshooting the target and correcting (0 disables)
+}
+
+// DefaultMouseConfig returns the movement settings the tool has always used:
+// 20-30 steps per path and an overshoot on about 30% of movements
+func DefaultMouseConfig() MouseConfig {
+	return MouseConfig{MinSteps: 20, MaxSteps: 30, OvershootProbability: 0.3}
+}
+
+// MouseConfigFromEnv reads MOUSE_MIN_STEPS, MOUSE_MAX_STEPS and MOUSE_OVERSHOOT_PERCENT,
+// keeping the default for any value that is unset or invalid
+func MouseConfigFromEnv() MouseConfig {
+	config := DefaultMouseConfig()
+
+	if v := os.Getenv("MOUSE_MIN_STEPS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			config.MinSteps = val
+		}
+	}
+
+	if v := os.Getenv("MOUSE_MAX_STEPS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val > 0 {
+			config.MaxSteps = val
+		}
+	}
+	if config.MaxSteps < config.MinSteps {
+		config.MaxSteps = config.MinSteps
+	}
+
+	if v := os.Getenv("MOUSE_OVERSHOOT_PERCENT"); v != "" {
+		if val, err := strconv.ParseFloat(v, 64); err == nil && val >= 0 && val <= 100 {
+			config.OvershootProbability = val / 100
+		}
+	}
+
+	return config
+}
+
+// activeMouseConfig is the config used by the mouse functions
+var activeMouseConfig = DefaultMouseConfig()
+
+// SetMouseConfig sets the config used by the mouse functions
+func SetMouseConfig(config MouseConfig) {
+	activeMouseConfig = config
+}
+
+// ActiveMouseConfig returns the config currently used by the mouse functions
+func ActiveMouseConfig() MouseConfig {
+	return activeMouseConfig
+}
+
+// bezierMove is a planned mouse movement: the points along the curve and,
+// if the movement overshoots, the point past the target to correct from
+type bezierMove struct {
+	Path      []Point
+	Overshoot *Point
+}
+
 // MoveBezier moves the mouse along a Bézier curve from start to end point
 // This creates natural, human-like mouse movements instead of straight lines
 func MoveBezier(page *rod.Page, fromX, fromY, toX, toY float64) {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	moveBezier(page, activeMouseConfig, fromX, fromY, toX, toY)
+}
+
+// moveBezier is MoveBezier with an explicit config
+func moveBezier(page *rod.Page, config MouseConfig, fromX, fromY, toX, toY float64) {
+	r := utils.SessionRand()
+	move := planBezierMove(config, r, fromX, fromY, toX, toY)
+
+	// Move mouse along the Bézier curve
+	for _, p := range move.Path {
+		page.Mouse.MustMoveTo(p.X, p.Y)
+
+		// Small delay between movements (1-3ms for smooth animation)
+		time.Sleep(time.Duration(1+r.Intn(3)) * time.Millisecond)
+	}
+
+	// Add slight overshoot and correction (human behavior)
+	if move.Overshoot != nil {
+		page.Mouse.MustMoveTo(move.Overshoot.X, move.Overshoot.Y)
+		time.Sleep(time.Duration(10+r.Intn(20)) * time.Millisecond)
+		page.Mouse.MustMoveTo(toX, toY)
+	}
+}
 
+// planBezierMove computes the points of a movement without touching the page
+func planBezierMove(config MouseConfig, r *rand.Rand, fromX, fromY, toX, toY float64) bezierMove {
 	// Generate random control points for the Bézier curve
 	// Control points determine the curve's shape
 	cp1X := fromX + (toX-fromX)*0.25 + float64(r.Intn(100)-50)
@@ -27,10 +118,13 @@ func MoveBezier(page *rod.Page, fromX, fromY, toX, toY float64) {
 	cp2X := fromX + (toX-fromX)*0.75 + float64(r.Intn(100)-50)
 	cp2Y := fromY + (toY-fromY)*r.Float64()
 
-	// Number of steps in the curve (20-30 for smooth movement)
-	steps := 20 + r.Intn(11)
+	// Number of steps in the curve (20-30 by default for smooth movement)
+	steps := randomCount(r, config.MinSteps, config.MaxSteps)
+	if steps < 1 {
+		steps = 1
+	}
 
-	// Move mouse along the Bézier curve
+	move := bezierMove{Path: make([]Point, 0, steps+1)}
 	for i := 0; i <= steps; i++ {
 		// Calculate parameter t (0 to 1)
 		t := float64(i) / float64(steps)
@@ -49,21 +143,18 @@ func MoveBezier(page *rod.Page, fromX, fromY, toX, toY float64) {
 			3*(1-t)*math.Pow(t, 2)*cp2Y +
 			math.Pow(t, 3)*toY
 
-		// Move to calculated position
-		page.Mouse.MustMoveTo(x, y)
-
-		// Small delay between movements (1-3ms for smooth animation)
-		time.Sleep(time.Duration(1+r.Intn(3)) * time.Millisecond)
+		move.Path = append(move.Path, Point{X: x, Y: y})
 	}
 
-	// Add slight overshoot and correction (human behavior)
-	if r.Float64() > 0.7 {
-		overshootX := toX + float64(r.Intn(10)-5)
-		overshootY := toY + float64(r.Intn(10)-5)
-		page.Mouse.MustMoveTo(overshootX, overshootY)
-		time.Sleep(time.Duration(10+r.Intn(20)) * time.Millisecond)
-		page.Mouse.MustMoveTo(toX, toY)
+	// Overshoot slightly past the target on some movements
+	if r.Float64() < config.OvershootProbability {
+		move.Overshoot = &Point{
+			X: toX + float64(r.Intn(10)-5),
+			Y: toY + float64(r.Intn(10)-5),
+		}
 	}
+
+	return move
 }
 
 // easeInOutCubic provides natural acceleration/deceleration
@@ -79,15 +170,20 @@ func easeInOutCubic(t float64) float64 {
 // It performs multiple random mouse movements across the page with natural pauses
 // to mimic real human behavior patterns.
 func MoveMouseRandomly(page *rod.Page) {
-	// Create a seeded random number generator
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	moveMouseRandomly(page, activeMouseConfig)
+}
+
+// moveMouseRandomly is MoveMouseRandomly with an explicit config
+func moveMouseRandomly(page *rod.Page, config MouseConfig) {
+	r := utils.SessionRand()
 
 	// Get current mouse position (or start from a random position)
 	currentX := float64(200 + r.Intn(400))
 	currentY := float64(150 + r.Intn(300))
 
-	// Perform 3-5 random mouse movements
-	numMovements := 3 + r.Intn(3) // Random number between 3-5
+	// Number of movements depends on the stealth mode (3-5 in advanced mode)
+	behavior := activeMode.Behavior()
+	numMovements := randomCount(r, behavior.MinMouseMoves, behavior.MaxMouseMoves)
 
 	for i := 0; i < numMovements; i++ {
 		// Generate random target coordinates
@@ -95,7 +191,7 @@ func MoveMouseRandomly(page *rod.Page) {
 		targetY := float64(r.Intn(500) + 100) // 100-600 pixels
 
 		// Move using Bézier curve for natural movement
-		MoveBezier(page, currentX, currentY, targetX, targetY)
+		moveBezier(page, config, currentX, currentY, targetX, targetY)
 
 		// Update current position
 		currentX = targetX
@@ -108,14 +204,24 @@ func MoveMouseRandomly(page *rod.Page) {
 
 // HoverRandomElements hovers the mouse over random interactive elements on the page
 // This simulates natural browsing behavior where users hover over links and buttons
+// Skipped in off and basic stealth modes
 func HoverRandomElements(page *rod.Page) error {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return hoverRandomElements(page, activeMouseConfig)
+}
+
+// hoverRandomElements is HoverRandomElements with an explicit config
+func hoverRandomElements(page *rod.Page, config MouseConfig) error {
+	if !activeMode.Behavior().HoverElements {
+		return nil
+	}
+
+	r := utils.SessionRand()
 
 	// Find all interactive elements (links, buttons)
 	elements, err := page.Elements("a, button, [role='button']")
 	if err != nil || len(elements) == 0 {
 		// If no elements found, just do random movements
-		MoveMouseRandomly(page)
+		moveMouseRandomly(page, config)
 		return nil
 	}
 
@@ -156,7 +262,7 @@ func HoverRandomElements(page *rod.Page) error {
 		currentY := float64(150 + r.Intn(300))
 
 		// Move to element with Bézier curve
-		MoveBezier(page, currentX, currentY, centerX, centerY)
+		moveBezier(page, config, currentX, currentY, centerX, centerY)
 
 		// Hover for 200-500ms (simulating user reading/thinking)
 		time.Sleep(time.Duration(200+r.Intn(300)) * time.Millisecond)
@@ -1,6 +1,8 @@
 package stealth
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -88,3 +90,93 @@ func TestBezierCurveProperties(t *testing.T) {
 	// At t=1, curve should be at end point (with our easing function applied)
 	// The actual implementation uses easing, so we just verify the concept
 }
+
+func TestPlanBezierMoveOvershootDisabled(t *testing.T) {
+	config := DefaultMouseConfig()
+	config.OvershootProbability = 0
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		if move := planBezierMove(config, r, 100, 100, 600, 400); move.Overshoot != nil {
+			t.Fatalf("Movement %d overshot with probability 0", i)
+		}
+	}
+}
+
+func TestPlanBezierMoveOvershootDefault(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	overshoots := 0
+	for i := 0; i < 1000; i++ {
+		move := planBezierMove(DefaultMouseConfig(), r, 100, 100, 600, 400)
+		if move.Overshoot != nil {
+			overshoots++
+			if math.Abs(move.Overshoot.X-600) > 5 || math.Abs(move.Overshoot.Y-400) > 5 {
+				t.Errorf("Overshoot %v is too far from the target", *move.Overshoot)
+			}
+		}
+	}
+
+	// About 30% of movements overshoot by default
+	if overshoots < 230 || overshoots > 370 {
+		t.Errorf("Expected about 300 overshoots in 1000 movements, got %d", overshoots)
+	}
+}
+
+func TestPlanBezierMoveStepBounds(t *testing.T) {
+	tests := []MouseConfig{
+		DefaultMouseConfig(),
+		{MinSteps: 5, MaxSteps: 8},
+		{MinSteps: 3, MaxSteps: 3},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for _, config := range tests {
+		seen := map[int]bool{}
+		for i := 0; i < 300; i++ {
+			move := planBezierMove(config, r, 0, 0, 300, 200)
+
+			// The path holds steps+1 points, from start to target
+			steps := len(move.Path) - 1
+			if steps < config.MinSteps || steps > config.MaxSteps {
+				t.Fatalf("%+v: %d steps outside bounds", config, steps)
+			}
+			seen[steps] = true
+
+			last := move.Path[len(move.Path)-1]
+			if math.Abs(last.X-300) > 1e-9 || math.Abs(last.Y-200) > 1e-9 {
+				t.Fatalf("%+v: path ends at %v, expected the target", config, last)
+			}
+		}
+		if len(seen) != config.MaxSteps-config.MinSteps+1 {
+			t.Errorf("%+v: expected every step count in bounds to occur, saw %v", config, seen)
+		}
+	}
+}
+
+func TestMouseConfigFromEnv(t *testing.T) {
+	t.Setenv("MOUSE_MIN_STEPS", "")
+	t.Setenv("MOUSE_MAX_STEPS", "")
+	t.Setenv("MOUSE_OVERSHOOT_PERCENT", "")
+	if config := MouseConfigFromEnv(); config != DefaultMouseConfig() {
+		t.Errorf("Expected defaults, got %+v", config)
+	}
+
+	t.Setenv("MOUSE_MIN_STEPS", "8")
+	t.Setenv("MOUSE_MAX_STEPS", "12")
+	t.Setenv("MOUSE_OVERSHOOT_PERCENT", "0")
+	config := MouseConfigFromEnv()
+	if config.MinSteps != 8 || config.MaxSteps != 12 || config.OvershootProbability != 0 {
+		t.Errorf("Expected 8-12 steps and no overshoot, got %+v", config)
+	}
+
+	// A max below the min is raised to the min; invalid values keep defaults
+	t.Setenv("MOUSE_MAX_STEPS", "4")
+	t.Setenv("MOUSE_OVERSHOOT_PERCENT", "150")
+	config = MouseConfigFromEnv()
+	if config.MaxSteps != 8 {
+		t.Errorf("Expected max steps raised to 8, got %d", config.MaxSteps)
+	}
+	if config.OvershootProbability != DefaultMouseConfig().OvershootProbability {
+		t.Errorf("Expected the default overshoot for an invalid percent, got %v", config.OvershootProbability)
+	}
+}
@@ -1,28 +1,203 @@
 package stealth
 
 import (
+	"fmt"
 	"math/rand"
 	"time"
 
 	"github.com/go-rod/rod"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/pkg/utils"
+)
+
+// Scroll step bounds for ScrollThroughContent, as fractions of the viewport:
+// people scroll less than a screen at a time so they keep their place
+const (
+	scrollMinFraction    = 0.35
+	scrollMaxFraction    = 0.85
+	scrollBottomMarginPx = 120 // Closer than this to the end of the content counts as the bottom
+	fallbackViewportPx   = 800 // Used when the viewport can't be measured
 )
 
+// scrollPositionJS returns the scroll offset, viewport height and full content height
+const scrollPositionJS = `() => ({
+	top: Math.round(window.scrollY),
+	viewport: window.innerHeight,
+	height: Math.max(document.body ? document.body.scrollHeight : 0, document.documentElement.scrollHeight)
+})`
+
+// scrollPosition is the page's scroll state in pixels
+type scrollPosition struct {
+	Top      int // Scroll offset of the top of the viewport
+	Viewport int // Viewport height
+	Height   int // Full scrollable content height
+}
+
+// remaining returns how much content is below the viewport
+func (p scrollPosition) remaining() int {
+	return p.Height - p.Top - p.Viewport
+}
+
+// nearBottom reports whether the viewport is at (or within the margin of) the end of the content
+func (p scrollPosition) nearBottom() bool {
+	return p.remaining() <= scrollBottomMarginPx
+}
+
 // RandomScroll simulates human-like scrolling behavior on a webpage.
-// It performs multiple scrolls with random distances and pauses to mimic natural browsing patterns.
+// It performs multiple scrolls with random distances and pauses to mimic natural
+// browsing patterns, stopping at the end of the content rather than scrolling into blank space.
 func RandomScroll(page *rod.Page) {
-	// Create a seeded random number generator
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-
-	// Perform 3-5 random scroll actions
-	numScrolls := 3 + r.Intn(3) // Random number between 3-5
+	r := utils.SessionRand()
 
-	for i := 0; i < numScrolls; i++ {
-		// Generate a random scroll distance between 200-600 pixels vertically
-		scrollY := r.Intn(400) + 200
+	// Number of scrolls depends on the stealth mode (3-5 in advanced mode)
+	behavior := activeMode.Behavior()
+	numScrolls := randomCount(r, behavior.MinScrolls, behavior.MaxScrolls)
 
-		// Scroll the page vertically (0 = no horizontal scroll, scrollY = vertical scroll distance)
-		page.Mouse.MustScroll(0, float64(scrollY))
+	measure, scroll := pageScroller(page, r)
+	readingPause := func(newContent bool) {
 		// Pause for 800-1500ms to simulate human reading time between scrolls
 		time.Sleep(time.Duration(800+r.Intn(700)) * time.Millisecond)
 	}
+
+	scrolled, err := scrollThroughContent(measure, scroll, readingPause, r, numScrolls, numScrolls)
+	if err != nil && scrolled == 0 {
+		// The page couldn't be measured; fall back to fixed distances
+		logger.Warning("Content-aware scroll failed, scrolling blind: " + err.Error())
+		for i := 0; i < numScrolls; i++ {
+			// Generate a random scroll distance between 200-600 pixels vertically
+			page.Mouse.MustScroll(0, float64(r.Intn(400)+200))
+			readingPause(false)
+		}
+	}
+}
+
+// ScrollThroughContent scrolls down through up to maxScreens viewport heights
+// of content in jittered, less-than-a-screen steps. It reads the content height
+// before each step so it never scrolls past the end, pauses longer when a step
+// lazy-loads more content, and at the bottom waits once for more content before
+// stopping.
+func ScrollThroughContent(page *rod.Page, maxScreens int) error {
+	r := utils.SessionRand()
+	measure, scroll := pageScroller(page, r)
+
+	pause := func(newContent bool) {
+		if newContent {
+			// Glance over what just loaded
+			RandomDelay(1500, 3000)
+		} else {
+			RandomDelay(700, 1500)
+		}
+	}
+
+	_, err := scrollThroughContent(measure, scroll, pause, r, maxScreens, 0)
+	return err
+}
+
+// pageScroller returns the measure and scroll functions for scrollThroughContent on page
+func pageScroller(page *rod.Page, r *rand.Rand) (func() (scrollPosition, error), func(dy int) error) {
+	measure := func() (scrollPosition, error) {
+		res, err := page.Eval(scrollPositionJS)
+		if err != nil {
+			return scrollPosition{}, err
+		}
+		return scrollPosition{
+			Top:      res.Value.Get("top").Int(),
+			Viewport: res.Value.Get("viewport").Int(),
+			Height:   res.Value.Get("height").Int(),
+		}, nil
+	}
+
+	scroll := func(dy int) error {
+		// A few wheel events per step rather than one jump
+		return page.Mouse.Scroll(0, float64(dy), 2+r.Intn(4))
+	}
+
+	return measure, scroll
+}
+
+// scrollThroughContent runs the scroll loop: up to maxScreens viewport heights
+// and, when maxSteps > 0, at most maxSteps steps. pause is called after every
+// step with whether the content grew. Returns the distance scrolled in pixels.
+func scrollThroughContent(measure func() (scrollPosition, error), scroll func(dy int) error, pause func(newContent bool), r *rand.Rand, maxScreens, maxSteps int) (int, error) {
+	pos, err := measure()
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure page: %w", err)
+	}
+
+	budget := maxScreens * viewportOrFallback(pos)
+	scrolled, steps := 0, 0
+	waitedAtBottom := false
+
+	for scrolled < budget && (maxSteps <= 0 || steps < maxSteps) {
+		step := scrollIncrement(r, pos, budget-scrolled)
+		if step == 0 {
+			// At the bottom: give lazy-loaded content one chance to appear
+			if waitedAtBottom {
+				break
+			}
+			waitedAtBottom = true
+			pause(false)
+
+			next, err := measure()
+			if err != nil {
+				return scrolled, fmt.Errorf("failed to measure page: %w", err)
+			}
+			grew := next.Height > pos.Height
+			pos = next
+			if !grew {
+				break
+			}
+			waitedAtBottom = false
+			continue
+		}
+
+		if err := scroll(step); err != nil {
+			return scrolled, fmt.Errorf("failed to scroll: %w", err)
+		}
+		scrolled += step
+		steps++
+
+		next, err := measure()
+		if err != nil {
+			return scrolled, fmt.Errorf("failed to measure page: %w", err)
+		}
+		grew := next.Height > pos.Height
+		pos = next
+		if grew {
+			// New content resets the bottom wait
+			waitedAtBottom = false
+		}
+		pause(grew)
+	}
+
+	return scrolled, nil
+}
+
+// scrollIncrement returns the next scroll step: a random fraction of the
+// viewport, cut to the content left below it and to budget. Returns 0 at the
+// bottom of the content.
+func scrollIncrement(r *rand.Rand, pos scrollPosition, budget int) int {
+	if pos.nearBottom() || budget <= 0 {
+		return 0
+	}
+
+	fraction := scrollMinFraction + r.Float64()*(scrollMaxFraction-scrollMinFraction)
+	step := int(float64(viewportOrFallback(pos)) * fraction)
+
+	if remaining := pos.remaining(); step > remaining {
+		step = remaining
+	}
+	if step > budget {
+		step = budget
+	}
+	return step
+}
+
+// viewportOrFallback returns the viewport height, or fallbackViewportPx if it wasn't measured
+func viewportOrFallback(pos scrollPosition) int {
+	if pos.Viewport > 0 {
+		return pos.Viewport
+	}
+	return fallbackViewportPx
 }
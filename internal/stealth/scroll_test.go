@@ -0,0 +1,148 @@
+package stealth
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// fakeScrollPage simulates a lazy-loading page: reaching the bottom starts a
+// load of loadPx more content (loads times), which shows on the next measurement
+type fakeScrollPage struct {
+	top      int
+	viewport int
+	height   int
+	loads    int
+	loadPx   int
+	loading  bool
+	measured int
+	steps    []int
+}
+
+func (p *fakeScrollPage) measure() (scrollPosition, error) {
+	p.measured++
+	if p.loading {
+		p.height += p.loadPx
+		p.loads--
+		p.loading = false
+	}
+
+	pos := scrollPosition{Top: p.top, Viewport: p.viewport, Height: p.height}
+	if pos.nearBottom() && p.loads > 0 {
+		p.loading = true
+	}
+	return pos, nil
+}
+
+func (p *fakeScrollPage) scroll(dy int) error {
+	p.steps = append(p.steps, dy)
+	p.top += dy
+	if max := p.height - p.viewport; p.top > max {
+		p.top = max
+	}
+	return nil
+}
+
+func TestScrollIncrement(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	pos := scrollPosition{Top: 0, Viewport: 1000, Height: 10000}
+
+	for i := 0; i < 200; i++ {
+		step := scrollIncrement(r, pos, 100000)
+		if step < 350 || step > 850 {
+			t.Fatalf("Step %d outside 35-85%% of the viewport", step)
+		}
+	}
+
+	// Cut to the content left below the viewport
+	nearEnd := scrollPosition{Top: 8700, Viewport: 1000, Height: 10000}
+	if step := scrollIncrement(r, nearEnd, 100000); step > 300 {
+		t.Errorf("Expected at most the 300px left, got %d", step)
+	}
+
+	// Cut to the remaining budget
+	if step := scrollIncrement(r, pos, 50); step != 50 {
+		t.Errorf("Expected the 50px budget, got %d", step)
+	}
+
+	// Nothing to scroll at the bottom
+	atBottom := scrollPosition{Top: 8950, Viewport: 1000, Height: 10000}
+	if step := scrollIncrement(r, atBottom, 100000); step != 0 {
+		t.Errorf("Expected no step within the bottom margin, got %d", step)
+	}
+
+	// Unmeasured viewport falls back to a typical screen
+	if step := scrollIncrement(r, scrollPosition{Height: 10000}, 100000); step < 280 || step > 680 {
+		t.Errorf("Expected a step based on the fallback viewport, got %d", step)
+	}
+}
+
+func TestScrollThroughContentStopsAtBottom(t *testing.T) {
+	page := &fakeScrollPage{viewport: 800, height: 2000}
+	var pauses []bool
+
+	scrolled, err := scrollThroughContent(page.measure, page.scroll, func(grew bool) { pauses = append(pauses, grew) },
+		rand.New(rand.NewSource(3)), 10, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Never past the end of the content
+	if scrolled > 1200 || page.top > 1200 {
+		t.Errorf("Scrolled %dpx to %d, past the 1200px of content below the fold", scrolled, page.top)
+	}
+	if page.top < 1200-scrollBottomMarginPx {
+		t.Errorf("Expected to reach the bottom, stopped at %d", page.top)
+	}
+
+	// One wait at the bottom for lazy-loaded content, after the scroll pauses
+	if len(pauses) != len(page.steps)+1 {
+		t.Errorf("Expected a pause per step plus one bottom wait, got %d pauses for %d steps", len(pauses), len(page.steps))
+	}
+}
+
+func TestScrollThroughContentFollowsLazyLoad(t *testing.T) {
+	// The feed loads 2000px more content each of the first two times its bottom is reached
+	page := &fakeScrollPage{viewport: 800, height: 2000, loads: 2, loadPx: 2000}
+	_, err := scrollThroughContent(page.measure, page.scroll, func(bool) {}, rand.New(rand.NewSource(5)), 20, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if page.height != 6000 {
+		t.Fatalf("Expected both loads to be triggered, content is %dpx", page.height)
+	}
+	if page.top < 5200-scrollBottomMarginPx || page.top > 5200 {
+		t.Errorf("Expected to follow the loaded content to its bottom, stopped at %d", page.top)
+	}
+}
+
+func TestScrollThroughContentLimits(t *testing.T) {
+	tall := func() *fakeScrollPage { return &fakeScrollPage{viewport: 800, height: 100000} }
+
+	page := tall()
+	scrolled, err := scrollThroughContent(page.measure, page.scroll, func(bool) {}, rand.New(rand.NewSource(7)), 2, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if scrolled != 1600 {
+		t.Errorf("Expected exactly 2 screens (1600px), got %d", scrolled)
+	}
+
+	page = tall()
+	if _, err := scrollThroughContent(page.measure, page.scroll, func(bool) {}, rand.New(rand.NewSource(7)), 10, 3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.steps) != 3 {
+		t.Errorf("Expected 3 steps, got %d", len(page.steps))
+	}
+}
+
+func TestScrollThroughContentMeasureError(t *testing.T) {
+	failing := func() (scrollPosition, error) { return scrollPosition{}, errors.New("target closed") }
+	scrolled, err := scrollThroughContent(failing, func(int) error { t.Error("Should not scroll"); return nil },
+		func(bool) {}, rand.New(rand.NewSource(1)), 3, 0)
+	if err == nil || scrolled != 0 {
+		t.Errorf("Expected an error before scrolling, got %d, %v", scrolled, err)
+	}
+}
@@ -0,0 +1,33 @@
+package stealth
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"linkedin-automation/pkg/utils"
+)
+
+// SeedFromEnv seeds the session random generator from STEALTH_SEED so a run's
+// mouse paths, delays and fingerprint can be reproduced. Without a valid seed
+// the time-based one is kept. Returns the seed in use.
+func SeedFromEnv() int64 {
+	if seed, ok := parseSeed(os.Getenv("STEALTH_SEED")); ok {
+		utils.SeedSessionRand(seed)
+	}
+	return utils.SessionSeed()
+}
+
+// parseSeed converts a STEALTH_SEED value to a seed
+func parseSeed(value string) (int64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	seed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seed, true
+}
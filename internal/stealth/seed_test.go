@@ -0,0 +1,61 @@
+package stealth
+
+import (
+	"testing"
+
+	"linkedin-automation/pkg/utils"
+)
+
+func TestParseSeed(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		ok       bool
+	}{
+		{"12345", 12345, true},
+		{" -7 ", -7, true},
+		{"", 0, false},
+		{"abc", 0, false},
+	}
+
+	for _, test := range tests {
+		seed, ok := parseSeed(test.input)
+		if seed != test.expected || ok != test.ok {
+			t.Errorf("parseSeed(%q) = (%d, %v), expected (%d, %v)", test.input, seed, ok, test.expected, test.ok)
+		}
+	}
+}
+
+func TestSeedFromEnvDeterministic(t *testing.T) {
+	t.Setenv("STEALTH_SEED", "2025")
+
+	sample := func() []int {
+		if seed := SeedFromEnv(); seed != 2025 {
+			t.Fatalf("SeedFromEnv() = %d, expected 2025", seed)
+		}
+		r := utils.SessionRand()
+		behavior := ModeMaximum.Behavior()
+		values := make([]int, 10)
+		for i := range values {
+			values[i] = randomCount(r, behavior.MinMouseMoves, behavior.MaxMouseMoves)*1000 + r.Intn(1000)
+		}
+		return values
+	}
+
+	first := sample()
+	second := sample()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Expected STEALTH_SEED to reproduce the sequence, got %v and %v", first, second)
+		}
+	}
+}
+
+func TestSeedFromEnvKeepsSeedWhenUnset(t *testing.T) {
+	utils.SeedSessionRand(99)
+	t.Setenv("STEALTH_SEED", "")
+
+	if seed := SeedFromEnv(); seed != 99 {
+		t.Errorf("Expected the existing seed to be kept, got %d", seed)
+	}
+}
@@ -1,17 +1,47 @@
 package stealth
 
 import (
-	"math/rand"
 	"time"
 
 	"github.com/go-rod/rod"
+
+	"linkedin-automation/pkg/utils"
 )
 
-// TypeLikeHuman types text character by character with random delays
-func TypeLikeHuman(el *rod.Element, text string) {
+// TypeLikeHuman types text character by character with random delays.
+// Stops at the first character that can't be typed and returns the error.
+func TypeLikeHuman(el *rod.Element, text string) error {
+	for _, char := range text {
+		if err := el.Input(string(char)); err != nil {
+			return err
+		}
+
+		keystrokePause()
+	}
+	return nil
+}
+
+// TypeIntoEditable types text into a contenteditable element (e.g. a
+// div[role=textbox]) character by character with random delays. Input only
+// works on form fields, so the element is focused and the characters are
+// inserted as keyboard text.
+func TypeIntoEditable(el *rod.Element, text string) error {
+	if err := el.Focus(); err != nil {
+		return err
+	}
+
+	page := el.Page()
 	for _, char := range text {
-		el.MustInput(string(char))
+		if err := page.InsertText(string(char)); err != nil {
+			return err
+		}
 
-		time.Sleep(time.Duration(100+rand.Intn(150)) * time.Millisecond)
+		keystrokePause()
 	}
+	return nil
+}
+
+// keystrokePause waits 100-250ms between typed characters
+func keystrokePause() {
+	time.Sleep(time.Duration(100+utils.SessionRand().Intn(150)) * time.Millisecond)
 }
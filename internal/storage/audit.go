@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Audit results
+const (
+	AuditStarted = "started" // Action is about to run
+	AuditOK      = "ok"      // Action completed
+	AuditFailed  = "failed"  // Action returned an error
+)
+
+// AuditEntry is one row of the per-action audit log
+type AuditEntry struct {
+	ID        int64
+	Timestamp time.Time
+	Action    string // e.g. 'navigate', 'search', 'send_connection', 'send_message'
+	ProfileID string // Empty for actions not tied to a profile
+	Result    string // 'started', 'ok', 'failed'
+	Detail    string // URL, error message or other context
+}
+
+// LogAudit appends an entry to the audit log
+func (db *Database) LogAudit(action, profileID, result, detail string) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO audit_log (ts, action, profile_id, result, detail)
+		VALUES (?, ?, ?, ?, ?)
+	`, time.Now().UTC(), action, profileID, result, detail)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry for %s: %w", action, err)
+	}
+	return nil
+}
+
+// GetAuditLog returns the audit entries written at or after since, oldest first
+func (db *Database) GetAuditLog(since time.Time) ([]AuditEntry, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, ts, action, COALESCE(profile_id, ''), result, COALESCE(detail, '')
+		FROM audit_log
+		WHERE datetime(ts) >= datetime(?)
+		ORDER BY ts ASC, id ASC
+	`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.Action, &e.ProfileID, &e.Result, &e.Detail); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
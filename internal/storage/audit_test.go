@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	testDBPath := "./test_audit.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	// An entry from before the query window
+	if _, err := db.conn.Exec(`INSERT INTO audit_log (ts, action, profile_id, result, detail) VALUES (?, ?, ?, ?, ?)`,
+		time.Now().UTC().Add(-48*time.Hour), "search", "", AuditOK, "old"); err != nil {
+		t.Fatalf("Failed to insert old entry: %v", err)
+	}
+
+	if err := db.LogAudit("send_connection", "alice", AuditStarted, "https://www.linkedin.com/in/alice/"); err != nil {
+		t.Fatalf("Failed to log audit entry: %v", err)
+	}
+	if err := db.LogAudit("send_connection", "alice", AuditFailed, "already connected"); err != nil {
+		t.Fatalf("Failed to log audit entry: %v", err)
+	}
+
+	entries, err := db.GetAuditLog(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get audit log: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries in the last day, got %d: %+v", len(entries), entries)
+	}
+
+	// Oldest first
+	if entries[0].Result != AuditStarted || entries[1].Result != AuditFailed {
+		t.Errorf("Entries out of order: %+v", entries)
+	}
+	if entries[1].ProfileID != "alice" || entries[1].Detail != "already connected" || entries[1].Action != "send_connection" {
+		t.Errorf("Unexpected entry: %+v", entries[1])
+	}
+	if entries[0].Timestamp.IsZero() {
+		t.Error("Expected entry timestamp to be set")
+	}
+
+	all, err := db.GetAuditLog(time.Now().Add(-72 * time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get audit log: %v", err)
+	}
+	if len(all) != 3 || all[0].Detail != "old" {
+		t.Errorf("Expected 3 entries starting with the old one, got %+v", all)
+	}
+}
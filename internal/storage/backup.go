@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrBackupDecrypt means an encrypted backup couldn't be opened: the
+// passphrase is wrong or the file was altered
+var ErrBackupDecrypt = errors.New("wrong passphrase or corrupted backup")
+
+// Encrypted backup layout: magic, scrypt cost (log2 N, r, p), salt, GCM
+// nonce, then the sealed database. The header is authenticated with the data.
+const (
+	backupMagic     = "LIABAK1\x00"
+	backupSaltLen   = 16
+	backupNonceLen  = 12
+	backupKeyLen    = 32 // AES-256
+	backupHeaderLen = len(backupMagic) + 3 + backupSaltLen + backupNonceLen
+)
+
+// Key derivation cost of new backups: N = 2^15, r = 8, p = 1 (about 32 MB)
+const (
+	backupLogN = 15
+	backupR    = 8
+	backupP    = 1
+)
+
+// sqliteHeader starts every SQLite database file
+const sqliteHeader = "SQLite format 3\x00"
+
+// BackupEncrypted writes an encrypted copy of the database at dbPath to
+// outPath. The copy is a snapshot taken by SQLite (VACUUM INTO), so it is
+// consistent even while a run is writing to the database. The key is derived
+// from passphrase with scrypt and the file is sealed with AES-256-GCM.
+func BackupEncrypted(dbPath, outPath, passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("a backup passphrase is required")
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("failed to find database: %w", err)
+	}
+
+	data, err := snapshotDatabase(dbPath)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := encryptBackup(data, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(outPath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create backup directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(outPath, sealed, 0600); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// RestoreEncrypted decrypts a backup written by BackupEncrypted into dbPath.
+// An existing database is kept as dbPath.before-restore, together with any
+// log files it still has. Callers must make sure no run has it open. Returns
+// ErrBackupDecrypt for a wrong passphrase, leaving dbPath untouched.
+func RestoreEncrypted(inPath, dbPath, passphrase string) error {
+	sealed, err := os.ReadFile(inPath)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	data, err := decryptBackup(sealed, passphrase)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(data, []byte(sqliteHeader)) {
+		return fmt.Errorf("backup does not hold a SQLite database")
+	}
+
+	if dir := filepath.Dir(dbPath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	tmpPath := dbPath + ".restore-tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write restored database: %w", err)
+	}
+
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := keepCurrentDatabase(dbPath); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	// A leftover log with no database must not be replayed onto the restored one
+	for _, suffix := range databaseSidecars {
+		os.Remove(dbPath + suffix)
+	}
+
+	if err := os.Rename(tmpPath, dbPath); err != nil {
+		return fmt.Errorf("failed to move restored database into place: %w", err)
+	}
+	return nil
+}
+
+// databaseSidecars are the files SQLite keeps next to a database: the
+// write-ahead log, its shared-memory index and the rollback journal
+var databaseSidecars = []string{"-wal", "-shm", "-journal"}
+
+// keepCurrentDatabase moves the database at dbPath to dbPath.before-restore.
+// The write-ahead log is checkpointed first, and any log files left over are
+// moved along with the database so the kept copy loses no committed writes.
+func keepCurrentDatabase(dbPath string) error {
+	if err := checkpointDatabase(dbPath); err != nil {
+		return fmt.Errorf("failed to keep the current database: %w", err)
+	}
+
+	keepPath := dbPath + ".before-restore"
+	for _, suffix := range databaseSidecars {
+		os.Remove(keepPath + suffix)
+	}
+	if err := os.Rename(dbPath, keepPath); err != nil {
+		return fmt.Errorf("failed to keep the current database: %w", err)
+	}
+	for _, suffix := range databaseSidecars {
+		if err := os.Rename(dbPath+suffix, keepPath+suffix); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to keep the current database log: %w", err)
+		}
+	}
+	return nil
+}
+
+// snapshotDatabase returns a consistent copy of the database at dbPath. SQLite
+// writes it inside a read transaction, so a concurrent write is either wholly
+// in the copy or not at all - unlike copying the file, which can tear.
+func snapshotDatabase(dbPath string) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "linkedin-backup-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database for backup: %w", err)
+	}
+	defer conn.Close()
+	conn.SetMaxOpenConns(1) // The busy timeout is per connection
+
+	// Wait out a writer's commit rather than failing on a locked database
+	if _, err := conn.Exec(`PRAGMA busy_timeout = 5000`); err != nil {
+		return nil, fmt.Errorf("failed to set busy timeout: %w", err)
+	}
+
+	snapshotPath := filepath.Join(tmpDir, "snapshot.db")
+	if _, err := conn.Exec(`VACUUM INTO ?`, snapshotPath); err != nil {
+		return nil, fmt.Errorf("failed to snapshot database: %w", err)
+	}
+
+	data, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// checkpointDatabase flushes the write-ahead log of the database at dbPath into the main file
+func checkpointDatabase(dbPath string) error {
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database for checkpoint: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return fmt.Errorf("failed to checkpoint database: %w", err)
+	}
+	return nil
+}
+
+// encryptBackup seals data with a key derived from passphrase
+func encryptBackup(data []byte, passphrase string) ([]byte, error) {
+	header := make([]byte, backupHeaderLen)
+	copy(header, backupMagic)
+	params := header[len(backupMagic):]
+	params[0], params[1], params[2] = backupLogN, backupR, backupP
+
+	salt := header[len(backupMagic)+3 : len(backupMagic)+3+backupSaltLen]
+	nonce := header[len(backupMagic)+3+backupSaltLen:]
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	gcm, err := backupCipher(passphrase, salt, backupLogN, backupR, backupP)
+	if err != nil {
+		return nil, err
+	}
+	return append(header, gcm.Seal(nil, nonce, data, header)...), nil
+}
+
+// decryptBackup opens a sealed backup, returning ErrBackupDecrypt when the
+// passphrase doesn't fit or the file was altered
+func decryptBackup(sealed []byte, passphrase string) ([]byte, error) {
+	if len(sealed) < backupHeaderLen || string(sealed[:len(backupMagic)]) != backupMagic {
+		return nil, fmt.Errorf("not an encrypted backup file")
+	}
+
+	header := sealed[:backupHeaderLen]
+	params := header[len(backupMagic):]
+	logN, r, p := int(params[0]), int(params[1]), int(params[2])
+	// Bounded so a crafted header can't demand gigabytes of memory
+	if logN < 10 || logN > 20 || r < 1 || r > 16 || p < 1 || p > 4 {
+		return nil, fmt.Errorf("unsupported backup key parameters")
+	}
+
+	salt := header[len(backupMagic)+3 : len(backupMagic)+3+backupSaltLen]
+	nonce := header[len(backupMagic)+3+backupSaltLen:]
+
+	gcm, err := backupCipher(passphrase, salt, logN, r, p)
+	if err != nil {
+		return nil, err
+	}
+	data, err := gcm.Open(nil, nonce, sealed[backupHeaderLen:], header)
+	if err != nil {
+		return nil, ErrBackupDecrypt
+	}
+	return data, nil
+}
+
+// backupCipher returns the AES-256-GCM cipher keyed from passphrase and salt
+func backupCipher(passphrase string, salt []byte, logN, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<logN, r, p, backupKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive backup key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
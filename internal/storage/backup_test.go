@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// backupTestDB creates a database at dir/linkedin.db holding one profile
+func backupTestDB(t *testing.T, dir string) string {
+	t.Helper()
+	dbPath := filepath.Join(dir, "linkedin.db")
+	db, err := InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	profile := Profile{ID: "jane-doe", Name: "Jane Doe", ProfileURL: "https://www.linkedin.com/in/jane-doe", VisitedAt: time.Now(), CreatedAt: time.Now()}
+	if err := db.SaveProfile(profile); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+	return dbPath
+}
+
+func TestBackupEncryptedRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := backupTestDB(t, dir)
+	backupPath := filepath.Join(dir, "backups", "linkedin.db.enc")
+
+	if err := BackupEncrypted(dbPath, backupPath, "correct horse battery staple"); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	// The backup must not carry the database in the clear
+	sealed, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	plain, _ := os.ReadFile(dbPath)
+	if len(sealed) <= len(plain) || string(sealed[:len(sqliteHeader)]) == sqliteHeader {
+		t.Error("Expected the backup to be encrypted")
+	}
+
+	restoredPath := filepath.Join(dir, "restored", "linkedin.db")
+	if err := RestoreEncrypted(backupPath, restoredPath, "correct horse battery staple"); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	restored, err := InitDB(restoredPath)
+	if err != nil {
+		t.Fatalf("Failed to open restored database: %v", err)
+	}
+	defer restored.Close()
+
+	profile, err := restored.GetProfile("jane-doe")
+	if err != nil || profile.Name != "Jane Doe" {
+		t.Errorf("Expected the profile in the restored database, got %+v (%v)", profile, err)
+	}
+}
+
+func TestRestoreEncryptedWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := backupTestDB(t, dir)
+	backupPath := filepath.Join(dir, "linkedin.db.enc")
+	if err := BackupEncrypted(dbPath, backupPath, "right passphrase"); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	restoredPath := filepath.Join(dir, "restored.db")
+	err := RestoreEncrypted(backupPath, restoredPath, "wrong passphrase")
+	if !errors.Is(err, ErrBackupDecrypt) {
+		t.Fatalf("Expected ErrBackupDecrypt, got %v", err)
+	}
+	if _, err := os.Stat(restoredPath); !os.IsNotExist(err) {
+		t.Error("Expected nothing written after a wrong passphrase")
+	}
+
+	// An altered byte is caught the same way
+	sealed, _ := os.ReadFile(backupPath)
+	sealed[len(sealed)-1] ^= 0xff
+	if err := os.WriteFile(backupPath, sealed, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreEncrypted(backupPath, restoredPath, "right passphrase"); !errors.Is(err, ErrBackupDecrypt) {
+		t.Errorf("Expected ErrBackupDecrypt for a tampered backup, got %v", err)
+	}
+}
+
+func TestRestoreEncryptedKeepsCurrentDatabase(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := backupTestDB(t, dir)
+	backupPath := filepath.Join(dir, "linkedin.db.enc")
+	if err := BackupEncrypted(dbPath, backupPath, "passphrase"); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	if err := RestoreEncrypted(backupPath, dbPath, "passphrase"); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if _, err := os.Stat(dbPath + ".before-restore"); err != nil {
+		t.Errorf("Expected the replaced database to be kept: %v", err)
+	}
+}
+
+func TestRestoreEncryptedKeepsUncheckpointedWrites(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := backupTestDB(t, dir)
+	backupPath := filepath.Join(dir, "linkedin.db.enc")
+	if err := BackupEncrypted(dbPath, backupPath, "passphrase"); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	// A write still sitting in the write-ahead log when the restore starts
+	db, err := InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.conn.Exec(`PRAGMA journal_mode=WAL`); err != nil {
+		t.Fatalf("Failed to enable WAL: %v", err)
+	}
+	late := Profile{ID: "john-roe", Name: "John Roe", ProfileURL: "https://www.linkedin.com/in/john-roe", VisitedAt: time.Now(), CreatedAt: time.Now()}
+	if err := db.SaveProfile(late); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+
+	if err := RestoreEncrypted(backupPath, dbPath, "passphrase"); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	kept, err := InitDB(dbPath + ".before-restore")
+	if err != nil {
+		t.Fatalf("Failed to open kept database: %v", err)
+	}
+	defer kept.Close()
+	if profile, err := kept.GetProfile("john-roe"); err != nil || profile.Name != "John Roe" {
+		t.Errorf("Expected the late write in the kept database, got %+v (%v)", profile, err)
+	}
+}
+
+func TestBackupEncryptedDuringWrite(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := backupTestDB(t, dir)
+
+	// A run in the middle of a write transaction when the backup starts
+	db, err := InitDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	tx, err := db.conn.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`INSERT INTO profiles (id, name, profile_url) VALUES ('john-roe', 'John Roe', 'https://www.linkedin.com/in/john-roe')`); err != nil {
+		t.Fatalf("Failed to write profile: %v", err)
+	}
+
+	backupPath := filepath.Join(dir, "linkedin.db.enc")
+	if err := BackupEncrypted(dbPath, backupPath, "passphrase"); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	restoredPath := filepath.Join(dir, "restored", "linkedin.db")
+	if err := RestoreEncrypted(backupPath, restoredPath, "passphrase"); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	restored, err := InitDB(restoredPath)
+	if err != nil {
+		t.Fatalf("Failed to open restored database: %v", err)
+	}
+	defer restored.Close()
+
+	// The committed state, without the write still in flight
+	if profile, err := restored.GetProfile("jane-doe"); err != nil || profile.Name != "Jane Doe" {
+		t.Errorf("Expected the committed profile in the backup, got %+v (%v)", profile, err)
+	}
+	if _, err := restored.GetProfile("john-roe"); err == nil {
+		t.Error("Expected the uncommitted profile to be left out of the backup")
+	}
+}
+
+func TestBackupEncryptedRequiresPassphrase(t *testing.T) {
+	dbPath := backupTestDB(t, t.TempDir())
+	if err := BackupEncrypted(dbPath, dbPath+".enc", ""); err == nil {
+		t.Error("Expected an empty passphrase to be rejected")
+	}
+}
+
+func TestRestoreEncryptedRejectsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := backupTestDB(t, dir)
+
+	// A plain database is not a backup
+	if err := RestoreEncrypted(dbPath, filepath.Join(dir, "restored.db"), "passphrase"); err == nil || errors.Is(err, ErrBackupDecrypt) {
+		t.Errorf("Expected a not-a-backup error, got %v", err)
+	}
+}
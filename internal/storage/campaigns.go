@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Campaign target statuses
+const (
+	TargetPending = "pending" // Not yet worked
+	TargetSent    = "sent"    // Message sent
+	TargetSkipped = "skipped" // Deliberately not messaged (e.g. already messaged or replied)
+	TargetFailed  = "failed"  // Can't be sent, or failed too many times; not retried
+)
+
+// Campaign is a messaging campaign with a fixed target list
+type Campaign struct {
+	ID         int64
+	Name       string
+	TemplateID string
+	CreatedAt  time.Time
+}
+
+// CampaignTarget is one profile in a campaign and its progress
+type CampaignTarget struct {
+	ID         int64
+	CampaignID int64
+	ProfileID  string
+	Status     string // 'pending', 'sent', 'skipped', 'failed'
+	Attempts   int    // Failed sends so far
+	UpdatedAt  time.Time
+}
+
+// CreateCampaign stores a campaign and its targets, returning the campaign ID.
+// Duplicate profile IDs are added once. Targets are worked in the given order.
+func (db *Database) CreateCampaign(name, templateID string, profileIDs []string) (int64, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`INSERT INTO campaigns (name, template_id, created_at) VALUES (?, ?, ?)`,
+		name, templateID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	campaignID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, profileID := range profileIDs {
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO campaign_targets (campaign_id, profile_id, status, updated_at)
+			VALUES (?, ?, ?, ?)
+		`, campaignID, profileID, TargetPending, time.Now())
+		if err != nil {
+			return 0, fmt.Errorf("failed to add campaign target %s: %w", profileID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return campaignID, nil
+}
+
+// GetCampaign retrieves a campaign by ID
+func (db *Database) GetCampaign(campaignID int64) (*Campaign, error) {
+	var campaign Campaign
+	err := db.conn.QueryRow(`SELECT id, name, template_id, created_at FROM campaigns WHERE id = ?`, campaignID).Scan(
+		&campaign.ID,
+		&campaign.Name,
+		&campaign.TemplateID,
+		&campaign.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+// NextPendingTarget returns the campaign's next unworked target after the
+// target afterID (0 to start from the first), or nil when there is none.
+// Walking forward lets a run move past targets it left pending.
+func (db *Database) NextPendingTarget(campaignID, afterID int64) (*CampaignTarget, error) {
+	query := `
+		SELECT id, campaign_id, profile_id, status, COALESCE(attempts, 0), updated_at
+		FROM campaign_targets
+		WHERE campaign_id = ? AND status = ? AND id > ?
+		ORDER BY id ASC
+		LIMIT 1
+	`
+
+	var target CampaignTarget
+	err := db.conn.QueryRow(query, campaignID, TargetPending, afterID).Scan(
+		&target.ID,
+		&target.CampaignID,
+		&target.ProfileID,
+		&target.Status,
+		&target.Attempts,
+		&target.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// MarkTargetDone records the outcome of working a target so it is not picked again
+func (db *Database) MarkTargetDone(targetID int64, status string) error {
+	if status == TargetPending {
+		return fmt.Errorf("cannot mark target done with status %q", status)
+	}
+
+	res, err := db.conn.Exec(`UPDATE campaign_targets SET status = ?, updated_at = ? WHERE id = ?`,
+		status, time.Now(), targetID)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("campaign target %d not found", targetID)
+	}
+
+	return nil
+}
+
+// RecordTargetFailure counts a failed send to a target. The target stays
+// pending for a later run until it has failed maxAttempts times, then it is
+// marked failed. Returns whether it was marked failed.
+func (db *Database) RecordTargetFailure(targetID int64, maxAttempts int) (bool, error) {
+	query := `
+		UPDATE campaign_targets SET
+			attempts = COALESCE(attempts, 0) + 1,
+			status = CASE WHEN ? > 0 AND COALESCE(attempts, 0) + 1 >= ? THEN ? ELSE status END,
+			updated_at = ?
+		WHERE id = ?
+	`
+	res, err := db.conn.Exec(query, maxAttempts, maxAttempts, TargetFailed, time.Now(), targetID)
+	if err != nil {
+		return false, err
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return false, fmt.Errorf("campaign target %d not found", targetID)
+	}
+
+	var status string
+	if err := db.conn.QueryRow(`SELECT status FROM campaign_targets WHERE id = ?`, targetID).Scan(&status); err != nil {
+		return false, err
+	}
+	return status == TargetFailed, nil
+}
+
+// GetCampaignProgress returns how many targets are in each status
+func (db *Database) GetCampaignProgress(campaignID int64) (map[string]int, error) {
+	rows, err := db.conn.Query(`
+		SELECT status, COUNT(*) FROM campaign_targets
+		WHERE campaign_id = ?
+		GROUP BY status
+	`, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		progress[status] = count
+	}
+
+	return progress, rows.Err()
+}
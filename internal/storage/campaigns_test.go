@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCampaignTargetProgression(t *testing.T) {
+	testDBPath := "./test_campaigns.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	campaignID, err := db.CreateCampaign("Q1 intros", "msg_introduction", []string{"alice", "bob", "alice", "carol"})
+	if err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+
+	campaign, err := db.GetCampaign(campaignID)
+	if err != nil {
+		t.Fatalf("Failed to get campaign: %v", err)
+	}
+	if campaign.Name != "Q1 intros" || campaign.TemplateID != "msg_introduction" {
+		t.Errorf("Unexpected campaign: %+v", campaign)
+	}
+
+	// Targets come back in insertion order, duplicates removed
+	expected := []string{"alice", "bob", "carol"}
+	outcomes := []string{TargetSent, TargetFailed, TargetSkipped}
+	for i, profileID := range expected {
+		target, err := db.NextPendingTarget(campaignID, 0)
+		if err != nil {
+			t.Fatalf("Failed to get next target: %v", err)
+		}
+		if target == nil {
+			t.Fatalf("Expected target %s, got none", profileID)
+		}
+		if target.ProfileID != profileID || target.Status != TargetPending {
+			t.Errorf("Expected pending target %s, got %+v", profileID, target)
+		}
+		if err := db.MarkTargetDone(target.ID, outcomes[i]); err != nil {
+			t.Fatalf("Failed to mark target done: %v", err)
+		}
+	}
+
+	target, err := db.NextPendingTarget(campaignID, 0)
+	if err != nil || target != nil {
+		t.Errorf("Expected no pending targets, got %+v (err %v)", target, err)
+	}
+
+	progress, err := db.GetCampaignProgress(campaignID)
+	if err != nil {
+		t.Fatalf("Failed to get progress: %v", err)
+	}
+	if progress[TargetSent] != 1 || progress[TargetFailed] != 1 || progress[TargetSkipped] != 1 || progress[TargetPending] != 0 {
+		t.Errorf("Unexpected progress: %v", progress)
+	}
+
+	if err := db.MarkTargetDone(anyTargetID(t, db, campaignID), TargetPending); err == nil {
+		t.Error("Expected marking a target back to pending to be rejected")
+	}
+	if err := db.MarkTargetDone(99999, TargetSent); err == nil {
+		t.Error("Expected an error for an unknown target")
+	}
+}
+
+// anyTargetID returns the ID of any target in the campaign
+func anyTargetID(t *testing.T, db *Database, campaignID int64) int64 {
+	var id int64
+	if err := db.conn.QueryRow(`SELECT id FROM campaign_targets WHERE campaign_id = ? LIMIT 1`, campaignID).Scan(&id); err != nil {
+		t.Fatalf("Failed to find a campaign target: %v", err)
+	}
+	return id
+}
+
+func TestCampaignProgressSurvivesRestart(t *testing.T) {
+	testDBPath := "./test_campaigns.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	campaignID, err := db.CreateCampaign("Follow-ups", "msg_follow_up", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+
+	target, err := db.NextPendingTarget(campaignID, 0)
+	if err != nil || target == nil {
+		t.Fatalf("Expected a pending target, got %+v (err %v)", target, err)
+	}
+	if err := db.MarkTargetDone(target.ID, TargetSent); err != nil {
+		t.Fatalf("Failed to mark target done: %v", err)
+	}
+	db.Close()
+
+	// Simulate a restart
+	db, err = InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	target, err = db.NextPendingTarget(campaignID, 0)
+	if err != nil || target == nil {
+		t.Fatalf("Expected a pending target after restart, got %+v (err %v)", target, err)
+	}
+	if target.ProfileID != "bob" {
+		t.Errorf("Expected to resume with bob, got %s", target.ProfileID)
+	}
+}
+
+func TestRecordTargetFailureRetriesThenFails(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "test_campaigns.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	campaignID, err := db.CreateCampaign("Retries", "msg_introduction", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+	alice, err := db.NextPendingTarget(campaignID, 0)
+	if err != nil || alice == nil {
+		t.Fatalf("Expected a pending target, got %+v (err %v)", alice, err)
+	}
+
+	// Stays pending below the limit; a run can walk past it to bob
+	for i := 1; i < 3; i++ {
+		failed, err := db.RecordTargetFailure(alice.ID, 3)
+		if err != nil || failed {
+			t.Fatalf("Attempt %d: expected alice still pending, got failed=%v (err %v)", i, failed, err)
+		}
+	}
+	if next, err := db.NextPendingTarget(campaignID, alice.ID); err != nil || next == nil || next.ProfileID != "bob" {
+		t.Errorf("Expected bob after alice, got %+v (err %v)", next, err)
+	}
+	if again, err := db.NextPendingTarget(campaignID, 0); err != nil || again == nil || again.ID != alice.ID || again.Attempts != 2 {
+		t.Errorf("Expected alice pending with 2 attempts, got %+v (err %v)", again, err)
+	}
+
+	// The last allowed attempt marks it failed
+	if failed, err := db.RecordTargetFailure(alice.ID, 3); err != nil || !failed {
+		t.Errorf("Expected alice marked failed, got failed=%v (err %v)", failed, err)
+	}
+	if progress, err := db.GetCampaignProgress(campaignID); err != nil || progress[TargetFailed] != 1 || progress[TargetPending] != 1 {
+		t.Errorf("Unexpected progress: %v (err %v)", progress, err)
+	}
+
+	if _, err := db.RecordTargetFailure(99999, 3); err == nil {
+		t.Error("Expected an error for an unknown target")
+	}
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// requiredLinkedInCookies must be in an imported jar for the session to work
+var requiredLinkedInCookies = []string{"li_at"}
+
+// httpOnlyPrefix marks an HttpOnly cookie line in a Netscape cookie jar (as
+// written by curl and most browser export extensions); it is not a comment
+const httpOnlyPrefix = "#HttpOnly_"
+
+// ImportNetscapeCookies reads a cookies.txt file in the Netscape format and
+// returns its linkedin.com cookies, ready for browser.SetCookies via
+// proto.CookiesToParams. Fails if a required cookie (li_at) is missing or expired.
+func ImportNetscapeCookies(path string) ([]*proto.NetworkCookie, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cookie file: %w", err)
+	}
+	defer file.Close()
+
+	return parseNetscapeCookies(file, time.Now())
+}
+
+// parseNetscapeCookies parses a Netscape cookie jar, keeping linkedin.com
+// cookies and checking the required ones are present and unexpired at now
+func parseNetscapeCookies(r io.Reader, now time.Time) ([]*proto.NetworkCookie, error) {
+	var cookies []*proto.NetworkCookie
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(line, httpOnlyPrefix); ok {
+			line = rest
+			httpOnly = true
+		} else if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		cookie, err := parseNetscapeCookieLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("cookie file line %d: %w", lineNumber, err)
+		}
+		cookie.HTTPOnly = httpOnly
+
+		if isLinkedInDomain(cookie.Domain) {
+			cookies = append(cookies, cookie)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cookie file: %w", err)
+	}
+
+	if err := checkRequiredCookies(cookies, now); err != nil {
+		return nil, err
+	}
+	return cookies, nil
+}
+
+// parseNetscapeCookieLine parses the seven tab-separated fields of a cookie:
+// domain, include subdomains, path, secure, expiry (Unix seconds, 0 for a
+// session cookie), name and value
+func parseNetscapeCookieLine(line string) (*proto.NetworkCookie, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != 7 {
+		return nil, fmt.Errorf("expected 7 tab-separated fields, got %d", len(fields))
+	}
+
+	expiry, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiry %q", fields[4])
+	}
+
+	cookie := &proto.NetworkCookie{
+		Domain: fields[0],
+		Path:   fields[2],
+		Secure: strings.EqualFold(fields[3], "TRUE"),
+		Name:   fields[5],
+		Value:  fields[6],
+	}
+	if expiry == 0 {
+		cookie.Session = true
+	} else {
+		cookie.Expires = proto.TimeSinceEpoch(expiry)
+	}
+	return cookie, nil
+}
+
+// isLinkedInDomain reports whether a cookie domain belongs to linkedin.com
+func isLinkedInDomain(domain string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	return domain == "linkedin.com" || strings.HasSuffix(domain, ".linkedin.com")
+}
+
+// checkRequiredCookies verifies every required cookie is present and, unless
+// it is a session cookie, expires after now
+func checkRequiredCookies(cookies []*proto.NetworkCookie, now time.Time) error {
+	for _, name := range requiredLinkedInCookies {
+		var found *proto.NetworkCookie
+		for _, cookie := range cookies {
+			if cookie.Name == name {
+				found = cookie
+				break
+			}
+		}
+
+		if found == nil {
+			return fmt.Errorf("cookie file has no %s cookie for linkedin.com", name)
+		}
+		if !found.Session && !found.Expires.Time().After(now) {
+			return fmt.Errorf("%s cookie expired on %s", name, found.Expires.Time().Format("2006-01-02"))
+		}
+	}
+	return nil
+}
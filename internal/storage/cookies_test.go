@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sampleCookieJar is a cookies.txt as exported by a browser extension
+const sampleCookieJar = `# Netscape HTTP Cookie File
+# https://curl.se/docs/http-cookies.html
+# This file was generated by an extension! Edit at your own risk.
+
+#HttpOnly_.www.linkedin.com	TRUE	/	TRUE	1893456000	li_at	AQEDAR-token
+.linkedin.com	TRUE	/	TRUE	1893456000	JSESSIONID	"ajax:123"
+.linkedin.com	TRUE	/	FALSE	0	lang	v=2&lang=en-us
+.example.com	TRUE	/	FALSE	1893456000	tracker	abc
+#HttpOnly_.google.com	TRUE	/	TRUE	1893456000	SID	xyz
+`
+
+func TestParseNetscapeCookies(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cookies, err := parseNetscapeCookies(strings.NewReader(sampleCookieJar), now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(cookies) != 3 {
+		t.Fatalf("Expected 3 linkedin.com cookies, got %d", len(cookies))
+	}
+
+	liAt := cookies[0]
+	if liAt.Name != "li_at" || liAt.Value != "AQEDAR-token" || liAt.Domain != ".www.linkedin.com" || liAt.Path != "/" {
+		t.Errorf("Unexpected li_at cookie: %+v", liAt)
+	}
+	if !liAt.HTTPOnly || !liAt.Secure || liAt.Session {
+		t.Errorf("Expected li_at to be HttpOnly, secure and persistent: %+v", liAt)
+	}
+	if got := liAt.Expires.Time().UTC(); !got.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected li_at to expire on 2030-01-01, got %s", got)
+	}
+
+	if cookies[1].HTTPOnly || cookies[1].Value != `"ajax:123"` {
+		t.Errorf("Unexpected JSESSIONID cookie: %+v", cookies[1])
+	}
+	if !cookies[2].Session || cookies[2].Secure || cookies[2].Expires != 0 {
+		t.Errorf("Expected lang to be an insecure session cookie: %+v", cookies[2])
+	}
+}
+
+func TestParseNetscapeCookiesErrors(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		jar     string
+		wantErr string
+	}{
+		{"Missing li_at", ".linkedin.com\tTRUE\t/\tTRUE\t1893456000\tJSESSIONID\tx\n", "no li_at cookie"},
+		{"li_at for another domain", ".example.com\tTRUE\t/\tTRUE\t1893456000\tli_at\tx\n", "no li_at cookie"},
+		{"Expired li_at", "#HttpOnly_.linkedin.com\tTRUE\t/\tTRUE\t1700000000\tli_at\tx\n", "li_at cookie expired on 2023-11-14"},
+		{"Too few fields", ".linkedin.com\tTRUE\t/\tTRUE\t1893456000\tli_at\n", "line 1: expected 7 tab-separated fields, got 6"},
+		{"Bad expiry", "# comment\n.linkedin.com\tTRUE\t/\tTRUE\tnever\tli_at\tx\n", "line 2: invalid expiry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseNetscapeCookies(strings.NewReader(tt.jar), now)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseNetscapeCookiesSessionLiAt(t *testing.T) {
+	jar := "#HttpOnly_.linkedin.com\tTRUE\t/\tTRUE\t0\tli_at\tx\r\n"
+	cookies, err := parseNetscapeCookies(strings.NewReader(jar), time.Now())
+	if err != nil {
+		t.Fatalf("A session li_at cookie should be accepted: %v", err)
+	}
+	if len(cookies) != 1 || cookies[0].Value != "x" {
+		t.Errorf("Expected the CRLF to be trimmed from the value, got %+v", cookies)
+	}
+}
+
+func TestImportNetscapeCookies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	if err := os.WriteFile(path, []byte(sampleCookieJar), 0600); err != nil {
+		t.Fatalf("Failed to write cookie file: %v", err)
+	}
+
+	cookies, err := ImportNetscapeCookies(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cookies) != 3 {
+		t.Errorf("Expected 3 cookies, got %d", len(cookies))
+	}
+
+	if _, err := ImportNetscapeCookies(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
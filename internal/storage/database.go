@@ -3,6 +3,8 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -23,16 +25,36 @@ type Profile struct {
 	ProfileURL string
 	VisitedAt  time.Time
 	CreatedAt  time.Time
+
+	MutualCount int  // Mutual connections shown on the search card (0 = none or unknown)
+	OpenToWork  bool // The search card showed the #OpenToWork photo frame
+
+	// Where the profile was first found: "search", or "group:<url>" / "event:<url>"
+	// for a scraped member or attendee list ("" for profiles saved before it was recorded)
+	ContextSource string
+
+	ConnectAttempts int  // Connection requests attempted on the profile (see RecordConnectAttempt)
+	FailedPermanent bool // Failed too often; GetRecentProfiles no longer selects it
 }
 
+// Where a connection_requests row came from. Observed rows stand for an
+// invitation seen on a profile rather than one the bot sent, so they are
+// left out of the counts behind the sending limits and the acceptance rate.
+const (
+	RequestSourceSent     = "sent"
+	RequestSourceObserved = "observed"
+)
+
 // ConnectionRequest tracks sent connection requests
 type ConnectionRequest struct {
-	ID        int
-	ProfileID string
-	SentAt    time.Time
-	NoteUsed  string
-	Status    string // 'pending', 'accepted', 'rejected', 'withdrawn'
-	CreatedAt time.Time
+	ID           int
+	ProfileID    string
+	SentAt       time.Time
+	NoteUsed     string
+	TemplateID   string // Template or note pool entry used for the note
+	EvidencePath string // Screenshot of the sent request (AUDIT_SCREENSHOTS mode)
+	Status       string // 'pending', 'accepted', 'rejected', 'withdrawn'
+	CreatedAt    time.Time
 }
 
 // Message tracks sent messages to connections
@@ -54,8 +76,14 @@ type RateLimit struct {
 	LastUpdated     time.Time
 }
 
-// InitDB creates a new database connection and initializes tables
+// InitDB creates a new database connection and initializes tables.
+// The database file's directory is created if it does not exist yet.
 func InitDB(dbPath string) (*Database, error) {
+	// Ensure the database directory exists (sqlite won't create it)
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
 	conn, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -73,6 +101,11 @@ func InitDB(dbPath string) (*Database, error) {
 		return nil, fmt.Errorf("failed to create tables: %w", err)
 	}
 
+	// Upgrade databases created by older versions
+	if err := db.migrateTables(); err != nil {
+		return nil, fmt.Errorf("failed to migrate tables: %w", err)
+	}
+
 	return db, nil
 }
 
@@ -88,6 +121,12 @@ func (db *Database) createTables() error {
 		location TEXT,
 		profile_url TEXT NOT NULL UNIQUE,
 		visited_at DATETIME,
+		mutual_count INTEGER DEFAULT 0,
+		open_to_work BOOLEAN DEFAULT 0,
+		context_source TEXT,
+		connect_attempts INTEGER DEFAULT 0,
+		last_attempt_at DATETIME,
+		failed_permanent BOOLEAN DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
@@ -97,8 +136,12 @@ func (db *Database) createTables() error {
 		profile_id TEXT NOT NULL,
 		sent_at DATETIME NOT NULL,
 		note_used TEXT,
+		template_id TEXT,
+		evidence_path TEXT,
 		status TEXT DEFAULT 'pending',
 		has_replied BOOLEAN DEFAULT 0,
+		accepted_at DATETIME,
+		source TEXT DEFAULT 'sent',
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (profile_id) REFERENCES profiles(id)
 	);
@@ -122,18 +165,191 @@ func (db *Database) createTables() error {
 		last_updated DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
+	-- Search runs table: one row per executed search, keyed by a hash of its filters
+	CREATE TABLE IF NOT EXISTS search_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		search_hash TEXT NOT NULL,
+		run_date TEXT NOT NULL,
+		run_at DATETIME NOT NULL
+	);
+
+	-- Daily snapshots table: end-of-run outcome metrics, one row per date
+	CREATE TABLE IF NOT EXISTS daily_snapshots (
+		date TEXT PRIMARY KEY,
+		connections_sent INTEGER DEFAULT 0,
+		accepted INTEGER DEFAULT 0,
+		messages_sent INTEGER DEFAULT 0,
+		replies INTEGER DEFAULT 0,
+		errors INTEGER DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Campaigns table: a messaging campaign defined once and worked through over many runs
+	CREATE TABLE IF NOT EXISTS campaigns (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		template_id TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Campaign targets table: durable per-profile progress of a campaign
+	CREATE TABLE IF NOT EXISTS campaign_targets (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		campaign_id INTEGER NOT NULL,
+		profile_id TEXT NOT NULL,
+		status TEXT DEFAULT 'pending',
+		attempts INTEGER DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (campaign_id, profile_id),
+		FOREIGN KEY (campaign_id) REFERENCES campaigns(id)
+	);
+
+	-- Sequences table: a connection request linked to the message to send once it is accepted
+	CREATE TABLE IF NOT EXISTS sequences (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		profile_id TEXT NOT NULL UNIQUE,
+		connection_template_id TEXT,
+		message_template_id TEXT NOT NULL,
+		status TEXT DEFAULT 'waiting',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Scheduled messages table: messages queued to be sent at or after send_after
+	CREATE TABLE IF NOT EXISTS scheduled_messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		profile_id TEXT NOT NULL,
+		template_id TEXT NOT NULL,
+		send_after DATETIME NOT NULL,
+		status TEXT DEFAULT 'pending',
+		sequence_id INTEGER,
+		attempts INTEGER DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (sequence_id) REFERENCES sequences(id)
+	);
+
+	-- Audit log table: chronological record of every browser action and its result
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		ts DATETIME NOT NULL,
+		action TEXT NOT NULL,
+		profile_id TEXT,
+		result TEXT NOT NULL,
+		detail TEXT
+	);
+
+	-- Dismissed suggestions table: "People you may know" cards dismissed so they are not reconsidered
+	CREATE TABLE IF NOT EXISTS dismissed_suggestions (
+		profile_id TEXT PRIMARY KEY,
+		dismissed_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Template rotations table: next position of each template rotation, kept across restarts
+	CREATE TABLE IF NOT EXISTS template_rotations (
+		name TEXT PRIMARY KEY,
+		next_index INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Geo cache table: geoUrn IDs resolved for locations missing from utils.LinkedInLocations
+	CREATE TABLE IF NOT EXISTS geo_cache (
+		name TEXT PRIMARY KEY,
+		urn TEXT NOT NULL,
+		resolved_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Visibility probes table: signals and risk score of each shadow-limit probe, for trends
+	CREATE TABLE IF NOT EXISTS visibility_probes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		probed_at DATETIME NOT NULL,
+		dashboard_read INTEGER DEFAULT 0,
+		profile_views INTEGER DEFAULT 0,
+		search_appearances INTEGER DEFAULT 0,
+		invites_checked INTEGER DEFAULT 0,
+		invites_missing INTEGER DEFAULT 0,
+		risk_score INTEGER DEFAULT 0,
+		risk_level TEXT NOT NULL
+	);
+
 	-- Indexes for better query performance
 	CREATE INDEX IF NOT EXISTS idx_profiles_visited ON profiles(visited_at);
 	CREATE INDEX IF NOT EXISTS idx_connection_requests_profile ON connection_requests(profile_id);
 	CREATE INDEX IF NOT EXISTS idx_connection_requests_sent ON connection_requests(sent_at);
 	CREATE INDEX IF NOT EXISTS idx_messages_connection ON messages(connection_id);
 	CREATE INDEX IF NOT EXISTS idx_messages_sent ON messages(sent_at);
+	CREATE INDEX IF NOT EXISTS idx_search_runs_hash_date ON search_runs(search_hash, run_date);
+	CREATE INDEX IF NOT EXISTS idx_campaign_targets_status ON campaign_targets(campaign_id, status);
+	CREATE INDEX IF NOT EXISTS idx_scheduled_messages_due ON scheduled_messages(status, send_after);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_ts ON audit_log(ts);
 	`
 
 	_, err := db.conn.Exec(schema)
 	return err
 }
 
+// migrateTables adds columns introduced after the initial schema to existing databases
+func (db *Database) migrateTables() error {
+	migrations := []struct {
+		table      string
+		column     string
+		definition string
+	}{
+		{"connection_requests", "template_id", "TEXT"},
+		{"connection_requests", "evidence_path", "TEXT"},
+		{"connection_requests", "accepted_at", "DATETIME"},
+		{"connection_requests", "source", "TEXT DEFAULT 'sent'"},
+		{"profiles", "mutual_count", "INTEGER DEFAULT 0"},
+		{"profiles", "open_to_work", "BOOLEAN DEFAULT 0"},
+		{"profiles", "context_source", "TEXT"},
+		{"profiles", "connect_attempts", "INTEGER DEFAULT 0"},
+		{"profiles", "last_attempt_at", "DATETIME"},
+		{"profiles", "failed_permanent", "BOOLEAN DEFAULT 0"},
+		{"campaign_targets", "attempts", "INTEGER DEFAULT 0"},
+		{"scheduled_messages", "attempts", "INTEGER DEFAULT 0"},
+	}
+
+	for _, m := range migrations {
+		if err := db.addColumnIfMissing(m.table, m.column, m.definition); err != nil {
+			return fmt.Errorf("failed to add %s.%s: %w", m.table, m.column, err)
+		}
+	}
+
+	return nil
+}
+
+// addColumnIfMissing adds a column to a table unless it already exists
+func (db *Database) addColumnIfMissing(table, column, definition string) error {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
 // Close closes the database connection
 func (db *Database) Close() error {
 	if db.conn != nil {
@@ -147,14 +363,19 @@ func (db *Database) Close() error {
 // SaveProfile saves a profile to the database
 func (db *Database) SaveProfile(profile Profile) error {
 	query := `
-		INSERT INTO profiles (id, name, title, company, location, profile_url, visited_at, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO profiles (id, name, title, company, location, profile_url, visited_at, created_at, mutual_count, open_to_work, context_source)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			name = excluded.name,
 			title = excluded.title,
 			company = excluded.company,
 			location = excluded.location,
-			visited_at = excluded.visited_at
+			visited_at = excluded.visited_at,
+			-- Saves that don't know the count or the badge (e.g. from My Network) keep what was recorded
+			mutual_count = CASE WHEN excluded.mutual_count > 0 THEN excluded.mutual_count ELSE profiles.mutual_count END,
+			open_to_work = CASE WHEN excluded.open_to_work THEN 1 ELSE profiles.open_to_work END,
+			-- The origin is where the profile was first found
+			context_source = COALESCE(NULLIF(profiles.context_source, ''), excluded.context_source)
 	`
 
 	_, err := db.conn.Exec(query,
@@ -166,11 +387,42 @@ func (db *Database) SaveProfile(profile Profile) error {
 		profile.ProfileURL,
 		profile.VisitedAt,
 		profile.CreatedAt,
+		profile.MutualCount,
+		profile.OpenToWork,
+		profile.ContextSource,
 	)
 
 	return err
 }
 
+// RecordConnectAttempt counts a connection attempt on a profile and stamps
+// last_attempt_at. A failed attempt that brings the count to maxAttempts
+// (when > 0) marks the profile failed_permanent, so GetRecentProfiles stops
+// selecting a profile that fails every run (private, out of network).
+// Returns whether the profile is marked.
+func (db *Database) RecordConnectAttempt(profileID string, failed bool, maxAttempts int) (bool, error) {
+	query := `
+		UPDATE profiles SET
+			connect_attempts = COALESCE(connect_attempts, 0) + 1,
+			last_attempt_at = ?,
+			failed_permanent = CASE
+				WHEN ? AND ? > 0 AND COALESCE(connect_attempts, 0) + 1 >= ? THEN 1
+				ELSE COALESCE(failed_permanent, 0)
+			END
+		WHERE id = ?
+	`
+	if _, err := db.conn.Exec(query, time.Now(), failed, maxAttempts, maxAttempts, profileID); err != nil {
+		return false, err
+	}
+
+	var permanent bool
+	err := db.conn.QueryRow(`SELECT COALESCE(failed_permanent, 0) FROM profiles WHERE id = ?`, profileID).Scan(&permanent)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return permanent, err
+}
+
 // IsDuplicateProfile checks if a profile was visited recently (within 30 days)
 func (db *Database) IsDuplicateProfile(profileID string, daysSince int) (bool, error) {
 	query := `
@@ -190,7 +442,8 @@ func (db *Database) IsDuplicateProfile(profileID string, daysSince int) (bool, e
 // GetProfile retrieves a profile by ID
 func (db *Database) GetProfile(profileID string) (*Profile, error) {
 	query := `
-		SELECT id, name, title, company, location, profile_url, visited_at, created_at
+		SELECT id, name, title, company, location, profile_url, visited_at, created_at, COALESCE(mutual_count, 0), COALESCE(open_to_work, 0), COALESCE(context_source, ''),
+			COALESCE(connect_attempts, 0), COALESCE(failed_permanent, 0)
 		FROM profiles WHERE id = ?
 	`
 
@@ -204,6 +457,11 @@ func (db *Database) GetProfile(profileID string) (*Profile, error) {
 		&profile.ProfileURL,
 		&profile.VisitedAt,
 		&profile.CreatedAt,
+		&profile.MutualCount,
+		&profile.OpenToWork,
+		&profile.ContextSource,
+		&profile.ConnectAttempts,
+		&profile.FailedPermanent,
 	)
 
 	if err != nil {
@@ -218,14 +476,16 @@ func (db *Database) GetProfile(profileID string) (*Profile, error) {
 // SaveConnectionRequest records a sent connection request
 func (db *Database) SaveConnectionRequest(req ConnectionRequest) error {
 	query := `
-		INSERT INTO connection_requests (profile_id, sent_at, note_used, status, created_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO connection_requests (profile_id, sent_at, note_used, template_id, evidence_path, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := db.conn.Exec(query,
 		req.ProfileID,
 		req.SentAt,
 		req.NoteUsed,
+		req.TemplateID,
+		req.EvidencePath,
 		req.Status,
 		req.CreatedAt,
 	)
@@ -233,22 +493,23 @@ func (db *Database) SaveConnectionRequest(req ConnectionRequest) error {
 	return err
 }
 
-// UpdateConnectionStatus updates the status of a connection request
+// UpdateConnectionStatus updates the status of a connection request,
+// recording accepted_at when it flips to accepted
 func (db *Database) UpdateConnectionStatus(profileID, status string) error {
 	query := `
 		UPDATE connection_requests
-		SET status = ?
+		SET status = ?, accepted_at = CASE WHEN ? = 'accepted' THEN ? ELSE accepted_at END
 		WHERE profile_id = ? AND status = 'pending'
 	`
 
-	_, err := db.conn.Exec(query, status, profileID)
+	_, err := db.conn.Exec(query, status, status, time.Now(), profileID)
 	return err
 }
 
 // GetPendingConnections retrieves all pending connection requests
 func (db *Database) GetPendingConnections() ([]ConnectionRequest, error) {
 	query := `
-		SELECT id, profile_id, sent_at, note_used, status, created_at
+		SELECT id, profile_id, sent_at, note_used, COALESCE(template_id, ''), COALESCE(evidence_path, ''), status, created_at
 		FROM connection_requests
 		WHERE status = 'pending'
 		ORDER BY sent_at DESC
@@ -268,6 +529,8 @@ func (db *Database) GetPendingConnections() ([]ConnectionRequest, error) {
 			&req.ProfileID,
 			&req.SentAt,
 			&req.NoteUsed,
+			&req.TemplateID,
+			&req.EvidencePath,
 			&req.Status,
 			&req.CreatedAt,
 		)
@@ -280,6 +543,70 @@ func (db *Database) GetPendingConnections() ([]ConnectionRequest, error) {
 	return requests, nil
 }
 
+// CountTotalConnectionsSent counts every connection request ever sent from this database
+func (db *Database) CountTotalConnectionsSent() (int, error) {
+	query := `SELECT COUNT(*) FROM connection_requests WHERE source IS NOT ?`
+
+	var count int
+	if err := db.conn.QueryRow(query, RequestSourceObserved).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountConnectionsToCompanyToday counts connection requests sent today to
+// profiles at the given company (case-insensitive)
+func (db *Database) CountConnectionsToCompanyToday(company string) (int, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	query := `
+		SELECT COUNT(*)
+		FROM connection_requests cr
+		INNER JOIN profiles p ON cr.profile_id = p.id
+		WHERE LOWER(TRIM(p.company)) = LOWER(TRIM(?))
+		AND datetime(cr.sent_at) >= datetime(?)
+		AND cr.source IS NOT ?
+	`
+
+	var count int
+	err := db.conn.QueryRow(query, company, startOfDay, RequestSourceObserved).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetRecentAcceptanceRate returns the share of connection requests sent in the
+// last `days` days that were accepted, along with the number of requests (the
+// sample size). Requests younger than minAgeDays are left out - they have not
+// had time to be accepted yet. The rate is 0 when no requests qualify.
+func (db *Database) GetRecentAcceptanceRate(days, minAgeDays int) (float64, int, error) {
+	now := time.Now()
+	since := now.AddDate(0, 0, -days)
+	until := now.AddDate(0, 0, -minAgeDays)
+
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN status = 'accepted' THEN 1 ELSE 0 END), 0)
+		FROM connection_requests
+		WHERE datetime(sent_at) >= datetime(?)
+		AND datetime(sent_at) <= datetime(?)
+		AND source IS NOT ?
+	`
+
+	var total, accepted int
+	if err := db.conn.QueryRow(query, since, until, RequestSourceObserved).Scan(&total, &accepted); err != nil {
+		return 0, 0, err
+	}
+
+	if total == 0 {
+		return 0, 0, nil
+	}
+
+	return float64(accepted) / float64(total), total, nil
+}
+
 // HasSentConnectionRequest checks if a connection request was already sent to a profile
 func (db *Database) HasSentConnectionRequest(profileID string) (bool, error) {
 	query := `
@@ -296,6 +623,30 @@ func (db *Database) HasSentConnectionRequest(profileID string) (bool, error) {
 	return count > 0, nil
 }
 
+// RecordPendingInvitation saves a pending request for an invitation seen on the
+// profile (e.g. one sent by hand) unless one is already pending. Reports whether
+// a request was added. The row is marked observed, as the bot did not send it.
+func (db *Database) RecordPendingInvitation(profileID string, seenAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO connection_requests (profile_id, sent_at, note_used, template_id, evidence_path, status, source, created_at)
+		SELECT ?, ?, '', '', '', 'pending', ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM connection_requests WHERE profile_id = ? AND status = 'pending'
+		)
+	`
+
+	result, err := db.conn.Exec(query, profileID, seenAt, RequestSourceObserved, seenAt, profileID)
+	if err != nil {
+		return false, err
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return added > 0, nil
+}
+
 // --- Message Operations ---
 
 // SaveMessage records a sent message
@@ -415,63 +766,92 @@ func (db *Database) GetTodayRateLimit() (*RateLimit, error) {
 	return &limit, nil
 }
 
+// Daily counters of the rate_limits table, for IncrementDailyCount
+const (
+	CounterConnections = "connection_count"
+	CounterMessages    = "message_count"
+	CounterSearches    = "search_count"
+)
+
 // IncrementConnectionCount increments today's connection request count
 func (db *Database) IncrementConnectionCount() error {
-	today := time.Now().Format("2006-01-02")
+	return db.IncrementDailyCount(time.Now().Format("2006-01-02"), CounterConnections)
+}
 
-	query := `
+// IncrementMessageCount increments today's message count
+func (db *Database) IncrementMessageCount() error {
+	return db.IncrementDailyCount(time.Now().Format("2006-01-02"), CounterMessages)
+}
+
+// IncrementSearchCount increments today's search count
+func (db *Database) IncrementSearchCount() error {
+	return db.IncrementDailyCount(time.Now().Format("2006-01-02"), CounterSearches)
+}
+
+// IncrementDailyCount increments counter (one of the Counter* columns) in the
+// rate_limits row of date (YYYY-MM-DD), creating the row if needed
+func (db *Database) IncrementDailyCount(date, counter string) error {
+	initial := map[string]int{CounterConnections: 0, CounterMessages: 0, CounterSearches: 0}
+	if _, ok := initial[counter]; !ok {
+		return fmt.Errorf("unknown rate limit counter: %s", counter)
+	}
+	initial[counter] = 1
+
+	query := fmt.Sprintf(`
 		INSERT INTO rate_limits (date, connection_count, message_count, search_count, last_updated)
-		VALUES (?, 1, 0, 0, ?)
+		VALUES (?, ?, ?, ?, ?)
 		ON CONFLICT(date) DO UPDATE SET
-			connection_count = connection_count + 1,
+			%[1]s = %[1]s + 1,
 			last_updated = ?
-	`
+	`, counter)
 
 	now := time.Now()
-	_, err := db.conn.Exec(query, today, now, now)
+	_, err := db.conn.Exec(query, date, initial[CounterConnections], initial[CounterMessages], initial[CounterSearches], now, now)
 	return err
 }
 
-// IncrementMessageCount increments today's message count
-func (db *Database) IncrementMessageCount() error {
-	today := time.Now().Format("2006-01-02")
+// --- Search Run Operations ---
+
+// RecordSearchRun records that the search identified by searchHash ran now
+func (db *Database) RecordSearchRun(searchHash string) error {
+	now := time.Now()
 
 	query := `
-		INSERT INTO rate_limits (date, connection_count, message_count, search_count, last_updated)
-		VALUES (?, 0, 1, 0, ?)
-		ON CONFLICT(date) DO UPDATE SET
-			message_count = message_count + 1,
-			last_updated = ?
+		INSERT INTO search_runs (search_hash, run_date, run_at)
+		VALUES (?, ?, ?)
 	`
 
-	now := time.Now()
-	_, err := db.conn.Exec(query, today, now, now)
+	_, err := db.conn.Exec(query, searchHash, now.Format("2006-01-02"), now)
 	return err
 }
 
-// IncrementSearchCount increments today's search count
-func (db *Database) IncrementSearchCount() error {
+// HasRunSearchToday checks if the search identified by searchHash already ran today
+func (db *Database) HasRunSearchToday(searchHash string) (bool, error) {
 	today := time.Now().Format("2006-01-02")
 
 	query := `
-		INSERT INTO rate_limits (date, connection_count, message_count, search_count, last_updated)
-		VALUES (?, 0, 0, 1, ?)
-		ON CONFLICT(date) DO UPDATE SET
-			search_count = search_count + 1,
-			last_updated = ?
+		SELECT COUNT(*) FROM search_runs
+		WHERE search_hash = ? AND run_date = ?
 	`
 
-	now := time.Now()
-	_, err := db.conn.Exec(query, today, now, now)
-	return err
+	var count int
+	err := db.conn.QueryRow(query, searchHash, today).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
 }
 
-// GetRecentProfiles retrieves recent profiles that haven't been contacted
+// GetRecentProfiles retrieves recent profiles that haven't been contacted,
+// leaving out those marked failed_permanent
 func (db *Database) GetRecentProfiles(limit int, daysBack int) ([]Profile, error) {
 	query := `
-		SELECT DISTINCT p.id, p.name, p.title, p.company, p.location, p.profile_url, p.visited_at, p.created_at
+		SELECT DISTINCT p.id, p.name, p.title, p.company, p.location, p.profile_url, p.visited_at, p.created_at, COALESCE(p.mutual_count, 0), COALESCE(p.open_to_work, 0), COALESCE(p.context_source, ''),
+			COALESCE(p.connect_attempts, 0), COALESCE(p.failed_permanent, 0)
 		FROM profiles p
 		WHERE datetime(p.visited_at, 'utc') >= datetime('now', '-' || ? || ' days')
+		AND COALESCE(p.failed_permanent, 0) = 0
 		AND p.id NOT IN (
 			SELECT profile_id FROM connection_requests
 			WHERE datetime(sent_at, 'utc') >= datetime('now', '-' || ? || ' days')
@@ -498,6 +878,11 @@ func (db *Database) GetRecentProfiles(limit int, daysBack int) ([]Profile, error
 			&profile.ProfileURL,
 			&profile.VisitedAt,
 			&profile.CreatedAt,
+			&profile.MutualCount,
+			&profile.OpenToWork,
+			&profile.ContextSource,
+			&profile.ConnectAttempts,
+			&profile.FailedPermanent,
 		)
 		if err != nil {
 			return nil, err
@@ -542,15 +927,19 @@ func (db *Database) GetDailyStats(date string) (*RateLimit, error) {
 }
 
 // GetAcceptedConnectionProfiles retrieves profiles where connection was accepted and haven't been messaged yet
-// This is used for messaging automation to only message actual connections
-func (db *Database) GetAcceptedConnectionProfiles(limit int, daysBack int) ([]Profile, error) {
+// This is used for messaging automation to only message actual connections.
+// minDaysSinceAccepted holds back connections accepted more recently than that
+// (0 = no gap); acceptances recorded before accepted_at existed count from sent_at.
+func (db *Database) GetAcceptedConnectionProfiles(limit int, daysBack int, minDaysSinceAccepted int) ([]Profile, error) {
 	query := `
-		SELECT DISTINCT p.id, p.name, p.title, p.company, p.location, p.profile_url, p.visited_at, p.created_at
+		SELECT DISTINCT p.id, p.name, p.title, p.company, p.location, p.profile_url, p.visited_at, p.created_at, COALESCE(p.mutual_count, 0), COALESCE(p.open_to_work, 0), COALESCE(p.context_source, ''),
+			COALESCE(p.connect_attempts, 0), COALESCE(p.failed_permanent, 0)
 		FROM profiles p
 		INNER JOIN connection_requests cr ON p.id = cr.profile_id
 		WHERE cr.status = 'accepted'
 		AND (cr.has_replied IS NULL OR cr.has_replied = 0)
 		AND datetime(cr.sent_at, 'utc') >= datetime('now', '-' || ? || ' days')
+		AND (? <= 0 OR datetime(COALESCE(cr.accepted_at, cr.sent_at), 'utc') <= datetime('now', '-' || ? || ' days'))
 		AND p.id NOT IN (
 			SELECT connection_id FROM messages
 			WHERE datetime(sent_at, 'utc') >= datetime('now', '-' || ? || ' days')
@@ -559,7 +948,7 @@ func (db *Database) GetAcceptedConnectionProfiles(limit int, daysBack int) ([]Pr
 		LIMIT ?
 	`
 
-	rows, err := db.conn.Query(query, daysBack, daysBack, limit)
+	rows, err := db.conn.Query(query, daysBack, minDaysSinceAccepted, minDaysSinceAccepted, daysBack, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -577,6 +966,11 @@ func (db *Database) GetAcceptedConnectionProfiles(limit int, daysBack int) ([]Pr
 			&profile.ProfileURL,
 			&profile.VisitedAt,
 			&profile.CreatedAt,
+			&profile.MutualCount,
+			&profile.OpenToWork,
+			&profile.ContextSource,
+			&profile.ConnectAttempts,
+			&profile.FailedPermanent,
 		)
 		if err != nil {
 			return nil, err
@@ -597,3 +991,30 @@ func (db *Database) UpdateConnectionReplyStatus(profileID string, hasReplied boo
 	_, err := db.conn.Exec(query, hasReplied, profileID)
 	return err
 }
+
+// GetRequestedProfileIDsByName returns the IDs of profiles named name (case
+// and surrounding spaces ignored) that were sent a connection request. The
+// inbox list shows only names, so a caller should act on a single match only.
+func (db *Database) GetRequestedProfileIDsByName(name string) ([]string, error) {
+	query := `
+		SELECT DISTINCT p.id FROM profiles p
+		JOIN connection_requests c ON c.profile_id = p.id
+		WHERE lower(trim(p.name)) = lower(trim(?))
+		ORDER BY p.id
+	`
+	rows, err := db.conn.Query(query, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -1,7 +1,11 @@
 package storage
 
 import (
+	"database/sql"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -23,6 +27,33 @@ func TestInitDB(t *testing.T) {
 	}
 }
 
+func TestInitDBCreatesDirectory(t *testing.T) {
+	testDBPath := filepath.Join(t.TempDir(), "nested", "data", "linkedin.db")
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database in a missing directory: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := os.Stat(testDBPath); err != nil {
+		t.Errorf("Expected the database file to be created: %v", err)
+	}
+}
+
+func TestInitDBDirectoryError(t *testing.T) {
+	// A regular file where the directory should be makes MkdirAll fail
+	blocker := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	_, err := InitDB(filepath.Join(blocker, "linkedin.db"))
+	if err == nil || !strings.Contains(err.Error(), "failed to create database directory") {
+		t.Errorf("Expected a directory creation error, got %v", err)
+	}
+}
+
 func TestSaveAndGetProfile(t *testing.T) {
 	testDBPath := "./test_linkedin.db"
 	defer os.Remove(testDBPath)
@@ -250,3 +281,689 @@ func TestSaveAndRetrieveMessage(t *testing.T) {
 		t.Errorf("Expected template 'welcome', got '%s'", history[0].TemplateName)
 	}
 }
+
+func TestMigrateAddsNewColumns(t *testing.T) {
+	testDBPath := "./test_migrate.db"
+	defer os.Remove(testDBPath)
+
+	// Create a database with the original connection_requests schema
+	conn, err := sql.Open("sqlite3", testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = conn.Exec(`CREATE TABLE connection_requests (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		profile_id TEXT NOT NULL,
+		sent_at DATETIME NOT NULL,
+		note_used TEXT,
+		status TEXT DEFAULT 'pending',
+		has_replied BOOLEAN DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+	conn.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize legacy database: %v", err)
+	}
+	defer db.Close()
+
+	req := ConnectionRequest{
+		ProfileID:  "migrated-profile",
+		SentAt:     time.Now(),
+		NoteUsed:   "Hi!",
+		TemplateID: "conn_brief",
+		Status:     "pending",
+		CreatedAt:  time.Now(),
+	}
+	if err := db.SaveConnectionRequest(req); err != nil {
+		t.Fatalf("Failed to save connection request: %v", err)
+	}
+
+	pending, err := db.GetPendingConnections()
+	if err != nil {
+		t.Fatalf("Failed to get pending connections: %v", err)
+	}
+	if len(pending) != 1 || pending[0].TemplateID != "conn_brief" {
+		t.Errorf("Expected template_id to be persisted, got %+v", pending)
+	}
+}
+
+func TestProfileMutualCount(t *testing.T) {
+	testDBPath := "./test_mutual.db"
+	defer os.Remove(testDBPath)
+
+	// Start from a profiles table that predates mutual_count
+	conn, err := sql.Open("sqlite3", testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = conn.Exec(`CREATE TABLE profiles (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		title TEXT,
+		company TEXT,
+		location TEXT,
+		profile_url TEXT NOT NULL UNIQUE,
+		visited_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+	conn.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize legacy database: %v", err)
+	}
+	defer db.Close()
+
+	profile := Profile{
+		ID:          "mutual-profile",
+		Name:        "Jane Doe",
+		ProfileURL:  "https://www.linkedin.com/in/mutual-profile/",
+		VisitedAt:   time.Now(),
+		CreatedAt:   time.Now(),
+		MutualCount: 12,
+	}
+
+	steps := []struct {
+		saved    int
+		expected int
+	}{
+		{12, 12},
+		{0, 12}, // A save without a count keeps the recorded one
+		{15, 15},
+	}
+	for _, step := range steps {
+		profile.MutualCount = step.saved
+		if err := db.SaveProfile(profile); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+		got, err := db.GetProfile(profile.ID)
+		if err != nil {
+			t.Fatalf("Failed to get profile: %v", err)
+		}
+		if got.MutualCount != step.expected {
+			t.Errorf("After saving %d: expected mutual count %d, got %d", step.saved, step.expected, got.MutualCount)
+		}
+	}
+}
+
+func TestProfileOpenToWork(t *testing.T) {
+	testDBPath := "./test_open_to_work.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	profile := Profile{
+		ID:         "otw-profile",
+		Name:       "Jane Doe",
+		ProfileURL: "https://www.linkedin.com/in/otw-profile/",
+		VisitedAt:  time.Now(),
+		CreatedAt:  time.Now(),
+	}
+
+	steps := []struct {
+		saved    bool
+		expected bool
+	}{
+		{false, false},
+		{true, true},
+		{false, true}, // A save that didn't see the photo keeps the recorded flag
+	}
+	for _, step := range steps {
+		profile.OpenToWork = step.saved
+		if err := db.SaveProfile(profile); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+		got, err := db.GetProfile(profile.ID)
+		if err != nil {
+			t.Fatalf("Failed to get profile: %v", err)
+		}
+		if got.OpenToWork != step.expected {
+			t.Errorf("After saving %v: expected open to work %v, got %v", step.saved, step.expected, got.OpenToWork)
+		}
+	}
+}
+
+func TestRecordConnectAttemptExcludesAfterMaxFailures(t *testing.T) {
+	testDBPath := "./test_attempts.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	for _, id := range []string{"private-profile", "other-profile"} {
+		if err := db.SaveProfile(Profile{
+			ID: id, Name: id, ProfileURL: "https://www.linkedin.com/in/" + id + "/",
+			VisitedAt: time.Now(), CreatedAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+	}
+
+	selected := func() map[string]bool {
+		profiles, err := db.GetRecentProfiles(10, 30)
+		if err != nil {
+			t.Fatalf("Failed to get recent profiles: %v", err)
+		}
+		ids := make(map[string]bool)
+		for _, profile := range profiles {
+			ids[profile.ID] = true
+		}
+		return ids
+	}
+
+	const maxAttempts = 3
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if !selected()["private-profile"] {
+			t.Fatalf("Profile excluded before attempt %d", attempt)
+		}
+		permanent, err := db.RecordConnectAttempt("private-profile", true, maxAttempts)
+		if err != nil {
+			t.Fatalf("Failed to record attempt: %v", err)
+		}
+		if permanent != (attempt == maxAttempts) {
+			t.Errorf("Attempt %d: expected permanent=%v, got %v", attempt, attempt == maxAttempts, permanent)
+		}
+	}
+
+	ids := selected()
+	if ids["private-profile"] || !ids["other-profile"] {
+		t.Errorf("Expected only the failing profile to be excluded, got %v", ids)
+	}
+
+	profile, err := db.GetProfile("private-profile")
+	if err != nil {
+		t.Fatalf("Failed to get profile: %v", err)
+	}
+	if profile.ConnectAttempts != maxAttempts || !profile.FailedPermanent {
+		t.Errorf("Expected %d attempts and failed_permanent, got %+v", maxAttempts, profile)
+	}
+}
+
+func TestRecordConnectAttemptWithoutLimit(t *testing.T) {
+	testDBPath := "./test_attempts_unlimited.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SaveProfile(Profile{
+		ID: "flaky-profile", Name: "Flaky", ProfileURL: "https://www.linkedin.com/in/flaky-profile/",
+		VisitedAt: time.Now(), CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+
+	// No limit, and successful attempts never mark a profile
+	for i := 0; i < 5; i++ {
+		if permanent, err := db.RecordConnectAttempt("flaky-profile", true, 0); err != nil || permanent {
+			t.Fatalf("Expected no limit, got permanent=%v (%v)", permanent, err)
+		}
+	}
+	if permanent, err := db.RecordConnectAttempt("flaky-profile", false, 3); err != nil || permanent {
+		t.Errorf("Expected a successful attempt not to mark the profile, got permanent=%v (%v)", permanent, err)
+	}
+
+	// Unknown profiles are not an error
+	if permanent, err := db.RecordConnectAttempt("unknown-profile", true, 1); err != nil || permanent {
+		t.Errorf("Expected nothing recorded for an unknown profile, got permanent=%v (%v)", permanent, err)
+	}
+}
+
+func TestConnectionRequestEvidencePath(t *testing.T) {
+	testDBPath := "./test_linkedin.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	requests := []ConnectionRequest{
+		{ProfileID: "with-evidence", SentAt: time.Now(), Status: "pending", EvidencePath: "data/screenshots/with-evidence.png", CreatedAt: time.Now()},
+		{ProfileID: "without-evidence", SentAt: time.Now(), Status: "pending", CreatedAt: time.Now()},
+	}
+	for _, req := range requests {
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+
+	pending, err := db.GetPendingConnections()
+	if err != nil {
+		t.Fatalf("Failed to get pending connections: %v", err)
+	}
+
+	paths := make(map[string]string)
+	for _, req := range pending {
+		paths[req.ProfileID] = req.EvidencePath
+	}
+
+	if paths["with-evidence"] != "data/screenshots/with-evidence.png" {
+		t.Errorf("Expected evidence path to be persisted, got %q", paths["with-evidence"])
+	}
+	if paths["without-evidence"] != "" {
+		t.Errorf("Expected empty evidence path, got %q", paths["without-evidence"])
+	}
+}
+
+func TestRecordPendingInvitation(t *testing.T) {
+	testDBPath := "./test_pending_invitation.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	if err := db.SaveConnectionRequest(ConnectionRequest{ProfileID: "already-pending", SentAt: now, Status: "pending", CreatedAt: now}); err != nil {
+		t.Fatalf("Failed to save connection request: %v", err)
+	}
+	if err := db.SaveConnectionRequest(ConnectionRequest{ProfileID: "withdrawn", SentAt: now, Status: "withdrawn", CreatedAt: now}); err != nil {
+		t.Fatalf("Failed to save connection request: %v", err)
+	}
+
+	tests := []struct {
+		profileID string
+		wantAdded bool
+	}{
+		{"already-pending", false},
+		{"withdrawn", true}, // Re-invited since the request was withdrawn
+		{"sent-by-hand", true},
+		{"sent-by-hand", false}, // Seen again
+	}
+	for _, tt := range tests {
+		added, err := db.RecordPendingInvitation(tt.profileID, now)
+		if err != nil {
+			t.Fatalf("Failed to record pending invitation: %v", err)
+		}
+		if added != tt.wantAdded {
+			t.Errorf("%s: expected added=%v, got %v", tt.profileID, tt.wantAdded, added)
+		}
+	}
+
+	pending, err := db.GetPendingConnections()
+	if err != nil {
+		t.Fatalf("Failed to get pending connections: %v", err)
+	}
+	if len(pending) != 3 {
+		t.Errorf("Expected 3 pending requests, got %d", len(pending))
+	}
+
+	// Observed invitations are not requests the bot sent
+	sent, err := db.CountTotalConnectionsSent()
+	if err != nil {
+		t.Fatalf("Failed to count connections: %v", err)
+	}
+	if sent != 2 {
+		t.Errorf("Expected only the 2 sent requests to count, got %d", sent)
+	}
+	rate, sample, err := db.GetRecentAcceptanceRate(14, 0)
+	if err != nil {
+		t.Fatalf("Failed to get acceptance rate: %v", err)
+	}
+	if sample != 2 || rate != 0 {
+		t.Errorf("Expected the acceptance rate over the 2 sent requests, got %v/%d", rate, sample)
+	}
+}
+
+func TestHasRunSearchToday(t *testing.T) {
+	testDBPath := "./test_search_runs.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	ran, err := db.HasRunSearchToday("hash-a")
+	if err != nil {
+		t.Fatalf("Failed to check search run: %v", err)
+	}
+	if ran {
+		t.Error("Expected search not to have run yet")
+	}
+
+	if err := db.RecordSearchRun("hash-a"); err != nil {
+		t.Fatalf("Failed to record search run: %v", err)
+	}
+
+	ran, _ = db.HasRunSearchToday("hash-a")
+	if !ran {
+		t.Error("Expected same search to be reported as run today")
+	}
+
+	ran, _ = db.HasRunSearchToday("hash-b")
+	if ran {
+		t.Error("Expected a different search to run again")
+	}
+
+	// A run from yesterday does not count
+	yesterday := time.Now().AddDate(0, 0, -1)
+	if _, err := db.conn.Exec(`INSERT INTO search_runs (search_hash, run_date, run_at) VALUES (?, ?, ?)`,
+		"hash-c", yesterday.Format("2006-01-02"), yesterday); err != nil {
+		t.Fatal(err)
+	}
+	ran, _ = db.HasRunSearchToday("hash-c")
+	if ran {
+		t.Error("Expected a search from yesterday to run again today")
+	}
+}
+
+func TestCountConnectionsToCompanyToday(t *testing.T) {
+	testDBPath := "./test_company_cap.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	profiles := []Profile{
+		{ID: "acme-1", Name: "A One", Company: "Acme", ProfileURL: "https://www.linkedin.com/in/acme-1/"},
+		{ID: "acme-2", Name: "A Two", Company: "acme ", ProfileURL: "https://www.linkedin.com/in/acme-2/"},
+		{ID: "acme-3", Name: "A Three", Company: "Acme", ProfileURL: "https://www.linkedin.com/in/acme-3/"},
+		{ID: "globex-1", Name: "G One", Company: "Globex", ProfileURL: "https://www.linkedin.com/in/globex-1/"},
+	}
+	for _, p := range profiles {
+		p.VisitedAt = time.Now()
+		p.CreatedAt = time.Now()
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+	}
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	requests := []ConnectionRequest{
+		{ProfileID: "acme-1", SentAt: time.Now(), Status: "pending", CreatedAt: time.Now()},
+		{ProfileID: "acme-2", SentAt: time.Now(), Status: "pending", CreatedAt: time.Now()},
+		{ProfileID: "acme-3", SentAt: yesterday, Status: "pending", CreatedAt: yesterday},
+		{ProfileID: "globex-1", SentAt: time.Now(), Status: "pending", CreatedAt: time.Now()},
+	}
+	for _, req := range requests {
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+
+	tests := []struct {
+		company  string
+		expected int
+	}{
+		{"Acme", 2}, // case and whitespace insensitive, yesterday's request excluded
+		{"ACME", 2},
+		{"Globex", 1},
+		{"Initech", 0},
+	}
+
+	for _, test := range tests {
+		count, err := db.CountConnectionsToCompanyToday(test.company)
+		if err != nil {
+			t.Fatalf("Failed to count connections: %v", err)
+		}
+		if count != test.expected {
+			t.Errorf("CountConnectionsToCompanyToday(%q) = %d, expected %d", test.company, count, test.expected)
+		}
+	}
+}
+
+func TestCountTotalConnectionsSent(t *testing.T) {
+	testDBPath := "./test_lifetime_count.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	count, err := db.CountTotalConnectionsSent()
+	if err != nil {
+		t.Fatalf("Failed to count connections: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 connections in an empty database, got %d", count)
+	}
+
+	lastMonth := time.Now().AddDate(0, -1, 0)
+	for i, sentAt := range []time.Time{lastMonth, time.Now()} {
+		profile := Profile{ID: fmt.Sprintf("total-%d", i), Name: "Total", ProfileURL: fmt.Sprintf("https://www.linkedin.com/in/total-%d/", i), VisitedAt: sentAt, CreatedAt: sentAt}
+		if err := db.SaveProfile(profile); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+		req := ConnectionRequest{ProfileID: profile.ID, SentAt: sentAt, Status: "pending", CreatedAt: sentAt}
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+
+	count, err = db.CountTotalConnectionsSent()
+	if err != nil {
+		t.Fatalf("Failed to count connections: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("CountTotalConnectionsSent() = %d, expected 2 (all days)", count)
+	}
+}
+
+func TestGetRecentAcceptanceRate(t *testing.T) {
+	testDBPath := "./test_acceptance.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	// No requests yet
+	rate, sample, err := db.GetRecentAcceptanceRate(14, 0)
+	if err != nil {
+		t.Fatalf("Failed to get acceptance rate: %v", err)
+	}
+	if rate != 0 || sample != 0 {
+		t.Errorf("Expected 0 rate and sample with no requests, got %v/%d", rate, sample)
+	}
+
+	now := time.Now()
+	requests := []struct {
+		profileID string
+		sentAt    time.Time
+		status    string
+	}{
+		{"a", now.AddDate(0, 0, -1), "accepted"},
+		{"b", now.AddDate(0, 0, -2), "pending"},
+		{"c", now.AddDate(0, 0, -3), "pending"},
+		{"d", now.AddDate(0, 0, -5), "accepted"},
+		{"old", now.AddDate(0, 0, -30), "accepted"}, // Outside the window
+	}
+	for _, r := range requests {
+		req := ConnectionRequest{ProfileID: r.profileID, SentAt: r.sentAt, Status: r.status, CreatedAt: r.sentAt}
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+
+	rate, sample, err = db.GetRecentAcceptanceRate(14, 0)
+	if err != nil {
+		t.Fatalf("Failed to get acceptance rate: %v", err)
+	}
+	if sample != 4 {
+		t.Errorf("Expected 4 requests in the window, got %d", sample)
+	}
+	if rate != 0.5 {
+		t.Errorf("Expected 50%% acceptance, got %v", rate)
+	}
+
+	// Requests younger than the minimum age have not had time to be accepted
+	rate, sample, err = db.GetRecentAcceptanceRate(14, 4)
+	if err != nil {
+		t.Fatalf("Failed to get acceptance rate: %v", err)
+	}
+	if sample != 1 {
+		t.Errorf("Expected 1 request at least 4 days old, got %d", sample)
+	}
+	if rate != 1 {
+		t.Errorf("Expected 100%% acceptance, got %v", rate)
+	}
+}
+
+func TestGetAcceptedConnectionProfilesMinDaysSinceAccepted(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "test_accepted_gap.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	acceptedAt := map[string]time.Time{
+		"just-now":    now.Add(-time.Minute),
+		"yesterday":   now.AddDate(0, 0, -1),
+		"three-days":  now.AddDate(0, 0, -3),
+		"legacy-none": {}, // accepted before accepted_at was recorded
+	}
+	for id, at := range acceptedAt {
+		profile := Profile{ID: id, Name: id, ProfileURL: "https://www.linkedin.com/in/" + id, VisitedAt: now, CreatedAt: now}
+		if err := db.SaveProfile(profile); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+		req := ConnectionRequest{ProfileID: id, SentAt: now.AddDate(0, 0, -5), Status: "pending", CreatedAt: now}
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+		if err := db.UpdateConnectionStatus(id, "accepted"); err != nil {
+			t.Fatalf("Failed to accept connection: %v", err)
+		}
+
+		var seeded interface{} = at
+		if at.IsZero() {
+			seeded = nil
+		}
+		if _, err := db.conn.Exec(`UPDATE connection_requests SET accepted_at = ? WHERE profile_id = ?`, seeded, id); err != nil {
+			t.Fatalf("Failed to seed accepted_at: %v", err)
+		}
+	}
+
+	ids := func(minDays int) map[string]bool {
+		profiles, err := db.GetAcceptedConnectionProfiles(10, 30, minDays)
+		if err != nil {
+			t.Fatalf("Failed to get accepted profiles: %v", err)
+		}
+		got := make(map[string]bool)
+		for _, p := range profiles {
+			got[p.ID] = true
+		}
+		return got
+	}
+
+	if got := ids(0); len(got) != 4 {
+		t.Errorf("Expected every acceptance without a gap, got %v", got)
+	}
+
+	got := ids(2)
+	if got["just-now"] || got["yesterday"] {
+		t.Errorf("Expected recent acceptances held back, got %v", got)
+	}
+	if !got["three-days"] || !got["legacy-none"] {
+		t.Errorf("Expected older acceptances to be eligible, got %v", got)
+	}
+}
+
+func TestUpdateConnectionStatusRecordsAcceptedAt(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "test_accepted_at.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	for _, id := range []string{"accepted", "withdrawn"} {
+		req := ConnectionRequest{ProfileID: id, SentAt: time.Now(), Status: "pending", CreatedAt: time.Now()}
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+		if err := db.UpdateConnectionStatus(id, id); err != nil {
+			t.Fatalf("Failed to update connection status: %v", err)
+		}
+	}
+
+	var recorded int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM connection_requests WHERE accepted_at IS NOT NULL`).Scan(&recorded); err != nil {
+		t.Fatal(err)
+	}
+	var acceptedSet bool
+	if err := db.conn.QueryRow(`SELECT accepted_at IS NOT NULL FROM connection_requests WHERE profile_id = 'accepted'`).Scan(&acceptedSet); err != nil {
+		t.Fatal(err)
+	}
+	if recorded != 1 || !acceptedSet {
+		t.Errorf("Expected accepted_at only on the accepted request, got %d set (accepted %v)", recorded, acceptedSet)
+	}
+}
+
+func TestGetRequestedProfileIDsByName(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "test_names.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	profiles := []Profile{
+		{ID: "jane-doe", Name: "Jane Doe", ProfileURL: "https://www.linkedin.com/in/jane-doe"},
+		{ID: "john-roe", Name: "John Roe", ProfileURL: "https://www.linkedin.com/in/john-roe"},
+		{ID: "john-roe-2", Name: "John Roe", ProfileURL: "https://www.linkedin.com/in/john-roe-2"},
+		{ID: "never-asked", Name: "Sam Poe", ProfileURL: "https://www.linkedin.com/in/never-asked"},
+	}
+	for _, profile := range profiles {
+		profile.VisitedAt, profile.CreatedAt = time.Now(), time.Now()
+		if err := db.SaveProfile(profile); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+		if profile.ID == "never-asked" {
+			continue
+		}
+		req := ConnectionRequest{ProfileID: profile.ID, SentAt: time.Now(), Status: "accepted", CreatedAt: time.Now()}
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{" jane doe ", "[jane-doe]"},
+		{"John Roe", "[john-roe john-roe-2]"},
+		{"Sam Poe", "[]"},
+		{"Nobody", "[]"},
+	}
+	for _, tt := range tests {
+		ids, err := db.GetRequestedProfileIDsByName(tt.name)
+		if err != nil {
+			t.Fatalf("Lookup of %q failed: %v", tt.name, err)
+		}
+		if got := fmt.Sprint(ids); got != tt.want {
+			t.Errorf("GetRequestedProfileIDsByName(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
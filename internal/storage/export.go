@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+// exportColumns is the header row of ExportConnectionsCSV
+var exportColumns = []string{
+	"profile_id", "name", "title", "company", "location", "profile_url",
+	"sent_at", "status", "has_replied", "template_id", "note",
+}
+
+// ExportConnectionsCSV writes every connection request with its profile as
+// CSV, oldest first, and returns the number of requests written
+func (db *Database) ExportConnectionsCSV(w io.Writer) (int, error) {
+	query := `
+		SELECT cr.profile_id, COALESCE(p.name, ''), COALESCE(p.title, ''), COALESCE(p.company, ''),
+			COALESCE(p.location, ''), COALESCE(p.profile_url, ''), cr.sent_at, cr.status,
+			COALESCE(cr.has_replied, 0), COALESCE(cr.template_id, ''), COALESCE(cr.note_used, '')
+		FROM connection_requests cr
+		LEFT JOIN profiles p ON p.id = cr.profile_id
+		ORDER BY cr.sent_at ASC, cr.id ASC
+	`
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for rows.Next() {
+		var (
+			profileID, name, title, company, location, profileURL string
+			status, templateID, note                              string
+			sentAt                                                time.Time
+			hasReplied                                            sql.NullBool
+		)
+		if err := rows.Scan(&profileID, &name, &title, &company, &location, &profileURL,
+			&sentAt, &status, &hasReplied, &templateID, &note); err != nil {
+			return count, err
+		}
+
+		record := []string{
+			profileID, name, title, company, location, profileURL,
+			sentAt.Format(time.RFC3339), status, fmt.Sprint(hasReplied.Bool), templateID, note,
+		}
+		if err := writer.Write(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+
+	writer.Flush()
+	return count, writer.Error()
+}
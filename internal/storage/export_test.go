@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestExportConnectionsCSV(t *testing.T) {
+	testDBPath := "./test_export.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	profile := Profile{ID: "jane-doe", Name: "Jane Doe", Title: "Staff Engineer", Company: "Acme, Inc.",
+		ProfileURL: "https://www.linkedin.com/in/jane-doe/", VisitedAt: now, CreatedAt: now}
+	if err := db.SaveProfile(profile); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+
+	requests := []ConnectionRequest{
+		{ProfileID: "jane-doe", SentAt: now, NoteUsed: "Hi Jane, \"great\" talk", TemplateID: "conn_generic", Status: "accepted", CreatedAt: now},
+		{ProfileID: "unknown", SentAt: now.Add(-time.Hour), Status: "pending", CreatedAt: now},
+	}
+	for _, req := range requests {
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+	if err := db.UpdateConnectionReplyStatus("jane-doe", true); err != nil {
+		t.Fatalf("Failed to mark reply: %v", err)
+	}
+
+	var buf bytes.Buffer
+	count, err := db.ExportConnectionsCSV(&buf)
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("Expected 2 requests exported, got %d", count)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Export is not valid CSV: %v", err)
+	}
+	if len(records) != 3 || len(records[0]) != len(exportColumns) {
+		t.Fatalf("Expected a header and 2 rows of %d columns, got %v", len(exportColumns), records)
+	}
+
+	// Oldest first; a request without a saved profile still exports
+	if records[1][0] != "unknown" || records[1][1] != "" || records[1][7] != "pending" {
+		t.Errorf("Unexpected first row: %v", records[1])
+	}
+	jane := records[2]
+	if jane[1] != "Jane Doe" || jane[3] != "Acme, Inc." || jane[7] != "accepted" || jane[8] != "true" || jane[10] != "Hi Jane, \"great\" talk" {
+		t.Errorf("Unexpected second row: %v", jane)
+	}
+}
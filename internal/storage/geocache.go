@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// geoCacheKey normalizes a location name so lookups ignore case and spacing
+func geoCacheKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// GetGeoURN returns the cached geoUrn ID resolved for a location name
+func (db *Database) GetGeoURN(name string) (string, bool, error) {
+	var urn string
+	err := db.conn.QueryRow(`SELECT urn FROM geo_cache WHERE name = ?`, geoCacheKey(name)).Scan(&urn)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get geoUrn for %s: %w", name, err)
+	}
+	return urn, true, nil
+}
+
+// SaveGeoURN caches the geoUrn ID resolved for a location name
+func (db *Database) SaveGeoURN(name, urn string) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO geo_cache (name, urn, resolved_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET urn = excluded.urn, resolved_at = excluded.resolved_at
+	`, geoCacheKey(name), urn, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save geoUrn for %s: %w", name, err)
+	}
+	return nil
+}
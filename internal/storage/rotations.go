@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetRotationIndex returns the saved position of a template rotation (0 if never saved)
+func (db *Database) GetRotationIndex(name string) (int, error) {
+	var index int
+	err := db.conn.QueryRow(`SELECT next_index FROM template_rotations WHERE name = ?`, name).Scan(&index)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rotation %s: %w", name, err)
+	}
+	return index, nil
+}
+
+// SetRotationIndex saves the position a template rotation continues from
+func (db *Database) SetRotationIndex(name string, index int) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO template_rotations (name, next_index, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET next_index = excluded.next_index, updated_at = excluded.updated_at
+	`, name, index, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save rotation %s: %w", name, err)
+	}
+	return nil
+}
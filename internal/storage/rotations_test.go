@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRotationIndex(t *testing.T) {
+	testDBPath := "./test_rotations.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	index, err := db.GetRotationIndex("connection")
+	if err != nil || index != 0 {
+		t.Fatalf("Expected a new rotation to start at 0, got %d (err %v)", index, err)
+	}
+
+	if err := db.SetRotationIndex("connection", 2); err != nil {
+		t.Fatalf("Failed to save rotation: %v", err)
+	}
+	if err := db.SetRotationIndex("connection", 3); err != nil {
+		t.Fatalf("Failed to update rotation: %v", err)
+	}
+	if err := db.SetRotationIndex("message", 1); err != nil {
+		t.Fatalf("Failed to save rotation: %v", err)
+	}
+	db.Close()
+
+	// Survives reopening the database
+	db, err = InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	if index, err := db.GetRotationIndex("connection"); err != nil || index != 3 {
+		t.Errorf("Expected connection rotation at 3, got %d (err %v)", index, err)
+	}
+	if index, err := db.GetRotationIndex("message"); err != nil || index != 1 {
+		t.Errorf("Expected message rotation at 1, got %d (err %v)", index, err)
+	}
+}
@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Sequence statuses
+const (
+	SequenceWaiting  = "waiting"  // Connection request sent, waiting for acceptance
+	SequenceEnqueued = "enqueued" // Accepted; the follow-up message is in the scheduled queue
+)
+
+// Scheduled message statuses
+const (
+	ScheduledPending = "pending" // Waiting for send_after
+	ScheduledSent    = "sent"    // Message sent
+	ScheduledSkipped = "skipped" // Deliberately not sent (e.g. already messaged)
+	ScheduledFailed  = "failed"  // Sending failed for good; not retried
+)
+
+// Sequence links a connection request to the message sent once it is accepted
+type Sequence struct {
+	ID                   int64
+	ProfileID            string
+	ConnectionTemplateID string
+	MessageTemplateID    string
+	Status               string // 'waiting', 'enqueued'
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// ScheduledMessage is a message queued to be sent at or after SendAfter
+type ScheduledMessage struct {
+	ID         int64
+	ProfileID  string
+	TemplateID string
+	SendAfter  time.Time
+	Status     string // 'pending', 'sent', 'skipped', 'failed'
+	SequenceID int64  // 0 when not queued by a sequence
+	Attempts   int    // Failed sends so far
+	CreatedAt  time.Time
+}
+
+// CreateSequence links a profile's connection request to a follow-up message template.
+// A profile has at most one sequence; calling it again while the sequence is still
+// waiting replaces the templates, and is a no-op once the message was enqueued.
+func (db *Database) CreateSequence(profileID, connectionTemplateID, messageTemplateID string) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO sequences (profile_id, connection_template_id, message_template_id, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(profile_id) DO UPDATE SET
+			connection_template_id = excluded.connection_template_id,
+			message_template_id = excluded.message_template_id,
+			updated_at = excluded.updated_at
+		WHERE sequences.status = ?
+	`, profileID, connectionTemplateID, messageTemplateID, SequenceWaiting, time.Now(), time.Now(), SequenceWaiting)
+	if err != nil {
+		return fmt.Errorf("failed to create sequence for %s: %w", profileID, err)
+	}
+	return nil
+}
+
+// GetSequence retrieves the sequence for a profile, or nil if there is none
+func (db *Database) GetSequence(profileID string) (*Sequence, error) {
+	var seq Sequence
+	err := db.conn.QueryRow(`
+		SELECT id, profile_id, COALESCE(connection_template_id, ''), message_template_id, status, created_at, updated_at
+		FROM sequences WHERE profile_id = ?
+	`, profileID).Scan(
+		&seq.ID,
+		&seq.ProfileID,
+		&seq.ConnectionTemplateID,
+		&seq.MessageTemplateID,
+		&seq.Status,
+		&seq.CreatedAt,
+		&seq.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &seq, nil
+}
+
+// EnqueueSequenceMessage queues the follow-up of a profile's waiting sequence to be
+// sent at or after sendAfter, and marks the sequence enqueued. Returns false when
+// the profile has no waiting sequence, so repeated acceptances enqueue only once.
+func (db *Database) EnqueueSequenceMessage(profileID string, sendAfter time.Time) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var sequenceID int64
+	var templateID string
+	err = tx.QueryRow(`SELECT id, message_template_id FROM sequences WHERE profile_id = ? AND status = ?`,
+		profileID, SequenceWaiting).Scan(&sequenceID, &templateID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	res, err := tx.Exec(`UPDATE sequences SET status = ?, updated_at = ? WHERE id = ? AND status = ?`,
+		SequenceEnqueued, time.Now(), sequenceID, SequenceWaiting)
+	if err != nil {
+		return false, err
+	}
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+		return false, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO scheduled_messages (profile_id, template_id, send_after, status, sequence_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, profileID, templateID, sendAfter.UTC(), ScheduledPending, sequenceID, time.Now(), time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to schedule message for %s: %w", profileID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetDueScheduledMessages returns up to limit pending messages due at now,
+// oldest first. A limit of 0 or less returns all of them.
+// minDaysSinceAccepted holds back messages to connections accepted more
+// recently than that (0 = no gap), like GetAcceptedConnectionProfiles.
+func (db *Database) GetDueScheduledMessages(now time.Time, limit int, minDaysSinceAccepted int) ([]ScheduledMessage, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite reads a negative LIMIT as no limit
+	}
+	rows, err := db.conn.Query(`
+		SELECT sm.id, sm.profile_id, sm.template_id, sm.send_after, sm.status, COALESCE(sm.sequence_id, 0),
+			COALESCE(sm.attempts, 0), sm.created_at
+		FROM scheduled_messages sm
+		WHERE sm.status = ? AND datetime(sm.send_after) <= datetime(?)
+		AND (? <= 0 OR EXISTS (
+			SELECT 1 FROM connection_requests cr
+			WHERE cr.profile_id = sm.profile_id AND cr.status = 'accepted'
+			AND datetime(COALESCE(cr.accepted_at, cr.sent_at), 'utc') <= datetime(?, '-' || ? || ' days')
+		))
+		ORDER BY sm.send_after ASC, sm.id ASC
+		LIMIT ?
+	`, ScheduledPending, now.UTC(), minDaysSinceAccepted, now.UTC(), minDaysSinceAccepted, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []ScheduledMessage
+	for rows.Next() {
+		var msg ScheduledMessage
+		if err := rows.Scan(
+			&msg.ID,
+			&msg.ProfileID,
+			&msg.TemplateID,
+			&msg.SendAfter,
+			&msg.Status,
+			&msg.SequenceID,
+			&msg.Attempts,
+			&msg.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
+
+// MarkScheduledMessage records the outcome of a scheduled message so it is not picked again
+func (db *Database) MarkScheduledMessage(id int64, status string) error {
+	if status == ScheduledPending {
+		return fmt.Errorf("cannot mark scheduled message with status %q", status)
+	}
+
+	res, err := db.conn.Exec(`UPDATE scheduled_messages SET status = ?, updated_at = ? WHERE id = ?`,
+		status, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("scheduled message %d not found", id)
+	}
+
+	return nil
+}
+
+// RecordScheduledFailure counts a failed send of a scheduled message. The
+// message stays pending for the next run until it has failed maxAttempts
+// times, then it is marked failed. Returns true once it is marked failed.
+func (db *Database) RecordScheduledFailure(id int64, maxAttempts int) (bool, error) {
+	query := `
+		UPDATE scheduled_messages SET
+			attempts = COALESCE(attempts, 0) + 1,
+			status = CASE WHEN ? > 0 AND COALESCE(attempts, 0) + 1 >= ? THEN ? ELSE status END,
+			updated_at = ?
+		WHERE id = ?
+	`
+	res, err := db.conn.Exec(query, maxAttempts, maxAttempts, ScheduledFailed, time.Now(), id)
+	if err != nil {
+		return false, err
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return false, fmt.Errorf("scheduled message %d not found", id)
+	}
+
+	var status string
+	if err := db.conn.QueryRow(`SELECT status FROM scheduled_messages WHERE id = ?`, id).Scan(&status); err != nil {
+		return false, err
+	}
+	return status == ScheduledFailed, nil
+}
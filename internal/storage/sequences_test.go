@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSequenceEnqueuesOnce(t *testing.T) {
+	testDBPath := "./test_sequences.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.CreateSequence("alice", "conn_generic", "msg_introduction"); err != nil {
+		t.Fatalf("Failed to create sequence: %v", err)
+	}
+
+	// Re-creating while waiting replaces the templates
+	if err := db.CreateSequence("alice", "conn_brief", "msg_follow_up"); err != nil {
+		t.Fatalf("Failed to update sequence: %v", err)
+	}
+
+	seq, err := db.GetSequence("alice")
+	if err != nil || seq == nil {
+		t.Fatalf("Failed to get sequence: %v", err)
+	}
+	if seq.Status != SequenceWaiting || seq.ConnectionTemplateID != "conn_brief" || seq.MessageTemplateID != "msg_follow_up" {
+		t.Errorf("Unexpected sequence: %+v", seq)
+	}
+
+	sendAfter := time.Now().Add(6 * time.Hour)
+	enqueued, err := db.EnqueueSequenceMessage("alice", sendAfter)
+	if err != nil || !enqueued {
+		t.Fatalf("Expected follow-up to be enqueued, got %v (err %v)", enqueued, err)
+	}
+
+	// A second acceptance must not enqueue again
+	enqueued, err = db.EnqueueSequenceMessage("alice", sendAfter)
+	if err != nil || enqueued {
+		t.Errorf("Expected no second enqueue, got %v (err %v)", enqueued, err)
+	}
+
+	// Once enqueued the sequence is no longer updated
+	if err := db.CreateSequence("alice", "conn_generic", "msg_networking"); err != nil {
+		t.Fatalf("Failed to call CreateSequence: %v", err)
+	}
+	seq, _ = db.GetSequence("alice")
+	if seq.Status != SequenceEnqueued || seq.MessageTemplateID != "msg_follow_up" {
+		t.Errorf("Expected enqueued sequence to be left alone, got %+v", seq)
+	}
+
+	// Profiles without a sequence enqueue nothing
+	enqueued, err = db.EnqueueSequenceMessage("bob", sendAfter)
+	if err != nil || enqueued {
+		t.Errorf("Expected nothing for a profile without a sequence, got %v (err %v)", enqueued, err)
+	}
+	if seq, _ := db.GetSequence("bob"); seq != nil {
+		t.Errorf("Expected no sequence for bob, got %+v", seq)
+	}
+}
+
+func TestScheduledMessagesDueAndMarked(t *testing.T) {
+	testDBPath := "./test_scheduled_messages.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	db.CreateSequence("alice", "conn_generic", "msg_introduction")
+	db.CreateSequence("bob", "conn_generic", "msg_introduction")
+	db.EnqueueSequenceMessage("alice", now.Add(2*time.Hour))
+	db.EnqueueSequenceMessage("bob", now.Add(10*time.Hour))
+
+	due, err := db.GetDueScheduledMessages(now, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to get due messages: %v", err)
+	}
+	if len(due) != 0 {
+		t.Errorf("Expected nothing due yet, got %d", len(due))
+	}
+
+	due, err = db.GetDueScheduledMessages(now.Add(3*time.Hour), 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to get due messages: %v", err)
+	}
+	if len(due) != 1 || due[0].ProfileID != "alice" || due[0].TemplateID != "msg_introduction" || due[0].SequenceID == 0 {
+		t.Fatalf("Expected alice's message to be due, got %+v", due)
+	}
+
+	if err := db.MarkScheduledMessage(due[0].ID, ScheduledSent); err != nil {
+		t.Fatalf("Failed to mark message: %v", err)
+	}
+	if err := db.MarkScheduledMessage(due[0].ID, ScheduledPending); err == nil {
+		t.Error("Expected error when marking a message pending")
+	}
+	if err := db.MarkScheduledMessage(9999, ScheduledSent); err == nil {
+		t.Error("Expected error for unknown scheduled message")
+	}
+
+	due, _ = db.GetDueScheduledMessages(now.Add(24*time.Hour), 10, 0)
+	if len(due) != 1 || due[0].ProfileID != "bob" {
+		t.Errorf("Expected only bob's message to remain due, got %+v", due)
+	}
+}
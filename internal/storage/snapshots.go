@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"time"
+)
+
+// DailySnapshot captures end-of-run outcome metrics for one day
+type DailySnapshot struct {
+	Date            string // YYYY-MM-DD format
+	ConnectionsSent int    // Connection requests sent that day
+	Accepted        int    // Accepted connections in total as of the snapshot
+	MessagesSent    int    // Messages sent that day
+	Replies         int    // Connections that replied in total as of the snapshot
+	Errors          int    // Errors across all runs that day
+	UpdatedAt       time.Time
+}
+
+// CollectDailySnapshot builds today's snapshot from the database.
+// runErrors is the number of errors seen in the current run.
+func (db *Database) CollectDailySnapshot(runErrors int) (DailySnapshot, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	snapshot := DailySnapshot{
+		Date:      now.Format("2006-01-02"),
+		Errors:    runErrors,
+		UpdatedAt: now,
+	}
+
+	counts := []struct {
+		query string
+		args  []interface{}
+		dest  *int
+	}{
+		{`SELECT COUNT(*) FROM connection_requests WHERE datetime(sent_at) >= datetime(?)`, []interface{}{startOfDay}, &snapshot.ConnectionsSent},
+		{`SELECT COUNT(*) FROM connection_requests WHERE status = 'accepted'`, nil, &snapshot.Accepted},
+		{`SELECT COUNT(*) FROM messages WHERE datetime(sent_at) >= datetime(?)`, []interface{}{startOfDay}, &snapshot.MessagesSent},
+		{`SELECT COUNT(*) FROM connection_requests WHERE has_replied = 1`, nil, &snapshot.Replies},
+	}
+
+	for _, c := range counts {
+		if err := db.conn.QueryRow(c.query, c.args...).Scan(c.dest); err != nil {
+			return snapshot, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+// SaveDailySnapshot upserts the snapshot for its date. Counts are replaced with
+// the latest values; errors accumulate because each run reports only its own.
+func (db *Database) SaveDailySnapshot(snapshot DailySnapshot) error {
+	if snapshot.Date == "" {
+		snapshot.Date = time.Now().Format("2006-01-02")
+	}
+	if snapshot.UpdatedAt.IsZero() {
+		snapshot.UpdatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO daily_snapshots (date, connections_sent, accepted, messages_sent, replies, errors, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(date) DO UPDATE SET
+			connections_sent = excluded.connections_sent,
+			accepted = excluded.accepted,
+			messages_sent = excluded.messages_sent,
+			replies = excluded.replies,
+			errors = daily_snapshots.errors + excluded.errors,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := db.conn.Exec(query,
+		snapshot.Date,
+		snapshot.ConnectionsSent,
+		snapshot.Accepted,
+		snapshot.MessagesSent,
+		snapshot.Replies,
+		snapshot.Errors,
+		snapshot.UpdatedAt,
+	)
+	return err
+}
+
+// GetSnapshotTrend returns the snapshots of the last `days` days (including today), oldest first
+func (db *Database) GetSnapshotTrend(days int) ([]DailySnapshot, error) {
+	since := time.Now().AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+
+	query := `
+		SELECT date, connections_sent, accepted, messages_sent, replies, errors, updated_at
+		FROM daily_snapshots
+		WHERE date >= ?
+		ORDER BY date ASC
+	`
+
+	rows, err := db.conn.Query(query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []DailySnapshot
+	for rows.Next() {
+		var s DailySnapshot
+		err := rows.Scan(
+			&s.Date,
+			&s.ConnectionsSent,
+			&s.Accepted,
+			&s.MessagesSent,
+			&s.Replies,
+			&s.Errors,
+			&s.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSaveDailySnapshotUpsert(t *testing.T) {
+	testDBPath := "./test_snapshots.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	today := time.Now().Format("2006-01-02")
+
+	first := DailySnapshot{Date: today, ConnectionsSent: 3, Accepted: 1, MessagesSent: 2, Replies: 0, Errors: 1}
+	if err := db.SaveDailySnapshot(first); err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+
+	second := DailySnapshot{Date: today, ConnectionsSent: 5, Accepted: 2, MessagesSent: 4, Replies: 1, Errors: 2}
+	if err := db.SaveDailySnapshot(second); err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+
+	var rows int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM daily_snapshots WHERE date = ?`, today).Scan(&rows); err != nil {
+		t.Fatalf("Failed to count snapshots: %v", err)
+	}
+	if rows != 1 {
+		t.Fatalf("Expected 1 snapshot row for %s, got %d", today, rows)
+	}
+
+	trend, err := db.GetSnapshotTrend(1)
+	if err != nil {
+		t.Fatalf("Failed to get trend: %v", err)
+	}
+	if len(trend) != 1 {
+		t.Fatalf("Expected 1 snapshot in trend, got %d", len(trend))
+	}
+
+	got := trend[0]
+	if got.ConnectionsSent != 5 || got.Accepted != 2 || got.MessagesSent != 4 || got.Replies != 1 {
+		t.Errorf("Expected counts to be replaced by latest run, got %+v", got)
+	}
+	if got.Errors != 3 {
+		t.Errorf("Expected errors to accumulate to 3, got %d", got.Errors)
+	}
+}
+
+func TestGetSnapshotTrend(t *testing.T) {
+	testDBPath := "./test_snapshots.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	// Save out of order to verify the trend is sorted oldest first
+	for _, daysAgo := range []int{0, 2, 10, 1} {
+		snapshot := DailySnapshot{
+			Date:            now.AddDate(0, 0, -daysAgo).Format("2006-01-02"),
+			ConnectionsSent: daysAgo,
+		}
+		if err := db.SaveDailySnapshot(snapshot); err != nil {
+			t.Fatalf("Failed to save snapshot: %v", err)
+		}
+	}
+
+	trend, err := db.GetSnapshotTrend(7)
+	if err != nil {
+		t.Fatalf("Failed to get trend: %v", err)
+	}
+
+	if len(trend) != 3 {
+		t.Fatalf("Expected 3 snapshots in the last 7 days, got %d", len(trend))
+	}
+
+	expected := []int{2, 1, 0}
+	for i, s := range trend {
+		if s.ConnectionsSent != expected[i] {
+			t.Errorf("Snapshot %d: expected the one from %d days ago, got %d (%s)", i, expected[i], s.ConnectionsSent, s.Date)
+		}
+	}
+}
+
+func TestCollectDailySnapshot(t *testing.T) {
+	testDBPath := "./test_snapshots.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+
+	requests := []ConnectionRequest{
+		{ProfileID: "today-1", SentAt: now, Status: "pending", CreatedAt: now},
+		{ProfileID: "today-2", SentAt: now, Status: "pending", CreatedAt: now},
+		{ProfileID: "old-1", SentAt: yesterday, Status: "pending", CreatedAt: yesterday},
+	}
+	for _, req := range requests {
+		if err := db.SaveConnectionRequest(req); err != nil {
+			t.Fatalf("Failed to save connection request: %v", err)
+		}
+	}
+	if err := db.UpdateConnectionStatus("old-1", "accepted"); err != nil {
+		t.Fatalf("Failed to update connection status: %v", err)
+	}
+
+	msg := Message{ConnectionID: "old-1", TemplateName: "welcome", MessageContent: "Hi", SentAt: now, CreatedAt: now}
+	if err := db.SaveMessage(msg); err != nil {
+		t.Fatalf("Failed to save message: %v", err)
+	}
+
+	snapshot, err := db.CollectDailySnapshot(4)
+	if err != nil {
+		t.Fatalf("Failed to collect snapshot: %v", err)
+	}
+
+	if snapshot.Date != now.Format("2006-01-02") {
+		t.Errorf("Expected today's date, got %s", snapshot.Date)
+	}
+	if snapshot.ConnectionsSent != 2 {
+		t.Errorf("Expected 2 connections sent today, got %d", snapshot.ConnectionsSent)
+	}
+	if snapshot.Accepted != 1 {
+		t.Errorf("Expected 1 accepted connection, got %d", snapshot.Accepted)
+	}
+	if snapshot.MessagesSent != 1 {
+		t.Errorf("Expected 1 message sent today, got %d", snapshot.MessagesSent)
+	}
+	if snapshot.Errors != 4 {
+		t.Errorf("Expected 4 errors, got %d", snapshot.Errors)
+	}
+}
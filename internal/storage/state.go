@@ -44,6 +44,26 @@ func SaveState(sessionValid bool) error {
 		state.LastLoginTime = existingState.LastLoginTime
 	}
 
+	return writeState(&state)
+}
+
+// RecordRun stamps LastRun with the current time, keeping the rest of the
+// saved state. Call it once a run has logged in so the next one can tell how long ago it was.
+func RecordRun() error {
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		state = &AppState{BrowserDataDir: "./browser_data"}
+	}
+
+	state.LastRun = time.Now()
+	return writeState(state)
+}
+
+// writeState creates or overwrites the state file with state
+func writeState(state *AppState) error {
 	// Ensure the data directory exists
 	if err := os.MkdirAll("data", 0755); err != nil {
 		return err
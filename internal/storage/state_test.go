@@ -86,3 +86,28 @@ func TestStateFileCreation(t *testing.T) {
 		t.Errorf("Failed to read state file: %v", err)
 	}
 }
+
+// TestRecordRun verifies RecordRun stamps LastRun and keeps the session fields
+func TestRecordRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	if err := SaveState(true); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+	before, _ := LoadState()
+
+	if err := RecordRun(); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+
+	after, err := LoadState()
+	if err != nil || after == nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if after.LastRun.Before(before.LastRun) {
+		t.Errorf("LastRun went backwards: %v before %v", after.LastRun, before.LastRun)
+	}
+	if !after.SessionValid || !after.LastLoginTime.Equal(before.LastLoginTime) {
+		t.Errorf("RecordRun changed the session: %+v", after)
+	}
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordDismissedSuggestion remembers that a "People you may know" suggestion was
+// dismissed so it is never reconsidered
+func (db *Database) RecordDismissedSuggestion(profileID string) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO dismissed_suggestions (profile_id, dismissed_at)
+		VALUES (?, ?)
+		ON CONFLICT(profile_id) DO NOTHING
+	`, profileID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record dismissed suggestion %s: %w", profileID, err)
+	}
+	return nil
+}
+
+// IsSuggestionDismissed checks whether a suggestion for the profile was dismissed before
+func (db *Database) IsSuggestionDismissed(profileID string) (bool, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM dismissed_suggestions WHERE profile_id = ?`, profileID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDismissedSuggestions(t *testing.T) {
+	testDBPath := "./test_suggestions.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	dismissed, err := db.IsSuggestionDismissed("alice")
+	if err != nil || dismissed {
+		t.Fatalf("Expected alice not dismissed yet, got %v (err %v)", dismissed, err)
+	}
+
+	if err := db.RecordDismissedSuggestion("alice"); err != nil {
+		t.Fatalf("Failed to record dismissal: %v", err)
+	}
+	// Recording twice is harmless
+	if err := db.RecordDismissedSuggestion("alice"); err != nil {
+		t.Fatalf("Failed to record repeated dismissal: %v", err)
+	}
+
+	dismissed, err = db.IsSuggestionDismissed("alice")
+	if err != nil || !dismissed {
+		t.Errorf("Expected alice dismissed, got %v (err %v)", dismissed, err)
+	}
+
+	dismissed, err = db.IsSuggestionDismissed("bob")
+	if err != nil || dismissed {
+		t.Errorf("Expected bob not dismissed, got %v (err %v)", dismissed, err)
+	}
+}
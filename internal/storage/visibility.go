@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"time"
+)
+
+// VisibilityProbe is the outcome of one shadow-limit probe
+type VisibilityProbe struct {
+	ID                int
+	ProbedAt          time.Time
+	DashboardRead     bool // Whether the dashboard counts below were read
+	ProfileViews      int  // "Profile views" count on the dashboard (last 90 days)
+	SearchAppearances int  // "Search appearances" count on the dashboard (last week)
+	InvitesChecked    int  // Recent pending requests looked for under Sent invitations
+	InvitesMissing    int  // Of those, how many were not listed
+	RiskScore         int  // 0-100
+	RiskLevel         string
+}
+
+// SaveVisibilityProbe records a probe
+func (db *Database) SaveVisibilityProbe(probe VisibilityProbe) error {
+	if probe.ProbedAt.IsZero() {
+		probe.ProbedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO visibility_probes (probed_at, dashboard_read, profile_views, search_appearances, invites_checked, invites_missing, risk_score, risk_level)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := db.conn.Exec(query,
+		probe.ProbedAt,
+		probe.DashboardRead,
+		probe.ProfileViews,
+		probe.SearchAppearances,
+		probe.InvitesChecked,
+		probe.InvitesMissing,
+		probe.RiskScore,
+		probe.RiskLevel,
+	)
+	return err
+}
+
+// GetVisibilityProbes returns the probes of the last `days` days, oldest first
+func (db *Database) GetVisibilityProbes(days int) ([]VisibilityProbe, error) {
+	since := time.Now().AddDate(0, 0, -days)
+
+	query := `
+		SELECT id, probed_at, dashboard_read, profile_views, search_appearances, invites_checked, invites_missing, risk_score, risk_level
+		FROM visibility_probes
+		WHERE datetime(probed_at) >= datetime(?)
+		ORDER BY probed_at ASC, id ASC
+	`
+
+	rows, err := db.conn.Query(query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var probes []VisibilityProbe
+	for rows.Next() {
+		var p VisibilityProbe
+		err := rows.Scan(
+			&p.ID,
+			&p.ProbedAt,
+			&p.DashboardRead,
+			&p.ProfileViews,
+			&p.SearchAppearances,
+			&p.InvitesChecked,
+			&p.InvitesMissing,
+			&p.RiskScore,
+			&p.RiskLevel,
+		)
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, p)
+	}
+
+	return probes, rows.Err()
+}
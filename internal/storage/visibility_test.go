@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestVisibilityProbes(t *testing.T) {
+	testDBPath := "./test_visibility.db"
+	defer os.Remove(testDBPath)
+
+	db, err := InitDB(testDBPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	probes := []VisibilityProbe{
+		{ProbedAt: now.AddDate(0, 0, -40), DashboardRead: true, ProfileViews: 90, RiskLevel: "low"},
+		{ProbedAt: now.AddDate(0, 0, -3), DashboardRead: true, ProfileViews: 80, SearchAppearances: 40, RiskLevel: "low"},
+		{ProbedAt: now, InvitesChecked: 10, InvitesMissing: 6, RiskScore: 40, RiskLevel: "medium"},
+	}
+	for _, p := range probes {
+		if err := db.SaveVisibilityProbe(p); err != nil {
+			t.Fatalf("Failed to save probe: %v", err)
+		}
+	}
+
+	got, err := db.GetVisibilityProbes(30)
+	if err != nil {
+		t.Fatalf("Failed to get probes: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 probes within 30 days, got %d", len(got))
+	}
+
+	if !got[0].DashboardRead || got[0].ProfileViews != 80 || got[0].SearchAppearances != 40 {
+		t.Errorf("Unexpected first probe: %+v", got[0])
+	}
+	if got[1].DashboardRead || got[1].InvitesChecked != 10 || got[1].InvitesMissing != 6 || got[1].RiskScore != 40 || got[1].RiskLevel != "medium" {
+		t.Errorf("Unexpected second probe: %+v", got[1])
+	}
+}
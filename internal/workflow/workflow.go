@@ -0,0 +1,162 @@
+package workflow
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/pkg/utils"
+)
+
+// defaultMaxPause is the longest random pause between shuffled phases
+const defaultMaxPause = 45 * time.Second
+
+// Phase is one named step of a run
+type Phase struct {
+	Name string
+
+	// DependsOn names the phases that must run before this one. Phases that
+	// are not part of the run (e.g. disabled ones) are ignored.
+	DependsOn []string
+
+	Run func() error
+}
+
+// Options controls the order and timing of phases
+type Options struct {
+	// Shuffle runs independent phases in a random order, with a random pause
+	// between phases, so runs don't repeat the same sequence
+	Shuffle  bool
+	Rand     *rand.Rand    // Source for the shuffle and pauses (seed it to replay a run)
+	MaxPause time.Duration // Longest pause between shuffled phases (0 = no pause)
+
+	// ShouldStop is checked before each phase; the run ends when it returns true
+	ShouldStop func() bool
+
+	sleep func(time.Duration)
+}
+
+// Result is the outcome of a phase that ran
+type Result struct {
+	Name string
+	Err  error
+}
+
+// OptionsFromEnv reads WORKFLOW_SHUFFLE and WORKFLOW_MAX_PAUSE_SECONDS (default
+// 45). The shuffle uses the session random generator, so STEALTH_SEED replays it.
+func OptionsFromEnv() Options {
+	opts := Options{
+		Shuffle:  os.Getenv("WORKFLOW_SHUFFLE") == "true",
+		Rand:     utils.SessionRand(),
+		MaxPause: defaultMaxPause,
+	}
+
+	if v := os.Getenv("WORKFLOW_MAX_PAUSE_SECONDS"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil && val >= 0 {
+			opts.MaxPause = time.Duration(val) * time.Second
+		}
+	}
+
+	return opts
+}
+
+// Run executes phases in an order that respects their dependencies: the
+// declared order, or a random one with opts.Shuffle. A failed phase is logged
+// and recorded and the run continues. Returns the results of the phases that
+// ran, or an error without running anything if the dependencies have a cycle.
+func Run(phases []Phase, opts Options) ([]Result, error) {
+	ordered, err := Order(phases, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sleep := opts.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	var results []Result
+	for i, phase := range ordered {
+		if opts.ShouldStop != nil && opts.ShouldStop() {
+			logger.Info("Stop requested - skipping remaining phases")
+			break
+		}
+
+		if i > 0 && opts.Shuffle && opts.MaxPause > 0 && opts.Rand != nil {
+			sleep(time.Duration(opts.Rand.Int63n(int64(opts.MaxPause))))
+		}
+
+		logger.Info(fmt.Sprintf("Phase %d/%d: %s", i+1, len(ordered), phase.Name))
+		err := phase.Run()
+		if err != nil {
+			logger.Error(fmt.Sprintf("Phase %s failed: %s", phase.Name, err.Error()))
+		}
+		results = append(results, Result{Name: phase.Name, Err: err})
+	}
+
+	return results, nil
+}
+
+// Order returns phases sorted so every phase comes after its dependencies.
+// Among the phases whose dependencies have run, the next one is the first
+// declared, or a random one with opts.Shuffle.
+func Order(phases []Phase, opts Options) ([]Phase, error) {
+	present := make(map[string]bool, len(phases))
+	for _, phase := range phases {
+		if present[phase.Name] {
+			return nil, fmt.Errorf("duplicate phase %q", phase.Name)
+		}
+		present[phase.Name] = true
+	}
+
+	done := make(map[string]bool, len(phases))
+	remaining := append([]Phase(nil), phases...)
+	ordered := make([]Phase, 0, len(phases))
+
+	for len(remaining) > 0 {
+		var ready []int
+		for i, phase := range remaining {
+			if dependenciesDone(phase, present, done) {
+				ready = append(ready, i)
+			}
+		}
+		if len(ready) == 0 {
+			return nil, fmt.Errorf("phase dependencies form a cycle among %s", phaseNames(remaining))
+		}
+
+		next := ready[0]
+		if opts.Shuffle && opts.Rand != nil {
+			next = ready[opts.Rand.Intn(len(ready))]
+		}
+
+		phase := remaining[next]
+		ordered = append(ordered, phase)
+		done[phase.Name] = true
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+
+	return ordered, nil
+}
+
+// dependenciesDone reports whether every dependency of phase that is part of
+// the run has already been ordered
+func dependenciesDone(phase Phase, present, done map[string]bool) bool {
+	for _, dep := range phase.DependsOn {
+		if present[dep] && !done[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// phaseNames lists the names of phases for error messages
+func phaseNames(phases []Phase) []string {
+	names := make([]string, len(phases))
+	for i, phase := range phases {
+		names[i] = phase.Name
+	}
+	return names
+}
@@ -0,0 +1,194 @@
+package workflow
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPhases mirrors the run's phases: connect needs search, and the probe
+// comes after everything that sends
+func testPhases(ran *[]string) []Phase {
+	phase := func(name string, deps ...string) Phase {
+		return Phase{Name: name, DependsOn: deps, Run: func() error {
+			*ran = append(*ran, name)
+			return nil
+		}}
+	}
+	return []Phase{
+		phase("warmup"),
+		phase("search"),
+		phase("connect", "search"),
+		phase("mynetwork"),
+		phase("followups"),
+		phase("visibility", "search", "connect", "mynetwork", "followups"),
+	}
+}
+
+// assertDependenciesFirst fails if a phase in order runs before one of its dependencies
+func assertDependenciesFirst(t *testing.T, phases []Phase, order []string) {
+	t.Helper()
+	position := make(map[string]int)
+	for i, name := range order {
+		position[name] = i
+	}
+	for _, phase := range phases {
+		for _, dep := range phase.DependsOn {
+			if position[dep] > position[phase.Name] {
+				t.Fatalf("%s ran before its dependency %s: %v", phase.Name, dep, order)
+			}
+		}
+	}
+}
+
+func TestOrderKeepsDeclaredOrderWithoutShuffle(t *testing.T) {
+	var ran []string
+	phases := testPhases(&ran)
+
+	ordered, err := Order(phases, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"warmup", "search", "connect", "mynetwork", "followups", "visibility"}
+	if got := phaseNames(ordered); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestOrderRespectsDependenciesUnderShuffle(t *testing.T) {
+	var ran []string
+	phases := testPhases(&ran)
+
+	firsts := make(map[string]bool)
+	for seed := int64(0); seed < 200; seed++ {
+		ordered, err := Order(phases, Options{Shuffle: true, Rand: rand.New(rand.NewSource(seed))})
+		if err != nil {
+			t.Fatalf("Seed %d: unexpected error: %v", seed, err)
+		}
+		if len(ordered) != len(phases) {
+			t.Fatalf("Seed %d: expected %d phases, got %d", seed, len(phases), len(ordered))
+		}
+
+		order := phaseNames(ordered)
+		assertDependenciesFirst(t, phases, order)
+		firsts[order[0]] = true
+	}
+
+	// Every phase without dependencies should sometimes go first
+	for _, name := range []string{"warmup", "search", "mynetwork", "followups"} {
+		if !firsts[name] {
+			t.Errorf("%s never ran first in 200 shuffles", name)
+		}
+	}
+}
+
+func TestOrderSameSeedSameOrder(t *testing.T) {
+	var ran []string
+	phases := testPhases(&ran)
+
+	first, _ := Order(phases, Options{Shuffle: true, Rand: rand.New(rand.NewSource(42))})
+	second, _ := Order(phases, Options{Shuffle: true, Rand: rand.New(rand.NewSource(42))})
+	if !reflect.DeepEqual(phaseNames(first), phaseNames(second)) {
+		t.Errorf("Expected the same order for the same seed, got %v and %v", phaseNames(first), phaseNames(second))
+	}
+}
+
+func TestOrderIgnoresAbsentDependencies(t *testing.T) {
+	phases := []Phase{
+		{Name: "connect", DependsOn: []string{"search"}},
+		{Name: "visibility", DependsOn: []string{"connect", "followups"}},
+	}
+
+	ordered, err := Order(phases, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := phaseNames(ordered); !reflect.DeepEqual(got, []string{"connect", "visibility"}) {
+		t.Errorf("Unexpected order %v", got)
+	}
+}
+
+func TestOrderErrors(t *testing.T) {
+	cycle := []Phase{
+		{Name: "warmup"},
+		{Name: "a", DependsOn: []string{"b"}},
+		{Name: "b", DependsOn: []string{"a"}},
+	}
+	if _, err := Order(cycle, Options{}); err == nil || !strings.Contains(err.Error(), "cycle among [a b]") {
+		t.Errorf("Expected a cycle error, got %v", err)
+	}
+
+	duplicate := []Phase{{Name: "search"}, {Name: "search"}}
+	if _, err := Order(duplicate, Options{}); err == nil {
+		t.Error("Expected an error for duplicate phase names")
+	}
+}
+
+func TestRun(t *testing.T) {
+	var ran []string
+	phases := testPhases(&ran)
+	phases[1].Run = func() error {
+		ran = append(ran, "search")
+		return errors.New("search page did not load")
+	}
+
+	var pauses []time.Duration
+	opts := Options{
+		Shuffle:  true,
+		Rand:     rand.New(rand.NewSource(7)),
+		MaxPause: 30 * time.Second,
+		sleep:    func(d time.Duration) { pauses = append(pauses, d) },
+	}
+
+	results, err := Run(phases, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A failed phase doesn't stop the run
+	if len(ran) != len(phases) || len(results) != len(phases) {
+		t.Fatalf("Expected all %d phases to run, ran %v", len(phases), ran)
+	}
+	assertDependenciesFirst(t, phases, ran)
+
+	for i, result := range results {
+		if result.Name != ran[i] {
+			t.Errorf("Result %d is %s, expected %s", i, result.Name, ran[i])
+		}
+		if (result.Err != nil) != (result.Name == "search") {
+			t.Errorf("Unexpected error for %s: %v", result.Name, result.Err)
+		}
+	}
+
+	// A pause between each pair of phases, none before the first
+	if len(pauses) != len(phases)-1 {
+		t.Errorf("Expected %d pauses, got %d", len(phases)-1, len(pauses))
+	}
+	for _, pause := range pauses {
+		if pause < 0 || pause >= opts.MaxPause {
+			t.Errorf("Pause %s outside [0, %s)", pause, opts.MaxPause)
+		}
+	}
+}
+
+func TestRunStopsWhenRequested(t *testing.T) {
+	var ran []string
+	phases := testPhases(&ran)
+
+	opts := Options{
+		ShouldStop: func() bool { return len(ran) == 2 },
+		sleep:      func(time.Duration) { t.Error("Unshuffled runs should not pause") },
+	}
+
+	results, err := Run(phases, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 2 || !reflect.DeepEqual(ran, []string{"warmup", "search"}) {
+		t.Errorf("Expected to stop after two phases, ran %v", ran)
+	}
+}
@@ -3,11 +3,12 @@ package utils
 // Constants for LinkedIn automation
 const (
 	// LinkedIn URLs
-	LinkedInBaseURL     = "https://www.linkedin.com"
-	LinkedInLoginURL    = "https://www.linkedin.com/login"
-	LinkedInFeedURL     = "https://www.linkedin.com/feed/"
-	LinkedInSearchURL   = "https://www.linkedin.com/search/results/people/"
-	LinkedInProfileBase = "https://www.linkedin.com/in/"
+	LinkedInBaseURL      = "https://www.linkedin.com"
+	LinkedInLoginURL     = "https://www.linkedin.com/login"
+	LinkedInFeedURL      = "https://www.linkedin.com/feed/"
+	LinkedInSearchURL    = "https://www.linkedin.com/search/results/people/"
+	LinkedInProfileBase  = "https://www.linkedin.com/in/"
+	LinkedInMyNetworkURL = "https://www.linkedin.com/mynetwork/"
 
 	// Delay ranges (milliseconds)
 	MinLoginDelay  = 800
@@ -146,24 +147,31 @@ var LinkedInLocations = map[string]string{
 	"Madrid":         "103924744",
 }
 
-// Search result selectors
-// ⚠️  WARNING: LinkedIn changes these selectors frequently (every 3-6 months)
-// If search returns 0 results, check the browser inspector and update these:
-// 1. Open LinkedIn search in browser
-// 2. Right-click on a profile card → Inspect
-// 3. Find the updated class names for profile containers
-// 4. Update the constants below
-// Last verified: December 2025
-const (
-	SearchResultContainerSelector = ".reusable-search__result-container"                                                       // Alternative: .search-results-container
-	SearchResultItemSelector      = ".reusable-search__result-container, .entity-result, li.reusable-search__result-container" // Multiple fallback selectors
-	SearchResultTitleSelector     = ".entity-result__title-text a"                                                             // Alternative: .app-aware-link
-	SearchResultSubtitleSelector  = ".entity-result__primary-subtitle"                                                         // Alternative: .entity-result__subtitle
-	SearchResultSecondarySelector = ".entity-result__secondary-subtitle"                                                       // Alternative: .entity-result__summary
-	SearchResultLinkSelector      = "a.app-aware-link"                                                                         // Alternative: a[href*='/in/']
-	PaginationNextButtonSelector  = ".artdeco-pagination__button--next"                                                        // Alternative: button[aria-label='Next']
-	PaginationDisabledClass       = "artdeco-button--disabled"                                                                 // Check for 'disabled' attribute too
-)
+// LinkedIn company IDs for the currentCompany search facet.
+// Lookups ignore case; companies not listed fall back to the company keyword.
+// ⚠️  Verify an ID by filtering a search by the company and reading currentCompany from the URL
+var LinkedInCompanies = map[string]string{
+	"Google":     "1441",
+	"Microsoft":  "1035",
+	"Amazon":     "1586",
+	"Apple":      "162479",
+	"Meta":       "10667",
+	"Netflix":    "165158",
+	"LinkedIn":   "1337",
+	"IBM":        "1009",
+	"Oracle":     "1028",
+	"Salesforce": "3185",
+	"Adobe":      "1480",
+	"Intel":      "1053",
+	"NVIDIA":     "3608",
+	"Tesla":      "15564",
+	"Uber":       "1815218",
+	"Airbnb":     "309694",
+	"Stripe":     "2135371",
+	"Spotify":    "207470",
+	"Deloitte":   "1038",
+	"Accenture":  "1033",
+}
 
 // Search constraints
 const (
@@ -172,36 +180,40 @@ const (
 	SearchDelaySeconds      = 2
 )
 
-// Connection request selectors
-// ⚠️  WARNING: LinkedIn changes these selectors frequently
-// Last verified: December 2025
-const (
-	ConnectButtonSelector           = "button[aria-label*='Connect']"                           // Main connect button on profile
-	ConnectButtonAltSelector        = ".pvs-profile-actions__action button:has-text('Connect')" // Alternative
-	MoreActionsButtonSelector       = "button[aria-label='More actions']"                       // More actions dropdown (for 3rd-degree connections)
-	MoreActionsButtonAltSelector    = "button:has-text('More')"                                 // Alternative More button
-	AddNoteButtonSelector           = "button[aria-label='Add a note']"                         // Button to add personalized note
-	ConnectionNoteTextareaSelector  = "#custom-message"                                         // Textarea for connection note
-	SendConnectionButtonSelector    = "button[aria-label='Send now']"                           // Send connection request button
-	SendConnectionButtonAltSelector = "button[type='submit']:has-text('Send')"                  // Alternative send button
-	AlreadyConnectedSelector        = "span:has-text('Connected')"                              // Indicator that already connected
-	PendingConnectionSelector       = "span:has-text('Pending')"                                // Indicator that connection pending
-)
+// Voyager profile API call the profile page makes; its response carries the relationship state
+// ⚠️  WARNING: LinkedIn changes this endpoint without notice
+const VoyagerProfileAPIPath = "/voyager/api/identity/dash/profiles"
+
+// Text identifying the weekly invitation limit message (see Selectors.WeeklyLimitAlert)
+const WeeklyLimitTextPattern = `(?i)weekly\s+(invitation\s+)?limit`
 
-// Messaging selectors
-// ⚠️  WARNING: LinkedIn changes these selectors frequently
-// Last verified: December 2025
+// Text of the "Invitation sent" pill a profile shows once an invitation is out (see Selectors.InvitationSentIndicator)
+const InvitationSentTextPattern = `(?i)\binvitation\s+sent\b`
+
+// Mutual connection insights on search results (see Selectors.SearchResultInsight).
+// Forms: "12 mutual connections", "Jane Doe and 12 other mutual connections",
+// "Jane Doe is a mutual connection", "Jane Doe and John Smith are mutual connections"
 const (
-	MessageButtonSelector        = "button[aria-label*='Message']"                           // Message button on profile
-	MessageButtonAltSelector     = ".pvs-profile-actions__action button:has-text('Message')" // Alternative
-	MessageComposerSelector      = ".msg-form__contenteditable"                              // Message composition area
-	MessageComposerAltSelector   = "div[role='textbox'][contenteditable='true']"             // Alternative composer
-	SendMessageButtonSelector    = "button[type='submit'][aria-label*='Send']"               // Send message button
-	SendMessageButtonAltSelector = ".msg-form__send-button"                                  // Alternative send button
-	ConversationSelector         = ".msg-overlay-conversation-bubble"                        // Message conversation container
-	MessageConfirmationSelector  = ".msg-s-message-list__event"                              // Message sent confirmation
+	MutualCountTextPattern  = `(?i)^([\d,]+)\+?\s+mutual\s+connections?\b`
+	MutualOthersTextPattern = `(?i)^(.+?)\s+and\s+([\d,]+)\+?\s+others?\s+mutual\s+connections?\b`
+	MutualNamesTextPattern  = `(?i)^(.+?)\s+(?:is\s+a|are)\s+mutual\s+connections?\b`
 )
 
+// Markers of the #OpenToWork photo frame in a search result's photo markup (see
+// Selectors.SearchResultPhoto): the ring overlay's class or image, or the photo's
+// alt text ("Jane Doe, #OPEN_TO_WORK"). The #Hiring frame does not match.
+const OpenToWorkPattern = `(?i)#open_?to_?work|open-to-work|opentowork|open_to_work`
+
+// Text identifying the commercial use limit message (see Selectors.CommercialUseLimit)
+const CommercialUseLimitTextPattern = `(?i)(commercial\s+use\s+limit|monthly\s+limit\s+for\s+profile\s+searches)`
+
+// Text of LinkedIn's soft "unusual activity" warning banner (see Selectors.UnusualActivityBanner),
+// e.g. "We noticed unusual activity on your account" or "We've restricted some activity"
+const UnusualActivityTextPattern = `(?i)(noticed\s+(some\s+)?unusual\s+activity|unusual\s+activity\s+(on|from)\s+your\s+account|we[’']ve\s+restricted\s+some\s+activity)`
+
+// Answer for the "How do you know X?" step when CONNECTION_RELATIONSHIP is unset
+const DefaultRelationshipOption = "Other"
+
 // Connection/Message limits
 const (
 	ConnectionNoteMaxChars = 300  // LinkedIn's character limit for connection notes
@@ -2,20 +2,23 @@ package utils
 
 import (
 	"fmt"
-	"math/rand"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // GenerateRandomDelay creates a random delay within range
 func GenerateRandomDelay(minMs, maxMs int) time.Duration {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r := SessionRand()
 	delay := r.Intn(maxMs-minMs+1) + minMs
 	return time.Duration(delay) * time.Millisecond
 }
 
 // GenerateRandomCoordinates creates random X, Y coordinates
 func GenerateRandomCoordinates(minX, maxX, minY, maxY int) (int, int) {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r := SessionRand()
 	x := r.Intn(maxX-minX+1) + minX
 	y := r.Intn(maxY-minY+1) + minY
 	return x, y
@@ -23,13 +26,13 @@ func GenerateRandomCoordinates(minX, maxX, minY, maxY int) (int, int) {
 
 // GenerateRandomScrollDistance creates random scroll distance
 func GenerateRandomScrollDistance(minDist, maxDist int) int {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r := SessionRand()
 	return r.Intn(maxDist-minDist+1) + minDist
 }
 
 // GenerateSessionID creates a unique session identifier
 func GenerateSessionID() string {
-	return fmt.Sprintf("session_%d_%d", time.Now().Unix(), rand.Intn(10000))
+	return fmt.Sprintf("session_%d_%d", time.Now().Unix(), SessionRand().Intn(10000))
 }
 
 // FormatDuration formats milliseconds to human-readable string
@@ -77,44 +80,219 @@ func IsLinkedInCheckpoint(url string) bool {
 	}
 
 	for _, pattern := range checkpointPatterns {
-		if len(url) > 0 && ContainsString([]string{url}, pattern) {
+		if strings.Contains(url, pattern) {
 			return true
 		}
 	}
 	return false
 }
 
-// ExtractProfileID extracts the profile ID from a LinkedIn URL
-func ExtractProfileID(url string) string {
-	// Remove query parameters
-	// URLs are typically https://www.linkedin.com/in/profile-id/
-	// or /in/profile-id/
-
-	// Find /in/
-	inIdx := -1
-	for i := 0; i < len(url)-3; i++ {
-		if url[i:i+4] == "/in/" {
-			inIdx = i
-			break
+// IsLinkedInLoginWall checks if the current URL is a LinkedIn login or auth wall page
+// LinkedIn redirects here when the session cookie is missing or expired. Only
+// the path is matched, by whole segments, so a query string that mentions
+// /login or a profile slug like /in/login-expert does not count.
+func IsLinkedInLoginWall(rawURL string) bool {
+	loginPaths := []string{
+		"/login",
+		"/authwall",
+		"/signup",
+		"/uas/login",
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	path := strings.ToLower(strings.TrimSuffix(u.Path, "/"))
+
+	for _, prefix := range loginPaths {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
 		}
 	}
+	return false
+}
 
-	if inIdx != -1 {
-		start := inIdx + 4
-		end := len(url)
+// profileURNPrefixes are LinkedIn URN types whose last segment is a profile ID
+var profileURNPrefixes = []string{
+	"urn:li:fs_miniProfile:",
+	"urn:li:fsd_profile:",
+	"urn:li:fs_profile:",
+	"urn:li:member:",
+}
+
+// ExtractProfileID extracts the profile ID from a LinkedIn profile URL or URN.
+// Handles absolute and relative URLs (/in/john-doe, /in/john-doe/,
+// /in/john-doe/detail/contact-info/), query strings and fragments,
+// percent-encoded IDs and miniProfile URNs (urn:li:fs_miniProfile:ACoAAB...).
+// Returns "" if no profile ID is found.
+func ExtractProfileID(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
 
-		// Find next slash or ?
-		for i := start; i < len(url); i++ {
-			if url[i] == '/' || url[i] == '?' {
-				end = i
-				break
+	// miniProfile / profile URNs carry the ID as their last segment
+	for _, prefix := range profileURNPrefixes {
+		if idx := strings.Index(rawURL, prefix); idx != -1 {
+			id := rawURL[idx+len(prefix):]
+			if end := strings.IndexAny(id, ",)/?#&"); end != -1 {
+				id = id[:end]
 			}
+			return id
+		}
+	}
+
+	// Drop query and fragment; fall back to manual trimming if parsing fails
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.EscapedPath()
+	} else if end := strings.IndexAny(rawURL, "?#"); end != -1 {
+		path = rawURL[:end]
+	}
+
+	// Profile ID is the segment right after "in"
+	segments := strings.Split(path, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] != "in" || segments[i+1] == "" {
+			continue
 		}
 
-		if start < end {
-			return url[start:end]
+		id, err := url.PathUnescape(segments[i+1])
+		if err != nil {
+			return segments[i+1]
 		}
+		return id
 	}
 
 	return ""
 }
+
+// subtitleSeparator splits a search result's secondary subtitle into its parts
+var subtitleSeparator = regexp.MustCompile(`\s*[|·•]\s*`)
+
+// usStateAbbreviation matches the ", CA" ending of "Austin, TX"-style locations
+var usStateAbbreviation = regexp.MustCompile(`,\s*[A-Z]{2}$`)
+
+// locationWords appear in LinkedIn's region names ("Greater Boston",
+// "San Francisco Bay Area", "Dallas-Fort Worth Metroplex")
+var locationWords = []string{"area", "greater", "metropolitan", "metroplex", "region", "county", "province", "remote"}
+
+// companySuffixes mark a part as a company even when it contains a comma ("Acme, Inc.")
+var companySuffixes = []string{"inc", "inc.", "llc", "ltd", "ltd.", "gmbh", "corp", "corp.", "corporation", "plc", "ag", "bv", "s.a."}
+
+// SplitCompanyLocation splits a search result's secondary subtitle, such as
+// "Acme Corp | San Francisco Bay Area", into company and location. Parts may
+// come in either order and be separated by |, · or •; the location is the last
+// part that looks like a place (a known location, a region word, or "City,
+// Region") and the company is the first other part. A single unrecognized part
+// is taken as the location, which is what the subtitle usually holds.
+func SplitCompanyLocation(secondary string) (company, location string) {
+	var parts []string
+	for _, part := range subtitleSeparator.Split(strings.TrimSpace(secondary), -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		if isCompanyName(parts[0]) {
+			return parts[0], ""
+		}
+		return "", parts[0]
+	}
+
+	locationIdx := -1
+	for i := len(parts) - 1; i >= 0; i-- {
+		if looksLikeLocation(parts[i]) {
+			locationIdx = i
+			break
+		}
+	}
+	if locationIdx == -1 {
+		// Nothing recognizable: keep the usual "Company | Location" order
+		return parts[0], parts[len(parts)-1]
+	}
+
+	for i, part := range parts {
+		if i != locationIdx {
+			company = part
+			break
+		}
+	}
+	return company, parts[locationIdx]
+}
+
+// looksLikeLocation reports whether text reads like a place name
+func looksLikeLocation(text string) bool {
+	if isCompanyName(text) {
+		return false
+	}
+
+	lower := strings.ToLower(text)
+	for name := range LinkedInLocations {
+		if strings.Contains(lower, strings.ToLower(name)) {
+			return true
+		}
+	}
+	for _, word := range strings.FieldsFunc(lower, func(r rune) bool { return r == ' ' || r == ',' || r == '-' }) {
+		if ContainsString(locationWords, word) {
+			return true
+		}
+	}
+
+	// "Austin, TX" or "Porto, Porto District, Portugal"
+	return usStateAbbreviation.MatchString(text) || strings.Contains(text, ", ")
+}
+
+// isCompanyName reports whether text ends with a legal-entity suffix like Inc or GmbH
+func isCompanyName(text string) bool {
+	fields := strings.Fields(strings.ToLower(text))
+	return len(fields) > 1 && ContainsString(companySuffixes, strings.TrimSuffix(fields[len(fields)-1], ","))
+}
+
+// relativeTimePattern matches LinkedIn's relative time labels, long ("Sent 3
+// weeks ago") or short ("3w"), capturing the count and the unit
+var relativeTimePattern = regexp.MustCompile(`^(\d+|an?|one)\s*(minutes?|mins?|hours?|hrs?|days?|weeks?|wks?|months?|mos?|years?|yrs?|m|h|d|w|y)(?:\s+ago)?$`)
+
+// ParseRelativeTime converts a relative time label such as "Sent 1 week ago",
+// "3 days ago", "yesterday" or "2mo" into an absolute time before now.
+// Returns the zero time when the label isn't recognized. Months and years
+// are calendar months and years, so "1 month ago" is the same day last month.
+func ParseRelativeTime(label string, now time.Time) time.Time {
+	text := strings.ToLower(strings.Join(strings.Fields(label), " "))
+	text = strings.TrimSuffix(text, ".")
+	text = strings.TrimPrefix(text, "sent ")
+
+	switch text {
+	case "today", "just now", "now", "moments ago":
+		return now
+	case "yesterday":
+		return now.AddDate(0, 0, -1)
+	}
+
+	match := relativeTimePattern.FindStringSubmatch(text)
+	if match == nil {
+		return time.Time{}
+	}
+
+	count := 1
+	if n, err := strconv.Atoi(match[1]); err == nil {
+		count = n
+	}
+
+	switch unit := match[2]; {
+	case unit == "m" || strings.HasPrefix(unit, "min"):
+		return now.Add(-time.Duration(count) * time.Minute)
+	case strings.HasPrefix(unit, "h"):
+		return now.Add(-time.Duration(count) * time.Hour)
+	case strings.HasPrefix(unit, "d"):
+		return now.AddDate(0, 0, -count)
+	case strings.HasPrefix(unit, "w"):
+		return now.AddDate(0, 0, -7*count)
+	case strings.HasPrefix(unit, "mo"):
+		return now.AddDate(0, -count, 0)
+	default: // y, yr, year
+		return now.AddDate(-count, 0, 0)
+	}
+}
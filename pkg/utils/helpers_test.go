@@ -118,3 +118,197 @@ func TestReverseString(t *testing.T) {
 		})
 	}
 }
+
+// TestIsLinkedInCheckpoint tests checkpoint URL detection
+func TestIsLinkedInCheckpoint(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{"https://www.linkedin.com/checkpoint/challenge/AgE123", true},
+		{"https://www.linkedin.com/uas/login-verification?x=1", true},
+		{"https://www.linkedin.com/feed/", false},
+		{"https://www.linkedin.com/search/results/people/?keywords=go", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := IsLinkedInCheckpoint(tt.url); got != tt.expected {
+				t.Errorf("IsLinkedInCheckpoint(%q) = %v, want %v", tt.url, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestIsLinkedInLoginWall tests login/auth wall URL detection
+func TestIsLinkedInLoginWall(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{"https://www.linkedin.com/login?session_redirect=%2Fsearch", true},
+		{"https://www.linkedin.com/authwall?trk=gf&sessionRedirect=x", true},
+		{"https://www.linkedin.com/signup/cold-join", true},
+		{"https://www.linkedin.com/feed/", false},
+		{"https://www.linkedin.com/search/results/people/?keywords=go", false},
+		{"https://www.linkedin.com/uas/login?session_redirect=x", true},
+		{"https://www.linkedin.com/login/", true},
+		{"https://www.linkedin.com/in/login-expert/", false},
+		{"https://www.linkedin.com/search/results/people/?keywords=%2Flogin", false},
+		{"https://www.linkedin.com/feed/?redirect=/authwall", false},
+		{"https://www.linkedin.com/company/signup-labs/", false},
+		{"https://www.linkedin.com/loginhelp", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := IsLinkedInLoginWall(tt.url); got != tt.expected {
+				t.Errorf("IsLinkedInLoginWall(%q) = %v, want %v", tt.url, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestExtractProfileID tests profile ID extraction from the URL shapes LinkedIn uses
+func TestExtractProfileID(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"absolute with trailing slash", "https://www.linkedin.com/in/john-doe/", "john-doe"},
+		{"absolute without trailing slash", "https://www.linkedin.com/in/john-doe", "john-doe"},
+		{"relative with trailing slash", "/in/john-doe/", "john-doe"},
+		{"relative without trailing slash", "/in/john-doe", "john-doe"},
+		{"sub-path", "/in/john-doe/detail/contact-info/", "john-doe"},
+		{"absolute sub-path", "https://www.linkedin.com/in/john-doe/overlay/about-this-profile/", "john-doe"},
+		{"query string", "https://www.linkedin.com/in/john-doe?miniProfileUrn=urn%3Ali%3Afs_miniProfile%3AACoAAB", "john-doe"},
+		{"query after slash", "/in/john-doe/?trk=people-search", "john-doe"},
+		{"fragment", "/in/john-doe#experience", "john-doe"},
+		{"percent-encoded", "https://www.linkedin.com/in/j%C3%B6rg-m%C3%BCller/", "jörg-müller"},
+		{"no scheme", "linkedin.com/in/jane-smith", "jane-smith"},
+		{"locale subdomain", "https://de.linkedin.com/in/max-mustermann", "max-mustermann"},
+		{"miniProfile URN", "urn:li:fs_miniProfile:ACoAABcdEFG", "ACoAABcdEFG"},
+		{"fsd_profile URN", "urn:li:fsd_profile:ACoAAHijKLM", "ACoAAHijKLM"},
+		{"URN in tuple", "urn:li:fsd_entityResultViewModel:(urn:li:fsd_profile:ACoAANopQRS,SEARCH_SRP,DEFAULT)", "ACoAANopQRS"},
+		{"whitespace", "  /in/john-doe/  ", "john-doe"},
+		{"company page", "https://www.linkedin.com/company/acme/", ""},
+		{"in without id", "https://www.linkedin.com/in/", ""},
+		{"name containing in", "https://www.linkedin.com/in/robin-inman/", "robin-inman"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExtractProfileID(tt.url)
+			if result != tt.expected {
+				t.Errorf("ExtractProfileID(%q) = %q, want %q", tt.url, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSplitCompanyLocation tests splitting secondary subtitles as LinkedIn renders them
+func TestSplitCompanyLocation(t *testing.T) {
+	tests := []struct {
+		name      string
+		secondary string
+		company   string
+		location  string
+	}{
+		{"company and location", "Google | San Francisco Bay Area", "Google", "San Francisco Bay Area"},
+		{"location first", "Greater Seattle Area | Microsoft", "Microsoft", "Greater Seattle Area"},
+		{"location only", "San Francisco Bay Area", "", "San Francisco Bay Area"},
+		{"unknown place only", "Ann Arbor, Michigan, United States", "", "Ann Arbor, Michigan, United States"},
+		{"company only", "Acme, Inc.", "Acme, Inc.", ""},
+		{"city and state abbreviation", "Stripe | Austin, TX", "Stripe", "Austin, TX"},
+		{"multiple pipes", "Acme Corp | Platform Team | Berlin, Germany", "Acme Corp", "Berlin, Germany"},
+		{"multiple pipes location in the middle", "Acme Corp | London Area, United Kingdom | Hybrid", "Acme Corp", "London Area, United Kingdom"},
+		{"middle dot separator", "Shopify · Remote", "Shopify", "Remote"},
+		{"no spaces around pipe", "Globex|Toronto, Ontario, Canada", "Globex", "Toronto, Ontario, Canada"},
+		{"company with comma suffix", "Initech, LLC | Dallas-Fort Worth Metroplex", "Initech, LLC", "Dallas-Fort Worth Metroplex"},
+		{"unrecognized parts", "Hooli | Springfield", "Hooli", "Springfield"},
+		{"whitespace and empty parts", "  Umbrella |  | Greater Boston  ", "Umbrella", "Greater Boston"},
+		{"empty", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			company, location := SplitCompanyLocation(tt.secondary)
+			if company != tt.company || location != tt.location {
+				t.Errorf("SplitCompanyLocation(%q) = %q, %q; want %q, %q", tt.secondary, company, location, tt.company, tt.location)
+			}
+		})
+	}
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	now := time.Date(2026, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		label string
+		want  time.Time
+	}{
+		// Sent Invitations page labels
+		{"Sent 1 week ago", now.AddDate(0, 0, -7)},
+		{"Sent 3 weeks ago", now.AddDate(0, 0, -21)},
+		{"Sent 3 days ago", now.AddDate(0, 0, -3)},
+		{"Sent yesterday", now.AddDate(0, 0, -1)},
+		{"Sent today", now},
+		{"Sent 2 months ago", time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)},
+		{"Sent 1 year ago", time.Date(2025, 3, 15, 14, 30, 0, 0, time.UTC)},
+		{"Sent 5 hours ago", now.Add(-5 * time.Hour)},
+		{"Sent 10 minutes ago", now.Add(-10 * time.Minute)},
+
+		// Without the "Sent" prefix
+		{"1 week ago", now.AddDate(0, 0, -7)},
+		{"3 days ago", now.AddDate(0, 0, -3)},
+		{"yesterday", now.AddDate(0, 0, -1)},
+		{"a week ago", now.AddDate(0, 0, -7)},
+		{"an hour ago", now.Add(-time.Hour)},
+		{"one month ago", time.Date(2026, 2, 15, 14, 30, 0, 0, time.UTC)},
+		{"just now", now},
+
+		// Case, spacing and punctuation
+		{"  SENT   2 Weeks\nago. ", now.AddDate(0, 0, -14)},
+		{"Yesterday", now.AddDate(0, 0, -1)},
+
+		// Short forms
+		{"3w", now.AddDate(0, 0, -21)},
+		{"4d", now.AddDate(0, 0, -4)},
+		{"2mo", time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)},
+		{"5h", now.Add(-5 * time.Hour)},
+		{"45m", now.Add(-45 * time.Minute)},
+		{"1y", time.Date(2025, 3, 15, 14, 30, 0, 0, time.UTC)},
+		{"2 mins ago", now.Add(-2 * time.Minute)},
+		{"3 yrs ago", time.Date(2023, 3, 15, 14, 30, 0, 0, time.UTC)},
+		{"2 wks ago", now.AddDate(0, 0, -14)},
+	}
+
+	for _, tt := range tests {
+		if got := ParseRelativeTime(tt.label, now); !got.Equal(tt.want) {
+			t.Errorf("ParseRelativeTime(%q) = %s, want %s", tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestParseRelativeTimeUnrecognized(t *testing.T) {
+	now := time.Date(2026, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	for _, label := range []string{
+		"",
+		"Sent",
+		"Withdraw",
+		"Jane Doe",
+		"Senior Engineer at Acme",
+		"weeks ago",
+		"3 fortnights ago",
+		"March 3, 2026",
+		"-2 days ago",
+	} {
+		if got := ParseRelativeTime(label, now); !got.IsZero() {
+			t.Errorf("ParseRelativeTime(%q) = %s, want the zero time", label, got)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedSource makes a rand.Source safe for concurrent use
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// Session random generator shared by all stealth and helper functions.
+// One generator per session avoids identical time-based seeds in tight loops
+// and lets a run be replayed by reusing its seed.
+var (
+	sessionMu   sync.Mutex
+	sessionSeed int64
+	sessionRand *rand.Rand
+)
+
+func init() {
+	SeedSessionRand(time.Now().UnixNano())
+}
+
+// SeedSessionRand replaces the session random generator with one seeded with seed.
+// Call it at startup, before any randomized behavior runs.
+func SeedSessionRand(seed int64) {
+	r := rand.New(&lockedSource{src: rand.NewSource(seed).(rand.Source64)})
+
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	sessionSeed = seed
+	sessionRand = r
+}
+
+// SessionRand returns the session random generator; it is safe for concurrent use
+func SessionRand() *rand.Rand {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	return sessionRand
+}
+
+// SessionSeed returns the seed the session random generator was created with
+func SessionSeed() int64 {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	return sessionSeed
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestSeedSessionRandDeterministic tests that a fixed seed replays the same sequence
+func TestSeedSessionRandDeterministic(t *testing.T) {
+	sample := func() []int {
+		r := SessionRand()
+		values := make([]int, 20)
+		for i := range values {
+			values[i] = r.Intn(1000)
+		}
+		return values
+	}
+
+	SeedSessionRand(42)
+	first := sample()
+	SeedSessionRand(42)
+	second := sample()
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Sequences diverged at %d: %v vs %v", i, first, second)
+		}
+	}
+
+	if SessionSeed() != 42 {
+		t.Errorf("SessionSeed() = %d, want 42", SessionSeed())
+	}
+
+	SeedSessionRand(43)
+	third := sample()
+	same := true
+	for i := range first {
+		if first[i] != third[i] {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Error("Expected a different seed to yield a different sequence")
+	}
+}
+
+// TestHelpersUseSessionRand tests that the random helpers follow the session seed
+func TestHelpersUseSessionRand(t *testing.T) {
+	SeedSessionRand(7)
+	d1 := GenerateRandomDelay(100, 5000)
+	x1, y1 := GenerateRandomCoordinates(0, 1000, 0, 1000)
+	s1 := GenerateRandomScrollDistance(100, 5000)
+
+	SeedSessionRand(7)
+	d2 := GenerateRandomDelay(100, 5000)
+	x2, y2 := GenerateRandomCoordinates(0, 1000, 0, 1000)
+	s2 := GenerateRandomScrollDistance(100, 5000)
+
+	if d1 != d2 || x1 != x2 || y1 != y2 || s1 != s2 {
+		t.Errorf("Expected identical values for the same seed: (%v %d %d %d) vs (%v %d %d %d)", d1, x1, y1, s1, d2, x2, y2, s2)
+	}
+}
+
+// TestSessionRandConcurrentUse tests that the session generator is safe across goroutines
+func TestSessionRandConcurrentUse(t *testing.T) {
+	SeedSessionRand(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				SessionRand().Intn(100)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,317 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// SelectorSet holds every CSS selector used to find elements on LinkedIn pages.
+// JSON tags are the names accepted in a SELECTORS_FILE override.
+type SelectorSet struct {
+	// Search results
+	SearchResultContainer   string `json:"search_result_container"`
+	SearchResultItem        string `json:"search_result_item"`
+	SearchResultTitle       string `json:"search_result_title"`
+	SearchResultSubtitle    string `json:"search_result_subtitle"`
+	SearchResultSecondary   string `json:"search_result_secondary"`
+	SearchResultLocation    string `json:"search_result_location"`
+	SearchResultLink        string `json:"search_result_link"`
+	PaginationNextButton    string `json:"pagination_next_button"`
+	PaginationDisabledClass string `json:"pagination_disabled_class"`
+
+	// Search results in the newer layout, where each result carries data-chameleon-result-urn
+	SearchResultContainerV2 string `json:"search_result_container_v2"`
+	SearchResultNameV2      string `json:"search_result_name_v2"`
+	SearchResultHeadlineV2  string `json:"search_result_headline_v2"`
+	SearchResultLocationV2  string `json:"search_result_location_v2"`
+	SearchResultDegreeV2    string `json:"search_result_degree_v2"`
+
+	// Insight lines under a search result (both layouts), e.g. "Jane and 12 other mutual connections"
+	SearchResultInsight string `json:"search_result_insight"`
+	// Photo container of a search result, which carries the #OpenToWork frame
+	SearchResultPhoto string `json:"search_result_photo"`
+
+	// Connect and Pending buttons on a search result card (connecting without opening the profile)
+	SearchResultConnectButton string `json:"search_result_connect_button"`
+	SearchResultPendingButton string `json:"search_result_pending_button"`
+
+	// Connection requests
+	ConnectButton           string `json:"connect_button"`
+	ConnectButtonAlt        string `json:"connect_button_alt"`
+	MoreActionsButton       string `json:"more_actions_button"`
+	MoreActionsButtonAlt    string `json:"more_actions_button_alt"`
+	AddNoteButton           string `json:"add_note_button"`
+	ConnectionNoteTextarea  string `json:"connection_note_textarea"`
+	SendConnectionButton    string `json:"send_connection_button"`
+	SendConnectionButtonAlt string `json:"send_connection_button_alt"`
+	AlreadyConnected        string `json:"already_connected"`
+	PendingConnection       string `json:"pending_connection"`
+	RelationshipRadio       string `json:"relationship_radio"`
+	RelationshipContinue    string `json:"relationship_continue"`
+	CreatorBadge            string `json:"creator_badge"`
+	InvitePageForm          string `json:"invite_page_form"`
+	ConnectButtonDisabled   string `json:"connect_button_disabled"`
+	InvitationSentIndicator string `json:"invitation_sent_indicator"`
+
+	// Note field variants of the invite modal, tried in order after connection_note_textarea
+	ConnectionNoteInputs []string `json:"connection_note_inputs"`
+
+	// Action bar of the sticky profile header that appears on scroll
+	ProfileStickyActions string `json:"profile_sticky_actions"`
+
+	// Limit warnings
+	WeeklyLimitAlert   string `json:"weekly_limit_alert"`
+	CommercialUseLimit string `json:"commercial_use_limit"`
+
+	// Banners and alerts that may carry the soft "unusual activity" warning
+	UnusualActivityBanner string `json:"unusual_activity_banner"`
+
+	// "People you may know" (My Network page)
+	PYMKCard           string `json:"pymk_card"`
+	PYMKCardLink       string `json:"pymk_card_link"`
+	PYMKCardName       string `json:"pymk_card_name"`
+	PYMKCardOccupation string `json:"pymk_card_occupation"`
+	PYMKConnectButton  string `json:"pymk_connect_button"`
+	PYMKDismissButton  string `json:"pymk_dismiss_button"`
+
+	// Group member list (linkedin.com/groups/<id>/members/)
+	GroupMemberItem     string `json:"group_member_item"`
+	GroupMemberName     string `json:"group_member_name"`
+	GroupMemberHeadline string `json:"group_member_headline"`
+	ShowMoreResults     string `json:"show_more_results"`
+
+	// Sent invitations (My Network → Manage invitations → Sent)
+	SentInvitationLink string `json:"sent_invitation_link"`
+	SentInvitationCard string `json:"sent_invitation_card"`
+
+	// Messaging
+	MessageButton        string `json:"message_button"`
+	MessageButtonAlt     string `json:"message_button_alt"`
+	MessageComposer      string `json:"message_composer"`
+	MessageComposerAlt   string `json:"message_composer_alt"`
+	SendMessageButton    string `json:"send_message_button"`
+	SendMessageButtonAlt string `json:"send_message_button_alt"`
+	Conversation         string `json:"conversation"`
+	MessageConfirmation  string `json:"message_confirmation"`
+	SentMessageBubble    string `json:"sent_message_bubble"`
+
+	// Messaging inbox conversation list (linkedin.com/messaging)
+	InboxConversation         string `json:"inbox_conversation"`
+	InboxConversationLink     string `json:"inbox_conversation_link"`
+	InboxConversationName     string `json:"inbox_conversation_name"`
+	InboxConversationSnippet  string `json:"inbox_conversation_snippet"`
+	InboxConversationUnread   string `json:"inbox_conversation_unread"`
+	InboxConversationOptions  string `json:"inbox_conversation_options"`
+	InboxConversationMenuItem string `json:"inbox_conversation_menu_item"`
+
+	// Cookie-consent banner shown to fresh browser profiles
+	CookieBanner       string `json:"cookie_banner"`
+	CookieAcceptButton string `json:"cookie_accept_button"`
+	CookieRejectButton string `json:"cookie_reject_button"`
+
+	// Logged-in account ("Me" menu and the feed's identity card)
+	MeMenuButton        string `json:"me_menu_button"`
+	IdentityProfileLink string `json:"identity_profile_link"`
+	IdentityName        string `json:"identity_name"`
+
+	// Verification code input of the sign-in challenge (code sent by email or text)
+	LoginChallengePinInput string `json:"login_challenge_pin_input"`
+
+	// "Verify it's you" passkey prompt at sign-in and its way around the passkey
+	PasskeyPrompt     string `json:"passkey_prompt"`
+	PasskeySkipButton string `json:"passkey_skip_button"`
+
+	// Blocking overlays (cookie banner, nag modals, messaging overlay)
+	BlockingOverlayDismiss   []string `json:"blocking_overlay_dismiss"`
+	BlockingOverlayContainer string   `json:"blocking_overlay_container"`
+}
+
+// DefaultSelectors returns the built-in selectors.
+// ⚠️  WARNING: LinkedIn changes these selectors frequently (every 3-6 months)
+// If search returns 0 results or buttons are not found, check the browser
+// inspector and override the stale entries with SELECTORS_FILE:
+// 1. Open the page in the browser
+// 2. Right-click on the element → Inspect
+// 3. Find the updated class names / attributes
+// 4. Put them in the JSON file under the selector's name (e.g. "connect_button")
+func DefaultSelectors() SelectorSet {
+	return SelectorSet{
+		SearchResultContainer:   ".reusable-search__result-container",                                                       // Alternative: .search-results-container
+		SearchResultItem:        ".reusable-search__result-container, .entity-result, li.reusable-search__result-container", // Multiple fallback selectors
+		SearchResultTitle:       ".entity-result__title-text a",                                                             // Alternative: .app-aware-link
+		SearchResultSubtitle:    ".entity-result__primary-subtitle",                                                         // Alternative: .entity-result__subtitle
+		SearchResultSecondary:   ".entity-result__secondary-subtitle",                                                       // Alternative: .entity-result__summary
+		SearchResultLocation:    ".entity-result__location",                                                                 // Dedicated location line, when the card has one
+		SearchResultLink:        "a.app-aware-link",                                                                         // Alternative: a[href*='/in/']
+		PaginationNextButton:    ".artdeco-pagination__button--next",                                                        // Alternative: button[aria-label='Next']
+		PaginationDisabledClass: "artdeco-button--disabled",                                                                 // Check for 'disabled' attribute too
+
+		SearchResultContainerV2: "[data-chameleon-result-urn]",                         // Result wrapper carrying the profile URN
+		SearchResultNameV2:      "a[href*='/in/'] span[aria-hidden='true']",            // Visible name inside the profile link
+		SearchResultHeadlineV2:  "div.t-14.t-black.t-normal",                           // "Role at Company" headline
+		SearchResultLocationV2:  "div.t-14.t-normal:not(.t-black)",                     // Location line under the headline
+		SearchResultDegreeV2:    ".entity-result__badge-text span[aria-hidden='true']", // "• 2nd" connection degree badge
+
+		SearchResultInsight: ".entity-result__insights, .entity-result__simple-insight-text", // Mutual connections, shared groups, followers
+		SearchResultPhoto:   ".entity-result__universal-image, .ivm-image-view-model",        // Photo plus its frame ring overlay
+
+		SearchResultConnectButton: "button[aria-label^='Invite'][aria-label$='to connect']", // "Invite Jane Doe to connect"
+		SearchResultPendingButton: "button[aria-label^='Pending']",                          // "Pending, click to withdraw invitation sent to Jane Doe"
+
+		ConnectButton:           "button[aria-label*='Connect']",                                                              // Main connect button on profile
+		ConnectButtonAlt:        ".pvs-profile-actions__action button:has-text('Connect')",                                    // Alternative
+		MoreActionsButton:       "button[aria-label='More actions']",                                                          // More actions dropdown (for 3rd-degree connections)
+		MoreActionsButtonAlt:    "button:has-text('More')",                                                                    // Alternative More button
+		AddNoteButton:           "button[aria-label='Add a note']",                                                            // Button to add personalized note
+		ConnectionNoteTextarea:  "#custom-message",                                                                            // Textarea for connection note
+		SendConnectionButton:    "button[aria-label='Send now']",                                                              // Send connection request button
+		SendConnectionButtonAlt: "button[type='submit']:has-text('Send')",                                                     // Alternative send button
+		AlreadyConnected:        "span:has-text('Connected')",                                                                 // Indicator that already connected
+		PendingConnection:       "span:has-text('Pending')",                                                                   // Indicator that connection pending
+		RelationshipRadio:       ".artdeco-modal input[type='radio']",                                                         // "How do you know X?" options
+		RelationshipContinue:    ".artdeco-modal button[aria-label='Connect'], .artdeco-modal button.artdeco-button--primary", // Continue after selecting
+		CreatorBadge:            ".pv-top-card__creator-badge, .pvs-header__creator-badge",                                    // Creator-mode badge in the profile header
+		InvitePageForm:          "main form[action*='invite'], main #custom-message, main textarea[name='message']",           // Full-page invite form (shown instead of the modal)
+
+		ConnectButtonDisabled:   "main button[aria-label*='Connect'][disabled], main button[aria-label*='Connect'][aria-disabled='true']", // Greyed-out Connect (invitation already out)
+		InvitationSentIndicator: "main .pvs-profile-actions span, main .artdeco-inline-feedback__message",                                 // Elements that may carry the "Invitation sent" pill
+
+		// Newer modals keep the #custom-message id on the textarea or swap it for a rich-text box
+		ConnectionNoteInputs: []string{
+			"textarea#custom-message",
+			"textarea[name='message']",
+			".artdeco-modal div[role='textbox'][contenteditable='true'], main form div[role='textbox'][contenteditable='true']",
+		},
+
+		ProfileStickyActions: ".pv-profile-sticky-header-v2__actions-container, .scaffold-layout-toolbar .pvs-sticky-header-profile-actions", // Some A/B variants only render Connect here
+
+		WeeklyLimitAlert:   ".ip-fuse-limit-alert, .artdeco-modal",                                                                   // Alert/modal that may carry the weekly limit message
+		CommercialUseLimit: ".search-paywall__info, .search-commercial-use-limit, .artdeco-inline-feedback--warning, .artdeco-modal", // Banner/modal that may carry the commercial use limit message
+
+		UnusualActivityBanner: ".artdeco-global-alert, .artdeco-inline-feedback, .artdeco-toast-item, [role='alert']", // Feed/global banners and toasts
+
+		PYMKCard:           "li.discover-entity-type-card, div[data-view-name='cohort-card']", // Suggestion card container
+		PYMKCardLink:       "a[href*='/in/']",                                                 // Profile link inside a card
+		PYMKCardName:       ".discover-person-card__name",                                     // Suggested person's name
+		PYMKCardOccupation: ".discover-person-card__occupation",                               // Suggested person's headline
+		PYMKConnectButton:  "button[aria-label^='Invite']",                                    // Connect button on a card
+		PYMKDismissButton:  "button[aria-label^='Dismiss']",                                   // Dismiss (X) button on a card
+
+		GroupMemberItem:     ".groups-members-list li.artdeco-list__item, li.groups-members-list__typeahead-result", // One member row
+		GroupMemberName:     ".artdeco-entity-lockup__title",                                                        // Member's name
+		GroupMemberHeadline: ".artdeco-entity-lockup__subtitle",                                                     // Member's "Role at Company" headline
+		ShowMoreResults:     "button.scaffold-finite-scroll__load-button",                                           // "Show more results" under lazy-loaded lists
+
+		SentInvitationLink: "main .invitation-card a[href*='/in/'], main [data-view-name*='invitation'] a[href*='/in/']", // Invitee's profile link on a sent invitation
+		SentInvitationCard: "main .invitation-card, main [data-view-name*='invitation']",                                 // Sent invitation card, carrying the "Sent X ago" label
+
+		MessageButton:        "button[aria-label*='Message']",                                                       // Message button on profile
+		MessageButtonAlt:     ".pvs-profile-actions__action button:has-text('Message')",                             // Alternative
+		MessageComposer:      ".msg-form__contenteditable",                                                          // Message composition area
+		MessageComposerAlt:   "div[role='textbox'][contenteditable='true']",                                         // Alternative composer
+		SendMessageButton:    "button[type='submit'][aria-label*='Send']",                                           // Send message button
+		SendMessageButtonAlt: ".msg-form__send-button",                                                              // Alternative send button
+		Conversation:         ".msg-overlay-conversation-bubble",                                                    // Message conversation container
+		MessageConfirmation:  ".msg-s-message-list__event",                                                          // Message sent confirmation
+		SentMessageBubble:    ".msg-s-event-listitem:not(.msg-s-event-listitem--other) .msg-s-event-listitem__body", // Body of a message we sent
+
+		InboxConversation:         ".msg-conversation-listitem",                                                                 // One thread in the conversation list
+		InboxConversationLink:     "a.msg-conversation-listitem__link",                                                          // Thread link, carrying the thread ID
+		InboxConversationName:     ".msg-conversation-listitem__participant-names",                                              // Other participant's name ("A, B" for groups)
+		InboxConversationSnippet:  ".msg-conversation-card__message-snippet",                                                    // Last message preview ("You: ..." when ours)
+		InboxConversationUnread:   ".msg-conversation-card__unread-count, .notification-badge--show",                            // Unread badge on a thread
+		InboxConversationOptions:  ".msg-conversation-card__inbox-shortcuts button, button[aria-label*='conversation options']", // Thread options menu button
+		InboxConversationMenuItem: ".msg-thread-actions__dropdown-option, .artdeco-dropdown__item, [role='menuitem']",           // Item of the options menu; "Mark as unread" is matched by its text
+
+		CookieBanner:       ".artdeco-global-alert[type='COOKIE_CONSENT'], section[data-test-global-alert*='cookie']", // Consent banner at the top or bottom of the page
+		CookieAcceptButton: "button[action-type='ACCEPT']",                                                            // "Accept" inside the banner
+		CookieRejectButton: "button[action-type='DENY']",                                                              // "Reject" inside the banner
+
+		MeMenuButton:        "button.global-nav__primary-link-me-menu-trigger",                                                  // "Me" avatar in the top nav
+		IdentityProfileLink: ".global-nav__me-content a[href*='/in/'], .feed-identity-module a[href*='/in/']",                   // "View Profile" link in the Me menu, or the feed's profile card
+		IdentityName:        ".global-nav__me-content .t-16, .feed-identity-module__actor-meta .t-16, img.global-nav__me-photo", // Own name (the avatar image carries it as alt text)
+
+		LoginChallengePinInput: "input#input__email_verification_pin, input#input__phone_verification_pin, input[name='pin']", // "Enter the code" field
+
+		PasskeyPrompt:     "form[action*='passkey'], [data-test-id*='passkey'], #passkey-challenge",                                                                // "Verify it's you" passkey prompt
+		PasskeySkipButton: "button[data-test-id*='passkey-skip'], button[aria-label*='Skip'], button[aria-label*='Use password'], a[data-test-id*='use-password']", // "Skip" / "Use password instead"
+
+		// These overlays can sit on top of a button and swallow the click
+		BlockingOverlayDismiss: []string{
+			"button[action-type='ACCEPT']",                                     // Cookie consent banner
+			".artdeco-global-alert__dismiss",                                   // Global alert banner
+			"button.artdeco-modal__dismiss",                                    // Nag/upsell modals
+			"button[data-control-name='overlay.close_conversation_window']",    // Open messaging bubble
+			"button[data-control-name='overlay.minimize_connection_list_bar']", // Messaging list overlay
+		},
+		// A dismiss button is skipped if its container holds the click target
+		BlockingOverlayContainer: "[role='dialog'], .artdeco-modal, .artdeco-global-alert, .msg-overlay-conversation-bubble, .msg-overlay-list-bubble",
+	}
+}
+
+// Selectors is the selector set consulted by the automation code.
+// It starts as DefaultSelectors and is overlaid by LoadSelectorOverrides.
+var Selectors = DefaultSelectors()
+
+// LoadSelectorOverrides reads a JSON object of selector name → CSS value from
+// path and overlays it on Selectors. Selectors missing from the file keep their
+// current value. Unknown names and empty values are rejected so a typo does not
+// silently leave a stale selector in place; on error Selectors is unchanged.
+func LoadSelectorOverrides(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read selectors file: %w", err)
+	}
+
+	merged, err := overlaySelectors(Selectors, data)
+	if err != nil {
+		return fmt.Errorf("invalid selectors file %s: %w", path, err)
+	}
+
+	Selectors = merged
+	return nil
+}
+
+// overlaySelectors decodes data on top of a copy of base and validates the result
+func overlaySelectors(base SelectorSet, data []byte) (SelectorSet, error) {
+	// Copy the slice so decoding never writes into base's backing array
+	base.BlockingOverlayDismiss = append([]string(nil), base.BlockingOverlayDismiss...)
+	base.ConnectionNoteInputs = append([]string(nil), base.ConnectionNoteInputs...)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&base); err != nil {
+		return SelectorSet{}, err
+	}
+
+	if name := emptySelector(base); name != "" {
+		return SelectorSet{}, fmt.Errorf("selector %q is empty", name)
+	}
+	return base, nil
+}
+
+// emptySelector returns the JSON name of the first empty selector, or ""
+func emptySelector(set SelectorSet) string {
+	v := reflect.ValueOf(set)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		empty := false
+		switch field.Kind() {
+		case reflect.String:
+			empty = field.String() == ""
+		case reflect.Slice:
+			empty = field.Len() == 0
+			for j := 0; j < field.Len() && !empty; j++ {
+				empty = field.Index(j).String() == ""
+			}
+		}
+		if empty {
+			return t.Field(i).Tag.Get("json")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSelectorsFile writes content to a temporary selectors file
+func writeSelectorsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "selectors.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write selectors file: %v", err)
+	}
+	return path
+}
+
+// resetSelectors restores the defaults once the test finishes
+func resetSelectors(t *testing.T) {
+	t.Helper()
+	Selectors = DefaultSelectors()
+	t.Cleanup(func() { Selectors = DefaultSelectors() })
+}
+
+// TestLoadSelectorOverrides tests that listed selectors are overridden
+func TestLoadSelectorOverrides(t *testing.T) {
+	resetSelectors(t)
+
+	path := writeSelectorsFile(t, `{
+		"connect_button": "button.new-connect",
+		"blocking_overlay_dismiss": ["button.close-banner"]
+	}`)
+
+	if err := LoadSelectorOverrides(path); err != nil {
+		t.Fatalf("LoadSelectorOverrides() error = %v", err)
+	}
+
+	if Selectors.ConnectButton != "button.new-connect" {
+		t.Errorf("ConnectButton = %q, want %q", Selectors.ConnectButton, "button.new-connect")
+	}
+	if len(Selectors.BlockingOverlayDismiss) != 1 || Selectors.BlockingOverlayDismiss[0] != "button.close-banner" {
+		t.Errorf("BlockingOverlayDismiss = %v, want [button.close-banner]", Selectors.BlockingOverlayDismiss)
+	}
+}
+
+// TestLoadSelectorOverridesKeepsDefaults tests that unspecified selectors keep their defaults
+func TestLoadSelectorOverridesKeepsDefaults(t *testing.T) {
+	resetSelectors(t)
+	defaults := DefaultSelectors()
+
+	path := writeSelectorsFile(t, `{"message_button": "button.msg"}`)
+	if err := LoadSelectorOverrides(path); err != nil {
+		t.Fatalf("LoadSelectorOverrides() error = %v", err)
+	}
+
+	if Selectors.ConnectButton != defaults.ConnectButton {
+		t.Errorf("ConnectButton = %q, want default %q", Selectors.ConnectButton, defaults.ConnectButton)
+	}
+	if Selectors.SearchResultItem != defaults.SearchResultItem {
+		t.Errorf("SearchResultItem = %q, want default %q", Selectors.SearchResultItem, defaults.SearchResultItem)
+	}
+	if len(Selectors.BlockingOverlayDismiss) != len(defaults.BlockingOverlayDismiss) {
+		t.Errorf("BlockingOverlayDismiss = %v, want defaults", Selectors.BlockingOverlayDismiss)
+	}
+	if Selectors.MessageButton != "button.msg" {
+		t.Errorf("MessageButton = %q, want %q", Selectors.MessageButton, "button.msg")
+	}
+}
+
+// TestLoadSelectorOverridesRejectsInvalid tests that bad files leave Selectors unchanged
+func TestLoadSelectorOverridesRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown name", `{"conect_button": "button.x"}`},
+		{"empty value", `{"connect_button": ""}`},
+		{"empty list", `{"blocking_overlay_dismiss": []}`},
+		{"wrong type", `{"connect_button": 5}`},
+		{"malformed json", `{"connect_button": `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetSelectors(t)
+
+			if err := LoadSelectorOverrides(writeSelectorsFile(t, tt.content)); err == nil {
+				t.Fatal("LoadSelectorOverrides() error = nil, want error")
+			}
+			if Selectors.ConnectButton != DefaultSelectors().ConnectButton {
+				t.Errorf("ConnectButton changed to %q after a failed load", Selectors.ConnectButton)
+			}
+		})
+	}
+
+	if err := LoadSelectorOverrides(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadSelectorOverrides() on a missing file: error = nil, want error")
+	}
+}
@@ -0,0 +1,724 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"linkedin-automation/internal/automation"
+	"linkedin-automation/internal/browser"
+	"linkedin-automation/internal/logger"
+	"linkedin-automation/internal/notify"
+	"linkedin-automation/internal/stealth"
+	"linkedin-automation/internal/storage"
+	"linkedin-automation/internal/workflow"
+	"linkedin-automation/pkg/utils"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// runner holds what the phases of a run share: the database, the rate limiter
+// and the logged-in browser page
+type runner struct {
+	db          *storage.Database
+	rateLimiter *automation.RateLimiter
+	br          *rod.Browser
+	page        *rod.Page
+
+	// False when the acceptance-rate guard has paused connection requests
+	connectionsAllowed bool
+
+	// Errors seen during this run, saved with the daily snapshot
+	runErrors int
+}
+
+// startRunner starts the browser and logs in to LinkedIn, reusing the saved
+// session or imported cookies when they still work. The returned function
+// closes the browser.
+func startRunner(db *storage.Database, rateLimiter *automation.RateLimiter) (*runner, func(), error) {
+	// Nothing may be sent during a cool-off, so don't open LinkedIn at all
+	if err := automation.CheckCoolOff(); err != nil {
+		return nil, nil, err
+	}
+
+	// Overlay selector overrides so stale selectors can be fixed without recompiling
+	if selectorsFile := os.Getenv("SELECTORS_FILE"); selectorsFile != "" {
+		if err := utils.LoadSelectorOverrides(selectorsFile); err != nil {
+			return nil, nil, fmt.Errorf("failed to load selector overrides: %w", err)
+		}
+		logger.Info("Loaded selector overrides from " + selectorsFile)
+	}
+
+	r := &runner{db: db, rateLimiter: rateLimiter, connectionsAllowed: true}
+
+	// Stop sending connection requests if the recent acceptance rate is too low
+	if err := automation.CheckAcceptanceRate(db, automation.GetAcceptanceGuardConfig()); err != nil {
+		logger.Warning("Connection requests disabled for this run: " + err.Error())
+		r.connectionsAllowed = false
+	}
+
+	// Check for existing session
+	logger.Info("Checking for existing session...")
+	state, err := storage.LoadState()
+	if err != nil {
+		logger.Warning("Failed to load state: " + err.Error())
+	}
+
+	sessionValid := false
+	if state != nil && storage.IsSessionValid(state) {
+		logger.Info("Valid session found! Skipping login...")
+		sessionValid = true
+	} else {
+		logger.Info("No valid session found, login will be required")
+	}
+
+	// Start the browser instance with persistent session support
+	// CHROME_BIN and CHROME_FLAGS point Rod at a custom Chrome build
+	browserConfig := browser.DefaultBrowserConfig()
+	browserConfig.BrowserBinaryPath = os.Getenv("CHROME_BIN")
+	browserConfig.ExtraLaunchFlags = browser.ParseLaunchFlags(os.Getenv("CHROME_FLAGS"))
+
+	r.br, err = browser.StartBrowserWithConfig(browserConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start browser: %w", err)
+	}
+	closeBrowser := func() { browser.CloseBrowser(r.br) }
+
+	// Apply fingerprint masking BEFORE any page loads
+	// STEALTH_MODE (off, basic, advanced, maximum) controls masking and behavior intensity
+	stealthMode := stealth.ModeFromEnv()
+	stealth.SetMode(stealthMode)
+	// MOUSE_* settings trade human-like mouse paths for speed
+	stealth.SetMouseConfig(stealth.MouseConfigFromEnv())
+	// STEALTH_SEED replays a previous run's random behavior; log the seed so any run can be reproduced
+	seed := stealth.SeedFromEnv()
+	logger.Info(fmt.Sprintf("Stealth random seed: %d (set STEALTH_SEED=%d to reproduce)", seed, seed))
+	logger.Info(fmt.Sprintf("Applying fingerprint masking (stealth mode: %s)...", stealthMode))
+	browser.ApplyFingerprintMasking(r.br)
+
+	// Reuse a session from the user's own browser (cookies.txt export); the
+	// feed check below still decides whether the login can be skipped
+	if cookiesFile := os.Getenv("LINKEDIN_COOKIES_FILE"); cookiesFile != "" {
+		cookies, err := storage.ImportNetscapeCookies(cookiesFile)
+		if err != nil {
+			logger.Warning("Failed to import cookies, falling back to the saved session: " + err.Error())
+		} else if err := r.br.SetCookies(proto.CookiesToParams(cookies)); err != nil {
+			logger.Warning("Failed to set imported cookies: " + err.Error())
+		} else {
+			logger.Info(fmt.Sprintf("Imported %d LinkedIn cookies from %s", len(cookies), cookiesFile))
+			sessionValid = true
+		}
+	}
+
+	if err := r.logIn(sessionValid); err != nil {
+		closeBrowser()
+		return nil, nil, err
+	}
+
+	// With several accounts, make sure this session is the intended one before anything is sent
+	if err := r.verifyAccount(); err != nil {
+		closeBrowser()
+		return nil, nil, err
+	}
+	return r, closeBrowser, nil
+}
+
+// verifyAccount logs which account the session belongs to and, with
+// EXPECTED_ACCOUNT_ID set, aborts unless it is that account
+func (r *runner) verifyAccount() error {
+	expected := os.Getenv("EXPECTED_ACCOUNT_ID")
+
+	name, profileID, err := automation.GetLoggedInIdentity(r.page)
+	if err != nil {
+		if expected != "" {
+			return fmt.Errorf("cannot confirm the logged-in account is %s: %w", expected, err)
+		}
+		logger.Warning("Could not read the logged-in account: " + err.Error())
+		return nil
+	}
+
+	logger.Info(fmt.Sprintf("Logged in as %s (%s)", name, profileID))
+	return automation.VerifyAccount(expected, profileID)
+}
+
+// logIn opens LinkedIn, trying the feed first when the session looks valid and
+// logging in with LINKEDIN_EMAIL and LINKEDIN_PASSWORD otherwise
+func (r *runner) logIn(sessionValid bool) error {
+	var err error
+	if sessionValid {
+		// Try to navigate to LinkedIn home page directly
+		logger.Info("Attempting to access LinkedIn with existing session...")
+		r.page, err = browser.OpenPage(r.br, "https://www.linkedin.com/feed/")
+		if err != nil {
+			return fmt.Errorf("failed to open LinkedIn: %w", err)
+		}
+
+		// Wait a moment for page to load
+		if err := r.page.WaitLoad(); err != nil {
+			logger.Warning("Feed page did not finish loading: " + err.Error())
+		}
+
+		// A fresh profile with imported cookies still gets the consent banner
+		if _, err := automation.DismissCookieBanner(r.page); err != nil {
+			logger.Warning("Cookie banner not dismissed: " + err.Error())
+		}
+
+		// Check if we're actually logged in by checking the current URL
+		currentURL := ""
+		if info, err := r.page.Info(); err == nil {
+			currentURL = info.URL
+		} else {
+			logger.Warning("Failed to read page URL: " + err.Error())
+		}
+		if strings.HasPrefix(currentURL, "https://www.linkedin.com/feed") {
+			logger.Info("Successfully accessed LinkedIn with saved session!")
+			return nil
+		}
+
+		// Session expired, need to login
+		logger.Warning("Session expired, proceeding with login...")
+	}
+
+	// Open the LinkedIn login page
+	r.page, err = browser.OpenPage(r.br, "https://www.linkedin.com/login")
+	if err != nil {
+		return fmt.Errorf("failed to open LinkedIn login page: %w", err)
+	}
+
+	// Read LinkedIn credentials from environment variables
+	email := os.Getenv("LINKEDIN_EMAIL")
+	password := os.Getenv("LINKEDIN_PASSWORD")
+
+	if email == "" || password == "" {
+		return errors.New("LINKEDIN_EMAIL or LINKEDIN_PASSWORD not set in .env file")
+	}
+
+	// Perform the login action with credentials
+	if err := automation.LoginLinkedln(r.page, email, password); err != nil {
+		// Invalidate session on failed login
+		storage.InvalidateSession()
+		return fmt.Errorf("login failed: %w", err)
+	}
+	logger.Info("Login Successful")
+
+	// Save successful login state
+	if err := storage.SaveState(true); err != nil {
+		logger.Warning("Failed to save state: " + err.Error())
+	}
+	return nil
+}
+
+// ensureConnected reconnects to the browser before a phase: long runs can lose
+// the CDP websocket, and one drop shouldn't fail every remaining page call. It
+// also closes pages other than the run's, which would otherwise pile up.
+// Returns an error when the browser can't be reached again.
+func (r *runner) ensureConnected() error {
+	reconnected, err := browser.EnsureConnected(r.br, r.page)
+	if err != nil {
+		return fmt.Errorf("browser connection check failed: %w", err)
+	}
+	r.page = reconnected
+
+	// Tabs left over from the previous phase (or the login flow) only hold memory
+	if closed, err := browser.ReclaimResources(r.br, r.page); err != nil {
+		logger.Warning("Failed to close extra browser pages: " + err.Error())
+	} else if closed > 0 {
+		logger.Info(fmt.Sprintf("Closed %d extra browser pages", closed))
+	}
+	return nil
+}
+
+// reconnecting wraps a phase so it starts on a live connection and, if the
+// connection drops partway through, is run once more after reconnecting.
+// Phases skip what they already finished, so the rerun picks up the rest.
+func (r *runner) reconnecting(run func() error) func() error {
+	return func() error {
+		if err := r.ensureConnected(); err != nil {
+			return err
+		}
+
+		err := run()
+		if !browser.Disconnected(r.br, r.page) {
+			return err
+		}
+
+		logger.Warning("Browser connection lost during the phase, reconnecting to finish it")
+		if err := r.ensureConnected(); err != nil {
+			return err
+		}
+		return run()
+	}
+}
+
+// phases returns the run's phases (Steps 7-10.4 of the full workflow). Login
+// precedes them all; with WORKFLOW_SHUFFLE=true independent phases run in a
+// random order with random pauses between them, so runs don't repeat one
+// sequence. With only set (a subcommand), exactly the named phases run, since
+// asking for one enables it; otherwise the ENABLE_* settings decide.
+func (r *runner) phases(only []string) []workflow.Phase {
+	enabled := func(name string, byEnv bool) bool {
+		if only == nil {
+			return byEnv
+		}
+		for _, want := range only {
+			if want == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	var phases []workflow.Phase
+
+	// Step 7: Execute comprehensive stealth actions (mid-run when shuffled)
+	if enabled("warmup", true) {
+		phases = append(phases, workflow.Phase{Name: "warmup", Run: r.warmUp})
+	}
+
+	// Step 8: Execute LinkedIn people search
+	if enabled("search", true) {
+		phases = append(phases, workflow.Phase{Name: "search", Run: r.search})
+	}
+
+	// Step 8.5: Collect members of the GROUP_URLS groups and attendees of the EVENT_URLS events
+	if enabled("groups", os.Getenv("GROUP_URLS") != "" || os.Getenv("EVENT_URLS") != "") {
+		phases = append(phases, workflow.Phase{Name: "groups", Run: r.scrapeGroups})
+	}
+
+	// Step 9: Send connection requests (if enabled)
+	// NOTE: This step is redundant if we are doing immediate connections during the search.
+	// However, it's useful for processing profiles found in previous runs.
+	if r.connectionsAllowed && enabled("connect", os.Getenv("ENABLE_CONNECTIONS") == "true") {
+		phases = append(phases, workflow.Phase{Name: "connect", DependsOn: []string{"search", "groups"}, Run: r.connect})
+	}
+
+	// Step 9.5: Connect from "People you may know" suggestions (if enabled)
+	if r.connectionsAllowed && enabled("mynetwork", os.Getenv("ENABLE_MYNETWORK_CONNECTIONS") == "true") {
+		phases = append(phases, workflow.Phase{Name: "mynetwork", Run: r.connectFromMyNetwork})
+	}
+
+	// Step 10: Execute daily follow-up workflow (Connection checks, Reply detection, Messaging)
+	if enabled("followups", os.Getenv("ENABLE_MESSAGING") == "true" || os.Getenv("CHECK_CONNECTION_STATUS") == "true") {
+		phases = append(phases, workflow.Phase{Name: "followups", Run: r.followUps})
+	}
+
+	// Step 10.4: Probe for signs of a shadow-limited account (report visibility shows the trend).
+	// Runs after the phases that send, so it sees the day's final state.
+	if enabled("visibility", os.Getenv("ENABLE_VISIBILITY_PROBE") == "true") {
+		phases = append(phases, workflow.Phase{Name: "visibility", DependsOn: []string{"search", "connect", "mynetwork", "followups"}, Run: r.probeVisibility})
+	}
+
+	for i := range phases {
+		phases[i].Run = r.reconnecting(phases[i].Run)
+	}
+	return phases
+}
+
+// runPhases runs the phases named in only (every enabled phase when nil),
+// counting failed phases as run errors
+func (r *runner) runPhases(only []string) {
+	workflowOpts := workflow.OptionsFromEnv()
+	workflowOpts.ShouldStop = func() bool {
+		return automation.StopRequested() || automation.CoolingOff()
+	}
+	results, err := workflow.Run(r.phases(only), workflowOpts)
+	if err != nil {
+		logger.Error("Invalid workflow: " + err.Error())
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			r.runErrors++
+		}
+	}
+}
+
+// saveSnapshot saves today's outcome snapshot for trend reporting (report trend)
+func (r *runner) saveSnapshot() {
+	if snapshot, err := r.db.CollectDailySnapshot(r.runErrors); err != nil {
+		logger.Warning("Failed to collect daily snapshot: " + err.Error())
+	} else if err := r.db.SaveDailySnapshot(snapshot); err != nil {
+		logger.Warning("Failed to save daily snapshot: " + err.Error())
+	}
+}
+
+// warmUp browses a noise page (the feed after login) like a person arriving:
+// mouse movements, hovers and scrolling
+func (r *runner) warmUp() error {
+	// Shuffled, the warm-up can follow another phase; browse a noise page like on arrival
+	noisePages := automation.GetNoisePages()
+	if info, err := r.page.Info(); err == nil && !automation.OnNoisePage(info.URL, noisePages) {
+		url := automation.PickNoisePage(utils.SessionRand(), noisePages)
+		browser.EnforceNavGap()
+		if err := r.page.Navigate(url); err != nil {
+			return fmt.Errorf("failed to open %s: %w", url, err)
+		}
+		if err := r.page.WaitLoad(); err != nil {
+			logger.Warning(url + " did not finish loading: " + err.Error())
+		}
+	}
+
+	// The feed is where LinkedIn shows its "unusual activity" banner
+	automation.AfterPageLoad(r.page)
+
+	logger.Info("Starting advanced human-like behavior simulation...")
+
+	// 7.1: Random mouse movements with Bézier curves
+	logger.Info("Executing Bézier curve mouse movements...")
+	stealth.MoveMouseRandomly(r.page)
+
+	// 7.2: Hover over random elements (links, buttons)
+	logger.Info("Hovering over interactive elements...")
+	if err := stealth.HoverRandomElements(r.page); err != nil {
+		logger.Warning("Failed to hover elements: " + err.Error())
+	}
+
+	// 7.3: Scroll through the feed as it lazy-loads, stopping at its end
+	logger.Info("Executing natural scrolling patterns...")
+	if err := stealth.ScrollThroughContent(r.page, 3); err != nil {
+		logger.Warning("Failed to scroll the feed: " + err.Error())
+	}
+
+	// 7.4: Idle pauses (maximum stealth mode only)
+	stealth.IdleNoise(r.page)
+	return nil
+}
+
+// search runs the people search from the SEARCH_* settings and, with
+// ENABLE_CONNECTIONS, connects to a few of the new profiles straight away
+func (r *runner) search() error {
+	logger.Info("Starting LinkedIn people search...")
+
+	// Check rate limit before searching
+	err := r.rateLimiter.CheckDailyLimit(automation.TaskSearch)
+	canSearch := (err == nil)
+
+	if canSearch {
+		// Configure search parameters from environment variables
+		searchConfig := automation.SearchConfig{
+			Keywords:           os.Getenv("SEARCH_KEYWORDS"),
+			JobTitle:           os.Getenv("SEARCH_JOB_TITLE"),
+			Company:            os.Getenv("SEARCH_COMPANY"),
+			CurrentCompanyOnly: os.Getenv("SEARCH_CURRENT_COMPANY_ONLY") == "true",
+			Location:           os.Getenv("SEARCH_LOCATION"),
+			MaxPages:           3, // Limit to 3 pages for now
+			SkipDuplicates:     true,
+			DuplicateDays:      30,
+
+			RandomStartPage: os.Getenv("SEARCH_RANDOM_START_PAGE") == "true",
+			SkipIfRunToday:  os.Getenv("SEARCH_SKIP_IF_RUN_TODAY") == "true",
+
+			IncludeTitleKeywords: automation.ParseTitleKeywords(os.Getenv("SEARCH_INCLUDE_TITLE_KEYWORDS")),
+			ExcludeTitleKeywords: automation.ParseTitleKeywords(os.Getenv("SEARCH_EXCLUDE_TITLE_KEYWORDS")),
+
+			OpenToWorkOnly: os.Getenv("SEARCH_OPEN_TO_WORK_ONLY") == "true",
+		}
+		if os.Getenv("SEARCH_START_PAGE_MAX") != "" {
+			fmt.Sscanf(os.Getenv("SEARCH_START_PAGE_MAX"), "%d", &searchConfig.StartPageMax)
+		}
+		if os.Getenv("SEARCH_MIN_MUTUAL_CONNECTIONS") != "" {
+			fmt.Sscanf(os.Getenv("SEARCH_MIN_MUTUAL_CONNECTIONS"), "%d", &searchConfig.MinMutualConnections)
+		}
+
+		// Use default values if environment variables are not set
+		if searchConfig.Keywords == "" {
+			searchConfig.Keywords = "software engineer"
+		}
+		if searchConfig.Location == "" {
+			searchConfig.Location = "San Francisco Bay Area"
+		}
+
+		logger.Info("Search configuration:")
+		logger.Info(fmt.Sprintf("  Keywords: %s", searchConfig.Keywords))
+		logger.Info(fmt.Sprintf("  Job Title: %s", searchConfig.JobTitle))
+		logger.Info(fmt.Sprintf("  Company: %s", searchConfig.Company))
+		logger.Info(fmt.Sprintf("  Location: %s", searchConfig.Location))
+
+		// Connect straight from the result cards instead of visiting each profile
+		if r.connectionsAllowed && os.Getenv("ENABLE_CONNECTIONS") == "true" && os.Getenv("SEARCH_CONNECT_FROM_CARDS") == "true" {
+			return r.searchAndConnect(searchConfig)
+		}
+
+		// Execute the search
+		searchResults, searchStats, err := automation.SearchPeople(r.page, r.db, searchConfig)
+		if err != nil {
+			// Session died mid-run - force a fresh login on the next run
+			if errors.Is(err, automation.ErrNotAuthenticated) {
+				logger.Warning("Session is no longer authenticated - invalidating saved session")
+				storage.InvalidateSession()
+			}
+
+			// More searches this month would only return empty pages
+			if errors.Is(err, automation.ErrCommercialUseLimit) {
+				logger.Warning("LinkedIn's monthly search limit is exhausted - pause searching until it resets at the start of next month")
+			}
+
+			return fmt.Errorf("search failed: %w", err)
+		} else if searchStats.Skipped {
+			logger.Info("Search skipped - the same search already ran today")
+		} else {
+			// Record search action in rate limiter
+			if err := r.rateLimiter.RecordAction(automation.TaskSearch); err != nil {
+				logger.Warning("Failed to record search action: " + err.Error())
+			}
+
+			r.runErrors += searchStats.ErrorCount
+
+			// Display search statistics
+			logger.Info("Search completed successfully!")
+			fmt.Println("\n========== Search Statistics ==========")
+			fmt.Printf("Total profiles found: %d\n", searchStats.TotalFound)
+			fmt.Printf("New profiles saved: %d\n", searchStats.NewProfiles)
+			fmt.Printf("Duplicates skipped: %d\n", searchStats.Duplicates)
+			fmt.Printf("Filtered by title/mutuals/#OpenToWork: %d\n", searchStats.Filtered)
+			fmt.Printf("Pages scraped: %d\n", searchStats.PagesScraped)
+			fmt.Printf("Errors encountered: %d\n", searchStats.ErrorCount)
+			fmt.Printf("Duration: %s\n", searchStats.EndTime.Sub(searchStats.StartTime))
+			fmt.Println("=======================================")
+
+			// Warn if no profiles found - likely indicates selector changes
+			if automation.SelectorsMayHaveChanged(searchStats) {
+				logger.Warning("⚠️  Zero profiles found despite successful page load!")
+				logger.Warning("⚠️  LinkedIn may have changed their HTML selectors.")
+				logger.Warning("⚠️  Check pkg/utils/selectors.go or override search_result_item in SELECTORS_FILE if needed.")
+				if automation.AlertSelectorsMayHaveChanged(notify.FromEnv(), searchConfig, searchStats) {
+					logger.Info("Sent selectors_may_have_changed alert to NOTIFY_WEBHOOK_URL")
+				}
+			}
+
+			// IMMEDIATE CONNECTION FLOW
+			// Connect to found profiles immediately (limit to 3)
+			if len(searchResults) > 0 && r.connectionsAllowed && os.Getenv("ENABLE_CONNECTIONS") == "true" && !automation.CoolingOff() {
+				logger.Info("Starting immediate connection requests for found profiles...")
+
+				var requests []automation.ConnectionRequest
+				for _, result := range searchResults {
+					if len(requests) >= 3 {
+						break
+					}
+					requests = append(requests, automation.ConnectionRequest{
+						ProfileID:   result.ProfileID,
+						ProfileURL:  result.ProfileURL,
+						Name:        result.Name,
+						Title:       result.Title,
+						Company:     result.Company,
+						RequestedAt: time.Now(),
+					})
+				}
+
+				connStats := automation.SendConnectionRequests(r.page, r.db, r.rateLimiter, requests)
+				r.runErrors += connStats.Failed
+			}
+		}
+	} else {
+		logger.Warning("Search rate limit reached - skipping search for today")
+	}
+	return nil
+}
+
+// searchAndConnect sends invitations from the search result cards, with
+// SEARCH_CONNECT_NOTE as the note (none when unset)
+func (r *runner) searchAndConnect(searchConfig automation.SearchConfig) error {
+	connStats := automation.SearchAndConnect(r.page, r.db, r.rateLimiter, searchConfig, os.Getenv("SEARCH_CONNECT_NOTE"))
+	r.runErrors += connStats.Failed
+
+	// Record search action in rate limiter
+	if err := r.rateLimiter.RecordAction(automation.TaskSearch); err != nil {
+		logger.Warning("Failed to record search action: " + err.Error())
+	}
+
+	fmt.Println("\n========== Search-and-Connect Statistics ==========")
+	fmt.Printf("Total attempted: %d\n", connStats.TotalAttempted)
+	fmt.Printf("Successful: %d\n", connStats.Successful)
+	fmt.Printf("Failed: %d\n", connStats.Failed)
+	fmt.Printf("Already connected: %d\n", connStats.AlreadyConnected)
+	fmt.Printf("Already pending: %d\n", connStats.Pending)
+	fmt.Printf("Company capped: %d\n", connStats.CompanyCapped)
+	fmt.Printf("Duration: %s\n", connStats.EndTime.Sub(connStats.StartTime))
+	fmt.Println("===================================================")
+	return nil
+}
+
+// scrapeGroups saves the members of the GROUP_URLS groups and the attendees
+// of the EVENT_URLS events (comma-separated) as profiles for the connect
+// phase: people who share a group or event are warm targets. Reads up to
+// GROUP_SCRAPE_MAX (default 25) profiles from each list.
+func (r *runner) scrapeGroups() error {
+	max := 25
+	if os.Getenv("GROUP_SCRAPE_MAX") != "" {
+		fmt.Sscanf(os.Getenv("GROUP_SCRAPE_MAX"), "%d", &max)
+	}
+
+	scrape := func(kind string, urls []string, scrapeList func(*rod.Page, string, int) ([]automation.SearchResult, error)) error {
+		for _, listURL := range urls {
+			results, err := scrapeList(r.page, listURL, max)
+			if err != nil {
+				r.runErrors++
+				logger.Error(fmt.Sprintf("Failed to read %s %s: %s", kind, listURL, err.Error()))
+				// A checkpoint or dead session fails every other list too
+				if errors.Is(err, automation.ErrCheckpoint) || errors.Is(err, automation.ErrNotAuthenticated) {
+					return err
+				}
+				if len(results) == 0 {
+					continue
+				}
+			}
+
+			saved, err := automation.SaveScrapedProfiles(r.db, results, kind+":"+listURL, 30)
+			if err != nil {
+				return err
+			}
+			logger.Info(fmt.Sprintf("Saved %d new profiles of %d from %s %s", saved, len(results), kind, listURL))
+		}
+		return nil
+	}
+
+	if err := scrape(automation.ContextSourceGroup, automation.ParseListURLs(os.Getenv("GROUP_URLS")), automation.ScrapeGroupMembers); err != nil {
+		return err
+	}
+	return scrape(automation.ContextSourceEvent, automation.ParseListURLs(os.Getenv("EVENT_URLS")), automation.ScrapeEventAttendees)
+}
+
+// connect sends connection requests to profiles collected by earlier searches
+func (r *runner) connect() error {
+	logger.Info("Starting connection request automation (processing backlog)...")
+
+	// Check rate limit
+	if err := r.rateLimiter.CheckDailyLimit(automation.TaskConnection); err == nil {
+		// Get profiles that haven't been contacted yet
+		maxConnections := 5 // Limit to 5 connections per run for safety
+		if os.Getenv("MAX_CONNECTIONS_PER_RUN") != "" {
+			fmt.Sscanf(os.Getenv("MAX_CONNECTIONS_PER_RUN"), "%d", &maxConnections)
+		}
+
+		profiles, err := r.db.GetRecentProfiles(maxConnections, 30) // Get up to 5 profiles from last 30 days
+		if err != nil {
+			logger.Warning("Failed to get profiles for connections: " + err.Error())
+		} else if len(profiles) > 0 {
+			logger.Info(fmt.Sprintf("Found %d profiles for connection requests", len(profiles)))
+
+			// Prepare sender variables and template from environment
+			senderVars := senderVarsFromEnv()
+			templateID := connectionTemplateFromEnv()
+
+			// Use a pool of notes picked at random per profile when configured
+			notePool := automation.NotePool{{TemplateID: templateID}}
+			if poolSpec := os.Getenv("CONNECTION_TEMPLATE_POOL"); poolSpec != "" {
+				pool, err := automation.ParseNotePool(poolSpec)
+				if err != nil {
+					logger.Warning("Invalid CONNECTION_TEMPLATE_POOL, using " + templateID + ": " + err.Error())
+				} else {
+					notePool = pool
+				}
+			}
+
+			// CONNECTION_TEMPLATE_ROTATION cycles templates deterministically instead of picking at random
+			rotator, err := automation.TemplateRotatorFromEnv(r.db, true)
+			if err != nil {
+				logger.Warning("Invalid connection template rotation, using the note pool: " + err.Error())
+			}
+
+			// Prepare connection requests
+			var requests []automation.ConnectionRequest
+			for _, profile := range profiles {
+				// NOTE_GENERATOR=http asks an external service for each note instead of the pool
+				var request *automation.ConnectionRequest
+				if automation.ExternalNoteGeneratorEnabled() {
+					request, err = automation.PrepareConnectionRequestFromProfile(profile, templateID, senderVars)
+				} else if rotator != nil {
+					// Each prepared request takes the next turn; the rotation only
+					// moves for the requests that are actually sent
+					var template automation.MessageTemplate
+					if template, err = rotator.Peek(len(requests)); err == nil {
+						request, err = automation.PrepareConnectionRequestFromPool(profile, automation.NotePool{{TemplateID: template.ID}}, senderVars)
+					}
+				} else {
+					request, err = automation.PrepareConnectionRequestFromPool(profile, notePool, senderVars)
+				}
+				if errors.Is(err, automation.ErrNoteTooShort) {
+					logger.Warning(fmt.Sprintf("Note for %s is below CONNECTION_NOTE_MIN, use a richer template: %s", profile.Name, err.Error()))
+					continue
+				}
+				if err != nil {
+					logger.Warning(fmt.Sprintf("Failed to prepare connection for %s: %s", profile.Name, err.Error()))
+					continue
+				}
+				requests = append(requests, *request)
+			}
+
+			if len(requests) > 0 {
+				// Send connection requests
+				connStats := automation.SendConnectionRequests(r.page, r.db, r.rateLimiter, requests)
+				r.runErrors += connStats.Failed
+				if rotator != nil {
+					for _, templateID := range connStats.SentTemplateIDs {
+						rotator.Advance(templateID)
+					}
+				}
+
+				// Display stats
+				fmt.Println("\n========== Connection Request Statistics ==========")
+				fmt.Printf("Total attempted: %d\n", connStats.TotalAttempted)
+				fmt.Printf("Successful: %d\n", connStats.Successful)
+				fmt.Printf("Failed: %d\n", connStats.Failed)
+				fmt.Printf("Already connected: %d\n", connStats.AlreadyConnected)
+				fmt.Printf("Already pending: %d\n", connStats.Pending)
+				if len(connStats.Errors) > 0 {
+					fmt.Printf("Errors: %d\n", len(connStats.Errors))
+					for i, errMsg := range connStats.Errors {
+						if i < 3 { // Show first 3 errors
+							fmt.Printf("  - %s\n", errMsg)
+						}
+					}
+				}
+				fmt.Printf("Duration: %s\n", connStats.EndTime.Sub(connStats.StartTime))
+				fmt.Println("===================================================")
+			}
+		} else {
+			logger.Info("No profiles available for connection requests")
+		}
+	} else {
+		logger.Warning("Connection rate limit reached - skipping connections for today")
+	}
+	return nil
+}
+
+// connectFromMyNetwork connects from the "People you may know" suggestions
+func (r *runner) connectFromMyNetwork() error {
+	maxSuggestions := 5
+	if os.Getenv("MAX_MYNETWORK_CONNECTIONS_PER_RUN") != "" {
+		fmt.Sscanf(os.Getenv("MAX_MYNETWORK_CONNECTIONS_PER_RUN"), "%d", &maxSuggestions)
+	}
+
+	networkStats := automation.ConnectFromMyNetwork(r.page, r.db, r.rateLimiter, maxSuggestions)
+	r.runErrors += networkStats.Failed
+	fmt.Println("\n========== My Network Connection Statistics ==========")
+	fmt.Printf("Total attempted: %d\n", networkStats.TotalAttempted)
+	fmt.Printf("Successful: %d\n", networkStats.Successful)
+	fmt.Printf("Failed: %d\n", networkStats.Failed)
+	fmt.Printf("Passed over: %d\n", networkStats.Passed)
+	fmt.Printf("Dismissed: %d\n", networkStats.Dismissed)
+	fmt.Printf("Duration: %s\n", networkStats.EndTime.Sub(networkStats.StartTime))
+	fmt.Println("======================================================")
+	return nil
+}
+
+// followUps checks acceptances and replies and sends follow-up messages
+func (r *runner) followUps() error {
+	if err := automation.ProcessDailyFollowUps(r.page, r.db, r.rateLimiter); err != nil {
+		return fmt.Errorf("daily follow-up workflow failed: %w", err)
+	}
+	return nil
+}
+
+// probeVisibility looks for signs of a shadow-limited account
+func (r *runner) probeVisibility() error {
+	if assessment, err := automation.ProbeVisibility(r.page, r.db); err != nil {
+		logger.Warning("Visibility probe failed: " + err.Error())
+	} else {
+		printVisibilityAssessment(assessment)
+	}
+	return nil
+}